@@ -5,11 +5,14 @@ package v1alpha1
 
 type (
 	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategy represent which strategy to distribute target to each collector
-	// +kubebuilder:validation:Enum=consistent-hashing
+	// +kubebuilder:validation:Enum=consistent-hashing;least-weighted
 	AmazonCloudWatchAgentTargetAllocatorAllocationStrategy string
 )
 
 const (
 	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing targets will be consistently added to collectors, which allows a high-availability setup.
 	AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing AmazonCloudWatchAgentTargetAllocatorAllocationStrategy = "consistent-hashing"
+
+	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted targets will be distributed to the collector with the fewest targets currently assigned.
+	AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted AmazonCloudWatchAgentTargetAllocatorAllocationStrategy = "least-weighted"
 )
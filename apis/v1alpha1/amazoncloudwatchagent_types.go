@@ -39,7 +39,7 @@ const (
 // SEE: AmazonCloudWatchAgent.spec.ports[index].
 type Ingress struct {
 	// Type default value is: ""
-	// Supported types are: ingress, route
+	// Supported types are: ingress, route, gateway
 	Type IngressType `json:"type,omitempty"`
 
 	// RuleType defines how Ingress exposes collector receivers.
@@ -71,6 +71,36 @@ type Ingress struct {
 	// type "route" is used.
 	// +optional
 	Route OpenShiftRoute `json:"route,omitempty"`
+
+	// Gateway is a Gateway API specific section that is only considered when
+	// type "gateway" is used.
+	// +optional
+	Gateway GatewayRoutes `json:"gateway,omitempty"`
+}
+
+// GatewayRoutes defines the Gateway API routes exposing the receivers. A GRPCRoute is created for each gRPC
+// receiver and an HTTPRoute for each HTTP receiver, on the <port name>.<hostname> host. TLS is terminated by the
+// listeners of the parent Gateways.
+type GatewayRoutes struct {
+	// ParentRefs are the Gateways the routes are attached to.
+	// +optional
+	// +listType=atomic
+	ParentRefs []GatewayParentReference `json:"parentRefs,omitempty"`
+}
+
+// GatewayParentReference identifies a Gateway, and optionally one of its listeners.
+type GatewayParentReference struct {
+	// Name is the name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway. Defaults to the namespace of the agent.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway, such as its HTTPS listener.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
 }
 
 // OpenShiftRoute defines openshift route specific settings.
@@ -95,6 +125,26 @@ type AmazonCloudWatchAgentSpec struct {
 	// This is only relevant to daemonset, statefulset, and deployment mode
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeGroups are configuration profiles for the nodes matching their selector. Each node group is rendered
+	// as its own DaemonSet and ConfigMap, and its nodes are excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	NodeGroups []NodeGroup `json:"nodeGroups,omitempty"`
+	// Windows renders an additional DaemonSet running the agent on the Windows nodes of the cluster, which are
+	// then excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	Windows *WindowsSpec `json:"windows,omitempty"`
+	// Hybrid renders an additional DaemonSet running the agent on the EKS Hybrid Nodes and other on-premises nodes of
+	// the cluster, which are then excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	Hybrid *HybridSpec `json:"hybrid,omitempty"`
+	// ContainerRuntime mounts the socket of the node container runtime into the agent pods, where the agent
+	// expects it for the container metrics and metadata.
+	// This is only relevant to daemonset mode.
+	// +optional
+	ContainerRuntime *ContainerRuntimeSpec `json:"containerRuntime,omitempty"`
 	// Args is the set of arguments to pass to the OpenTelemetry Collector binary
 	// +optional
 	Args map[string]string `json:"args,omitempty"`
@@ -139,6 +189,11 @@ type AmazonCloudWatchAgentSpec struct {
 	//
 	// +optional
 	PodSecurityContext *v1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Hardening runs the agent as a non-root user, without privilege escalation and with only the capabilities
+	// needed by the enabled features. Its settings take precedence over SecurityContext and PodSecurityContext.
+	//
+	// +optional
+	Hardening *HardeningSpec `json:"hardening,omitempty"`
 	// PodAnnotations is the set of annotations that will be attached to
 	// Collector and Target Allocator pods.
 	// +optional
@@ -153,9 +208,28 @@ type AmazonCloudWatchAgentSpec struct {
 	// the operator will not automatically create a ServiceAccount for the collector.
 	// +optional
 	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// ServiceAccountAnnotations are added to the ServiceAccount created for the agent, e.g. eks.amazonaws.com/role-arn
+	// to bind it to an IAM role through IAM roles for service accounts. They cannot be used with serviceAccount, as the
+	// operator does not manage existing service accounts.
+	// +optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// PodIdentityAssociation binds the service account of the agent to an IAM role with EKS Pod Identity. The operator
+	// renders a PodIdentityAssociation of the EKS controller of AWS Controllers for Kubernetes, which must be installed.
+	// +optional
+	PodIdentityAssociation *PodIdentityAssociationSpec `json:"podIdentityAssociation,omitempty"`
 	// Image indicates the container image to use for the OpenTelemetry Collector.
 	// +optional
 	Image string `json:"image,omitempty"`
+	// Version of the CloudWatch agent to run, e.g. 1.300049.1. The operator resolves it to the image of that version
+	// in the repository of its default agent image and records it in the status. Without a version, the agent follows
+	// the version of the operator as defined by the upgradeStrategy. Cannot be set together with image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.-]*$`
+	Version string `json:"version,omitempty"`
+	// FIPS runs the FIPS validated agent image configured in the operator, unless image is set, and makes the agent
+	// send its telemetry to the FIPS endpoints of the AWS services.
+	// +optional
+	FIPS bool `json:"fips,omitempty"`
 	// WorkingDir represents Container's working directory. If not specified,
 	// the container runtime's default will be used, which might
 	// be configured in the container image. Cannot be updated.
@@ -170,12 +244,80 @@ type AmazonCloudWatchAgentSpec struct {
 	// Prometheus is the raw YAML to be used as the collector's prometheus configuration.
 	// +optional
 	Prometheus PrometheusConfig `json:"prometheus,omitempty"`
+	// PrometheusConfigReload applies the changes of the Prometheus configuration to the running agents and target
+	// allocator instead of rolling them, which keeps the OTLP connections of the agents open.
+	// +optional
+	PrometheusConfigReload *PrometheusConfigReloadSpec `json:"prometheusConfigReload,omitempty"`
 	// Config is the raw JSON to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
 	// +required
 	Config string `json:"config,omitempty"`
+	// ConfigSources are JSON agent configuration fragments merged on top of Config in the order they are
+	// listed. Objects are merged key by key and a later source overrides scalars and lists of earlier ones,
+	// so platform-wide defaults and team-specific additions can be maintained separately.
+	// +optional
+	ConfigSources []ConfigSource `json:"configSources,omitempty"`
+	// AWS sets the region, the IAM role and the endpoints of the agent configuration, on top of Config and
+	// ConfigSources, so that sending telemetry to another account or region does not require templating the JSON.
+	// +optional
+	AWS *AWSSpec `json:"aws,omitempty"`
+	// Proxy routes the traffic of the agent container to AWS through an HTTP proxy, for clusters without direct
+	// internet egress. The proxy is set in the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars honored by the agent,
+	// and the instance metadata service, the EKS Pod Identity agent and the cluster services are never proxied.
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+	// KubernetesEvents deploys a pipeline shipping the events of the cluster to CloudWatch Logs, next to the
+	// Container Insights data. This is not supported in sidecar mode.
+	// +optional
+	KubernetesEvents *KubernetesEventsSpec `json:"kubernetesEvents,omitempty"`
+	// ControlPlaneMetrics scrapes a curated set of metrics of the EKS control plane, the API server and etcd
+	// through the Kubernetes API, and sends them to CloudWatch as Container Insights Prometheus metrics. Every
+	// replica scrapes the control plane, so this is only supported in deployment and statefulset modes.
+	// +optional
+	ControlPlaneMetrics *ControlPlaneMetricsSpec `json:"controlPlaneMetrics,omitempty"`
+	// Logs deploys Fluent Bit on the nodes of the agent to collect the application, dataplane and host logs into
+	// the Container Insights log groups, in place of the Fluent Bit manifests of Container Insights. This is not
+	// supported in sidecar mode.
+	// +optional
+	Logs *LogsSpec `json:"logs,omitempty"`
+	// GPUMetrics deploys the NVIDIA DCGM exporter on the nodes with NVIDIA GPUs, and scrapes it from the agent on
+	// the same node. This is only supported in daemonset mode.
+	// +optional
+	GPUMetrics *GPUMetricsSpec `json:"gpuMetrics,omitempty"`
+	// NeuronMetrics deploys the Neuron monitor on the Inferentia and Trainium nodes, and scrapes it from the agent on
+	// the same node. This is only supported in daemonset mode.
+	// +optional
+	NeuronMetrics *NeuronMetricsSpec `json:"neuronMetrics,omitempty"`
+	// EFAMetrics collects the metrics of the Elastic Fabric Adapters from the agents of the nodes exposing EFA
+	// devices. This is only supported in daemonset mode.
+	// +optional
+	EFAMetrics *EFAMetricsSpec `json:"efaMetrics,omitempty"`
+	// StatsD enables the StatsD listener of the agent in the metrics_collected section of Config, and exposes it on
+	// the Service of the agent. In daemonset mode, the listener can also be bound to the port of the node so that the
+	// daemons of the node reach the agent on the node IP.
+	// +optional
+	StatsD *MetricsListenerSpec `json:"statsd,omitempty"`
+	// CollectD enables the collectd network listener of the agent in the metrics_collected section of Config, and
+	// exposes it like StatsD.
+	// +optional
+	CollectD *MetricsListenerSpec `json:"collectd,omitempty"`
+	// SelfTelemetry exposes the internal metrics of the agent, such as the data points and spans it accepted,
+	// refused, dropped or failed to send, on a Prometheus endpoint of the pod. The metrics can also be sent to
+	// CloudWatch, per node and pod, to alert on the telemetry lost by the agents.
+	// +optional
+	SelfTelemetry *SelfTelemetrySpec `json:"selfTelemetry,omitempty"`
 	// Config is the raw YAML to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
 	// +optional
 	OtelConfig string `json:"otelConfig,omitempty"`
+	// EMF sets the namespace, log group, log stream and dimension rollup of the awsemf exporters of OtelConfig,
+	// on top of the settings of the exporters.
+	// +optional
+	EMF *EMFSpec `json:"emf,omitempty"`
+	// ConfigSecret projects keys of a Secret in the same namespace into the agent configuration directory,
+	// next to the configuration rendered from Config. The agent merges every JSON file in that directory,
+	// which keeps credentials and private endpoints out of the ConfigMap managed by the operator.
+	// This is not supported in sidecar mode.
+	// +optional
+	ConfigSecret *v1.SecretProjection `json:"configSecret,omitempty"`
 	// VolumeMounts represents the mount points to use in the underlying collector deployment(s)
 	// +optional
 	// +listType=atomic
@@ -186,6 +328,10 @@ type AmazonCloudWatchAgentSpec struct {
 	// +optional
 	// +listType=atomic
 	Ports []v1.ServicePort `json:"ports,omitempty"`
+	// Service customizes the Service exposing the agent ports. Ports that can't be inferred from the
+	// configuration are still declared through Ports.
+	// +optional
+	Service ServiceSpec `json:"service,omitempty"`
 	// ENV vars to set on the OpenTelemetry Collector's Pods. These can then in certain cases be
 	// consumed in the config file for the Collector.
 	// +optional
@@ -198,6 +344,11 @@ type AmazonCloudWatchAgentSpec struct {
 	// +optional
 	// +listType=atomic
 	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+	// PersistentVolumeClaimRetentionPolicy describes the lifecycle of the persistent volume claims created from
+	// VolumeClaimTemplates, e.g. to keep the file_storage queue of a scaled down replica. Only available when the
+	// mode=statefulset.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
 	// Toleration to schedule OpenTelemetry Collector pods.
 	// This is only relevant to daemonset, statefulset, and deployment mode
 	// +optional
@@ -206,6 +357,14 @@ type AmazonCloudWatchAgentSpec struct {
 	// +optional
 	// +listType=atomic
 	Volumes []v1.Volume `json:"volumes,omitempty"`
+	// HostMounts are directories and files of the node mounted read-only into the agent container, such as
+	// /proc, /sys or the log directories to collect. Host paths mounted through Volumes are writable unless
+	// their mount says otherwise, so HostMounts is the way to grant the agent a minimal host access.
+	// This is only relevant to daemonset mode.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HostMounts []HostMount `json:"hostMounts,omitempty"`
 	// Ingress is used to specify how OpenTelemetry Collector is exposed. This
 	// functionality is only available if one of the valid modes is set.
 	// Valid modes are: deployment, daemonset and statefulset.
@@ -214,9 +373,17 @@ type AmazonCloudWatchAgentSpec struct {
 	// HostNetwork indicates if the pod should run in the host networking namespace.
 	// +optional
 	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// DNSPolicy sets the DNS policy of the agent pods. Defaults to ClusterFirstWithHostNet when
+	// hostNetwork is enabled and ClusterFirst otherwise.
+	// +optional
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// DNSConfig specifies DNS parameters of the agent pods, merged with the ones generated from DNSPolicy.
+	// It is required when DNSPolicy is set to None.
+	// +optional
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
 	// If specified, indicates the pod's priority.
 	// If not specified, the pod priority will be default or zero if there is no
-	// default.
+	// default. Defaults to system-node-critical in daemonset mode.
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 	// If specified, indicates the pod's scheduling constraints
@@ -225,13 +392,23 @@ type AmazonCloudWatchAgentSpec struct {
 	// Actions that the management system should take in response to container lifecycle events. Cannot be updated.
 	// +optional
 	Lifecycle *v1.Lifecycle `json:"lifecycle,omitempty"`
-	// Duration in seconds the pod needs to terminate gracefully upon probe failure.
+	// Duration in seconds the agent pods need to terminate gracefully, including the time spent in a preStop hook.
+	// This is not applicable to Sidecar mode.
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 	// Liveness config for the OpenTelemetry Collector except the probe handler which is auto generated from the health extension of the collector.
 	// It is only effective when healthcheckextension is configured in the OpenTelemetry Collector pipeline.
 	// +optional
 	LivenessProbe *Probe `json:"livenessProbe,omitempty"`
+	// Readiness config for the agent container. The probe handler is generated from the health extension of the collector
+	// and the probe is only added when this is set and healthcheckextension is configured.
+	// +optional
+	ReadinessProbe *Probe `json:"readinessProbe,omitempty"`
+	// Startup config for the agent container, which holds off the liveness probe until a slow starting agent is up.
+	// The probe handler is generated from the health extension of the collector and the probe is only added when this
+	// is set and healthcheckextension is configured.
+	// +optional
+	StartupProbe *Probe `json:"startupProbe,omitempty"`
 	// InitContainers allows injecting initContainers to the Collector's pod definition.
 	// These init containers can be used to fetch secrets for injection into the
 	// configuration from external sources, run added checks, etc. Any errors during the execution of
@@ -242,9 +419,9 @@ type AmazonCloudWatchAgentSpec struct {
 
 	// AdditionalContainers allows injecting additional containers into the Collector's pod definition.
 	// These sidecar containers can be used for authentication proxies, log shipping sidecars, agents for shipping
-	// metrics to their cloud, or in general sidecars that do not support automatic injection. This option only
-	// applies to Deployment, DaemonSet, and StatefulSet deployment modes of the collector. It does not apply to the sidecar
-	// deployment mode. More info about sidecars:
+	// metrics to their cloud, or in general sidecars that do not support automatic injection. In sidecar mode the
+	// containers are injected into the workload pod next to the agent container, unless the pod already has a
+	// container with the same name. More info about sidecars:
 	// https://kubernetes.io/docs/tasks/configure-pod-container/share-process-namespace/
 	//
 	// Container names managed by the operator:
@@ -271,9 +448,16 @@ type AmazonCloudWatchAgentSpec struct {
 	// +optional
 	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// SpreadAcrossZones spreads the collector pods evenly across the availability zones of the cluster, in
+	// addition to the TopologySpreadConstraints. Pods are still scheduled when a zone is unavailable.
+	// This is only relevant to statefulset, and deployment mode
+	// +optional
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+
 	// ConfigMaps is a list of ConfigMaps in the same namespace as the AmazonCloudWatchAgent
 	// object, which shall be mounted into the Collector Pods.
-	// Each ConfigMap will be added to the Collector's Deployments as a volume named `configmap-<configmap-name>`.
+	// Each ConfigMap will be added to the Collector's Deployments as a volume named `configmap-<configmap-name>` and
+	// mounted at `/var/conf/<mountpath>/configmap-<configmap-name>`.
 	ConfigMaps []ConfigMapsSpec `json:"configmaps,omitempty"`
 	// UpdateStrategy represents the strategy the operator will take replacing existing DaemonSet pods with new pods
 	// https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/daemon-set-v1/#DaemonSetSpec
@@ -285,6 +469,18 @@ type AmazonCloudWatchAgentSpec struct {
 	// This is only applicable to Deployment mode.
 	// +optional
 	DeploymentUpdateStrategy appsv1.DeploymentStrategy `json:"deploymentUpdateStrategy,omitempty"`
+	// MinReadySeconds is the minimum number of seconds a new collector pod must be ready, without any of its
+	// containers crashing, before it is considered available and the rollout continues.
+	// This is only relevant to daemonset, statefulset, and deployment mode
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rollout of the collector Deployment to make
+	// progress before it is reported as failed in the Deployment conditions. Defaults to 600s.
+	// This is only applicable to Deployment mode.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 // AmazonCloudWatchAgentTargetAllocator defines the configurations for the Prometheus target allocator.
@@ -301,7 +497,8 @@ type AmazonCloudWatchAgentTargetAllocator struct {
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
 	// AllocationStrategy determines which strategy the target allocator should use for allocation.
-	// The current option is consistent-hashing.
+	// The options are consistent-hashing and least-weighted.
+	// Defaults to consistent-hashing.
 	// +optional
 	AllocationStrategy AmazonCloudWatchAgentTargetAllocatorAllocationStrategy `json:"allocationStrategy,omitempty"`
 	// FilterStrategy determines how to filter targets before allocating them among the collectors.
@@ -412,10 +609,32 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	// Deprecated: use "AmazonCloudWatchAgent.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the operator last reconciled. The conditions describe
+	// that generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe whether the configuration is valid, whether the agents run it and whether the
+	// last reconciliation failed.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionTypeAvailable is True when every agent pod runs the last reconciled spec and is available.
+	ConditionTypeAvailable = "Available"
+
+	// ConditionTypeConfigValid is True when the agent configuration could be rendered into the agent resources.
+	ConditionTypeConfigValid = "ConfigValid"
+
+	// ConditionTypeDegraded is True when the last reconciliation failed.
+	ConditionTypeDegraded = "Degraded"
+)
+
 // +kubebuilder:object:root=true
-// +kubebuilder:storageversion
 // +kubebuilder:resource:shortName=otelcol;otelcols
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
@@ -459,7 +678,7 @@ type AutoscalerSpec struct {
 	// +optional
 	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
 	// Metrics is meant to provide a customizable way to configure HPA metrics.
-	// currently the only supported custom metrics is type=Pod.
+	// currently the only supported custom metrics are type=Pods and type=External.
 	// Use TargetCPUUtilization or TargetMemoryUtilization instead if scaling on these common resource metrics.
 	// +optional
 	Metrics []MetricSpec `json:"metrics,omitempty"`
@@ -489,6 +708,40 @@ type PodDisruptionBudgetSpec struct {
 	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
+// ServiceSpec defines the AmazonCloudWatchAgent's service specification.
+type ServiceSpec struct {
+	// Type of the agent Service. Defaults to ClusterIP.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type v1.ServiceType `json:"type,omitempty"`
+	// Annotations to add to the agent Service on top of the ones of the AmazonCloudWatchAgent,
+	// e.g. to provision an internal Network Load Balancer.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// LoadBalancerSourceRanges restricts the client IPs allowed through a LoadBalancer Service.
+	// +optional
+	// +listType=atomic
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// InternalTrafficPolicy of the agent Service. Defaults to Local in daemonset mode, so instrumented pods
+	// send their telemetry to the agent on their own node, and to Cluster otherwise.
+	// +optional
+	// +kubebuilder:validation:Enum=Cluster;Local
+	InternalTrafficPolicy *v1.ServiceInternalTrafficPolicy `json:"internalTrafficPolicy,omitempty"`
+	// TrafficDistribution expresses a preference for how traffic is routed to the agent endpoints,
+	// e.g. PreferClose keeps traffic in the client's zone when possible.
+	// +optional
+	TrafficDistribution *string `json:"trafficDistribution,omitempty"`
+	// IPFamilies lists the IP families (IPv4, IPv6) assigned to the agent Services, in order of preference.
+	// Defaults to the cluster's primary family.
+	// +optional
+	// +listType=atomic
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+	// IPFamilyPolicy of the agent Services, set it to PreferDualStack or RequireDualStack on dual-stack clusters.
+	// +optional
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy *v1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+}
+
 // MetricsConfigSpec defines a metrics config.
 type MetricsConfigSpec struct {
 	// EnableMetrics specifies if ServiceMonitor or PodMonitor(for sidecar mode) should be created for the service managed by the OpenTelemetry Operator.
@@ -510,7 +763,7 @@ type ObservabilitySpec struct {
 	Metrics MetricsConfigSpec `json:"metrics,omitempty"`
 }
 
-// Probe defines the OpenTelemetry's pod probe config. Only Liveness probe is supported currently.
+// Probe defines the OpenTelemetry's pod probe config.
 type Probe struct {
 	// Number of seconds after the container has started before liveness probes are initiated.
 	// Defaults to 0 seconds. Minimum value is 0.
@@ -554,6 +807,502 @@ type Probe struct {
 type MetricSpec struct {
 	Type autoscalingv2.MetricSourceType  `json:"type"`
 	Pods *autoscalingv2.PodsMetricSource `json:"pods,omitempty"`
+	// External scales on a metric that is not associated with any Kubernetes object, e.g. the OTLP request rate
+	// of a load balancer published through an external metrics adapter.
+	External *autoscalingv2.ExternalMetricSource `json:"external,omitempty"`
+}
+
+// NodeGroup is a configuration profile applied to the agents running on the nodes matching NodeSelector.
+type NodeGroup struct {
+	// Name of the node group, appended to the name of the DaemonSet and ConfigMap rendered for it.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+	// NodeSelector selects the nodes of the group.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// Config is a JSON agent configuration fragment merged on top of the agent configuration for the group.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// Tolerations are added to the agent tolerations for the group.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// Resources replace the agent resources for the group.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// ContainerRuntime replaces the agent container runtime for the group, for node pools running another
+	// operating system or distribution.
+	// +optional
+	ContainerRuntime *ContainerRuntimeSpec `json:"containerRuntime,omitempty"`
+}
+
+// HardeningSpec defines the user and group the hardened agent runs as.
+type HardeningSpec struct {
+	// RunAsUser is the UID of the agent process. Defaults to 10001.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// RunAsGroup is the GID of the agent process, which is also the fsGroup of the pod so that the agent can
+	// read the volumes mounted into it. Defaults to 10001.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+}
+
+// HostMount is a path of the node mounted read-only into the agent container.
+type HostMount struct {
+	// Name of the volume, which must be unique among the volumes of the agent pods.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Path of the directory or file on the node.
+	Path string `json:"path"`
+	// MountPath is where the path is mounted in the agent container. Defaults to Path.
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// AWSSpec defines the AWS settings merged into the agent configuration.
+type AWSSpec struct {
+	// Region the agent sends its telemetry to, which is the agent.region of the configuration.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// RoleARN is the IAM role the agent assumes to send its telemetry, e.g. to a central monitoring account. It is
+	// set as the credentials of the agent and of the metrics, logs and traces sections of the configuration.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// EndpointOverrides replace the endpoints of the metrics, logs and traces sections, e.g. with VPC endpoints.
+	// An override only applies when the configuration has its section.
+	// +optional
+	EndpointOverrides *EndpointOverrides `json:"endpointOverrides,omitempty"`
+}
+
+// EndpointOverrides defines the endpoint_override of each section of the agent configuration.
+type EndpointOverrides struct {
+	// Metrics is the CloudWatch endpoint the metrics are sent to.
+	// +optional
+	Metrics string `json:"metrics,omitempty"`
+
+	// Logs is the CloudWatch Logs endpoint the logs are sent to.
+	// +optional
+	Logs string `json:"logs,omitempty"`
+
+	// Traces is the X-Ray endpoint the traces are sent to.
+	// +optional
+	Traces string `json:"traces,omitempty"`
+}
+
+// EMFSpec defines the settings of the awsemf exporters, which send the metrics to CloudWatch as embedded metric
+// format logs. The log group and log stream names may hold the {ClusterName}, {ContainerInstanceId}, {NodeName},
+// {TaskDefinitionFamily} and {TaskId} placeholders, which the exporter replaces with the resource attributes of the
+// metrics.
+type EMFSpec struct {
+	// Namespace is the CloudWatch namespace of the metrics.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	Namespace string `json:"namespace,omitempty"`
+
+	// LogGroupName is the log group the metrics are sent to.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+
+	// LogStreamName is the log stream the metrics are sent to.
+	// +optional
+	LogStreamName string `json:"logStreamName,omitempty"`
+
+	// DimensionRollupOption rolls the dimensions of the metrics up, into metrics with no dimension and one metric
+	// per dimension (ZeroAndSingleDimensionRollup), or one metric per dimension only (SingleDimensionRollup).
+	// +optional
+	// +kubebuilder:validation:Enum=NoDimensionRollup;SingleDimensionRollup;ZeroAndSingleDimensionRollup
+	DimensionRollupOption string `json:"dimensionRollupOption,omitempty"`
+}
+
+// PodIdentityAssociationSpec defines the EKS Pod Identity association of the service account of the agent.
+type PodIdentityAssociationSpec struct {
+	// ClusterName is the name of the EKS cluster the agent runs in.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// RoleARN is the ARN of the IAM role the agent assumes.
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	RoleARN string `json:"roleARN"`
+}
+
+// PrometheusConfigReloadSpec defines how the changes of the Prometheus configuration reach the running agents and
+// target allocator. The configuration is mounted from its own ConfigMap, whose files the kubelet updates in place. A
+// sidecar of the Linux agent pods then signals the agent to reload it, and the target allocator reloads its own file.
+type PrometheusConfigReloadSpec struct {
+	// Enabled runs the reloader sidecar in the Linux agent pods, and leaves the Prometheus configuration out of the
+	// hash of these pods and of the target allocator pods.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// KubernetesEventsSpec defines the pipeline collecting the Kubernetes events of the cluster. The events are collected
+// by a single agent replica, so that they are not duplicated by the agents of a DaemonSet.
+type KubernetesEventsSpec struct {
+	// Enabled deploys the agent collecting the events.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ClusterName names the default log group of the events, /aws/containerinsights/<clusterName>/events.
+	// Defaults to the cluster_name of the kubernetes section of the agent configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// LogGroupName is the log group the events are sent to, instead of the Container Insights one.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+
+	// Namespaces restricts the collection to the events of these namespaces. The events of every namespace are
+	// collected when empty.
+	// +optional
+	// +listType=atomic
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// ControlPlaneMetricsSpec defines the scraping of the metrics of the EKS control plane.
+type ControlPlaneMetricsSpec struct {
+	// Enabled scrapes the metrics of the control plane.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ClusterName is the ClusterName dimension of the metrics and names their log group,
+	// /aws/containerinsights/<clusterName>/prometheus. Defaults to the cluster_name of the kubernetes section of
+	// the agent configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// GPUMetricsSpec defines the DCGM exporter run on the nodes with NVIDIA GPUs, and the pipeline of the agent scraping
+// it. The nodes are detected by the nvidia.com/gpu.present label of the NVIDIA GPU feature discovery, and by the
+// GPU manufacturer labels of Karpenter and EKS Auto Mode.
+type GPUMetricsSpec struct {
+	// Enabled deploys the DCGM exporter DaemonSet and scrapes it. When a DcgmExporter resource deploys the exporter
+	// already, its exporter is scraped instead.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image is the DCGM exporter image. Defaults to the DCGM exporter image of the operator, which is upgraded
+	// with the agent.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ClusterName names the log group of the metrics, /aws/containerinsights/<clusterName>/prometheus.
+	// Defaults to the cluster_name of the kubernetes section of the agent configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// MetricsConfig is the CSV of the DCGM fields collected by the exporter. Defaults to the fields of
+	// Container Insights.
+	// +optional
+	MetricsConfig string `json:"metricsConfig,omitempty"`
+
+	// Affinity schedules the DCGM exporter on the nodes it selects, in place of the detection of the nodes with
+	// NVIDIA GPUs.
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// Resources to set on the DCGM exporter container.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// MIG configures the DCGM exporter of the nodes whose GPUs are partitioned with Multi-Instance GPU.
+	// +optional
+	MIG *GPUMIGSpec `json:"mig,omitempty"`
+}
+
+// GPUMIGSpec defines the DCGM exporter run on the nodes with MIG enabled. The GPU instances of these nodes do not
+// report the utilization of the whole GPU and share the index of their GPU, so their exporter collects the profiling
+// fields DCGM reports per GPU instance, maps the instances to the pods by their device names, and their metrics are
+// exported per GPU instance.
+type GPUMIGSpec struct {
+	// Enabled deploys a DCGM exporter DaemonSet of its own on the nodes with MIG enabled, alongside the exporter of
+	// a DcgmExporter resource too, which then has to leave these nodes out.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// NodeSelector selects the nodes with MIG enabled among the nodes with NVIDIA GPUs. Defaults to the
+	// nvidia.com/mig.config.state=success label of the NVIDIA MIG manager.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// MetricsConfig is the CSV of the DCGM fields collected on the nodes with MIG enabled. Defaults to the fields of
+	// Container Insights reported per GPU instance.
+	// +optional
+	MetricsConfig string `json:"metricsConfig,omitempty"`
+}
+
+// NeuronMetricsSpec defines the Neuron monitor run on the nodes with AWS Inferentia and Trainium accelerators, and the
+// pipeline of the agent scraping it. The nodes are detected by their instance types, and by the accelerator
+// manufacturer label of Karpenter.
+type NeuronMetricsSpec struct {
+	// Enabled deploys the Neuron monitor DaemonSet, unless a NeuronMonitor resource deploys it already.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image is the Neuron monitor image. Defaults to the Neuron monitor image of the operator, which is upgraded
+	// with the agent.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ClusterName names the log group of the metrics, /aws/containerinsights/<clusterName>/prometheus.
+	// Defaults to the cluster_name of the kubernetes section of the agent configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// MonitorConfig is the raw JSON configuration of the Neuron monitor. Defaults to the metrics of
+	// Container Insights, collected every 5 seconds.
+	// +optional
+	MonitorConfig string `json:"monitorConfig,omitempty"`
+
+	// Affinity schedules the Neuron monitor on the nodes it selects, in place of the detection of the nodes with
+	// Neuron devices.
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// Resources to set on the Neuron monitor container.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// EFAMetricsSpec defines the collection of the metrics of the Elastic Fabric Adapters. The nodes are detected by the
+// vpc.amazonaws.com/efa resource advertised by the EFA device plugin, and get their agents from a DaemonSet of their
+// own, which mounts the sysfs of the devices and the pod resources of the kubelet and enables the accelerated
+// compute metrics of the agent configuration. The nodes of the node groups keep the agents of their group.
+type EFAMetricsSpec struct {
+	// Enabled deploys the EFA DaemonSet on the nodes exposing EFA devices.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// MetricsListenerSpec defines a UDP listener of the agent receiving metrics from the applications.
+type MetricsListenerSpec struct {
+	// Enabled adds the listener to the agent configuration, replacing the service_address set in Config.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Port is the UDP port of the listener. Defaults to 8125 for StatsD and 25826 for collectd.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// HostPort binds the listener to the same port of the node. This is only supported in daemonset mode.
+	// +optional
+	HostPort bool `json:"hostPort,omitempty"`
+}
+
+// SelfTelemetrySpec defines the exposure of the internal metrics of the agent.
+type SelfTelemetrySpec struct {
+	// Enabled sets the service telemetry of the OpenTelemetry configuration of the agent, and exposes the endpoint
+	// on the monitoring port of the pod and of the monitoring Service.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Port is the port of the Prometheus endpoint of the internal metrics. Defaults to 8888.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Level is the verbosity of the internal metrics. Defaults to normal.
+	// +optional
+	// +kubebuilder:validation:Enum=basic;normal;detailed
+	Level string `json:"level,omitempty"`
+
+	// CloudWatch scrapes the endpoint from the agent itself and sends the internal metrics to CloudWatch, with the
+	// NodeName and PodName dimensions.
+	// +optional
+	CloudWatch bool `json:"cloudWatch,omitempty"`
+
+	// Namespace is the CloudWatch namespace of the internal metrics. Defaults to CWAgent/SelfTelemetry.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// LogsSpec defines the collection of the logs of the nodes by Fluent Bit.
+type LogsSpec struct {
+	// Enabled deploys the Fluent Bit DaemonSet.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image is the Fluent Bit image. Defaults to the AWS for Fluent Bit image of the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ClusterName names the log groups, /aws/containerinsights/<clusterName>/application, dataplane and host.
+	// Defaults to the cluster_name of the kubernetes section of the agent configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Filters keep or drop the application logs matching them, in the order they are listed.
+	// +optional
+	// +listType=atomic
+	Filters []LogFilter `json:"filters,omitempty"`
+
+	// MultilineParsers are built-in Fluent Bit multiline parsers, such as java, go or python, joining the lines of
+	// the stack traces of the application logs into single events.
+	// +optional
+	// +listType=atomic
+	MultilineParsers []string `json:"multilineParsers,omitempty"`
+
+	// MultilinePatterns join the lines of the application logs of the containers they select into single events,
+	// from a line matching their start pattern to the next one, for the logs the built-in parsers do not know.
+	// +optional
+	// +listType=atomic
+	MultilinePatterns []MultilinePattern `json:"multilinePatterns,omitempty"`
+
+	// LogGroups names the log groups of the logs and sets their retention.
+	// +optional
+	LogGroups *LogGroupsSpec `json:"logGroups,omitempty"`
+
+	// Resources to set on the Fluent Bit container.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// LogGroupsSpec defines the log groups Fluent Bit sends the logs to. The names accept the {cluster} placeholder, and
+// the name of the application log groups also accepts the {namespace}, {pod}, {container} and {workload}
+// placeholders, the workload being the app.kubernetes.io/name label of the pod. The application logs missing a
+// placeholder value are sent to the default application log group.
+type LogGroupsSpec struct {
+	// Application names the log groups of the application logs.
+	// Defaults to /aws/containerinsights/{cluster}/application.
+	// +optional
+	Application string `json:"application,omitempty"`
+
+	// Dataplane names the log group of the logs of the kubelet, the container runtime, and the aws-node and
+	// kube-proxy pods. Defaults to /aws/containerinsights/{cluster}/dataplane.
+	// +optional
+	Dataplane string `json:"dataplane,omitempty"`
+
+	// Host names the log group of the system logs of the nodes. Defaults to /aws/containerinsights/{cluster}/host.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// RetentionInDays is the retention of the log groups created by Fluent Bit. The log events of the log groups
+	// never expire when unset.
+	// +optional
+	// +kubebuilder:validation:Enum=1;3;5;7;14;30;60;90;120;150;180;365;400;545;731;1096;1827;2192;2557;2922;3288;3653
+	RetentionInDays int32 `json:"retentionInDays,omitempty"`
+}
+
+// MultilinePattern is a multiline parser of the application logs of some containers.
+type MultilinePattern struct {
+	// Namespace selects the containers of a namespace. All the namespaces are selected when unset.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Container selects the containers of a name. All the containers are selected when unset.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// StartPattern is the regular expression matching the first line of an event, such as
+	// ^\d{4}-\d{2}-\d{2} for the logs starting with a date.
+	// +required
+	StartPattern string `json:"startPattern"`
+
+	// ContinuePattern is the regular expression matching the following lines of an event, such as
+	// ^\s+at\s for the frames of a Java stack trace. Defaults to the lines not matching the start pattern.
+	// +optional
+	ContinuePattern string `json:"continuePattern,omitempty"`
+}
+
+// LogFilter matches the application logs on a field of their records.
+type LogFilter struct {
+	// Key is the field of the records matched, such as log, or $kubernetes['namespace_name'] for a field of the
+	// Kubernetes metadata of the records.
+	// +required
+	Key string `json:"key"`
+
+	// Regex is the regular expression matched against the field.
+	// +required
+	Regex string `json:"regex"`
+
+	// Exclude drops the records matching the filter, instead of keeping only them.
+	// +optional
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// ContainerRuntimeSpec defines the container runtime socket mounted into the agent pods.
+type ContainerRuntimeSpec struct {
+	// Type of the container runtime, which selects the host path of its socket and where it is mounted in the
+	// agent container.
+	// +optional
+	// +kubebuilder:default:=containerd
+	Type ContainerRuntime `json:"type,omitempty"`
+	// SocketPath is the host path of the runtime socket, for nodes where it is not at the default location of
+	// the runtime type.
+	// +optional
+	SocketPath string `json:"socketPath,omitempty"`
+}
+
+// WindowsSpec configures the agents running on the Windows nodes. The configuration is mounted under the
+// agent installation directory of the Windows image.
+type WindowsSpec struct {
+	// Image of the Windows agent container. Defaults to the agent image, which is published for Windows too.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// NodeSelector is added to the agent node selector for the Windows nodes, on top of the
+	// kubernetes.io/os=windows label.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Config is a JSON agent configuration fragment merged on top of the agent configuration for the Windows nodes.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// Tolerations are added to the agent tolerations for the Windows nodes, which are often tainted with
+	// os=windows:NoSchedule.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// Resources replace the agent resources for the Windows nodes.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// HybridSpec configures the agents running on the hybrid nodes, which have no instance metadata to get the
+// credentials and region from, and whose host paths may differ from the ones of the EC2 nodes.
+type HybridSpec struct {
+	// NodeSelector selects the hybrid nodes. Defaults to the eks.amazonaws.com/compute-type=hybrid label of the
+	// EKS Hybrid Nodes.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Config is a JSON agent configuration fragment merged on top of the agent configuration for the hybrid nodes.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// Tolerations are added to the agent tolerations for the hybrid nodes.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// Resources replace the agent resources for the hybrid nodes.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// ContainerRuntime replaces the agent container runtime for the hybrid nodes.
+	// +optional
+	ContainerRuntime *ContainerRuntimeSpec `json:"containerRuntime,omitempty"`
+	// HostMounts replace the agent host mounts for the hybrid nodes.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HostMounts []HostMount `json:"hostMounts,omitempty"`
+	// CredentialsFile is the host path of the AWS shared credentials file the agents use on the hybrid nodes, such
+	// as the one written by the SSM hybrid activation of the node. Defaults to the credentials of the service
+	// account of the agent, from EKS Pod Identity or IAM roles for service accounts.
+	// +optional
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+}
+
+// ConfigSource is a fragment of agent JSON configuration. Exactly one of Inline or ConfigMap must be set.
+type ConfigSource struct {
+	// Inline is a raw JSON agent configuration fragment.
+	// +optional
+	Inline string `json:"inline,omitempty"`
+	// ConfigMap selects the key of a ConfigMap in the same namespace holding a raw JSON agent configuration
+	// fragment. Changes to the ConfigMap are picked up and roll the agent pods.
+	// +optional
+	ConfigMap *v1.ConfigMapKeySelector `json:"configMap,omitempty"`
 }
 
 type ConfigMapsSpec struct {
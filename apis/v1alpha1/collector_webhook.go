@@ -5,10 +5,20 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -18,9 +28,18 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	ta "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
+// DefaultDaemonSetPriorityClassName is the priority class of daemonset agents that do not set one, so that the
+// agent is not evicted before the workloads it monitors under node pressure.
+const DefaultDaemonSetPriorityClassName = "system-node-critical"
+
+// irsaRoleAnnotation binds a ServiceAccount to an IAM role with IAM roles for service accounts.
+const irsaRoleAnnotation = "eks.amazonaws.com/role-arn"
+
 var (
 	_ admission.CustomValidator = &CollectorWebhook{}
 	_ admission.CustomDefaulter = &CollectorWebhook{}
@@ -50,7 +69,12 @@ func (c CollectorWebhook) ValidateCreate(ctx context.Context, obj runtime.Object
 	if !ok {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", obj)
 	}
-	return c.validate(otelcol)
+	warnings, err := c.validate(otelcol)
+	if err == nil {
+		err = validateWatchedNamespace(c.cfg, "AmazonCloudWatchAgent", otelcol.Namespace)
+	}
+	telemetry.RecordValidation("AmazonCloudWatchAgent", "create", err)
+	return warnings, err
 }
 
 func (c CollectorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
@@ -58,7 +82,12 @@ func (c CollectorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj run
 	if !ok {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", newObj)
 	}
-	return c.validate(otelcol)
+	warnings, err := c.validate(otelcol)
+	if err == nil {
+		err = validateWatchedNamespace(c.cfg, "AmazonCloudWatchAgent", otelcol.Namespace)
+	}
+	telemetry.RecordValidation("AmazonCloudWatchAgent", "update", err)
+	return warnings, err
 }
 
 func (c CollectorWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -66,7 +95,9 @@ func (c CollectorWebhook) ValidateDelete(ctx context.Context, obj runtime.Object
 	if !ok || otelcol == nil {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", obj)
 	}
-	return c.validate(otelcol)
+	warnings, err := c.validate(otelcol)
+	telemetry.RecordValidation("AmazonCloudWatchAgent", "delete", err)
+	return warnings, err
 }
 
 func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
@@ -76,6 +107,9 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 	if len(r.Spec.UpgradeStrategy) == 0 {
 		r.Spec.UpgradeStrategy = UpgradeStrategyAutomatic
 	}
+	if r.Spec.Mode == ModeDaemonSet && r.Spec.PriorityClassName == "" {
+		r.Spec.PriorityClassName = DefaultDaemonSetPriorityClassName
+	}
 
 	if r.Labels == nil {
 		r.Labels = map[string]string{}
@@ -93,6 +127,9 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 	if r.Spec.TargetAllocator.Enabled && r.Spec.TargetAllocator.Replicas == nil {
 		r.Spec.TargetAllocator.Replicas = &one
 	}
+	if r.Spec.TargetAllocator.Enabled && r.Spec.TargetAllocator.AllocationStrategy == "" {
+		r.Spec.TargetAllocator.AllocationStrategy = AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing
+	}
 
 	if r.Spec.MaxReplicas != nil || (r.Spec.Autoscaler != nil && r.Spec.Autoscaler.MaxReplicas != nil) {
 		if r.Spec.Autoscaler == nil {
@@ -150,6 +187,171 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'volumeClaimTemplates'", r.Spec.Mode)
 	}
 
+	// validate persistentVolumeClaimRetentionPolicy
+	if r.Spec.Mode != ModeStatefulSet && r.Spec.PersistentVolumeClaimRetentionPolicy != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'persistentVolumeClaimRetentionPolicy'", r.Spec.Mode)
+	}
+
+	// validate podDisruptionBudget
+	if r.Spec.PodDisruptionBudget != nil && r.Spec.PodDisruptionBudget.MinAvailable != nil && r.Spec.PodDisruptionBudget.MaxUnavailable != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec podDisruptionBudget configuration is incorrect, minAvailable and maxUnavailable are mutually exclusive")
+	}
+
+	// validate FIPS
+	if r.Spec.FIPS && r.Spec.Image == "" && c.cfg.CollectorFIPSImage() == "" {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec FIPS configuration is incorrect, the operator has no FIPS agent image, set image or configure the operator with --agent-fips-image")
+	}
+	if r.Spec.FIPS && r.Spec.AWS != nil && r.Spec.AWS.EndpointOverrides != nil {
+		warnings = append(warnings, "the endpoint overrides replace the FIPS endpoints of their sections")
+	}
+
+	// the agents outside of AWS can't detect their region and cluster from the instance metadata
+	if !c.cfg.RunsOnAWS() {
+		warnings = append(warnings, offAWSWarnings(r, c.cfg.CloudProvider())...)
+	}
+
+	// validate proxy
+	if r.Spec.Proxy != nil {
+		if r.Spec.Proxy.HTTPSProxy != "" && !isProxyURL(r.Spec.Proxy.HTTPSProxy) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec proxy configuration is incorrect, httpsProxy %q is not an http or https URL", r.Spec.Proxy.HTTPSProxy)
+		}
+		if r.Spec.Proxy.HTTPProxy != "" && !isProxyURL(r.Spec.Proxy.HTTPProxy) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec proxy configuration is incorrect, httpProxy %q is not an http or https URL", r.Spec.Proxy.HTTPProxy)
+		}
+		if len(r.Spec.Proxy.NoProxy) > 0 && r.Spec.Proxy.HTTPSProxy == "" && r.Spec.Proxy.HTTPProxy == "" {
+			warnings = append(warnings, "the proxy noProxy hosts are ignored, as neither httpsProxy nor httpProxy is set")
+		}
+	}
+
+	// validate kubernetes events
+	if events := r.Spec.KubernetesEvents; events != nil && events.Enabled {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'kubernetesEvents'", r.Spec.Mode)
+		}
+		if events.LogGroupName == "" && events.ClusterName == "" && agentClusterName(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec kubernetesEvents configuration is incorrect, set clusterName or logGroupName, or the cluster_name of the kubernetes section of the agent configuration")
+		}
+	}
+
+	// validate control plane metrics
+	if controlPlane := r.Spec.ControlPlaneMetrics; controlPlane != nil && controlPlane.Enabled {
+		if r.Spec.Mode != ModeDeployment && r.Spec.Mode != ModeStatefulSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'controlPlaneMetrics'", r.Spec.Mode)
+		}
+		if controlPlane.ClusterName == "" && agentClusterName(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec controlPlaneMetrics configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration")
+		}
+		if (r.Spec.Replicas != nil && *r.Spec.Replicas > 1) || r.Spec.Autoscaler != nil {
+			warnings = append(warnings, "every replica scrapes the control plane, so its metrics are duplicated when controlPlaneMetrics is set on more than one replica")
+		}
+	}
+
+	// validate logs
+	if logs := r.Spec.Logs; logs != nil && logs.Enabled {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'logs'", r.Spec.Mode)
+		}
+		if logs.ClusterName == "" && agentClusterName(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration")
+		}
+		if (r.Spec.AWS == nil || r.Spec.AWS.Region == "") && agentRegion(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, Fluent Bit needs the region of aws, or the region of the agent section of the agent configuration")
+		}
+		for _, pattern := range logs.MultilinePatterns {
+			if err := validateMultilinePattern(pattern); err != nil {
+				return warnings, err
+			}
+		}
+		if groups := logs.LogGroups; groups != nil {
+			if err := validateLogGroupName("application", groups.Application, "{cluster}", "{namespace}", "{pod}", "{container}", "{workload}"); err != nil {
+				return warnings, err
+			}
+			if err := validateLogGroupName("dataplane", groups.Dataplane, "{cluster}"); err != nil {
+				return warnings, err
+			}
+			if err := validateLogGroupName("host", groups.Host, "{cluster}"); err != nil {
+				return warnings, err
+			}
+		}
+	}
+
+	// validate GPU metrics
+	if gpu := r.Spec.GPUMetrics; gpu != nil && gpu.Enabled {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'gpuMetrics'", r.Spec.Mode)
+		}
+		if gpu.ClusterName == "" && agentClusterName(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec gpuMetrics configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration")
+		}
+	}
+
+	// validate Neuron metrics
+	if neuron := r.Spec.NeuronMetrics; neuron != nil && neuron.Enabled {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'neuronMetrics'", r.Spec.Mode)
+		}
+		if neuron.ClusterName == "" && agentClusterName(r.Spec.Config) == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec neuronMetrics configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration")
+		}
+		if neuron.MonitorConfig != "" && !json.Valid([]byte(neuron.MonitorConfig)) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec neuronMetrics configuration is incorrect, monitorConfig is not valid JSON")
+		}
+	}
+
+	// validate EFA metrics
+	if efa := r.Spec.EFAMetrics; efa != nil && efa.Enabled {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'efaMetrics'", r.Spec.Mode)
+		}
+		if conf, err := adapters.ConfigStructFromJSONString(r.Spec.Config); err == nil && (conf == nil || !conf.IsEnhancedContainerInsightsEnabled()) {
+			warnings = append(warnings, "efaMetrics: the EFA metrics are only collected when enhanced_container_insights is set in the kubernetes section of the agent configuration")
+		}
+	}
+
+	// validate the metrics listeners
+	listenerPorts := map[int32]string{}
+	for _, listener := range []struct {
+		name        string
+		spec        *MetricsListenerSpec
+		defaultPort int32
+	}{{"statsd", r.Spec.StatsD, 8125}, {"collectd", r.Spec.CollectD, 25826}} {
+		if listener.spec == nil || !listener.spec.Enabled {
+			continue
+		}
+		if listener.spec.HostPort && r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute '%s.hostPort'", r.Spec.Mode, listener.name)
+		}
+		port := listener.spec.Port
+		if port == 0 {
+			port = listener.defaultPort
+		}
+		if other, ok := listenerPorts[port]; ok {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec %s configuration is incorrect, the %s listener already uses the UDP port %d", listener.name, other, port)
+		}
+		listenerPorts[port] = listener.name
+	}
+
+	if r.Spec.PrometheusConfigReload != nil && r.Spec.PrometheusConfigReload.Enabled {
+		if r.Spec.Prometheus.IsEmpty() {
+			warnings = append(warnings, "prometheusConfigReload: there is no Prometheus configuration to reload, as prometheus is not set")
+		} else if r.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+			warnings = append(warnings, "prometheusConfigReload: the Windows agents do not reload the Prometheus configuration and still roll, only the target allocator reloads it")
+		}
+	}
+
+	// validate service account annotations
+	if r.Spec.ServiceAccount != "" && len(r.Spec.ServiceAccountAnnotations) > 0 {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec serviceAccountAnnotations configuration is incorrect, the operator does not create a ServiceAccount to annotate when serviceAccount is set")
+	}
+	if r.Spec.PodIdentityAssociation != nil && r.Spec.ServiceAccountAnnotations[irsaRoleAnnotation] != "" {
+		warnings = append(warnings, fmt.Sprintf("the IAM role of the %s annotation takes precedence over the podIdentityAssociation", irsaRoleAnnotation))
+	}
+
+	// validate version
+	if r.Spec.Version != "" && r.Spec.Image != "" {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec version configuration is incorrect, version and image are mutually exclusive")
+	}
+
 	// validate tolerations
 	if r.Spec.Mode == ModeSidecar && len(r.Spec.Tolerations) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'tolerations'", r.Spec.Mode)
@@ -160,19 +362,55 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'priorityClassName'", r.Spec.Mode)
 	}
 
+	// validate termination
+	if r.Spec.Mode == ModeSidecar && r.Spec.TerminationGracePeriodSeconds != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'terminationGracePeriodSeconds'", r.Spec.Mode)
+	}
+	if r.Spec.Lifecycle != nil && r.Spec.Lifecycle.PreStop != nil && r.Spec.Lifecycle.PreStop.Sleep != nil {
+		gracePeriod := int64(corev1.DefaultTerminationGracePeriodSeconds)
+		if r.Spec.TerminationGracePeriodSeconds != nil {
+			gracePeriod = *r.Spec.TerminationGracePeriodSeconds
+		}
+		if r.Spec.Lifecycle.PreStop.Sleep.Seconds >= gracePeriod {
+			warnings = append(warnings, fmt.Sprintf("the preStop sleep of %ds is not shorter than the termination grace period of %ds, the agent will be killed before it can shut down", r.Spec.Lifecycle.PreStop.Sleep.Seconds, gracePeriod))
+		}
+	}
+
 	// validate affinity
 	if r.Spec.Mode == ModeSidecar && r.Spec.Affinity != nil {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'affinity'", r.Spec.Mode)
 	}
 
-	if r.Spec.Mode == ModeSidecar && len(r.Spec.AdditionalContainers) > 0 {
-		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'AdditionalContainers'", r.Spec.Mode)
+	// validate additional containers
+	containerNames := map[string]bool{naming.Container(): true}
+	for _, c := range r.Spec.AdditionalContainers {
+		if containerNames[c.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec AdditionalContainers configuration is incorrect, container name '%s' is already in use", c.Name)
+		}
+		containerNames[c.Name] = true
+	}
+
+	// validate init containers, their names share the pod's container namespace
+	for _, c := range r.Spec.InitContainers {
+		if containerNames[c.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec InitContainers configuration is incorrect, container name '%s' is already in use", c.Name)
+		}
+		if c.Image == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec InitContainers configuration is incorrect, init container '%s' must set an image", c.Name)
+		}
+		containerNames[c.Name] = true
 	}
 
 	// validate target allocation
+	allocationStrategy := r.Spec.TargetAllocator.AllocationStrategy
 	if r.Spec.TargetAllocator.Enabled && r.Spec.Mode != ModeStatefulSet {
 		warnings = append(warnings, fmt.Sprintf("The Amazon CloudWatch Agent mode is set to %s, we do not recommend enabling Target Allocator when not running as a StatefulSet", r.Spec.Mode))
 	}
+	// the allocators only agree on the assignments when they hash the targets consistently
+	if r.Spec.TargetAllocator.Enabled && r.Spec.TargetAllocator.Replicas != nil && *r.Spec.TargetAllocator.Replicas > 1 &&
+		allocationStrategy != "" && allocationStrategy != AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec TargetAllocator configuration is incorrect, more than one replica requires the allocation strategy %s", AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing)
+	}
 
 	// validate Prometheus config for target allocation
 	if r.Spec.TargetAllocator.Enabled {
@@ -205,6 +443,327 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		}
 	}
 
+	// validate env and envFrom
+	for _, env := range r.Spec.Env {
+		if errs := validation.IsEnvVarName(env.Name); len(errs) > 0 {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Env configuration is incorrect, env var name '%s' errors: %s", env.Name, errs)
+		}
+		if env.Value != "" && env.ValueFrom != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Env configuration is incorrect, env var '%s' must not set both value and valueFrom", env.Name)
+		}
+	}
+	for _, envFrom := range r.Spec.EnvFrom {
+		if (envFrom.ConfigMapRef == nil) == (envFrom.SecretRef == nil) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec EnvFrom configuration is incorrect, exactly one of configMapRef or secretRef must be set")
+		}
+		if envFrom.Prefix != "" {
+			if errs := validation.IsEnvVarName(envFrom.Prefix); len(errs) > 0 {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec EnvFrom configuration is incorrect, prefix '%s' errors: %s", envFrom.Prefix, errs)
+			}
+		}
+	}
+
+	// validate service
+	if r.Spec.Mode == ModeSidecar && !reflect.DeepEqual(r.Spec.Service, ServiceSpec{}) {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'service'", r.Spec.Mode)
+	}
+	if len(r.Spec.Service.LoadBalancerSourceRanges) > 0 && r.Spec.Service.Type != corev1.ServiceTypeLoadBalancer {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec Service configuration is incorrect, loadBalancerSourceRanges requires the service type %s", corev1.ServiceTypeLoadBalancer)
+	}
+	for _, cidr := range r.Spec.Service.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Service configuration is incorrect, loadBalancerSourceRanges %w", err)
+		}
+	}
+	if r.Spec.Service.InternalTrafficPolicy != nil && *r.Spec.Service.InternalTrafficPolicy == corev1.ServiceInternalTrafficPolicyLocal && r.Spec.Mode != ModeDaemonSet {
+		warnings = append(warnings, fmt.Sprintf("the internalTrafficPolicy is Local while the mode is %s, telemetry sent from nodes without an agent pod will be dropped", r.Spec.Mode))
+	}
+	if r.Spec.Service.TrafficDistribution != nil && *r.Spec.Service.TrafficDistribution != corev1.ServiceTrafficDistributionPreferClose {
+		warnings = append(warnings, fmt.Sprintf("the trafficDistribution %s may not be supported by the cluster, %s is the generally available value", *r.Spec.Service.TrafficDistribution, corev1.ServiceTrafficDistributionPreferClose))
+	}
+	if len(r.Spec.Service.IPFamilies) > 2 || (len(r.Spec.Service.IPFamilies) == 2 && r.Spec.Service.IPFamilies[0] == r.Spec.Service.IPFamilies[1]) {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec Service configuration is incorrect, ipFamilies must list at most one IPv4 and one IPv6 family")
+	}
+	if len(r.Spec.Service.IPFamilies) == 2 && r.Spec.Service.IPFamilyPolicy != nil && *r.Spec.Service.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec Service configuration is incorrect, ipFamilyPolicy %s allows a single ipFamily", corev1.IPFamilyPolicySingleStack)
+	}
+	if r.Spec.Service.Type == "" || r.Spec.Service.Type == corev1.ServiceTypeClusterIP {
+		for _, p := range r.Spec.Ports {
+			if p.NodePort != 0 {
+				warnings = append(warnings, fmt.Sprintf("port '%s' sets nodePort %d, which is ignored unless the service type is NodePort or LoadBalancer", p.Name, p.NodePort))
+			}
+		}
+	}
+
+	// validate the agent JSON configuration
+	if r.Spec.Config != "" {
+		configWarnings, err := adapters.ValidateJSONConfig(r.Spec.Config)
+		warnings = append(warnings, configWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Config configuration is incorrect, %w", err)
+		}
+	}
+
+	// validate the OTel configuration
+	if r.Spec.OtelConfig != "" {
+		otelConfig, err := adapters.ConfigFromString(r.Spec.OtelConfig)
+		if err != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec OtelConfig configuration is incorrect, %w", err)
+		}
+		otelWarnings, err := adapters.ValidateOtelConfig(otelConfig)
+		warnings = append(warnings, otelWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec OtelConfig configuration is incorrect, %w", err)
+		}
+	}
+
+	// validate the EMF settings
+	if r.Spec.EMF != nil {
+		if err := validateEMF(r.Spec.EMF); err != nil {
+			return warnings, err
+		}
+		if otelConfig, err := adapters.ConfigFromString(r.Spec.OtelConfig); err != nil || len(adapters.EMFExporters(otelConfig)) == 0 {
+			warnings = append(warnings, "emf: the EMF settings are ignored, as otelConfig has no awsemf exporter")
+		}
+	}
+
+	// validate the self telemetry
+	if selfTelemetry := r.Spec.SelfTelemetry; selfTelemetry != nil && selfTelemetry.Enabled {
+		if otelConfig, err := adapters.ConfigFromString(r.Spec.OtelConfig); err == nil && hasTelemetryMetricsAddress(otelConfig) {
+			warnings = append(warnings, "selfTelemetry: the service telemetry metrics address of otelConfig takes precedence over the selfTelemetry port")
+		}
+	}
+
+	// validate node groups
+	if len(r.Spec.NodeGroups) > 0 && r.Spec.Mode != ModeDaemonSet {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'nodeGroups'", r.Spec.Mode)
+	}
+	nodeGroupNames := map[string]bool{}
+	for _, group := range r.Spec.NodeGroups {
+		if group.Name == "" || group.Name == "prometheus-config" || group.Name == "target-allocator" || (r.Spec.Windows != nil && group.Name == "windows") || (r.Spec.Hybrid != nil && group.Name == "hybrid") ||
+			(r.Spec.EFAMetrics != nil && r.Spec.EFAMetrics.Enabled && group.Name == "efa") {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec NodeGroups configuration is incorrect, node group name '%s' is empty or reserved", group.Name)
+		}
+		if nodeGroupNames[group.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec NodeGroups configuration is incorrect, node group name '%s' is used more than once", group.Name)
+		}
+		nodeGroupNames[group.Name] = true
+		if len(group.NodeSelector) == 0 {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec NodeGroups configuration is incorrect, node group '%s' must set a nodeSelector", group.Name)
+		}
+		if group.Config != "" {
+			groupWarnings, err := adapters.ValidateJSONConfig(group.Config)
+			for _, w := range groupWarnings {
+				warnings = append(warnings, fmt.Sprintf("node group %s: %s", group.Name, w))
+			}
+			if err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec NodeGroups configuration is incorrect, node group '%s': %w", group.Name, err)
+			}
+		}
+	}
+
+	// validate the container runtime sockets
+	if r.Spec.ContainerRuntime != nil && r.Spec.Mode != ModeDaemonSet {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'containerRuntime'", r.Spec.Mode)
+	}
+	if err := checkContainerRuntime(r.Spec.ContainerRuntime); err != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec ContainerRuntime configuration is incorrect, %w", err)
+	}
+	for _, group := range r.Spec.NodeGroups {
+		if err := checkContainerRuntime(group.ContainerRuntime); err != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec NodeGroups configuration is incorrect, node group '%s': %w", group.Name, err)
+		}
+	}
+
+	// validate host mounts
+	if len(r.Spec.HostMounts) > 0 && r.Spec.Mode != ModeDaemonSet {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'hostMounts'", r.Spec.Mode)
+	}
+	volumeNames := map[string]bool{
+		naming.ConfigMapVolume():              true,
+		naming.PrometheusConfigMapVolume():    true,
+		naming.ContainerRuntimeSocketVolume(): true,
+	}
+	for _, volume := range r.Spec.Volumes {
+		volumeNames[volume.Name] = true
+	}
+	if efa := r.Spec.EFAMetrics; efa != nil && efa.Enabled {
+		volumeNames[naming.EFADevicesVolume()] = true
+		volumeNames[naming.SysDevicesVolume()] = true
+		volumeNames[naming.PodResourcesVolume()] = true
+	}
+	for _, hostMount := range r.Spec.HostMounts {
+		if volumeNames[hostMount.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec HostMounts configuration is incorrect, volume name '%s' is already used by the agent pods", hostMount.Name)
+		}
+		volumeNames[hostMount.Name] = true
+		if !path.IsAbs(hostMount.Path) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec HostMounts configuration is incorrect, path '%s' must be an absolute path", hostMount.Path)
+		}
+		if hostMount.MountPath != "" && !path.IsAbs(hostMount.MountPath) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec HostMounts configuration is incorrect, mountPath '%s' must be an absolute path", hostMount.MountPath)
+		}
+	}
+	for _, volume := range r.Spec.Volumes {
+		if volume.HostPath == nil {
+			continue
+		}
+		for _, volumeMount := range r.Spec.VolumeMounts {
+			if volumeMount.Name == volume.Name && !volumeMount.ReadOnly {
+				warnings = append(warnings, fmt.Sprintf("volume '%s' mounts the host path '%s' writable, use hostMounts to mount it read-only", volume.Name, volume.HostPath.Path))
+				break
+			}
+		}
+	}
+
+	// validate hardening
+	if r.Spec.Hardening != nil {
+		if r.Spec.ContainerRuntime != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Hardening configuration is incorrect, the container runtime socket is only accessible to root, remove containerRuntime")
+		}
+		for _, group := range r.Spec.NodeGroups {
+			if group.ContainerRuntime != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec Hardening configuration is incorrect, the container runtime socket is only accessible to root, remove containerRuntime from node group '%s'", group.Name)
+			}
+		}
+		if r.Spec.Hybrid != nil && r.Spec.Hybrid.ContainerRuntime != nil {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Hardening configuration is incorrect, the container runtime socket is only accessible to root, remove containerRuntime from hybrid")
+		}
+		if r.Spec.SecurityContext != nil && r.Spec.SecurityContext.Privileged != nil && *r.Spec.SecurityContext.Privileged {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Hardening configuration is incorrect, the agent cannot be privileged")
+		}
+	}
+
+	// validate the windows agents
+	if r.Spec.Windows != nil {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'windows'", r.Spec.Mode)
+		}
+		if os, ok := r.Spec.Windows.NodeSelector[corev1.LabelOSStable]; ok && os != string(corev1.Windows) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Windows configuration is incorrect, nodeSelector '%s' must be '%s'", corev1.LabelOSStable, corev1.Windows)
+		}
+		if r.Spec.Windows.Config != "" {
+			windowsWarnings, err := adapters.ValidateJSONConfig(r.Spec.Windows.Config)
+			for _, w := range windowsWarnings {
+				warnings = append(warnings, fmt.Sprintf("windows: %s", w))
+			}
+			if err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec Windows configuration is incorrect, %w", err)
+			}
+		}
+	}
+
+	// validate the hybrid agents
+	if r.Spec.Hybrid != nil {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'hybrid'", r.Spec.Mode)
+		}
+		if r.Spec.Hybrid.Config != "" {
+			hybridWarnings, err := adapters.ValidateJSONConfig(r.Spec.Hybrid.Config)
+			for _, w := range hybridWarnings {
+				warnings = append(warnings, fmt.Sprintf("hybrid: %s", w))
+			}
+			if err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec Hybrid configuration is incorrect, %w", err)
+			}
+		}
+		for _, hostMount := range r.Spec.Hybrid.HostMounts {
+			if hostMount.Name == naming.AWSCredentialsVolume() || !path.IsAbs(hostMount.Path) || (hostMount.MountPath != "" && !path.IsAbs(hostMount.MountPath)) {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec Hybrid configuration is incorrect, host mount '%s' must have absolute paths and a name other than '%s'", hostMount.Name, naming.AWSCredentialsVolume())
+			}
+		}
+		if r.Spec.Hybrid.CredentialsFile != "" && !path.IsAbs(r.Spec.Hybrid.CredentialsFile) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Hybrid configuration is incorrect, credentialsFile '%s' must be an absolute path", r.Spec.Hybrid.CredentialsFile)
+		}
+		// the hybrid nodes have no instance metadata to detect the region from
+		if (r.Spec.AWS == nil || r.Spec.AWS.Region == "") && agentRegion(r.Spec.Config) == "" && agentRegion(r.Spec.Hybrid.Config) == "" && c.cfg.AWSRegion() == "" {
+			warnings = append(warnings, "the region of the agent can't be detected on the hybrid nodes, set aws.region")
+		}
+	}
+
+	// validate config sources
+	for i, source := range r.Spec.ConfigSources {
+		if (source.Inline == "") == (source.ConfigMap == nil) {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec ConfigSources configuration is incorrect, source %d must set exactly one of inline or configMap", i)
+		}
+		if source.ConfigMap != nil && (source.ConfigMap.Name == "" || source.ConfigMap.Key == "") {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec ConfigSources configuration is incorrect, source %d must set the configMap name and key", i)
+		}
+		if source.Inline != "" {
+			sourceWarnings, err := adapters.ValidateJSONConfig(source.Inline)
+			for _, w := range sourceWarnings {
+				warnings = append(warnings, fmt.Sprintf("config source %d: %s", i, w))
+			}
+			if err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec ConfigSources configuration is incorrect, source %d: %w", i, err)
+			}
+		}
+	}
+
+	// validate config secret
+	if r.Spec.ConfigSecret != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'configSecret'", r.Spec.Mode)
+		}
+		if r.Spec.ConfigSecret.Name == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec ConfigSecret configuration is incorrect, name is required")
+		}
+		for _, item := range r.Spec.ConfigSecret.Items {
+			if item.Path == c.cfg.CollectorConfigMapEntry() || item.Path == c.cfg.OtelCollectorConfigMapEntry() {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec ConfigSecret configuration is incorrect, path '%s' would replace the configuration rendered by the operator", item.Path)
+			}
+		}
+	}
+
+	// validate volumes and volume mounts
+	reservedVolumes := map[string]bool{
+		naming.ConfigMapVolume():           true,
+		naming.PrometheusConfigMapVolume(): true,
+	}
+	for _, cm := range r.Spec.ConfigMaps {
+		reservedVolumes[naming.ConfigMapExtra(cm.Name)] = true
+	}
+	volumes := map[string]bool{}
+	for _, v := range r.Spec.Volumes {
+		if reservedVolumes[v.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Volumes configuration is incorrect, volume name '%s' is reserved for volumes managed by the operator", v.Name)
+		}
+		if volumes[v.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Volumes configuration is incorrect, volume name '%s' is used more than once", v.Name)
+		}
+		volumes[v.Name] = true
+	}
+	for _, pvc := range r.Spec.VolumeClaimTemplates {
+		volumes[pvc.Name] = true
+	}
+	for _, m := range r.Spec.VolumeMounts {
+		if !volumes[m.Name] && !reservedVolumes[m.Name] {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec VolumeMounts configuration is incorrect, volume mount '%s' does not reference a volume", m.Name)
+		}
+	}
+
+	// validate host network port usage
+	if r.Spec.HostNetwork {
+		hostPorts := map[string]string{}
+		for _, p := range r.Spec.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			key := fmt.Sprintf("%d/%s", p.Port, protocol)
+			if name, ok := hostPorts[key]; ok {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec Ports configuration is incorrect, ports '%s' and '%s' both bind host port %s when hostNetwork is enabled", name, p.Name, key)
+			}
+			hostPorts[key] = p.Name
+		}
+		if (r.Spec.Mode == ModeDeployment || r.Spec.Mode == ModeStatefulSet) && r.Spec.Replicas != nil && *r.Spec.Replicas > 1 {
+			warnings = append(warnings, fmt.Sprintf("hostNetwork is enabled with %d replicas, pods scheduled on the same node will fail to bind their ports", *r.Spec.Replicas))
+		}
+	}
+	if r.Spec.DNSPolicy == corev1.DNSNone && r.Spec.DNSConfig == nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec dnsConfig configuration is incorrect, dnsConfig is required when dnsPolicy is %s", corev1.DNSNone)
+	}
+
 	var maxReplicas *int32
 	if r.Spec.Autoscaler != nil && r.Spec.Autoscaler.MaxReplicas != nil {
 		maxReplicas = r.Spec.Autoscaler.MaxReplicas
@@ -237,6 +796,10 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		)
 	}
 
+	if maxReplicas != nil && (r.Spec.Mode == ModeDaemonSet || r.Spec.Mode == ModeSidecar) {
+		warnings = append(warnings, fmt.Sprintf("The Amazon CloudWatch Agent mode is set to %s, which does not support autoscaling. The autoscaler settings are ignored", r.Spec.Mode))
+	}
+
 	// validate autoscale with horizontal pod autoscaler
 	if maxReplicas != nil {
 		if *maxReplicas < int32(1) {
@@ -268,41 +831,124 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 	if r.Spec.Ingress.RuleType == IngressRuleTypeSubdomain && (r.Spec.Ingress.Hostname == "" || r.Spec.Ingress.Hostname == "*") {
 		return warnings, fmt.Errorf("a valid Ingress hostname has to be defined for subdomain ruleType")
 	}
-
-	if r.Spec.LivenessProbe != nil {
-		if r.Spec.LivenessProbe.InitialDelaySeconds != nil && *r.Spec.LivenessProbe.InitialDelaySeconds < 0 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe InitialDelaySeconds configuration is incorrect. InitialDelaySeconds should be greater than or equal to 0")
-		}
-		if r.Spec.LivenessProbe.PeriodSeconds != nil && *r.Spec.LivenessProbe.PeriodSeconds < 1 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe PeriodSeconds configuration is incorrect. PeriodSeconds should be greater than or equal to 1")
-		}
-		if r.Spec.LivenessProbe.TimeoutSeconds != nil && *r.Spec.LivenessProbe.TimeoutSeconds < 1 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe TimeoutSeconds configuration is incorrect. TimeoutSeconds should be greater than or equal to 1")
+	if r.Spec.Ingress.Type == IngressTypeGateway {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ingress configuration is incorrect. Gateway routes can only be used in combination with the modes: %s, %s, %s",
+				ModeDeployment, ModeDaemonSet, ModeStatefulSet,
+			)
 		}
-		if r.Spec.LivenessProbe.SuccessThreshold != nil && *r.Spec.LivenessProbe.SuccessThreshold < 1 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe SuccessThreshold configuration is incorrect. SuccessThreshold should be greater than or equal to 1")
+		if len(r.Spec.Ingress.Gateway.ParentRefs) == 0 {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ingress configuration is incorrect, the gateway routes need at least one parentRef")
 		}
-		if r.Spec.LivenessProbe.FailureThreshold != nil && *r.Spec.LivenessProbe.FailureThreshold < 1 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe FailureThreshold configuration is incorrect. FailureThreshold should be greater than or equal to 1")
-		}
-		if r.Spec.LivenessProbe.TerminationGracePeriodSeconds != nil && *r.Spec.LivenessProbe.TerminationGracePeriodSeconds < 1 {
-			return warnings, fmt.Errorf("the OpenTelemetry Spec LivenessProbe TerminationGracePeriodSeconds configuration is incorrect. TerminationGracePeriodSeconds should be greater than or equal to 1")
+		// the routes of the receivers are told apart by their hosts, as the OTLP receivers share their paths and gRPC services
+		if r.Spec.Ingress.Hostname == "" || r.Spec.Ingress.Hostname == "*" {
+			return warnings, fmt.Errorf("a valid Ingress hostname has to be defined for gateway type")
 		}
 	}
 
+	if err := checkProbe("LivenessProbe", r.Spec.LivenessProbe); err != nil {
+		return warnings, err
+	}
+	if err := checkProbe("ReadinessProbe", r.Spec.ReadinessProbe); err != nil {
+		return warnings, err
+	}
+	if r.Spec.ReadinessProbe != nil && r.Spec.ReadinessProbe.TerminationGracePeriodSeconds != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec ReadinessProbe TerminationGracePeriodSeconds configuration is incorrect. TerminationGracePeriodSeconds must not be set for readiness probes")
+	}
+	if err := checkProbe("StartupProbe", r.Spec.StartupProbe); err != nil {
+		return warnings, err
+	}
+	if r.Spec.StartupProbe != nil && r.Spec.StartupProbe.SuccessThreshold != nil && *r.Spec.StartupProbe.SuccessThreshold != 1 {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec StartupProbe SuccessThreshold configuration is incorrect. SuccessThreshold must be 1")
+	}
+
 	// validate updateStrategy for DaemonSet
 	if r.Spec.Mode != ModeDaemonSet && len(r.Spec.UpdateStrategy.Type) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'updateStrategy'", r.Spec.Mode)
 	}
+	if err := checkDaemonSetUpdateStrategy(r.Spec.UpdateStrategy); err != nil {
+		return warnings, err
+	}
 
 	// validate updateStrategy for Deployment
 	if r.Spec.Mode != ModeDeployment && len(r.Spec.DeploymentUpdateStrategy.Type) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'deploymentUpdateStrategy'", r.Spec.Mode)
 	}
+	if r.Spec.DeploymentUpdateStrategy.Type == appsv1.RecreateDeploymentStrategyType && r.Spec.DeploymentUpdateStrategy.RollingUpdate != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec deploymentUpdateStrategy configuration is incorrect, rollingUpdate must not be set when the type is %s", r.Spec.DeploymentUpdateStrategy.Type)
+	}
+
+	// validate rollout timing
+	if r.Spec.Mode != ModeDeployment && r.Spec.ProgressDeadlineSeconds != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'progressDeadlineSeconds'", r.Spec.Mode)
+	}
+	if r.Spec.ProgressDeadlineSeconds != nil && *r.Spec.ProgressDeadlineSeconds <= r.Spec.MinReadySeconds {
+		return warnings, fmt.Errorf("the OpenTelemetry Spec progressDeadlineSeconds configuration is incorrect, progressDeadlineSeconds must be greater than minReadySeconds")
+	}
 
 	return warnings, nil
 }
 
+// checkProbe validates the user provided settings of the probe with the given name.
+func checkProbe(name string, probe *Probe) error {
+	if probe == nil {
+		return nil
+	}
+	if probe.InitialDelaySeconds != nil && *probe.InitialDelaySeconds < 0 {
+		return fmt.Errorf("the OpenTelemetry Spec %s InitialDelaySeconds configuration is incorrect. InitialDelaySeconds should be greater than or equal to 0", name)
+	}
+	if probe.PeriodSeconds != nil && *probe.PeriodSeconds < 1 {
+		return fmt.Errorf("the OpenTelemetry Spec %s PeriodSeconds configuration is incorrect. PeriodSeconds should be greater than or equal to 1", name)
+	}
+	if probe.TimeoutSeconds != nil && *probe.TimeoutSeconds < 1 {
+		return fmt.Errorf("the OpenTelemetry Spec %s TimeoutSeconds configuration is incorrect. TimeoutSeconds should be greater than or equal to 1", name)
+	}
+	if probe.SuccessThreshold != nil && *probe.SuccessThreshold < 1 {
+		return fmt.Errorf("the OpenTelemetry Spec %s SuccessThreshold configuration is incorrect. SuccessThreshold should be greater than or equal to 1", name)
+	}
+	if probe.FailureThreshold != nil && *probe.FailureThreshold < 1 {
+		return fmt.Errorf("the OpenTelemetry Spec %s FailureThreshold configuration is incorrect. FailureThreshold should be greater than or equal to 1", name)
+	}
+	if probe.TerminationGracePeriodSeconds != nil && *probe.TerminationGracePeriodSeconds < 1 {
+		return fmt.Errorf("the OpenTelemetry Spec %s TerminationGracePeriodSeconds configuration is incorrect. TerminationGracePeriodSeconds should be greater than or equal to 1", name)
+	}
+	return nil
+}
+
+func checkDaemonSetUpdateStrategy(strategy appsv1.DaemonSetUpdateStrategy) error {
+	if strategy.RollingUpdate == nil {
+		return nil
+	}
+	if strategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		return fmt.Errorf("the OpenTelemetry Spec updateStrategy configuration is incorrect, rollingUpdate must not be set when the type is %s", strategy.Type)
+	}
+	if isZeroIntOrString(strategy.RollingUpdate.MaxUnavailable) && isZeroIntOrString(strategy.RollingUpdate.MaxSurge) {
+		return fmt.Errorf("the OpenTelemetry Spec updateStrategy configuration is incorrect, maxUnavailable and maxSurge must not both be 0")
+	}
+	return nil
+}
+
+// isZeroIntOrString returns true if the value is explicitly set to 0 or 0%.
+func checkContainerRuntime(containerRuntime *ContainerRuntimeSpec) error {
+	if containerRuntime == nil || containerRuntime.SocketPath == "" {
+		return nil
+	}
+	if !path.IsAbs(containerRuntime.SocketPath) {
+		return fmt.Errorf("socketPath '%s' must be an absolute path", containerRuntime.SocketPath)
+	}
+	return nil
+}
+
+func isZeroIntOrString(value *intstr.IntOrString) bool {
+	if value == nil {
+		return false
+	}
+	if value.Type == intstr.Int {
+		return value.IntVal == 0
+	}
+	return strings.TrimSuffix(value.StrVal, "%") == "0"
+}
+
 func checkAutoscalerSpec(autoscaler *AutoscalerSpec) error {
 	if autoscaler.Behavior != nil {
 		if autoscaler.Behavior.ScaleDown != nil && autoscaler.Behavior.ScaleDown.StabilizationWindowSeconds != nil &&
@@ -323,36 +969,171 @@ func checkAutoscalerSpec(autoscaler *AutoscalerSpec) error {
 	}
 
 	for _, metric := range autoscaler.Metrics {
-		if metric.Type != autoscalingv2.PodsMetricSourceType {
-			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pod")
+		var target autoscalingv2.MetricTarget
+		switch {
+		case metric.Type == autoscalingv2.PodsMetricSourceType && metric.Pods != nil:
+			target = metric.Pods.Target
+		case metric.Type == autoscalingv2.ExternalMetricSourceType && metric.External != nil:
+			target = metric.External.Target
+		default:
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pods or External")
 		}
 
-		// pod metrics target only support value and averageValue.
-		if metric.Pods.Target.Type == autoscalingv2.AverageValueMetricType {
-			if val, ok := metric.Pods.Target.AverageValue.AsInt64(); !ok || val < int64(1) {
+		// pod and external metrics target only support value and averageValue.
+		if target.Type == autoscalingv2.AverageValueMetricType {
+			if val, ok := target.AverageValue.AsInt64(); !ok || val < int64(1) {
 				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0")
 			}
-		} else if metric.Pods.Target.Type == autoscalingv2.ValueMetricType {
-			if val, ok := metric.Pods.Target.Value.AsInt64(); !ok || val < int64(1) {
+		} else if target.Type == autoscalingv2.ValueMetricType {
+			if val, ok := target.Value.AsInt64(); !ok || val < int64(1) {
 				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, value should be greater than 0")
 			}
 		} else {
-			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type")
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, invalid %s target type", strings.ToLower(string(metric.Type)))
 		}
 	}
 
 	return nil
 }
 
-func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
-	cvw := &CollectorWebhook{
-		logger: mgr.GetLogger().WithValues("handler", "CollectorWebhook"),
-		scheme: mgr.GetScheme(),
+func NewCollectorWebhook(logger logr.Logger, scheme *runtime.Scheme, cfg config.Config) *CollectorWebhook {
+	return &CollectorWebhook{
+		logger: logger,
+		scheme: scheme,
 		cfg:    cfg,
 	}
+}
+
+func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
+	cvw := NewCollectorWebhook(
+		mgr.GetLogger().WithValues("handler", "CollectorWebhook"),
+		mgr.GetScheme(),
+		cfg,
+	)
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AmazonCloudWatchAgent{}).
 		WithValidator(cvw).
 		WithDefaulter(cvw).
 		Complete()
 }
+
+// validateWatchedNamespace rejects the resources created in a namespace the operator doesn't watch when it is
+// namespace-scoped, as they would never be reconciled. The deletions are always allowed.
+// offAWSWarnings returns the warnings about the settings the agent detects from the instance metadata on AWS, which
+// have to be set on the clusters of the other cloud providers.
+func offAWSWarnings(r *AmazonCloudWatchAgent, provider string) admission.Warnings {
+	var warnings admission.Warnings
+	conf, err := adapters.ConfigStructFromJSONString(r.Spec.Config)
+	if err != nil || conf == nil {
+		conf = &adapters.CwaConfig{}
+	}
+	if (r.Spec.AWS == nil || r.Spec.AWS.Region == "") && conf.GetRegion() == "" {
+		warnings = append(warnings, fmt.Sprintf("the region of the agent can't be detected on a %s cluster, set aws.region", provider))
+	}
+	if conf.Logs != nil && conf.Logs.LogMetricsCollected != nil && conf.Logs.LogMetricsCollected.Kubernetes != nil && conf.GetKubernetesClusterName() == "" {
+		warnings = append(warnings, fmt.Sprintf("the cluster of the kubernetes section can't be detected on a %s cluster, set its cluster_name", provider))
+	}
+	return warnings
+}
+
+func validateWatchedNamespace(cfg config.Config, kind, namespace string) error {
+	if cfg.Watches(namespace) {
+		return nil
+	}
+	return fmt.Errorf("the %s can't be managed in the namespace %s, the operator only watches the namespaces %s", kind, namespace, strings.Join(cfg.WatchNamespaces(), ", "))
+}
+
+// isProxyURL returns whether the proxy is an http or https URL with a host, as expected by the agent.
+func isProxyURL(proxy string) bool {
+	u, err := url.Parse(proxy)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// agentRegion returns the region of the agent section of the agent configuration.
+func agentRegion(config string) string {
+	conf, err := adapters.ConfigStructFromJSONString(config)
+	if err != nil || conf == nil {
+		return ""
+	}
+	return conf.GetRegion()
+}
+
+// validateMultilinePattern checks that the containers of a multiline pattern are selected by their names, and that its
+// regular expressions can be quoted in the rules of the Fluent Bit parsers.
+func validateMultilinePattern(pattern MultilinePattern) error {
+	if errs := validation.IsDNS1123Label(pattern.Namespace); pattern.Namespace != "" && len(errs) > 0 {
+		return fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, the namespace %q of a multiline pattern is invalid: %s", pattern.Namespace, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Label(pattern.Container); pattern.Container != "" && len(errs) > 0 {
+		return fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, the container %q of a multiline pattern is invalid: %s", pattern.Container, strings.Join(errs, ", "))
+	}
+	if strings.Contains(pattern.StartPattern, `"`) || strings.Contains(pattern.ContinuePattern, `"`) {
+		return fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, the patterns of a multiline pattern cannot contain double quotes")
+	}
+	return nil
+}
+
+// logGroupPlaceholder matches the placeholders of the names of the log groups.
+var logGroupPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// validateLogGroupName checks that the name of a log group only holds the supported placeholders.
+func validateLogGroupName(logGroup, name string, placeholders ...string) error {
+	for _, placeholder := range logGroupPlaceholder.FindAllString(name, -1) {
+		if !slices.Contains(placeholders, placeholder) {
+			return fmt.Errorf("the OpenTelemetry Spec logs configuration is incorrect, the %s log group name %q holds the placeholder %s, expected one of %s", logGroup, name, placeholder, strings.Join(placeholders, ", "))
+		}
+	}
+	return nil
+}
+
+// hasTelemetryMetricsAddress returns whether the OpenTelemetry configuration sets the address of the internal metrics.
+func hasTelemetryMetricsAddress(otelConfig map[interface{}]interface{}) bool {
+	service, _ := otelConfig["service"].(map[interface{}]interface{})
+	telemetry, _ := service["telemetry"].(map[interface{}]interface{})
+	metrics, _ := telemetry["metrics"].(map[interface{}]interface{})
+	return metrics["address"] != nil
+}
+
+// emfPlaceholders are the placeholders the awsemf exporter replaces in the names of the log groups and streams.
+var emfPlaceholders = []string{"{ClusterName}", "{ContainerInstanceId}", "{NodeName}", "{TaskDefinitionFamily}", "{TaskId}"}
+
+var (
+	emfNamespace    = regexp.MustCompile(`^[0-9A-Za-z.\-_/#: ]*$`)
+	emfLogGroupName = regexp.MustCompile(`^[.\-_/#A-Za-z0-9]{1,512}$`)
+)
+
+// validateEMF checks the EMF settings against the naming rules of the CloudWatch namespaces, log groups and log
+// streams, once the placeholders are replaced.
+func validateEMF(emf *EMFSpec) error {
+	if emf.Namespace != "" {
+		if !emfNamespace.MatchString(emf.Namespace) || strings.TrimSpace(emf.Namespace) == "" {
+			return fmt.Errorf("the OpenTelemetry Spec EMF configuration is incorrect, the namespace %q must only hold alphanumeric characters, spaces and the characters . - _ / # :", emf.Namespace)
+		}
+		if strings.HasPrefix(emf.Namespace, "AWS/") {
+			return fmt.Errorf("the OpenTelemetry Spec EMF configuration is incorrect, the namespace %q cannot start with AWS/, which is reserved for the AWS services", emf.Namespace)
+		}
+	}
+	for _, name := range []struct{ field, value string }{{"logGroupName", emf.LogGroupName}, {"logStreamName", emf.LogStreamName}} {
+		for _, placeholder := range logGroupPlaceholder.FindAllString(name.value, -1) {
+			if !slices.Contains(emfPlaceholders, placeholder) {
+				return fmt.Errorf("the OpenTelemetry Spec EMF configuration is incorrect, the %s %q holds the placeholder %s, expected one of %s", name.field, name.value, placeholder, strings.Join(emfPlaceholders, ", "))
+			}
+		}
+	}
+	if emf.LogGroupName != "" && !emfLogGroupName.MatchString(logGroupPlaceholder.ReplaceAllString(emf.LogGroupName, "placeholder")) {
+		return fmt.Errorf("the OpenTelemetry Spec EMF configuration is incorrect, the logGroupName %q must be at most 512 characters among alphanumeric characters and . - _ / #", emf.LogGroupName)
+	}
+	if strings.ContainsAny(emf.LogStreamName, ":*") || len(emf.LogStreamName) > 512 {
+		return fmt.Errorf("the OpenTelemetry Spec EMF configuration is incorrect, the logStreamName %q must be at most 512 characters and cannot contain : or *", emf.LogStreamName)
+	}
+	return nil
+}
+
+// agentClusterName returns the cluster name of the kubernetes section of the agent configuration.
+func agentClusterName(config string) string {
+	conf, err := adapters.ConfigStructFromJSONString(config)
+	if err != nil || conf == nil {
+		return ""
+	}
+	return conf.GetKubernetesClusterName()
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -161,6 +163,34 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "daemonset priority class",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:              ModeDaemonSet,
+					Replicas:          &one,
+					UpgradeStrategy:   UpgradeStrategyAutomatic,
+					ManagementState:   ManagementStateManaged,
+					PriorityClassName: DefaultDaemonSetPriorityClassName,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 1,
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "MaxReplicas but no Autoscale",
 			otelcol: AmazonCloudWatchAgent{
@@ -265,6 +295,39 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "target allocator enabled",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{Enabled: true},
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeStatefulSet,
+					Replicas:        &one,
+					UpgradeStrategy: UpgradeStrategyAutomatic,
+					ManagementState: ManagementStateManaged,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						Replicas:           &one,
+						AllocationStrategy: AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing,
+					},
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 1,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -299,9 +362,13 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 	minusOne := int32(-1)
 	zero := int32(0)
 	zero64 := int64(0)
+	one64 := int64(1)
+	localTrafficPolicy := v1.ServiceInternalTrafficPolicyLocal
+	singleStack := v1.IPFamilyPolicySingleStack
 	one := int32(1)
 	three := int32(3)
 	five := int32(5)
+	privileged := true
 
 	promCfg := PrometheusConfig{}
 	err := yaml.Unmarshal([]byte(promCfgYaml), &promCfg)
@@ -365,6 +432,58 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "does not support the attribute 'volumeClaimTemplates'",
 		},
+		{
+			name: "invalid mode with persistentVolumeClaimRetentionPolicy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                                 ModeDeployment,
+					PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{},
+				},
+			},
+			expectedErr: "does not support the attribute 'persistentVolumeClaimRetentionPolicy'",
+		},
+		{
+			name: "invalid podDisruptionBudget",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MinAvailable:   &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+						MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+					},
+				},
+			},
+			expectedErr: "minAvailable and maxUnavailable are mutually exclusive",
+		},
+		{
+			name: "rollingUpdate with OnDelete updateStrategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type:          appsv1.OnDeleteDaemonSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{},
+					},
+				},
+			},
+			expectedErr: "rollingUpdate must not be set when the type is OnDelete",
+		},
+		{
+			name: "updateStrategy without progress",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type: appsv1.RollingUpdateDaemonSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "0%"},
+							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+						},
+					},
+				},
+			},
+			expectedErr: "maxUnavailable and maxSurge must not both be 0",
+		},
 		{
 			name: "invalid mode with tolerations",
 			otelcol: AmazonCloudWatchAgent{
@@ -387,6 +506,20 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Spec Prometheus configuration is incorrect",
 		},
+		{
+			name: "replicated target allocator without consistent hashing",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						Replicas:           &three,
+						AllocationStrategy: AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec TargetAllocator configuration is incorrect, more than one replica requires the allocation strategy consistent-hashing",
+		},
 		{
 			name: "invalid port name",
 			otelcol: AmazonCloudWatchAgent{
@@ -431,6 +564,140 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
 		},
+		{
+			name: "invalid env var name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Env: []v1.EnvVar{{Name: "1PROXY", Value: "http://proxy:3128"}},
+				},
+			},
+			expectedErr: "env var name '1PROXY' errors",
+		},
+		{
+			name: "envFrom without a source",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EnvFrom: []v1.EnvFromSource{{Prefix: "AWS_"}},
+				},
+			},
+			expectedErr: "exactly one of configMapRef or secretRef must be set",
+		},
+		{
+			name: "service in sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:    ModeSidecar,
+					Service: ServiceSpec{Type: v1.ServiceTypeNodePort},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'service'",
+		},
+		{
+			name: "loadBalancerSourceRanges without a LoadBalancer service",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Service: ServiceSpec{
+						Type:                     v1.ServiceTypeNodePort,
+						LoadBalancerSourceRanges: []string{"10.0.0.0/16"},
+					},
+				},
+			},
+			expectedErr: "loadBalancerSourceRanges requires the service type LoadBalancer",
+		},
+		{
+			name: "local internalTrafficPolicy outside daemonset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Service: ServiceSpec{
+						InternalTrafficPolicy: &localTrafficPolicy,
+					},
+					MaxReplicas: &zero,
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"the internalTrafficPolicy is Local while the mode is deployment, telemetry sent from nodes without an agent pod will be dropped",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "duplicate ipFamilies",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Service: ServiceSpec{
+						IPFamilies: []v1.IPFamily{v1.IPv6Protocol, v1.IPv6Protocol},
+					},
+				},
+			},
+			expectedErr: "ipFamilies must list at most one IPv4 and one IPv6 family",
+		},
+		{
+			name: "dual-stack ipFamilies with a SingleStack policy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Service: ServiceSpec{
+						IPFamilies:     []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+						IPFamilyPolicy: &singleStack,
+					},
+				},
+			},
+			expectedErr: "ipFamilyPolicy SingleStack allows a single ipFamily",
+		},
+		{
+			name: "invalid loadBalancerSourceRanges",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Service: ServiceSpec{
+						Type:                     v1.ServiceTypeLoadBalancer,
+						LoadBalancerSourceRanges: []string{"10.0.0.0"},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Service configuration is incorrect, loadBalancerSourceRanges invalid CIDR address: 10.0.0.0",
+		},
+		{
+			name: "volume name reserved by the operator",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Volumes: []v1.Volume{{Name: "otc-internal"}},
+				},
+			},
+			expectedErr: "volume name 'otc-internal' is reserved for volumes managed by the operator",
+		},
+		{
+			name: "volume mount without a volume",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Volumes:      []v1.Volume{{Name: "ca-bundle"}},
+					VolumeMounts: []v1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ssl/custom"}, {Name: "host-logs", MountPath: "/var/log"}},
+				},
+			},
+			expectedErr: "volume mount 'host-logs' does not reference a volume",
+		},
+		{
+			name: "conflicting host ports",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					HostNetwork: true,
+					Ports: []v1.ServicePort{
+						{Name: "statsd", Port: 8125, Protocol: v1.ProtocolUDP},
+						{Name: "custom", Port: 8125, Protocol: v1.ProtocolTCP},
+						{Name: "other", Port: 8125},
+					},
+				},
+			},
+			expectedErr: "ports 'custom' and 'other' both bind host port 8125/TCP when hostNetwork is enabled",
+		},
+		{
+			name: "dnsPolicy None without dnsConfig",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					DNSPolicy: v1.DNSNone,
+				},
+			},
+			expectedErr: "dnsConfig is required when dnsPolicy is None",
+		},
 		{
 			name: "invalid max replicas",
 			otelcol: AmazonCloudWatchAgent{
@@ -602,6 +869,46 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type",
 			expectedWarnings: []string{"MaxReplicas is deprecated"},
 		},
+		{
+			name: "invalid external metric value",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &three,
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.ExternalMetricSourceType,
+								External: &autoscalingv2.ExternalMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: "otlp_requests_per_second",
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type:  autoscalingv2.ValueMetricType,
+										Value: resource.NewQuantity(int64(0), resource.DecimalSI),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec autoscale configuration is incorrect, value should be greater than 0",
+		},
+		{
+			name: "autoscaler in daemonset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &three,
+						Metrics:     []MetricSpec{{Type: autoscalingv2.ResourceMetricSourceType}},
+					},
+				},
+			},
+			expectedErr:      "metric type unsupported",
+			expectedWarnings: []string{"The Amazon CloudWatch Agent mode is set to daemonset, which does not support autoscaling. The autoscaler settings are ignored"},
+		},
 		{
 			name: "invalid deployment mode incompabible with ingress settings",
 			otelcol: AmazonCloudWatchAgent{
@@ -719,68 +1026,1040 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			expectedErr: "the OpenTelemetry Spec LivenessProbe TerminationGracePeriodSeconds configuration is incorrect",
 		},
 		{
-			name: "invalid AdditionalContainers",
+			name: "terminationGracePeriodSeconds in sidecar mode",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeSidecar,
-					AdditionalContainers: []v1.Container{
-						{
-							Name: "test",
-						},
+					Mode:                          ModeSidecar,
+					TerminationGracePeriodSeconds: &zero64,
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'terminationGracePeriodSeconds'",
+		},
+		{
+			name: "preStop sleep longer than the termination grace period",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Lifecycle: &v1.Lifecycle{
+						PreStop: &v1.LifecycleHandler{Sleep: &v1.SleepAction{Seconds: 30}},
 					},
+					MaxReplicas: &zero,
 				},
 			},
-			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'AdditionalContainers'",
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"the preStop sleep of 30s is not shorter than the termination grace period of 30s, the agent will be killed before it can shut down",
+				"MaxReplicas is deprecated",
+			},
 		},
 		{
-			name: "missing ingress hostname for subdomain ruleType",
+			name: "invalid ReadinessProbe PeriodSeconds",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Ingress: Ingress{
-						RuleType: IngressRuleTypeSubdomain,
+					ReadinessProbe: &Probe{
+						PeriodSeconds: &zero,
 					},
 				},
 			},
-			expectedErr: "a valid Ingress hostname has to be defined for subdomain ruleType",
+			expectedErr: "the OpenTelemetry Spec ReadinessProbe PeriodSeconds configuration is incorrect",
 		},
 		{
-			name: "invalid updateStrategy for Deployment mode",
+			name: "ReadinessProbe with TerminationGracePeriodSeconds",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeDeployment,
-					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
-						Type: "RollingUpdate",
-						RollingUpdate: &appsv1.RollingUpdateDaemonSet{
-							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
-							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
-						},
+					ReadinessProbe: &Probe{
+						TerminationGracePeriodSeconds: &one64,
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'updateStrategy'",
+			expectedErr: "TerminationGracePeriodSeconds must not be set for readiness probes",
 		},
 		{
-			name: "invalid updateStrategy for Statefulset mode",
+			name: "invalid StartupProbe SuccessThreshold",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeStatefulSet,
-					DeploymentUpdateStrategy: appsv1.DeploymentStrategy{
-						Type: "RollingUpdate",
-						RollingUpdate: &appsv1.RollingUpdateDeployment{
-							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
-							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+					StartupProbe: &Probe{
+						SuccessThreshold: &three,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec StartupProbe SuccessThreshold configuration is incorrect. SuccessThreshold must be 1",
+		},
+		{
+			name: "invalid AdditionalContainers",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					AdditionalContainers: []v1.Container{
+						{
+							Name: "test",
+						},
+						{
+							Name: "test",
 						},
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Collector mode is set to statefulset, which does not support the attribute 'deploymentUpdateStrategy'",
+			expectedErr: "the OpenTelemetry Spec AdditionalContainers configuration is incorrect, container name 'test' is already in use",
 		},
-	}
-
-	for _, test := range tests {
-		test := test
-		t.Run(test.name, func(t *testing.T) {
-			cvw := &CollectorWebhook{
+		{
+			name: "InitContainers sharing a name with AdditionalContainers",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					AdditionalContainers: []v1.Container{{Name: "setup", Image: "busybox"}},
+					InitContainers:       []v1.Container{{Name: "setup", Image: "busybox"}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec InitContainers configuration is incorrect, container name 'setup' is already in use",
+		},
+		{
+			name: "InitContainers without an image",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					InitContainers: []v1.Container{{Name: "sysctl"}},
+				},
+			},
+			expectedErr: "init container 'sysctl' must set an image",
+		},
+		{
+			name: "AdditionalContainers overriding the agent container",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					AdditionalContainers: []v1.Container{
+						{
+							Name: "otc-container",
+						},
+					},
+				},
+			},
+			expectedErr: "container name 'otc-container' is already in use",
+		},
+		{
+			name: "missing ingress hostname for subdomain ruleType",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ingress: Ingress{
+						RuleType: IngressRuleTypeSubdomain,
+					},
+				},
+			},
+			expectedErr: "a valid Ingress hostname has to be defined for subdomain ruleType",
+		},
+		{
+			name: "invalid updateStrategy for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type: "RollingUpdate",
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'updateStrategy'",
+		},
+		{
+			name: "invalid updateStrategy for Statefulset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					DeploymentUpdateStrategy: appsv1.DeploymentStrategy{
+						Type: "RollingUpdate",
+						RollingUpdate: &appsv1.RollingUpdateDeployment{
+							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to statefulset, which does not support the attribute 'deploymentUpdateStrategy'",
+		},
+		{
+			name: "rollingUpdate with Recreate deploymentUpdateStrategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					DeploymentUpdateStrategy: appsv1.DeploymentStrategy{
+						Type:          appsv1.RecreateDeploymentStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateDeployment{},
+					},
+				},
+			},
+			expectedErr: "rollingUpdate must not be set when the type is Recreate",
+		},
+		{
+			name: "progressDeadlineSeconds for daemonset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                    ModeDaemonSet,
+					ProgressDeadlineSeconds: &one,
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to daemonset, which does not support the attribute 'progressDeadlineSeconds'",
+		},
+		{
+			name: "progressDeadlineSeconds not greater than minReadySeconds",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                    ModeDeployment,
+					MinReadySeconds:         5,
+					ProgressDeadlineSeconds: &one,
+				},
+			},
+			expectedErr: "progressDeadlineSeconds must be greater than minReadySeconds",
+		},
+		{
+			name: "configSecret in sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					ConfigSecret: &v1.SecretProjection{
+						LocalObjectReference: v1.LocalObjectReference{Name: "agent-credentials"},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'configSecret'",
+		},
+		{
+			name: "configSecret without name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigSecret: &v1.SecretProjection{},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec ConfigSecret configuration is incorrect, name is required",
+		},
+		{
+			name: "configSecret replacing the rendered config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigSecret: &v1.SecretProjection{
+						LocalObjectReference: v1.LocalObjectReference{Name: "agent-credentials"},
+						Items:                []v1.KeyToPath{{Key: "config", Path: "cwagentconfig.json"}},
+					},
+				},
+			},
+			expectedErr: "path 'cwagentconfig.json' would replace the configuration rendered by the operator",
+		},
+		{
+			name: "configSources with inline and configMap",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigSources: []ConfigSource{{
+						Inline: "{}",
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "team-config"},
+							Key:                  "agent.json",
+						},
+					}},
+				},
+			},
+			expectedErr: "source 0 must set exactly one of inline or configMap",
+		},
+		{
+			name: "configSources configMap without key",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigSources: []ConfigSource{{
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "team-config"},
+						},
+					}},
+				},
+			},
+			expectedErr: "source 0 must set the configMap name and key",
+		},
+		{
+			name: "configSources inline is not a JSON object",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigSources: []ConfigSource{{Inline: "{}"}, {Inline: "[]"}},
+				},
+			},
+			expectedErr: "source 1: the configuration must be a JSON object, got array",
+		},
+		{
+			name: "config with invalid JSON",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: "{\n  \"agent\": {\n    \"region\": us-west-2\n  }\n}",
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Config configuration is incorrect, invalid JSON at line 3, column 15",
+		},
+		{
+			name: "config with unknown section",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"agent":{},"metric":{}}`,
+				},
+			},
+			expectedErr: "unknown section 'metric', expected one of agent, logs, metrics, traces",
+		},
+		{
+			name: "config with a setting of the wrong type",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"agent":{"metrics_collection_interval":"60"}}`,
+				},
+			},
+			expectedErr: "'agent.metrics_collection_interval' must be of type number, got string",
+		},
+		{
+			name: "otelConfig with an unknown section",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: "receiver:\n  otlp:\n",
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec OtelConfig configuration is incorrect, unknown section 'receiver'",
+		},
+		{
+			name: "otelConfig with an undefined exporter",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: `
+receivers:
+  otlp:
+exporters:
+  awsxray:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [awsxray/2]
+`,
+				},
+			},
+			expectedErr: "'service.pipelines.traces.exporters' references exporter 'awsxray/2', which is not defined",
+		},
+		{
+			name: "otelConfig with an unused receiver",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: `
+receivers:
+  otlp:
+  statsd:
+exporters:
+  awsemf:
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp]
+      exporters: [awsemf]
+`,
+					Mode:        ModeDeployment,
+					MaxReplicas: &zero,
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"receiver 'statsd' is defined but not used by the service",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "config with an unknown setting",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config:      `{"logs":{"log_collected":{}}}`,
+					Mode:        ModeDeployment,
+					MaxReplicas: &zero,
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"unknown setting 'logs.log_collected' in the agent configuration",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "nodeGroups in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					NodeGroups: []NodeGroup{{
+						Name:         "gpu",
+						NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p4d.24xlarge"},
+					}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nodeGroups'",
+		},
+		{
+			name: "nodeGroups with a reserved name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					NodeGroups: []NodeGroup{{
+						Name:         "target-allocator",
+						NodeSelector: map[string]string{"pool": "a"},
+					}},
+				},
+			},
+			expectedErr: "node group name 'target-allocator' is empty or reserved",
+		},
+		{
+			name: "nodeGroups with a duplicate name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					NodeGroups: []NodeGroup{
+						{Name: "gpu", NodeSelector: map[string]string{"pool": "a"}},
+						{Name: "gpu", NodeSelector: map[string]string{"pool": "b"}},
+					},
+				},
+			},
+			expectedErr: "node group name 'gpu' is used more than once",
+		},
+		{
+			name: "nodeGroups without nodeSelector",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					NodeGroups: []NodeGroup{{Name: "gpu"}},
+				},
+			},
+			expectedErr: "node group 'gpu' must set a nodeSelector",
+		},
+		{
+			name: "windows in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:    ModeDeployment,
+					Windows: &WindowsSpec{},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'windows'",
+		},
+		{
+			name: "windows with a linux nodeSelector",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					Windows: &WindowsSpec{
+						NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+					},
+				},
+			},
+			expectedErr: "nodeSelector 'kubernetes.io/os' must be 'windows'",
+		},
+		{
+			name: "nodeGroups named windows next to the windows agents",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					NodeGroups: []NodeGroup{{Name: "windows", NodeSelector: map[string]string{"pool": "a"}}},
+					Windows:    &WindowsSpec{},
+				},
+			},
+			expectedErr: "node group name 'windows' is empty or reserved",
+		},
+		{
+			name: "hybrid in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDeployment,
+					Hybrid: &HybridSpec{},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'hybrid'",
+		},
+		{
+			name: "hybrid with a relative credentials file",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Hybrid: &HybridSpec{CredentialsFile: "root/.aws/credentials"},
+				},
+			},
+			expectedErr: "credentialsFile 'root/.aws/credentials' must be an absolute path",
+		},
+		{
+			name: "hybrid host mount named after the credentials volume",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Hybrid: &HybridSpec{HostMounts: []HostMount{{Name: "aws-credentials", Path: "/root/.aws"}}},
+				},
+			},
+			expectedErr: "host mount 'aws-credentials' must have absolute paths and a name other than 'aws-credentials'",
+		},
+		{
+			name: "nodeGroups named hybrid next to the hybrid agents",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					NodeGroups: []NodeGroup{{Name: "hybrid", NodeSelector: map[string]string{"pool": "a"}}},
+					Hybrid:     &HybridSpec{},
+				},
+			},
+			expectedErr: "node group name 'hybrid' is empty or reserved",
+		},
+		{
+			name: "containerRuntime in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:             ModeDeployment,
+					ContainerRuntime: &ContainerRuntimeSpec{Type: ContainerRuntimeContainerd},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'containerRuntime'",
+		},
+		{
+			name: "containerRuntime with a relative socket path",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					NodeGroups: []NodeGroup{{
+						Name:             "k3s",
+						NodeSelector:     map[string]string{"pool": "k3s"},
+						ContainerRuntime: &ContainerRuntimeSpec{SocketPath: "run/k3s/containerd/containerd.sock"},
+					}},
+				},
+			},
+			expectedErr: "node group 'k3s': socketPath 'run/k3s/containerd/containerd.sock' must be an absolute path",
+		},
+		{
+			name: "hostMounts in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDeployment,
+					HostMounts: []HostMount{{Name: "proc", Path: "/proc"}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'hostMounts'",
+		},
+		{
+			name: "hostMounts with a volume name in use",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					Volumes:    []v1.Volume{{Name: "logs"}},
+					HostMounts: []HostMount{{Name: "logs", Path: "/var/log"}},
+				},
+			},
+			expectedErr: "volume name 'logs' is already used by the agent pods",
+		},
+		{
+			name: "hostMounts with a relative path",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					HostMounts: []HostMount{{Name: "logs", Path: "var/log"}},
+				},
+			},
+			expectedErr: "path 'var/log' must be an absolute path",
+		},
+		{
+			name: "writable host path volume",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Volumes: []v1.Volume{{
+						Name:         "rootfs",
+						VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/"}},
+					}},
+					VolumeMounts: []v1.VolumeMount{{Name: "rootfs", MountPath: "/rootfs"}},
+					Mode:         ModeDeployment,
+					MaxReplicas:  &zero,
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"volume 'rootfs' mounts the host path '/' writable, use hostMounts to mount it read-only",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "hardening with the container runtime socket",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:             ModeDaemonSet,
+					Hardening:        &HardeningSpec{},
+					ContainerRuntime: &ContainerRuntimeSpec{},
+				},
+			},
+			expectedErr: "the container runtime socket is only accessible to root, remove containerRuntime",
+		},
+		{
+			name: "hardening with a privileged container",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Hardening:       &HardeningSpec{},
+					SecurityContext: &v1.SecurityContext{Privileged: &privileged},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Hardening configuration is incorrect, the agent cannot be privileged",
+		},
+		{
+			name: "version with an image",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Image:   "cloudwatch-agent:1.300049.1",
+					Version: "1.300049.1",
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec version configuration is incorrect, version and image are mutually exclusive",
+		},
+		{
+			name: "FIPS without a FIPS image",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					FIPS: true,
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec FIPS configuration is incorrect, the operator has no FIPS agent image, set image or configure the operator with --agent-fips-image",
+		},
+		{
+			name: "FIPS with endpoint overrides",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:        ModeDeployment,
+					MaxReplicas: &zero,
+					Image:       "cloudwatch-agent-fips:1.300049.1",
+					FIPS:        true,
+					AWS: &AWSSpec{
+						EndpointOverrides: &EndpointOverrides{Logs: "https://logs-fips.us-gov-west-1.amazonaws.com"},
+					},
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"the endpoint overrides replace the FIPS endpoints of their sections",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "proxy without a scheme",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Proxy: &Proxy{HTTPSProxy: "proxy.example.com:3128"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec proxy configuration is incorrect, httpsProxy \"proxy.example.com:3128\" is not an http or https URL",
+		},
+		{
+			name: "proxy noProxy hosts without a proxy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:        ModeDeployment,
+					MaxReplicas: &zero,
+					Proxy:       &Proxy{NoProxy: []string{"10.100.0.0/16"}},
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"the proxy noProxy hosts are ignored, as neither httpsProxy nor httpProxy is set",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "kubernetes events in sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:             ModeSidecar,
+					KubernetesEvents: &KubernetesEventsSpec{Enabled: true, ClusterName: "my-cluster"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'kubernetesEvents'",
+		},
+		{
+			name: "kubernetes events without a log group",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:             ModeDaemonSet,
+					Config:           `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+					KubernetesEvents: &KubernetesEventsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec kubernetesEvents configuration is incorrect, set clusterName or logGroupName, or the cluster_name of the kubernetes section of the agent configuration",
+		},
+		{
+			name: "control plane metrics in daemonset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                ModeDaemonSet,
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true, ClusterName: "my-cluster"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to daemonset, which does not support the attribute 'controlPlaneMetrics'",
+		},
+		{
+			name: "control plane metrics without a cluster name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                ModeDeployment,
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec controlPlaneMetrics configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration",
+		},
+		{
+			name: "control plane metrics on more than one replica",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                ModeDeployment,
+					Replicas:            &three,
+					MaxReplicas:         &zero,
+					Config:              `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`,
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"every replica scrapes the control plane, so its metrics are duplicated when controlPlaneMetrics is set on more than one replica",
+				"MaxReplicas is deprecated",
+			},
+		},
+		{
+			name: "logs in sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					Logs: &LogsSpec{Enabled: true, ClusterName: "my-cluster"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'logs'",
+		},
+		{
+			name: "logs without a region",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`,
+					Logs:   &LogsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec logs configuration is incorrect, Fluent Bit needs the region of aws, or the region of the agent section of the agent configuration",
+		},
+		{
+			name: "logs with a multiline pattern quoting its start pattern",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"agent":{"region":"us-west-2"}}`,
+					Logs: &LogsSpec{
+						Enabled:           true,
+						ClusterName:       "my-cluster",
+						MultilinePatterns: []MultilinePattern{{Namespace: "payments", StartPattern: `^"time"`}},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec logs configuration is incorrect, the patterns of a multiline pattern cannot contain double quotes",
+		},
+		{
+			name: "logs with a multiline pattern of an invalid container",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"agent":{"region":"us-west-2"}}`,
+					Logs: &LogsSpec{
+						Enabled:           true,
+						ClusterName:       "my-cluster",
+						MultilinePatterns: []MultilinePattern{{Container: "Payments_API", StartPattern: `^\d{4}-`}},
+					},
+				},
+			},
+			expectedErr: `the OpenTelemetry Spec logs configuration is incorrect, the container "Payments_API" of a multiline pattern is invalid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		{
+			name: "logs with a record placeholder in the host log group",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"agent":{"region":"us-west-2"}}`,
+					Logs: &LogsSpec{
+						Enabled:     true,
+						ClusterName: "my-cluster",
+						LogGroups:   &LogGroupsSpec{Application: "/eks/{cluster}/{namespace}", Host: "/eks/{cluster}/{pod}"},
+					},
+				},
+			},
+			expectedErr: `the OpenTelemetry Spec logs configuration is incorrect, the host log group name "/eks/{cluster}/{pod}" holds the placeholder {pod}, expected one of {cluster}`,
+		},
+		{
+			name: "logs with an unknown placeholder in the application log group",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"agent":{"region":"us-west-2"}}`,
+					Logs: &LogsSpec{
+						Enabled:     true,
+						ClusterName: "my-cluster",
+						LogGroups:   &LogGroupsSpec{Application: "/eks/{cluster}/{node}"},
+					},
+				},
+			},
+			expectedErr: `the OpenTelemetry Spec logs configuration is incorrect, the application log group name "/eks/{cluster}/{node}" holds the placeholder {node}, expected one of {cluster}, {namespace}, {pod}, {container}, {workload}`,
+		},
+		{
+			name: "gpu metrics in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDeployment,
+					GPUMetrics: &GPUMetricsSpec{Enabled: true, ClusterName: "my-cluster"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'gpuMetrics'",
+		},
+		{
+			name: "gpu metrics without a cluster name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					GPUMetrics: &GPUMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec gpuMetrics configuration is incorrect, set clusterName, or the cluster_name of the kubernetes section of the agent configuration",
+		},
+		{
+			name: "neuron metrics in statefulset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeStatefulSet,
+					NeuronMetrics: &NeuronMetricsSpec{Enabled: true, ClusterName: "my-cluster"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to statefulset, which does not support the attribute 'neuronMetrics'",
+		},
+		{
+			name: "neuron metrics with an invalid monitor config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeDaemonSet,
+					NeuronMetrics: &NeuronMetricsSpec{Enabled: true, ClusterName: "my-cluster", MonitorConfig: `{"period": "5s"`},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec neuronMetrics configuration is incorrect, monitorConfig is not valid JSON",
+		},
+		{
+			name: "efa metrics outside daemonset mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDeployment,
+					EFAMetrics: &EFAMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'efaMetrics'",
+		},
+		{
+			name: "efa metrics with a node group named efa",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					Config:     `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`,
+					EFAMetrics: &EFAMetricsSpec{Enabled: true},
+					NodeGroups: []NodeGroup{{Name: "efa", NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p5.48xlarge"}}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec NodeGroups configuration is incorrect, node group name 'efa' is empty or reserved",
+			expectedWarnings: []string{
+				"efaMetrics: the EFA metrics are only collected when enhanced_container_insights is set in the kubernetes section of the agent configuration",
+			},
+		},
+		{
+			name: "efa metrics with a host mount named like the EFA volumes",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDaemonSet,
+					Config:     `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+					EFAMetrics: &EFAMetricsSpec{Enabled: true},
+					HostMounts: []HostMount{{Name: "pod-resources", Path: "/var/lib/kubelet/pod-resources"}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec HostMounts configuration is incorrect, volume name 'pod-resources' is already used by the agent pods",
+		},
+		{
+			name: "prometheus config reload without prometheus config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					PrometheusConfigReload:    &PrometheusConfigReloadSpec{Enabled: true},
+					ServiceAccount:            "agent",
+					ServiceAccountAnnotations: map[string]string{"team": "observability"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec serviceAccountAnnotations configuration is incorrect",
+			expectedWarnings: []string{
+				"prometheusConfigReload: there is no Prometheus configuration to reload, as prometheus is not set",
+			},
+		},
+		{
+			name: "prometheus config reload on windows",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Prometheus: PrometheusConfig{
+						Config: &AnyConfig{Object: map[string]interface{}{"scrape_configs": []interface{}{}}},
+					},
+					PrometheusConfigReload:    &PrometheusConfigReloadSpec{Enabled: true},
+					NodeSelector:              map[string]string{"kubernetes.io/os": "windows"},
+					ServiceAccount:            "agent",
+					ServiceAccountAnnotations: map[string]string{"team": "observability"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec serviceAccountAnnotations configuration is incorrect",
+			expectedWarnings: []string{
+				"prometheusConfigReload: the Windows agents do not reload the Prometheus configuration and still roll, only the target allocator reloads it",
+			},
+		},
+		{
+			name: "emf namespace reserved for the AWS services",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EMF: &EMFSpec{Namespace: "AWS/ContainerInsights"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec EMF configuration is incorrect, the namespace \"AWS/ContainerInsights\" cannot start with AWS/",
+		},
+		{
+			name: "emf namespace with invalid characters",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EMF: &EMFSpec{Namespace: "My*App"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec EMF configuration is incorrect, the namespace \"My*App\" must only hold",
+		},
+		{
+			name: "emf log group with an unknown placeholder",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EMF: &EMFSpec{LogGroupName: "/aws/containerinsights/{Cluster}/performance"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec EMF configuration is incorrect, the logGroupName \"/aws/containerinsights/{Cluster}/performance\" holds the placeholder {Cluster}",
+		},
+		{
+			name: "emf log group with invalid characters",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EMF: &EMFSpec{LogGroupName: "/aws/containerinsights/{ClusterName}/perf ormance"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec EMF configuration is incorrect, the logGroupName \"/aws/containerinsights/{ClusterName}/perf ormance\" must be at most 512 characters",
+		},
+		{
+			name: "emf log stream with a colon",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					EMF: &EMFSpec{LogStreamName: "{NodeName}:metrics"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec EMF configuration is incorrect, the logStreamName \"{NodeName}:metrics\" must be at most 512 characters and cannot contain : or *",
+		},
+		{
+			name: "emf without awsemf exporter",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:       ModeDeployment,
+					EMF:        &EMFSpec{Namespace: "MyApp", LogGroupName: "/aws/{ClusterName}/app", LogStreamName: "{NodeName}"},
+					NodeGroups: []NodeGroup{{Name: "gpu"}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nodeGroups'",
+			expectedWarnings: []string{
+				"emf: the EMF settings are ignored, as otelConfig has no awsemf exporter",
+			},
+		},
+		{
+			name: "statsd host port in deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDeployment,
+					StatsD: &MetricsListenerSpec{Enabled: true, HostPort: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'statsd.hostPort'",
+		},
+		{
+			name: "statsd and collectd on the same port",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:     ModeDaemonSet,
+					StatsD:   &MetricsListenerSpec{Enabled: true, HostPort: true},
+					CollectD: &MetricsListenerSpec{Enabled: true, Port: 8125},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec collectd configuration is incorrect, the statsd listener already uses the UDP port 8125",
+		},
+		{
+			name: "self telemetry with a metrics address in the otel config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeDeployment,
+					OtelConfig:    "service:\n  telemetry:\n    metrics:\n      address: 0.0.0.0:9090\n",
+					SelfTelemetry: &SelfTelemetrySpec{Enabled: true, CloudWatch: true},
+					NodeGroups:    []NodeGroup{{Name: "gpu"}},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nodeGroups'",
+			expectedWarnings: []string{
+				"selfTelemetry: the service telemetry metrics address of otelConfig takes precedence over the selfTelemetry port",
+			},
+		},
+		{
+			name: "gateway routes without parent",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Ingress: Ingress{
+						Type:     IngressTypeGateway,
+						Hostname: "telemetry.example.com",
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Ingress configuration is incorrect, the gateway routes need at least one parentRef",
+		},
+		{
+			name: "gateway routes without hostname",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Ingress: Ingress{
+						Type:    IngressTypeGateway,
+						Gateway: GatewayRoutes{ParentRefs: []GatewayParentReference{{Name: "public"}}},
+					},
+				},
+			},
+			expectedErr: "a valid Ingress hostname has to be defined for gateway type",
+		},
+		{
+			name: "service account annotations with an existing service account",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ServiceAccount:            "cloudwatch-agent",
+					ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/cloudwatch-agent"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec serviceAccountAnnotations configuration is incorrect, the operator does not create a ServiceAccount to annotate when serviceAccount is set",
+		},
+		{
+			name: "IRSA role with a pod identity association",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                      ModeDeployment,
+					MaxReplicas:               &zero,
+					ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/cloudwatch-agent"},
+					PodIdentityAssociation: &PodIdentityAssociationSpec{
+						ClusterName: "cluster",
+						RoleARN:     "arn:aws:iam::123456789012:role/cloudwatch-agent",
+					},
+				},
+			},
+			expectedErr: "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{
+				"the IAM role of the eks.amazonaws.com/role-arn annotation takes precedence over the podIdentityAssociation",
+				"MaxReplicas is deprecated",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cvw := &CollectorWebhook{
 				logger: logr.Discard(),
 				scheme: testScheme,
 				cfg: config.New(
@@ -803,3 +2082,69 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestHybridRegionWarning(t *testing.T) {
+	warning := "the region of the agent can't be detected on the hybrid nodes, set aws.region"
+	for _, tt := range []struct {
+		name     string
+		cfg      config.Config
+		spec     AmazonCloudWatchAgentSpec
+		expected bool
+	}{
+		{name: "no region", cfg: config.New(), spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet, Hybrid: &HybridSpec{}}, expected: true},
+		{name: "region of the operator", cfg: config.New(config.WithAWSResource("us-west-2", "")), spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet, Hybrid: &HybridSpec{}}},
+		{name: "region of the hybrid configuration", cfg: config.New(), spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet, Hybrid: &HybridSpec{Config: `{"agent":{"region":"us-west-2"}}`}}},
+		{name: "no hybrid", cfg: config.New(), spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cvw := &CollectorWebhook{logger: logr.Discard(), scheme: testScheme, cfg: tt.cfg}
+			warnings, err := cvw.validate(&AmazonCloudWatchAgent{Spec: tt.spec})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, slices.Contains(warnings, warning))
+		})
+	}
+}
+
+func TestOffAWSWarnings(t *testing.T) {
+	kubernetesConfig := `{"logs":{"metrics_collected":{"kubernetes":{}}}}`
+	for _, tt := range []struct {
+		name     string
+		provider string
+		spec     AmazonCloudWatchAgentSpec
+		expected []string
+	}{
+		{name: "aws", provider: config.CloudProviderAWS, spec: AmazonCloudWatchAgentSpec{Config: kubernetesConfig}},
+		{
+			name:     "no region nor cluster",
+			provider: config.CloudProviderGCP,
+			spec:     AmazonCloudWatchAgentSpec{Config: kubernetesConfig},
+			expected: []string{
+				"the region of the agent can't be detected on a gcp cluster, set aws.region",
+				"the cluster of the kubernetes section can't be detected on a gcp cluster, set its cluster_name",
+			},
+		},
+		{
+			name:     "region of the configuration",
+			provider: config.CloudProviderOnPremises,
+			spec:     AmazonCloudWatchAgentSpec{Config: `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"lab"}}}}`},
+		},
+		{
+			name:     "region of the spec",
+			provider: config.CloudProviderAzure,
+			spec:     AmazonCloudWatchAgentSpec{AWS: &AWSSpec{Region: "us-west-2"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cvw := &CollectorWebhook{logger: logr.Discard(), scheme: testScheme, cfg: config.New(config.WithCloudProvider(tt.provider))}
+			warnings, err := cvw.validate(&AmazonCloudWatchAgent{Spec: tt.spec})
+			assert.NoError(t, err)
+			var detected []string
+			for _, warning := range warnings {
+				if strings.Contains(warning, "can't be detected") {
+					detected = append(detected, warning)
+				}
+			}
+			assert.Equal(t, tt.expected, detected)
+		})
+	}
+}
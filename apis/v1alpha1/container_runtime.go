@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+type (
+	// ContainerRuntime represents the container runtime of the nodes the agent runs on, which defines where its socket lives on the host.
+	// +kubebuilder:validation:Enum=containerd;docker;k3s;bottlerocket
+	ContainerRuntime string
+)
+
+const (
+	// ContainerRuntimeContainerd is containerd with its default socket, /run/containerd/containerd.sock.
+	ContainerRuntimeContainerd ContainerRuntime = "containerd"
+
+	// ContainerRuntimeDocker is the Docker engine, listening on /var/run/docker.sock.
+	ContainerRuntimeDocker ContainerRuntime = "docker"
+
+	// ContainerRuntimeK3s is the containerd embedded in k3s, listening on /run/k3s/containerd/containerd.sock.
+	ContainerRuntimeK3s ContainerRuntime = "k3s"
+
+	// ContainerRuntimeBottlerocket is the containerd of Bottlerocket, whose CRI socket is /run/dockershim.sock.
+	ContainerRuntimeBottlerocket ContainerRuntime = "bottlerocket"
+)
@@ -0,0 +1,13 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// The v1alpha1 resources are the conversion hub, which the resources of the other versions are converted from and to,
+// while they are stored as v1beta1.
+
+// Hub marks this type as a conversion hub.
+func (*AmazonCloudWatchAgent) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Instrumentation) Hub() {}
@@ -4,8 +4,8 @@
 package v1alpha1
 
 type (
-	// IngressType represents how a collector should be exposed (ingress vs route).
-	// +kubebuilder:validation:Enum=ingress;route
+	// IngressType represents how a collector should be exposed (ingress, route or gateway).
+	// +kubebuilder:validation:Enum=ingress;route;gateway
 	IngressType string
 )
 
@@ -14,6 +14,8 @@ const (
 	IngressTypeNginx IngressType = "ingress"
 	// IngressTypeOpenshiftRoute specifies that an route entry should be created.
 	IngressTypeRoute IngressType = "route"
+	// IngressTypeGateway specifies that Gateway API routes should be created.
+	IngressTypeGateway IngressType = "gateway"
 )
 
 type (
@@ -29,6 +29,19 @@ type InstrumentationSpec struct {
 	// +optional
 	Sampler `json:"sampler,omitempty"`
 
+	// RuntimeMetrics defines whether the instrumented workloads emit runtime metrics, e.g. JVM or CLR metrics.
+	// It can be overridden per workload with the cloudwatch.aws.amazon.com/runtime-metrics annotation.
+	// +optional
+	RuntimeMetrics *RuntimeMetrics `json:"runtimeMetrics,omitempty"`
+
+	// Logs defines the logs pipeline of the instrumented workloads.
+	// +optional
+	Logs *Logs `json:"logs,omitempty"`
+
+	// Proxy defines the proxy the instrumented workloads use for egress traffic.
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+
 	// Env defines common env vars. There are four layers for env vars' definitions and
 	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
 	// If the former var had been defined, then the other vars would be ignored.
@@ -67,6 +80,42 @@ type InstrumentationSpec struct {
 	Nginx Nginx `json:"nginx,omitempty"`
 }
 
+// Logs defines the logs configuration of the instrumented workloads.
+type Logs struct {
+	// Enabled defines whether the SDK exports logs over OTLP, e.g. to get trace correlated logs in CloudWatch.
+	// For Java, the log appender instrumentations are enabled as well.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Endpoint is the OTLP/HTTP endpoint logs are exported to. Defaults to the CloudWatch agent.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Proxy defines the proxy configuration injected in the instrumented and the agent containers.
+type Proxy struct {
+	// HTTPSProxy is set in the HTTPS_PROXY env var.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// HTTPProxy is set in the HTTP_PROXY env var.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// NoProxy defines the hosts that are not accessed through the proxy. The values are set in the NO_PROXY env var,
+	// together with the hosts the container always reaches directly, such as the CloudWatch agent service.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// RuntimeMetrics defines the runtime metrics configuration of the instrumented workloads.
+type RuntimeMetrics struct {
+	// Enabled defines whether runtime metrics are emitted. Traces are not affected.
+	// Defaults to the operator's auto instrumentation configuration.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
 // Resource defines the configuration for the resource attributes, as defined by the OpenTelemetry specification.
 // See also: https://github.com/open-telemetry/opentelemetry-specification/blob/v1.8.0/specification/overview.md#resources
 type Resource struct {
@@ -78,6 +127,19 @@ type Resource struct {
 	// AddK8sUIDAttributes defines whether K8s UID attributes should be collected (e.g. k8s.deployment.uid).
 	// +optional
 	AddK8sUIDAttributes bool `json:"addK8sUIDAttributes,omitempty"`
+
+	// ApplicationARN is the ARN of the myApplications (AppRegistry) application the workloads belong to. It is
+	// added to the resource as the aws.application attribute. If not set, the
+	// cloudwatch.aws.amazon.com/aws-application annotation of the namespace is used.
+	// +kubebuilder:validation:Pattern=`^arn:`
+	// +optional
+	ApplicationARN string `json:"applicationARN,omitempty"`
+
+	// OwnerStopKinds lists the owner kinds at which the traversal of the owner references of a pod stops, e.g.
+	// StrimziPodSet. Owners that are not built-in workloads are followed up to the root by default, and the last
+	// one visited is added to the resource as the k8s.owner.kind and k8s.owner.name attributes.
+	// +optional
+	OwnerStopKinds []string `json:"ownerStopKinds,omitempty"`
 }
 
 // Exporter defines OTLP exporter configuration.
@@ -85,6 +147,13 @@ type Exporter struct {
 	// Endpoint is address of the collector with OTLP endpoint.
 	// +optional
 	Endpoint string `json:"endpoint,omitempty"`
+
+	// DualExport defines whether the SDK is injected into containers that export to a third-party OTLP endpoint.
+	// Application Signals data is sent to the CloudWatch agent while the container's OTLP endpoints are kept for
+	// traces. By default, containers with a third-party endpoint are not instrumented.
+	// It can be overridden per workload with the cloudwatch.aws.amazon.com/dual-export annotation.
+	// +optional
+	DualExport bool `json:"dualExport,omitempty"`
 }
 
 // Sampler defines sampling configuration.
@@ -122,6 +191,73 @@ type Java struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// JmxInsights enables the JMX metric insight target systems of the Java agent.
+	// +optional
+	JmxInsights *JmxInsights `json:"jmxInsights,omitempty"`
+
+	// Kafka defines the Kafka client instrumentation configuration.
+	// +optional
+	Kafka *Kafka `json:"kafka,omitempty"`
+
+	// Profiler defines a continuous profiler injected alongside the auto-instrumentation.
+	// +optional
+	Profiler *Profiler `json:"profiler,omitempty"`
+}
+
+// JmxInsights defines the JMX metrics collected by the Java agent.
+type JmxInsights struct {
+	// TargetSystems defines the target systems to collect JMX metrics from.
+	// Values in this list will be set in the OTEL_JMX_TARGET_SYSTEM env var, unless the env var is already set.
+	// +optional
+	TargetSystems []JmxTargetSystem `json:"targetSystems,omitempty"`
+}
+
+// JmxTargetSystem represents a JMX metric insight target system.
+// +kubebuilder:validation:Enum=jvm;tomcat;kafka;kafka-consumer;kafka-producer;hikari
+type JmxTargetSystem string
+
+// Kafka defines the Kafka producer and consumer instrumentation configuration.
+type Kafka struct {
+	// Enabled defines whether the Kafka producer and consumer instrumentation is enabled.
+	// The instrumentation is enabled by default.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ConsumerGroup is added to the resource as the messaging.kafka.consumer.group attribute.
+	// +optional
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+
+	// Cluster is added to the resource as the messaging.kafka.cluster attribute.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Profiler defines a continuous profiler agent, e.g. a Pyroscope compatible agent. For Java, the image must contain
+// the agent at /profiler.jar, which is added as an additional -javaagent. For Python, the image must contain the
+// pyroscope package in /profiler, which is added to the PYTHONPATH, and a /profiler/bootstrap/sitecustomize.py module
+// starting it, which is put first in the PYTHONPATH and must run the next sitecustomize module of the sys.path, the
+// one of the auto-instrumentation or of the application. Profilers are not injected in Windows pods.
+type Profiler struct {
+	// Image is a container image with the profiler agent.
+	Image string `json:"image"`
+
+	// Endpoint is the address of the profiling server. The value will be set in the PYROSCOPE_SERVER_ADDRESS env var.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Env defines profiler specific env vars. Env vars defined in the original container take precedence.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// VolumeSizeLimit defines size limit for volume used for the profiler agent.
+	// The default size is 200Mi.
+	// +optional
+	VolumeSizeLimit *resource.Quantity `json:"volumeLimitSize,omitempty"`
+
+	// Resources describes the compute resource requirements of the profiler init container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // NodeJS defines NodeJS SDK and instrumentation configuration.
@@ -164,6 +300,14 @@ type Python struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// Kafka defines the Kafka client instrumentation configuration.
+	// +optional
+	Kafka *Kafka `json:"kafka,omitempty"`
+
+	// Profiler defines a continuous profiler injected alongside the auto-instrumentation.
+	// +optional
+	Profiler *Profiler `json:"profiler,omitempty"`
 }
 
 // DotNet defines DotNet SDK and instrumentation configuration.
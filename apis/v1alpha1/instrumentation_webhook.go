@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -62,7 +63,15 @@ func (w InstrumentationWebhook) ValidateCreate(ctx context.Context, obj runtime.
 	if !ok {
 		return nil, fmt.Errorf("expected an Instrumentation, received %T", obj)
 	}
-	return w.validate(inst)
+	warnings, err := w.validate(inst)
+	if err == nil {
+		err = validateWatchedNamespace(w.cfg, "Instrumentation", inst.Namespace)
+	}
+	if err == nil {
+		err = w.validateEndpoints(inst)
+	}
+	telemetry.RecordValidation("Instrumentation", "create", err)
+	return warnings, err
 }
 
 func (w InstrumentationWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
@@ -70,7 +79,15 @@ func (w InstrumentationWebhook) ValidateUpdate(ctx context.Context, oldObj, newO
 	if !ok {
 		return nil, fmt.Errorf("expected an Instrumentation, received %T", newObj)
 	}
-	return w.validate(inst)
+	warnings, err := w.validate(inst)
+	if err == nil {
+		err = validateWatchedNamespace(w.cfg, "Instrumentation", inst.Namespace)
+	}
+	if err == nil {
+		err = w.validateEndpoints(inst)
+	}
+	telemetry.RecordValidation("Instrumentation", "update", err)
+	return warnings, err
 }
 
 func (w InstrumentationWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -78,7 +95,9 @@ func (w InstrumentationWebhook) ValidateDelete(ctx context.Context, obj runtime.
 	if !ok || inst == nil {
 		return nil, fmt.Errorf("expected an Instrumentation, received %T", obj)
 	}
-	return w.validate(inst)
+	warnings, err := w.validate(inst)
+	telemetry.RecordValidation("Instrumentation", "delete", err)
+	return warnings, err
 }
 
 func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
@@ -93,85 +112,85 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 		r.Spec.Java.Image = w.cfg.AutoInstrumentationJavaImage()
 	}
 	if r.Spec.Java.Resources.Limits == nil {
-		r.Spec.Java.Resources.Limits = corev1.ResourceList{
+		r.Spec.Java.Resources.Limits = w.limits(config.LanguageJava, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 			corev1.ResourceMemory: resource.MustParse("64Mi"),
-		}
+		})
 	}
 	if r.Spec.Java.Resources.Requests == nil {
-		r.Spec.Java.Resources.Requests = corev1.ResourceList{
+		r.Spec.Java.Resources.Requests = w.requests(config.LanguageJava, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("50m"),
 			corev1.ResourceMemory: resource.MustParse("64Mi"),
-		}
+		})
 	}
 	if r.Spec.NodeJS.Image == "" {
 		r.Spec.NodeJS.Image = w.cfg.AutoInstrumentationNodeJSImage()
 	}
 	if r.Spec.NodeJS.Resources.Limits == nil {
-		r.Spec.NodeJS.Resources.Limits = corev1.ResourceList{
+		r.Spec.NodeJS.Resources.Limits = w.limits(config.LanguageNodeJS, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
+		})
 	}
 	if r.Spec.NodeJS.Resources.Requests == nil {
-		r.Spec.NodeJS.Resources.Requests = corev1.ResourceList{
+		r.Spec.NodeJS.Resources.Requests = w.requests(config.LanguageNodeJS, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("50m"),
 			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
+		})
 	}
 	if r.Spec.Python.Image == "" {
 		r.Spec.Python.Image = w.cfg.AutoInstrumentationPythonImage()
 	}
 	if r.Spec.Python.Resources.Limits == nil {
-		r.Spec.Python.Resources.Limits = corev1.ResourceList{
+		r.Spec.Python.Resources.Limits = w.limits(config.LanguagePython, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
+		})
 	}
 	if r.Spec.Python.Resources.Requests == nil {
-		r.Spec.Python.Resources.Requests = corev1.ResourceList{
+		r.Spec.Python.Resources.Requests = w.requests(config.LanguagePython, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("50m"),
 			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
+		})
 	}
 	if r.Spec.DotNet.Image == "" {
 		r.Spec.DotNet.Image = w.cfg.AutoInstrumentationDotNetImage()
 	}
 	if r.Spec.DotNet.Resources.Limits == nil {
-		r.Spec.DotNet.Resources.Limits = corev1.ResourceList{
+		r.Spec.DotNet.Resources.Limits = w.limits(config.LanguageDotNet, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
+		})
 	}
 	if r.Spec.DotNet.Resources.Requests == nil {
-		r.Spec.DotNet.Resources.Requests = corev1.ResourceList{
+		r.Spec.DotNet.Resources.Requests = w.requests(config.LanguageDotNet, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("50m"),
 			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
+		})
 	}
 	if r.Spec.Go.Image == "" {
 		r.Spec.Go.Image = w.cfg.AutoInstrumentationGoImage()
 	}
 	if r.Spec.Go.Resources.Limits == nil {
-		r.Spec.Go.Resources.Limits = corev1.ResourceList{
+		r.Spec.Go.Resources.Limits = w.limits(config.LanguageGo, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
+		})
 	}
 	if r.Spec.Go.Resources.Requests == nil {
-		r.Spec.Go.Resources.Requests = corev1.ResourceList{
+		r.Spec.Go.Resources.Requests = w.requests(config.LanguageGo, corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("50m"),
 			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
+		})
 	}
 	if r.Spec.ApacheHttpd.Image == "" {
 		r.Spec.ApacheHttpd.Image = w.cfg.AutoInstrumentationApacheHttpdImage()
 	}
 	if r.Spec.ApacheHttpd.Resources.Limits == nil {
-		r.Spec.ApacheHttpd.Resources.Limits = initContainerDefaultLimitResources
+		r.Spec.ApacheHttpd.Resources.Limits = w.limits(config.LanguageApacheHttpd, initContainerDefaultLimitResources)
 	}
 	if r.Spec.ApacheHttpd.Resources.Requests == nil {
-		r.Spec.ApacheHttpd.Resources.Requests = initContainerDefaultRequestedResources
+		r.Spec.ApacheHttpd.Resources.Requests = w.requests(config.LanguageApacheHttpd, initContainerDefaultRequestedResources)
 	}
 	if r.Spec.ApacheHttpd.Version == "" {
 		r.Spec.ApacheHttpd.Version = "2.4"
@@ -183,10 +202,10 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 		r.Spec.Nginx.Image = w.cfg.AutoInstrumentationNginxImage()
 	}
 	if r.Spec.Nginx.Resources.Limits == nil {
-		r.Spec.Nginx.Resources.Limits = initContainerDefaultLimitResources
+		r.Spec.Nginx.Resources.Limits = w.limits(config.LanguageNginx, initContainerDefaultLimitResources)
 	}
 	if r.Spec.Nginx.Resources.Requests == nil {
-		r.Spec.Nginx.Resources.Requests = initContainerDefaultRequestedResources
+		r.Spec.Nginx.Resources.Requests = w.requests(config.LanguageNginx, initContainerDefaultRequestedResources)
 	}
 	if r.Spec.Nginx.ConfigFile == "" {
 		r.Spec.Nginx.ConfigFile = "/etc/nginx/nginx.conf"
@@ -205,6 +224,22 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	return nil
 }
 
+// limits returns the init container limits of the language of the operator configuration, or the fallback ones.
+func (w InstrumentationWebhook) limits(language string, fallback corev1.ResourceList) corev1.ResourceList {
+	if limits := w.cfg.AutoInstrumentationResources(language).Limits; limits != nil {
+		return limits
+	}
+	return fallback
+}
+
+// requests returns the init container requests of the language of the operator configuration, or the fallback ones.
+func (w InstrumentationWebhook) requests(language string, fallback corev1.ResourceList) corev1.ResourceList {
+	if requests := w.cfg.AutoInstrumentationResources(language).Requests; requests != nil {
+		return requests
+	}
+	return fallback
+}
+
 func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings, error) {
 	var warnings []string
 	switch r.Spec.Sampler.Type {
@@ -263,6 +298,22 @@ func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings
 	return warnings, nil
 }
 
+// validateEndpoints rejects the exporter endpoints which are not trusted by the operator configuration. The deletions
+// are always allowed, so that the instrumentations created before an endpoint stopped being trusted can be removed.
+func (w InstrumentationWebhook) validateEndpoints(r *Instrumentation) error {
+	if r.Spec.Exporter.Endpoint != "" && !w.cfg.TrustsEndpoint(r.Spec.Exporter.Endpoint) {
+		return fmt.Errorf("spec.exporter.endpoint is not a trusted endpoint: %s", r.Spec.Exporter.Endpoint)
+	}
+	for _, envs := range [][]corev1.EnvVar{r.Spec.Env, r.Spec.Java.Env, r.Spec.NodeJS.Env, r.Spec.Python.Env, r.Spec.DotNet.Env, r.Spec.Go.Env, r.Spec.ApacheHttpd.Env, r.Spec.Nginx.Env} {
+		for _, env := range envs {
+			if strings.HasPrefix(env.Name, "OTEL_EXPORTER_") && strings.HasSuffix(env.Name, "_ENDPOINT") && env.Value != "" && !w.cfg.TrustsEndpoint(env.Value) {
+				return fmt.Errorf("env %s is not a trusted endpoint: %s", env.Name, env.Value)
+			}
+		}
+	}
+	return nil
+}
+
 func (w InstrumentationWebhook) validateEnv(envs []corev1.EnvVar) error {
 	for _, env := range envs {
 		if !strings.HasPrefix(env.Name, envPrefix) && !strings.HasPrefix(env.Name, envSplunkPrefix) {
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -187,3 +189,58 @@ func TestInstrumentationJaegerRemote(t *testing.T) {
 		}
 	}
 }
+
+func TestInstrumentationWatchedNamespace(t *testing.T) {
+	w := InstrumentationWebhook{cfg: config.New(config.WithWatchNamespaces([]string{"watched"}))}
+	ctx := context.Background()
+
+	inst := Instrumentation{}
+	inst.Namespace = "watched"
+	_, err := w.ValidateCreate(ctx, &inst)
+	assert.NoError(t, err)
+
+	inst.Namespace = "unwatched"
+	_, err = w.ValidateCreate(ctx, &inst)
+	assert.EqualError(t, err, "the Instrumentation can't be managed in the namespace unwatched, the operator only watches the namespaces watched")
+	_, err = w.ValidateUpdate(ctx, nil, &inst)
+	assert.Error(t, err)
+	// the instrumentations created before the operator was namespace-scoped can still be deleted
+	_, err = w.ValidateDelete(ctx, &inst)
+	assert.NoError(t, err)
+}
+
+func TestInstrumentationRuntimeDefaults(t *testing.T) {
+	cfg := config.New(config.WithAutoInstrumentationJavaImage("java-img:1"))
+	cfg.SetDefaults(config.Defaults{
+		AutoInstrumentation: map[string]config.LanguageDefaults{
+			config.LanguageJava: {
+				Image: "java-img:2",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			},
+		},
+		TrustedEndpoints: []string{"cloudwatch-agent.amazon-cloudwatch"},
+	})
+	w := InstrumentationWebhook{cfg: cfg}
+	ctx := context.Background()
+
+	inst := &Instrumentation{}
+	assert.NoError(t, w.Default(ctx, inst))
+	assert.Equal(t, "java-img:2", inst.Spec.Java.Image)
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")}, inst.Spec.Java.Resources.Limits)
+	assert.Equal(t, resource.MustParse("50m"), inst.Spec.Java.Resources.Requests[corev1.ResourceCPU])
+
+	inst.Spec.Sampler.Type = AlwaysOn
+	inst.Spec.Exporter.Endpoint = "http://cloudwatch-agent.amazon-cloudwatch:4316"
+	_, err := w.ValidateCreate(ctx, inst)
+	assert.NoError(t, err)
+	inst.Spec.Java.Env = []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://collector.example.com"}}
+	_, err = w.ValidateUpdate(ctx, nil, inst)
+	assert.EqualError(t, err, "env OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is not a trusted endpoint: https://collector.example.com")
+	inst.Spec.Exporter.Endpoint = "http://collector.example.com:4318"
+	_, err = w.ValidateCreate(ctx, inst)
+	assert.EqualError(t, err, "spec.exporter.endpoint is not a trusted endpoint: http://collector.example.com:4318")
+	_, err = w.ValidateDelete(ctx, inst)
+	assert.NoError(t, err)
+}
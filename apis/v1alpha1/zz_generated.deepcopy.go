@@ -7,6 +7,7 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	v2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
@@ -15,6 +16,26 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSpec) DeepCopyInto(out *AWSSpec) {
+	*out = *in
+	if in.EndpointOverrides != nil {
+		in, out := &in.EndpointOverrides, &out.EndpointOverrides
+		*out = new(EndpointOverrides)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSpec.
+func (in *AWSSpec) DeepCopy() *AWSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgent) DeepCopyInto(out *AmazonCloudWatchAgent) {
 	*out = *in
@@ -85,6 +106,28 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*out)[key] = val
 		}
 	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = new(WindowsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hybrid != nil {
+		in, out := &in.Hybrid, &out.Hybrid
+		*out = new(HybridSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(ContainerRuntimeSpec)
+		**out = **in
+	}
 	if in.Args != nil {
 		in, out := &in.Args, &out.Args
 		*out = make(map[string]string, len(*in))
@@ -127,6 +170,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodAnnotations != nil {
 		in, out := &in.PodAnnotations, &out.PodAnnotations
 		*out = make(map[string]string, len(*in))
@@ -135,7 +183,96 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		}
 	}
 	in.TargetAllocator.DeepCopyInto(&out.TargetAllocator)
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodIdentityAssociation != nil {
+		in, out := &in.PodIdentityAssociation, &out.PodIdentityAssociation
+		*out = new(PodIdentityAssociationSpec)
+		**out = **in
+	}
 	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	if in.PrometheusConfigReload != nil {
+		in, out := &in.PrometheusConfigReload, &out.PrometheusConfigReload
+		*out = new(PrometheusConfigReloadSpec)
+		**out = **in
+	}
+	if in.ConfigSources != nil {
+		in, out := &in.ConfigSources, &out.ConfigSources
+		*out = make([]ConfigSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesEvents != nil {
+		in, out := &in.KubernetesEvents, &out.KubernetesEvents
+		*out = new(KubernetesEventsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneMetrics != nil {
+		in, out := &in.ControlPlaneMetrics, &out.ControlPlaneMetrics
+		*out = new(ControlPlaneMetricsSpec)
+		**out = **in
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(LogsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUMetrics != nil {
+		in, out := &in.GPUMetrics, &out.GPUMetrics
+		*out = new(GPUMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NeuronMetrics != nil {
+		in, out := &in.NeuronMetrics, &out.NeuronMetrics
+		*out = new(NeuronMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EFAMetrics != nil {
+		in, out := &in.EFAMetrics, &out.EFAMetrics
+		*out = new(EFAMetricsSpec)
+		**out = **in
+	}
+	if in.StatsD != nil {
+		in, out := &in.StatsD, &out.StatsD
+		*out = new(MetricsListenerSpec)
+		**out = **in
+	}
+	if in.CollectD != nil {
+		in, out := &in.CollectD, &out.CollectD
+		*out = new(MetricsListenerSpec)
+		**out = **in
+	}
+	if in.SelfTelemetry != nil {
+		in, out := &in.SelfTelemetry, &out.SelfTelemetry
+		*out = new(SelfTelemetrySpec)
+		**out = **in
+	}
+	if in.EMF != nil {
+		in, out := &in.EMF, &out.EMF
+		*out = new(EMFSpec)
+		**out = **in
+	}
+	if in.ConfigSecret != nil {
+		in, out := &in.ConfigSecret, &out.ConfigSecret
+		*out = new(corev1.SecretProjection)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]corev1.VolumeMount, len(*in))
@@ -150,6 +287,7 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Service.DeepCopyInto(&out.Service)
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]corev1.EnvVar, len(*in))
@@ -171,6 +309,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -185,7 +328,17 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HostMounts != nil {
+		in, out := &in.HostMounts, &out.HostMounts
+		*out = make([]HostMount, len(*in))
+		copy(*out, *in)
+	}
 	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(corev1.Affinity)
@@ -206,6 +359,16 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		*out = new(Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]corev1.Container, len(*in))
@@ -235,6 +398,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
 	in.DeploymentUpdateStrategy.DeepCopyInto(&out.DeploymentUpdateStrategy)
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentSpec.
@@ -256,6 +424,13 @@ func (in *AmazonCloudWatchAgentStatus) DeepCopyInto(out *AmazonCloudWatchAgentSt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentStatus.
@@ -459,6 +634,56 @@ func (in *ConfigMapsSpec) DeepCopy() *ConfigMapsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSource) DeepCopyInto(out *ConfigSource) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSource.
+func (in *ConfigSource) DeepCopy() *ConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRuntimeSpec) DeepCopyInto(out *ContainerRuntimeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntimeSpec.
+func (in *ContainerRuntimeSpec) DeepCopy() *ContainerRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneMetricsSpec) DeepCopyInto(out *ControlPlaneMetricsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneMetricsSpec.
+func (in *ControlPlaneMetricsSpec) DeepCopy() *ControlPlaneMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DcgmExporter) DeepCopyInto(out *DcgmExporter) {
 	*out = *in
@@ -643,196 +868,150 @@ func (in *DotNet) DeepCopy() *DotNet {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Exporter) DeepCopyInto(out *Exporter) {
+func (in *EFAMetricsSpec) DeepCopyInto(out *EFAMetricsSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exporter.
-func (in *Exporter) DeepCopy() *Exporter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EFAMetricsSpec.
+func (in *EFAMetricsSpec) DeepCopy() *EFAMetricsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Exporter)
+	out := new(EFAMetricsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Go) DeepCopyInto(out *Go) {
+func (in *EMFSpec) DeepCopyInto(out *EMFSpec) {
 	*out = *in
-	if in.VolumeSizeLimit != nil {
-		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Go.
-func (in *Go) DeepCopy() *Go {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EMFSpec.
+func (in *EMFSpec) DeepCopy() *EMFSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Go)
+	out := new(EMFSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ingress) DeepCopyInto(out *Ingress) {
+func (in *EndpointOverrides) DeepCopyInto(out *EndpointOverrides) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = make([]v1.IngressTLS, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.IngressClassName != nil {
-		in, out := &in.IngressClassName, &out.IngressClassName
-		*out = new(string)
-		**out = **in
-	}
-	out.Route = in.Route
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
-func (in *Ingress) DeepCopy() *Ingress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointOverrides.
+func (in *EndpointOverrides) DeepCopy() *EndpointOverrides {
 	if in == nil {
 		return nil
 	}
-	out := new(Ingress)
+	out := new(EndpointOverrides)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Instrumentation) DeepCopyInto(out *Instrumentation) {
+func (in *Exporter) DeepCopyInto(out *Exporter) {
 	*out = *in
-	out.Status = in.Status
-	out.TypeMeta = in.TypeMeta
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instrumentation.
-func (in *Instrumentation) DeepCopy() *Instrumentation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exporter.
+func (in *Exporter) DeepCopy() *Exporter {
 	if in == nil {
 		return nil
 	}
-	out := new(Instrumentation)
+	out := new(Exporter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Instrumentation) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstrumentationList) DeepCopyInto(out *InstrumentationList) {
+func (in *GPUMIGSpec) DeepCopyInto(out *GPUMIGSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Instrumentation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationList.
-func (in *InstrumentationList) DeepCopy() *InstrumentationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUMIGSpec.
+func (in *GPUMIGSpec) DeepCopy() *GPUMIGSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InstrumentationList)
+	out := new(GPUMIGSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InstrumentationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUMetricsSpec) DeepCopyInto(out *GPUMetricsSpec) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.MIG != nil {
+		in, out := &in.MIG, &out.MIG
+		*out = new(GPUMIGSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUMetricsSpec.
+func (in *GPUMetricsSpec) DeepCopy() *GPUMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstrumentationSpec) DeepCopyInto(out *InstrumentationSpec) {
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
 	*out = *in
-	out.Exporter = in.Exporter
-	in.Resource.DeepCopyInto(&out.Resource)
-	if in.Propagators != nil {
-		in, out := &in.Propagators, &out.Propagators
-		*out = make([]Propagator, len(*in))
-		copy(*out, *in)
-	}
-	out.Sampler = in.Sampler
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Java.DeepCopyInto(&out.Java)
-	in.NodeJS.DeepCopyInto(&out.NodeJS)
-	in.Python.DeepCopyInto(&out.Python)
-	in.DotNet.DeepCopyInto(&out.DotNet)
-	in.Go.DeepCopyInto(&out.Go)
-	in.ApacheHttpd.DeepCopyInto(&out.ApacheHttpd)
-	in.Nginx.DeepCopyInto(&out.Nginx)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationSpec.
-func (in *InstrumentationSpec) DeepCopy() *InstrumentationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
 	if in == nil {
 		return nil
 	}
-	out := new(InstrumentationSpec)
+	out := new(GatewayParentReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstrumentationStatus) DeepCopyInto(out *InstrumentationStatus) {
+func (in *GatewayRoutes) DeepCopyInto(out *GatewayRoutes) {
 	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayParentReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationStatus.
-func (in *InstrumentationStatus) DeepCopy() *InstrumentationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRoutes.
+func (in *GatewayRoutes) DeepCopy() *GatewayRoutes {
 	if in == nil {
 		return nil
 	}
-	out := new(InstrumentationStatus)
+	out := new(GatewayRoutes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Java) DeepCopyInto(out *Java) {
+func (in *Go) DeepCopyInto(out *Go) {
 	*out = *in
 	if in.VolumeSizeLimit != nil {
 		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
@@ -849,12 +1028,442 @@ func (in *Java) DeepCopyInto(out *Java) {
 	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Java.
-func (in *Java) DeepCopy() *Java {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Go.
+func (in *Go) DeepCopy() *Go {
+	if in == nil {
+		return nil
+	}
+	out := new(Go)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardeningSpec) DeepCopyInto(out *HardeningSpec) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunAsGroup != nil {
+		in, out := &in.RunAsGroup, &out.RunAsGroup
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardeningSpec.
+func (in *HardeningSpec) DeepCopy() *HardeningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardeningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostMount) DeepCopyInto(out *HostMount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostMount.
+func (in *HostMount) DeepCopy() *HostMount {
+	if in == nil {
+		return nil
+	}
+	out := new(HostMount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HybridSpec) DeepCopyInto(out *HybridSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(ContainerRuntimeSpec)
+		**out = **in
+	}
+	if in.HostMounts != nil {
+		in, out := &in.HostMounts, &out.HostMounts
+		*out = make([]HostMount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HybridSpec.
+func (in *HybridSpec) DeepCopy() *HybridSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HybridSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]v1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Route = in.Route
+	in.Gateway.DeepCopyInto(&out.Gateway)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Instrumentation) DeepCopyInto(out *Instrumentation) {
+	*out = *in
+	out.Status = in.Status
+	out.TypeMeta = in.TypeMeta
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instrumentation.
+func (in *Instrumentation) DeepCopy() *Instrumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(Instrumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Instrumentation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstrumentationList) DeepCopyInto(out *InstrumentationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Instrumentation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationList.
+func (in *InstrumentationList) DeepCopy() *InstrumentationList {
+	if in == nil {
+		return nil
+	}
+	out := new(InstrumentationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstrumentationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstrumentationSpec) DeepCopyInto(out *InstrumentationSpec) {
+	*out = *in
+	out.Exporter = in.Exporter
+	in.Resource.DeepCopyInto(&out.Resource)
+	if in.Propagators != nil {
+		in, out := &in.Propagators, &out.Propagators
+		*out = make([]Propagator, len(*in))
+		copy(*out, *in)
+	}
+	out.Sampler = in.Sampler
+	if in.RuntimeMetrics != nil {
+		in, out := &in.RuntimeMetrics, &out.RuntimeMetrics
+		*out = new(RuntimeMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(Logs)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Java.DeepCopyInto(&out.Java)
+	in.NodeJS.DeepCopyInto(&out.NodeJS)
+	in.Python.DeepCopyInto(&out.Python)
+	in.DotNet.DeepCopyInto(&out.DotNet)
+	in.Go.DeepCopyInto(&out.Go)
+	in.ApacheHttpd.DeepCopyInto(&out.ApacheHttpd)
+	in.Nginx.DeepCopyInto(&out.Nginx)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationSpec.
+func (in *InstrumentationSpec) DeepCopy() *InstrumentationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstrumentationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstrumentationStatus) DeepCopyInto(out *InstrumentationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationStatus.
+func (in *InstrumentationStatus) DeepCopy() *InstrumentationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstrumentationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Java) DeepCopyInto(out *Java) {
+	*out = *in
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.JmxInsights != nil {
+		in, out := &in.JmxInsights, &out.JmxInsights
+		*out = new(JmxInsights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(Kafka)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiler != nil {
+		in, out := &in.Profiler, &out.Profiler
+		*out = new(Profiler)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Java.
+func (in *Java) DeepCopy() *Java {
+	if in == nil {
+		return nil
+	}
+	out := new(Java)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JmxInsights) DeepCopyInto(out *JmxInsights) {
+	*out = *in
+	if in.TargetSystems != nil {
+		in, out := &in.TargetSystems, &out.TargetSystems
+		*out = make([]JmxTargetSystem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JmxInsights.
+func (in *JmxInsights) DeepCopy() *JmxInsights {
+	if in == nil {
+		return nil
+	}
+	out := new(JmxInsights)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kafka) DeepCopyInto(out *Kafka) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kafka.
+func (in *Kafka) DeepCopy() *Kafka {
+	if in == nil {
+		return nil
+	}
+	out := new(Kafka)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesEventsSpec) DeepCopyInto(out *KubernetesEventsSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesEventsSpec.
+func (in *KubernetesEventsSpec) DeepCopy() *KubernetesEventsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesEventsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogFilter) DeepCopyInto(out *LogFilter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilter.
+func (in *LogFilter) DeepCopy() *LogFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(LogFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogGroupsSpec) DeepCopyInto(out *LogGroupsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogGroupsSpec.
+func (in *LogGroupsSpec) DeepCopy() *LogGroupsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Java)
+	out := new(LogGroupsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logs) DeepCopyInto(out *Logs) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logs.
+func (in *Logs) DeepCopy() *Logs {
+	if in == nil {
+		return nil
+	}
+	out := new(Logs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogsSpec) DeepCopyInto(out *LogsSpec) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]LogFilter, len(*in))
+		copy(*out, *in)
+	}
+	if in.MultilineParsers != nil {
+		in, out := &in.MultilineParsers, &out.MultilineParsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MultilinePatterns != nil {
+		in, out := &in.MultilinePatterns, &out.MultilinePatterns
+		*out = make([]MultilinePattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogGroups != nil {
+		in, out := &in.LogGroups, &out.LogGroups
+		*out = new(LogGroupsSpec)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSpec.
+func (in *LogsSpec) DeepCopy() *LogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -867,6 +1476,11 @@ func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 		*out = new(v2.PodsMetricSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(v2.ExternalMetricSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
@@ -894,6 +1508,57 @@ func (in *MetricsConfigSpec) DeepCopy() *MetricsConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsListenerSpec) DeepCopyInto(out *MetricsListenerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsListenerSpec.
+func (in *MetricsListenerSpec) DeepCopy() *MetricsListenerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsListenerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultilinePattern) DeepCopyInto(out *MultilinePattern) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultilinePattern.
+func (in *MultilinePattern) DeepCopy() *MultilinePattern {
+	if in == nil {
+		return nil
+	}
+	out := new(MultilinePattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeuronMetricsSpec) DeepCopyInto(out *NeuronMetricsSpec) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeuronMetricsSpec.
+func (in *NeuronMetricsSpec) DeepCopy() *NeuronMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NeuronMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NeuronMonitor) DeepCopyInto(out *NeuronMonitor) {
 	*out = *in
@@ -1089,6 +1754,45 @@ func (in *Nginx) DeepCopy() *Nginx {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(ContainerRuntimeSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroup.
+func (in *NodeGroup) DeepCopy() *NodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeJS) DeepCopyInto(out *NodeJS) {
 	*out = *in
@@ -1173,6 +1877,21 @@ func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIdentityAssociationSpec) DeepCopyInto(out *PodIdentityAssociationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIdentityAssociationSpec.
+func (in *PodIdentityAssociationSpec) DeepCopy() *PodIdentityAssociationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIdentityAssociationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Probe) DeepCopyInto(out *Probe) {
 	*out = *in
@@ -1218,6 +1937,34 @@ func (in *Probe) DeepCopy() *Probe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Profiler) DeepCopyInto(out *Profiler) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profiler.
+func (in *Profiler) DeepCopy() *Profiler {
+	if in == nil {
+		return nil
+	}
+	out := new(Profiler)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusConfig) DeepCopyInto(out *PrometheusConfig) {
 	*out = *in
@@ -1241,6 +1988,41 @@ func (in *PrometheusConfig) DeepCopy() *PrometheusConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusConfigReloadSpec) DeepCopyInto(out *PrometheusConfigReloadSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusConfigReloadSpec.
+func (in *PrometheusConfigReloadSpec) DeepCopy() *PrometheusConfigReloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusConfigReloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Proxy) DeepCopyInto(out *Proxy) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
+func (in *Proxy) DeepCopy() *Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Python) DeepCopyInto(out *Python) {
 	*out = *in
@@ -1257,6 +2039,16 @@ func (in *Python) DeepCopyInto(out *Python) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(Kafka)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiler != nil {
+		in, out := &in.Profiler, &out.Profiler
+		*out = new(Profiler)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Python.
@@ -1279,6 +2071,11 @@ func (in *Resource) DeepCopyInto(out *Resource) {
 			(*out)[key] = val
 		}
 	}
+	if in.OwnerStopKinds != nil {
+		in, out := &in.OwnerStopKinds, &out.OwnerStopKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
@@ -1291,6 +2088,26 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeMetrics) DeepCopyInto(out *RuntimeMetrics) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeMetrics.
+func (in *RuntimeMetrics) DeepCopy() *RuntimeMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sampler) DeepCopyInto(out *Sampler) {
 	*out = *in
@@ -1320,3 +2137,99 @@ func (in *ScaleSubresourceStatus) DeepCopy() *ScaleSubresourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTelemetrySpec) DeepCopyInto(out *SelfTelemetrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTelemetrySpec.
+func (in *SelfTelemetrySpec) DeepCopy() *SelfTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(corev1.ServiceInternalTrafficPolicy)
+		**out = **in
+	}
+	if in.TrafficDistribution != nil {
+		in, out := &in.TrafficDistribution, &out.TrafficDistribution
+		*out = new(string)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
+func (in *ServiceSpec) DeepCopy() *ServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WindowsSpec) DeepCopyInto(out *WindowsSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WindowsSpec.
+func (in *WindowsSpec) DeepCopy() *WindowsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WindowsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,425 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// AmazonCloudWatchAgentSpec defines the desired state of AmazonCloudWatchAgent.
+type AmazonCloudWatchAgentSpec struct {
+	// ManagementState defines if the CR should be managed by the operator or not.
+	// Default is managed.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:default:=managed
+	ManagementState v1alpha1.ManagementStateType `json:"managementState,omitempty"`
+	// Resources to set on the OpenTelemetry Collector pods.
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector to schedule OpenTelemetry Collector pods.
+	// This is only relevant to daemonset, statefulset, and deployment mode
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeGroups are configuration profiles for the nodes matching their selector. Each node group is rendered
+	// as its own DaemonSet and ConfigMap, and its nodes are excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	NodeGroups []v1alpha1.NodeGroup `json:"nodeGroups,omitempty"`
+	// Windows renders an additional DaemonSet running the agent on the Windows nodes of the cluster, which are
+	// then excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	Windows *v1alpha1.WindowsSpec `json:"windows,omitempty"`
+	// Hybrid renders an additional DaemonSet running the agent on the EKS Hybrid Nodes and other on-premises nodes of
+	// the cluster, which are then excluded from the default DaemonSet.
+	// This is only relevant to daemonset mode.
+	// +optional
+	Hybrid *v1alpha1.HybridSpec `json:"hybrid,omitempty"`
+	// ContainerRuntime mounts the socket of the node container runtime into the agent pods, where the agent
+	// expects it for the container metrics and metadata.
+	// This is only relevant to daemonset mode.
+	// +optional
+	ContainerRuntime *v1alpha1.ContainerRuntimeSpec `json:"containerRuntime,omitempty"`
+	// Args is the set of arguments to pass to the OpenTelemetry Collector binary
+	// +optional
+	Args map[string]string `json:"args,omitempty"`
+	// Replicas is the number of pod instances for the underlying OpenTelemetry Collector. Set this if your are not using autoscaling
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// MinReplicas sets a lower bound to the autoscaling feature.  Set this if you are using autoscaling. It must be at least 1
+	// +optional
+	// Deprecated: use "AmazonCloudWatchAgent.Spec.Autoscaler.MinReplicas" instead.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas sets an upper bound to the autoscaling feature. If MaxReplicas is set autoscaling is enabled.
+	// +optional
+	// Deprecated: use "AmazonCloudWatchAgent.Spec.Autoscaler.MaxReplicas" instead.
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// Autoscaler specifies the pod autoscaling configuration to use
+	// for the AmazonCloudWatchAgent workload.
+	//
+	// +optional
+	Autoscaler *v1alpha1.AutoscalerSpec `json:"autoscaler,omitempty"`
+	// PodDisruptionBudget specifies the pod disruption budget configuration to use
+	// for the AmazonCloudWatchAgent workload.
+	//
+	// +optional
+	PodDisruptionBudget *v1alpha1.PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// SecurityContext configures the container security context for
+	// the amazon-cloudwatch-agent container.
+	//
+	// In deployment, daemonset, or statefulset mode, this controls
+	// the security context settings for the primary application
+	// container.
+	//
+	// In sidecar mode, this controls the security context for the
+	// injected sidecar container.
+	//
+	// +optional
+	SecurityContext *v1.SecurityContext `json:"securityContext,omitempty"`
+	// PodSecurityContext configures the pod security context for the
+	// amazon-cloudwatch-agent pod, when running as a deployment, daemonset,
+	// or statefulset.
+	//
+	// In sidecar mode, the amazon-cloudwatch-agent-operator will ignore this setting.
+	//
+	// +optional
+	PodSecurityContext *v1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Hardening runs the agent as a non-root user, without privilege escalation and with only the capabilities
+	// needed by the enabled features. Its settings take precedence over SecurityContext and PodSecurityContext.
+	//
+	// +optional
+	Hardening *v1alpha1.HardeningSpec `json:"hardening,omitempty"`
+	// PodAnnotations is the set of annotations that will be attached to
+	// Collector and Target Allocator pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// TargetAllocator indicates a value which determines whether to spawn a target allocation resource or not.
+	// +optional
+	TargetAllocator v1alpha1.AmazonCloudWatchAgentTargetAllocator `json:"targetAllocator,omitempty"`
+	// Mode represents how the collector should be deployed (deployment, daemonset, statefulset or sidecar)
+	// +optional
+	Mode v1alpha1.Mode `json:"mode,omitempty"`
+	// ServiceAccount indicates the name of an existing service account to use with this instance. When set,
+	// the operator will not automatically create a ServiceAccount for the collector.
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// ServiceAccountAnnotations are added to the ServiceAccount created for the agent, e.g. eks.amazonaws.com/role-arn
+	// to bind it to an IAM role through IAM roles for service accounts. They cannot be used with serviceAccount, as the
+	// operator does not manage existing service accounts.
+	// +optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// PodIdentityAssociation binds the service account of the agent to an IAM role with EKS Pod Identity. The operator
+	// renders a PodIdentityAssociation of the EKS controller of AWS Controllers for Kubernetes, which must be installed.
+	// +optional
+	PodIdentityAssociation *v1alpha1.PodIdentityAssociationSpec `json:"podIdentityAssociation,omitempty"`
+	// Image indicates the container image to use for the OpenTelemetry Collector.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version of the CloudWatch agent to run, e.g. 1.300049.1. The operator resolves it to the image of that version
+	// in the repository of its default agent image and records it in the status. Without a version, the agent follows
+	// the version of the operator as defined by the upgradeStrategy. Cannot be set together with image.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.-]*$`
+	Version string `json:"version,omitempty"`
+	// FIPS runs the FIPS validated agent image configured in the operator, unless image is set, and makes the agent
+	// send its telemetry to the FIPS endpoints of the AWS services.
+	// +optional
+	FIPS bool `json:"fips,omitempty"`
+	// WorkingDir represents Container's working directory. If not specified,
+	// the container runtime's default will be used, which might
+	// be configured in the container image. Cannot be updated.
+	// +optional
+	WorkingDir string `json:"workingDir,omitempty"`
+	// UpgradeStrategy represents how the operator will handle upgrades to the CR when a newer version of the operator is deployed
+	// +optional
+	UpgradeStrategy v1alpha1.UpgradeStrategy `json:"upgradeStrategy"`
+	// ImagePullPolicy indicates the pull policy to be used for retrieving the container image (Always, Never, IfNotPresent)
+	// +optional
+	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// Prometheus is the raw YAML to be used as the collector's prometheus configuration.
+	// +optional
+	Prometheus v1alpha1.PrometheusConfig `json:"prometheus,omitempty"`
+	// PrometheusConfigReload applies the changes of the Prometheus configuration to the running agents and target
+	// allocator instead of rolling them, which keeps the OTLP connections of the agents open.
+	// +optional
+	PrometheusConfigReload *v1alpha1.PrometheusConfigReloadSpec `json:"prometheusConfigReload,omitempty"`
+	// Config is the agent configuration, written as an object instead of a JSON string. Refer to the CloudWatch
+	// Agent documentation for details.
+	// +required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *runtime.RawExtension `json:"config,omitempty"`
+	// ConfigSources are JSON agent configuration fragments merged on top of Config in the order they are
+	// listed. Objects are merged key by key and a later source overrides scalars and lists of earlier ones,
+	// so platform-wide defaults and team-specific additions can be maintained separately.
+	// +optional
+	ConfigSources []v1alpha1.ConfigSource `json:"configSources,omitempty"`
+	// AWS sets the region, the IAM role and the endpoints of the agent configuration, on top of Config and
+	// ConfigSources, so that sending telemetry to another account or region does not require templating the JSON.
+	// +optional
+	AWS *v1alpha1.AWSSpec `json:"aws,omitempty"`
+	// Proxy routes the traffic of the agent container to AWS through an HTTP proxy, for clusters without direct
+	// internet egress. The proxy is set in the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars honored by the agent,
+	// and the instance metadata service, the EKS Pod Identity agent and the cluster services are never proxied.
+	// +optional
+	Proxy *v1alpha1.Proxy `json:"proxy,omitempty"`
+	// KubernetesEvents deploys a pipeline shipping the events of the cluster to CloudWatch Logs, next to the
+	// Container Insights data. This is not supported in sidecar mode.
+	// +optional
+	KubernetesEvents *v1alpha1.KubernetesEventsSpec `json:"kubernetesEvents,omitempty"`
+	// ControlPlaneMetrics scrapes a curated set of metrics of the EKS control plane, the API server and etcd
+	// through the Kubernetes API, and sends them to CloudWatch as Container Insights Prometheus metrics. Every
+	// replica scrapes the control plane, so this is only supported in deployment and statefulset modes.
+	// +optional
+	ControlPlaneMetrics *v1alpha1.ControlPlaneMetricsSpec `json:"controlPlaneMetrics,omitempty"`
+	// Logs deploys Fluent Bit on the nodes of the agent to collect the application, dataplane and host logs into
+	// the Container Insights log groups, in place of the Fluent Bit manifests of Container Insights. This is not
+	// supported in sidecar mode.
+	// +optional
+	Logs *v1alpha1.LogsSpec `json:"logs,omitempty"`
+	// GPUMetrics deploys the NVIDIA DCGM exporter on the nodes with NVIDIA GPUs, and scrapes it from the agent on
+	// the same node. This is only supported in daemonset mode.
+	// +optional
+	GPUMetrics *v1alpha1.GPUMetricsSpec `json:"gpuMetrics,omitempty"`
+	// NeuronMetrics deploys the Neuron monitor on the Inferentia and Trainium nodes, and scrapes it from the agent on
+	// the same node. This is only supported in daemonset mode.
+	// +optional
+	NeuronMetrics *v1alpha1.NeuronMetricsSpec `json:"neuronMetrics,omitempty"`
+	// EFAMetrics collects the metrics of the Elastic Fabric Adapters from the agents of the nodes exposing EFA
+	// devices. This is only supported in daemonset mode.
+	// +optional
+	EFAMetrics *v1alpha1.EFAMetricsSpec `json:"efaMetrics,omitempty"`
+	// StatsD enables the StatsD listener of the agent in the metrics_collected section of Config, and exposes it on
+	// the Service of the agent. In daemonset mode, the listener can also be bound to the port of the node so that the
+	// daemons of the node reach the agent on the node IP.
+	// +optional
+	StatsD *v1alpha1.MetricsListenerSpec `json:"statsd,omitempty"`
+	// CollectD enables the collectd network listener of the agent in the metrics_collected section of Config, and
+	// exposes it like StatsD.
+	// +optional
+	CollectD *v1alpha1.MetricsListenerSpec `json:"collectd,omitempty"`
+	// SelfTelemetry exposes the internal metrics of the agent, such as the data points and spans it accepted,
+	// refused, dropped or failed to send, on a Prometheus endpoint of the pod. The metrics can also be sent to
+	// CloudWatch, per node and pod, to alert on the telemetry lost by the agents.
+	// +optional
+	SelfTelemetry *v1alpha1.SelfTelemetrySpec `json:"selfTelemetry,omitempty"`
+	// OtelConfig is the OpenTelemetry Collector configuration of the agent, written as an object instead of a YAML
+	// string.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	OtelConfig *runtime.RawExtension `json:"otelConfig,omitempty"`
+	// EMF sets the namespace, log group, log stream and dimension rollup of the awsemf exporters of OtelConfig,
+	// on top of the settings of the exporters.
+	// +optional
+	EMF *v1alpha1.EMFSpec `json:"emf,omitempty"`
+	// ConfigSecret projects keys of a Secret in the same namespace into the agent configuration directory,
+	// next to the configuration rendered from Config. The agent merges every JSON file in that directory,
+	// which keeps credentials and private endpoints out of the ConfigMap managed by the operator.
+	// This is not supported in sidecar mode.
+	// +optional
+	ConfigSecret *v1.SecretProjection `json:"configSecret,omitempty"`
+	// VolumeMounts represents the mount points to use in the underlying collector deployment(s)
+	// +optional
+	// +listType=atomic
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Ports allows a set of ports to be exposed by the underlying v1.Service. By default, the operator
+	// will attempt to infer the required ports by parsing the .Spec.Config property but this property can be
+	// used to open additional ports that can't be inferred by the operator, like for custom receivers.
+	// +optional
+	// +listType=atomic
+	Ports []v1.ServicePort `json:"ports,omitempty"`
+	// Service customizes the Service exposing the agent ports. Ports that can't be inferred from the
+	// configuration are still declared through Ports.
+	// +optional
+	Service v1alpha1.ServiceSpec `json:"service,omitempty"`
+	// ENV vars to set on the OpenTelemetry Collector's Pods. These can then in certain cases be
+	// consumed in the config file for the Collector.
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// List of sources to populate environment variables on the OpenTelemetry Collector's Pods.
+	// These can then in certain cases be consumed in the config file for the Collector.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+	// VolumeClaimTemplates will provide stable storage using PersistentVolumes. Only available when the mode=statefulset.
+	// +optional
+	// +listType=atomic
+	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+	// PersistentVolumeClaimRetentionPolicy describes the lifecycle of the persistent volume claims created from
+	// VolumeClaimTemplates, e.g. to keep the file_storage queue of a scaled down replica. Only available when the
+	// mode=statefulset.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+	// Toleration to schedule OpenTelemetry Collector pods.
+	// This is only relevant to daemonset, statefulset, and deployment mode
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// Volumes represents which volumes to use in the underlying collector deployment(s).
+	// +optional
+	// +listType=atomic
+	Volumes []v1.Volume `json:"volumes,omitempty"`
+	// HostMounts are directories and files of the node mounted read-only into the agent container, such as
+	// /proc, /sys or the log directories to collect. Host paths mounted through Volumes are writable unless
+	// their mount says otherwise, so HostMounts is the way to grant the agent a minimal host access.
+	// This is only relevant to daemonset mode.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HostMounts []v1alpha1.HostMount `json:"hostMounts,omitempty"`
+	// Ingress is used to specify how OpenTelemetry Collector is exposed. This
+	// functionality is only available if one of the valid modes is set.
+	// Valid modes are: deployment, daemonset and statefulset.
+	// +optional
+	Ingress v1alpha1.Ingress `json:"ingress,omitempty"`
+	// HostNetwork indicates if the pod should run in the host networking namespace.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// DNSPolicy sets the DNS policy of the agent pods. Defaults to ClusterFirstWithHostNet when
+	// hostNetwork is enabled and ClusterFirst otherwise.
+	// +optional
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// DNSConfig specifies DNS parameters of the agent pods, merged with the ones generated from DNSPolicy.
+	// It is required when DNSPolicy is set to None.
+	// +optional
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
+	// If specified, indicates the pod's priority.
+	// If not specified, the pod priority will be default or zero if there is no
+	// default. Defaults to system-node-critical in daemonset mode.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// If specified, indicates the pod's scheduling constraints
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// Actions that the management system should take in response to container lifecycle events. Cannot be updated.
+	// +optional
+	Lifecycle *v1.Lifecycle `json:"lifecycle,omitempty"`
+	// Duration in seconds the agent pods need to terminate gracefully, including the time spent in a preStop hook.
+	// This is not applicable to Sidecar mode.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// Liveness config for the OpenTelemetry Collector except the probe handler which is auto generated from the health extension of the collector.
+	// It is only effective when healthcheckextension is configured in the OpenTelemetry Collector pipeline.
+	// +optional
+	LivenessProbe *v1alpha1.Probe `json:"livenessProbe,omitempty"`
+	// Readiness config for the agent container. The probe handler is generated from the health extension of the collector
+	// and the probe is only added when this is set and healthcheckextension is configured.
+	// +optional
+	ReadinessProbe *v1alpha1.Probe `json:"readinessProbe,omitempty"`
+	// Startup config for the agent container, which holds off the liveness probe until a slow starting agent is up.
+	// The probe handler is generated from the health extension of the collector and the probe is only added when this
+	// is set and healthcheckextension is configured.
+	// +optional
+	StartupProbe *v1alpha1.Probe `json:"startupProbe,omitempty"`
+	// InitContainers allows injecting initContainers to the Collector's pod definition.
+	// These init containers can be used to fetch secrets for injection into the
+	// configuration from external sources, run added checks, etc. Any errors during the execution of
+	// an initContainer will lead to a restart of the Pod. More info:
+	// https://kubernetes.io/docs/concepts/workloads/pods/init-containers/
+	// +optional
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+
+	// AdditionalContainers allows injecting additional containers into the Collector's pod definition.
+	// These sidecar containers can be used for authentication proxies, log shipping sidecars, agents for shipping
+	// metrics to their cloud, or in general sidecars that do not support automatic injection. In sidecar mode the
+	// containers are injected into the workload pod next to the agent container, unless the pod already has a
+	// container with the same name. More info about sidecars:
+	// https://kubernetes.io/docs/tasks/configure-pod-container/share-process-namespace/
+	//
+	// Container names managed by the operator:
+	// * `otc-container`
+	//
+	// Overriding containers managed by the operator is outside the scope of what the maintainers will support and by
+	// doing so, you wil accept the risk of it breaking things.
+	//
+	// +optional
+	AdditionalContainers []v1.Container `json:"additionalContainers,omitempty"`
+
+	// ObservabilitySpec defines how telemetry data gets handled.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Observability"
+	Observability v1alpha1.ObservabilitySpec `json:"observability,omitempty"`
+
+	// TopologySpreadConstraints embedded kubernetes pod configuration option,
+	// controls how pods are spread across your cluster among failure-domains
+	// such as regions, zones, nodes, and other user-defined topology domains
+	// https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/
+	// This is only relevant to statefulset, and deployment mode
+	// +optional
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// SpreadAcrossZones spreads the collector pods evenly across the availability zones of the cluster, in
+	// addition to the TopologySpreadConstraints. Pods are still scheduled when a zone is unavailable.
+	// This is only relevant to statefulset, and deployment mode
+	// +optional
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+
+	// ConfigMaps is a list of ConfigMaps in the same namespace as the AmazonCloudWatchAgent
+	// object, which shall be mounted into the Collector Pods.
+	// Each ConfigMap will be added to the Collector's Deployments as a volume named `configmap-<configmap-name>` and
+	// mounted at `/var/conf/<mountpath>/configmap-<configmap-name>`.
+	ConfigMaps []v1alpha1.ConfigMapsSpec `json:"configmaps,omitempty"`
+	// UpdateStrategy represents the strategy the operator will take replacing existing DaemonSet pods with new pods
+	// https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/daemon-set-v1/#DaemonSetSpec
+	// This is only applicable to Daemonset mode.
+	// +optional
+	UpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// UpdateStrategy represents the strategy the operator will take replacing existing Deployment pods with new pods
+	// https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/deployment-v1/#DeploymentSpec
+	// This is only applicable to Deployment mode.
+	// +optional
+	DeploymentUpdateStrategy appsv1.DeploymentStrategy `json:"deploymentUpdateStrategy,omitempty"`
+	// MinReadySeconds is the minimum number of seconds a new collector pod must be ready, without any of its
+	// containers crashing, before it is considered available and the rollout continues.
+	// This is only relevant to daemonset, statefulset, and deployment mode
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rollout of the collector Deployment to make
+	// progress before it is reported as failed in the Deployment conditions. Defaults to 600s.
+	// This is only applicable to Deployment mode.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=otelcol;otelcols
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Deployment Mode"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="CloudWatch Agent Version"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.scale.statusReplicas"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.image"
+// +kubebuilder:printcolumn:name="Management",type="string",JSONPath=".spec.managementState",description="Management State"
+
+// AmazonCloudWatchAgent is the Schema for the amazoncloudwatchagents API.
+type AmazonCloudWatchAgent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AmazonCloudWatchAgentSpec            `json:"spec,omitempty"`
+	Status v1alpha1.AmazonCloudWatchAgentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AmazonCloudWatchAgentList contains a list of AmazonCloudWatchAgent.
+type AmazonCloudWatchAgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AmazonCloudWatchAgent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AmazonCloudWatchAgent{}, &AmazonCloudWatchAgentList{})
+}
@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+var (
+	_ conversion.Convertible = (*AmazonCloudWatchAgent)(nil)
+	_ conversion.Convertible = (*Instrumentation)(nil)
+)
+
+// ConvertTo converts the AmazonCloudWatchAgent to the v1alpha1 version, whose configurations are a JSON and a YAML
+// string.
+func (src *AmazonCloudWatchAgent) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.AmazonCloudWatchAgent)
+	spec := src.Spec.DeepCopy()
+	config, otelConfig := spec.Config, spec.OtelConfig
+	spec.Config, spec.OtelConfig = nil, nil
+	if err := convertSpec(spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert the AmazonCloudWatchAgent %s/%s: %w", src.Namespace, src.Name, err)
+	}
+	if config != nil && len(config.Raw) > 0 {
+		dst.Spec.Config = string(config.Raw)
+	}
+	if otelConfig != nil && len(otelConfig.Raw) > 0 {
+		data, err := yaml.JSONToYAML(otelConfig.Raw)
+		if err != nil {
+			return fmt.Errorf("failed to convert the otelConfig of the AmazonCloudWatchAgent %s/%s: %w", src.Namespace, src.Name, err)
+		}
+		dst.Spec.OtelConfig = string(data)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	src.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 AmazonCloudWatchAgent, whose configurations have to be objects.
+func (dst *AmazonCloudWatchAgent) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.AmazonCloudWatchAgent)
+	spec := src.Spec.DeepCopy()
+	config, otelConfig := spec.Config, spec.OtelConfig
+	spec.Config, spec.OtelConfig = "", ""
+	if err := convertSpec(spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert the AmazonCloudWatchAgent %s/%s: %w", src.Namespace, src.Name, err)
+	}
+	var err error
+	if dst.Spec.Config, err = objectFromJSON([]byte(config)); err != nil {
+		return fmt.Errorf("failed to convert the config of the AmazonCloudWatchAgent %s/%s: %w", src.Namespace, src.Name, err)
+	}
+	otelJSON, err := yaml.YAMLToJSON([]byte(otelConfig))
+	if err == nil {
+		dst.Spec.OtelConfig, err = objectFromJSON(otelJSON)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert the otelConfig of the AmazonCloudWatchAgent %s/%s: %w", src.Namespace, src.Name, err)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	src.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// convertSpec copies the fields of the spec to the spec of the other version through their common JSON encoding.
+func convertSpec(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// objectFromJSON returns the JSON object, or nil for an empty configuration.
+func objectFromJSON(data []byte) (*runtime.RawExtension, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("the configuration is not an object: %w", err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: compact.Bytes()}, nil
+}
+
+// ConvertTo converts the Instrumentation to the v1alpha1 version.
+func (src *Instrumentation) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Instrumentation)
+	dst.ObjectMeta = src.ObjectMeta
+	src.Spec.DeepCopyInto(&dst.Spec)
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 Instrumentation.
+func (dst *Instrumentation) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Instrumentation)
+	dst.ObjectMeta = src.ObjectMeta
+	src.Spec.DeepCopyInto(&dst.Spec)
+	dst.Status = src.Status
+	return nil
+}
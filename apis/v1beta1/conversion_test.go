@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestAmazonCloudWatchAgentConversion(t *testing.T) {
+	replicas := int32(2)
+	hub := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:     v1alpha1.ModeDeployment,
+			Replicas: &replicas,
+			Image:    "cloudwatch-agent:1",
+			Env:      []corev1.EnvVar{{Name: "RUN_WITH_IRSA", Value: "True"}},
+			Config: `{
+  "agent": {"region": "us-west-2"},
+  "logs": {"metrics_collected": {"kubernetes": {}}}
+}`,
+			OtelConfig: "receivers:\n  otlp:\n    protocols:\n      grpc: {}\n",
+		},
+		Status: v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300"},
+	}
+
+	spoke := &AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, hub.ObjectMeta, spoke.ObjectMeta)
+	assert.Equal(t, hub.Spec.Mode, spoke.Spec.Mode)
+	assert.Equal(t, &replicas, spoke.Spec.Replicas)
+	assert.Equal(t, hub.Spec.Env, spoke.Spec.Env)
+	assert.Equal(t, hub.Status, spoke.Status)
+	assert.JSONEq(t, hub.Spec.Config, string(spoke.Spec.Config.Raw))
+	assert.JSONEq(t, `{"receivers": {"otlp": {"protocols": {"grpc": {}}}}}`, string(spoke.Spec.OtelConfig.Raw))
+
+	converted := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	assert.JSONEq(t, hub.Spec.Config, converted.Spec.Config)
+	assert.YAMLEq(t, hub.Spec.OtelConfig, converted.Spec.OtelConfig)
+	converted.Spec.Config, converted.Spec.OtelConfig = hub.Spec.Config, hub.Spec.OtelConfig
+	assert.Equal(t, hub, converted)
+}
+
+func TestAmazonCloudWatchAgentConversionWithoutConfig(t *testing.T) {
+	hub := &v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeDaemonSet}}
+	spoke := &AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.Nil(t, spoke.Spec.Config)
+	assert.Nil(t, spoke.Spec.OtelConfig)
+
+	converted := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
+
+func TestAmazonCloudWatchAgentConversionInvalidConfig(t *testing.T) {
+	for _, spec := range []v1alpha1.AmazonCloudWatchAgentSpec{
+		{Config: "{"},
+		{Config: `["agent"]`},
+		{OtelConfig: "receivers: ["},
+	} {
+		hub := &v1alpha1.AmazonCloudWatchAgent{Spec: spec}
+		assert.Error(t, (&AmazonCloudWatchAgent{}).ConvertFrom(hub))
+	}
+
+	spoke := &AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{OtelConfig: &runtime.RawExtension{Raw: []byte("{")}}}
+	assert.Error(t, spoke.ConvertTo(&v1alpha1.AmazonCloudWatchAgent{}))
+}
+
+func TestInstrumentationConversion(t *testing.T) {
+	hub := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "java", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://cloudwatch-agent.amazon-cloudwatch:4316"},
+			Java:     v1alpha1.Java{Image: "java:1"},
+		},
+	}
+	spoke := &Instrumentation{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, hub.Spec, spoke.Spec)
+
+	converted := &v1alpha1.Instrumentation{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
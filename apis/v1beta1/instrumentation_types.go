@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=otelinst;otelinsts
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.exporter.endpoint"
+// +kubebuilder:printcolumn:name="Sampler",type="string",JSONPath=".spec.sampler.type"
+// +kubebuilder:printcolumn:name="Sampler Arg",type="string",JSONPath=".spec.sampler.argument"
+
+// Instrumentation is the spec for OpenTelemetry instrumentation.
+type Instrumentation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   v1alpha1.InstrumentationSpec   `json:"spec,omitempty"`
+	Status v1alpha1.InstrumentationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InstrumentationList contains a list of Instrumentation.
+type InstrumentationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Instrumentation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Instrumentation{}, &InstrumentationList{})
+}
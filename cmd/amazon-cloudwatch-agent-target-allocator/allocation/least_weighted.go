@@ -0,0 +1,241 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var _ Allocator = &leastWeightedAllocator{}
+
+const leastWeightedStrategyName = "least-weighted"
+
+// leastWeightedAllocator assigns each new target to the collector with the fewest targets. Unlike the consistent
+// hashing, the assignments depend on the order in which the targets were discovered, so that the targets of the
+// remaining collectors are left in place when the collectors change.
+type leastWeightedAllocator struct {
+	// m protects collectors and targetItems for concurrent use.
+	m sync.RWMutex
+
+	// collectors is a map from a Collector's name to a Collector instance
+	// collectorKey -> collector pointer
+	collectors map[string]*Collector
+
+	// targetItems is a map from a target item's hash to the target items allocated state
+	// targetItem hash -> target item pointer
+	targetItems map[string]*target.Item
+
+	// collectorKey -> job -> target item hash -> true
+	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
+
+	log logr.Logger
+
+	filter Filter
+}
+
+func newLeastWeightedAllocator(log logr.Logger, opts ...AllocationOption) Allocator {
+	lwAllocator := &leastWeightedAllocator{
+		collectors:                    make(map[string]*Collector),
+		targetItems:                   make(map[string]*target.Item),
+		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		log:                           log,
+	}
+	for _, opt := range opts {
+		opt(lwAllocator)
+	}
+
+	return lwAllocator
+}
+
+// SetFilter sets the filtering hook to use.
+func (l *leastWeightedAllocator) SetFilter(filter Filter) {
+	l.filter = filter
+}
+
+// findNextCollector returns the collector with the fewest targets, breaking the ties by name so that the
+// assignments do not depend on the iteration order of the map. The caller of this method has to acquire a lock.
+// INVARIANT: l.collectors must have at least 1 collector set.
+func (l *leastWeightedAllocator) findNextCollector() *Collector {
+	var col *Collector
+	for _, v := range l.collectors {
+		if col == nil || v.NumTargets < col.NumTargets || (v.NumTargets == col.NumTargets && v.Name < col.Name) {
+			col = v
+		}
+	}
+	return col
+}
+
+// addCollectorTargetItemMapping keeps track of which collector has which jobs and targets. The caller of this
+// method has to acquire a lock.
+func (l *leastWeightedAllocator) addCollectorTargetItemMapping(tg *target.Item) {
+	if l.targetItemsPerJobPerCollector[tg.CollectorName] == nil {
+		l.targetItemsPerJobPerCollector[tg.CollectorName] = make(map[string]map[string]bool)
+	}
+	if l.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] == nil {
+		l.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] = make(map[string]bool)
+	}
+	l.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()] = true
+}
+
+// addTargetToTargetItems assigns a target to the next available collector and adds it to the allocator's
+// targetItems. This method is called from within SetTargets and SetCollectors, which acquire the needed lock.
+// INVARIANT: l.collectors must have at least 1 collector set.
+func (l *leastWeightedAllocator) addTargetToTargetItems(tg *target.Item) {
+	chosenCollector := l.findNextCollector()
+	tg.CollectorName = chosenCollector.Name
+	l.targetItems[tg.Hash()] = tg
+	l.addCollectorTargetItemMapping(tg)
+	chosenCollector.NumTargets++
+	TargetsPerCollector.WithLabelValues(chosenCollector.Name, leastWeightedStrategyName).Set(float64(chosenCollector.NumTargets))
+}
+
+// handleTargets receives the new and removed targets and reconciles the current state.
+// Any removals are removed from the allocator's targetItems and unassigned from the corresponding collector.
+// Any net-new additions are assigned to the next available collector.
+func (l *leastWeightedAllocator) handleTargets(diff diff.Changes[*target.Item]) {
+	// Check for removals
+	for k, item := range l.targetItems {
+		if _, ok := diff.Removals()[k]; ok {
+			col := l.collectors[item.CollectorName]
+			col.NumTargets--
+			delete(l.targetItems, k)
+			delete(l.targetItemsPerJobPerCollector[item.CollectorName][item.JobName], item.Hash())
+			TargetsPerCollector.WithLabelValues(item.CollectorName, leastWeightedStrategyName).Set(float64(col.NumTargets))
+		}
+	}
+
+	// Check for additions
+	for k, item := range diff.Additions() {
+		if _, ok := l.targetItems[k]; !ok {
+			l.addTargetToTargetItems(item)
+		}
+	}
+}
+
+// handleCollectors receives the new and removed collectors and reconciles the current state.
+// The targets of the removed collectors, and the ones saved before any collector was present, are assigned to the
+// next available collector. The targets of the other collectors are not moved.
+func (l *leastWeightedAllocator) handleCollectors(diff diff.Changes[*Collector]) {
+	// Clear removed collectors
+	for _, k := range diff.Removals() {
+		delete(l.collectors, k.Name)
+		delete(l.targetItemsPerJobPerCollector, k.Name)
+		TargetsPerCollector.WithLabelValues(k.Name, leastWeightedStrategyName).Set(0)
+	}
+	// Insert the new collectors
+	for _, i := range diff.Additions() {
+		l.collectors[i.Name] = NewCollector(i.Name)
+	}
+
+	// Allocate the targets which are not assigned to any of the remaining collectors
+	for _, item := range l.targetItems {
+		if !l.targetItemsPerJobPerCollector[item.CollectorName][item.JobName][item.Hash()] {
+			l.addTargetToTargetItems(item)
+		}
+	}
+}
+
+// SetTargets accepts a list of targets that will be used to make
+// load balancing decisions. This method should be called when there are
+// new targets discovered or existing targets are shutdown.
+func (l *leastWeightedAllocator) SetTargets(targets map[string]*target.Item) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargets", leastWeightedStrategyName))
+	defer timer.ObserveDuration()
+
+	if l.filter != nil {
+		targets = l.filter.Apply(targets)
+	}
+	RecordTargetsKept(targets)
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if len(l.collectors) == 0 {
+		l.log.Info("No collector instances present, saving targets to allocate to collector(s)")
+		// The targets are assigned when the first collectors are set
+		targetsDiffEmptyCollectorSet := diff.Maps(l.targetItems, targets)
+		for k, item := range targetsDiffEmptyCollectorSet.Additions() {
+			l.targetItems[k] = item
+		}
+		for k := range targetsDiffEmptyCollectorSet.Removals() {
+			delete(l.targetItems, k)
+		}
+		return
+	}
+	// Check for target changes
+	targetsDiff := diff.Maps(l.targetItems, targets)
+	// If there are any additions or removals
+	if len(targetsDiff.Additions()) != 0 || len(targetsDiff.Removals()) != 0 {
+		l.handleTargets(targetsDiff)
+	}
+}
+
+// SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
+// This method is called when Collectors are added or removed.
+func (l *leastWeightedAllocator) SetCollectors(collectors map[string]*Collector) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetCollectors", leastWeightedStrategyName))
+	defer timer.ObserveDuration()
+
+	CollectorsAllocatable.WithLabelValues(leastWeightedStrategyName).Set(float64(len(collectors)))
+	if len(collectors) == 0 {
+		l.log.Info("No collector instances present")
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	// Check for collector changes
+	collectorsDiff := diff.Maps(l.collectors, collectors)
+	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
+		l.handleCollectors(collectorsDiff)
+	}
+	l.log.Info("Setting collector completed")
+}
+
+func (l *leastWeightedAllocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	if _, ok := l.targetItemsPerJobPerCollector[collector]; !ok {
+		return []*target.Item{}
+	}
+	if _, ok := l.targetItemsPerJobPerCollector[collector][job]; !ok {
+		return []*target.Item{}
+	}
+	targetItemsCopy := make([]*target.Item, len(l.targetItemsPerJobPerCollector[collector][job]))
+	index := 0
+	for targetHash := range l.targetItemsPerJobPerCollector[collector][job] {
+		targetItemsCopy[index] = l.targetItems[targetHash]
+		index++
+	}
+	return targetItemsCopy
+}
+
+// TargetItems returns a shallow copy of the targetItems map.
+func (l *leastWeightedAllocator) TargetItems() map[string]*target.Item {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	targetItemsCopy := make(map[string]*target.Item)
+	for k, v := range l.targetItems {
+		targetItemsCopy[k] = v
+	}
+	return targetItemsCopy
+}
+
+// Collectors returns a shallow copy of the collectors map.
+func (l *leastWeightedAllocator) Collectors() map[string]*Collector {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	collectorsCopy := make(map[string]*Collector)
+	for k, v := range l.collectors {
+		collectorsCopy[k] = v
+	}
+	return collectorsCopy
+}
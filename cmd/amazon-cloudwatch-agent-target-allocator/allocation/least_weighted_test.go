@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastWeightedEvenDistribution(t *testing.T) {
+	c := newLeastWeightedAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.SetTargets(MakeNNewTargets(10, 3, 0))
+	assert.Len(t, c.TargetItems(), 10)
+	counts := map[int]int{}
+	for _, col := range c.Collectors() {
+		counts[col.NumTargets]++
+	}
+	assert.Equal(t, map[int]int{3: 2, 4: 1}, counts)
+}
+
+func TestLeastWeightedKeepsAssignments(t *testing.T) {
+	c := newLeastWeightedAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.SetTargets(MakeNNewTargets(9, 3, 0))
+	assigned := map[string]string{}
+	for k, item := range c.TargetItems() {
+		assigned[k] = item.CollectorName
+	}
+
+	// only the targets of the removed collector are moved
+	cols := MakeNCollectors(2, 0)
+	c.SetCollectors(cols)
+	for k, item := range c.TargetItems() {
+		assert.Contains(t, cols, item.CollectorName)
+		if assigned[k] != "collector-2" {
+			assert.Equal(t, assigned[k], item.CollectorName)
+		}
+		assert.Len(t, c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), 1)
+	}
+	for _, col := range c.Collectors() {
+		assert.InDelta(t, 4.5, col.NumTargets, 0.5)
+	}
+
+	// the removed targets are unassigned
+	c.SetTargets(MakeNNewTargets(4, 3, 0))
+	total := 0
+	for _, col := range c.Collectors() {
+		total += col.NumTargets
+	}
+	assert.Equal(t, 4, total)
+}
+
+func TestTargetsWithNoCollectorsLeastWeighted(t *testing.T) {
+	c := newLeastWeightedAllocator(logger)
+	c.SetTargets(MakeNNewTargets(4, 0, 0))
+	assert.Len(t, c.TargetItems(), 4)
+
+	c.SetCollectors(MakeNCollectors(2, 0))
+	for _, col := range c.Collectors() {
+		assert.Equal(t, 2, col.NumTargets)
+	}
+	for _, item := range c.TargetItems() {
+		assert.Len(t, c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), 1)
+	}
+}
@@ -115,4 +115,8 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	err = Register(leastWeightedStrategyName, newLeastWeightedAllocator)
+	if err != nil {
+		panic(err)
+	}
 }
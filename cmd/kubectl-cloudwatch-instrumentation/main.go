@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Command kubectl-cloudwatch-instrumentation prints the instrumentation status of the workloads of a cluster. Installed
+// as kubectl-cloudwatch_instrumentation in the PATH, it is run as "kubectl cloudwatch-instrumentation".
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/instrumentationstatus"
+)
+
+func main() {
+	var (
+		namespace     string
+		allNamespaces bool
+		output        string
+	)
+	pflag.StringVarP(&namespace, "namespace", "n", "", "The namespace of the workloads. Defaults to the namespace of the current context.")
+	pflag.BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Report the workloads of all the namespaces.")
+	pflag.StringVarP(&output, "output", "o", instrumentationstatus.OutputTable, "The output format: wide or json. Defaults to a table.")
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	if err := run(namespace, allNamespaces, output); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(namespace string, allNamespaces bool, output string) error {
+	if allNamespaces {
+		namespace = ""
+	} else if namespace == "" {
+		// the namespace of the current context, as for kubectl
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		if kubeconfig := flag.Lookup("kubeconfig"); kubeconfig != nil {
+			rules.ExplicitPath = kubeconfig.Value.String()
+		}
+		current, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).Namespace()
+		if err != nil {
+			return err
+		}
+		namespace = current
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	workloads, err := instrumentationstatus.Collect(context.Background(), c, namespace)
+	if err != nil {
+		return err
+	}
+	return instrumentationstatus.Print(os.Stdout, workloads, output)
+}
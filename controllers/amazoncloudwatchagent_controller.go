@@ -10,19 +10,35 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/cleanup"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	collectorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 )
 
 // AmazonCloudWatchAgentReconciler reconciles a AmazonCloudWatchAgent object.
@@ -32,6 +48,10 @@ type AmazonCloudWatchAgentReconciler struct {
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+	sweeper  *cleanup.Sweeper
+
+	maxConcurrentReconciles int
+	rateLimiter             workqueue.TypedRateLimiter[reconcile.Request]
 }
 
 // Params is the set of options to build a new AmazonCloudWatchAgentReconciler.
@@ -41,6 +61,15 @@ type Params struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Config   config.Config
+
+	// MaxConcurrentReconciles is the number of instances reconciled concurrently, 1 when it is not set.
+	MaxConcurrentReconciles int
+	// RateLimiter delays the reconciles of the instances, the default rate limiter of controller-runtime when it is
+	// not set. Each controller needs its own rate limiter.
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	// Sweeper deletes the resources of the deleted AmazonCloudWatchAgents, which are then held by a finalizer until
+	// their resources are deleted. The finalizer is not added when it is not set.
+	Sweeper *cleanup.Sweeper
 }
 
 func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
@@ -58,6 +87,7 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 	deploymentList := &appsv1.DeploymentList{}
 	statefulSetList := &appsv1.StatefulSetList{}
 	daemonSetList := &appsv1.DaemonSetList{}
+	podDisruptionBudgetList := &policyV1.PodDisruptionBudgetList{}
 	var err error
 
 	// List ConfigMaps
@@ -113,6 +143,81 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		ownedObjects[daemonSetList.Items[i].GetUID()] = &daemonSetList.Items[i]
 	}
 
+	// List PodDisruptionBudgets
+	err = r.List(ctx, podDisruptionBudgetList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range podDisruptionBudgetList.Items {
+		ownedObjects[podDisruptionBudgetList.Items[i].GetUID()] = &podDisruptionBudgetList.Items[i]
+	}
+
+	if r.config.NamespaceScoped() {
+		// List Roles and RoleBindings, which replace the cluster ones when the operator is namespace-scoped
+		roleList := &rbacv1.RoleList{}
+		err = r.List(ctx, roleList, listOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range roleList.Items {
+			ownedObjects[roleList.Items[i].GetUID()] = &roleList.Items[i]
+		}
+		roleBindingList := &rbacv1.RoleBindingList{}
+		err = r.List(ctx, roleBindingList, listOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range roleBindingList.Items {
+			ownedObjects[roleBindingList.Items[i].GetUID()] = &roleBindingList.Items[i]
+		}
+	} else {
+		// List ClusterRoles and ClusterRoleBindings, which are not namespaced
+		clusterListOps := &client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(selector),
+		}
+		clusterRoleList := &rbacv1.ClusterRoleList{}
+		err = r.List(ctx, clusterRoleList, clusterListOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range clusterRoleList.Items {
+			ownedObjects[clusterRoleList.Items[i].GetUID()] = &clusterRoleList.Items[i]
+		}
+		clusterRoleBindingList := &rbacv1.ClusterRoleBindingList{}
+		err = r.List(ctx, clusterRoleBindingList, clusterListOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range clusterRoleBindingList.Items {
+			ownedObjects[clusterRoleBindingList.Items[i].GetUID()] = &clusterRoleBindingList.Items[i]
+		}
+	}
+
+	// List PodIdentityAssociations, whose kind only exists when the EKS controller of AWS Controllers for Kubernetes
+	// is installed
+	podIdentityAssociationList := &unstructured.UnstructuredList{}
+	podIdentityAssociationList.SetGroupVersionKind(collector.PodIdentityAssociationGVK.GroupVersion().WithKind(collector.PodIdentityAssociationGVK.Kind + "List"))
+	err = r.List(ctx, podIdentityAssociationList, listOps)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for i := range podIdentityAssociationList.Items {
+		ownedObjects[podIdentityAssociationList.Items[i].GetUID()] = &podIdentityAssociationList.Items[i]
+	}
+
+	// List the Gateway API routes, whose kinds only exist when the Gateway API is installed
+	for _, gvk := range []schema.GroupVersionKind{collector.HTTPRouteGVK, collector.GRPCRouteGVK} {
+		routeList := &unstructured.UnstructuredList{}
+		routeList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err = r.List(ctx, routeList, listOps)
+		if err != nil && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+		for i := range routeList.Items {
+			ownedObjects[routeList.Items[i].GetUID()] = &routeList.Items[i]
+		}
+	}
+
 	return ownedObjects, nil
 
 }
@@ -135,6 +240,10 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+		sweeper:  p.Sweeper,
+
+		maxConcurrentReconciles: p.MaxConcurrentReconciles,
+		rateLimiter:             p.RateLimiter,
 	}
 	return r
 }
@@ -148,6 +257,8 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=eks.services.k8s.aws,resources=podidentityassociations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;grpcroutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/finalizers,verbs=get;update;patch
@@ -169,7 +280,7 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 	}
 	// We have a deletion, short circuit and let the deletion happen
 	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.finalize(ctx, log, &instance)
 	}
 
 	if instance.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged {
@@ -178,27 +289,188 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, nil
 	}
 
-	params := r.getParams(instance)
+	if r.sweeper != nil && controllerutil.AddFinalizer(&instance, cleanup.Finalizer) {
+		if err := r.Update(ctx, &instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	resolved, resolveErr := collector.ResolveConfigSources(ctx, r.Client, instance)
+	if resolveErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, r.getParams(instance), &collectorStatus.InvalidConfigError{Err: resolveErr})
+	}
+	params := r.getParams(resolved)
+	// the nodes are cluster-scoped, so a namespace-scoped operator cannot discover the EFA nodes
+	if !r.config.NamespaceScoped() {
+		efaNodes, err := collector.EFANodes(ctx, r.Client, resolved)
+		if err != nil {
+			return collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+		}
+		params.EFANodes = efaNodes
+	}
+	// the DcgmExporter and NeuronMonitor resources of the other namespaces are not visible to a namespace-scoped operator
+	exportersNamespace := ""
+	if r.config.NamespaceScoped() {
+		exportersNamespace = resolved.Namespace
+	}
+	dcgmExporter, dcgmErr := collector.ExistingDcgmExporter(ctx, r.Client, resolved, exportersNamespace)
+	if dcgmErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, dcgmErr)
+	}
+	if dcgmExporter != nil {
+		r.recorder.Eventf(&instance, corev1.EventTypeNormal, "DcgmExporterFound",
+			"Not deploying the DCGM exporter of the GPU metrics, scraping the one of DcgmExporter %s/%s", dcgmExporter.Namespace, dcgmExporter.Name)
+	}
+	params.DcgmExporter = dcgmExporter
+	neuronMonitorDeployed, neuronErr := collector.NeuronMonitorDeployed(ctx, r.Client, resolved, exportersNamespace)
+	if neuronErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, neuronErr)
+	}
+	if neuronMonitorDeployed {
+		log.V(1).Info("Not deploying the Neuron monitor of the Neuron metrics, a NeuronMonitor deploys it already")
+	}
+	params.NeuronMonitorDeployed = neuronMonitorDeployed
 
 	desiredObjects, buildErr := BuildCollector(params)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, &collectorStatus.InvalidConfigError{Err: buildErr})
+	}
+	// the workloads are left as they are when one of their images doesn't verify, and deploy the verified digests
+	if verifyErr := pinVerifiedImages(ctx, params.Config, desiredObjects); verifyErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, verifyErr)
 	}
 
 	err := reconcileDesiredObjectsWPrune(ctx, r.Client, log, params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
 	return collectorStatus.HandleReconcileStatus(ctx, log, params, err)
 }
 
+// finalize deletes the resources of the deleted instance before removing its finalizer. The resources of an unmanaged
+// instance are left to the garbage collector.
+func (r *AmazonCloudWatchAgentReconciler) finalize(ctx context.Context, log logr.Logger, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	if !controllerutil.ContainsFinalizer(instance, cleanup.Finalizer) {
+		return nil
+	}
+	if r.sweeper != nil && instance.Spec.ManagementState != v1alpha1.ManagementStateUnmanaged {
+		if err := r.sweeper.DeleteOwned(ctx, instance); err != nil {
+			log.Error(err, "failed to delete the resources of the AmazonCloudWatchAgent")
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(instance, cleanup.Finalizer)
+	return client.IgnoreNotFound(r.Update(ctx, instance))
+}
+
 // SetupWithManager tells the manager what our controller is interested in.
 func (r *AmazonCloudWatchAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.maxConcurrentReconciles, RateLimiter: r.rateLimiter}).
 		For(&v1alpha1.AmazonCloudWatchAgent{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
-		Owns(&appsv1.StatefulSet{})
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyV1.PodDisruptionBudget{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.findAgentsForConfigSource)).
+		Watches(&v1alpha1.DcgmExporter{}, handler.EnqueueRequestsFromMapFunc(r.findAgentsCollectingGPUMetrics)).
+		Watches(&v1alpha1.NeuronMonitor{}, handler.EnqueueRequestsFromMapFunc(r.findAgentsCollectingNeuronMetrics))
+	if !r.config.NamespaceScoped() {
+		builder = builder.Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.findAgentsCollectingEFAMetrics),
+			ctrlbuilder.WithPredicates(efaDevicesChanged))
+	}
+
+	return builder.Complete(telemetry.TracedReconciler("AmazonCloudWatchAgent", r))
+}
+
+// findAgentsForConfigSource maps a ConfigMap to the agents in its namespace that list it in their config
+// sources, so that edits to the ConfigMap are merged into the agent configuration.
+func (r *AmazonCloudWatchAgentReconciler) findAgentsForConfigSource(ctx context.Context, obj client.Object) []reconcile.Request {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "failed to list AmazonCloudWatchAgents referencing ConfigMap", "configmap", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		for _, source := range agent.Spec.ConfigSources {
+			if source.ConfigMap != nil && source.ConfigMap.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
 
-	return builder.Complete(r)
+// findAgentsCollectingGPUMetrics maps a DcgmExporter to the agents collecting the GPU metrics, so that they scrape it
+// instead of deploying their own DCGM exporter while the DcgmExporter exists. A namespace-scoped operator only looks
+// the DcgmExporter up in the namespace of the agent.
+func (r *AmazonCloudWatchAgentReconciler) findAgentsCollectingGPUMetrics(ctx context.Context, obj client.Object) []reconcile.Request {
+	var opts []client.ListOption
+	if r.config.NamespaceScoped() {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents, opts...); err != nil {
+		r.log.Error(err, "failed to list AmazonCloudWatchAgents collecting GPU metrics", "dcgmexporter", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if agent.Spec.GPUMetrics != nil && agent.Spec.GPUMetrics.Enabled {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}})
+		}
+	}
+	return requests
+}
+
+// findAgentsCollectingNeuronMetrics maps a NeuronMonitor to the agents collecting the Neuron metrics, so that they stop
+// deploying their own Neuron monitor while the NeuronMonitor exists.
+func (r *AmazonCloudWatchAgentReconciler) findAgentsCollectingNeuronMetrics(ctx context.Context, obj client.Object) []reconcile.Request {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents); err != nil {
+		r.log.Error(err, "failed to list AmazonCloudWatchAgents collecting Neuron metrics", "neuronmonitor", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if agent.Spec.NeuronMetrics != nil && agent.Spec.NeuronMetrics.Enabled {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}})
+		}
+	}
+	return requests
+}
+
+// efaDevicesChanged filters the node events down to the ones changing the set of nodes exposing EFA devices.
+var efaDevicesChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return collector.HasEFADevices(e.Object.(*corev1.Node))
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return collector.HasEFADevices(e.ObjectOld.(*corev1.Node)) != collector.HasEFADevices(e.ObjectNew.(*corev1.Node))
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return collector.HasEFADevices(e.Object.(*corev1.Node))
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+}
+
+// findAgentsCollectingEFAMetrics maps a node to the agents collecting the EFA metrics, so that their EFA DaemonSet
+// follows the nodes on which the EFA device plugin advertises devices.
+func (r *AmazonCloudWatchAgentReconciler) findAgentsCollectingEFAMetrics(ctx context.Context, obj client.Object) []reconcile.Request {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents); err != nil {
+		r.log.Error(err, "failed to list AmazonCloudWatchAgents collecting EFA metrics", "node", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if agent.Spec.EFAMetrics != nil && agent.Spec.EFAMetrics.Enabled {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}})
+		}
+	}
+	return requests
 }
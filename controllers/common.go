@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -20,9 +21,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator"
 )
 
@@ -55,8 +58,27 @@ func BuildCollector(params manifests.Params) ([]client.Object, error) {
 		}
 		resources = append(resources, objs...)
 	}
-	return resources, nil
+	return manifestutils.WithImagePullSecrets(resources, params.Config.ImagePullSecrets()), nil
 }
+
+// pinVerifiedImages verifies the images of the pod templates of the objects, reading their registries with the image
+// pull secrets of the pods, and replaces them by the digests which verified. The objects are left as they are when
+// one of the images doesn't verify.
+func pinVerifiedImages(ctx context.Context, cfg config.Config, objects []client.Object) error {
+	pinned := map[string]string{}
+	for _, obj := range objects {
+		for _, podSpec := range manifestutils.PodSpecs([]client.Object{obj}) {
+			verified, err := cfg.VerifyImages(ctx, manifestutils.PodImages(*podSpec), config.NewImagePull(obj.GetNamespace(), *podSpec))
+			if err != nil {
+				return err
+			}
+			maps.Copy(pinned, verified)
+		}
+	}
+	manifestutils.PinImages(manifestutils.PodSpecs(objects), pinned)
+	return nil
+}
+
 func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, logger logr.Logger,
 	owner metav1.Object, scheme *runtime.Scheme, desiredObjects ...client.Object) (map[types.UID]client.Object, error) {
 	var errs []error
@@ -191,5 +213,9 @@ var getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client
 		Name:      amazonCloudWatchAgentName,
 	}, cr)
 
+	// the merged configuration is what the agent runs with, fall back to the plain config if it cannot be built
+	if resolved, err := collector.ResolveConfigSources(ctx, c, *cr); err == nil {
+		return resolved
+	}
 	return *cr
 }
@@ -5,14 +5,20 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 )
 
 func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
@@ -73,3 +79,38 @@ func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 		assert.Equal(t, tc.expected, actual)
 	}
 }
+
+// pullSecretVerifier verifies the images read with the image pull secret of the namespace.
+type pullSecretVerifier map[string]string
+
+func (v pullSecretVerifier) Verify(_ context.Context, image string, pull config.ImagePull) (string, error) {
+	if !slices.Contains(pull.ImagePullSecrets, v[pull.Namespace]) {
+		return "", fmt.Errorf("registry of %s refused the credentials", image)
+	}
+	return image + "@sha256:signed", nil
+}
+
+func TestPinVerifiedImages(t *testing.T) {
+	newDaemonSet := func(namespace, image, pullSecret string) *appsv1.DaemonSet {
+		daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}}
+		daemonSet.Spec.Template.Spec = corev1.PodSpec{
+			Containers:       []corev1.Container{{Name: "agent", Image: image}},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: pullSecret}},
+		}
+		return daemonSet
+	}
+	cfg := config.New(config.WithImageVerifier(pullSecretVerifier{"amazon-cloudwatch": "registry", "app": "app-registry"}))
+
+	// the images of every object are read with the image pull secrets of its pods in its namespace
+	agent := newDaemonSet("amazon-cloudwatch", "cloudwatch-agent:1.0", "registry")
+	sidecar := newDaemonSet("app", "fluent-bit:1.0", "app-registry")
+	require.NoError(t, pinVerifiedImages(context.Background(), cfg, []client.Object{agent, sidecar, &corev1.ServiceAccount{}}))
+	assert.Equal(t, "cloudwatch-agent:1.0@sha256:signed", agent.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "fluent-bit:1.0@sha256:signed", sidecar.Spec.Template.Spec.Containers[0].Image)
+
+	// the objects are left as they are when one of the images doesn't verify
+	agent = newDaemonSet("amazon-cloudwatch", "cloudwatch-agent:1.0", "registry")
+	sidecar = newDaemonSet("app", "fluent-bit:1.0", "registry")
+	assert.ErrorContains(t, pinVerifiedImages(context.Background(), cfg, []client.Object{agent, sidecar}), "fluent-bit:1.0")
+	assert.Equal(t, "cloudwatch-agent:1.0", agent.Spec.Template.Spec.Containers[0].Image)
+}
@@ -14,14 +14,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	dcgmexporterStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 )
 
 // DcgmExporterReconciler reconciles a DcgmExporter object.
@@ -31,6 +36,9 @@ type DcgmExporterReconciler struct {
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+
+	maxConcurrentReconciles int
+	rateLimiter             workqueue.TypedRateLimiter[reconcile.Request]
 }
 
 func (r *DcgmExporterReconciler) getParams(instance v1alpha1.DcgmExporter) manifests.Params {
@@ -52,6 +60,9 @@ func NewDcgmExporterReconciler(p Params) *DcgmExporterReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+
+		maxConcurrentReconciles: p.MaxConcurrentReconciles,
+		rateLimiter:             p.RateLimiter,
 	}
 	return r
 }
@@ -94,6 +105,10 @@ func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if buildErr != nil {
 		return ctrl.Result{}, buildErr
 	}
+	if verifyErr := pinVerifiedImages(ctx, params.Config, desiredObjects); verifyErr != nil {
+		log.Error(verifyErr, "refusing to deploy an image whose signature doesn't verify")
+		return ctrl.Result{}, verifyErr
+	}
 
 	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log) {
 		log.Info("enhanced_container_insights or accelerated_compute_metrics is disabled")
@@ -123,12 +138,13 @@ func BuildDcgmExporter(params manifests.Params) ([]client.Object, error) {
 		}
 		resources = append(resources, objs...)
 	}
-	return resources, nil
+	return manifestutils.WithImagePullSecrets(resources, params.Config.ImagePullSecrets()), nil
 }
 
 // SetupWithManager tells the manager what our controller is interested in.
 func (r *DcgmExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.maxConcurrentReconciles, RateLimiter: r.rateLimiter}).
 		For(&v1alpha1.DcgmExporter{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -136,5 +152,5 @@ func (r *DcgmExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{})
 
-	return builder.Complete(r)
+	return builder.Complete(telemetry.TracedReconciler("DcgmExporter", r))
 }
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
+)
+
+// managedObjectsListTimeout bounds the listing of the managed instances on each scrape.
+const managedObjectsListTimeout = 10 * time.Second
+
+var (
+	managedAgentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetry.Namespace, "", "managed_agents"),
+		"Number of AmazonCloudWatchAgent instances managed by the operator, by mode.",
+		[]string{"mode"}, nil,
+	)
+	managedInstrumentationsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetry.Namespace, "", "managed_instrumentations"),
+		"Number of Instrumentation instances known to the operator.",
+		nil, nil,
+	)
+)
+
+// managedObjectsCollector counts the instances managed by the operator when the metrics are scraped, so that the
+// counts never drift from the cluster.
+type managedObjectsCollector struct {
+	client client.Reader
+	log    logr.Logger
+}
+
+// NewManagedObjectsCollector returns the collector of the number of AmazonCloudWatchAgent and Instrumentation
+// instances. The instances are listed through the given reader, usually the cached client of the manager.
+func NewManagedObjectsCollector(c client.Reader, log logr.Logger) prometheus.Collector {
+	return &managedObjectsCollector{client: c, log: log}
+}
+
+func (m *managedObjectsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedAgentsDesc
+	ch <- managedInstrumentationsDesc
+}
+
+func (m *managedObjectsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), managedObjectsListTimeout)
+	defer cancel()
+
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	if err := m.client.List(ctx, agents); err != nil {
+		m.log.Error(err, "failed to list the AmazonCloudWatchAgent instances")
+	} else {
+		perMode := map[v1alpha1.Mode]int{}
+		for _, agent := range agents.Items {
+			if agent.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged {
+				continue
+			}
+			perMode[agent.Spec.Mode]++
+		}
+		for mode, count := range perMode {
+			ch <- prometheus.MustNewConstMetric(managedAgentsDesc, prometheus.GaugeValue, float64(count), string(mode))
+		}
+	}
+
+	instrumentations := &v1alpha1.InstrumentationList{}
+	if err := m.client.List(ctx, instrumentations); err != nil {
+		m.log.Error(err, "failed to list the Instrumentation instances")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(managedInstrumentationsDesc, prometheus.GaugeValue, float64(len(instrumentations.Items)))
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestManagedObjectsCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	agent := func(name string, mode v1alpha1.Mode, state v1alpha1.ManagementStateType) *v1alpha1.AmazonCloudWatchAgent {
+		return &v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: mode, ManagementState: state},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		agent("a", v1alpha1.ModeDaemonSet, v1alpha1.ManagementStateManaged),
+		agent("b", v1alpha1.ModeDaemonSet, v1alpha1.ManagementStateManaged),
+		agent("c", v1alpha1.ModeDeployment, v1alpha1.ManagementStateManaged),
+		agent("d", v1alpha1.ModeDeployment, v1alpha1.ManagementStateUnmanaged),
+		&v1alpha1.Instrumentation{ObjectMeta: metav1.ObjectMeta{Name: "java", Namespace: "default"}},
+	).Build()
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(NewManagedObjectsCollector(c, logr.Discard())))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range m.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			values[name] = m.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"cloudwatch_agent_operator_managed_agents/daemonset":  2,
+		"cloudwatch_agent_operator_managed_agents/deployment": 1,
+		"cloudwatch_agent_operator_managed_instrumentations":  1,
+	}, values)
+}
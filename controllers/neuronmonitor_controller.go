@@ -14,14 +14,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/neuronmonitor"
 	neuronmonitorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/neuronmonitor"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 )
 
 // NeuronMonitorReconciler reconciles a NeuronMonitor object.
@@ -31,6 +36,9 @@ type NeuronMonitorReconciler struct {
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+
+	maxConcurrentReconciles int
+	rateLimiter             workqueue.TypedRateLimiter[reconcile.Request]
 }
 
 func (r *NeuronMonitorReconciler) getParams(instance v1alpha1.NeuronMonitor) manifests.Params {
@@ -52,6 +60,9 @@ func NewNeuronMonitorReconciler(p Params) *NeuronMonitorReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+
+		maxConcurrentReconciles: p.MaxConcurrentReconciles,
+		rateLimiter:             p.RateLimiter,
 	}
 	return r
 }
@@ -94,6 +105,10 @@ func (r *NeuronMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if buildErr != nil {
 		return ctrl.Result{}, buildErr
 	}
+	if verifyErr := pinVerifiedImages(ctx, params.Config, desiredObjects); verifyErr != nil {
+		log.Error(verifyErr, "refusing to deploy an image whose signature doesn't verify")
+		return ctrl.Result{}, verifyErr
+	}
 
 	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log) {
 		log.Info("enhanced_container_insights or accelerated_compute_metrics is disabled")
@@ -122,12 +137,13 @@ func BuildNeuronMonitor(params manifests.Params) ([]client.Object, error) {
 		}
 		resources = append(resources, objs...)
 	}
-	return resources, nil
+	return manifestutils.WithImagePullSecrets(resources, params.Config.ImagePullSecrets()), nil
 }
 
 // SetupWithManager tells the manager what our controller is interested in.
 func (r *NeuronMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.maxConcurrentReconciles, RateLimiter: r.rateLimiter}).
 		For(&v1alpha1.NeuronMonitor{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -135,5 +151,5 @@ func (r *NeuronMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{})
 
-	return builder.Complete(r)
+	return builder.Complete(telemetry.TracedReconciler("NeuronMonitor", r))
 }
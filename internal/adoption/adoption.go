@@ -0,0 +1,284 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package adoption migrates the resources of the OpenTelemetry operator to the ones of the operator: each
+// opentelemetry.io Instrumentation and OpenTelemetryCollector is converted to a cloudwatch.aws.amazon.com
+// Instrumentation and AmazonCloudWatchAgent of the same name and namespace. The inject annotations of the workloads are
+// shared by both operators, so the workloads keep referring to the same instrumentations and sidecars.
+package adoption
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	// AdoptedFromAnnotation is the kind of the opentelemetry.io resource an adopted resource was converted from.
+	AdoptedFromAnnotation = "cloudwatch.aws.amazon.com/adopted-from"
+	// adoptedGenerationAnnotation is the generation of the opentelemetry.io resource which was converted last.
+	adoptedGenerationAnnotation = "cloudwatch.aws.amazon.com/adopted-generation"
+
+	// DefaultInterval is the interval between two adoptions.
+	DefaultInterval = time.Minute
+
+	// upstreamImagePrefix is the prefix of the auto-instrumentation images defaulted by the OpenTelemetry operator,
+	// which are dropped so that the images of the operator are defaulted instead.
+	upstreamImagePrefix = "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-"
+)
+
+var (
+	instrumentationGVK = schema.GroupVersionKind{Group: "opentelemetry.io", Version: "v1alpha1", Kind: "Instrumentation"}
+	// collectorGVKs are the versions of the OpenTelemetryCollector, by order of preference.
+	collectorGVKs = []schema.GroupVersionKind{
+		{Group: "opentelemetry.io", Version: "v1beta1", Kind: "OpenTelemetryCollector"},
+		{Group: "opentelemetry.io", Version: "v1alpha1", Kind: "OpenTelemetryCollector"},
+	}
+
+	// ignoredAnnotations are the annotations of the opentelemetry.io resources which are not copied.
+	ignoredAnnotations = []string{"kubectl.kubernetes.io/last-applied-configuration"}
+
+	errNoCollectorVersion = errors.New("no served version of the OpenTelemetryCollector")
+)
+
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=instrumentations;opentelemetrycollectors,verbs=get;list;watch
+
+var _ manager.LeaderElectionRunnable = (*Adopter)(nil)
+
+// Adopter converts the opentelemetry.io resources at every interval. The converted resources are created when
+// missing, and updated when the opentelemetry.io resource they were adopted from changes. The resources of the operator
+// which were not adopted are never changed.
+type Adopter struct {
+	reader     client.Reader
+	client     client.Client
+	namespaces []string
+	interval   time.Duration
+	log        logr.Logger
+}
+
+// New returns the adopter of the opentelemetry.io resources of the namespaces, or of all the namespaces when there
+// are none. The opentelemetry.io resources are read with the reader, so that the client doesn't cache them.
+func New(reader client.Reader, c client.Client, namespaces []string, interval time.Duration, log logr.Logger) *Adopter {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	return &Adopter{reader: reader, client: c, namespaces: namespaces, interval: interval, log: log}
+}
+
+// NeedLeaderElection is true, as the adopted resources are only written by the leader.
+func (a *Adopter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start adopts the opentelemetry.io resources at every interval until the context is done.
+func (a *Adopter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if err := a.Adopt(ctx); err != nil {
+			a.log.Error(err, "failed to adopt the OpenTelemetry operator resources")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Adopt converts all the opentelemetry.io resources. The kinds whose CustomResourceDefinition is not installed are
+// skipped.
+func (a *Adopter) Adopt(ctx context.Context) error {
+	var errs []error
+	instrumentations, err := a.list(ctx, instrumentationGVK)
+	if err != nil && !meta.IsNoMatchError(err) {
+		errs = append(errs, err)
+	}
+	for i := range instrumentations {
+		if err := a.adoptInstrumentation(ctx, &instrumentations[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	collectors, err := a.listCollectors(ctx)
+	if err != nil && !errors.Is(err, errNoCollectorVersion) {
+		errs = append(errs, err)
+	}
+	for i := range collectors {
+		if err := a.adoptCollector(ctx, &collectors[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adopter) list(ctx context.Context, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	for _, namespace := range a.namespaces {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := a.reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+	}
+	return items, nil
+}
+
+// listCollectors lists the OpenTelemetryCollectors with the most recent version served by the API server.
+func (a *Adopter) listCollectors(ctx context.Context) ([]unstructured.Unstructured, error) {
+	for _, gvk := range collectorGVKs {
+		items, err := a.list(ctx, gvk)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		return items, err
+	}
+	return nil, errNoCollectorVersion
+}
+
+func (a *Adopter) adoptInstrumentation(ctx context.Context, src *unstructured.Unstructured) error {
+	inst := &v1alpha1.Instrumentation{}
+	return a.adopt(ctx, src, inst, func() error {
+		spec, err := ConvertInstrumentationSpec(src.Object["spec"])
+		inst.Spec = spec
+		return err
+	})
+}
+
+func (a *Adopter) adoptCollector(ctx context.Context, src *unstructured.Unstructured) error {
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	return a.adopt(ctx, src, agent, func() error {
+		spec, err := ConvertCollectorSpec(src.Object["spec"])
+		agent.Spec = spec
+		return err
+	})
+}
+
+// adopt creates or updates the resource whose spec is converted from the opentelemetry.io resource by toSpec.
+func (a *Adopter) adopt(ctx context.Context, src *unstructured.Unstructured, obj client.Object, toSpec func() error) error {
+	key := types.NamespacedName{Namespace: src.GetNamespace(), Name: src.GetName()}
+	adoptedFrom := src.GroupVersionKind().GroupKind().String()
+	generation := strconv.FormatInt(src.GetGeneration(), 10)
+
+	err := a.client.Get(ctx, key, obj)
+	create := apierrors.IsNotFound(err)
+	if err != nil && !create {
+		return err
+	}
+	if !create {
+		annotations := obj.GetAnnotations()
+		if annotations[AdoptedFromAnnotation] != adoptedFrom {
+			a.log.V(1).Info("skipping the adoption, the resource already exists", "kind", adoptedFrom, "resource", key.String())
+			return nil
+		}
+		if annotations[adoptedGenerationAnnotation] == generation {
+			return nil
+		}
+	}
+
+	if err := toSpec(); err != nil {
+		return fmt.Errorf("failed to adopt the %s %s: %w", adoptedFrom, key, err)
+	}
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	obj.SetLabels(src.GetLabels())
+	annotations := maps.Clone(src.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for _, k := range ignoredAnnotations {
+		delete(annotations, k)
+	}
+	annotations[AdoptedFromAnnotation] = adoptedFrom
+	annotations[adoptedGenerationAnnotation] = generation
+	obj.SetAnnotations(annotations)
+
+	if create {
+		a.log.Info("adopting the OpenTelemetry operator resource", "kind", adoptedFrom, "resource", key.String())
+		return a.client.Create(ctx, obj)
+	}
+	a.log.Info("updating the adopted OpenTelemetry operator resource", "kind", adoptedFrom, "resource", key.String())
+	return a.client.Update(ctx, obj)
+}
+
+// ConvertInstrumentationSpec converts the spec of an opentelemetry.io Instrumentation. The auto-instrumentation images
+// of the OpenTelemetry operator are dropped, so that the images of the operator are defaulted instead.
+func ConvertInstrumentationSpec(src interface{}) (v1alpha1.InstrumentationSpec, error) {
+	spec := v1alpha1.InstrumentationSpec{}
+	if err := convert(src, &spec); err != nil {
+		return spec, err
+	}
+	for _, image := range []*string{
+		&spec.Java.Image, &spec.NodeJS.Image, &spec.Python.Image, &spec.DotNet.Image, &spec.Go.Image,
+		&spec.ApacheHttpd.Image, &spec.Nginx.Image,
+	} {
+		if strings.HasPrefix(*image, upstreamImagePrefix) {
+			*image = ""
+		}
+	}
+	return spec, nil
+}
+
+// ConvertCollectorSpec converts the spec of an opentelemetry.io OpenTelemetryCollector to the spec of an agent running
+// its OpenTelemetry configuration. The image of the collector is dropped, so that the agent image is defaulted.
+func ConvertCollectorSpec(src interface{}) (v1alpha1.AmazonCloudWatchAgentSpec, error) {
+	spec := v1alpha1.AmazonCloudWatchAgentSpec{}
+	// the fields are copied, so that deleting some of them doesn't change the listed resource
+	fields, _ := src.(map[string]interface{})
+	fields = maps.Clone(fields)
+	otelConfig := fields["config"]
+	for _, field := range []string{"config", "image", "upgradeStrategy"} {
+		delete(fields, field)
+	}
+	if err := convert(fields, &spec); err != nil {
+		return spec, err
+	}
+
+	switch config := otelConfig.(type) {
+	case nil:
+	case string:
+		// the v1alpha1 configuration is a YAML string
+		spec.OtelConfig = config
+	default:
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return spec, fmt.Errorf("invalid config: %w", err)
+		}
+		spec.OtelConfig = string(data)
+	}
+	return spec, nil
+}
+
+// convert copies the fields of the spec which exist in the spec of the operator through their JSON encoding.
+func convert(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("incompatible spec: %w", err)
+	}
+	return nil
+}
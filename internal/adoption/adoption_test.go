@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adoption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func newUnstructured(gvk schema.GroupVersionKind, name string, generation int64, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetGeneration(generation)
+	return obj
+}
+
+// newTestClient returns a client serving the opentelemetry.io kinds of the versions.
+func newTestClient(t *testing.T, gvks []schema.GroupVersionKind, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(v1alpha1.GroupVersion.WithKind("Instrumentation"), meta.RESTScopeNamespace)
+	mapper.Add(v1alpha1.GroupVersion.WithKind("AmazonCloudWatchAgent"), meta.RESTScopeNamespace)
+	for _, gvk := range gvks {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build()
+}
+
+func TestAdopt(t *testing.T) {
+	ctx := context.Background()
+	inst := newUnstructured(instrumentationGVK, "java", 1, map[string]interface{}{
+		"exporter": map[string]interface{}{"endpoint": "http://otel-collector:4317"},
+		"java": map[string]interface{}{
+			"image": "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-java:2.0.0",
+			"env":   []interface{}{map[string]interface{}{"name": "OTEL_LOGS_EXPORTER", "value": "none"}},
+		},
+		"python": map[string]interface{}{"image": "registry.example.com/python:1"},
+	})
+	inst.SetAnnotations(map[string]string{
+		"team": "payments",
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+	})
+	collector := newUnstructured(collectorGVKs[0], "otel", 2, map[string]interface{}{
+		"mode":     "daemonset",
+		"image":    "otel/opentelemetry-collector-contrib:0.100.0",
+		"replicas": int64(1),
+		"config": map[string]interface{}{
+			"receivers": map[string]interface{}{"otlp": map[string]interface{}{"protocols": map[string]interface{}{"grpc": map[string]interface{}{}}}},
+		},
+		"unknownField": true,
+	})
+	c := newTestClient(t, []schema.GroupVersionKind{instrumentationGVK, collectorGVKs[0]}, inst, collector)
+	a := New(c, c, nil, 0, logr.Discard())
+	require.NoError(t, a.Adopt(ctx))
+
+	adoptedInst := &v1alpha1.Instrumentation{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "java"}, adoptedInst))
+	assert.Equal(t, "http://otel-collector:4317", adoptedInst.Spec.Exporter.Endpoint)
+	assert.Empty(t, adoptedInst.Spec.Java.Image)
+	assert.Equal(t, "OTEL_LOGS_EXPORTER", adoptedInst.Spec.Java.Env[0].Name)
+	assert.Equal(t, "registry.example.com/python:1", adoptedInst.Spec.Python.Image)
+	assert.Equal(t, map[string]string{
+		"team":                      "payments",
+		AdoptedFromAnnotation:       "Instrumentation.opentelemetry.io",
+		adoptedGenerationAnnotation: "1",
+	}, adoptedInst.Annotations)
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "otel"}, agent))
+	assert.Equal(t, v1alpha1.ModeDaemonSet, agent.Spec.Mode)
+	assert.Empty(t, agent.Spec.Image)
+	assert.Empty(t, agent.Spec.Config)
+	assert.YAMLEq(t, "receivers:\n  otlp:\n    protocols:\n      grpc: {}\n", agent.Spec.OtelConfig)
+	assert.Equal(t, "OpenTelemetryCollector.opentelemetry.io", agent.Annotations[AdoptedFromAnnotation])
+
+	// the adopted resources follow the changes of the opentelemetry.io resources
+	require.NoError(t, unstructured.SetNestedField(inst.Object, "http://otel-collector:4318", "spec", "exporter", "endpoint"))
+	inst.SetGeneration(2)
+	require.NoError(t, c.Update(ctx, inst))
+	require.NoError(t, a.Adopt(ctx))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "java"}, adoptedInst))
+	assert.Equal(t, "http://otel-collector:4318", adoptedInst.Spec.Exporter.Endpoint)
+	assert.Equal(t, "2", adoptedInst.Annotations[adoptedGenerationAnnotation])
+}
+
+func TestAdoptSkipsExistingResources(t *testing.T) {
+	ctx := context.Background()
+	existing := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "java"},
+		Spec:       v1alpha1.InstrumentationSpec{Exporter: v1alpha1.Exporter{Endpoint: "http://cloudwatch-agent.amazon-cloudwatch:4316"}},
+	}
+	inst := newUnstructured(instrumentationGVK, "java", 1, map[string]interface{}{
+		"exporter": map[string]interface{}{"endpoint": "http://otel-collector:4317"},
+	})
+	c := newTestClient(t, []schema.GroupVersionKind{instrumentationGVK}, existing, inst)
+	require.NoError(t, New(c, c, nil, 0, logr.Discard()).Adopt(ctx))
+
+	actual := &v1alpha1.Instrumentation{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "java"}, actual))
+	assert.Equal(t, existing.Spec, actual.Spec)
+	assert.NotContains(t, actual.Annotations, AdoptedFromAnnotation)
+}
+
+func TestAdoptWithoutOpenTelemetryOperator(t *testing.T) {
+	c := newTestClient(t, nil)
+	assert.NoError(t, New(c, c, []string{"team-a", "team-b"}, 0, logr.Discard()).Adopt(context.Background()))
+}
+
+func TestConvertCollectorSpec(t *testing.T) {
+	spec, err := ConvertCollectorSpec(map[string]interface{}{
+		"mode":   "sidecar",
+		"config": "receivers:\n  otlp: {}\n",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.ModeSidecar, spec.Mode)
+	assert.Equal(t, "receivers:\n  otlp: {}\n", spec.OtelConfig)
+
+	_, err = ConvertCollectorSpec(map[string]interface{}{"replicas": "two"})
+	assert.Error(t, err)
+}
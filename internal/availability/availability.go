@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package availability keeps the webhooks of the operator available by managing the replicas, the pod anti-affinity
+// and the PodDisruptionBudget of its own deployment.
+package availability
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DefaultReplicas is the number of replicas of the operator, so that the webhooks survive the loss of one of them.
+	DefaultReplicas = 2
+
+	hostnameTopologyKey = "kubernetes.io/hostname"
+)
+
+// Options are the availability settings of the deployment of the operator.
+type Options struct {
+	// Replicas is the number of replicas of the deployment.
+	Replicas int32
+	// MinAvailable is the number of replicas the PodDisruptionBudget keeps during voluntary disruptions, such as node
+	// drains. The PodDisruptionBudget is only created with more than one replica, as it would block the drains
+	// otherwise.
+	MinAvailable intstr.IntOrString
+	// AntiAffinity spreads the replicas on different nodes when possible.
+	AntiAffinity bool
+}
+
+// Validate returns an error when the options would leave no replica to serve the webhooks.
+func (o Options) Validate() error {
+	if o.Replicas < 1 {
+		return fmt.Errorf("invalid number of replicas %d, expected at least 1", o.Replicas)
+	}
+	if o.MinAvailable.Type == intstr.Int && o.MinAvailable.IntValue() >= int(o.Replicas) && o.Replicas > 1 {
+		return fmt.Errorf("invalid min available %d, expected less than the %d replicas so that the nodes can be drained", o.MinAvailable.IntValue(), o.Replicas)
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
+// Apply sets the replicas and the pod anti-affinity of the deployment, and creates, updates or deletes its
+// PodDisruptionBudget, which is owned by the deployment.
+func Apply(ctx context.Context, c client.Client, key types.NamespacedName, opts Options) error {
+	deployment := &appsv1.Deployment{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.Get(ctx, key, deployment); err != nil {
+			return err
+		}
+		desired := deployment.DeepCopy()
+		desired.Spec.Replicas = &opts.Replicas
+		if opts.AntiAffinity {
+			setAntiAffinity(&desired.Spec.Template.Spec, desired.Spec.Selector)
+		}
+		if equality.Semantic.DeepEqual(deployment.Spec, desired.Spec) {
+			return nil
+		}
+		return c.Update(ctx, desired)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply the availability settings to the deployment %s: %w", key, err)
+	}
+	if err := applyPodDisruptionBudget(ctx, c, deployment, opts); err != nil {
+		return fmt.Errorf("failed to apply the PodDisruptionBudget of the deployment %s: %w", key, err)
+	}
+	return nil
+}
+
+// setAntiAffinity prefers scheduling the pods of the selector on different nodes, unless the pod spec already has a
+// pod anti-affinity.
+func setAntiAffinity(spec *corev1.PodSpec, selector *metav1.LabelSelector) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.PodAntiAffinity != nil {
+		return
+	}
+	spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: selector,
+				TopologyKey:   hostnameTopologyKey,
+			},
+		}},
+	}
+}
+
+func applyPodDisruptionBudget(ctx context.Context, c client.Client, deployment *appsv1.Deployment, opts Options) error {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+		},
+	}
+	if opts.Replicas <= 1 {
+		return client.IgnoreNotFound(c.Delete(ctx, pdb))
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, pdb, func() error {
+		pdb.Labels = deployment.Spec.Template.Labels
+		pdb.Spec.Selector = deployment.Spec.Selector
+		pdb.Spec.MinAvailable = &opts.MinAvailable
+		return controllerutil.SetOwnerReference(deployment, pdb, c.Scheme())
+	})
+	if apierrors.IsAlreadyExists(err) {
+		// another replica created it first
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package availability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{Replicas: 2, MinAvailable: intstr.FromInt32(1)}.Validate())
+	assert.NoError(t, Options{Replicas: 1, MinAvailable: intstr.FromInt32(1)}.Validate())
+	assert.NoError(t, Options{Replicas: 3, MinAvailable: intstr.FromString("50%")}.Validate())
+	assert.Error(t, Options{Replicas: 0, MinAvailable: intstr.FromInt32(1)}.Validate())
+	assert.Error(t, Options{Replicas: 2, MinAvailable: intstr.FromInt32(2)}.Validate())
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}}
+	one := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-controller-manager", Namespace: "amazon-cloudwatch"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &one,
+			Selector: selector,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: selector.MatchLabels},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(deployment).Build()
+	key := types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}
+
+	require.NoError(t, Apply(ctx, c, key, Options{Replicas: 2, MinAvailable: intstr.FromInt32(1), AntiAffinity: true}))
+
+	actual := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, key, actual))
+	assert.Equal(t, int32(2), *actual.Spec.Replicas)
+	terms := actual.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	require.Len(t, terms, 1)
+	assert.Equal(t, selector, terms[0].PodAffinityTerm.LabelSelector)
+	assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey)
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, c.Get(ctx, key, pdb))
+	assert.Equal(t, selector, pdb.Spec.Selector)
+	assert.Equal(t, intstr.FromInt32(1), *pdb.Spec.MinAvailable)
+	require.Len(t, pdb.OwnerReferences, 1)
+	assert.Equal(t, deployment.Name, pdb.OwnerReferences[0].Name)
+
+	// the deployment is not updated again when it has the settings
+	require.NoError(t, Apply(ctx, c, key, Options{Replicas: 2, MinAvailable: intstr.FromInt32(1), AntiAffinity: true}))
+	unchanged := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, key, unchanged))
+	assert.Equal(t, actual.ResourceVersion, unchanged.ResourceVersion)
+
+	// a single replica has no PodDisruptionBudget
+	require.NoError(t, Apply(ctx, c, key, Options{Replicas: 1, MinAvailable: intstr.FromInt32(1)}))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, key, &policyv1.PodDisruptionBudget{})))
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package awsresource detects the AWS region and account of the cluster, which the operator injects into the
+// instrumented workloads so that their SDKs don't need the instance metadata (IMDS) to detect them. The pods can't
+// reach IMDSv2 when the hop limit of the nodes is 1.
+package awsresource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// envRegion and envDefaultRegion are the env vars of the region of the AWS SDKs.
+	envRegion        = "AWS_REGION"
+	envDefaultRegion = "AWS_DEFAULT_REGION"
+	// envRoleARN is the env var of the IAM role of the operator set by IAM roles for service accounts.
+	envRoleARN = "AWS_ROLE_ARN"
+)
+
+// Resource is the AWS region and account of the cluster.
+type Resource struct {
+	Region    string
+	AccountID string
+}
+
+// Detect completes the resource set by hand with the region of the env vars of the operator or, failing that, the
+// region label of the nodes, and with the account of the IAM role of the operator. The reader is only used when the
+// region is neither set nor in the env vars.
+func Detect(ctx context.Context, reader client.Reader, resource Resource, getenv func(string) string) (Resource, error) {
+	if resource.Region == "" {
+		resource.Region = getenv(envRegion)
+	}
+	if resource.Region == "" {
+		resource.Region = getenv(envDefaultRegion)
+	}
+	if resource.Region == "" {
+		region, err := nodeRegion(ctx, reader)
+		if err != nil {
+			return resource, err
+		}
+		resource.Region = region
+	}
+	if resource.AccountID == "" {
+		resource.AccountID = accountID(getenv(envRoleARN))
+	}
+	return resource, nil
+}
+
+// nodeRegion returns the region label of a node of the cluster, all of them being in the same region.
+func nodeRegion(ctx context.Context, reader client.Reader) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes, client.HasLabels{corev1.LabelTopologyRegion}, client.Limit(1)); err != nil {
+		return "", fmt.Errorf("failed to list the nodes to detect the region: %w", err)
+	}
+	if len(nodes.Items) == 0 {
+		return "", fmt.Errorf("no node has the label %s to detect the region from", corev1.LabelTopologyRegion)
+	}
+	return nodes.Items[0].Labels[corev1.LabelTopologyRegion], nil
+}
+
+// accountID returns the account of an ARN such as arn:aws:iam::123456789012:role/name, or an empty string when it is
+// not an ARN.
+func accountID(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[4]
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDetect(t *testing.T) {
+	ctx := context.Background()
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	reader := fake.NewClientBuilder().WithObjects(
+		node("unlabelled", nil),
+		node("labelled", map[string]string{corev1.LabelTopologyRegion: "eu-west-1"}),
+	).Build()
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	for _, tt := range []struct {
+		name     string
+		resource Resource
+		env      map[string]string
+		expected Resource
+	}{
+		{name: "set by hand", resource: Resource{Region: "us-east-1", AccountID: "111111111111"}, env: map[string]string{"AWS_REGION": "us-west-2"}, expected: Resource{Region: "us-east-1", AccountID: "111111111111"}},
+		{
+			name:     "env vars",
+			env:      map[string]string{"AWS_DEFAULT_REGION": "us-west-2", "AWS_ROLE_ARN": "arn:aws:iam::123456789012:role/cloudwatch-operator"},
+			expected: Resource{Region: "us-west-2", AccountID: "123456789012"},
+		},
+		{name: "node label", expected: Resource{Region: "eu-west-1"}},
+		{name: "invalid role", env: map[string]string{"AWS_REGION": "us-west-2", "AWS_ROLE_ARN": "cloudwatch-operator"}, expected: Resource{Region: "us-west-2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Detect(ctx, reader, tt.resource, env(tt.env))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	_, err := Detect(ctx, fake.NewClientBuilder().WithObjects(node("unlabelled", nil)).Build(), Resource{}, env(nil))
+	assert.Error(t, err)
+}
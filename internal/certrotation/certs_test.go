@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certrotation
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServingCert(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ca, err := newCA(now, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ca.cert.IsCA)
+	assert.Equal(t, now.Add(-clockSkew), ca.cert.NotBefore)
+
+	dnsNames := []string{"webhook.amazon-cloudwatch.svc", "webhook.amazon-cloudwatch.svc.cluster.local"}
+	serving, err := newServingCert(ca, dnsNames, now, 24*time.Hour)
+	require.NoError(t, err)
+	assert.NoError(t, serving.cert.CheckSignatureFrom(ca.cert))
+	assert.Equal(t, dnsNames, serving.cert.DNSNames)
+	// the serving certificate doesn't outlive its certificate authority
+	assert.Equal(t, ca.cert.NotAfter, serving.cert.NotAfter)
+
+	parsed, err := parseKeyPair(serving.certPEM, serving.keyPEM)
+	require.NoError(t, err)
+	assert.True(t, parsed.cert.Equal(serving.cert))
+
+	_, err = parseKeyPair(serving.certPEM, nil)
+	assert.Error(t, err)
+	_, err = parseKeyPair(append(serving.certPEM, ca.certPEM...), serving.keyPEM)
+	assert.Error(t, err)
+}
+
+func TestCertificateBundle(t *testing.T) {
+	now := time.Now()
+	first, err := newCA(now, time.Hour)
+	require.NoError(t, err)
+	second, err := newCA(now, time.Hour)
+	require.NoError(t, err)
+
+	bundle, err := parseCertificates(encodeCertificates([]*x509.Certificate{first.cert, second.cert}))
+	require.NoError(t, err)
+	require.Len(t, bundle, 2)
+	assert.True(t, containsCertificate(bundle, first.cert))
+	assert.True(t, containsCertificate(bundle, second.cert))
+	assert.False(t, containsCertificate(bundle[:1], second.cert))
+
+	assert.False(t, expiresWithin(first.cert, now, 0))
+	assert.True(t, expiresWithin(first.cert, now, time.Hour))
+}
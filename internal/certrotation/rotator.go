@@ -0,0 +1,458 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certrotation issues and rotates the serving certificate of the webhooks of the operator in the clusters
+// without cert-manager.
+//
+// The certificate authority is rotated in stages, so that the API servers never receive a serving certificate they
+// do not trust: the next certificate authority is first added to the CA bundles of the webhook configurations, and
+// only signs the serving certificate once the bundles have trusted it for a while. The previous certificate
+// authority is dropped from the bundles when it expires.
+package certrotation
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// The entries of the certificate Secret, next to the serving certificate and key.
+const (
+	CACertKey     = "ca.crt"
+	caKeyKey      = "ca.key"
+	nextCACertKey = "ca-next.crt"
+	nextCAKeyKey  = "ca-next.key"
+	// CABundleKey is the bundle of the certificate authorities trusted by the webhook configurations.
+	CABundleKey = "ca-bundle.crt"
+
+	// stagedAtAnnotation is the time the next certificate authority was added to the CA bundle.
+	stagedAtAnnotation = "cloudwatch.aws.amazon.com/next-ca-staged-at"
+)
+
+var (
+	crdGVK          = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+	crdCABundlePath = []string{"spec", "conversion", "webhook", "clientConfig", "caBundle"}
+)
+
+// The default validities and rotation times of the certificates.
+const (
+	DefaultCAValidity       = 5 * 365 * 24 * time.Hour
+	DefaultCertValidity     = 365 * 24 * time.Hour
+	DefaultCARotateBefore   = 90 * 24 * time.Hour
+	DefaultCertRotateBefore = 30 * 24 * time.Hour
+	DefaultStageDuration    = 10 * time.Minute
+	DefaultInterval         = 10 * time.Minute
+	DefaultSyncInterval     = 30 * time.Second
+)
+
+// Options configures the certificates of the webhooks.
+type Options struct {
+	// Secret holds the certificate authorities and the serving certificate.
+	Secret types.NamespacedName
+	// Service is the webhook service the serving certificate is issued for.
+	Service types.NamespacedName
+	// AdditionalServices are the other services of the webhook server the serving certificate is issued for, such as
+	// the services of the shards of the pod webhook.
+	AdditionalServices []types.NamespacedName
+	// WebhookConfigurations are the names of the mutating and validating webhook configurations whose CA bundle is
+	// managed.
+	WebhookConfigurations []string
+	// CustomResourceDefinitions are the names of the CustomResourceDefinitions whose conversion webhook CA bundle is
+	// managed.
+	CustomResourceDefinitions []string
+	// CertDir is the directory the webhook server reads the serving certificate and key from.
+	CertDir string
+
+	CAValidity       time.Duration
+	CertValidity     time.Duration
+	CARotateBefore   time.Duration
+	CertRotateBefore time.Duration
+	// StageDuration is how long the next certificate authority is trusted by the CA bundles before it signs the
+	// serving certificate.
+	StageDuration time.Duration
+	// Interval is the interval between two rotation checks of the leader.
+	Interval time.Duration
+	// SyncInterval is the interval between two reads of the Secret by every replica.
+	SyncInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;update;patch
+
+var _ manager.LeaderElectionRunnable = (*Rotator)(nil)
+
+// Rotator issues and rotates the certificates of the Secret, and writes the serving certificate to the certificate
+// directory. It uses a client without cache, as it runs before the manager starts.
+type Rotator struct {
+	client client.Client
+	opts   Options
+	log    logr.Logger
+	now    func() time.Time
+}
+
+// New returns the rotator of the certificates of the options.
+func New(c client.Client, opts Options, log logr.Logger) *Rotator {
+	setDefault := func(d *time.Duration, value time.Duration) {
+		if *d <= 0 {
+			*d = value
+		}
+	}
+	setDefault(&opts.CAValidity, DefaultCAValidity)
+	setDefault(&opts.CertValidity, DefaultCertValidity)
+	setDefault(&opts.CARotateBefore, DefaultCARotateBefore)
+	setDefault(&opts.CertRotateBefore, DefaultCertRotateBefore)
+	setDefault(&opts.StageDuration, DefaultStageDuration)
+	setDefault(&opts.Interval, DefaultInterval)
+	setDefault(&opts.SyncInterval, DefaultSyncInterval)
+	return &Rotator{client: c, opts: opts, log: log, now: time.Now}
+}
+
+// Bootstrap creates the Secret when it doesn't exist, and writes the serving certificate to the certificate
+// directory, so that the webhook server can start.
+func (r *Rotator) Bootstrap(ctx context.Context) error {
+	err := r.client.Get(ctx, r.opts.Secret, &corev1.Secret{})
+	if apierrors.IsNotFound(err) {
+		err = r.Rotate(ctx)
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return r.Sync(ctx)
+}
+
+// NeedLeaderElection is true, as only the leader rotates the certificates.
+func (r *Rotator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start rotates the certificates at every interval until the context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	return every(ctx, r.opts.Interval, func() {
+		if err := r.Rotate(ctx); err != nil {
+			r.log.Error(err, "failed to rotate the webhook certificates", "secret", r.opts.Secret.String())
+		}
+	})
+}
+
+// Syncer returns the runnable writing the serving certificate of the Secret to the certificate directory of every
+// replica.
+func (r *Rotator) Syncer() manager.Runnable {
+	return &syncer{r}
+}
+
+type syncer struct {
+	*Rotator
+}
+
+// NeedLeaderElection is false, as every replica serves the webhooks.
+func (s *syncer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start syncs the serving certificate at every sync interval until the context is done.
+func (s *syncer) Start(ctx context.Context) error {
+	return every(ctx, s.opts.SyncInterval, func() {
+		if err := s.Sync(ctx); err != nil {
+			s.log.Error(err, "failed to sync the webhook serving certificate", "secret", s.opts.Secret.String())
+		}
+	})
+}
+
+func every(ctx context.Context, interval time.Duration, fn func()) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rotate issues the certificates which are missing, invalid or about to expire. The CA bundles of the webhook
+// configurations are updated before the Secret, so that they trust a certificate authority before it signs anything.
+func (r *Rotator) Rotate(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, r.opts.Secret, secret)
+	create := apierrors.IsNotFound(err)
+	if err != nil && !create {
+		return err
+	}
+	if create {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.opts.Secret.Name, Namespace: r.opts.Secret.Namespace},
+			Type:       corev1.SecretTypeTLS,
+		}
+	}
+
+	desired := secret.DeepCopy()
+	if err := r.desired(ctx, desired); err != nil {
+		return err
+	}
+	if create {
+		// nothing is served yet, the Secret is created first so that the replicas starting together agree on the
+		// certificate authority of the bundles
+		if err := r.client.Create(ctx, desired); err != nil {
+			return err
+		}
+		r.log.Info("issued the webhook certificates", "secret", r.opts.Secret.String())
+		return r.patchBundles(ctx, desired.Data[CABundleKey])
+	}
+	if err := r.patchBundles(ctx, desired.Data[CABundleKey]); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(secret.Data, desired.Data) && equality.Semantic.DeepEqual(secret.Annotations, desired.Annotations) {
+		return nil
+	}
+	r.log.Info("rotated the webhook certificates", "secret", r.opts.Secret.String())
+	return r.client.Update(ctx, desired)
+}
+
+// desired updates the certificates of the Secret.
+func (r *Rotator) desired(ctx context.Context, secret *corev1.Secret) error {
+	now := r.now()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	bundle, _ := parseCertificates(secret.Data[CABundleKey])
+
+	ca, err := parseKeyPair(secret.Data[CACertKey], secret.Data[caKeyKey])
+	if err != nil || expiresWithin(ca.cert, now, 0) {
+		// there is no certificate authority to rotate from, which only happens on the first start
+		if ca, err = newCA(now, r.opts.CAValidity); err != nil {
+			return err
+		}
+		setKeyPair(secret, CACertKey, caKeyKey, ca)
+		removeNextCA(secret)
+	}
+
+	next, err := parseKeyPair(secret.Data[nextCACertKey], secret.Data[nextCAKeyKey])
+	switch {
+	case err != nil && expiresWithin(ca.cert, now, r.opts.CARotateBefore):
+		// stage the next certificate authority in the bundles
+		if next, err = newCA(now, r.opts.CAValidity); err != nil {
+			return err
+		}
+		setKeyPair(secret, nextCACertKey, nextCAKeyKey, next)
+		secret.Annotations[stagedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	case err == nil && r.staged(secret, now):
+		trusted, err := r.bundlesTrust(ctx, next.cert)
+		if err != nil {
+			return err
+		}
+		if trusted {
+			// promote the next certificate authority, which signs the serving certificate from now on
+			ca = next
+			setKeyPair(secret, CACertKey, caKeyKey, ca)
+			removeNextCA(secret)
+		}
+	case err != nil:
+		removeNextCA(secret)
+	}
+
+	serving, err := parseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil || expiresWithin(serving.cert, now, r.opts.CertRotateBefore) || serving.cert.CheckSignatureFrom(ca.cert) != nil ||
+		!slices.Equal(serving.cert.DNSNames, r.dnsNames()) {
+		if serving, err = newServingCert(ca, r.dnsNames(), now, r.opts.CertValidity); err != nil {
+			return err
+		}
+		setKeyPair(secret, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, serving)
+	}
+
+	// the bundle trusts the current and the next certificate authorities, and the previous ones until they expire
+	trusted := []*x509.Certificate{ca.cert}
+	if next, err := parseKeyPair(secret.Data[nextCACertKey], secret.Data[nextCAKeyKey]); err == nil {
+		trusted = append(trusted, next.cert)
+	}
+	for _, cert := range bundle {
+		if !containsCertificate(trusted, cert) && !expiresWithin(cert, now, 0) {
+			trusted = append(trusted, cert)
+		}
+	}
+	secret.Data[CABundleKey] = encodeCertificates(trusted)
+	return nil
+}
+
+// staged returns whether the next certificate authority was added to the bundles for the stage duration.
+func (r *Rotator) staged(secret *corev1.Secret, now time.Time) bool {
+	stagedAt, err := time.Parse(time.RFC3339, secret.Annotations[stagedAtAnnotation])
+	return err != nil || !now.Before(stagedAt.Add(r.opts.StageDuration))
+}
+
+func (r *Rotator) dnsNames() []string {
+	var dnsNames []string
+	for _, service := range append([]types.NamespacedName{r.opts.Service}, r.opts.AdditionalServices...) {
+		dnsNames = append(dnsNames,
+			fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace),
+		)
+	}
+	return dnsNames
+}
+
+func setKeyPair(secret *corev1.Secret, certKey, keyKey string, pair *keyPair) {
+	secret.Data[certKey] = pair.certPEM
+	secret.Data[keyKey] = pair.keyPEM
+}
+
+func removeNextCA(secret *corev1.Secret) {
+	delete(secret.Data, nextCACertKey)
+	delete(secret.Data, nextCAKeyKey)
+	delete(secret.Annotations, stagedAtAnnotation)
+}
+
+// patchBundles sets the CA bundle of all the webhooks of the webhook configurations, and of the conversion webhooks of
+// the CustomResourceDefinitions.
+func (r *Rotator) patchBundles(ctx context.Context, bundle []byte) error {
+	return r.visitBundles(ctx, func(caBundles []*[]byte) bool {
+		changed := false
+		for _, caBundle := range caBundles {
+			if !bytes.Equal(*caBundle, bundle) {
+				*caBundle = bundle
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// bundlesTrust returns whether all the CA bundles trust the certificate authority.
+func (r *Rotator) bundlesTrust(ctx context.Context, ca *x509.Certificate) (bool, error) {
+	trusted := true
+	err := r.visitBundles(ctx, func(caBundles []*[]byte) bool {
+		for _, caBundle := range caBundles {
+			certs, err := parseCertificates(*caBundle)
+			if err != nil || !containsCertificate(certs, ca) {
+				trusted = false
+			}
+		}
+		return false
+	})
+	return trusted, err
+}
+
+// visitBundles calls the function with pointers to the CA bundles of each of the webhook configurations and
+// CustomResourceDefinitions, which are updated when the function returns that it changed them.
+func (r *Rotator) visitBundles(ctx context.Context, fn func([]*[]byte) bool) error {
+	for _, name := range r.opts.WebhookConfigurations {
+		found := false
+		mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, mutating); err == nil {
+			found = true
+			var caBundles []*[]byte
+			for i := range mutating.Webhooks {
+				caBundles = append(caBundles, &mutating.Webhooks[i].ClientConfig.CABundle)
+			}
+			if fn(caBundles) {
+				if err := r.client.Update(ctx, mutating); err != nil {
+					return err
+				}
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, validating); err == nil {
+			found = true
+			var caBundles []*[]byte
+			for i := range validating.Webhooks {
+				caBundles = append(caBundles, &validating.Webhooks[i].ClientConfig.CABundle)
+			}
+			if fn(caBundles) {
+				if err := r.client.Update(ctx, validating); err != nil {
+					return err
+				}
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no mutating or validating webhook configuration %s", name)
+		}
+	}
+
+	for _, name := range r.opts.CustomResourceDefinitions {
+		// the CustomResourceDefinitions are read unstructured, so that the client doesn't need their scheme
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+		if strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+			continue
+		}
+		encoded, _, _ := unstructured.NestedString(crd.Object, crdCABundlePath...)
+		caBundle, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			caBundle = nil
+		}
+		if !fn([]*[]byte{&caBundle}) {
+			continue
+		}
+		if err := unstructured.SetNestedField(crd.Object, base64.StdEncoding.EncodeToString(caBundle), crdCABundlePath...); err != nil {
+			return err
+		}
+		if err := r.client.Update(ctx, crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync writes the serving certificate and key of the Secret to the certificate directory when they changed. The
+// webhook server reloads them on its own.
+func (r *Rotator) Sync(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, r.opts.Secret, secret); err != nil {
+		return err
+	}
+	cert, key := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(cert) == 0 || len(key) == 0 {
+		return errors.New("the Secret has no serving certificate")
+	}
+	if err := os.MkdirAll(r.opts.CertDir, 0o700); err != nil {
+		return err
+	}
+	// the key is written first, so that a certificate is never read along with the key of the previous one for long
+	if err := writeFileIfChanged(filepath.Join(r.opts.CertDir, corev1.TLSPrivateKeyKey), key); err != nil {
+		return err
+	}
+	return writeFileIfChanged(filepath.Join(r.opts.CertDir, corev1.TLSCertKey), cert)
+}
+
+// writeFileIfChanged atomically replaces the content of the file when it changed.
+func writeFileIfChanged(name string, data []byte) error {
+	if current, err := os.ReadFile(name); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
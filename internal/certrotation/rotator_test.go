@@ -0,0 +1,248 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certrotation
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	secretKey  = types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-server-cert"}
+	serviceKey = types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-service"}
+)
+
+func newTestRotator(t *testing.T, objs ...client.Object) (*Rotator, client.Client, *time.Time) {
+	objs = append(objs,
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "mutating-webhook-configuration"},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mpod.kb.io"}, {Name: "minstrumentation.kb.io"}},
+		},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "validating-webhook-configuration"},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vinstrumentation.kb.io"}},
+		},
+	)
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	r := New(c, Options{
+		Secret:                secretKey,
+		Service:               serviceKey,
+		WebhookConfigurations: []string{"mutating-webhook-configuration", "validating-webhook-configuration"},
+		CertDir:               t.TempDir(),
+		CAValidity:            100 * time.Hour,
+		CertValidity:          200 * time.Hour,
+		CARotateBefore:        10 * time.Hour,
+		CertRotateBefore:      5 * time.Hour,
+		StageDuration:         10 * time.Minute,
+	}, logr.Discard())
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	return r, c, &now
+}
+
+func getSecret(t *testing.T, c client.Client) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretKey, secret))
+	return secret
+}
+
+// webhookBundles returns the CA bundles of all the webhooks.
+func webhookBundles(t *testing.T, c client.Client) [][]byte {
+	ctx := context.Background()
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "mutating-webhook-configuration"}, mutating))
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "validating-webhook-configuration"}, validating))
+	var bundles [][]byte
+	for _, webhook := range mutating.Webhooks {
+		bundles = append(bundles, webhook.ClientConfig.CABundle)
+	}
+	for _, webhook := range validating.Webhooks {
+		bundles = append(bundles, webhook.ClientConfig.CABundle)
+	}
+	return bundles
+}
+
+func parseCert(t *testing.T, data []byte) *x509.Certificate {
+	certs, err := parseCertificates(data)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	return certs[0]
+}
+
+func TestRotateIssuesCertificates(t *testing.T) {
+	r, c, _ := newTestRotator(t)
+	require.NoError(t, r.Rotate(context.Background()))
+
+	secret := getSecret(t, c)
+	ca := parseCert(t, secret.Data[CACertKey])
+	serving := parseCert(t, secret.Data[corev1.TLSCertKey])
+	assert.NoError(t, serving.CheckSignatureFrom(ca))
+	assert.Equal(t, []string{"webhook-service.amazon-cloudwatch.svc", "webhook-service.amazon-cloudwatch.svc.cluster.local"}, serving.DNSNames)
+	assert.Equal(t, secret.Data[CACertKey], secret.Data[CABundleKey])
+	for _, bundle := range webhookBundles(t, c) {
+		assert.Equal(t, secret.Data[CABundleKey], bundle)
+	}
+
+	// nothing changes until the certificates are about to expire
+	resourceVersion := secret.ResourceVersion
+	require.NoError(t, r.Rotate(context.Background()))
+	assert.Equal(t, resourceVersion, getSecret(t, c).ResourceVersion)
+}
+
+func TestRotateAdditionalServices(t *testing.T) {
+	r, c, _ := newTestRotator(t)
+	require.NoError(t, r.Rotate(context.Background()))
+
+	// the serving certificate is reissued for the added services
+	r.opts.AdditionalServices = []types.NamespacedName{{Namespace: "amazon-cloudwatch", Name: "webhook-service-shard-1"}}
+	require.NoError(t, r.Rotate(context.Background()))
+	serving := parseCert(t, getSecret(t, c).Data[corev1.TLSCertKey])
+	assert.Equal(t, []string{
+		"webhook-service.amazon-cloudwatch.svc", "webhook-service.amazon-cloudwatch.svc.cluster.local",
+		"webhook-service-shard-1.amazon-cloudwatch.svc", "webhook-service-shard-1.amazon-cloudwatch.svc.cluster.local",
+	}, serving.DNSNames)
+}
+
+func TestRotateCertificateAuthority(t *testing.T) {
+	ctx := context.Background()
+	r, c, now := newTestRotator(t)
+	require.NoError(t, r.Rotate(ctx))
+	oldCA := parseCert(t, getSecret(t, c).Data[CACertKey])
+
+	// the next certificate authority is staged in the bundles, the serving certificate is still signed by the old one
+	*now = now.Add(91 * time.Hour)
+	require.NoError(t, r.Rotate(ctx))
+	secret := getSecret(t, c)
+	nextCA := parseCert(t, secret.Data[nextCACertKey])
+	bundle, err := parseCertificates(secret.Data[CABundleKey])
+	require.NoError(t, err)
+	assert.Len(t, bundle, 2)
+	assert.True(t, containsCertificate(bundle, oldCA))
+	assert.True(t, containsCertificate(bundle, nextCA))
+	for _, webhookBundle := range webhookBundles(t, c) {
+		assert.Equal(t, secret.Data[CABundleKey], webhookBundle)
+	}
+	assert.NoError(t, parseCert(t, secret.Data[corev1.TLSCertKey]).CheckSignatureFrom(oldCA))
+
+	// it is not promoted before the end of the stage duration
+	*now = now.Add(5 * time.Minute)
+	require.NoError(t, r.Rotate(ctx))
+	assert.Equal(t, secret.Data, getSecret(t, c).Data)
+
+	// it signs the serving certificate once promoted, while the old one is still trusted
+	*now = now.Add(5 * time.Minute)
+	require.NoError(t, r.Rotate(ctx))
+	secret = getSecret(t, c)
+	assert.True(t, parseCert(t, secret.Data[CACertKey]).Equal(nextCA))
+	assert.NotContains(t, secret.Data, nextCACertKey)
+	assert.NotContains(t, secret.Annotations, stagedAtAnnotation)
+	assert.NoError(t, parseCert(t, secret.Data[corev1.TLSCertKey]).CheckSignatureFrom(nextCA))
+	bundle, err = parseCertificates(secret.Data[CABundleKey])
+	require.NoError(t, err)
+	assert.Len(t, bundle, 2)
+
+	// the old one is dropped from the bundles once expired
+	*now = now.Add(10 * time.Hour)
+	require.NoError(t, r.Rotate(ctx))
+	secret = getSecret(t, c)
+	bundle, err = parseCertificates(secret.Data[CABundleKey])
+	require.NoError(t, err)
+	require.Len(t, bundle, 1)
+	assert.True(t, bundle[0].Equal(nextCA))
+	for _, webhookBundle := range webhookBundles(t, c) {
+		assert.Equal(t, secret.Data[CABundleKey], webhookBundle)
+	}
+}
+
+func TestRotateWaitsForTheBundles(t *testing.T) {
+	ctx := context.Background()
+	r, c, now := newTestRotator(t)
+	require.NoError(t, r.Rotate(ctx))
+	*now = now.Add(91 * time.Hour)
+	require.NoError(t, r.Rotate(ctx))
+	staged := getSecret(t, c)
+
+	// a webhook configuration was reapplied with the previous bundle, which doesn't trust the next certificate authority
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "mutating-webhook-configuration"}, mutating))
+	mutating.Webhooks[0].ClientConfig.CABundle = staged.Data[CACertKey]
+	require.NoError(t, c.Update(ctx, mutating))
+	*now = now.Add(time.Hour)
+
+	require.NoError(t, r.Rotate(ctx))
+	assert.Equal(t, staged.Data[CACertKey], getSecret(t, c).Data[CACertKey])
+	// the bundles were patched again, so the next rotation promotes it
+	require.NoError(t, r.Rotate(ctx))
+	assert.Equal(t, staged.Data[nextCACertKey], getSecret(t, c).Data[CACertKey])
+}
+
+func TestRotateConversionWebhook(t *testing.T) {
+	ctx := context.Background()
+	crd := func(name, strategy string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"conversion": map[string]interface{}{"strategy": strategy}},
+		}}
+		obj.SetGroupVersionKind(crdGVK)
+		obj.SetName(name)
+		return obj
+	}
+	r, c, _ := newTestRotator(t, crd("amazoncloudwatchagents.cloudwatch.aws.amazon.com", "Webhook"), crd("dcgmexporters.cloudwatch.aws.amazon.com", "None"))
+	r.opts.CustomResourceDefinitions = []string{"amazoncloudwatchagents.cloudwatch.aws.amazon.com", "dcgmexporters.cloudwatch.aws.amazon.com"}
+	require.NoError(t, r.Rotate(ctx))
+	secret := getSecret(t, c)
+
+	actual := crd("amazoncloudwatchagents.cloudwatch.aws.amazon.com", "")
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: actual.GetName()}, actual))
+	caBundle, _, _ := unstructured.NestedString(actual.Object, crdCABundlePath...)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(secret.Data[CABundleKey]), caBundle)
+
+	// the conversion webhook of a CustomResourceDefinition without one is left unset
+	actual = crd("dcgmexporters.cloudwatch.aws.amazon.com", "")
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: actual.GetName()}, actual))
+	_, found, _ := unstructured.NestedString(actual.Object, crdCABundlePath...)
+	assert.False(t, found)
+}
+
+func TestRotateMissingWebhookConfiguration(t *testing.T) {
+	r, _, _ := newTestRotator(t)
+	r.opts.WebhookConfigurations = append(r.opts.WebhookConfigurations, "missing")
+	assert.Error(t, r.Rotate(context.Background()))
+}
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	r, c, _ := newTestRotator(t)
+	require.NoError(t, r.Bootstrap(ctx))
+	secret := getSecret(t, c)
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		data, err := os.ReadFile(filepath.Join(r.opts.CertDir, key))
+		require.NoError(t, err)
+		assert.Equal(t, secret.Data[key], data)
+	}
+
+	// the serving certificate issued by the leader is written by every replica
+	secret.Data[corev1.TLSCertKey] = []byte("rotated")
+	require.NoError(t, c.Update(ctx, secret))
+	require.NoError(t, r.Sync(ctx))
+	data, err := os.ReadFile(filepath.Join(r.opts.CertDir, corev1.TLSCertKey))
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", string(data))
+}
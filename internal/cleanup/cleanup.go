@@ -0,0 +1,262 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cleanup deletes the resources created by the operator which outlive the custom resource they were created
+// for. The garbage collector of Kubernetes misses the cluster-scoped resources, which have no owner reference, and the
+// resources whose owner references were removed, while the pruning of the reconcilers misses the resources whose
+// labels were changed.
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+const (
+	// Finalizer holds the deletion of an AmazonCloudWatchAgent until the resources created for it are deleted.
+	Finalizer = "cloudwatch.aws.amazon.com/cleanup"
+
+	// DefaultInterval is the interval between two sweeps of the orphaned resources.
+	DefaultInterval = 10 * time.Minute
+
+	instanceLabel = "app.kubernetes.io/instance"
+)
+
+var (
+	// managedLabels are the labels of all the resources created for a custom resource.
+	managedLabels = client.MatchingLabels{
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/part-of":    "amazon-cloudwatch-agent",
+	}
+
+	// namespacedKinds are the kinds of the namespaced resources created for the custom resources. The kinds whose
+	// CustomResourceDefinition is not installed are skipped.
+	namespacedKinds = []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Version: "v1", Kind: "Service"},
+		{Version: "v1", Kind: "ServiceAccount"},
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+		{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+		{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+		{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+		{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"},
+		{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+		{Group: "eks.services.k8s.aws", Version: "v1alpha1", Kind: "PodIdentityAssociation"},
+		{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"},
+		{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GRPCRoute"},
+	}
+
+	// clusterKinds are the kinds of the cluster-scoped resources, which are only created by a cluster-scoped operator.
+	clusterKinds = []schema.GroupVersionKind{
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	}
+)
+
+var _ manager.LeaderElectionRunnable = (*Sweeper)(nil)
+
+// Sweeper deletes the resources of the deleted custom resources at every interval, and the resources of an
+// AmazonCloudWatchAgent when it is deleted.
+type Sweeper struct {
+	reader     client.Reader
+	client     client.Client
+	namespaces []string
+	interval   time.Duration
+	log        logr.Logger
+}
+
+// New returns the sweeper of the resources of the namespaces, or of all the namespaces and the cluster-scoped
+// resources when there are none. The resources are read with the reader, so that the client doesn't cache them.
+func New(reader client.Reader, c client.Client, namespaces []string, interval time.Duration, log logr.Logger) *Sweeper {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Sweeper{reader: reader, client: c, namespaces: namespaces, interval: interval, log: log}
+}
+
+// NeedLeaderElection is true, as only the leader deletes the resources.
+func (s *Sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sweeps the orphaned resources at every interval until the context is done.
+func (s *Sweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sweep(ctx); err != nil {
+			s.log.Error(err, "failed to sweep the orphaned resources")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeleteOwned deletes the resources created for the owner: the ones it controls, and the ones labelled with its
+// instance which are controlled by no other resource.
+func (s *Sweeper) DeleteOwned(ctx context.Context, owner metav1.Object) error {
+	instance := instanceOf(owner)
+	return s.visit(ctx, []string{owner.GetNamespace()}, func(obj *metav1.PartialObjectMetadata) bool {
+		if ref := metav1.GetControllerOfNoCopy(obj); ref != nil {
+			return ref.UID == owner.GetUID()
+		}
+		return obj.GetLabels()[instanceLabel] == instance
+	})
+}
+
+// Sweep deletes the resources whose owner no longer exists. The owners are listed after the resources, so that the
+// owner of a resource created during the sweep is always found.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	var objs []*metav1.PartialObjectMetadata
+	if err := s.visit(ctx, s.namespaces, func(obj *metav1.PartialObjectMetadata) bool {
+		objs = append(objs, obj)
+		return false
+	}); err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	uids, instances, err := s.owners(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, obj := range objs {
+		if ref := metav1.GetControllerOfNoCopy(obj); ref != nil {
+			// the resources controlled by another kind are left to their controller
+			if !isCustomResource(ref) || uids[ref.UID] {
+				continue
+			}
+		} else if instances[obj.GetLabels()[instanceLabel]] {
+			continue
+		}
+		if err := s.delete(ctx, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// owners returns the UIDs and the instance labels of the custom resources.
+func (s *Sweeper) owners(ctx context.Context) (map[types.UID]bool, map[string]bool, error) {
+	uids := map[types.UID]bool{}
+	instances := map[string]bool{}
+	add := func(owner metav1.Object) {
+		uids[owner.GetUID()] = true
+		instances[instanceOf(owner)] = true
+	}
+	for _, namespace := range s.listNamespaces(s.namespaces) {
+		agents := &v1alpha1.AmazonCloudWatchAgentList{}
+		if err := s.reader.List(ctx, agents, client.InNamespace(namespace)); err != nil {
+			return nil, nil, err
+		}
+		for i := range agents.Items {
+			add(&agents.Items[i])
+		}
+		dcgmExporters := &v1alpha1.DcgmExporterList{}
+		if err := s.reader.List(ctx, dcgmExporters, client.InNamespace(namespace)); err != nil {
+			return nil, nil, err
+		}
+		for i := range dcgmExporters.Items {
+			add(&dcgmExporters.Items[i])
+		}
+		neuronMonitors := &v1alpha1.NeuronMonitorList{}
+		if err := s.reader.List(ctx, neuronMonitors, client.InNamespace(namespace)); err != nil {
+			return nil, nil, err
+		}
+		for i := range neuronMonitors.Items {
+			add(&neuronMonitors.Items[i])
+		}
+	}
+	return uids, instances, nil
+}
+
+// visit deletes the resources of the namespaces for which fn returns true. The cluster-scoped resources are only
+// visited by a cluster-scoped operator.
+func (s *Sweeper) visit(ctx context.Context, namespaces []string, fn func(*metav1.PartialObjectMetadata) bool) error {
+	var errs []error
+	visitKind := func(gvk schema.GroupVersionKind, namespace string) {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := s.reader.List(ctx, list, managedLabels, client.HasLabels{instanceLabel}, client.InNamespace(namespace))
+		if err != nil {
+			if !meta.IsNoMatchError(err) {
+				errs = append(errs, err)
+			}
+			return
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetGroupVersionKind(gvk)
+			if obj.GetDeletionTimestamp() != nil || !fn(obj) {
+				continue
+			}
+			if err := s.delete(ctx, obj); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, namespace := range s.listNamespaces(namespaces) {
+		for _, gvk := range namespacedKinds {
+			visitKind(gvk, namespace)
+		}
+	}
+	if len(s.namespaces) == 0 {
+		for _, gvk := range clusterKinds {
+			visitKind(gvk, metav1.NamespaceAll)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listNamespaces returns the namespaces to list, all of them when there are none.
+func (s *Sweeper) listNamespaces(namespaces []string) []string {
+	if len(namespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+	return namespaces
+}
+
+func (s *Sweeper) delete(ctx context.Context, obj *metav1.PartialObjectMetadata) error {
+	s.log.Info("deleting the orphaned resource", "kind", obj.GroupVersionKind().Kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	err := s.client.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isCustomResource returns whether the owner reference refers to a custom resource of the operator.
+func isCustomResource(ref *metav1.OwnerReference) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	return err == nil && gv.Group == v1alpha1.GroupVersion.Group
+}
+
+// instanceOf returns the instance label of the resources created for the owner.
+func instanceOf(owner metav1.Object) string {
+	return manifestutils.SelectorLabelsForAllOperatorManaged(metav1.ObjectMeta{Namespace: owner.GetNamespace(), Name: owner.GetName()})[instanceLabel]
+}
@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+var agent = &v1alpha1.AmazonCloudWatchAgent{
+	ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch", Name: "cloudwatch-agent", UID: "agent-uid"},
+}
+
+// newTestClient returns a client serving all the kinds created for the custom resources.
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, kind := range []string{"AmazonCloudWatchAgent", "DcgmExporter", "NeuronMonitor"} {
+		mapper.Add(v1alpha1.GroupVersion.WithKind(kind), meta.RESTScopeNamespace)
+	}
+	for _, gvk := range namespacedKinds {
+		// the kinds of the optional CustomResourceDefinitions are installed
+		if !scheme.Recognizes(gvk) {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	for _, gvk := range clusterKinds {
+		mapper.Add(gvk, meta.RESTScopeRoot)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build()
+}
+
+// managedMeta returns the metadata of a resource created for the instance, controlled by the owner if any.
+func managedMeta(namespace, name, instance string, owner *metav1.OwnerReference) metav1.ObjectMeta {
+	objectMeta := metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      name,
+		Labels: map[string]string{
+			"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+			"app.kubernetes.io/part-of":    "amazon-cloudwatch-agent",
+			"app.kubernetes.io/instance":   instance,
+		},
+	}
+	if owner != nil {
+		objectMeta.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return objectMeta
+}
+
+func controllerRef(apiVersion, kind string, uid types.UID) *metav1.OwnerReference {
+	return &metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: "owner", UID: uid, Controller: ptr.To(true)}
+}
+
+func exists(t *testing.T, c client.Client, obj client.Object) bool {
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestDeleteOwned(t *testing.T) {
+	agentRef := controllerRef("cloudwatch.aws.amazon.com/v1alpha1", "AmazonCloudWatchAgent", agent.UID)
+	controlled := &appsv1.DaemonSet{ObjectMeta: managedMeta("amazon-cloudwatch", "cloudwatch-agent", "relabelled", agentRef)}
+	labelled := &rbacv1.ClusterRole{ObjectMeta: managedMeta("", "cloudwatch-agent-role", "amazon-cloudwatch.cloudwatch-agent", nil)}
+	other := &corev1.ConfigMap{ObjectMeta: managedMeta("amazon-cloudwatch", "other", "amazon-cloudwatch.other", nil)}
+	// a resource labelled with the instance but controlled by another resource is left to it
+	otherController := &corev1.Service{ObjectMeta: managedMeta("amazon-cloudwatch", "dcgm-exporter-service", "amazon-cloudwatch.cloudwatch-agent",
+		controllerRef("cloudwatch.aws.amazon.com/v1alpha1", "DcgmExporter", "dcgm-uid"))}
+	c := newTestClient(t, controlled, labelled, other, otherController)
+
+	require.NoError(t, New(c, c, nil, 0, logr.Discard()).DeleteOwned(context.Background(), agent))
+	assert.False(t, exists(t, c, controlled))
+	assert.False(t, exists(t, c, labelled))
+	assert.True(t, exists(t, c, other))
+	assert.True(t, exists(t, c, otherController))
+}
+
+func TestDeleteOwnedNamespaceScoped(t *testing.T) {
+	labelled := &rbacv1.ClusterRole{ObjectMeta: managedMeta("", "cloudwatch-agent-role", "amazon-cloudwatch.cloudwatch-agent", nil)}
+	role := &rbacv1.Role{ObjectMeta: managedMeta("amazon-cloudwatch", "cloudwatch-agent-role", "amazon-cloudwatch.cloudwatch-agent", nil)}
+	c := newTestClient(t, labelled, role)
+
+	require.NoError(t, New(c, c, []string{"amazon-cloudwatch"}, 0, logr.Discard()).DeleteOwned(context.Background(), agent))
+	assert.True(t, exists(t, c, labelled))
+	assert.False(t, exists(t, c, role))
+}
+
+func TestSweep(t *testing.T) {
+	dcgmExporter := &v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch", Name: "dcgm-exporter", UID: "dcgm-uid"}}
+	live := []client.Object{
+		&appsv1.DaemonSet{ObjectMeta: managedMeta("amazon-cloudwatch", "cloudwatch-agent", "amazon-cloudwatch.cloudwatch-agent",
+			controllerRef("cloudwatch.aws.amazon.com/v1alpha1", "AmazonCloudWatchAgent", agent.UID))},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: managedMeta("", "cloudwatch-agent-role-binding", "amazon-cloudwatch.cloudwatch-agent", nil)},
+		&corev1.Service{ObjectMeta: managedMeta("amazon-cloudwatch", "dcgm-exporter-service", "amazon-cloudwatch.dcgm-exporter",
+			controllerRef("cloudwatch.aws.amazon.com/v1alpha1", "DcgmExporter", dcgmExporter.UID))},
+		// the resources controlled by another kind are never swept
+		&corev1.ConfigMap{ObjectMeta: managedMeta("amazon-cloudwatch", "helm", "amazon-cloudwatch.deleted",
+			controllerRef("apps/v1", "Deployment", "deployment-uid"))},
+	}
+	orphaned := []client.Object{
+		&appsv1.DaemonSet{ObjectMeta: managedMeta("amazon-cloudwatch", "deleted", "amazon-cloudwatch.deleted",
+			controllerRef("cloudwatch.aws.amazon.com/v1alpha1", "AmazonCloudWatchAgent", "deleted-uid"))},
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("", "deleted-role", "amazon-cloudwatch.deleted", nil)},
+		&corev1.ServiceAccount{ObjectMeta: managedMeta("team-a", "deleted", "team-a.deleted", nil)},
+	}
+	c := newTestClient(t, append(append([]client.Object{agent.DeepCopy(), dcgmExporter}, live...), orphaned...)...)
+
+	require.NoError(t, New(c, c, nil, 0, logr.Discard()).Sweep(context.Background()))
+	for _, obj := range live {
+		assert.True(t, exists(t, c, obj), obj.GetName())
+	}
+	for _, obj := range orphaned {
+		assert.False(t, exists(t, c, obj), obj.GetName())
+	}
+}
+
+func TestSweepWithoutResources(t *testing.T) {
+	c := newTestClient(t)
+	assert.NoError(t, New(c, c, []string{"team-a", "team-b"}, 0, logr.Discard()).Sweep(context.Background()))
+}
@@ -0,0 +1,199 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// The languages of the auto-instrumentation defaults, named after the fields of the Instrumentation spec.
+const (
+	LanguageJava        = "java"
+	LanguageNodeJS      = "nodejs"
+	LanguagePython      = "python"
+	LanguageDotNet      = "dotnet"
+	LanguageGo          = "go"
+	LanguageApacheHttpd = "apacheHttpd"
+	LanguageNginx       = "nginx"
+)
+
+// DefaultsConfigMapEntry is the entry of the operator configuration ConfigMap holding the defaults.
+const DefaultsConfigMapEntry = "config.yaml"
+
+// The apiVersion and kind of the operator configuration document, such as the one of the EKS add-on configuration.
+// The defaults may also be given without them.
+const (
+	OperatorConfigurationAPIVersion = "cloudwatch.aws.amazon.com/v1alpha1"
+	OperatorConfigurationKind       = "OperatorConfiguration"
+)
+
+var languages = []string{LanguageJava, LanguageNodeJS, LanguagePython, LanguageDotNet, LanguageGo, LanguageApacheHttpd, LanguageNginx}
+
+// Defaults are the defaults of the operator which can be changed at runtime through the operator configuration
+// ConfigMap, rather than through the flags of the operator deployment. The zero value keeps the defaults of the flags.
+type Defaults struct {
+	// AutoInstrumentation are the defaults of the auto-instrumentation of each language, by language.
+	AutoInstrumentation map[string]LanguageDefaults `json:"autoInstrumentation,omitempty"`
+
+	// TrustedEndpoints are the hosts, with an optional port, the Instrumentation exporters may send their telemetry
+	// to. A leading "*." matches any subdomain. All the endpoints are trusted when empty.
+	TrustedEndpoints []string `json:"trustedEndpoints,omitempty"`
+
+	// DeniedNamespaces are the namespaces, or shell patterns of namespaces, whose pods are never injected.
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+
+	// FeatureGates enable, with no prefix or a "+" prefix, or disable, with a "-" prefix, the feature gates of the
+	// operator, overriding the --feature-gates flag.
+	FeatureGates []string `json:"featureGates,omitempty"`
+}
+
+// operatorConfiguration is the versioned document of the defaults.
+type operatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	Defaults        `json:",inline"`
+}
+
+// LanguageDefaults are the defaults of the auto-instrumentation of a language.
+type LanguageDefaults struct {
+	// Image is the auto-instrumentation image of the Instrumentation instances which do not specify one.
+	Image string `json:"image,omitempty"`
+
+	// Resources are the resources of the init container of the Instrumentation instances which do not specify them.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ParseDefaults parses the YAML or JSON defaults of the operator configuration ConfigMap.
+func ParseDefaults(data string) (Defaults, error) {
+	document := operatorConfiguration{}
+	if err := yaml.UnmarshalStrict([]byte(data), &document); err != nil {
+		return Defaults{}, fmt.Errorf("invalid operator configuration: %w", err)
+	}
+	if document.APIVersion != "" && document.APIVersion != OperatorConfigurationAPIVersion {
+		return Defaults{}, fmt.Errorf("invalid operator configuration: unknown apiVersion %q, expected %s", document.APIVersion, OperatorConfigurationAPIVersion)
+	}
+	if document.Kind != "" && document.Kind != OperatorConfigurationKind {
+		return Defaults{}, fmt.Errorf("invalid operator configuration: unknown kind %q, expected %s", document.Kind, OperatorConfigurationKind)
+	}
+	defaults := document.Defaults
+	for language := range defaults.AutoInstrumentation {
+		if !slices.Contains(languages, language) {
+			return Defaults{}, fmt.Errorf("invalid operator configuration: unknown auto-instrumentation language %q, expected one of %s", language, strings.Join(languages, ", "))
+		}
+	}
+	for _, endpoint := range defaults.TrustedEndpoints {
+		if _, _, err := splitTrustedEndpoint(endpoint); err != nil {
+			return Defaults{}, fmt.Errorf("invalid operator configuration: %w", err)
+		}
+	}
+	for _, pattern := range defaults.DeniedNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Defaults{}, fmt.Errorf("invalid operator configuration: invalid denied namespace %q: %w", pattern, err)
+		}
+	}
+	for _, gate := range defaults.FeatureGates {
+		if id, _ := ParseFeatureGate(gate); id == "" {
+			return Defaults{}, fmt.Errorf("invalid operator configuration: invalid feature gate %q", gate)
+		}
+	}
+	return defaults, nil
+}
+
+// merge returns the defaults overridden by the ones set in override: the languages of override replace the ones of
+// the defaults, its non-empty lists replace the ones of the defaults, and its feature gates are applied after the
+// ones of the defaults.
+func (d Defaults) merge(override Defaults) Defaults {
+	merged := Defaults{
+		AutoInstrumentation: maps.Clone(d.AutoInstrumentation),
+		TrustedEndpoints:    d.TrustedEndpoints,
+		DeniedNamespaces:    d.DeniedNamespaces,
+		FeatureGates:        append(slices.Clone(d.FeatureGates), override.FeatureGates...),
+	}
+	for language, defaults := range override.AutoInstrumentation {
+		if merged.AutoInstrumentation == nil {
+			merged.AutoInstrumentation = map[string]LanguageDefaults{}
+		}
+		merged.AutoInstrumentation[language] = defaults
+	}
+	if len(override.TrustedEndpoints) > 0 {
+		merged.TrustedEndpoints = override.TrustedEndpoints
+	}
+	if len(override.DeniedNamespaces) > 0 {
+		merged.DeniedNamespaces = override.DeniedNamespaces
+	}
+	return merged
+}
+
+// ParseFeatureGate returns the identifier of the feature gate and whether it is enabled.
+func ParseFeatureGate(gate string) (string, bool) {
+	gate = strings.TrimSpace(gate)
+	if id, ok := strings.CutPrefix(gate, "-"); ok {
+		return id, false
+	}
+	return strings.TrimPrefix(gate, "+"), true
+}
+
+// splitTrustedEndpoint returns the host and the optional port of a trusted endpoint.
+func splitTrustedEndpoint(endpoint string) (string, string, error) {
+	host, port := endpoint, ""
+	if strings.Contains(endpoint, ":") {
+		var err error
+		if host, port, err = net.SplitHostPort(endpoint); err != nil {
+			return "", "", fmt.Errorf("invalid trusted endpoint %q: %w", endpoint, err)
+		}
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); port != "" && err != nil {
+		return "", "", fmt.Errorf("invalid trusted endpoint %q: invalid port %q", endpoint, port)
+	}
+	if host == "" || strings.Contains(host, "/") {
+		return "", "", fmt.Errorf("invalid trusted endpoint %q: expected a host with an optional port", endpoint)
+	}
+	return host, port, nil
+}
+
+// trusts returns whether the exporter endpoint, such as http://cloudwatch-agent.amazon-cloudwatch:4316, is one of
+// the trusted endpoints.
+func (d Defaults) trusts(endpoint string) bool {
+	if len(d.TrustedEndpoints) == 0 {
+		return true
+	}
+	if !strings.Contains(endpoint, "://") {
+		// the gRPC endpoints may have no scheme
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	for _, trusted := range d.TrustedEndpoints {
+		host, port, err := splitTrustedEndpoint(trusted)
+		if err != nil || (port != "" && port != u.Port()) {
+			continue
+		}
+		if host == u.Hostname() || (strings.HasPrefix(host, "*.") && strings.HasSuffix(u.Hostname(), host[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// denies returns whether the pods of the namespace are never injected.
+func (d Defaults) denies(namespace string) bool {
+	for _, pattern := range d.DeniedNamespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
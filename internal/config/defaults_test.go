@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestParseDefaults(t *testing.T) {
+	defaults, err := config.ParseDefaults(`
+autoInstrumentation:
+  java:
+    image: java:v2
+    resources:
+      limits:
+        memory: 256Mi
+trustedEndpoints:
+- cloudwatch-agent.amazon-cloudwatch
+deniedNamespaces:
+- kube-*
+featureGates:
+- -operator.autoinstrumentation.go
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "java:v2", defaults.AutoInstrumentation[config.LanguageJava].Image)
+	assert.Equal(t, resource.MustParse("256Mi"), defaults.AutoInstrumentation[config.LanguageJava].Resources.Limits[corev1.ResourceMemory])
+	assert.Equal(t, []string{"cloudwatch-agent.amazon-cloudwatch"}, defaults.TrustedEndpoints)
+	assert.Equal(t, []string{"kube-*"}, defaults.DeniedNamespaces)
+	assert.Equal(t, []string{"-operator.autoinstrumentation.go"}, defaults.FeatureGates)
+
+	defaults, err = config.ParseDefaults("")
+	require.NoError(t, err)
+	assert.Equal(t, config.Defaults{}, defaults)
+
+	for _, invalid := range []string{
+		"autoInstrumentation:\n  ruby:\n    image: ruby:v1\n",
+		"trustedEndpoints:\n- http://cloudwatch-agent/v1/traces\n",
+		"deniedNamespaces:\n- '['\n",
+		"featureGates:\n- '-'\n",
+		"unknownField: true\n",
+		"apiVersion: cloudwatch.aws.amazon.com/v2\nkind: OperatorConfiguration\n",
+		"apiVersion: cloudwatch.aws.amazon.com/v1alpha1\nkind: Instrumentation\n",
+	} {
+		_, err = config.ParseDefaults(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRuntimeDefaults(t *testing.T) {
+	cfg := config.New(config.WithAutoInstrumentationJavaImage("java:v1"))
+	var changes int
+	cfg.RegisterChangeCallback(func() error {
+		changes++
+		return nil
+	})
+	assert.True(t, cfg.TrustsEndpoint("http://anywhere:4316"))
+	assert.False(t, cfg.DeniesNamespace("kube-system"))
+
+	// the copies of the configuration share the defaults
+	copied := cfg
+	copied.SetDefaults(config.Defaults{
+		AutoInstrumentation: map[string]config.LanguageDefaults{
+			config.LanguageJava: {Image: "java:v2"},
+		},
+		TrustedEndpoints: []string{"cloudwatch-agent.amazon-cloudwatch:4316", "*.svc.cluster.local"},
+		DeniedNamespaces: []string{"kube-*"},
+	})
+	assert.Equal(t, 1, changes)
+	assert.Equal(t, "java:v2", cfg.AutoInstrumentationJavaImage())
+	assert.True(t, cfg.TrustsEndpoint("http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"))
+	assert.True(t, cfg.TrustsEndpoint("cloudwatch-agent.amazon-cloudwatch:4316"))
+	assert.False(t, cfg.TrustsEndpoint("http://cloudwatch-agent.amazon-cloudwatch:4317"))
+	assert.True(t, cfg.TrustsEndpoint("https://collector.team-a.svc.cluster.local:4318"))
+	assert.False(t, cfg.TrustsEndpoint("https://collector.example.com"))
+	assert.True(t, cfg.DeniesNamespace("kube-system"))
+	assert.False(t, cfg.DeniesNamespace("default"))
+
+	cfg.SetDefaults(config.Defaults{})
+	assert.Equal(t, "java:v1", cfg.AutoInstrumentationJavaImage())
+}
+
+func TestParseOperatorConfiguration(t *testing.T) {
+	// the EKS add-on configuration is JSON
+	defaults, err := config.ParseDefaults(`{
+  "apiVersion": "cloudwatch.aws.amazon.com/v1alpha1",
+  "kind": "OperatorConfiguration",
+  "deniedNamespaces": ["kube-*"],
+  "featureGates": ["-operator.autoinstrumentation.go"]
+}`)
+	require.NoError(t, err)
+	assert.Equal(t, config.Defaults{
+		DeniedNamespaces: []string{"kube-*"},
+		FeatureGates:     []string{"-operator.autoinstrumentation.go"},
+	}, defaults)
+}
+
+func TestDocumentDefaults(t *testing.T) {
+	cfg := config.New(
+		config.WithAutoInstrumentationJavaImage("java:v1"),
+		config.WithDefaults(config.Defaults{
+			AutoInstrumentation: map[string]config.LanguageDefaults{
+				config.LanguageJava:   {Image: "java:v2"},
+				config.LanguagePython: {Image: "python:v2"},
+			},
+			DeniedNamespaces: []string{"kube-*"},
+			FeatureGates:     []string{"-operator.autoinstrumentation.go"},
+		}),
+	)
+	assert.Equal(t, "java:v2", cfg.AutoInstrumentationJavaImage())
+	assert.True(t, cfg.DeniesNamespace("kube-system"))
+
+	// the defaults changed at runtime override the ones of the document
+	cfg.SetDefaults(config.Defaults{
+		AutoInstrumentation: map[string]config.LanguageDefaults{
+			config.LanguageJava: {Image: "java:v3"},
+		},
+		FeatureGates: []string{"+operator.autoinstrumentation.go"},
+	})
+	assert.Equal(t, "java:v3", cfg.AutoInstrumentationJavaImage())
+	assert.Equal(t, "python:v2", cfg.AutoInstrumentationPythonImage())
+	assert.True(t, cfg.DeniesNamespace("kube-system"))
+	assert.Equal(t, []string{"-operator.autoinstrumentation.go", "+operator.autoinstrumentation.go"}, cfg.Defaults().FeatureGates)
+
+	// and the ones of the document are restored when the runtime ones are reset
+	cfg.SetDefaults(config.Defaults{})
+	assert.Equal(t, "java:v2", cfg.AutoInstrumentationJavaImage())
+}
+
+func TestOperatorConfigurationSchema(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "addon", "operator-configuration.schema.json"))
+	require.NoError(t, err)
+	schema := struct {
+		Properties map[string]struct {
+			PropertyNames struct {
+				Enum []string `json:"enum"`
+			} `json:"propertyNames"`
+		} `json:"properties"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	// the schema of the EKS add-on follows the fields of the defaults
+	fields := []string{"apiVersion", "kind"}
+	defaultsType := reflect.TypeOf(config.Defaults{})
+	for i := 0; i < defaultsType.NumField(); i++ {
+		name, _, _ := strings.Cut(defaultsType.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+	assert.ElementsMatch(t, fields, slices.Collect(maps.Keys(schema.Properties)))
+	assert.ElementsMatch(t, []string{
+		config.LanguageJava, config.LanguageNodeJS, config.LanguagePython, config.LanguageDotNet,
+		config.LanguageGo, config.LanguageApacheHttpd, config.LanguageNginx,
+	}, schema.Properties["autoInstrumentation"].PropertyNames.Enum)
+}
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// publicECRHost is the registry host of Amazon ECR Public, which most of the default images are pulled from.
+const publicECRHost = "public.ecr.aws"
+
+// registryHost splits the image into its registry host and the rest of its reference. The host is empty for the
+// Docker Hub images, which don't name one: the first component of a reference is only a host when it has a dot or a
+// port, or is localhost.
+func registryHost(image string) (host string, rest string) {
+	first, remainder, found := strings.Cut(image, "/")
+	if !found || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return "", image
+	}
+	return first, remainder
+}
+
+// MirrorImage returns the image pulled from the registry mirror, whose prefix replaces the registry host of the image
+// while its repository, tag and digest are kept. The images already pulled from the mirror are kept as they are, as
+// are all the images when there is no mirror.
+func MirrorImage(mirror, image string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	if mirror == "" || image == "" || strings.HasPrefix(image, mirror+"/") {
+		return image
+	}
+	_, rest := registryHost(image)
+	return mirror + "/" + rest
+}
+
+// RegionalECRRegistry returns the prefix of the repositories the ECR pull through cache rule of ECR Public creates in
+// the private registry of the account in the region, such as 123456789012.dkr.ecr.us-west-2.amazonaws.com/ecr-public.
+func RegionalECRRegistry(region, accountID, cachePrefix string) string {
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+	return fmt.Sprintf("%s.dkr.ecr.%s.%s/%s", accountID, region, domain, strings.Trim(cachePrefix, "/"))
+}
+
+// RegionalImage returns the image pulled from the in-region ECR registry when it is hosted on ECR Public, keeping its
+// repository, tag and digest. The images of the other registries are kept as they are, as are all the images when
+// there is no regional registry.
+func RegionalImage(registry, image string) string {
+	if registry == "" {
+		return image
+	}
+	if host, rest := registryHost(image); host == publicECRHost {
+		return registry + "/" + rest
+	}
+	return image
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorImage(t *testing.T) {
+	for _, tt := range []struct {
+		mirror   string
+		image    string
+		expected string
+	}{
+		{mirror: "", image: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0", expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0"},
+		{mirror: "registry.example.com/mirror", image: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0", expected: "registry.example.com/mirror/cloudwatch-agent/cloudwatch-agent:1.0"},
+		{mirror: "registry.example.com/mirror/", image: "nvcr.io/nvidia/k8s/dcgm-exporter@sha256:abc", expected: "registry.example.com/mirror/nvidia/k8s/dcgm-exporter@sha256:abc"},
+		{mirror: "registry.example.com:5000", image: "localhost:5000/agent:1.0", expected: "registry.example.com:5000/agent:1.0"},
+		{mirror: "registry.example.com", image: "amazon/cloudwatch-agent:1.0", expected: "registry.example.com/amazon/cloudwatch-agent:1.0"},
+		{mirror: "registry.example.com", image: "busybox", expected: "registry.example.com/busybox"},
+		{mirror: "registry.example.com", image: "registry.example.com/cloudwatch-agent:1.0", expected: "registry.example.com/cloudwatch-agent:1.0"},
+		{mirror: "registry.example.com", image: "", expected: ""},
+	} {
+		assert.Equal(t, tt.expected, MirrorImage(tt.mirror, tt.image), tt.image)
+	}
+}
+
+func TestRegionalECRRegistry(t *testing.T) {
+	assert.Equal(t, "123456789012.dkr.ecr.us-west-2.amazonaws.com/ecr-public", RegionalECRRegistry("us-west-2", "123456789012", "ecr-public"))
+	assert.Equal(t, "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn/public", RegionalECRRegistry("cn-north-1", "123456789012", "/public/"))
+}
+
+func TestRegionalImage(t *testing.T) {
+	registry := "123456789012.dkr.ecr.us-west-2.amazonaws.com/ecr-public"
+	for _, tt := range []struct {
+		registry string
+		image    string
+		expected string
+	}{
+		{registry: "", image: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0", expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0"},
+		{registry: registry, image: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0", expected: registry + "/cloudwatch-agent/cloudwatch-agent:1.0"},
+		{registry: registry, image: "public.ecr.aws/neuron@sha256:abc", expected: registry + "/neuron@sha256:abc"},
+		{registry: registry, image: "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-nodejs:1.0", expected: "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-nodejs:1.0"},
+		{registry: registry, image: registry + "/cloudwatch-agent/cloudwatch-agent:1.0", expected: registry + "/cloudwatch-agent/cloudwatch-agent:1.0"},
+		{registry: registry, image: "", expected: ""},
+	} {
+		assert.Equal(t, tt.expected, RegionalImage(tt.registry, tt.image), tt.image)
+	}
+}
@@ -5,7 +5,13 @@
 package config
 
 import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
@@ -18,11 +24,53 @@ const (
 	defaultPrometheusConfigMapEntry      = "prometheus.yaml"
 )
 
+// AdmissionImageVerificationTimeout bounds the verification of the images the webhooks inject, well under the 10
+// seconds the API server waits for the webhooks, so that a slow registry refuses the injection rather than failing
+// the admission of the pod.
+const AdmissionImageVerificationTimeout = 4 * time.Second
+
+// The cloud providers of the cluster. The resource detectors of the injected SDKs which read the instance metadata
+// (IMDS) are only enabled on AWS.
+const (
+	CloudProviderAWS        = "aws"
+	CloudProviderGCP        = "gcp"
+	CloudProviderAzure      = "azure"
+	CloudProviderOnPremises = "on-premises"
+)
+
+// CloudProviders are the supported cloud providers of the cluster.
+var CloudProviders = []string{CloudProviderAWS, CloudProviderGCP, CloudProviderAzure, CloudProviderOnPremises}
+
+// ImageVerifier verifies the signatures of the images before the operator deploys them or injects them into the
+// workloads, returning the references of the images by the digests it verified. The registries are read with the
+// credentials the pods pull the images with.
+type ImageVerifier interface {
+	Verify(ctx context.Context, image string, pull ImagePull) (string, error)
+}
+
+// ImagePull is how the pods pull their images: the image pull secrets of their spec and of their service account in
+// their namespace. The zero value pulls without the credentials of a pod.
+type ImagePull struct {
+	Namespace          string
+	ServiceAccountName string
+	ImagePullSecrets   []string
+}
+
+// NewImagePull returns how the pods of the spec pull their images in the namespace.
+func NewImagePull(namespace string, podSpec corev1.PodSpec) ImagePull {
+	pull := ImagePull{Namespace: namespace, ServiceAccountName: podSpec.ServiceAccountName}
+	for _, secret := range podSpec.ImagePullSecrets {
+		pull.ImagePullSecrets = append(pull.ImagePullSecrets, secret.Name)
+	}
+	return pull
+}
+
 // Config holds the static configuration for this operator.
 type Config struct {
 	logger                              logr.Logger
 	autoInstrumentationPythonImage      string
 	collectorImage                      string
+	collectorFIPSImage                  string
 	collectorConfigMapEntry             string
 	otelCollectorConfigMapEntry         string
 	autoInstrumentationDotNetImage      string
@@ -34,9 +82,34 @@ type Config struct {
 	dcgmExporterImage                   string
 	neuronMonitorImage                  string
 	targetAllocatorImage                string
+	fluentBitImage                      string
+	prometheusConfigReloaderImage       string
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	legacyAgentRBAC                     bool
+	watchNamespaces                     []string
+	fargateAgent                        string
+	cloudProvider                       string
+	clusterName                         string
+	awsRegion                           string
+	awsAccountID                        string
+	imageRegistry                       string
+	imagePullSecrets                    []string
+	regionalRegistry                    string
+	imageVerifier                       ImageVerifier
+	// defaults are the defaults of the operator configuration document of the flags, which the defaults changed at
+	// runtime override.
+	defaults Defaults
+
+	// runtime is shared by the copies of the configuration, so that they all see the defaults changed at runtime.
+	runtime *runtimeDefaults
+}
+
+// runtimeDefaults holds the defaults of the operator configuration ConfigMap.
+type runtimeDefaults struct {
+	defaults atomic.Pointer[Defaults]
+	onChange changeHandler
 }
 
 // New constructs a new configuration based on the given options.
@@ -53,26 +126,103 @@ func New(opts ...Option) Config {
 	for _, opt := range opts {
 		opt(&o)
 	}
+	// the registry mirror of the air-gapped clusters takes precedence over the in-region registry
+	mirror := func(image string) string {
+		if o.imageRegistry != "" {
+			return MirrorImage(o.imageRegistry, image)
+		}
+		return RegionalImage(o.regionalRegistry, image)
+	}
 
 	return Config{
-		collectorImage:                      o.collectorImage,
+		collectorImage:                      mirror(o.collectorImage),
+		collectorFIPSImage:                  mirror(o.collectorFIPSImage),
 		collectorConfigMapEntry:             o.collectorConfigMapEntry,
 		otelCollectorConfigMapEntry:         o.otelCollectorConfigMapEntry,
 		logger:                              o.logger,
-		autoInstrumentationJavaImage:        o.autoInstrumentationJavaImage,
-		autoInstrumentationNodeJSImage:      o.autoInstrumentationNodeJSImage,
-		autoInstrumentationPythonImage:      o.autoInstrumentationPythonImage,
-		autoInstrumentationDotNetImage:      o.autoInstrumentationDotNetImage,
-		autoInstrumentationGoImage:          o.autoInstrumentationGoImage,
-		autoInstrumentationApacheHttpdImage: o.autoInstrumentationApacheHttpdImage,
-		autoInstrumentationNginxImage:       o.autoInstrumentationNginxImage,
-		dcgmExporterImage:                   o.dcgmExporterImage,
-		neuronMonitorImage:                  o.neuronMonitorImage,
-		targetAllocatorImage:                o.targetAllocatorImage,
+		autoInstrumentationJavaImage:        mirror(o.autoInstrumentationJavaImage),
+		autoInstrumentationNodeJSImage:      mirror(o.autoInstrumentationNodeJSImage),
+		autoInstrumentationPythonImage:      mirror(o.autoInstrumentationPythonImage),
+		autoInstrumentationDotNetImage:      mirror(o.autoInstrumentationDotNetImage),
+		autoInstrumentationGoImage:          mirror(o.autoInstrumentationGoImage),
+		autoInstrumentationApacheHttpdImage: mirror(o.autoInstrumentationApacheHttpdImage),
+		autoInstrumentationNginxImage:       mirror(o.autoInstrumentationNginxImage),
+		dcgmExporterImage:                   mirror(o.dcgmExporterImage),
+		neuronMonitorImage:                  mirror(o.neuronMonitorImage),
+		targetAllocatorImage:                mirror(o.targetAllocatorImage),
+		fluentBitImage:                      mirror(o.fluentBitImage),
+		prometheusConfigReloaderImage:       mirror(o.prometheusConfigReloaderImage),
 		targetAllocatorConfigMapEntry:       o.targetAllocatorConfigMapEntry,
 		prometheusConfigMapEntry:            o.prometheusConfigMapEntry,
 		labelsFilter:                        o.labelsFilter,
+		legacyAgentRBAC:                     o.legacyAgentRBAC,
+		watchNamespaces:                     o.watchNamespaces,
+		fargateAgent:                        o.fargateAgent,
+		cloudProvider:                       o.cloudProvider,
+		clusterName:                         o.clusterName,
+		awsRegion:                           o.awsRegion,
+		awsAccountID:                        o.awsAccountID,
+		imageRegistry:                       o.imageRegistry,
+		imagePullSecrets:                    o.imagePullSecrets,
+		regionalRegistry:                    o.regionalRegistry,
+		imageVerifier:                       o.imageVerifier,
+		defaults:                            o.defaults,
+		runtime:                             &runtimeDefaults{onChange: newOnChange()},
+	}
+}
+
+// Defaults returns the defaults of the operator configuration ConfigMap merged over the ones of the operator
+// configuration document, which override the ones of the flags.
+func (c *Config) Defaults() Defaults {
+	if c.runtime == nil {
+		return c.defaults
 	}
+	if defaults := c.runtime.defaults.Load(); defaults != nil {
+		return *defaults
+	}
+	return c.defaults
+}
+
+// SetDefaults replaces the defaults of the operator configuration ConfigMap, and calls the change callbacks.
+func (c *Config) SetDefaults(defaults Defaults) {
+	if c.runtime == nil {
+		return
+	}
+	merged := c.defaults.merge(defaults)
+	c.runtime.defaults.Store(&merged)
+	_ = c.runtime.onChange.Do()
+}
+
+// RegisterChangeCallback registers a callback called when the defaults are changed at runtime.
+func (c *Config) RegisterChangeCallback(f func() error) {
+	if c.runtime != nil {
+		c.runtime.onChange.Register(f)
+	}
+}
+
+// autoInstrumentationImage returns the image of the language of the operator configuration ConfigMap, or the one of
+// the flags.
+func (c *Config) autoInstrumentationImage(language, image string) string {
+	if override := c.Defaults().AutoInstrumentation[language].Image; override != "" {
+		return override
+	}
+	return image
+}
+
+// AutoInstrumentationResources returns the init container resources of the language of the operator configuration
+// ConfigMap, which are empty when the ConfigMap doesn't set them.
+func (c *Config) AutoInstrumentationResources(language string) corev1.ResourceRequirements {
+	return c.Defaults().AutoInstrumentation[language].Resources
+}
+
+// TrustsEndpoint returns whether the Instrumentation exporters may send their telemetry to the endpoint.
+func (c *Config) TrustsEndpoint(endpoint string) bool {
+	return c.Defaults().trusts(endpoint)
+}
+
+// DeniesNamespace returns whether the pods of the namespace are never injected.
+func (c *Config) DeniesNamespace(namespace string) bool {
+	return c.Defaults().denies(namespace)
 }
 
 // CollectorImage represents the flag to override the OpenTelemetry Collector container image.
@@ -80,6 +230,11 @@ func (c *Config) CollectorImage() string {
 	return c.collectorImage
 }
 
+// CollectorFIPSImage returns the FIPS validated agent image of the instances running in FIPS mode.
+func (c *Config) CollectorFIPSImage() string {
+	return c.collectorFIPSImage
+}
+
 // CollectorConfigMapEntry represents the configuration JSON file name for the collector. Immutable.
 func (c *Config) CollectorConfigMapEntry() string {
 	return c.collectorConfigMapEntry
@@ -92,37 +247,37 @@ func (c *Config) OtelCollectorConfigMapEntry() string {
 
 // AutoInstrumentationJavaImage returns OpenTelemetry Java auto-instrumentation container image.
 func (c *Config) AutoInstrumentationJavaImage() string {
-	return c.autoInstrumentationJavaImage
+	return c.autoInstrumentationImage(LanguageJava, c.autoInstrumentationJavaImage)
 }
 
 // AutoInstrumentationNodeJSImage returns OpenTelemetry NodeJS auto-instrumentation container image.
 func (c *Config) AutoInstrumentationNodeJSImage() string {
-	return c.autoInstrumentationNodeJSImage
+	return c.autoInstrumentationImage(LanguageNodeJS, c.autoInstrumentationNodeJSImage)
 }
 
 // AutoInstrumentationPythonImage returns OpenTelemetry Python auto-instrumentation container image.
 func (c *Config) AutoInstrumentationPythonImage() string {
-	return c.autoInstrumentationPythonImage
+	return c.autoInstrumentationImage(LanguagePython, c.autoInstrumentationPythonImage)
 }
 
 // AutoInstrumentationDotNetImage returns OpenTelemetry DotNet auto-instrumentation container image.
 func (c *Config) AutoInstrumentationDotNetImage() string {
-	return c.autoInstrumentationDotNetImage
+	return c.autoInstrumentationImage(LanguageDotNet, c.autoInstrumentationDotNetImage)
 }
 
 // AutoInstrumentationGoImage returns OpenTelemetry Go auto-instrumentation container image.
 func (c *Config) AutoInstrumentationGoImage() string {
-	return c.autoInstrumentationGoImage
+	return c.autoInstrumentationImage(LanguageGo, c.autoInstrumentationGoImage)
 }
 
 // AutoInstrumentationApacheHttpdImage returns OpenTelemetry ApacheHttpd auto-instrumentation container image.
 func (c *Config) AutoInstrumentationApacheHttpdImage() string {
-	return c.autoInstrumentationApacheHttpdImage
+	return c.autoInstrumentationImage(LanguageApacheHttpd, c.autoInstrumentationApacheHttpdImage)
 }
 
 // AutoInstrumentationNginxImage returns OpenTelemetry Nginx auto-instrumentation container image.
 func (c *Config) AutoInstrumentationNginxImage() string {
-	return c.autoInstrumentationNginxImage
+	return c.autoInstrumentationImage(LanguageNginx, c.autoInstrumentationNginxImage)
 }
 
 // DcgmExporterImage returns Nvidia DCGM Exporter container image.
@@ -140,6 +295,75 @@ func (c *Config) TargetAllocatorImage() string {
 	return c.targetAllocatorImage
 }
 
+// FluentBitImage returns the Fluent Bit image collecting the container logs of the instances which do not specify one.
+func (c *Config) FluentBitImage() string {
+	return c.fluentBitImage
+}
+
+// PrometheusConfigReloaderImage returns the image of the sidecar reloading the Prometheus configuration of the agents.
+func (c *Config) PrometheusConfigReloaderImage() string {
+	return c.prometheusConfigReloaderImage
+}
+
+// ImageRegistry returns the registry mirror the default images are pulled from, or an empty string when they are
+// pulled from their own registries.
+func (c *Config) ImageRegistry() string {
+	return c.imageRegistry
+}
+
+// RegionalRegistry returns the in-region ECR registry the default images hosted on ECR Public are pulled from, or an
+// empty string when they are pulled from ECR Public.
+func (c *Config) RegionalRegistry() string {
+	return c.regionalRegistry
+}
+
+// VerifiesImages returns whether the signatures of the images are verified before they are deployed or injected.
+func (c *Config) VerifiesImages() bool {
+	return c.imageVerifier != nil
+}
+
+// VerifyImages returns the references of the images by the digests whose signatures verified, by image, or an error
+// when the signature of one of the images doesn't verify. The images are deployed or injected by these references,
+// so that a tag moved after the verification doesn't pull an unverified image. The registries are read with the
+// credentials the images are pulled with. All the images are accepted as they are when no verifier is configured.
+func (c *Config) VerifyImages(ctx context.Context, images []string, pull ImagePull) (map[string]string, error) {
+	if c.imageVerifier == nil {
+		return nil, nil
+	}
+	pinned := make(map[string]string, len(images))
+	for _, image := range images {
+		ref, err := c.imageVerifier.Verify(ctx, image, pull)
+		if err != nil {
+			return nil, err
+		}
+		pinned[image] = ref
+	}
+	return pinned, nil
+}
+
+// DefaultImages returns the distinct default images the operator deploys or injects.
+func (c *Config) DefaultImages() []string {
+	var images []string
+	for _, image := range []string{
+		c.CollectorImage(), c.CollectorFIPSImage(), c.TargetAllocatorImage(), c.FluentBitImage(),
+		c.PrometheusConfigReloaderImage(), c.DcgmExporterImage(), c.NeuronMonitorImage(),
+		c.AutoInstrumentationJavaImage(), c.AutoInstrumentationPythonImage(), c.AutoInstrumentationDotNetImage(),
+		c.AutoInstrumentationNodeJSImage(), c.AutoInstrumentationGoImage(), c.AutoInstrumentationApacheHttpdImage(),
+		c.AutoInstrumentationNginxImage(),
+	} {
+		if image != "" && !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// ImagePullSecrets returns the names of the image pull secrets, in the namespace of each workload, attached to the
+// pods the operator creates or instruments and to the service accounts it creates.
+func (c *Config) ImagePullSecrets() []string {
+	return c.imagePullSecrets
+}
+
 // TargetAllocatorConfigMapEntry represents the configuration file name for the TargetAllocator. Immutable.
 func (c *Config) TargetAllocatorConfigMapEntry() string {
 	return c.targetAllocatorConfigMapEntry
@@ -152,3 +376,65 @@ func (c *Config) PrometheusConfigMapEntry() string { return c.prometheusConfigMa
 func (c *Config) LabelsFilter() []string {
 	return c.labelsFilter
 }
+
+// LegacyAgentRBAC returns whether the agents are granted the cluster permissions of all the agent features, rather
+// than the permissions their configuration needs.
+func (c *Config) LegacyAgentRBAC() bool {
+	return c.legacyAgentRBAC
+}
+
+// WatchNamespaces returns the namespaces the operator watches, or nil when it watches all of them.
+func (c *Config) WatchNamespaces() []string {
+	return c.watchNamespaces
+}
+
+// NamespaceScoped returns whether the operator only watches a set of namespaces, in which case it has no cluster
+// permissions to grant and grants the agents the permissions of their namespace only.
+func (c *Config) NamespaceScoped() bool {
+	return len(c.watchNamespaces) > 0
+}
+
+// FargateAgent returns the namespace/name of the sidecar mode agent injected into the instrumented Fargate pods, or
+// an empty string when the Fargate pods are not given an agent sidecar.
+func (c *Config) FargateAgent() string {
+	return c.fargateAgent
+}
+
+// CloudProvider returns the cloud provider of the cluster, AWS unless another one is set.
+func (c *Config) CloudProvider() string {
+	if c.cloudProvider == "" {
+		return CloudProviderAWS
+	}
+	return c.cloudProvider
+}
+
+// RunsOnAWS returns whether the cluster runs on AWS, where the instance metadata (IMDS) is available.
+func (c *Config) RunsOnAWS() bool {
+	return c.CloudProvider() == CloudProviderAWS
+}
+
+// ClusterName returns the name of the cluster set by hand, or an empty string when it is detected.
+func (c *Config) ClusterName() string {
+	return c.clusterName
+}
+
+// InjectsAWSResource returns whether the AWS region and account of the cluster are injected into the instrumented
+// workloads, whose SDKs then don't read them from the instance metadata.
+func (c *Config) InjectsAWSResource() bool {
+	return c.awsRegion != ""
+}
+
+// AWSRegion returns the region injected into the instrumented workloads, or an empty string when it isn't injected.
+func (c *Config) AWSRegion() string {
+	return c.awsRegion
+}
+
+// AWSAccountID returns the account injected into the instrumented workloads, or an empty string when it isn't known.
+func (c *Config) AWSAccountID() string {
+	return c.awsAccountID
+}
+
+// Watches returns whether the operator watches the given namespace.
+func (c *Config) Watches(namespace string) bool {
+	return !c.NamespaceScoped() || slices.Contains(c.watchNamespaces, namespace)
+}
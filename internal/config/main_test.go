@@ -4,9 +4,13 @@
 package config_test
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 )
@@ -15,16 +19,136 @@ func TestNewConfig(t *testing.T) {
 	// prepare
 	cfg := config.New(
 		config.WithCollectorImage("some-image"),
+		config.WithCollectorFIPSImage("some-fips-image"),
 		config.WithCollectorConfigMapEntry("some-config.json"),
 		config.WithOtelCollectorConfigMapEntry("some-otel-config.yaml"),
 		config.WithTargetAllocatorConfigMapEntry("some-ta-config.yaml"),
 		config.WithPrometheusConfigMapEntry("some-prom-config.yaml"),
+		config.WithLegacyAgentRBAC(true),
+		config.WithFargateAgent("amazon-cloudwatch/cloudwatch-agent-fargate"),
+		config.WithCloudProvider(config.CloudProviderGCP),
+		config.WithClusterName("gke-cluster"),
+		config.WithAWSResource("us-west-2", "123456789012"),
 	)
 
 	// test
 	assert.Equal(t, "some-image", cfg.CollectorImage())
+	assert.Equal(t, "some-fips-image", cfg.CollectorFIPSImage())
 	assert.Equal(t, "some-config.json", cfg.CollectorConfigMapEntry())
 	assert.Equal(t, "some-otel-config.yaml", cfg.OtelCollectorConfigMapEntry())
 	assert.Equal(t, "some-ta-config.yaml", cfg.TargetAllocatorConfigMapEntry())
 	assert.Equal(t, "some-prom-config.yaml", cfg.PrometheusConfigMapEntry())
+	assert.True(t, cfg.LegacyAgentRBAC())
+	assert.Equal(t, "amazon-cloudwatch/cloudwatch-agent-fargate", cfg.FargateAgent())
+	assert.Equal(t, config.CloudProviderGCP, cfg.CloudProvider())
+	assert.False(t, cfg.RunsOnAWS())
+	assert.Equal(t, "gke-cluster", cfg.ClusterName())
+	assert.True(t, cfg.InjectsAWSResource())
+	assert.Equal(t, "us-west-2", cfg.AWSRegion())
+	assert.Equal(t, "123456789012", cfg.AWSAccountID())
+	assert.False(t, cfg.NamespaceScoped())
+	assert.True(t, cfg.Watches("team-a"))
+}
+
+func TestImageRegistry(t *testing.T) {
+	cfg := config.New(
+		config.WithImageRegistry("registry.example.com/mirror"),
+		config.WithCollectorImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0"),
+		config.WithAutoInstrumentationJavaImage("public.ecr.aws/aws-observability/adot-autoinstrumentation-java:2.0"),
+		config.WithTargetAllocatorImage("registry.example.com/mirror/target-allocator:1.0"),
+	)
+
+	assert.Equal(t, "registry.example.com/mirror", cfg.ImageRegistry())
+	assert.Equal(t, "registry.example.com/mirror/cloudwatch-agent/cloudwatch-agent:1.0", cfg.CollectorImage())
+	assert.Equal(t, "registry.example.com/mirror/aws-observability/adot-autoinstrumentation-java:2.0", cfg.AutoInstrumentationJavaImage())
+	assert.Equal(t, "registry.example.com/mirror/target-allocator:1.0", cfg.TargetAllocatorImage())
+	assert.Empty(t, cfg.FluentBitImage())
+}
+
+func TestDefaultCloudProvider(t *testing.T) {
+	cfg := config.New()
+
+	assert.Equal(t, config.CloudProviderAWS, cfg.CloudProvider())
+	assert.True(t, cfg.RunsOnAWS())
+	assert.Empty(t, cfg.ClusterName())
+	assert.False(t, cfg.InjectsAWSResource())
+}
+
+func TestNamespaceScopedConfig(t *testing.T) {
+	cfg := config.New(config.WithWatchNamespaces([]string{"team-a", "team-b"}))
+
+	assert.True(t, cfg.NamespaceScoped())
+	assert.Equal(t, []string{"team-a", "team-b"}, cfg.WatchNamespaces())
+	assert.True(t, cfg.Watches("team-b"))
+	assert.False(t, cfg.Watches("team-c"))
+}
+
+func TestRegionalRegistry(t *testing.T) {
+	registry := "123456789012.dkr.ecr.us-west-2.amazonaws.com/ecr-public"
+	cfg := config.New(
+		config.WithRegionalRegistry(registry),
+		config.WithCollectorImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0"),
+		config.WithDcgmExporterImage("nvcr.io/nvidia/k8s/dcgm-exporter:1.0"),
+	)
+
+	assert.Equal(t, registry, cfg.RegionalRegistry())
+	assert.Equal(t, registry+"/cloudwatch-agent/cloudwatch-agent:1.0", cfg.CollectorImage())
+	assert.Equal(t, "nvcr.io/nvidia/k8s/dcgm-exporter:1.0", cfg.DcgmExporterImage())
+
+	// the registry mirror takes precedence
+	cfg = config.New(
+		config.WithRegionalRegistry(registry),
+		config.WithImageRegistry("registry.example.com/mirror"),
+		config.WithCollectorImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.0"),
+	)
+	assert.Equal(t, "registry.example.com/mirror/cloudwatch-agent/cloudwatch-agent:1.0", cfg.CollectorImage())
+}
+
+type fakeImageVerifier struct {
+	signed []string
+	// secret is the image pull secret the signed images are read with
+	secret string
+}
+
+func (v fakeImageVerifier) Verify(_ context.Context, image string, pull config.ImagePull) (string, error) {
+	if !slices.Contains(v.signed, image) || !slices.Contains(pull.ImagePullSecrets, v.secret) {
+		return "", errors.New("unsigned")
+	}
+	return image + "@sha256:signed", nil
+}
+
+func TestVerifyImage(t *testing.T) {
+	cfg := config.New()
+	assert.False(t, cfg.VerifiesImages())
+	pinned, err := cfg.VerifyImages(context.Background(), []string{"unsigned:1.0"}, config.ImagePull{})
+	assert.NoError(t, err)
+	assert.Nil(t, pinned)
+
+	cfg = config.New(config.WithImageVerifier(fakeImageVerifier{signed: []string{"signed:1.0"}, secret: "registry"}))
+	assert.True(t, cfg.VerifiesImages())
+	pull := config.NewImagePull("app", corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}}})
+	pinned, err = cfg.VerifyImages(context.Background(), []string{"signed:1.0"}, pull)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"signed:1.0": "signed:1.0@sha256:signed"}, pinned)
+	_, err = cfg.VerifyImages(context.Background(), []string{"signed:1.0", "unsigned:1.0"}, pull)
+	assert.Error(t, err)
+	_, err = cfg.VerifyImages(context.Background(), []string{"signed:1.0"}, config.ImagePull{Namespace: "app"})
+	assert.Error(t, err)
+}
+
+func TestNewImagePull(t *testing.T) {
+	pull := config.NewImagePull("app", corev1.PodSpec{
+		ServiceAccountName: "app",
+		ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}},
+	})
+	assert.Equal(t, config.ImagePull{Namespace: "app", ServiceAccountName: "app", ImagePullSecrets: []string{"registry", "mirror"}}, pull)
+}
+
+func TestDefaultImages(t *testing.T) {
+	cfg := config.New(
+		config.WithCollectorImage("cloudwatch-agent:1.0"),
+		config.WithCollectorFIPSImage("cloudwatch-agent:1.0"),
+		config.WithAutoInstrumentationJavaImage("adot-java:1.0"),
+	)
+	assert.Equal(t, []string{"cloudwatch-agent:1.0", "adot-java:1.0"}, cfg.DefaultImages())
 }
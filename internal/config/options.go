@@ -26,14 +26,29 @@ type options struct {
 	autoInstrumentationApacheHttpdImage string
 	autoInstrumentationNginxImage       string
 	collectorImage                      string
+	collectorFIPSImage                  string
 	collectorConfigMapEntry             string
 	otelCollectorConfigMapEntry         string
 	dcgmExporterImage                   string
 	neuronMonitorImage                  string
 	targetAllocatorImage                string
+	fluentBitImage                      string
+	prometheusConfigReloaderImage       string
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	legacyAgentRBAC                     bool
+	watchNamespaces                     []string
+	fargateAgent                        string
+	cloudProvider                       string
+	clusterName                         string
+	awsRegion                           string
+	awsAccountID                        string
+	imageRegistry                       string
+	imagePullSecrets                    []string
+	regionalRegistry                    string
+	imageVerifier                       ImageVerifier
+	defaults                            Defaults
 }
 
 func WithCollectorImage(s string) Option {
@@ -41,6 +56,95 @@ func WithCollectorImage(s string) Option {
 		o.collectorImage = s
 	}
 }
+func WithCollectorFIPSImage(s string) Option {
+	return func(o *options) {
+		o.collectorFIPSImage = s
+	}
+}
+
+// WithLegacyAgentRBAC grants every agent the cluster permissions of all the agent features.
+func WithLegacyAgentRBAC(legacy bool) Option {
+	return func(o *options) {
+		o.legacyAgentRBAC = legacy
+	}
+}
+
+// WithWatchNamespaces restricts the operator to the given namespaces.
+func WithWatchNamespaces(namespaces []string) Option {
+	return func(o *options) {
+		o.watchNamespaces = namespaces
+	}
+}
+
+// WithFargateAgent sets the namespace/name of the sidecar mode agent injected into the instrumented Fargate pods.
+func WithFargateAgent(name string) Option {
+	return func(o *options) {
+		o.fargateAgent = name
+	}
+}
+
+// WithCloudProvider sets the cloud provider of the cluster, one of CloudProviders.
+func WithCloudProvider(provider string) Option {
+	return func(o *options) {
+		o.cloudProvider = provider
+	}
+}
+
+// WithClusterName sets the name of the cluster, for the clusters whose name can't be detected.
+func WithClusterName(name string) Option {
+	return func(o *options) {
+		o.clusterName = name
+	}
+}
+
+// WithAWSResource sets the AWS region and account injected into the instrumented workloads. Nothing is injected
+// without a region.
+func WithAWSResource(region, accountID string) Option {
+	return func(o *options) {
+		o.awsRegion = region
+		o.awsAccountID = accountID
+	}
+}
+
+// WithImageRegistry sets the registry mirror, such as registry.example.com/mirror, which replaces the registry of the
+// default images.
+func WithImageRegistry(registry string) Option {
+	return func(o *options) {
+		o.imageRegistry = registry
+	}
+}
+
+// WithRegionalRegistry sets the in-region ECR registry, such as
+// 123456789012.dkr.ecr.us-west-2.amazonaws.com/ecr-public, which replaces ECR Public in the default images.
+func WithRegionalRegistry(registry string) Option {
+	return func(o *options) {
+		o.regionalRegistry = registry
+	}
+}
+
+// WithImageVerifier sets the verifier of the signatures of the images the operator deploys or injects.
+func WithImageVerifier(verifier ImageVerifier) Option {
+	return func(o *options) {
+		o.imageVerifier = verifier
+	}
+}
+
+// WithImagePullSecrets sets the names of the image pull secrets attached to the pods and service accounts of the
+// operator.
+func WithImagePullSecrets(secrets []string) Option {
+	return func(o *options) {
+		o.imagePullSecrets = secrets
+	}
+}
+
+// WithDefaults sets the defaults of the operator configuration document, which the defaults changed at runtime
+// override.
+func WithDefaults(defaults Defaults) Option {
+	return func(o *options) {
+		o.defaults = defaults
+	}
+}
+
 func WithCollectorConfigMapEntry(s string) Option {
 	return func(o *options) {
 		o.collectorConfigMapEntry = s
@@ -132,6 +236,18 @@ func WithTargetAllocatorImage(s string) Option {
 	}
 }
 
+func WithFluentBitImage(s string) Option {
+	return func(o *options) {
+		o.fluentBitImage = s
+	}
+}
+
+func WithPrometheusConfigReloaderImage(s string) Option {
+	return func(o *options) {
+		o.prometheusConfigReloaderImage = s
+	}
+}
+
 func WithLabelFilters(labelFilters []string) Option {
 	return func(o *options) {
 
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// DefaultReloadInterval is the interval between two reads of the operator configuration ConfigMap.
+const DefaultReloadInterval = 30 * time.Second
+
+var _ manager.Runnable = (*DefaultsReloader)(nil)
+
+// DefaultsReloader reads the operator configuration ConfigMap at every interval, and applies its defaults to the
+// configuration when they change. The defaults are reset to the ones of the flags when the ConfigMap is deleted.
+type DefaultsReloader struct {
+	reader   client.Reader
+	key      types.NamespacedName
+	cfg      Config
+	registry *featuregate.Registry
+	interval time.Duration
+	log      logr.Logger
+
+	// data is the content of the ConfigMap entry which was read last.
+	data   string
+	loaded bool
+	// flagGates are the states of the feature gates overridden by the ConfigMap before they were overridden, so that
+	// they are restored when the ConfigMap no longer overrides them.
+	flagGates map[string]bool
+}
+
+// NewDefaultsReloader returns the reloader of the defaults of the ConfigMap, which overrides the feature gates of the
+// registry.
+func NewDefaultsReloader(reader client.Reader, key types.NamespacedName, cfg Config, registry *featuregate.Registry, interval time.Duration, log logr.Logger) *DefaultsReloader {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	return &DefaultsReloader{
+		reader:    reader,
+		key:       key,
+		cfg:       cfg,
+		registry:  registry,
+		interval:  interval,
+		log:       log,
+		flagGates: map[string]bool{},
+	}
+}
+
+// NeedLeaderElection is false, as every replica of the operator defaults the resources it admits.
+func (r *DefaultsReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reloads the defaults at every interval until the context is done.
+func (r *DefaultsReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Reload(ctx); err != nil {
+			r.log.Error(err, "failed to reload the operator configuration, keeping the previous defaults", "configmap", r.key.String())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reload reads the ConfigMap and applies its defaults when they changed since the previous read. Invalid defaults
+// are reported once and leave the previous ones in place.
+func (r *DefaultsReloader) Reload(ctx context.Context) error {
+	data := ""
+	cm := &corev1.ConfigMap{}
+	if err := r.reader.Get(ctx, r.key, cm); err == nil {
+		data = cm.Data[DefaultsConfigMapEntry]
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	if r.loaded && data == r.data {
+		return nil
+	}
+	r.loaded, r.data = true, data
+
+	defaults, err := ParseDefaults(data)
+	if err != nil {
+		return err
+	}
+	if err := r.applyFeatureGates(defaults.FeatureGates); err != nil {
+		return err
+	}
+	r.cfg.SetDefaults(defaults)
+	r.log.Info("applied the operator configuration", "configmap", r.key.String())
+	return nil
+}
+
+// applyFeatureGates overrides the feature gates of the registry, and restores the ones which are no longer
+// overridden. None of the gates are changed when one of them is unknown.
+func (r *DefaultsReloader) applyFeatureGates(gates []string) error {
+	current := map[string]bool{}
+	r.registry.VisitAll(func(g *featuregate.Gate) {
+		current[g.ID()] = g.IsEnabled()
+	})
+	overrides := map[string]bool{}
+	for _, gate := range gates {
+		id, enabled := ParseFeatureGate(gate)
+		if _, ok := current[id]; !ok {
+			return fmt.Errorf("invalid operator configuration: no such feature gate %q", id)
+		}
+		overrides[id] = enabled
+	}
+
+	for id, enabled := range r.flagGates {
+		if _, ok := overrides[id]; !ok {
+			if err := r.registry.Set(id, enabled); err != nil {
+				return err
+			}
+			delete(r.flagGates, id)
+		}
+	}
+	for id, enabled := range overrides {
+		if _, ok := r.flagGates[id]; !ok {
+			r.flagGates[id] = current[id]
+		}
+		if err := r.registry.Set(id, enabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
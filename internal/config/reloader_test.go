@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestDefaultsReloader(t *testing.T) {
+	ctx := context.Background()
+	registry := featuregate.NewRegistry()
+	gate := registry.MustRegister("operator.test", featuregate.StageBeta)
+	key := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "amazon-cloudwatch-agent-operator-config"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data: map[string]string{config.DefaultsConfigMapEntry: `
+autoInstrumentation:
+  python:
+    image: python:v2
+featureGates:
+- -operator.test
+`},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+	cfg := config.New(config.WithAutoInstrumentationPythonImage("python:v1"))
+	reloader := config.NewDefaultsReloader(c, key, cfg, registry, 0, logr.Discard())
+
+	// the ConfigMap overrides the defaults of the flags
+	require.NoError(t, reloader.Reload(ctx))
+	assert.Equal(t, "python:v2", cfg.AutoInstrumentationPythonImage())
+	assert.False(t, gate.IsEnabled())
+
+	// invalid defaults leave the previous ones in place
+	cm.Data[config.DefaultsConfigMapEntry] = "featureGates:\n- operator.unknown\n"
+	require.NoError(t, c.Update(ctx, cm))
+	assert.Error(t, reloader.Reload(ctx))
+	assert.Equal(t, "python:v2", cfg.AutoInstrumentationPythonImage())
+	assert.False(t, gate.IsEnabled())
+	// and are reported once
+	assert.NoError(t, reloader.Reload(ctx))
+
+	// the deletion of the ConfigMap restores the defaults of the flags
+	require.NoError(t, c.Delete(ctx, cm))
+	require.NoError(t, reloader.Reload(ctx))
+	assert.Equal(t, "python:v1", cfg.AutoInstrumentationPythonImage())
+	assert.True(t, gate.IsEnabled())
+}
@@ -0,0 +1,269 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package instrumentationstatus reports which workloads of a cluster are auto-instrumented, with which languages and
+// images, and why the others are not. It reads the inject annotations of the workloads and of their namespaces, the
+// markers of the auto-monitor, and the injection status recorded on the pods by the webhook.
+package instrumentationstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+)
+
+const (
+	// SourceAnnotation is the source of the instrumentations requested by the inject annotations.
+	SourceAnnotation = "annotation"
+	// SourceAutoMonitor is the source of the instrumentations requested by the auto-monitor or the auto-annotation.
+	SourceAutoMonitor = "auto-monitor"
+
+	// OutputTable prints a table of the workloads, OutputWide adds the images, and OutputJSON prints them as JSON.
+	OutputTable = ""
+	OutputWide  = "wide"
+	OutputJSON  = "json"
+)
+
+// Workload is the instrumentation status of a Deployment, a StatefulSet or a DaemonSet.
+type Workload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Requested are the languages requested by the annotations of the pod template and of the namespace.
+	Requested []string `json:"requested,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	// Instrumentations are the instrumentations injected into the pods.
+	Instrumentations []Instrumentation `json:"instrumentations,omitempty"`
+	Pods             int               `json:"pods"`
+	InstrumentedPods int               `json:"instrumentedPods"`
+	// Reason explains why the workload is not instrumented, or only partially.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Instrumentation is an auto-instrumentation injected into the pods of a workload.
+type Instrumentation struct {
+	Language string `json:"language"`
+	Image    string `json:"image"`
+	Version  string `json:"version,omitempty"`
+}
+
+// Collect returns the instrumentation status of the workloads of the namespace, or of all the namespaces when it is
+// empty, sorted by namespace, kind and name.
+func Collect(ctx context.Context, reader client.Reader, namespace string) ([]Workload, error) {
+	namespaces := map[string]corev1.Namespace{}
+	if namespace != "" {
+		ns := corev1.Namespace{}
+		if err := reader.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+			return nil, err
+		}
+		namespaces[ns.Name] = ns
+	} else {
+		list := &corev1.NamespaceList{}
+		if err := reader.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for _, ns := range list.Items {
+			namespaces[ns.Name] = ns
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := reader.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := reader.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := reader.List(ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := reader.List(ctx, daemonSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var workloads []Workload
+	add := func(kind string, obj metav1.Object, selector *metav1.LabelSelector, template corev1.PodTemplateSpec) error {
+		sel, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector of the %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		var selected []corev1.Pod
+		for _, pod := range pods.Items {
+			if pod.Namespace == obj.GetNamespace() && pod.DeletionTimestamp == nil && sel.Matches(labels.Set(pod.Labels)) {
+				selected = append(selected, pod)
+			}
+		}
+		workload := status(namespaces[obj.GetNamespace()].ObjectMeta, template.ObjectMeta, selected)
+		workload.Kind, workload.Namespace, workload.Name = kind, obj.GetNamespace(), obj.GetName()
+		workloads = append(workloads, workload)
+		return nil
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if err := add("Deployment", d, d.Spec.Selector, d.Spec.Template); err != nil {
+			return nil, err
+		}
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if err := add("StatefulSet", s, s.Spec.Selector, s.Spec.Template); err != nil {
+			return nil, err
+		}
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		if err := add("DaemonSet", d, d.Spec.Selector, d.Spec.Template); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads, nil
+}
+
+// status returns the instrumentation status of the pods of a workload.
+func status(ns metav1.ObjectMeta, template metav1.ObjectMeta, pods []corev1.Pod) Workload {
+	workload := Workload{
+		Requested: instrumentation.RequestedInstrumentations(ns, template),
+		Pods:      len(pods),
+	}
+	if len(workload.Requested) == 0 {
+		workload.Reason = "not annotated"
+		return workload
+	}
+	workload.Source = SourceAnnotation
+	for _, language := range workload.Requested {
+		key := auto.AnnotateKey(instrumentation.Type(language))
+		if _, found := template.Annotations[key]; found {
+			workload.Source = SourceAutoMonitor
+		} else if _, found := ns.Annotations[key]; found {
+			workload.Source = SourceAutoMonitor
+		}
+	}
+
+	images := map[string]string{}
+	var skipped []string
+	var unmutated int
+	for _, pod := range pods {
+		injected := instrumentation.InjectedInstrumentations(pod)
+		for language, image := range injected {
+			images[language] = image
+		}
+		if len(injected) > 0 {
+			workload.InstrumentedPods++
+		}
+		injectionStatus, found := pod.Annotations[podmutation.InjectionStatusAnnotation]
+		if !found && len(injected) == 0 {
+			unmutated++
+		}
+		if _, reason, found := strings.Cut(injectionStatus, instrumentation.InjectionStatusSkipped+": "); found && !slices.Contains(skipped, reason) {
+			skipped = append(skipped, reason)
+		}
+	}
+	for language, image := range images {
+		workload.Instrumentations = append(workload.Instrumentations, Instrumentation{Language: language, Image: image, Version: imageVersion(image)})
+	}
+	sort.Slice(workload.Instrumentations, func(i, j int) bool {
+		return workload.Instrumentations[i].Language < workload.Instrumentations[j].Language
+	})
+
+	var reasons []string
+	switch {
+	case len(pods) == 0:
+		reasons = append(reasons, "no pods")
+	case unmutated > 0:
+		reasons = append(reasons, fmt.Sprintf("%d pods were not mutated by the webhook, restart the workload if they were created before the instrumentation was requested", unmutated))
+	case workload.InstrumentedPods < len(pods) && len(skipped) == 0:
+		reasons = append(reasons, fmt.Sprintf("%d pods are not instrumented", len(pods)-workload.InstrumentedPods))
+	}
+	reasons = append(reasons, skipped...)
+	workload.Reason = strings.Join(reasons, "; ")
+	return workload
+}
+
+// imageVersion returns the tag or the digest of the image.
+func imageVersion(image string) string {
+	if _, digest, found := strings.Cut(image, "@"); found {
+		return digest
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if _, tag, found := strings.Cut(name, ":"); found {
+		return tag
+	}
+	return ""
+}
+
+// Print writes the workloads in the output format.
+func Print(w io.Writer, workloads []Workload, output string) error {
+	switch output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(workloads)
+	case OutputTable, OutputWide:
+	default:
+		return fmt.Errorf("unsupported output %q", output)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	header := "NAMESPACE\tKIND\tNAME\tREQUESTED\tSOURCE\tINSTRUMENTED\tPODS\tREASON"
+	if output == OutputWide {
+		header += "\tIMAGES"
+	}
+	fmt.Fprintln(tw, header)
+	for _, workload := range workloads {
+		var instrumented, images []string
+		for _, inst := range workload.Instrumentations {
+			instrumented = append(instrumented, strings.TrimSuffix(inst.Language+":"+inst.Version, ":"))
+			images = append(images, inst.Image)
+		}
+		row := strings.Join([]string{
+			workload.Namespace,
+			workload.Kind,
+			workload.Name,
+			orNone(strings.Join(workload.Requested, ",")),
+			orNone(workload.Source),
+			orNone(strings.Join(instrumented, ",")),
+			fmt.Sprintf("%d/%d", workload.InstrumentedPods, workload.Pods),
+			orNone(workload.Reason),
+		}, "\t")
+		if output == OutputWide {
+			row += "\t" + orNone(strings.Join(images, ","))
+		}
+		fmt.Fprintln(tw, row)
+	}
+	return tw.Flush()
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentationstatus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+)
+
+const javaImage = "test.registry/adot-autoinstrumentation-java:v1.32.0"
+
+func newPod(name string, labels, annotations map[string]string, initContainers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels, Annotations: annotations},
+		Spec: corev1.PodSpec{
+			InitContainers: initContainers,
+			Containers:     []corev1.Container{{Name: "app", Image: "app:latest"}},
+		},
+	}
+}
+
+func newDeployment(name string, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}, Annotations: annotations},
+			},
+		},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	javaInit := corev1.Container{Name: "opentelemetry-auto-instrumentation-java", Image: javaImage}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		newDeployment("instrumented", map[string]string{
+			"instrumentation.opentelemetry.io/inject-java": "true",
+			"cloudwatch.aws.amazon.com/auto-annotate-java": "true",
+		}),
+		newPod("instrumented-1", map[string]string{"app": "instrumented"},
+			map[string]string{podmutation.InjectionStatusAnnotation: "injected"}, javaInit),
+		newDeployment("restart", map[string]string{"instrumentation.opentelemetry.io/inject-java": "true"}),
+		newPod("restart-1", map[string]string{"app": "restart"}, nil),
+		newDeployment("skipped", map[string]string{"instrumentation.opentelemetry.io/inject-go": "true"}),
+		newPod("skipped-1", map[string]string{"app": "skipped"},
+			map[string]string{podmutation.InjectionStatusAnnotation: "skipped: support for Go auto instrumentation is not enabled"}),
+		newDeployment("plain", nil),
+		newPod("plain-1", map[string]string{"app": "plain"}, nil),
+	).Build()
+
+	workloads, err := Collect(context.Background(), c, "default")
+	require.NoError(t, err)
+	assert.Equal(t, []Workload{
+		{
+			Kind: "Deployment", Namespace: "default", Name: "instrumented",
+			Requested: []string{"java"}, Source: SourceAutoMonitor,
+			Instrumentations: []Instrumentation{{Language: "java", Image: javaImage, Version: "v1.32.0"}},
+			Pods:             1, InstrumentedPods: 1,
+		},
+		{
+			Kind: "Deployment", Namespace: "default", Name: "plain",
+			Pods: 1, Reason: "not annotated",
+		},
+		{
+			Kind: "Deployment", Namespace: "default", Name: "restart",
+			Requested: []string{"java"}, Source: SourceAnnotation,
+			Pods:   1,
+			Reason: "1 pods were not mutated by the webhook, restart the workload if they were created before the instrumentation was requested",
+		},
+		{
+			Kind: "Deployment", Namespace: "default", Name: "skipped",
+			Requested: []string{"go"}, Source: SourceAnnotation,
+			Pods:   1,
+			Reason: "support for Go auto instrumentation is not enabled",
+		},
+	}, workloads)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, Print(out, workloads[:1], OutputWide))
+	assert.Equal(t, "NAMESPACE   KIND         NAME           REQUESTED   SOURCE         INSTRUMENTED   PODS   REASON   IMAGES\n"+
+		"default     Deployment   instrumented   java        auto-monitor   java:v1.32.0   1/1    <none>   "+javaImage+"\n", out.String())
+	assert.ErrorContains(t, Print(out, workloads, "yaml"), `unsupported output "yaml"`)
+}
+
+func TestImageVersion(t *testing.T) {
+	assert.Equal(t, "v1.32.0", imageVersion("public.ecr.aws/aws-observability/adot-autoinstrumentation-java:v1.32.0"))
+	assert.Equal(t, "sha256:abc", imageVersion("localhost:5000/java@sha256:abc"))
+	assert.Equal(t, "", imageVersion("localhost:5000/java"))
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// emfExporterType is the type of the exporter sending the metrics as embedded metric format logs.
+const emfExporterType = "awsemf"
+
+// EMFExporters returns the sorted IDs of the awsemf exporters of the OpenTelemetry configuration.
+func EMFExporters(config map[interface{}]interface{}) []string {
+	exporters, ok := config["exporters"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	var ids []string
+	for key := range exporters {
+		id := fmt.Sprint(key)
+		if exporterType, _, _ := strings.Cut(id, "/"); exporterType == emfExporterType {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEMFExporters(t *testing.T) {
+	config, err := ConfigFromString(`
+exporters:
+  awsemf/prometheus:
+    namespace: Prometheus
+  awsemf:
+  awsemfx:
+  debug:
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"awsemf", "awsemf/prometheus"}, EMFExporters(config))
+
+	config, err = ConfigFromString(`receivers: {}`)
+	require.NoError(t, err)
+	assert.Empty(t, EMFExporters(config))
+}
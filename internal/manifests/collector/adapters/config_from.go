@@ -38,11 +38,16 @@ func ConfigFromJSONString(configStr string) (map[string]interface{}, error) {
 }
 
 type CwaConfig struct {
+	Agent   *agent   `json:"agent,omitempty"`
 	Metrics *Metrics `json:"metrics,omitempty"`
 	Logs    *Logs    `json:"logs,omitempty"`
 	Traces  *Traces  `json:"traces,omitempty"`
 }
 
+type agent struct {
+	Region string `json:"region,omitempty"`
+}
+
 type Metrics struct {
 	MetricsCollected *MetricsCollected `json:"metrics_collected,omitempty"`
 }
@@ -67,6 +72,7 @@ type LogMetricsCollected struct {
 	ApplicationSignals *AppSignals `json:"application_signals,omitempty"`
 	AppSignals         *AppSignals `json:"app_signals,omitempty"`
 	Kubernetes         *kubernetes `json:"kubernetes,omitempty"`
+	Prometheus         *prometheus `json:"prometheus,omitempty"`
 	OTLP               *otlp       `json:"otlp,omitempty"`
 }
 
@@ -94,10 +100,13 @@ type emf struct {
 
 type jmx struct{}
 
+type prometheus struct{}
+
 type kubernetes struct {
-	EnhancedContainerInsights bool `json:"enhanced_container_insights,omitempty"`
-	AcceleratedComputeMetrics bool `json:"accelerated_compute_metrics,omitempty"`
-	JMXContainerInsights      bool `json:"jmx_container_insights,omitempty"`
+	ClusterName               string `json:"cluster_name,omitempty"`
+	EnhancedContainerInsights bool   `json:"enhanced_container_insights,omitempty"`
+	AcceleratedComputeMetrics bool   `json:"accelerated_compute_metrics,omitempty"`
+	JMXContainerInsights      bool   `json:"jmx_container_insights,omitempty"`
 }
 
 type xray struct {
@@ -159,3 +168,28 @@ func (c *CwaConfig) GetApplicationSignalsTracesConfig() *AppSignals {
 	}
 	return nil
 }
+
+// GetKubernetesClusterName returns the cluster name of the kubernetes section of the configuration.
+func (c *CwaConfig) GetKubernetesClusterName() string {
+	if c.Logs == nil || c.Logs.LogMetricsCollected == nil || c.Logs.LogMetricsCollected.Kubernetes == nil {
+		return ""
+	}
+	return c.Logs.LogMetricsCollected.Kubernetes.ClusterName
+}
+
+// IsEnhancedContainerInsightsEnabled tells whether the kubernetes section of the configuration collects the enhanced
+// Container Insights metrics.
+func (c *CwaConfig) IsEnhancedContainerInsightsEnabled() bool {
+	if c.Logs == nil || c.Logs.LogMetricsCollected == nil || c.Logs.LogMetricsCollected.Kubernetes == nil {
+		return false
+	}
+	return c.Logs.LogMetricsCollected.Kubernetes.EnhancedContainerInsights
+}
+
+// GetRegion returns the region of the agent section of the configuration.
+func (c *CwaConfig) GetRegion() string {
+	if c.Agent == nil {
+		return ""
+	}
+	return c.Agent.Region
+}
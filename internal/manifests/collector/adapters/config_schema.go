@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type jsonType string
+
+const (
+	jsonObject  jsonType = "object"
+	jsonArray   jsonType = "array"
+	jsonString  jsonType = "string"
+	jsonNumber  jsonType = "number"
+	jsonBoolean jsonType = "boolean"
+)
+
+// configSchema describes the parts of the agent JSON configuration that are checked before the configuration
+// reaches the agent. Sections map the keys the agent accepts to their type, any other key in a section is
+// reported as a warning since the agent ignores or rejects it depending on its version.
+var configSchema = map[string]map[string]jsonType{
+	"agent": {
+		"aws_sdk_log_level":           jsonString,
+		"credentials":                 jsonObject,
+		"debug":                       jsonBoolean,
+		"deployment.environment":      jsonString,
+		"logfile":                     jsonString,
+		"metrics_collection_interval": jsonNumber,
+		"omit_hostname":               jsonBoolean,
+		"quiet":                       jsonBoolean,
+		"region":                      jsonString,
+		"run_as_user":                 jsonString,
+		"service.name":                jsonString,
+		"usage_data":                  jsonBoolean,
+		"use_dualstack_endpoint":      jsonBoolean,
+		"user_agent":                  jsonString,
+	},
+	"metrics": {
+		"aggregation_dimensions": jsonArray,
+		"append_dimensions":      jsonObject,
+		"credentials":            jsonObject,
+		"endpoint_override":      jsonString,
+		"force_flush_interval":   jsonNumber,
+		"metrics_collected":      jsonObject,
+		"metrics_destinations":   jsonObject,
+		"namespace":              jsonString,
+	},
+	"logs": {
+		"concurrency":          jsonNumber,
+		"credentials":          jsonObject,
+		"endpoint_override":    jsonString,
+		"force_flush_interval": jsonNumber,
+		"log_stream_name":      jsonString,
+		"logs_collected":       jsonObject,
+		"metrics_collected":    jsonObject,
+	},
+	"traces": {
+		"buffer_size_mb":    jsonNumber,
+		"concurrency":       jsonNumber,
+		"credentials":       jsonObject,
+		"endpoint_override": jsonString,
+		"insecure":          jsonBoolean,
+		"local_mode":        jsonBoolean,
+		"proxy_override":    jsonString,
+		"region_override":   jsonString,
+		"resource_arn":      jsonString,
+		"traces_collected":  jsonObject,
+	},
+}
+
+// ValidateJSONConfig checks that the given agent JSON configuration is a JSON object made of the sections the agent
+// knows, and that the well-known settings of each section have the right type. Errors and warnings name the
+// offending setting by its path in the configuration, for example `agent.metrics_collection_interval`.
+func ValidateJSONConfig(configStr string) ([]string, error) {
+	var config interface{}
+	if err := json.Unmarshal([]byte(configStr), &config); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := position(configStr, syntaxErr.Offset)
+			return nil, fmt.Errorf("invalid JSON at line %d, column %d: %w", line, column, err)
+		}
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	root, ok := config.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the configuration must be a JSON object, got %s", typeOf(config))
+	}
+
+	var warnings []string
+	for _, section := range slices.Sorted(maps.Keys(root)) {
+		keys, known := configSchema[section]
+		if !known {
+			return warnings, fmt.Errorf("unknown section '%s', expected one of %s", section, strings.Join(slices.Sorted(maps.Keys(configSchema)), ", "))
+		}
+		settings, ok := root[section].(map[string]interface{})
+		if !ok {
+			return warnings, fmt.Errorf("'%s' must be of type object, got %s", section, typeOf(root[section]))
+		}
+		for _, key := range slices.Sorted(maps.Keys(settings)) {
+			expected, known := keys[key]
+			if !known {
+				warnings = append(warnings, fmt.Sprintf("unknown setting '%s.%s' in the agent configuration", section, key))
+				continue
+			}
+			if actual := typeOf(settings[key]); actual != expected {
+				return warnings, fmt.Errorf("'%s.%s' must be of type %s, got %s", section, key, expected, actual)
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func typeOf(value interface{}) jsonType {
+	switch value.(type) {
+	case map[string]interface{}:
+		return jsonObject
+	case []interface{}:
+		return jsonArray
+	case string:
+		return jsonString
+	case float64:
+		return jsonNumber
+	case bool:
+		return jsonBoolean
+	default:
+		return "null"
+	}
+}
+
+// position converts the offset of a JSON syntax error, which counts the offending byte, into a 1-based line
+// and column.
+func position(s string, offset int64) (int, int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndexByte(before, '\n') - 1
+	return line, column
+}
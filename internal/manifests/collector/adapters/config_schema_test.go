@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJSONConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           string
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name: "valid",
+			config: `{
+				"agent": {"region": "us-west-2", "metrics_collection_interval": 60, "debug": false},
+				"logs": {"metrics_collected": {"kubernetes": {"enhanced_container_insights": true}}},
+				"traces": {"traces_collected": {"xray": {}}}
+			}`,
+		},
+		{
+			name:   "empty object",
+			config: `{}`,
+		},
+		{
+			name:        "syntax error",
+			config:      "{\n\"agent\": {,}\n}",
+			expectedErr: "invalid JSON at line 2, column 11",
+		},
+		{
+			name:        "trailing data",
+			config:      `{} {}`,
+			expectedErr: "invalid character '{' after top-level value",
+		},
+		{
+			name:        "not an object",
+			config:      `"agent"`,
+			expectedErr: "the configuration must be a JSON object, got string",
+		},
+		{
+			name:        "unknown section",
+			config:      `{"trace": {}}`,
+			expectedErr: "unknown section 'trace'",
+		},
+		{
+			name:        "section of the wrong type",
+			config:      `{"metrics": []}`,
+			expectedErr: "'metrics' must be of type object, got array",
+		},
+		{
+			name:        "setting of the wrong type",
+			config:      `{"metrics": {"append_dimensions": "InstanceId"}}`,
+			expectedErr: "'metrics.append_dimensions' must be of type object, got string",
+		},
+		{
+			name:        "null setting",
+			config:      `{"logs": {"logs_collected": null}}`,
+			expectedErr: "'logs.logs_collected' must be of type object, got null",
+		},
+		{
+			name:             "unknown settings are warnings",
+			config:           `{"logs": {"metric_collected": {}}, "agent": {"regoin": "us-west-2"}}`,
+			expectedWarnings: []string{"unknown setting 'agent.regoin' in the agent configuration", "unknown setting 'logs.metric_collected' in the agent configuration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := ValidateJSONConfig(tt.config)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+			assert.Equal(t, tt.expectedWarnings, warnings)
+		})
+	}
+}
@@ -5,6 +5,7 @@ package adapters
 
 import (
 	"errors"
+	"net"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -135,11 +136,11 @@ func extractPortFromExtensionConfig(cfg map[interface{}]interface{}) intstr.IntO
 	if !ok {
 		return defaultHealthCheckEndpoint()
 	}
-	endpointComponents := strings.Split(parsedEndpoint, ":")
-	if len(endpointComponents) != 2 {
+	_, port, err := net.SplitHostPort(parsedEndpoint)
+	if err != nil {
 		return defaultHealthCheckEndpoint()
 	}
-	return intstr.Parse(endpointComponents[1])
+	return intstr.Parse(port)
 }
 
 func defaultHealthCheckEndpoint() intstr.IntOrString {
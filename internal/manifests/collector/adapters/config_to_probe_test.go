@@ -51,6 +51,15 @@ service:
 			config: `extensions:
   health_check:
     endpoint: :1234
+service:
+  extensions: [health_check]`,
+		}, {
+			desc:         "CustomIPv6EndpointAndDefaultPath",
+			expectedPort: int32(1234),
+			expectedPath: "/",
+			config: `extensions:
+  health_check:
+    endpoint: "[::]:1234"
 service:
   extensions: [health_check]`,
 		}, {
@@ -3,7 +3,17 @@
 
 package adapters
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	otelConfigSections = []string{"connectors", "exporters", "extensions", "processors", "receivers", "service"}
+	otelPipelineTypes  = []string{"logs", "metrics", "traces"}
+)
 
 // Following Otel Doc: Configuring a receiver does not enable it. The receivers are enabled via pipelines within the service section.
 // getEnabledComponents returns all enabled components as a true flag set. If it can't find any receiver, it will return a nil interface.
@@ -97,3 +107,99 @@ func getEnabledComponents(config map[interface{}]interface{}, componentType Comp
 	}
 	return availableComponents
 }
+
+// ValidateOtelConfig checks the structure of an OTel collector configuration the same way the collector does at
+// startup: only known top-level sections are allowed, and every component referenced by the service must be
+// defined in its section. Components that are defined but never used only produce warnings.
+func ValidateOtelConfig(config map[interface{}]interface{}) ([]string, error) {
+	sections := map[string]map[interface{}]interface{}{}
+	for key, value := range config {
+		name, ok := key.(string)
+		if !ok || !slices.Contains(otelConfigSections, name) {
+			return nil, fmt.Errorf("unknown section '%v', expected one of %s", key, strings.Join(otelConfigSections, ", "))
+		}
+		if value == nil {
+			continue
+		}
+		section, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' must be a map", name)
+		}
+		sections[name] = section
+	}
+
+	used := map[string]map[string]bool{}
+	use := func(section string, path string, refs interface{}) error {
+		if refs == nil {
+			return nil
+		}
+		list, ok := refs.([]interface{})
+		if !ok {
+			return fmt.Errorf("'%s' must be a list", path)
+		}
+		for _, ref := range list {
+			id, ok := ref.(string)
+			if !ok {
+				return fmt.Errorf("'%s' must only contain component IDs", path)
+			}
+			if _, defined := sections[section][id]; !defined {
+				// connectors act as exporters of one pipeline and receivers of another
+				if _, isConnector := sections["connectors"][id]; isConnector && (section == "receivers" || section == "exporters") {
+					markUsed(used, "connectors", id)
+					continue
+				}
+				return fmt.Errorf("'%s' references %s '%s', which is not defined", path, strings.TrimSuffix(section, "s"), id)
+			}
+			markUsed(used, section, id)
+		}
+		return nil
+	}
+
+	service := sections["service"]
+	if err := use("extensions", "service.extensions", service["extensions"]); err != nil {
+		return nil, err
+	}
+	if pipelines, ok := service["pipelines"]; ok && pipelines != nil {
+		pipelineMap, ok := pipelines.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'service.pipelines' must be a map")
+		}
+		for pipelineKey, pipelineValue := range pipelineMap {
+			pipelineID := fmt.Sprint(pipelineKey)
+			pipelineType, _, _ := strings.Cut(pipelineID, "/")
+			if !slices.Contains(otelPipelineTypes, pipelineType) {
+				return nil, fmt.Errorf("pipeline '%s' has an unknown type, expected one of %s", pipelineID, strings.Join(otelPipelineTypes, ", "))
+			}
+			pipeline, ok := pipelineValue.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'service.pipelines.%s' must be a map", pipelineID)
+			}
+			for _, section := range []string{"receivers", "processors", "exporters"} {
+				if err := use(section, fmt.Sprintf("service.pipelines.%s.%s", pipelineID, section), pipeline[section]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var warnings []string
+	for _, section := range otelConfigSections {
+		for key := range sections[section] {
+			if section == "service" {
+				continue
+			}
+			if id := fmt.Sprint(key); !used[section][id] {
+				warnings = append(warnings, fmt.Sprintf("%s '%s' is defined but not used by the service", strings.TrimSuffix(section, "s"), id))
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+func markUsed(used map[string]map[string]bool, section, id string) {
+	if used[section] == nil {
+		used[section] = map[string]bool{}
+	}
+	used[section][id] = true
+}
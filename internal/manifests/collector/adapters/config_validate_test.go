@@ -90,3 +90,69 @@ service:
 	check := getEnabledComponents(config, ComponentTypeReceiver)
 	require.Empty(t, check)
 }
+
+func TestValidateOtelConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           string
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name: "valid with connector and extension",
+			config: `
+extensions:
+  health_check:
+receivers:
+  otlp:
+connectors:
+  spanmetrics:
+exporters:
+  awsxray:
+  awsemf:
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [awsxray, spanmetrics]
+    metrics/spans:
+      receivers: [spanmetrics]
+      exporters: [awsemf]
+`,
+		},
+		{
+			name:        "unknown pipeline type",
+			config:      "service:\n  pipelines:\n    profiles:\n      receivers: []\n",
+			expectedErr: "pipeline 'profiles' has an unknown type",
+		},
+		{
+			name:        "undefined extension",
+			config:      "service:\n  extensions: [pprof]\n",
+			expectedErr: "'service.extensions' references extension 'pprof', which is not defined",
+		},
+		{
+			name:        "receivers is not a list",
+			config:      "receivers:\n  otlp:\nservice:\n  pipelines:\n    logs:\n      receivers: otlp\n",
+			expectedErr: "'service.pipelines.logs.receivers' must be a list",
+		},
+		{
+			name:             "unused components",
+			config:           "processors:\n  batch:\nexporters:\n  debug:\n",
+			expectedWarnings: []string{"exporter 'debug' is defined but not used by the service", "processor 'batch' is defined but not used by the service"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ConfigFromString(tt.config)
+			require.NoError(t, err)
+			warnings, err := ValidateOtelConfig(config)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+			require.Equal(t, tt.expectedWarnings, warnings)
+		})
+	}
+}
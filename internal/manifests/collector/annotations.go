@@ -28,7 +28,7 @@ func Annotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	}
 
 	// make sure sha256 for configMap is always calculated
-	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance)
 
 	return annotations
 }
@@ -51,12 +51,27 @@ func PodAnnotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	}
 
 	// make sure sha256 for configMap is always calculated
-	podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance)
 
 	return podAnnotations
 }
 
-func getConfigMapSHA(config string) string {
-	h := sha256.Sum256([]byte(config))
-	return fmt.Sprintf("%x", h)
+// getConfigMapSHA hashes every configuration that ends up in the agent ConfigMaps, so that a change to any
+// of them rolls the pods. The OTel and Prometheus configurations only contribute when set, which keeps the
+// hash of agents configured through the JSON config alone stable across operator upgrades. The Prometheus
+// configuration is left out when the reloader sidecar of the agents reloads it from its mounted ConfigMap.
+func getConfigMapSHA(instance v1alpha1.AmazonCloudWatchAgent) string {
+	h := sha256.New()
+	h.Write([]byte(instance.Spec.Config))
+	if instance.Spec.OtelConfig != "" {
+		h.Write([]byte("\x00otelConfig\x00"))
+		h.Write([]byte(instance.Spec.OtelConfig))
+	}
+	if !instance.Spec.Prometheus.IsEmpty() && !reloadsPrometheusConfig(instance) {
+		if promConfig, err := instance.Spec.Prometheus.Yaml(); err == nil {
+			h.Write([]byte("\x00prometheus\x00"))
+			h.Write([]byte(promConfig))
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
@@ -7,9 +7,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
 
 func TestDefaultAnnotations(t *testing.T) {
@@ -79,3 +84,74 @@ func TestAnnotationsPropagateDown(t *testing.T) {
 	assert.Equal(t, "mycomponent", podAnnotations["myapp"])
 	assert.Equal(t, "pod_annotation_value", podAnnotations["pod_annotation"])
 }
+
+func TestConfigHashCoversAllConfigs(t *testing.T) {
+	base := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: "test",
+		},
+	}
+	withOtel := *base.DeepCopy()
+	withOtel.Spec.OtelConfig = "receivers: {}"
+	withOtelChanged := *base.DeepCopy()
+	withOtelChanged.Spec.OtelConfig = "exporters: {}"
+	withPrometheus := *base.DeepCopy()
+	withPrometheus.Spec.Prometheus = v1alpha1.PrometheusConfig{
+		Config: &v1alpha1.AnyConfig{Object: map[string]interface{}{"scrape_configs": []interface{}{}}},
+	}
+
+	baseSHA := PodAnnotations(base)["amazon-cloudwatch-agent-operator-config/sha256"]
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", baseSHA)
+
+	otelSHA := PodAnnotations(withOtel)["amazon-cloudwatch-agent-operator-config/sha256"]
+	assert.NotEqual(t, baseSHA, otelSHA)
+	assert.NotEqual(t, otelSHA, PodAnnotations(withOtelChanged)["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.Equal(t, otelSHA, Annotations(withOtel)["amazon-cloudwatch-agent-operator-config/sha256"])
+
+	assert.NotEqual(t, baseSHA, PodAnnotations(withPrometheus)["amazon-cloudwatch-agent-operator-config/sha256"])
+}
+
+func TestPrometheusConfigReloadWithoutRestart(t *testing.T) {
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "my-ns"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: "test",
+			Prometheus: v1alpha1.PrometheusConfig{
+				Config: &v1alpha1.AnyConfig{Object: map[string]interface{}{"scrape_configs": []interface{}{}}},
+			},
+			PrometheusConfigReload: &v1alpha1.PrometheusConfigReloadSpec{Enabled: true},
+		},
+	}
+	changed := *instance.DeepCopy()
+	changed.Spec.Prometheus.Config.Object["scrape_configs"] = []interface{}{map[string]interface{}{"job_name": "app"}}
+	cfg := config.New(config.WithPrometheusConfigReloaderImage("cloudwatch-agent-operator:1.0"))
+
+	// the change of the Prometheus configuration leaves the pods of the agents as they are
+	ds := DaemonSet(manifests.Params{Config: cfg, OtelCol: instance, Log: logger})
+	assert.Equal(t, ds.Spec.Template, DaemonSet(manifests.Params{Config: cfg, OtelCol: changed, Log: logger}).Spec.Template)
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", ds.Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+
+	// and the sidecar signals the agent to reload the updated file of the mounted ConfigMap
+	assert.Equal(t, ptr.To(true), ds.Spec.Template.Spec.ShareProcessNamespace)
+	require.Len(t, ds.Spec.Template.Spec.Containers, 2)
+	reloader := ds.Spec.Template.Spec.Containers[1]
+	assert.Equal(t, "prometheus-config-reloader", reloader.Name)
+	assert.Equal(t, "cloudwatch-agent-operator:1.0", reloader.Image)
+	assert.Equal(t, []string{"--reload-prometheus-config=/etc/prometheusconfig/prometheus.yaml"}, reloader.Args)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "prometheus-config", MountPath: "/etc/prometheusconfig", ReadOnly: true}}, reloader.VolumeMounts)
+	assert.Equal(t, ptr.To(int64(0)), reloader.SecurityContext.RunAsUser)
+
+	// the hardened agents are signaled by their own user
+	instance.Spec.Hardening = &v1alpha1.HardeningSpec{}
+	reloader = DaemonSet(manifests.Params{Config: cfg, OtelCol: instance, Log: logger}).Spec.Template.Spec.Containers[1]
+	assert.Equal(t, ptr.To(hardenedID), reloader.SecurityContext.RunAsUser)
+
+	// the Windows agents have no shared process namespace, and still roll
+	instance.Spec.Hardening = nil
+	instance.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	changed.Spec.NodeSelector = instance.Spec.NodeSelector
+	ds = DaemonSet(manifests.Params{Config: cfg, OtelCol: instance, Log: logger})
+	assert.Nil(t, ds.Spec.Template.Spec.ShareProcessNamespace)
+	assert.Len(t, ds.Spec.Template.Spec.Containers, 1)
+	assert.NotEqual(t, ds.Spec.Template.Annotations, DaemonSet(manifests.Params{Config: cfg, OtelCol: changed, Log: logger}).Spec.Template.Annotations)
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+// telemetrySections are the sections of the agent configuration sending telemetry to an AWS endpoint.
+var telemetrySections = []string{"metrics", "logs", "traces"}
+
+// mergeAWSConfig merges the AWS settings of the instance into the agent configuration. The role is set on the
+// agent and on every telemetry section present in the configuration, so that a section assuming its own role does
+// not bypass it, while endpoint overrides only apply to the sections present in the configuration.
+func mergeAWSConfig(aws *v1alpha1.AWSSpec, conf *confmap.Conf) error {
+	if aws == nil {
+		return nil
+	}
+
+	agent := map[string]interface{}{}
+	if aws.Region != "" {
+		agent["region"] = aws.Region
+	}
+	if aws.RoleARN != "" {
+		agent["credentials"] = map[string]interface{}{"role_arn": aws.RoleARN}
+	}
+	overrides := map[string]interface{}{}
+	if len(agent) > 0 {
+		overrides["agent"] = agent
+	}
+
+	endpoints := map[string]string{}
+	if aws.EndpointOverrides != nil {
+		endpoints["metrics"] = aws.EndpointOverrides.Metrics
+		endpoints["logs"] = aws.EndpointOverrides.Logs
+		endpoints["traces"] = aws.EndpointOverrides.Traces
+	}
+	for _, section := range telemetrySections {
+		if !conf.IsSet(section) {
+			continue
+		}
+		settings := map[string]interface{}{}
+		if aws.RoleARN != "" {
+			settings["credentials"] = map[string]interface{}{"role_arn": aws.RoleARN}
+		}
+		if endpoints[section] != "" {
+			settings["endpoint_override"] = endpoints[section]
+		}
+		if len(settings) > 0 {
+			overrides[section] = settings
+		}
+	}
+
+	if len(overrides) == 0 {
+		return nil
+	}
+	return conf.Merge(confmap.NewFromStringMap(overrides))
+}
+
+// agentRegion returns the region the instance sends its telemetry to, the one of its AWS settings or else the one of
+// the agent section of its configuration.
+func agentRegion(instance v1alpha1.AmazonCloudWatchAgent) string {
+	if instance.Spec.AWS != nil && instance.Spec.AWS.Region != "" {
+		return instance.Spec.AWS.Region
+	}
+	conf, err := adapters.ConfigStructFromJSONString(instance.Spec.Config)
+	if err != nil || conf == nil {
+		return ""
+	}
+	return conf.GetRegion()
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestReplaceConfigAWS(t *testing.T) {
+	roleARN := "arn:aws:iam::123456789012:role/central-monitoring"
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"agent":{"region":"us-east-1"},"logs":{"credentials":{"role_arn":"arn:aws:iam::210987654321:role/logs"}},"traces":{"traces_collected":{"xray":{"bind_address":"127.0.0.1:2000"}}}}`,
+			AWS: &v1alpha1.AWSSpec{
+				Region:  "eu-west-1",
+				RoleARN: roleARN,
+				EndpointOverrides: &v1alpha1.EndpointOverrides{
+					Metrics: "https://monitoring.eu-west-1.amazonaws.com",
+					Logs:    "https://logs.eu-west-1.amazonaws.com",
+				},
+			},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"agent": {"region": "eu-west-1", "credentials": {"role_arn": "`+roleARN+`"}},
+		"logs": {"credentials": {"role_arn": "`+roleARN+`"}, "endpoint_override": "https://logs.eu-west-1.amazonaws.com"},
+		"traces": {"credentials": {"role_arn": "`+roleARN+`"}, "traces_collected": {"xray": {"bind_address": "127.0.0.1:2000"}}}
+	}`, replaced)
+}
+
+func TestReplaceConfigWithoutAWS(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"agent":{"region":"us-east-1"},"logs":{"force_flush_interval":5}}`,
+			AWS:    &v1alpha1.AWSSpec{EndpointOverrides: &v1alpha1.EndpointOverrides{Traces: "https://xray.us-east-1.amazonaws.com"}},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"agent":{"region":"us-east-1"},"logs":{"force_flush_interval":5}}`, replaced)
+}
@@ -17,6 +17,30 @@ const (
 
 // Build creates the manifest for the collector resource.
 func Build(params manifests.Params) ([]client.Object, error) {
+	params, err := withEMFConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withMetricsListeners(params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withSelfTelemetry(params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withControlPlaneMetrics(params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withGPUMetrics(params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withNeuronMetrics(params)
+	if err != nil {
+		return nil, err
+	}
 	var resourceManifests []client.Object
 	var manifestFactories []manifests.K8sManifestFactory
 	switch params.OtelCol.Spec.Mode {
@@ -34,10 +58,21 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
 		manifests.FactoryWithoutError(HorizontalPodAutoscaler),
 		manifests.FactoryWithoutError(ServiceAccount),
+		manifests.FactoryWithoutError(ClusterRole),
+		manifests.FactoryWithoutError(ClusterRoleBinding),
+		manifests.FactoryWithoutError(Role),
+		manifests.FactoryWithoutError(RoleBinding),
+		manifests.FactoryWithoutError(PodIdentityAssociation),
 		manifests.Factory(Service),
 		manifests.Factory(HeadlessService),
 		manifests.Factory(MonitoringService),
 		manifests.Factory(Ingress),
+		manifests.Factory(KubernetesEventsDeployment),
+		manifests.Factory(EFADaemonSet),
+		manifests.Factory(FluentBitDaemonSet),
+		manifests.FactoryWithoutError(DcgmExporterDaemonSet),
+		manifests.FactoryWithoutError(DcgmExporterMIGDaemonSet),
+		manifests.FactoryWithoutError(DcgmExporterService),
 	}...)
 	if params.OtelCol.Spec.Observability.Metrics.EnableMetrics && featuregate.PrometheusOperatorIsAvailable.IsEnabled() {
 		if params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
@@ -61,6 +96,18 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	for _, configmap := range configmaps {
 		resourceManifests = append(resourceManifests, configmap)
 	}
+	daemonSets, err := NodeGroupDaemonSets(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, daemonSet := range daemonSets {
+		resourceManifests = append(resourceManifests, daemonSet)
+	}
+	neuronMonitor, err := NeuronMonitorObjects(params)
+	if err != nil {
+		return nil, err
+	}
+	resourceManifests = append(resourceManifests, neuronMonitor...)
 	routes, err := Routes(params)
 	if err != nil {
 		return nil, err
@@ -69,5 +116,12 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	for _, route := range routes {
 		resourceManifests = append(resourceManifests, route)
 	}
+	gatewayRoutes, err := GatewayRoutes(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range gatewayRoutes {
+		resourceManifests = append(resourceManifests, route)
+	}
 	return resourceManifests, nil
 }
@@ -83,6 +83,10 @@ func ReplaceConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
 		}
 	}
 
+	if err := mergeAWSConfig(instance.Spec.AWS, conf); err != nil {
+		return "", err
+	}
+
 	finalConfig := conf.ToStringMap()
 	out, err := json.Marshal(finalConfig)
 	if err != nil {
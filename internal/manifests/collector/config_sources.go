@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/collector/confmap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+// ResolveConfigSources returns a copy of the instance whose Config holds the result of merging every entry of
+// ConfigSources on top of Config, in order. ConfigMap sources are read through the given client, which may be
+// nil when only inline sources are used. Instances without config sources are returned untouched, so their
+// Config, and therefore their config hash, stays byte for byte what the user wrote.
+func ResolveConfigSources(ctx context.Context, c client.Reader, instance v1alpha1.AmazonCloudWatchAgent) (v1alpha1.AmazonCloudWatchAgent, error) {
+	if len(instance.Spec.ConfigSources) == 0 {
+		return instance, nil
+	}
+
+	var fragments []string
+	for i, source := range instance.Spec.ConfigSources {
+		raw, found, err := configSourceContent(ctx, c, instance.Namespace, source)
+		if err != nil {
+			return instance, fmt.Errorf("config source %d: %w", i, err)
+		}
+		if found {
+			fragments = append(fragments, raw)
+		}
+	}
+
+	merged, err := mergeJSONConfig(instance.Spec.Config, fragments...)
+	if err != nil {
+		return instance, err
+	}
+
+	resolved := *instance.DeepCopy()
+	resolved.Spec.Config = merged
+	resolved.Spec.ConfigSources = nil
+	return resolved, nil
+}
+
+// mergeJSONConfig merges JSON agent configuration fragments on top of the base configuration, in order.
+func mergeJSONConfig(base string, fragments ...string) (string, error) {
+	conf := confmap.New()
+	if base != "" {
+		config, err := adapters.ConfigFromJSONString(base)
+		if err != nil {
+			return "", err
+		}
+		conf = confmap.NewFromStringMap(config)
+	}
+	for i, raw := range fragments {
+		fragment, err := adapters.ConfigFromJSONString(raw)
+		if err != nil {
+			return "", fmt.Errorf("config fragment %d: %w", i, err)
+		}
+		if err := conf.Merge(confmap.NewFromStringMap(fragment)); err != nil {
+			return "", fmt.Errorf("config fragment %d: %w", i, err)
+		}
+	}
+	out, err := json.Marshal(conf.ToStringMap())
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// configSourceContent returns the raw JSON of a config source. Missing optional ConfigMaps or keys are
+// reported as not found instead of as an error.
+func configSourceContent(ctx context.Context, c client.Reader, namespace string, source v1alpha1.ConfigSource) (string, bool, error) {
+	if source.ConfigMap == nil {
+		return source.Inline, true, nil
+	}
+	if c == nil {
+		return "", false, fmt.Errorf("ConfigMap '%s' cannot be read without a client", source.ConfigMap.Name)
+	}
+
+	optional := source.ConfigMap.Optional != nil && *source.ConfigMap.Optional
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMap.Name}, cm); err != nil {
+		if apierrors.IsNotFound(err) && optional {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	raw, ok := cm.Data[source.ConfigMap.Key]
+	if !ok {
+		if optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key '%s' not found in ConfigMap '%s'", source.ConfigMap.Key, source.ConfigMap.Name)
+	}
+	return raw, true, nil
+}
@@ -0,0 +1,85 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestResolveConfigSources(t *testing.T) {
+	optional := true
+	teamConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-config", Namespace: "my-ns"},
+		Data: map[string]string{
+			"agent.json": `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/team.log"}]}}}}`,
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(teamConfig).Build()
+
+	newAgent := func(sources ...v1alpha1.ConfigSource) v1alpha1.AmazonCloudWatchAgent {
+		return v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "my-ns"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Config:        `{"agent":{"region":"us-west-2"},"logs":{"force_flush_interval":5}}`,
+				ConfigSources: sources,
+			},
+		}
+	}
+
+	t.Run("no sources keeps the config untouched", func(t *testing.T) {
+		agent := newAgent()
+		resolved, err := ResolveConfigSources(context.Background(), nil, agent)
+		require.NoError(t, err)
+		assert.Equal(t, agent.Spec.Config, resolved.Spec.Config)
+	})
+	t.Run("sources are merged in order", func(t *testing.T) {
+		agent := newAgent(
+			v1alpha1.ConfigSource{Inline: `{"agent":{"region":"eu-west-1","debug":true}}`},
+			v1alpha1.ConfigSource{ConfigMap: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "team-config"},
+				Key:                  "agent.json",
+			}},
+			v1alpha1.ConfigSource{Inline: `{"agent":{"region":"us-east-1"}}`},
+		)
+		resolved, err := ResolveConfigSources(context.Background(), c, agent)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"agent":{"region":"us-east-1","debug":true},
+			"logs":{"force_flush_interval":5,"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/team.log"}]}}}
+		}`, resolved.Spec.Config)
+		assert.Empty(t, resolved.Spec.ConfigSources)
+		assert.Len(t, agent.Spec.ConfigSources, 3)
+	})
+	t.Run("missing optional configmap is skipped", func(t *testing.T) {
+		agent := newAgent(v1alpha1.ConfigSource{ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+			Key:                  "agent.json",
+			Optional:             &optional,
+		}})
+		resolved, err := ResolveConfigSources(context.Background(), c, agent)
+		require.NoError(t, err)
+		assert.JSONEq(t, agent.Spec.Config, resolved.Spec.Config)
+	})
+	t.Run("missing key is an error", func(t *testing.T) {
+		agent := newAgent(v1alpha1.ConfigSource{ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "team-config"},
+			Key:                  "other.json",
+		}})
+		_, err := ResolveConfigSources(context.Background(), c, agent)
+		assert.ErrorContains(t, err, "key 'other.json' not found in ConfigMap 'team-config'")
+	})
+	t.Run("invalid inline source", func(t *testing.T) {
+		_, err := ResolveConfigSources(context.Background(), nil, newAgent(v1alpha1.ConfigSource{Inline: "{"}))
+		assert.Error(t, err)
+	})
+}
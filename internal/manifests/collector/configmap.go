@@ -19,37 +19,54 @@ import (
 func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 	var configmaps []*corev1.ConfigMap
 
-	name := naming.ConfigMap(params.OtelCol.Name)
-	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
-
-	replacedConf, err := ReplaceConfig(params.OtelCol)
+	configMap, err := agentConfigMap(params, naming.ConfigMap(params.OtelCol.Name))
 	if err != nil {
-		params.Log.V(2).Info("failed to update config: ", "err", err)
 		return nil, err
 	}
+	configmaps = append(configmaps, configMap)
 
-	sourceDataMap := map[string]string{
-		params.Config.CollectorConfigMapEntry(): replacedConf,
-	}
-
-	if params.OtelCol.Spec.OtelConfig != "" {
-		replacedOtelConfig, err := ReplaceOtelConfig(params.OtelCol)
+	for _, group := range nodeGroups(params.OtelCol) {
+		groupParams, err := nodeGroupParams(params, group)
 		if err != nil {
-			params.Log.V(2).Info("failed to update otel config: ", "err", err)
 			return nil, err
 		}
-		sourceDataMap[params.Config.OtelCollectorConfigMapEntry()] = replacedOtelConfig
+		groupConfigMap, err := agentConfigMap(groupParams, naming.NodeGroup(params.OtelCol.Name, group.Name))
+		if err != nil {
+			return nil, err
+		}
+		configmaps = append(configmaps, groupConfigMap)
 	}
 
-	configmaps = append(configmaps, &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Namespace:   params.OtelCol.Namespace,
-			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
-		},
-		Data: sourceDataMap,
-	})
+	efaConfigMap, err := efaConfigMap(params)
+	if err != nil {
+		return nil, err
+	}
+	if efaConfigMap != nil {
+		configmaps = append(configmaps, efaConfigMap)
+	}
+
+	eventsConfigMap, err := kubernetesEventsConfigMap(params)
+	if err != nil {
+		return nil, err
+	}
+	if eventsConfigMap != nil {
+		configmaps = append(configmaps, eventsConfigMap)
+	}
+
+	fluentBitConfigMap, err := fluentBitConfigMap(params)
+	if err != nil {
+		return nil, err
+	}
+	if fluentBitConfigMap != nil {
+		configmaps = append(configmaps, fluentBitConfigMap)
+	}
+
+	if dcgmExporterConfigMap := dcgmExporterConfigMap(params); dcgmExporterConfigMap != nil {
+		configmaps = append(configmaps, dcgmExporterConfigMap)
+	}
+	if dcgmExporterMIGConfigMap := dcgmExporterMIGConfigMap(params); dcgmExporterMIGConfigMap != nil {
+		configmaps = append(configmaps, dcgmExporterMIGConfigMap)
+	}
 
 	if !params.OtelCol.Spec.Prometheus.IsEmpty() {
 		promName := naming.PrometheusConfigMap(params.OtelCol.Name)
@@ -95,3 +112,37 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 
 	return configmaps, nil
 }
+
+// agentConfigMap builds the ConfigMap holding the rendered agent configuration.
+func agentConfigMap(params manifests.Params, name string) (*corev1.ConfigMap, error) {
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	replacedConf, err := ReplaceConfig(params.OtelCol)
+	if err != nil {
+		params.Log.V(2).Info("failed to update config: ", "err", err)
+		return nil, err
+	}
+
+	sourceDataMap := map[string]string{
+		params.Config.CollectorConfigMapEntry(): replacedConf,
+	}
+
+	if params.OtelCol.Spec.OtelConfig != "" {
+		replacedOtelConfig, err := ReplaceOtelConfig(params.OtelCol)
+		if err != nil {
+			params.Log.V(2).Info("failed to update otel config: ", "err", err)
+			return nil, err
+		}
+		sourceDataMap[params.Config.OtelCollectorConfigMapEntry()] = replacedOtelConfig
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      labels,
+			Annotations: params.OtelCol.Annotations,
+		},
+		Data: sourceDataMap,
+	}, nil
+}
@@ -6,6 +6,7 @@ package collector
 import (
 	"errors"
 	"fmt"
+	"path"
 	"sort"
 
 	"github.com/go-logr/logr"
@@ -23,12 +24,14 @@ const maxPortLen = 15
 
 // Container builds a container for the given collector.
 func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloudWatchAgent, addConfig bool) corev1.Container {
-	image := agent.Spec.Image
-	if len(image) == 0 {
-		image = cfg.CollectorImage()
-	}
+	image := agentImage(cfg, agent)
 
 	ports := getContainerPorts(logger, agent.Spec.Config, agent.Spec.OtelConfig, agent.Spec.Ports)
+	if port, ok := selfTelemetryPort(agent); ok {
+		if _, exists := ports[monitoringPortName]; !exists {
+			ports[monitoringPortName] = corev1.ContainerPort{Name: monitoringPortName, ContainerPort: port, Protocol: corev1.ProtocolTCP}
+		}
+	}
 
 	var volumeMounts []corev1.VolumeMount
 	argsMap := agent.Spec.Args
@@ -50,6 +53,23 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		if !agent.Spec.Prometheus.IsEmpty() {
 			volumeMounts = append(volumeMounts, getPrometheusVolumeMounts(agent.Spec.NodeSelector["kubernetes.io/os"]))
 		}
+
+		if volumeMount, ok := containerRuntimeVolumeMount(agent); ok {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+
+		// host mounts are always read-only so that the agent cannot alter the node
+		for _, hostMount := range agent.Spec.HostMounts {
+			mountPath := hostMount.MountPath
+			if mountPath == "" {
+				mountPath = hostMount.Path
+			}
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      hostMount.Name,
+				MountPath: mountPath,
+				ReadOnly:  true,
+			})
+		}
 	}
 
 	// ensure that the v1alpha1.AmazonCloudWatchAgentSpec.Args are ordered when moved to container.Args,
@@ -66,21 +86,52 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		volumeMounts = append(volumeMounts, agent.Spec.VolumeMounts...)
 	}
 
-	var envVars = agent.Spec.Env
-	if agent.Spec.Env == nil {
-		envVars = []corev1.EnvVar{}
+	// mount the extra config maps, their volumes are added by Volumes
+	for _, cm := range agent.Spec.ConfigMaps {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.ConfigMapExtra(cm.Name),
+			MountPath: path.Join("/var/conf", cm.MountPath, naming.ConfigMapExtra(cm.Name)),
+		})
+	}
+
+	// copy the user defined variables so that appending does not write into the spec's backing array
+	envVars := make([]corev1.EnvVar, 0, len(agent.Spec.Env)+2)
+	envVars = append(envVars, agent.Spec.Env...)
+
+	if !hasEnvVar(envVars, "POD_NAME") {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
 	}
 
-	envVars = append(envVars, corev1.EnvVar{
-		Name: "POD_NAME",
-		ValueFrom: &corev1.EnvVarSource{
-			FieldRef: &corev1.ObjectFieldSelector{
-				FieldPath: "metadata.name",
+	if selfTelemetryCloudWatchEnabled(agent) && !hasEnvVar(envVars, "K8S_NODE_NAME") {
+		// the node name tags the internal metrics sent to CloudWatch
+		envVars = append(envVars, corev1.EnvVar{
+			Name: "K8S_NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
 			},
-		},
-	})
+		})
+	}
+
+	if agent.Spec.FIPS && !hasEnvVar(envVars, "AWS_USE_FIPS_ENDPOINT") {
+		// the AWS SDK of the agent resolves the FIPS endpoints of every service it sends telemetry to
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "AWS_USE_FIPS_ENDPOINT",
+			Value: "true",
+		})
+	}
 
-	if agent.Spec.TargetAllocator.Enabled {
+	envVars = proxyEnvVars(agent.Spec.Proxy, envVars)
+
+	if agent.Spec.TargetAllocator.Enabled && !hasEnvVar(envVars, "SHARD") {
 		// We need to add a SHARD here so the collector is able to keep targets after the hashmod operation which is
 		// added by default by the Prometheus operator's config generator.
 		// All collector instances use SHARD == 0 as they only receive targets
@@ -96,10 +147,17 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		logger.Error(err, "error parsing config")
 	}
 
-	var livenessProbe *corev1.Probe
+	var livenessProbe, readinessProbe, startupProbe *corev1.Probe
 	if configFromString, err := adapters.ConfigFromString(agent.Spec.OtelConfig); err == nil {
-		if probe, err := getLivenessProbe(configFromString, agent.Spec.LivenessProbe); err == nil {
-			livenessProbe = probe
+		if probe, err := adapters.ConfigToContainerProbe(configFromString); err == nil {
+			livenessProbe = withProbeConfig(probe, agent.Spec.LivenessProbe)
+			// readiness and startup probes share the health check handler and are only added when configured
+			if agent.Spec.ReadinessProbe != nil {
+				readinessProbe = withProbeConfig(probe, agent.Spec.ReadinessProbe)
+			}
+			if agent.Spec.StartupProbe != nil {
+				startupProbe = withProbeConfig(probe, agent.Spec.StartupProbe)
+			}
 		} else if errors.Is(err, adapters.ErrNoServiceExtensions) {
 			logger.Info("extensions not configured, skipping liveness probe creation")
 		} else if errors.Is(err, adapters.ErrNoServiceExtensionHealthCheck) {
@@ -109,6 +167,9 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		}
 	}
 
+	containerPorts := portMapToContainerPortList(ports)
+	setListenerHostPorts(agent, containerPorts)
+
 	return corev1.Container{
 		Name:            naming.Container(),
 		Image:           image,
@@ -119,13 +180,37 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		Env:             envVars,
 		EnvFrom:         agent.Spec.EnvFrom,
 		Resources:       agent.Spec.Resources,
-		Ports:           portMapToContainerPortList(ports),
-		SecurityContext: agent.Spec.SecurityContext,
+		Ports:           containerPorts,
+		SecurityContext: containerSecurityContext(agent, containerPorts),
 		LivenessProbe:   livenessProbe,
+		ReadinessProbe:  readinessProbe,
+		StartupProbe:    startupProbe,
 		Lifecycle:       agent.Spec.Lifecycle,
 	}
 }
 
+// podContainers returns the additional containers of the given instance followed by the agent container, and the
+// reloader of its Prometheus configuration when enabled.
+func podContainers(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloudWatchAgent) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(agent.Spec.AdditionalContainers)+2)
+	containers = append(containers, agent.Spec.AdditionalContainers...)
+	agentContainer := Container(cfg, logger, agent, true)
+	containers = append(containers, agentContainer)
+	if reloadsPrometheusConfig(agent) {
+		containers = append(containers, prometheusConfigReloaderContainer(cfg, agent, agentContainer))
+	}
+	return containers
+}
+
+func hasEnvVar(envVars []corev1.EnvVar, name string) bool {
+	for _, env := range envVars {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func getVolumeMounts(os string) corev1.VolumeMount {
 	var volumeMount corev1.VolumeMount
 	if os == "windows" {
@@ -169,11 +254,9 @@ func portMapToContainerPortList(portMap map[string]corev1.ContainerPort) []corev
 	return ports
 }
 
-func getLivenessProbe(config map[interface{}]interface{}, probeConfig *v1alpha1.Probe) (*corev1.Probe, error) {
-	probe, err := adapters.ConfigToContainerProbe(config)
-	if err != nil {
-		return nil, err
-	}
+// withProbeConfig returns a copy of the generated probe with the user provided settings applied.
+func withProbeConfig(generated *corev1.Probe, probeConfig *v1alpha1.Probe) *corev1.Probe {
+	probe := generated.DeepCopy()
 	if probeConfig != nil {
 		if probeConfig.InitialDelaySeconds != nil {
 			probe.InitialDelaySeconds = *probeConfig.InitialDelaySeconds
@@ -192,5 +275,5 @@ func getLivenessProbe(config map[interface{}]interface{}, probeConfig *v1alpha1.
 		}
 		probe.TerminationGracePeriodSeconds = probeConfig.TerminationGracePeriodSeconds
 	}
-	return probe, nil
+	return probe
 }
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	containerdSocket = "/run/containerd/containerd.sock"
+	dockerSocket     = "/var/run/docker.sock"
+)
+
+// runtimeSocketHostPaths are the default host paths of the runtime sockets.
+var runtimeSocketHostPaths = map[v1alpha1.ContainerRuntime]string{
+	v1alpha1.ContainerRuntimeContainerd:   containerdSocket,
+	v1alpha1.ContainerRuntimeDocker:       dockerSocket,
+	v1alpha1.ContainerRuntimeK3s:          "/run/k3s/containerd/containerd.sock",
+	v1alpha1.ContainerRuntimeBottlerocket: "/run/dockershim.sock",
+}
+
+// containerRuntimeSocket returns the host path of the runtime socket and the path the agent looks for it at. The
+// agent only knows about the containerd and Docker default paths, so the containerd flavours are all mounted at
+// the containerd one.
+func containerRuntimeSocket(runtime *v1alpha1.ContainerRuntimeSpec) (hostPath string, mountPath string, ok bool) {
+	if runtime == nil {
+		return "", "", false
+	}
+	runtimeType := runtime.Type
+	if runtimeType == "" {
+		runtimeType = v1alpha1.ContainerRuntimeContainerd
+	}
+	hostPath = runtime.SocketPath
+	if hostPath == "" {
+		hostPath = runtimeSocketHostPaths[runtimeType]
+	}
+	mountPath = containerdSocket
+	if runtimeType == v1alpha1.ContainerRuntimeDocker {
+		mountPath = dockerSocket
+	}
+	return hostPath, mountPath, true
+}
+
+// containerRuntimeVolume returns the volume of the runtime socket, if the instance mounts one.
+func containerRuntimeVolume(otelcol v1alpha1.AmazonCloudWatchAgent) (corev1.Volume, bool) {
+	hostPath, _, ok := containerRuntimeSocket(otelcol.Spec.ContainerRuntime)
+	if !ok {
+		return corev1.Volume{}, false
+	}
+	socket := corev1.HostPathSocket
+	return corev1.Volume{
+		Name: naming.ContainerRuntimeSocketVolume(),
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: hostPath,
+				Type: &socket,
+			},
+		},
+	}, true
+}
+
+// containerRuntimeVolumeMount returns the mount of the runtime socket, if the instance mounts one.
+func containerRuntimeVolumeMount(otelcol v1alpha1.AmazonCloudWatchAgent) (corev1.VolumeMount, bool) {
+	_, mountPath, ok := containerRuntimeSocket(otelcol.Spec.ContainerRuntime)
+	if !ok {
+		return corev1.VolumeMount{}, false
+	}
+	return corev1.VolumeMount{
+		Name:      naming.ContainerRuntimeSocketVolume(),
+		MountPath: mountPath,
+		ReadOnly:  true,
+	}, true
+}
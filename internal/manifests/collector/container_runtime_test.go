@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestContainerRuntimeSocket(t *testing.T) {
+	tests := []struct {
+		name              string
+		runtime           *v1alpha1.ContainerRuntimeSpec
+		expectedHostPath  string
+		expectedMountPath string
+	}{
+		{
+			name:              "defaults to containerd",
+			runtime:           &v1alpha1.ContainerRuntimeSpec{},
+			expectedHostPath:  "/run/containerd/containerd.sock",
+			expectedMountPath: "/run/containerd/containerd.sock",
+		},
+		{
+			name:              "docker",
+			runtime:           &v1alpha1.ContainerRuntimeSpec{Type: v1alpha1.ContainerRuntimeDocker},
+			expectedHostPath:  "/var/run/docker.sock",
+			expectedMountPath: "/var/run/docker.sock",
+		},
+		{
+			name:              "k3s",
+			runtime:           &v1alpha1.ContainerRuntimeSpec{Type: v1alpha1.ContainerRuntimeK3s},
+			expectedHostPath:  "/run/k3s/containerd/containerd.sock",
+			expectedMountPath: "/run/containerd/containerd.sock",
+		},
+		{
+			name:              "bottlerocket",
+			runtime:           &v1alpha1.ContainerRuntimeSpec{Type: v1alpha1.ContainerRuntimeBottlerocket},
+			expectedHostPath:  "/run/dockershim.sock",
+			expectedMountPath: "/run/containerd/containerd.sock",
+		},
+		{
+			name:              "custom socket path",
+			runtime:           &v1alpha1.ContainerRuntimeSpec{Type: v1alpha1.ContainerRuntimeContainerd, SocketPath: "/var/snap/microk8s/common/run/containerd.sock"},
+			expectedHostPath:  "/var/snap/microk8s/common/run/containerd.sock",
+			expectedMountPath: "/run/containerd/containerd.sock",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostPath, mountPath, ok := containerRuntimeSocket(tt.runtime)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedHostPath, hostPath)
+			assert.Equal(t, tt.expectedMountPath, mountPath)
+		})
+	}
+
+	_, _, ok := containerRuntimeSocket(nil)
+	assert.False(t, ok)
+}
+
+func TestContainerRuntimeNodeGroup(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.ContainerRuntime = &v1alpha1.ContainerRuntimeSpec{}
+	params.OtelCol.Spec.NodeGroups = []v1alpha1.NodeGroup{{
+		Name:             "k3s",
+		NodeSelector:     map[string]string{"node.kubernetes.io/instance-type": "k3s"},
+		ContainerRuntime: &v1alpha1.ContainerRuntimeSpec{Type: v1alpha1.ContainerRuntimeK3s},
+	}}
+	params.OtelCol.Spec.Windows = &v1alpha1.WindowsSpec{}
+
+	socketPath := func(volumes []corev1.Volume) string {
+		for _, v := range volumes {
+			if v.Name == naming.ContainerRuntimeSocketVolume() {
+				return v.HostPath.Path
+			}
+		}
+		return ""
+	}
+
+	assert.Equal(t, "/run/containerd/containerd.sock", socketPath(DaemonSet(params).Spec.Template.Spec.Volumes))
+	daemonSets, err := NodeGroupDaemonSets(params)
+	require.NoError(t, err)
+	require.Len(t, daemonSets, 2)
+	assert.Equal(t, "/run/k3s/containerd/containerd.sock", socketPath(daemonSets[0].Spec.Template.Spec.Volumes))
+	assert.Empty(t, socketPath(daemonSets[1].Spec.Template.Spec.Volumes))
+
+	agent := daemonSets[0].Spec.Template.Spec.Containers[len(daemonSets[0].Spec.Template.Spec.Containers)-1]
+	assert.Contains(t, agent.VolumeMounts, corev1.VolumeMount{
+		Name:      naming.ContainerRuntimeSocketVolume(),
+		MountPath: "/run/containerd/containerd.sock",
+		ReadOnly:  true,
+	})
+}
@@ -54,6 +54,172 @@ func TestGetPrometheusVolumeMounts(t *testing.T) {
 	assert.Equal(t, volumeMount.MountPath, "/etc/prometheusconfig")
 }
 
+func TestContainerVolumeMounts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      "ca-bundle",
+				MountPath: "/etc/ssl/custom",
+			}},
+			ConfigMaps: []v1alpha1.ConfigMapsSpec{{
+				Name:      "auth",
+				MountPath: "/creds",
+			}},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Len(t, c.VolumeMounts, 3)
+	assert.Equal(t, "/etc/cwagentconfig", c.VolumeMounts[0].MountPath)
+	assert.Equal(t, corev1.VolumeMount{Name: "ca-bundle", MountPath: "/etc/ssl/custom"}, c.VolumeMounts[1])
+	assert.Equal(t, corev1.VolumeMount{Name: "configmap-auth", MountPath: "/var/conf/creds/configmap-auth"}, c.VolumeMounts[2])
+}
+
+func TestContainerHostMounts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			HostMounts: []v1alpha1.HostMount{
+				{Name: "proc", Path: "/proc", MountPath: "/rootfs/proc"},
+				{Name: "app-logs", Path: "/var/log/app"},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Len(t, c.VolumeMounts, 3)
+	assert.Equal(t, corev1.VolumeMount{Name: "proc", MountPath: "/rootfs/proc", ReadOnly: true}, c.VolumeMounts[1])
+	assert.Equal(t, corev1.VolumeMount{Name: "app-logs", MountPath: "/var/log/app", ReadOnly: true}, c.VolumeMounts[2])
+}
+
+func TestContainerFIPS(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			FIPS: true,
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("cloudwatch-agent:1.0.0"), config.WithCollectorFIPSImage("cloudwatch-agent-fips:1.0.0"))
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Equal(t, "cloudwatch-agent-fips:1.0.0", c.Image)
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "AWS_USE_FIPS_ENDPOINT", Value: "true"})
+}
+
+func TestContainerProxy(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Env: []corev1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "http://sidecar-proxy:3128"},
+				{Name: "NO_PROXY", Value: "internal.example.com, 169.254.169.254"},
+			},
+			Proxy: &v1alpha1.Proxy{
+				HTTPSProxy: "http://proxy:3128",
+				HTTPProxy:  "http://proxy:3128",
+				NoProxy:    []string{"10.100.0.0/16"},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"})
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://sidecar-proxy:3128"})
+	assert.NotContains(t, c.Env, corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy:3128"})
+	assert.Contains(t, c.Env, corev1.EnvVar{
+		Name:  "NO_PROXY",
+		Value: "internal.example.com,169.254.169.254,10.100.0.0/16,localhost,127.0.0.1,fd00:ec2::254,169.254.170.23,fd00:ec2::23,.svc,.cluster.local",
+	})
+	assert.Equal(t, "internal.example.com, 169.254.169.254", otelcol.Spec.Env[1].Value)
+}
+
+func TestContainerWithoutProxy(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Proxy: &v1alpha1.Proxy{NoProxy: []string{"10.100.0.0/16"}},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	for _, env := range c.Env {
+		assert.NotEqual(t, "NO_PROXY", env.Name)
+	}
+}
+
+func TestContainerEnvVars(t *testing.T) {
+	// prepare
+	env := make([]corev1.EnvVar, 1, 4)
+	env[0] = corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Env: env,
+			EnvFrom: []corev1.EnvFromSource{{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}},
+			}},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Len(t, c.Env, 2)
+	assert.Equal(t, "HTTPS_PROXY", c.Env[0].Name)
+	assert.Equal(t, "POD_NAME", c.Env[1].Name)
+	assert.Equal(t, otelcol.Spec.EnvFrom, c.EnvFrom)
+	assert.Len(t, otelcol.Spec.Env, 1)
+	assert.Empty(t, otelcol.Spec.Env[:2][1].Name)
+
+	// a user defined POD_NAME is kept as is
+	otelcol.Spec.Env = append(otelcol.Spec.Env, corev1.EnvVar{Name: "POD_NAME", Value: "agent"})
+	c = Container(cfg, logger, otelcol, true)
+	assert.Len(t, c.Env, 2)
+	assert.Equal(t, "agent", c.Env[1].Value)
+}
+
+func TestPodContainers(t *testing.T) {
+	// prepare
+	additional := make([]corev1.Container, 1, 2)
+	additional[0] = corev1.Container{Name: "log-shipper"}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			AdditionalContainers: additional,
+		},
+	}
+	cfg := config.New()
+
+	// test
+	containers := podContainers(cfg, logger, otelcol)
+
+	// verify
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "log-shipper", containers[0].Name)
+	assert.Equal(t, "otc-container", containers[1].Name)
+	assert.Empty(t, otelcol.Spec.AdditionalContainers[:2][1].Name)
+}
+
 func TestContainerPorts(t *testing.T) {
 	var sampleJSONConfig = `{
 	  "logs": {
@@ -172,6 +338,47 @@ service:
 	assert.Equal(t, terminationGracePeriodSeconds, *c.LivenessProbe.TerminationGracePeriodSeconds)
 }
 
+func TestContainerReadinessAndStartupProbe(t *testing.T) {
+	// prepare
+	periodSeconds := int32(5)
+	failureThreshold := int32(60)
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `extensions:
+ health_check:
+service:
+ extensions: [health_check]`,
+			ReadinessProbe: &v1alpha1.Probe{
+				PeriodSeconds: &periodSeconds,
+			},
+			StartupProbe: &v1alpha1.Probe{
+				FailureThreshold: &failureThreshold,
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Equal(t, int32(13133), c.ReadinessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, periodSeconds, c.ReadinessProbe.PeriodSeconds)
+	assert.Equal(t, int32(13133), c.StartupProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, failureThreshold, c.StartupProbe.FailureThreshold)
+	// the liveness probe keeps the generated defaults
+	assert.NotEqual(t, periodSeconds, c.LivenessProbe.PeriodSeconds)
+	assert.NotEqual(t, failureThreshold, c.LivenessProbe.FailureThreshold)
+
+	// readiness and startup probes are only added when configured
+	otelcol.Spec.ReadinessProbe = nil
+	otelcol.Spec.StartupProbe = nil
+	c = Container(cfg, logger, otelcol, true)
+	assert.NotNil(t, c.LivenessProbe)
+	assert.Nil(t, c.ReadinessProbe)
+	assert.Nil(t, c.StartupProbe)
+}
+
 func TestContainerProbeEmptyConfig(t *testing.T) {
 	// prepare
 
@@ -0,0 +1,204 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+const (
+	controlPlanePipeline  = "metrics/eks_control_plane"
+	controlPlaneReceiver  = "prometheus/eks_control_plane"
+	controlPlaneProcessor = "resource/eks_control_plane"
+	controlPlaneExporter  = "awsemf/eks_control_plane"
+	controlPlaneNamespace = "ContainerInsights/Prometheus"
+	controlPlaneLogStream = "control-plane"
+
+	kubernetesAPIServer = "kubernetes.default.svc:443"
+	serviceAccountDir   = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+var errNoControlPlaneMetricsClusterName = errors.New("the cluster of the control plane metrics is unknown, set the clusterName of controlPlaneMetrics")
+
+// controlPlaneJob is a scrape job of the control plane and the metrics kept from it.
+type controlPlaneJob struct {
+	name    string
+	path    string
+	metrics []string
+}
+
+// controlPlaneJobs are the control plane components whose metrics EKS exposes through the Kubernetes API. The API
+// server also reports the latency of its requests to etcd and the size of the objects stored in it, while the
+// scheduler and the controller manager are proxied by the API server under the metrics.eks.amazonaws.com group.
+var controlPlaneJobs = []controlPlaneJob{
+	{
+		name: "kube-apiserver",
+		path: "/metrics",
+		metrics: []string{
+			"apiserver_admission_webhook_admission_duration_seconds",
+			"apiserver_admission_webhook_rejection_count",
+			"apiserver_current_inflight_requests",
+			"apiserver_flowcontrol_rejected_requests_total",
+			"apiserver_request_duration_seconds",
+			"apiserver_request_total",
+			"apiserver_storage_objects",
+			"apiserver_storage_size_bytes",
+			"etcd_request_duration_seconds",
+			"rest_client_requests_total",
+		},
+	},
+	{
+		name: "kube-scheduler",
+		path: "/apis/metrics.eks.amazonaws.com/v1/ksh/container/metrics",
+		metrics: []string{
+			"scheduler_pending_pods",
+			"scheduler_schedule_attempts_total",
+			"scheduler_scheduling_attempt_duration_seconds",
+		},
+	},
+	{
+		name: "kube-controller-manager",
+		path: "/apis/metrics.eks.amazonaws.com/v1/kcm/container/metrics",
+		metrics: []string{
+			"workqueue_adds_total",
+			"workqueue_depth",
+			"workqueue_queue_duration_seconds",
+		},
+	},
+}
+
+func controlPlaneMetricsEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	mode := instance.Spec.Mode
+	return (mode == v1alpha1.ModeDeployment || mode == v1alpha1.ModeStatefulSet) &&
+		instance.Spec.ControlPlaneMetrics != nil && instance.Spec.ControlPlaneMetrics.Enabled
+}
+
+// withControlPlaneMetrics merges the pipeline scraping the control plane into the OpenTelemetry configuration of the
+// instance.
+func withControlPlaneMetrics(params manifests.Params) (manifests.Params, error) {
+	if !controlPlaneMetricsEnabled(params.OtelCol) {
+		return params, nil
+	}
+	preset, err := controlPlaneMetricsOtelConfig(params.OtelCol)
+	if err != nil {
+		return params, err
+	}
+	return withOtelConfigPreset(params, preset)
+}
+
+// withOtelConfigPreset merges a pipeline rendered by the operator into the OpenTelemetry configuration of the
+// instance, so that it is rendered, mounted and hashed like the configuration set by the user. The components of the
+// user take precedence over the ones of the pipeline sharing their names.
+func withOtelConfigPreset(params manifests.Params, preset map[interface{}]interface{}) (manifests.Params, error) {
+	conf, err := adapters.ConfigFromString(params.OtelCol.Spec.OtelConfig)
+	if err != nil {
+		return params, err
+	}
+	mergeOtelConfig(conf, preset)
+	out, err := yaml.Marshal(conf)
+	if err != nil {
+		return params, err
+	}
+
+	instance := *params.OtelCol.DeepCopy()
+	instance.Spec.OtelConfig = string(out)
+	presetParams := params
+	presetParams.OtelCol = instance
+	return presetParams, nil
+}
+
+// controlPlaneMetricsOtelConfig renders the pipeline scraping the control plane through the API server with the
+// token of the agent, and exporting the curated metrics as Container Insights Prometheus metrics.
+func controlPlaneMetricsOtelConfig(instance v1alpha1.AmazonCloudWatchAgent) (map[interface{}]interface{}, error) {
+	cluster := clusterName(instance, instance.Spec.ControlPlaneMetrics.ClusterName)
+	if cluster == "" {
+		return nil, errNoControlPlaneMetricsClusterName
+	}
+
+	var scrapeConfigs []interface{}
+	var metrics []string
+	for _, job := range controlPlaneJobs {
+		scrapeConfigs = append(scrapeConfigs, map[string]interface{}{
+			"job_name":       job.name,
+			"scheme":         "https",
+			"metrics_path":   job.path,
+			"authorization":  map[string]interface{}{"credentials_file": serviceAccountDir + "/token"},
+			"tls_config":     map[string]interface{}{"ca_file": serviceAccountDir + "/ca.crt"},
+			"static_configs": []interface{}{map[string]interface{}{"targets": []string{kubernetesAPIServer}}},
+			// histograms are scraped as their bucket, sum and count series
+			"metric_relabel_configs": []interface{}{map[string]interface{}{
+				"source_labels": []string{"__name__"},
+				"regex":         fmt.Sprintf("(%s)(_bucket|_sum|_count)?", strings.Join(job.metrics, "|")),
+				"action":        "keep",
+			}},
+		})
+		metrics = append(metrics, job.metrics...)
+	}
+
+	exporter := map[string]interface{}{
+		"namespace":                        controlPlaneNamespace,
+		"log_group_name":                   fmt.Sprintf("/aws/containerinsights/%s/prometheus", cluster),
+		"log_stream_name":                  controlPlaneLogStream,
+		"dimension_rollup_option":          "NoDimensionRollup",
+		"resource_to_telemetry_conversion": map[string]interface{}{"enabled": true},
+		"metric_declarations": []interface{}{map[string]interface{}{
+			"dimensions":            [][]string{{"ClusterName", "job"}},
+			"metric_name_selectors": []string{fmt.Sprintf("^(%s)$", strings.Join(metrics, "|"))},
+		}},
+	}
+	setExporterAWSConfig(exporter, instance.Spec.AWS)
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"receivers": map[string]interface{}{
+			controlPlaneReceiver: map[string]interface{}{
+				"config": map[string]interface{}{"scrape_configs": scrapeConfigs},
+			},
+		},
+		"processors": map[string]interface{}{
+			controlPlaneProcessor: map[string]interface{}{
+				"attributes": []interface{}{
+					map[string]interface{}{"key": "ClusterName", "value": cluster, "action": "upsert"},
+					map[string]interface{}{"key": "job", "from_attribute": "service.name", "action": "upsert"},
+				},
+			},
+		},
+		"exporters": map[string]interface{}{controlPlaneExporter: exporter},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				controlPlanePipeline: map[string]interface{}{
+					"receivers":  []string{controlPlaneReceiver},
+					"processors": []string{controlPlaneProcessor},
+					"exporters":  []string{controlPlaneExporter},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adapters.ConfigFromString(string(out))
+}
+
+// mergeOtelConfig merges the sections of src into dst, keeping the values already set in dst.
+func mergeOtelConfig(dst, src map[interface{}]interface{}) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok || existing == nil {
+			dst[key] = value
+			continue
+		}
+		existingMap, ok := existing.(map[interface{}]interface{})
+		if valueMap, isMap := value.(map[interface{}]interface{}); ok && isMap {
+			mergeOtelConfig(existingMap, valueMap)
+		}
+	}
+}
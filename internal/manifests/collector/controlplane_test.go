@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestControlPlaneMetrics(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+	params.OtelCol.Spec.OtelConfig = `
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`
+	params.OtelCol.Spec.AWS = &v1alpha1.AWSSpec{Region: "eu-west-1"}
+	params.OtelCol.Spec.ControlPlaneMetrics = &v1alpha1.ControlPlaneMetricsSpec{Enabled: true}
+
+	presetParams, err := withControlPlaneMetrics(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	receivers := conf["receivers"].(map[interface{}]interface{})
+	assert.Contains(t, receivers, "otlp")
+	assert.Contains(t, receivers, controlPlaneReceiver)
+	pipelines := conf["service"].(map[interface{}]interface{})["pipelines"].(map[interface{}]interface{})
+	assert.Contains(t, pipelines, "traces")
+	assert.Contains(t, pipelines, controlPlanePipeline)
+
+	exporter := conf["exporters"].(map[interface{}]interface{})[controlPlaneExporter].(map[interface{}]interface{})
+	assert.Equal(t, "/aws/containerinsights/my-cluster/prometheus", exporter["log_group_name"])
+	assert.Equal(t, "eu-west-1", exporter["region"])
+
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "/apis/metrics.eks.amazonaws.com/v1/kcm/container/metrics")
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "etcd_request_duration_seconds")
+	assert.NotEqual(t, params.OtelCol.Spec.OtelConfig, presetParams.OtelCol.Spec.OtelConfig, "the instance must not be modified")
+}
+
+func TestControlPlaneMetricsKeepsUserComponents(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeStatefulSet)
+	params.OtelCol.Spec.OtelConfig = `
+exporters:
+  awsemf/eks_control_plane:
+    namespace: Custom
+`
+	params.OtelCol.Spec.ControlPlaneMetrics = &v1alpha1.ControlPlaneMetricsSpec{Enabled: true, ClusterName: "my-cluster"}
+
+	presetParams, err := withControlPlaneMetrics(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+	exporter := conf["exporters"].(map[interface{}]interface{})[controlPlaneExporter].(map[interface{}]interface{})
+	assert.Equal(t, "Custom", exporter["namespace"])
+	assert.Equal(t, "/aws/containerinsights/my-cluster/prometheus", exporter["log_group_name"])
+}
+
+func TestControlPlaneMetricsDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.ControlPlaneMetrics = &v1alpha1.ControlPlaneMetricsSpec{Enabled: true, ClusterName: "my-cluster"}
+
+	presetParams, err := withControlPlaneMetrics(params)
+	require.NoError(t, err)
+	assert.Equal(t, params.OtelCol.Spec.OtelConfig, presetParams.OtelCol.Spec.OtelConfig)
+}
+
+func TestControlPlaneMetricsWithoutClusterName(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.Config = `{}`
+	params.OtelCol.Spec.ControlPlaneMetrics = &v1alpha1.ControlPlaneMetricsSpec{Enabled: true}
+
+	_, err := withControlPlaneMetrics(params)
+	assert.ErrorIs(t, err, errNoControlPlaneMetricsClusterName)
+}
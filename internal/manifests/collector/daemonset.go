@@ -15,14 +15,24 @@ import (
 
 // DaemonSet builds the deployment for the given instance.
 func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
-	name := naming.Collector(params.OtelCol.Name)
+	ds := daemonSet(params, naming.Collector(params.OtelCol.Name), naming.ConfigMap(params.OtelCol.Name))
+	affinity := params.OtelCol.Spec.Affinity
+	// the nodes exposing EFA devices get their agents from the EFA DaemonSet
+	if efaMetricsEnabled(params.OtelCol) {
+		affinity = selectNodes(affinity, corev1.NodeSelectorOpNotIn, params.EFANodes)
+	}
+	ds.Spec.Template.Spec.Affinity = excludeNodeGroups(affinity, nodeGroups(params.OtelCol))
+	return ds
+}
+
+func daemonSet(params manifests.Params, name string, configMapName string) *appsv1.DaemonSet {
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
 
 	annotations := Annotations(params.OtelCol)
 	podAnnotations := PodAnnotations(params.OtelCol)
 	return &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        naming.Collector(params.OtelCol.Name),
+			Name:        name,
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -37,20 +47,24 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: ServiceAccountName(params.OtelCol),
-					InitContainers:     params.OtelCol.Spec.InitContainers,
-					Containers:         append(params.OtelCol.Spec.AdditionalContainers, Container(params.Config, params.Log, params.OtelCol, true)),
-					Volumes:            Volumes(params.Config, params.OtelCol),
-					Tolerations:        params.OtelCol.Spec.Tolerations,
-					NodeSelector:       params.OtelCol.Spec.NodeSelector,
-					HostNetwork:        params.OtelCol.Spec.HostNetwork,
-					DNSPolicy:          getDNSPolicy(params.OtelCol),
-					SecurityContext:    params.OtelCol.Spec.PodSecurityContext,
-					PriorityClassName:  params.OtelCol.Spec.PriorityClassName,
-					Affinity:           params.OtelCol.Spec.Affinity,
+					ServiceAccountName:            ServiceAccountName(params.OtelCol),
+					InitContainers:                params.OtelCol.Spec.InitContainers,
+					Containers:                    podContainers(params.Config, params.Log, params.OtelCol),
+					ShareProcessNamespace:         shareProcessNamespace(params.OtelCol),
+					Volumes:                       volumes(params.Config, params.OtelCol, configMapName),
+					Tolerations:                   params.OtelCol.Spec.Tolerations,
+					NodeSelector:                  params.OtelCol.Spec.NodeSelector,
+					HostNetwork:                   params.OtelCol.Spec.HostNetwork,
+					DNSPolicy:                     getDNSPolicy(params.OtelCol),
+					DNSConfig:                     params.OtelCol.Spec.DNSConfig,
+					SecurityContext:               podSecurityContext(params.OtelCol),
+					PriorityClassName:             params.OtelCol.Spec.PriorityClassName,
+					Affinity:                      params.OtelCol.Spec.Affinity,
+					TerminationGracePeriodSeconds: params.OtelCol.Spec.TerminationGracePeriodSeconds,
 				},
 			},
-			UpdateStrategy: params.OtelCol.Spec.UpdateStrategy,
+			UpdateStrategy:  params.OtelCol.Spec.UpdateStrategy,
+			MinReadySeconds: params.OtelCol.Spec.MinReadySeconds,
 		},
 	}
 }
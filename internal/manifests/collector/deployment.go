@@ -15,8 +15,11 @@ import (
 
 // Deployment builds the deployment for the given instance.
 func Deployment(params manifests.Params) *appsv1.Deployment {
-	name := naming.Collector(params.OtelCol.Name)
-	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
+	return deployment(params, naming.Collector(params.OtelCol.Name), naming.ConfigMap(params.OtelCol.Name), ComponentAmazonCloudWatchAgent)
+}
+
+func deployment(params manifests.Params, name string, configMapName string, component string) *appsv1.Deployment {
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, component, params.Config.LabelsFilter())
 
 	annotations := Annotations(params.OtelCol)
 	podAnnotations := PodAnnotations(params.OtelCol)
@@ -31,9 +34,11 @@ func Deployment(params manifests.Params) *appsv1.Deployment {
 		Spec: appsv1.DeploymentSpec{
 			Replicas: params.OtelCol.Spec.Replicas,
 			Selector: &metav1.LabelSelector{
-				MatchLabels: manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
+				MatchLabels: manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, component),
 			},
-			Strategy: params.OtelCol.Spec.DeploymentUpdateStrategy,
+			Strategy:                params.OtelCol.Spec.DeploymentUpdateStrategy,
+			MinReadySeconds:         params.OtelCol.Spec.MinReadySeconds,
+			ProgressDeadlineSeconds: params.OtelCol.Spec.ProgressDeadlineSeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
@@ -42,17 +47,19 @@ func Deployment(params manifests.Params) *appsv1.Deployment {
 				Spec: corev1.PodSpec{
 					ServiceAccountName:            ServiceAccountName(params.OtelCol),
 					InitContainers:                params.OtelCol.Spec.InitContainers,
-					Containers:                    append(params.OtelCol.Spec.AdditionalContainers, Container(params.Config, params.Log, params.OtelCol, true)),
-					Volumes:                       Volumes(params.Config, params.OtelCol),
+					Containers:                    podContainers(params.Config, params.Log, params.OtelCol),
+					ShareProcessNamespace:         shareProcessNamespace(params.OtelCol),
+					Volumes:                       volumes(params.Config, params.OtelCol, configMapName),
 					DNSPolicy:                     getDNSPolicy(params.OtelCol),
+					DNSConfig:                     params.OtelCol.Spec.DNSConfig,
 					HostNetwork:                   params.OtelCol.Spec.HostNetwork,
 					Tolerations:                   params.OtelCol.Spec.Tolerations,
 					NodeSelector:                  params.OtelCol.Spec.NodeSelector,
-					SecurityContext:               params.OtelCol.Spec.PodSecurityContext,
+					SecurityContext:               podSecurityContext(params.OtelCol),
 					PriorityClassName:             params.OtelCol.Spec.PriorityClassName,
 					Affinity:                      params.OtelCol.Spec.Affinity,
 					TerminationGracePeriodSeconds: params.OtelCol.Spec.TerminationGracePeriodSeconds,
-					TopologySpreadConstraints:     params.OtelCol.Spec.TopologySpreadConstraints,
+					TopologySpreadConstraints:     TopologySpreadConstraints(params.OtelCol),
 				},
 			},
 		},
@@ -0,0 +1,136 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// EFANodeGroup is the value of the node group label of the DaemonSet rendered for the nodes exposing EFA devices.
+	EFANodeGroup = "efa"
+	// EFAResource is the extended resource the EFA device plugin advertises on the nodes with EFA devices.
+	EFAResource corev1.ResourceName = "vpc.amazonaws.com/efa"
+
+	// efaConfig enables the collection of the EFA metrics by the kubernetes section of the agent.
+	efaConfig = `{"logs":{"metrics_collected":{"kubernetes":{"accelerated_compute_metrics":true}}}}`
+)
+
+// efaHostMounts are the counters of the EFA devices, which are symbolic links into /sys/devices, and the socket of
+// the kubelet telling which pods the devices are allocated to.
+var efaHostMounts = []v1alpha1.HostMount{
+	{Name: naming.EFADevicesVolume(), Path: "/sys/class/infiniband"},
+	{Name: naming.SysDevicesVolume(), Path: "/sys/devices"},
+	{Name: naming.PodResourcesVolume(), Path: "/var/lib/kubelet/pod-resources"},
+}
+
+// EFANodes returns the sorted names of the nodes on which the EFA device plugin advertises EFA devices, or nil when
+// the instance does not collect the EFA metrics.
+func EFANodes(ctx context.Context, c client.Reader, instance v1alpha1.AmazonCloudWatchAgent) ([]string, error) {
+	if !efaMetricsEnabled(instance) {
+		return nil, nil
+	}
+	var nodes corev1.NodeList
+	if err := c.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, node := range nodes.Items {
+		if HasEFADevices(&node) {
+			names = append(names, node.Name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// HasEFADevices tells whether the EFA device plugin advertises EFA devices on the node.
+func HasEFADevices(node *corev1.Node) bool {
+	quantity, ok := node.Status.Allocatable[EFAResource]
+	return ok && !quantity.IsZero()
+}
+
+// EFADaemonSet returns the DaemonSet of the agents of the nodes exposing EFA devices, or nil when the instance does
+// not collect the EFA metrics or no such node was found.
+func EFADaemonSet(params manifests.Params) (*appsv1.DaemonSet, error) {
+	if !efaMetricsEnabled(params.OtelCol) || len(params.EFANodes) == 0 {
+		return nil, nil
+	}
+	efaParams, err := efaMetricsParams(params)
+	if err != nil {
+		return nil, err
+	}
+	name := naming.NodeGroup(params.OtelCol.Name, EFANodeGroup)
+	ds := daemonSet(efaParams, name, name)
+	// the labels map is shared by the DaemonSet and its pod template
+	ds.Labels[NodeGroupLabel] = EFANodeGroup
+	ds.Spec.Selector.MatchLabels[NodeGroupLabel] = EFANodeGroup
+	affinity := selectNodes(params.OtelCol.Spec.Affinity, corev1.NodeSelectorOpIn, params.EFANodes)
+	ds.Spec.Template.Spec.Affinity = excludeNodeGroups(affinity, nodeGroups(params.OtelCol))
+	return ds, nil
+}
+
+// efaConfigMap returns the configuration of the agents of the nodes exposing EFA devices, or nil when the instance
+// does not collect the EFA metrics or no such node was found.
+func efaConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
+	if !efaMetricsEnabled(params.OtelCol) || len(params.EFANodes) == 0 {
+		return nil, nil
+	}
+	efaParams, err := efaMetricsParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return agentConfigMap(efaParams, naming.NodeGroup(params.OtelCol.Name, EFANodeGroup))
+}
+
+func efaMetricsEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode == v1alpha1.ModeDaemonSet && instance.Spec.EFAMetrics != nil && instance.Spec.EFAMetrics.Enabled
+}
+
+// efaMetricsParams returns the params as seen by the agents of the nodes exposing EFA devices: the accelerated
+// compute metrics are enabled and the devices are mounted, on top of the settings of the default agents.
+func efaMetricsParams(params manifests.Params) (manifests.Params, error) {
+	efaParams, err := nodeGroupParams(params, v1alpha1.NodeGroup{Name: EFANodeGroup, Config: efaConfig})
+	if err != nil {
+		return params, err
+	}
+	efaParams.OtelCol.Spec.HostMounts = append(efaParams.OtelCol.Spec.HostMounts, efaHostMounts...)
+	return efaParams, nil
+}
+
+// selectNodes adds a required node affinity to the given affinity, which selects the nodes by their names with the
+// given operator.
+func selectNodes(affinity *corev1.Affinity, operator corev1.NodeSelectorOperator, names []string) *corev1.Affinity {
+	if len(names) == 0 {
+		return affinity
+	}
+	result := affinity.DeepCopy()
+	if result == nil {
+		result = &corev1.Affinity{}
+	}
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+	}
+	// the terms are ORed, so that the requirement is added to each of them
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchFields = append(required.NodeSelectorTerms[i].MatchFields,
+			corev1.NodeSelectorRequirement{Key: metav1.ObjectNameField, Operator: operator, Values: names})
+	}
+	result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func efaNode(name string, devices string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if devices != "" {
+		node.Status.Allocatable = corev1.ResourceList{EFAResource: resource.MustParse(devices)}
+	}
+	return node
+}
+
+func TestEFANodes(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		efaNode("node-c", "32"),
+		efaNode("node-b", ""),
+		efaNode("node-a", "4"),
+		efaNode("node-d", "0"),
+	).Build()
+	instance := paramsWithMode(v1alpha1.ModeDaemonSet).OtelCol
+
+	nodes, err := EFANodes(context.Background(), c, instance)
+	require.NoError(t, err)
+	assert.Nil(t, nodes)
+
+	instance.Spec.EFAMetrics = &v1alpha1.EFAMetricsSpec{Enabled: true}
+	nodes, err = EFANodes(context.Background(), c, instance)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a", "node-c"}, nodes)
+}
+
+func TestEFADaemonSet(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`
+	params.OtelCol.Spec.EFAMetrics = &v1alpha1.EFAMetricsSpec{Enabled: true}
+	params.OtelCol.Spec.NodeGroups = []v1alpha1.NodeGroup{{
+		Name:         "gpu",
+		NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p4d.24xlarge"},
+	}}
+
+	ds, err := EFADaemonSet(params)
+	require.NoError(t, err)
+	assert.Nil(t, ds, "no node exposes EFA devices")
+
+	params.EFANodes = []string{"node-a", "node-c"}
+	ds, err = EFADaemonSet(params)
+	require.NoError(t, err)
+	require.NotNil(t, ds)
+	assert.Equal(t, "test-efa", ds.Name)
+	assert.Equal(t, EFANodeGroup, ds.Spec.Selector.MatchLabels[NodeGroupLabel])
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"p4d.24xlarge"}}},
+		MatchFields:      []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a", "node-c"}}},
+	}}, ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	mounts := map[string]string{}
+	for _, mount := range ds.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mounts[mount.Name] = mount.MountPath
+		if mount.Name != naming.ConfigMapVolume() {
+			assert.True(t, mount.ReadOnly, mount.Name)
+		}
+	}
+	assert.Equal(t, "/sys/class/infiniband", mounts[naming.EFADevicesVolume()])
+	assert.Equal(t, "/sys/devices", mounts[naming.SysDevicesVolume()])
+	assert.Equal(t, "/var/lib/kubelet/pod-resources", mounts[naming.PodResourcesVolume()])
+
+	configMap, err := efaConfigMap(params)
+	require.NoError(t, err)
+	require.NotNil(t, configMap)
+	assert.Equal(t, "test-efa", configMap.Name)
+	assert.Contains(t, configMap.Data["cwagentconfig.json"], `"accelerated_compute_metrics":true`)
+
+	// the default agents leave the nodes exposing EFA devices to the EFA DaemonSet
+	terms := DaemonSet(params).Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-a", "node-c"}}}, terms[0].MatchFields)
+}
+
+func TestEFADaemonSetOutsideDaemonSetMode(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.EFAMetrics = &v1alpha1.EFAMetricsSpec{Enabled: true}
+	params.EFANodes = []string{"node-a"}
+
+	ds, err := EFADaemonSet(params)
+	require.NoError(t, err)
+	assert.Nil(t, ds)
+}
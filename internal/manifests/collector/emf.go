@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"gopkg.in/yaml.v2"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+// withEMFConfig sets the typed EMF settings of the instance on the awsemf exporters of its OpenTelemetry
+// configuration, overriding the values set in the exporters. The exporters of the operator presets are merged later
+// and keep their own settings.
+func withEMFConfig(params manifests.Params) (manifests.Params, error) {
+	emf := params.OtelCol.Spec.EMF
+	if emf == nil || params.OtelCol.Spec.OtelConfig == "" {
+		return params, nil
+	}
+	conf, err := adapters.ConfigFromString(params.OtelCol.Spec.OtelConfig)
+	if err != nil {
+		return params, err
+	}
+	ids := adapters.EMFExporters(conf)
+	if len(ids) == 0 {
+		return params, nil
+	}
+	exporters := conf["exporters"].(map[interface{}]interface{})
+	for _, id := range ids {
+		exporter, ok := exporters[id].(map[interface{}]interface{})
+		if !ok {
+			exporter = map[interface{}]interface{}{}
+		}
+		setEMFConfig(exporter, emf)
+		exporters[id] = exporter
+	}
+	out, err := yaml.Marshal(conf)
+	if err != nil {
+		return params, err
+	}
+
+	instance := *params.OtelCol.DeepCopy()
+	instance.Spec.OtelConfig = string(out)
+	emfParams := params
+	emfParams.OtelCol = instance
+	return emfParams, nil
+}
+
+func setEMFConfig(exporter map[interface{}]interface{}, emf *v1alpha1.EMFSpec) {
+	if emf.Namespace != "" {
+		exporter["namespace"] = emf.Namespace
+	}
+	if emf.LogGroupName != "" {
+		exporter["log_group_name"] = emf.LogGroupName
+	}
+	if emf.LogStreamName != "" {
+		exporter["log_stream_name"] = emf.LogStreamName
+	}
+	if emf.DimensionRollupOption != "" {
+		exporter["dimension_rollup_option"] = emf.DimensionRollupOption
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestEMFConfig(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.OtelConfig = `
+exporters:
+  awsemf:
+  awsemf/app:
+    namespace: App
+    log_retention: 7
+  debug: {}
+`
+	params.OtelCol.Spec.EMF = &v1alpha1.EMFSpec{
+		Namespace:             "MyApp",
+		LogGroupName:          "/aws/containerinsights/{ClusterName}/performance",
+		DimensionRollupOption: "NoDimensionRollup",
+	}
+
+	emfParams, err := withEMFConfig(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(emfParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	exporters := conf["exporters"].(map[interface{}]interface{})
+	for _, id := range []string{"awsemf", "awsemf/app"} {
+		exporter := exporters[id].(map[interface{}]interface{})
+		assert.Equal(t, "MyApp", exporter["namespace"])
+		assert.Equal(t, "/aws/containerinsights/{ClusterName}/performance", exporter["log_group_name"])
+		assert.Equal(t, "NoDimensionRollup", exporter["dimension_rollup_option"])
+		assert.NotContains(t, exporter, "log_stream_name")
+	}
+	assert.Equal(t, 7, exporters["awsemf/app"].(map[interface{}]interface{})["log_retention"])
+	assert.Equal(t, map[interface{}]interface{}{}, exporters["debug"])
+	assert.NotEqual(t, params.OtelCol.Spec.OtelConfig, emfParams.OtelCol.Spec.OtelConfig, "the instance must not be modified")
+}
+
+func TestEMFConfigWithoutEMFExporter(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.OtelConfig = `
+exporters:
+  debug: {}
+`
+	params.OtelCol.Spec.EMF = &v1alpha1.EMFSpec{Namespace: "MyApp"}
+
+	emfParams, err := withEMFConfig(params)
+	require.NoError(t, err)
+	assert.Equal(t, params.OtelCol.Spec.OtelConfig, emfParams.OtelCol.Spec.OtelConfig)
+}
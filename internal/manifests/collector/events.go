@@ -0,0 +1,204 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// ComponentKubernetesEvents is the component of the agent collecting the Kubernetes events, which is kept apart
+	// from the agents of the instance so that it neither joins their Service nor their selectors.
+	ComponentKubernetesEvents = "amazon-cloudwatch-agent-events"
+
+	kubernetesEventsPipeline  = "logs/k8s_events"
+	kubernetesEventsReceiver  = "k8s_events"
+	kubernetesEventsExporter  = "awscloudwatchlogs/k8s_events"
+	kubernetesEventsLogStream = "kubernetes-events"
+)
+
+var errNoKubernetesEventsLogGroup = errors.New("the log group of the Kubernetes events is unknown, set the clusterName or the logGroupName of kubernetesEvents")
+
+// KubernetesEventsDeployment returns the single replica Deployment of the agent collecting the Kubernetes events of
+// the cluster, or nil when the instance does not collect them.
+func KubernetesEventsDeployment(params manifests.Params) (*appsv1.Deployment, error) {
+	if !kubernetesEventsEnabled(params.OtelCol) {
+		return nil, nil
+	}
+	eventsParams, err := kubernetesEventsParams(params)
+	if err != nil {
+		return nil, err
+	}
+	name := naming.KubernetesEvents(params.OtelCol.Name)
+	return deployment(eventsParams, name, name, ComponentKubernetesEvents), nil
+}
+
+// kubernetesEventsConfigMap returns the configuration of the agent collecting the Kubernetes events, or nil when the
+// instance does not collect them.
+func kubernetesEventsConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
+	if !kubernetesEventsEnabled(params.OtelCol) {
+		return nil, nil
+	}
+	eventsParams, err := kubernetesEventsParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return agentConfigMap(eventsParams, naming.KubernetesEvents(params.OtelCol.Name))
+}
+
+func kubernetesEventsEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode != v1alpha1.ModeSidecar && instance.Spec.KubernetesEvents != nil && instance.Spec.KubernetesEvents.Enabled
+}
+
+// kubernetesEventsParams returns the params of the agent collecting the Kubernetes events. It runs the events
+// pipeline only, with the agent section of the instance configuration so that it sends them with the same region
+// and credentials, and without any of the node level settings of the instance.
+func kubernetesEventsParams(params manifests.Params) (manifests.Params, error) {
+	instance := *params.OtelCol.DeepCopy()
+	agentConfig, err := agentSection(instance.Spec.Config)
+	if err != nil {
+		return params, err
+	}
+	otelConfig, err := kubernetesEventsOtelConfig(params.OtelCol)
+	if err != nil {
+		return params, err
+	}
+
+	one := int32(1)
+	instance.Spec.Mode = v1alpha1.ModeDeployment
+	instance.Spec.Replicas = &one
+	instance.Spec.MinReplicas = nil
+	instance.Spec.MaxReplicas = nil
+	instance.Spec.Autoscaler = nil
+	instance.Spec.Config = agentConfig
+	instance.Spec.OtelConfig = otelConfig
+	instance.Spec.Prometheus = v1alpha1.PrometheusConfig{}
+	instance.Spec.TargetAllocator = v1alpha1.AmazonCloudWatchAgentTargetAllocator{}
+	instance.Spec.Ports = nil
+	instance.Spec.NodeGroups = nil
+	instance.Spec.Windows = nil
+	instance.Spec.Hybrid = nil
+	instance.Spec.HostNetwork = false
+	instance.Spec.HostMounts = nil
+	instance.Spec.ContainerRuntime = nil
+	instance.Spec.AdditionalContainers = nil
+	instance.Spec.LivenessProbe = nil
+	instance.Spec.ReadinessProbe = nil
+	instance.Spec.StartupProbe = nil
+
+	eventsParams := params
+	eventsParams.OtelCol = instance
+	return eventsParams, nil
+}
+
+// agentSection keeps the agent section of the JSON configuration only.
+func agentSection(config string) (string, error) {
+	section := map[string]interface{}{}
+	if config != "" {
+		conf, err := adapters.ConfigFromJSONString(config)
+		if err != nil {
+			return "", err
+		}
+		if agent, ok := conf["agent"]; ok {
+			section["agent"] = agent
+		}
+	}
+	out, err := json.Marshal(section)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// kubernetesEventsOtelConfig renders the pipeline receiving the Kubernetes events and exporting them to CloudWatch
+// Logs.
+func kubernetesEventsOtelConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	events := instance.Spec.KubernetesEvents
+	logGroup, err := kubernetesEventsLogGroup(instance)
+	if err != nil {
+		return "", err
+	}
+
+	receiver := map[string]interface{}{"auth_type": "serviceAccount"}
+	if len(events.Namespaces) > 0 {
+		receiver["namespaces"] = events.Namespaces
+	}
+	exporter := map[string]interface{}{
+		"log_group_name":  logGroup,
+		"log_stream_name": kubernetesEventsLogStream,
+	}
+	setExporterAWSConfig(exporter, instance.Spec.AWS)
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"receivers": map[string]interface{}{kubernetesEventsReceiver: receiver},
+		"exporters": map[string]interface{}{kubernetesEventsExporter: exporter},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				kubernetesEventsPipeline: map[string]interface{}{
+					"receivers": []string{kubernetesEventsReceiver},
+					"exporters": []string{kubernetesEventsExporter},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// kubernetesEventsLogGroup returns the log group of the Kubernetes events: the one of the spec, or the Container
+// Insights one of the cluster.
+func kubernetesEventsLogGroup(instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	events := instance.Spec.KubernetesEvents
+	if events.LogGroupName != "" {
+		return events.LogGroupName, nil
+	}
+	cluster := clusterName(instance, events.ClusterName)
+	if cluster == "" {
+		return "", errNoKubernetesEventsLogGroup
+	}
+	return fmt.Sprintf("/aws/containerinsights/%s/events", cluster), nil
+}
+
+// clusterName returns the given cluster name, or the cluster_name of the kubernetes section of the agent
+// configuration of the instance.
+func clusterName(instance v1alpha1.AmazonCloudWatchAgent, name string) string {
+	if name != "" {
+		return name
+	}
+	conf, err := adapters.ConfigStructFromJSONString(instance.Spec.Config)
+	if err != nil || conf == nil {
+		return ""
+	}
+	return conf.GetKubernetesClusterName()
+}
+
+// setExporterAWSConfig sets the AWS settings of the instance on an exporter sending to CloudWatch Logs. The exporters
+// of the pipelines the operator renders are not configured by the agent section, unlike the ones of the agent.
+func setExporterAWSConfig(exporter map[string]interface{}, aws *v1alpha1.AWSSpec) {
+	if aws == nil {
+		return
+	}
+	if aws.Region != "" {
+		exporter["region"] = aws.Region
+	}
+	if aws.RoleARN != "" {
+		exporter["role_arn"] = aws.RoleARN
+	}
+	if aws.EndpointOverrides != nil && aws.EndpointOverrides.Logs != "" {
+		exporter["endpoint"] = aws.EndpointOverrides.Logs
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func kubernetesEventsTestSpec() v1alpha1.AmazonCloudWatchAgentSpec {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Config = `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+	params.OtelCol.Spec.KubernetesEvents = &v1alpha1.KubernetesEventsSpec{
+		Enabled:    true,
+		Namespaces: []string{"default", "kube-system"},
+	}
+	return params.OtelCol.Spec
+}
+
+func TestKubernetesEventsDeployment(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec = kubernetesEventsTestSpec()
+
+	d, err := KubernetesEventsDeployment(params)
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	assert.Equal(t, "test-k8s-events", d.Name)
+	assert.Equal(t, int32(1), *d.Spec.Replicas)
+	assert.Equal(t, ComponentKubernetesEvents, d.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+	assert.False(t, d.Spec.Template.Spec.HostNetwork)
+	require.NotEmpty(t, d.Spec.Template.Spec.Volumes)
+	require.NotNil(t, d.Spec.Template.Spec.Volumes[0].ConfigMap)
+	assert.Equal(t, "test-k8s-events", d.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+}
+
+func TestKubernetesEventsConfigMap(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec = kubernetesEventsTestSpec()
+
+	configMaps, err := ConfigMaps(params)
+	require.NoError(t, err)
+	found := false
+	for _, cm := range configMaps {
+		if cm.Name != "test-k8s-events" {
+			continue
+		}
+		found = true
+		agentConfig := cm.Data[params.Config.CollectorConfigMapEntry()]
+		assert.Contains(t, agentConfig, `"region":"us-west-2"`)
+		assert.NotContains(t, agentConfig, "metrics_collected")
+		otelConfig := cm.Data[params.Config.OtelCollectorConfigMapEntry()]
+		assert.Contains(t, otelConfig, "/aws/containerinsights/my-cluster/events")
+		assert.Contains(t, otelConfig, "kube-system")
+	}
+	assert.True(t, found, "the config map of the Kubernetes events is missing")
+}
+
+func TestKubernetesEventsDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+
+	d, err := KubernetesEventsDeployment(params)
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	params.OtelCol.Spec = kubernetesEventsTestSpec()
+	params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+	d, err = KubernetesEventsDeployment(params)
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+}
+
+func TestKubernetesEventsWithoutLogGroup(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec = kubernetesEventsTestSpec()
+	params.OtelCol.Spec.Config = `{"agent":{"region":"us-west-2"}}`
+
+	_, err := KubernetesEventsDeployment(params)
+	assert.ErrorIs(t, err, errNoKubernetesEventsLogGroup)
+
+	params.OtelCol.Spec.KubernetesEvents.LogGroupName = "events"
+	d, err := KubernetesEventsDeployment(params)
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+}
@@ -0,0 +1,397 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// ComponentFluentBit is the component of the Fluent Bit pods collecting the logs of the nodes.
+	ComponentFluentBit = "fluent-bit"
+
+	fluentBitConfigEntry  = "fluent-bit.conf"
+	fluentBitParsersEntry = "parsers.conf"
+	fluentBitConfigDir    = "/fluent-bit/etc/"
+	fluentBitStateDir     = "/var/fluent-bit/state"
+	fluentBitHTTPPort     = 2020
+)
+
+var (
+	errNoLogsClusterName = errors.New("the cluster of the logs is unknown, set the clusterName of logs")
+	errNoLogsRegion      = errors.New("the region of the logs is unknown, set the region of aws or of the agent section of the configuration")
+)
+
+// fluentBitParsers are the parsers of the host logs, which are not part of the parsers of the image.
+const fluentBitParsers = `[PARSER]
+    Name        syslog
+    Format      regex
+    Regex       ^(?<time>[^ ]* {1,2}[^ ]* [^ ]*) (?<host>[^ ]*) (?<ident>[a-zA-Z0-9_\/\.\-]*)(?:\[(?<pid>[0-9]+)\])?(?:[^\:]*\:)? *(?<message>.*)$
+    Time_Key    time
+    Time_Format %b %d %H:%M:%S
+`
+
+// logGroupPlaceholders maps the placeholders of the names of the application log groups to the fields of the
+// Kubernetes metadata of the records.
+var logGroupPlaceholders = map[string]string{
+	"{namespace}": "$kubernetes['namespace_name']",
+	"{pod}":       "$kubernetes['pod_name']",
+	"{container}": "$kubernetes['container_name']",
+	"{workload}":  "$kubernetes['labels']['app.kubernetes.io/name']",
+}
+
+// fluentBitHostPaths are the directories of the nodes Fluent Bit reads the logs from, or keeps its state in.
+var fluentBitHostPaths = []struct {
+	name     string
+	path     string
+	readOnly bool
+}{
+	{name: "fluentbitstate", path: fluentBitStateDir},
+	{name: "varlog", path: "/var/log", readOnly: true},
+	{name: "varlibdockercontainers", path: "/var/lib/docker/containers", readOnly: true},
+	{name: "runlogjournal", path: "/run/log/journal", readOnly: true},
+}
+
+// FluentBitDaemonSet returns the DaemonSet of Fluent Bit collecting the logs of the nodes, or nil when the instance
+// does not collect them.
+func FluentBitDaemonSet(params manifests.Params) (*appsv1.DaemonSet, error) {
+	if !fluentBitEnabled(params.OtelCol) {
+		return nil, nil
+	}
+	config, err := fluentBitConfig(params.OtelCol)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := params.OtelCol
+	logs := instance.Spec.Logs
+	name := naming.FluentBit(instance.Name)
+	image := logs.Image
+	if image == "" {
+		image = params.Config.FluentBitImage()
+	}
+	labels := manifestutils.Labels(instance.ObjectMeta, name, image, ComponentFluentBit, params.Config.LabelsFilter())
+	podAnnotations := map[string]string{
+		"amazon-cloudwatch-agent-operator-config/sha256": fmt.Sprintf("%x", sha256.Sum256([]byte(config+fluentBitParsersConfig(logs)))),
+	}
+	// Fluent Bit only runs on Linux
+	nodeSelector := maps.Clone(instance.Spec.NodeSelector)
+	if nodeSelector == nil {
+		nodeSelector = map[string]string{}
+	}
+	nodeSelector["kubernetes.io/os"] = "linux"
+
+	volumes := []corev1.Volume{{
+		Name: naming.ConfigMapVolume(),
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+		},
+	}}
+	volumeMounts := []corev1.VolumeMount{{Name: naming.ConfigMapVolume(), MountPath: fluentBitConfigDir}}
+	for _, hostPath := range fluentBitHostPaths {
+		volumes = append(volumes, corev1.Volume{
+			Name:         hostPath.name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: hostPath.path}},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: hostPath.name, MountPath: hostPath.path, ReadOnly: hostPath.readOnly})
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "HOST_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		{Name: "HOSTNAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+	}
+	env = proxyEnvVars(instance.Spec.Proxy, env)
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   instance.Namespace,
+			Labels:      labels,
+			Annotations: instance.Annotations,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: manifestutils.SelectorLabels(instance.ObjectMeta, ComponentFluentBit),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ServiceAccountName(instance),
+					Containers: []corev1.Container{{
+						Name:            ComponentFluentBit,
+						Image:           image,
+						ImagePullPolicy: instance.Spec.ImagePullPolicy,
+						Env:             env,
+						Resources:       fluentBitResources(logs.Resources),
+						VolumeMounts:    volumeMounts,
+					}},
+					Volumes: volumes,
+					// the kubernetes filter queries the metadata of the pods from the kubelet of the node
+					HostNetwork:                   true,
+					DNSPolicy:                     corev1.DNSClusterFirstWithHostNet,
+					NodeSelector:                  nodeSelector,
+					Tolerations:                   instance.Spec.Tolerations,
+					Affinity:                      instance.Spec.Affinity,
+					PriorityClassName:             instance.Spec.PriorityClassName,
+					TerminationGracePeriodSeconds: instance.Spec.TerminationGracePeriodSeconds,
+				},
+			},
+		},
+	}, nil
+}
+
+// fluentBitConfigMap returns the configuration of Fluent Bit, or nil when the instance does not collect the logs of
+// the nodes.
+func fluentBitConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
+	if !fluentBitEnabled(params.OtelCol) {
+		return nil, nil
+	}
+	config, err := fluentBitConfig(params.OtelCol)
+	if err != nil {
+		return nil, err
+	}
+	name := naming.FluentBit(params.OtelCol.Name)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      manifestutils.Labels(params.OtelCol.ObjectMeta, name, "", ComponentFluentBit, []string{}),
+			Annotations: params.OtelCol.Annotations,
+		},
+		Data: map[string]string{
+			fluentBitConfigEntry:  config,
+			fluentBitParsersEntry: fluentBitParsersConfig(params.OtelCol.Spec.Logs),
+		},
+	}, nil
+}
+
+func fluentBitEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode != v1alpha1.ModeSidecar && instance.Spec.Logs != nil && instance.Spec.Logs.Enabled
+}
+
+// fluentBitResources returns the resources of the Fluent Bit container, the ones of the Container Insights manifests
+// unless set.
+func fluentBitResources(resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if len(resources.Limits) > 0 || len(resources.Requests) > 0 {
+		return resources
+	}
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("100Mi"),
+		},
+	}
+}
+
+// fluentBitSection is a section of the Fluent Bit configuration, whose keys may repeat.
+type fluentBitSection struct {
+	name    string
+	entries [][2]string
+}
+
+func (s *fluentBitSection) set(key, value string) *fluentBitSection {
+	s.entries = append(s.entries, [2]string{key, value})
+	return s
+}
+
+// fluentBitConfig renders the configuration of Fluent Bit, which sends the application, dataplane and host logs of
+// the node to their Container Insights log groups.
+func fluentBitConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	logs := instance.Spec.Logs
+	cluster := clusterName(instance, logs.ClusterName)
+	if cluster == "" {
+		return "", errNoLogsClusterName
+	}
+	region := agentRegion(instance)
+	if region == "" {
+		return "", errNoLogsRegion
+	}
+
+	var sections []*fluentBitSection
+	section := func(name string) *fluentBitSection {
+		s := &fluentBitSection{name: name}
+		sections = append(sections, s)
+		return s
+	}
+	tail := func(tag, path, db string) *fluentBitSection {
+		return section("INPUT").set("Name", "tail").set("Tag", tag).set("Path", path).
+			set("DB", fmt.Sprintf("%s/%s", fluentBitStateDir, db)).
+			set("Mem_Buf_Limit", "50MB").set("Skip_Long_Lines", "On").set("Refresh_Interval", "10").
+			set("Read_from_Head", "Off")
+	}
+	logGroups := logs.LogGroups
+	if logGroups == nil {
+		logGroups = &v1alpha1.LogGroupsSpec{}
+	}
+	output := func(match, logGroup, template, streamPrefix string) {
+		name, recordTemplate := fluentBitLogGroup(template, cluster, fmt.Sprintf("/aws/containerinsights/%s/%s", cluster, logGroup))
+		out := section("OUTPUT").set("Name", "cloudwatch_logs").set("Match", match).set("region", region).
+			set("log_group_name", name)
+		if recordTemplate != "" {
+			out.set("log_group_template", recordTemplate)
+		}
+		out.set("log_stream_prefix", "${HOST_NAME}"+streamPrefix).set("auto_create_group", "true")
+		if logGroups.RetentionInDays > 0 {
+			out.set("log_retention_days", fmt.Sprint(logGroups.RetentionInDays))
+		}
+		out.set("extra_user_agent", "container-insights")
+		if aws := instance.Spec.AWS; aws != nil {
+			if aws.RoleARN != "" {
+				out.set("role_arn", aws.RoleARN)
+			}
+			if aws.EndpointOverrides != nil && aws.EndpointOverrides.Logs != "" {
+				out.set("endpoint", aws.EndpointOverrides.Logs)
+			}
+		}
+	}
+
+	section("SERVICE").set("Flush", "5").set("Grace", "30").set("Log_Level", "error").set("Daemon", "off").
+		set("Parsers_File", fluentBitParsersEntry).
+		set("HTTP_Server", "On").set("HTTP_Listen", "0.0.0.0").set("HTTP_Port", fmt.Sprint(fluentBitHTTPPort)).
+		set("storage.path", fluentBitStateDir+"/flb-storage/").set("storage.sync", "normal").
+		set("storage.checksum", "off").set("storage.backlog.mem_limit", "5M")
+
+	// application logs
+	tail("application.*", "/var/log/containers/*.log", "flb_container.db").
+		set("Exclude_Path", "/var/log/containers/cloudwatch-agent*, /var/log/containers/fluent-bit*, /var/log/containers/aws-node*, /var/log/containers/kube-proxy*").
+		set("multiline.parser", "docker, cri").set("Rotate_Wait", "30").set("storage.type", "filesystem")
+	if len(logs.MultilineParsers) > 0 {
+		section("FILTER").set("Name", "multiline").set("Match", "application.*").
+			set("multiline.key_content", "log").set("multiline.parser", strings.Join(logs.MultilineParsers, ", "))
+	}
+	for i, pattern := range logs.MultilinePatterns {
+		section("FILTER").set("Name", "multiline").set("Match_Regex", multilinePatternTag(pattern)).
+			set("multiline.key_content", "log").set("multiline.parser", multilinePatternParser(i))
+	}
+	// the labels are only added to the records when a log group is named after the workload label
+	labels := "Off"
+	if strings.Contains(logGroups.Application, "{workload}") {
+		labels = "On"
+	}
+	section("FILTER").set("Name", "kubernetes").set("Match", "application.*").
+		set("Kube_URL", "https://kubernetes.default.svc:443").
+		set("Kube_Tag_Prefix", "application.var.log.containers.").
+		set("Merge_Log", "On").set("Merge_Log_Key", "log_processed").
+		set("K8S-Logging.Parser", "On").set("K8S-Logging.Exclude", "Off").
+		set("Labels", labels).set("Annotations", "Off").
+		set("Use_Kubelet", "On").set("Kubelet_Port", "10250").set("Buffer_Size", "0")
+	for _, filter := range logs.Filters {
+		action := "Regex"
+		if filter.Exclude {
+			action = "Exclude"
+		}
+		section("FILTER").set("Name", "grep").set("Match", "application.*").set(action, filter.Key+" "+filter.Regex)
+	}
+	output("application.*", "application", logGroups.Application, "-")
+
+	// dataplane logs
+	section("INPUT").set("Name", "systemd").set("Tag", "dataplane.systemd.*").
+		set("Systemd_Filter", "_SYSTEMD_UNIT=docker.service").
+		set("Systemd_Filter", "_SYSTEMD_UNIT=containerd.service").
+		set("Systemd_Filter", "_SYSTEMD_UNIT=kubelet.service").
+		set("DB", fluentBitStateDir+"/systemd.db").set("Path", "/var/log/journal").set("Read_From_Tail", "On")
+	tail("dataplane.tail.*", "/var/log/containers/aws-node*, /var/log/containers/kube-proxy*", "flb_dataplane_tail.db").
+		set("multiline.parser", "docker, cri").set("Rotate_Wait", "30").set("storage.type", "filesystem")
+	section("FILTER").set("Name", "modify").set("Match", "dataplane.systemd.*").
+		set("Rename", "_HOSTNAME hostname").set("Rename", "_SYSTEMD_UNIT systemd_unit").
+		set("Rename", "MESSAGE message").set("Remove_regex", "^((?!hostname|systemd_unit|message).)*$")
+	section("FILTER").set("Name", "aws").set("Match", "dataplane.*").set("imds_version", "v2")
+	output("dataplane.*", "dataplane", logGroups.Dataplane, "-")
+
+	// host logs
+	tail("host.dmesg", "/var/log/dmesg", "flb_dmesg.db").set("Key", "message")
+	tail("host.messages", "/var/log/messages", "flb_messages.db").set("Parser", "syslog")
+	tail("host.secure", "/var/log/secure", "flb_secure.db").set("Parser", "syslog")
+	section("FILTER").set("Name", "aws").set("Match", "host.*").set("imds_version", "v2")
+	output("host.*", "host", logGroups.Host, ".")
+
+	return renderFluentBitSections(sections), nil
+}
+
+func renderFluentBitSections(sections []*fluentBitSection) string {
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", s.name)
+		for _, entry := range s.entries {
+			fmt.Fprintf(&b, "    %-25s %s\n", entry[0], entry[1])
+		}
+	}
+	return b.String()
+}
+
+// fluentBitParsersConfig returns the parsers of the host logs, followed by the multiline parsers of the patterns of
+// the instance.
+func fluentBitParsersConfig(logs *v1alpha1.LogsSpec) string {
+	if len(logs.MultilinePatterns) == 0 {
+		return fluentBitParsers
+	}
+	var sections []*fluentBitSection
+	for i, pattern := range logs.MultilinePatterns {
+		continuePattern := pattern.ContinuePattern
+		if continuePattern == "" {
+			continuePattern = "^(?!" + strings.TrimPrefix(pattern.StartPattern, "^") + ")"
+		}
+		sections = append(sections, (&fluentBitSection{name: "MULTILINE_PARSER"}).
+			set("name", multilinePatternParser(i)).set("type", "regex").set("flush_timeout", "1000").
+			set("rule", fmt.Sprintf(`"start_state" "/%s/" "cont"`, pattern.StartPattern)).
+			set("rule", fmt.Sprintf(`"cont" "/%s/" "cont"`, continuePattern)))
+	}
+	return fluentBitParsers + "\n" + renderFluentBitSections(sections)
+}
+
+func multilinePatternParser(index int) string {
+	return fmt.Sprintf("multiline_pattern_%d", index)
+}
+
+// multilinePatternTag returns the regular expression matching the tags of the logs of the containers of a pattern,
+// which are named after the files of the logs, <pod>_<namespace>_<container>-<container id>.log.
+func multilinePatternTag(pattern v1alpha1.MultilinePattern) string {
+	namespace, container := "[^_]+", "[^_]+"
+	if pattern.Namespace != "" {
+		namespace = regexp.QuoteMeta(pattern.Namespace)
+	}
+	if pattern.Container != "" {
+		container = regexp.QuoteMeta(pattern.Container)
+	}
+	return fmt.Sprintf(`^application\.var\.log\.containers\.[^_]+_%s_%s-[0-9a-f]{64}\.log$`, namespace, container)
+}
+
+// fluentBitLogGroup resolves the {cluster} placeholder of the name of a log group. A name holding placeholders of the
+// records is returned as the log group template of Fluent Bit, with the default name for the records missing them.
+func fluentBitLogGroup(template, cluster, defaultName string) (name string, recordTemplate string) {
+	if template == "" {
+		return defaultName, ""
+	}
+	name = strings.ReplaceAll(template, "{cluster}", cluster)
+	recordTemplate = name
+	for placeholder, field := range logGroupPlaceholders {
+		recordTemplate = strings.ReplaceAll(recordTemplate, placeholder, field)
+	}
+	if recordTemplate == name {
+		return name, ""
+	}
+	return defaultName, recordTemplate
+}
@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func fluentBitParams() manifests.Params {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.Config = config.New(config.WithCollectorImage(defaultCollectorImage), config.WithFluentBitImage("public.ecr.aws/aws-observability/aws-for-fluent-bit:2.32.2"))
+	params.OtelCol.Spec.Config = `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+	params.OtelCol.Spec.Logs = &v1alpha1.LogsSpec{Enabled: true}
+	return params
+}
+
+func TestFluentBitDaemonSet(t *testing.T) {
+	params := fluentBitParams()
+	params.OtelCol.Spec.NodeSelector = map[string]string{"eks.amazonaws.com/compute-type": "ec2"}
+	params.OtelCol.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+
+	ds, err := FluentBitDaemonSet(params)
+	require.NoError(t, err)
+	require.NotNil(t, ds)
+	assert.Equal(t, "test-fluent-bit", ds.Name)
+	assert.Equal(t, ComponentFluentBit, ds.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+	assert.NotEmpty(t, ds.Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+
+	podSpec := ds.Spec.Template.Spec
+	assert.True(t, podSpec.HostNetwork)
+	assert.Equal(t, map[string]string{"eks.amazonaws.com/compute-type": "ec2", "kubernetes.io/os": "linux"}, podSpec.NodeSelector)
+	assert.Equal(t, params.OtelCol.Spec.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, "test", podSpec.ServiceAccountName)
+	assert.Equal(t, "test-fluent-bit", podSpec.Volumes[0].ConfigMap.Name)
+
+	require.Len(t, podSpec.Containers, 1)
+	container := podSpec.Containers[0]
+	assert.Equal(t, "public.ecr.aws/aws-observability/aws-for-fluent-bit:2.32.2", container.Image)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "varlog", MountPath: "/var/log", ReadOnly: true})
+	assert.Equal(t, "200Mi", container.Resources.Limits.Memory().String())
+}
+
+func TestFluentBitConfigMap(t *testing.T) {
+	params := fluentBitParams()
+	params.OtelCol.Spec.AWS = &v1alpha1.AWSSpec{RoleARN: "arn:aws:iam::123456789012:role/logs"}
+	params.OtelCol.Spec.Logs.MultilineParsers = []string{"java", "go"}
+	params.OtelCol.Spec.Logs.Filters = []v1alpha1.LogFilter{{Key: "$kubernetes['namespace_name']", Regex: "^kube-system$", Exclude: true}}
+
+	cm, err := fluentBitConfigMap(params)
+	require.NoError(t, err)
+	require.NotNil(t, cm)
+	assert.Equal(t, "test-fluent-bit", cm.Name)
+	assert.Equal(t, fluentBitParsers, cm.Data["parsers.conf"])
+
+	conf := cm.Data["fluent-bit.conf"]
+	for _, logGroup := range []string{"application", "dataplane", "host"} {
+		assert.Contains(t, conf, "/aws/containerinsights/my-cluster/"+logGroup)
+	}
+	assert.Contains(t, conf, "    region                    us-west-2\n")
+	assert.Contains(t, conf, "    role_arn                  arn:aws:iam::123456789012:role/logs\n")
+	assert.Contains(t, conf, "    multiline.parser          java, go\n")
+	assert.Contains(t, conf, "    Exclude                   $kubernetes['namespace_name'] ^kube-system$\n")
+}
+
+func TestFluentBitDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	ds, err := FluentBitDaemonSet(params)
+	assert.NoError(t, err)
+	assert.Nil(t, ds)
+
+	params = fluentBitParams()
+	params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+	ds, err = FluentBitDaemonSet(params)
+	assert.NoError(t, err)
+	assert.Nil(t, ds)
+}
+
+func TestFluentBitWithoutRegion(t *testing.T) {
+	params := fluentBitParams()
+	params.OtelCol.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+
+	_, err := FluentBitDaemonSet(params)
+	assert.ErrorIs(t, err, errNoLogsRegion)
+
+	params.OtelCol.Spec.AWS = &v1alpha1.AWSSpec{Region: "eu-west-1"}
+	cm, err := fluentBitConfigMap(params)
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data["fluent-bit.conf"], "eu-west-1")
+}
+
+func TestFluentBitLogGroups(t *testing.T) {
+	params := fluentBitParams()
+	params.OtelCol.Spec.Logs.LogGroups = &v1alpha1.LogGroupsSpec{
+		Application:     "/eks/{cluster}/{namespace}/{workload}",
+		Host:            "/eks/{cluster}/host",
+		RetentionInDays: 30,
+	}
+
+	cm, err := fluentBitConfigMap(params)
+	require.NoError(t, err)
+	conf := cm.Data["fluent-bit.conf"]
+	assert.Contains(t, conf, "    log_group_name            /aws/containerinsights/my-cluster/application\n")
+	assert.Contains(t, conf, "    log_group_template        /eks/my-cluster/$kubernetes['namespace_name']/$kubernetes['labels']['app.kubernetes.io/name']\n")
+	assert.Contains(t, conf, "    log_group_name            /aws/containerinsights/my-cluster/dataplane\n")
+	assert.Contains(t, conf, "    log_group_name            /eks/my-cluster/host\n")
+	assert.Contains(t, conf, "    Labels                    On\n")
+	assert.Equal(t, 3, strings.Count(conf, "    log_retention_days        30\n"))
+	assert.Equal(t, 1, strings.Count(conf, "log_group_template"))
+}
+
+func TestFluentBitMultilinePatterns(t *testing.T) {
+	params := fluentBitParams()
+	params.OtelCol.Spec.Logs.MultilinePatterns = []v1alpha1.MultilinePattern{
+		{Namespace: "payments", Container: "api", StartPattern: `^\d{4}-\d{2}-\d{2}`},
+		{StartPattern: `^\[`, ContinuePattern: `^\s+at\s`},
+	}
+
+	cm, err := fluentBitConfigMap(params)
+	require.NoError(t, err)
+	conf := cm.Data["fluent-bit.conf"]
+	assert.Contains(t, conf, "    Match_Regex               ^application\\.var\\.log\\.containers\\.[^_]+_payments_api-[0-9a-f]{64}\\.log$\n    multiline.key_content     log\n    multiline.parser          multiline_pattern_0\n")
+	assert.Contains(t, conf, "    Match_Regex               ^application\\.var\\.log\\.containers\\.[^_]+_[^_]+_[^_]+-[0-9a-f]{64}\\.log$\n    multiline.key_content     log\n    multiline.parser          multiline_pattern_1\n")
+	assert.Less(t, strings.Index(conf, "multiline_pattern_1"), strings.Index(conf, "Name                      kubernetes"))
+
+	parsers := cm.Data["parsers.conf"]
+	assert.True(t, strings.HasPrefix(parsers, fluentBitParsers))
+	assert.Contains(t, parsers, `    rule                      "start_state" "/^\d{4}-\d{2}-\d{2}/" "cont"`+"\n")
+	assert.Contains(t, parsers, `    rule                      "cont" "/^(?!\d{4}-\d{2}-\d{2})/" "cont"`+"\n")
+	assert.Contains(t, parsers, `    rule                      "cont" "/^\s+at\s/" "cont"`+"\n")
+
+	// the pods are restarted when only the parsers change
+	ds, err := FluentBitDaemonSet(params)
+	require.NoError(t, err)
+	params.OtelCol.Spec.Logs.MultilinePatterns[1].ContinuePattern = `^\s+`
+	updated, err := FluentBitDaemonSet(params)
+	require.NoError(t, err)
+	assert.NotEqual(t, ds.Spec.Template.Annotations, updated.Spec.Template.Annotations)
+}
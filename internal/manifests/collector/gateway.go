@@ -0,0 +1,101 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+var (
+	// HTTPRouteGVK is the kind of the Gateway API routes of the HTTP receivers.
+	HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	// GRPCRouteGVK is the kind of the Gateway API routes of the gRPC receivers.
+	GRPCRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GRPCRoute"}
+)
+
+// GatewayRoutes returns the Gateway API routes exposing each receiver of the instance on its own host, through the
+// parent Gateways of the instance.
+func GatewayRoutes(params manifests.Params) ([]*unstructured.Unstructured, error) {
+	if params.OtelCol.Spec.Ingress.Type != v1alpha1.IngressTypeGateway {
+		return nil, nil
+	}
+
+	if params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
+		params.Log.V(3).Info("ingress settings are not supported in sidecar mode")
+		return nil, nil
+	}
+
+	ports, err := servicePortsFromCfg(params.Log, params.OtelCol)
+
+	// if we have no ports, we don't need any route
+	if len(ports) == 0 || err != nil {
+		params.Log.V(1).Info(
+			"the instance's configuration didn't yield any ports to open, skipping gateway routes",
+			"instance.name", params.OtelCol.Name,
+			"instance.namespace", params.OtelCol.Namespace,
+		)
+		return nil, err
+	}
+
+	var parentRefs []interface{}
+	for _, ref := range params.OtelCol.Spec.Ingress.Gateway.ParentRefs {
+		parentRef := map[string]interface{}{"name": ref.Name}
+		if ref.Namespace != "" {
+			parentRef["namespace"] = ref.Namespace
+		}
+		if ref.SectionName != "" {
+			parentRef["sectionName"] = ref.SectionName
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	routes := make([]*unstructured.Unstructured, 0, len(ports))
+	for _, p := range ports {
+		portName := naming.PortName(p.Name, p.Port)
+		name := naming.Route(params.OtelCol.Name, p.Name)
+		spec := map[string]interface{}{
+			"parentRefs": parentRefs,
+			"rules": []interface{}{map[string]interface{}{
+				"backendRefs": []interface{}{map[string]interface{}{
+					"name": naming.Service(params.OtelCol.Name),
+					"port": int64(p.Port),
+				}},
+			}},
+		}
+		if params.OtelCol.Spec.Ingress.Hostname != "" {
+			spec["hostnames"] = []interface{}{fmt.Sprintf("%s.%s", portName, params.OtelCol.Spec.Ingress.Hostname)}
+		}
+
+		route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		if isGRPCPort(p) {
+			route.SetGroupVersionKind(GRPCRouteGVK)
+		} else {
+			route.SetGroupVersionKind(HTTPRouteGVK)
+		}
+		route.SetName(name)
+		route.SetNamespace(params.OtelCol.Namespace)
+		route.SetAnnotations(params.OtelCol.Spec.Ingress.Annotations)
+		route.SetLabels(manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{}))
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// isGRPCPort returns whether the receiver of the port speaks gRPC, from its app protocol or its name.
+func isGRPCPort(port corev1.ServicePort) bool {
+	if port.AppProtocol != nil {
+		return *port.AppProtocol == "grpc"
+	}
+	return strings.Contains(port.Name, "grpc")
+}
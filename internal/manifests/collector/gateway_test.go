@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestGatewayRoutes(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.Log = logr.Discard()
+	params.OtelCol.Spec.Config = `{"traces":{"traces_collected":{"otlp":{"grpc_endpoint":"0.0.0.0:4317","http_endpoint":"0.0.0.0:4318"}}},"metrics":{"metrics_collected":{"statsd":{}}}}`
+	params.OtelCol.Spec.Ingress = v1alpha1.Ingress{
+		Type:     v1alpha1.IngressTypeGateway,
+		Hostname: "telemetry.example.com",
+		Gateway: v1alpha1.GatewayRoutes{
+			ParentRefs: []v1alpha1.GatewayParentReference{{Name: "public", Namespace: "gateways", SectionName: "https"}},
+		},
+	}
+
+	routes, err := GatewayRoutes(params)
+	require.NoError(t, err)
+	require.Len(t, routes, 2, "the UDP listeners are not exposed")
+
+	byKind := map[string]*unstructured.Unstructured{}
+	for _, route := range routes {
+		byKind[route.GetKind()] = route
+		assert.Equal(t, "default", route.GetNamespace())
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "public", "namespace": "gateways", "sectionName": "https"}}, parentRefs)
+	}
+
+	grpc := byKind["GRPCRoute"]
+	require.NotNil(t, grpc)
+	hostnames, _, _ := unstructured.NestedStringSlice(grpc.Object, "spec", "hostnames")
+	assert.Equal(t, []string{"otlp-grpc-4317.telemetry.example.com"}, hostnames)
+	rules, _, _ := unstructured.NestedSlice(grpc.Object, "spec", "rules")
+	assert.Equal(t, []interface{}{map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "test", "port": int64(4317)}}}}, rules)
+
+	http := byKind["HTTPRoute"]
+	require.NotNil(t, http)
+	hostnames, _, _ = unstructured.NestedStringSlice(http.Object, "spec", "hostnames")
+	assert.Equal(t, []string{"otlp-http-4318.telemetry.example.com"}, hostnames)
+}
+
+func TestGatewayRoutesOtherIngressType(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.Log = logr.Discard()
+	params.OtelCol.Spec.Config = `{"traces":{"traces_collected":{"otlp":{}}}}`
+	params.OtelCol.Spec.Ingress.Type = v1alpha1.IngressTypeNginx
+
+	routes, err := GatewayRoutes(params)
+	require.NoError(t, err)
+	assert.Nil(t, routes)
+}
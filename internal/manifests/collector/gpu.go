@@ -0,0 +1,312 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// ComponentDcgmExporter is the component of the DCGM exporter pods exposing the metrics of the NVIDIA GPUs.
+	ComponentDcgmExporter = "dcgm-exporter"
+
+	dcgmExporterPort         = 9400
+	dcgmExporterConfigDir    = "/etc/dcgm-exporter"
+	dcgmExporterMetricsEntry = "dcp-metrics-included.csv"
+	podResourcesDir          = "/var/lib/kubelet/pod-resources"
+
+	gpuPipeline = "dcgm"
+)
+
+var errNoGPUMetricsClusterName = errors.New("the cluster of the GPU metrics is unknown, set the clusterName of gpuMetrics")
+
+// dcgmExporterMetrics are the DCGM fields of the GPU metrics of Container Insights.
+const dcgmExporterMetrics = `# DCGM field, Prometheus metric type, help message
+DCGM_FI_DEV_GPU_UTIL,      gauge, GPU utilization (in %).
+DCGM_FI_DEV_MEM_COPY_UTIL, gauge, Memory utilization (in %).
+DCGM_FI_DEV_FB_FREE,       gauge, Framebuffer memory free (in MiB).
+DCGM_FI_DEV_FB_USED,       gauge, Framebuffer memory used (in MiB).
+DCGM_FI_DEV_FB_TOTAL,      gauge, Framebuffer memory total (in MiB).
+DCGM_FI_DEV_GPU_TEMP,      gauge, GPU temperature (in C).
+DCGM_FI_DEV_POWER_USAGE,   gauge, Power draw (in W).
+`
+
+// dcgmExporterMIGMetrics are the DCGM fields of the GPU metrics of Container Insights on the nodes with MIG enabled.
+// The utilization of a GPU instance is the activity of its graphics engine, as DCGM does not report the utilization
+// of the GPU for its instances. The temperature and power draw are the ones of the whole GPU.
+const dcgmExporterMIGMetrics = `# DCGM field, Prometheus metric type, help message
+DCGM_FI_DEV_GPU_UTIL,          gauge, GPU utilization (in %).
+DCGM_FI_PROF_GR_ENGINE_ACTIVE, gauge, Ratio of time the graphics engine is active.
+DCGM_FI_DEV_FB_FREE,           gauge, Framebuffer memory free (in MiB).
+DCGM_FI_DEV_FB_USED,           gauge, Framebuffer memory used (in MiB).
+DCGM_FI_DEV_FB_TOTAL,          gauge, Framebuffer memory total (in MiB).
+DCGM_FI_DEV_GPU_TEMP,          gauge, GPU temperature (in C).
+DCGM_FI_DEV_POWER_USAGE,       gauge, Power draw (in W).
+`
+
+// migNodeSelector selects the nodes whose MIG configuration was applied by the NVIDIA MIG manager.
+var migNodeSelector = map[string]string{"nvidia.com/mig.config.state": "success"}
+
+// gpuNodeLabels are the labels of the nodes with NVIDIA GPUs: the one of the NVIDIA GPU feature discovery, and the
+// GPU manufacturer of the instance types of Karpenter and EKS Auto Mode.
+var gpuNodeLabels = []corev1.NodeSelectorRequirement{
+	{Key: "nvidia.com/gpu.present", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+	{Key: "karpenter.k8s.aws/instance-gpu-manufacturer", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvidia"}},
+	{Key: "eks.amazonaws.com/instance-gpu-manufacturer", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvidia"}},
+}
+
+// gpuMetricsPipeline exports the DCGM metrics per GPU and per container using them.
+var gpuMetricsPipeline = nodeExporterPipeline{
+	name:            gpuPipeline,
+	metricSelectors: []string{"^DCGM_FI_"},
+	dimensions:      [][]string{{"ClusterName"}, {"ClusterName", "Hostname", "gpu"}, {"ClusterName", "namespace", "pod", "container"}},
+}
+
+// gpuMIGDimensions exports the DCGM metrics per GPU instance, whose metrics are labeled with the index of their GPU
+// and their own.
+var gpuMIGDimensions = []string{"ClusterName", "Hostname", "gpu", "GPU_I_ID"}
+
+// DcgmExporterDaemonSet returns the DaemonSet of the DCGM exporter on the nodes with NVIDIA GPUs, or nil when the
+// instance does not collect the GPU metrics.
+func DcgmExporterDaemonSet(params manifests.Params) *appsv1.DaemonSet {
+	if !deploysDcgmExporter(params) {
+		return nil
+	}
+	return nodeExporterDaemonSet(params, dcgmExporter(params))
+}
+
+// DcgmExporterMIGDaemonSet returns the DaemonSet of the DCGM exporter on the nodes with MIG enabled, or nil when the
+// instance does not collect the metrics of the GPU instances.
+func DcgmExporterMIGDaemonSet(params manifests.Params) *appsv1.DaemonSet {
+	if !deploysDcgmExporterMIG(params) {
+		return nil
+	}
+	return nodeExporterDaemonSet(params, dcgmExporterMIG(params))
+}
+
+// DcgmExporterService returns the Service the agents scrape the DCGM exporters of their node through, or nil when the
+// instance deploys none.
+func DcgmExporterService(params manifests.Params) *corev1.Service {
+	if !deploysDcgmExporter(params) && !deploysDcgmExporterMIG(params) {
+		return nil
+	}
+	return nodeExporterService(params, dcgmExporter(params))
+}
+
+// dcgmExporterConfigMap returns the DCGM fields collected by the exporter, or nil when the instance does not collect
+// the GPU metrics.
+func dcgmExporterConfigMap(params manifests.Params) *corev1.ConfigMap {
+	if !deploysDcgmExporter(params) {
+		return nil
+	}
+	return nodeExporterConfigMap(params, dcgmExporter(params))
+}
+
+// dcgmExporterMIGConfigMap returns the DCGM fields collected by the exporter of the nodes with MIG enabled, or nil
+// when the instance does not collect the metrics of the GPU instances.
+func dcgmExporterMIGConfigMap(params manifests.Params) *corev1.ConfigMap {
+	if !deploysDcgmExporterMIG(params) {
+		return nil
+	}
+	return nodeExporterConfigMap(params, dcgmExporterMIG(params))
+}
+
+func gpuMetricsEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode == v1alpha1.ModeDaemonSet && instance.Spec.GPUMetrics != nil && instance.Spec.GPUMetrics.Enabled
+}
+
+func gpuMIGEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return gpuMetricsEnabled(instance) && instance.Spec.GPUMetrics.MIG != nil && instance.Spec.GPUMetrics.MIG.Enabled
+}
+
+// deploysDcgmExporter returns whether the instance deploys its DCGM exporter, which it does not when a DcgmExporter
+// resource runs one on the GPU nodes already.
+func deploysDcgmExporter(params manifests.Params) bool {
+	return gpuMetricsEnabled(params.OtelCol) && params.DcgmExporter == nil
+}
+
+// deploysDcgmExporterMIG returns whether the instance deploys the DCGM exporter of the nodes with MIG enabled, which
+// it does alongside a DcgmExporter resource too, as the exporters of the DcgmExporter resources do not map the GPU
+// instances to the pods using them.
+func deploysDcgmExporterMIG(params manifests.Params) bool {
+	return gpuMIGEnabled(params.OtelCol)
+}
+
+// ExistingDcgmExporter returns the DcgmExporter resource of the namespace, or of all namespaces when it is empty,
+// which deploys the DCGM exporter already, or nil when there is none or the instance does not collect the GPU metrics.
+func ExistingDcgmExporter(ctx context.Context, c client.Reader, instance v1alpha1.AmazonCloudWatchAgent, namespace string) (*v1alpha1.DcgmExporter, error) {
+	if !gpuMetricsEnabled(instance) {
+		return nil, nil
+	}
+	var exporters v1alpha1.DcgmExporterList
+	if err := c.List(ctx, &exporters, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if len(exporters.Items) == 0 {
+		return nil, nil
+	}
+	// the exporter of the namespace of the instance is preferred, else the first one by namespace and name
+	slices.SortFunc(exporters.Items, func(a, b v1alpha1.DcgmExporter) int {
+		if (a.Namespace == instance.Namespace) != (b.Namespace == instance.Namespace) {
+			if a.Namespace == instance.Namespace {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return &exporters.Items[0], nil
+}
+
+// gpuMIGNodeSelector returns the labels of the nodes with MIG enabled.
+func gpuMIGNodeSelector(gpu *v1alpha1.GPUMetricsSpec) map[string]string {
+	if len(gpu.MIG.NodeSelector) > 0 {
+		return gpu.MIG.NodeSelector
+	}
+	return migNodeSelector
+}
+
+// dcgmExporter returns the DCGM exporter of the instance.
+func dcgmExporter(params manifests.Params) nodeExporter {
+	gpu := params.OtelCol.Spec.GPUMetrics
+	image := gpu.Image
+	if image == "" {
+		image = params.Config.DcgmExporterImage()
+	}
+	metricsConfig := gpu.MetricsConfig
+	if metricsConfig == "" {
+		metricsConfig = dcgmExporterMetrics
+	}
+	affinity := gpu.Affinity
+	if affinity == nil {
+		affinity = nodeAffinity(gpuNodeLabels...)
+	}
+	// the nodes with MIG enabled are left to the exporter of the GPU instances
+	if gpuMIGEnabled(params.OtelCol) {
+		affinity = excludeNodeGroups(affinity, []v1alpha1.NodeGroup{{NodeSelector: gpuMIGNodeSelector(gpu)}})
+	}
+	rootUser := int64(0)
+	runAsNonRoot := false
+
+	return nodeExporter{
+		component:   ComponentDcgmExporter,
+		name:        naming.DcgmExporter(params.OtelCol.Name),
+		image:       image,
+		port:        dcgmExporterPort,
+		configDir:   dcgmExporterConfigDir,
+		configEntry: dcgmExporterMetricsEntry,
+		config:      metricsConfig,
+		container: corev1.Container{
+			Env: []corev1.EnvVar{
+				{Name: "DCGM_EXPORTER_COLLECTORS", Value: dcgmExporterConfigDir + "/" + dcgmExporterMetricsEntry},
+				{Name: "DCGM_EXPORTER_LISTEN", Value: fmt.Sprintf(":%d", dcgmExporterPort)},
+				// maps the GPUs to the pods using them through the pod resources of the kubelet
+				{Name: "DCGM_EXPORTER_KUBERNETES", Value: "true"},
+				// names the Hostname label of the metrics after the node rather than the pod
+				{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "podresources", MountPath: podResourcesDir, ReadOnly: true}},
+			// the profiling metrics of DCGM need the SYS_ADMIN capability
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:    &rootUser,
+				RunAsNonRoot: &runAsNonRoot,
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}},
+			},
+		},
+		volumes: []corev1.Volume{{
+			Name:         "podresources",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: podResourcesDir}},
+		}},
+		affinity:    affinity,
+		tolerations: []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		resources:   dcgmExporterResources(gpu.Resources),
+	}
+}
+
+// dcgmExporterMIG returns the DCGM exporter of the nodes with MIG enabled, which only differs from the one of the
+// other nodes by its fields and the mapping of the GPU instances to the pods. It shares the component of the other
+// exporter, so that the agents scrape both through the same Service.
+func dcgmExporterMIG(params manifests.Params) nodeExporter {
+	gpu := params.OtelCol.Spec.GPUMetrics
+	exporter := dcgmExporter(params)
+	exporter.name = naming.DcgmExporterMIG(params.OtelCol.Name)
+	exporter.config = gpu.MIG.MetricsConfig
+	if exporter.config == "" {
+		exporter.config = dcgmExporterMIGMetrics
+	}
+	exporter.affinity = gpu.Affinity
+	if exporter.affinity == nil {
+		exporter.affinity = nodeAffinity(gpuNodeLabels...)
+	}
+	exporter.nodeSelector = gpuMIGNodeSelector(gpu)
+	// the device plugin allocates the GPU instances by their device names rather than the UUIDs of their GPUs
+	exporter.container.Env = append(slices.Clip(exporter.container.Env), corev1.EnvVar{Name: "DCGM_EXPORTER_KUBERNETES_GPU_ID_TYPE", Value: "device-name"})
+	return exporter
+}
+
+// dcgmExporterResources returns the resources of the DCGM exporter container, the ones of the Container Insights
+// manifests unless set.
+func dcgmExporterResources(resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if len(resources.Limits) > 0 || len(resources.Requests) > 0 {
+		return resources
+	}
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("500Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("250m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+}
+
+// withGPUMetrics merges the pipeline scraping the DCGM exporters into the OpenTelemetry configuration of the instance:
+// the ones it deploys, and the one of the DcgmExporter resource deploying it already, if any.
+func withGPUMetrics(params manifests.Params) (manifests.Params, error) {
+	if !gpuMetricsEnabled(params.OtelCol) {
+		return params, nil
+	}
+	cluster := clusterName(params.OtelCol, params.OtelCol.Spec.GPUMetrics.ClusterName)
+	if cluster == "" {
+		return params, errNoGPUMetricsClusterName
+	}
+	pipeline := gpuMetricsPipeline
+	if gpuMIGEnabled(params.OtelCol) {
+		pipeline.dimensions = append(slices.Clip(pipeline.dimensions), gpuMIGDimensions)
+	}
+	var targets []string
+	if service := DcgmExporterService(params); service != nil {
+		targets = append(targets, serviceTarget(service))
+	}
+	if params.DcgmExporter != nil {
+		exporterParams := params
+		exporterParams.DcgmExp = *params.DcgmExporter
+		service, err := dcgmexporter.Service(exporterParams)
+		if err != nil {
+			return params, err
+		}
+		targets = append(targets, serviceTarget(service))
+	}
+	preset, err := nodeExporterOtelConfig(params.OtelCol, ComponentDcgmExporter, targets, pipeline, cluster)
+	if err != nil {
+		return params, err
+	}
+	return withOtelConfigPreset(params, preset)
+}
@@ -0,0 +1,208 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func gpuMetricsParams() manifests.Params {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.Config = config.New(config.WithCollectorImage(defaultCollectorImage), config.WithDcgmExporterImage("nvcr.io/nvidia/k8s/dcgm-exporter:3.3.7-3.5.0-ubuntu22.04"))
+	params.OtelCol.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+	params.OtelCol.Spec.GPUMetrics = &v1alpha1.GPUMetricsSpec{Enabled: true}
+	return params
+}
+
+func TestDcgmExporterDaemonSet(t *testing.T) {
+	params := gpuMetricsParams()
+	params.OtelCol.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+
+	ds := DcgmExporterDaemonSet(params)
+	require.NotNil(t, ds)
+	assert.Equal(t, "test-dcgm-exporter", ds.Name)
+	assert.Equal(t, ComponentDcgmExporter, ds.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+
+	podSpec := ds.Spec.Template.Spec
+	terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, len(gpuNodeLabels))
+	assert.Equal(t, "nvidia.com/gpu.present", terms[0].MatchExpressions[0].Key)
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: corev1.TolerationOpExists},
+	}, podSpec.Tolerations)
+	assert.Equal(t, "test-dcgm-exporter", podSpec.Volumes[0].ConfigMap.Name)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, "nvcr.io/nvidia/k8s/dcgm-exporter:3.3.7-3.5.0-ubuntu22.04", container.Image)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "DCGM_EXPORTER_COLLECTORS", Value: "/etc/dcgm-exporter/dcp-metrics-included.csv"})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "podresources", MountPath: "/var/lib/kubelet/pod-resources", ReadOnly: true})
+	assert.Equal(t, "500Mi", container.Resources.Limits.Memory().String())
+
+	service := DcgmExporterService(params)
+	require.NotNil(t, service)
+	assert.Equal(t, corev1.ServiceInternalTrafficPolicyLocal, *service.Spec.InternalTrafficPolicy)
+	assert.Equal(t, ds.Spec.Selector.MatchLabels, service.Spec.Selector)
+
+	configMap := dcgmExporterConfigMap(params)
+	require.NotNil(t, configMap)
+	assert.Equal(t, dcgmExporterMetrics, configMap.Data[dcgmExporterMetricsEntry])
+}
+
+func TestDcgmExporterAffinity(t *testing.T) {
+	params := gpuMetricsParams()
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpIn, Values: []string{"g5.xlarge"}}},
+		}}},
+	}}
+	params.OtelCol.Spec.GPUMetrics.Affinity = affinity
+	params.OtelCol.Spec.GPUMetrics.MetricsConfig = "DCGM_FI_DEV_GPU_UTIL, gauge, GPU utilization (in %).\n"
+
+	ds := DcgmExporterDaemonSet(params)
+	require.NotNil(t, ds)
+	assert.Equal(t, affinity, ds.Spec.Template.Spec.Affinity)
+	assert.Equal(t, "DCGM_FI_DEV_GPU_UTIL, gauge, GPU utilization (in %).\n", dcgmExporterConfigMap(params).Data[dcgmExporterMetricsEntry])
+}
+
+func TestDcgmExporterMIG(t *testing.T) {
+	params := gpuMetricsParams()
+	assert.Nil(t, DcgmExporterMIGDaemonSet(params))
+	assert.Nil(t, dcgmExporterMIGConfigMap(params))
+
+	params.OtelCol.Spec.GPUMetrics.MIG = &v1alpha1.GPUMIGSpec{Enabled: true}
+	ds := DcgmExporterMIGDaemonSet(params)
+	require.NotNil(t, ds)
+	assert.Equal(t, "test-dcgm-exporter-mig", ds.Name)
+	assert.Equal(t, DcgmExporterService(params).Spec.Selector, ds.Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "nvidia.com/mig.config.state": "success"}, ds.Spec.Template.Spec.NodeSelector)
+	assert.Len(t, ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, len(gpuNodeLabels))
+	assert.Equal(t, "test-dcgm-exporter-mig", ds.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.Contains(t, ds.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "DCGM_EXPORTER_KUBERNETES_GPU_ID_TYPE", Value: "device-name"})
+	assert.Equal(t, dcgmExporterMIGMetrics, dcgmExporterMIGConfigMap(params).Data[dcgmExporterMetricsEntry])
+
+	// the other exporter leaves the nodes with MIG enabled
+	other := DcgmExporterDaemonSet(params)
+	assert.NotContains(t, other.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "DCGM_EXPORTER_KUBERNETES_GPU_ID_TYPE", Value: "device-name"})
+	for _, term := range other.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		assert.Contains(t, term.MatchExpressions, corev1.NodeSelectorRequirement{Key: "nvidia.com/mig.config.state", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"success"}})
+	}
+
+	params.OtelCol.Spec.GPUMetrics.MIG.NodeSelector = map[string]string{"nvidia.com/mig.strategy": "mixed"}
+	params.OtelCol.Spec.GPUMetrics.MIG.MetricsConfig = "DCGM_FI_PROF_GR_ENGINE_ACTIVE, gauge, Ratio of time the graphics engine is active.\n"
+	ds = DcgmExporterMIGDaemonSet(params)
+	assert.Equal(t, "mixed", ds.Spec.Template.Spec.NodeSelector["nvidia.com/mig.strategy"])
+	assert.NotContains(t, ds.Spec.Template.Spec.NodeSelector, "nvidia.com/mig.config.state")
+	assert.Equal(t, params.OtelCol.Spec.GPUMetrics.MIG.MetricsConfig, dcgmExporterMIGConfigMap(params).Data[dcgmExporterMetricsEntry])
+
+	presetParams, err := withGPUMetrics(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+	exporter := conf["exporters"].(map[interface{}]interface{})["awsemf/dcgm"].(map[interface{}]interface{})
+	declaration := exporter["metric_declarations"].([]interface{})[0].(map[interface{}]interface{})
+	assert.Contains(t, declaration["dimensions"], []interface{}{"ClusterName", "Hostname", "gpu", "GPU_I_ID"})
+}
+
+func TestGPUMetricsDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	assert.Nil(t, DcgmExporterDaemonSet(params))
+	assert.Nil(t, DcgmExporterService(params))
+	assert.Nil(t, dcgmExporterConfigMap(params))
+
+	params = gpuMetricsParams()
+	params.OtelCol.Spec.Mode = v1alpha1.ModeDeployment
+	assert.Nil(t, DcgmExporterDaemonSet(params))
+	presetParams, err := withGPUMetrics(params)
+	require.NoError(t, err)
+	assert.Equal(t, params.OtelCol.Spec.OtelConfig, presetParams.OtelCol.Spec.OtelConfig)
+}
+
+func TestGPUMetricsExistingDcgmExporter(t *testing.T) {
+	params := gpuMetricsParams()
+	params.DcgmExporter = &v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "amazon-cloudwatch"}}
+	assert.Nil(t, DcgmExporterDaemonSet(params))
+	assert.Nil(t, DcgmExporterService(params))
+	assert.Nil(t, dcgmExporterConfigMap(params))
+
+	// the GPU metrics are still collected, from the exporter of the DcgmExporter
+	presetParams, err := withGPUMetrics(params)
+	require.NoError(t, err)
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "dcgm-exporter-service.amazon-cloudwatch.svc:9400")
+	assert.NotContains(t, presetParams.OtelCol.Spec.OtelConfig, "test-dcgm-exporter.default.svc:9400")
+
+	// the GPU instances are still collected by the exporter of the instance, through its own Service
+	params.OtelCol.Spec.GPUMetrics.MIG = &v1alpha1.GPUMIGSpec{Enabled: true}
+	assert.Nil(t, DcgmExporterDaemonSet(params))
+	assert.NotNil(t, DcgmExporterMIGDaemonSet(params))
+	assert.NotNil(t, dcgmExporterMIGConfigMap(params))
+	assert.NotNil(t, DcgmExporterService(params))
+	presetParams, err = withGPUMetrics(params)
+	require.NoError(t, err)
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "dcgm-exporter-service.amazon-cloudwatch.svc:9400")
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "test-dcgm-exporter.default.svc:9400")
+}
+
+func TestExistingDcgmExporter(t *testing.T) {
+	params := gpuMetricsParams()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	existing, err := ExistingDcgmExporter(context.Background(), fake.NewClientBuilder().WithScheme(scheme).Build(), params.OtelCol, "")
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "amazon-cloudwatch"}},
+		&v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "gpu"}},
+	).Build()
+	existing, err = ExistingDcgmExporter(context.Background(), c, params.OtelCol, "")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "amazon-cloudwatch", existing.Namespace)
+
+	// the exporter of the namespace of the instance is preferred
+	params.OtelCol.Namespace = "gpu"
+	existing, err = ExistingDcgmExporter(context.Background(), c, params.OtelCol, "")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "gpu", existing.Namespace)
+
+	existing, err = ExistingDcgmExporter(context.Background(), c, params.OtelCol, "default")
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestGPUMetricsScrapeConfig(t *testing.T) {
+	params := gpuMetricsParams()
+	params.OtelCol.Spec.AWS = &v1alpha1.AWSSpec{Region: "us-east-1"}
+
+	presetParams, err := withGPUMetrics(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	pipelines := conf["service"].(map[interface{}]interface{})["pipelines"].(map[interface{}]interface{})
+	assert.Contains(t, pipelines, "metrics/dcgm")
+	exporter := conf["exporters"].(map[interface{}]interface{})["awsemf/dcgm"].(map[interface{}]interface{})
+	assert.Equal(t, "/aws/containerinsights/my-cluster/prometheus", exporter["log_group_name"])
+	assert.Equal(t, "us-east-1", exporter["region"])
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "test-dcgm-exporter.default.svc:9400")
+
+	params.OtelCol.Spec.Config = ""
+	_, err = withGPUMetrics(params)
+	assert.ErrorIs(t, err, errNoGPUMetricsClusterName)
+}
@@ -34,6 +34,12 @@ func HorizontalPodAutoscaler(params manifests.Params) client.Object {
 		return nil
 	}
 
+	// only the deployment and statefulset modes can be scaled through the scale subresource
+	if params.OtelCol.Spec.Mode == v1alpha1.ModeDaemonSet || params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
+		params.Log.V(1).Info("autoscaling is not supported in this mode, skipping autoscaler creation", "mode", params.OtelCol.Spec.Mode)
+		return nil
+	}
+
 	if params.OtelCol.Spec.Autoscaler.MaxReplicas == nil {
 		params.OtelCol.Spec.Autoscaler.MaxReplicas = params.OtelCol.Spec.MaxReplicas
 	}
@@ -95,13 +101,18 @@ func HorizontalPodAutoscaler(params manifests.Params) client.Object {
 
 	// convert from v1alpha1.MetricSpec into a autoscalingv2.MetricSpec.
 	for _, metric := range params.OtelCol.Spec.Autoscaler.Metrics {
-		if metric.Type == autoscalingv2.PodsMetricSourceType {
-			v2metric := autoscalingv2.MetricSpec{
+		switch metric.Type {
+		case autoscalingv2.PodsMetricSourceType:
+			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, autoscalingv2.MetricSpec{
 				Type: metric.Type,
 				Pods: metric.Pods,
-			}
-			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, v2metric)
-		} // pod metrics
+			})
+		case autoscalingv2.ExternalMetricSourceType:
+			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, autoscalingv2.MetricSpec{
+				Type:     metric.Type,
+				External: metric.External,
+			})
+		}
 	}
 	result = &autoscaler
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -93,3 +94,39 @@ func TestHPA(t *testing.T) {
 	}
 
 }
+
+func TestHPAExternalMetrics(t *testing.T) {
+	var maxReplicas int32 = 5
+	external := &autoscalingv2.ExternalMetricSource{
+		Metric: autoscalingv2.MetricIdentifier{Name: "otlp_requests_per_second"},
+		Target: autoscalingv2.MetricTarget{
+			Type:         autoscalingv2.AverageValueMetricType,
+			AverageValue: resource.NewQuantity(int64(1000), resource.DecimalSI),
+		},
+	}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode: v1alpha1.ModeDeployment,
+			Autoscaler: &v1alpha1.AutoscalerSpec{
+				MaxReplicas: &maxReplicas,
+				Metrics: []v1alpha1.MetricSpec{
+					{Type: autoscalingv2.ExternalMetricSourceType, External: external},
+				},
+			},
+		},
+	}
+	params := manifests.Params{
+		Config:  config.New(),
+		OtelCol: otelcol,
+		Log:     logger,
+	}
+
+	hpa := HorizontalPodAutoscaler(params).(*autoscalingv2.HorizontalPodAutoscaler)
+	assert.Equal(t, []autoscalingv2.MetricSpec{{Type: autoscalingv2.ExternalMetricSourceType, External: external}}, hpa.Spec.Metrics)
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeDaemonSet
+	assert.Nil(t, HorizontalPodAutoscaler(params))
+}
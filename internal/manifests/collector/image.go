@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// ManagedVersion returns the agent version the instance is pinned to, or an empty string when it follows the agent
+// version of the operator. A version set in the spec always wins. Instances opting out of upgrades keep running the
+// version recorded in their status, so that upgrading the operator does not roll them. Instances on the pinned
+// upgrade channel opt out the same way.
+func ManagedVersion(agent v1alpha1.AmazonCloudWatchAgent) string {
+	if agent.Spec.Version != "" {
+		return agent.Spec.Version
+	}
+	if agent.Spec.UpgradeStrategy == v1alpha1.UpgradeStrategyNone || pinned(agent) {
+		return agent.Status.Version
+	}
+	return ""
+}
+
+func pinned(agent v1alpha1.AmazonCloudWatchAgent) bool {
+	if skip, _ := strconv.ParseBool(agent.Annotations[constants.AnnotationSkipUpgrade]); skip {
+		return true
+	}
+	return agent.Annotations[constants.AnnotationUpgradeChannel] == "pinned"
+}
+
+// agentImage returns the image of the agent container: the image of the spec when set, otherwise the default agent
+// image of the operator, or its FIPS image in FIPS mode, tagged with the managed version of the instance if there is
+// one.
+func agentImage(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) string {
+	if agent.Spec.Image != "" {
+		return agent.Spec.Image
+	}
+	image := cfg.CollectorImage()
+	if agent.Spec.FIPS {
+		image = cfg.CollectorFIPSImage()
+	}
+	if version := ManagedVersion(agent); version != "" {
+		return imageWithTag(image, version)
+	}
+	return image
+}
+
+// imageWithTag replaces the tag or digest of the image reference with the given tag. The port of a registry host
+// is not mistaken for a tag, as only the part after the last slash is considered.
+func imageWithTag(image string, tag string) string {
+	repository, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return repository + ":" + tag
+}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestAgentImage(t *testing.T) {
+	cfg := config.New(
+		config.WithCollectorImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300040.0"),
+		config.WithCollectorFIPSImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent-fips:1.300040.0"),
+	)
+
+	tests := []struct {
+		name     string
+		meta     metav1.ObjectMeta
+		spec     v1alpha1.AmazonCloudWatchAgentSpec
+		status   v1alpha1.AmazonCloudWatchAgentStatus
+		expected string
+	}{
+		{
+			name:     "default image",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{UpgradeStrategy: v1alpha1.UpgradeStrategyAutomatic},
+			status:   v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300040.0",
+		},
+		{
+			name:     "custom image",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{Image: "my-registry:5000/cloudwatch-agent@sha256:abc"},
+			expected: "my-registry:5000/cloudwatch-agent@sha256:abc",
+		},
+		{
+			name:     "version",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{Version: "1.300049.1"},
+			status:   v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300049.1",
+		},
+		{
+			name:     "FIPS",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{FIPS: true},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-fips:1.300040.0",
+		},
+		{
+			name:     "FIPS with a version",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{FIPS: true, Version: "1.300049.1"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-fips:1.300049.1",
+		},
+		{
+			name:     "upgrades disabled",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{UpgradeStrategy: v1alpha1.UpgradeStrategyNone},
+			status:   v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300030.0",
+		},
+		{
+			name:     "pinned channel",
+			meta:     metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationUpgradeChannel: "pinned"}},
+			status:   v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300030.0",
+		},
+		{
+			name:     "upgrades skipped",
+			meta:     metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationSkipUpgrade: "true"}},
+			status:   v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300030.0",
+		},
+		{
+			name:     "upgrades disabled before the first status",
+			spec:     v1alpha1.AmazonCloudWatchAgentSpec{UpgradeStrategy: v1alpha1.UpgradeStrategyNone},
+			expected: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300040.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: tt.meta, Spec: tt.spec, Status: tt.status}
+			assert.Equal(t, tt.expected, agentImage(cfg, agent))
+		})
+	}
+}
+
+func TestImageWithTag(t *testing.T) {
+	assert.Equal(t, "cloudwatch-agent:1.2.3", imageWithTag("cloudwatch-agent", "1.2.3"))
+	assert.Equal(t, "cloudwatch-agent:1.2.3", imageWithTag("cloudwatch-agent:latest", "1.2.3"))
+	assert.Equal(t, "localhost:5000/cloudwatch-agent:1.2.3", imageWithTag("localhost:5000/cloudwatch-agent", "1.2.3"))
+	assert.Equal(t, "localhost:5000/cloudwatch-agent:1.2.3", imageWithTag("localhost:5000/cloudwatch-agent:1.0.0@sha256:abc", "1.2.3"))
+}
@@ -124,8 +124,15 @@ func createSubdomainIngressRules(otelcol string, hostname string, ports []corev1
 	return rules
 }
 
+// servicePortsFromCfg returns the ports of the receivers exposed outside the cluster. The UDP listeners are left out,
+// as they cannot be proxied by the ingress controllers and the Gateway API HTTP and gRPC routes.
 func servicePortsFromCfg(logger logr.Logger, otelcol v1alpha1.AmazonCloudWatchAgent) ([]corev1.ServicePort, error) {
 	var ports []corev1.ServicePort
+	for _, p := range containerPortsToServicePortList(getContainerPorts(logger, otelcol.Spec.Config, otelcol.Spec.OtelConfig, nil)) {
+		if p.Protocol != corev1.ProtocolUDP {
+			ports = append(ports, p)
+		}
+	}
 	if len(otelcol.Spec.Ports) > 0 {
 		// we should add all the ports from the CR
 		// there are two cases where problems might occur:
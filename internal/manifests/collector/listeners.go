@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+// metricsListener is a listener of the agent configured by the instance, and the section of metrics_collected it
+// enables.
+type metricsListener struct {
+	section string
+	spec    *v1alpha1.MetricsListenerSpec
+}
+
+func metricsListeners(instance v1alpha1.AmazonCloudWatchAgent) []metricsListener {
+	var listeners []metricsListener
+	if instance.Spec.StatsD != nil && instance.Spec.StatsD.Enabled {
+		listeners = append(listeners, metricsListener{section: StatsD, spec: instance.Spec.StatsD})
+	}
+	if instance.Spec.CollectD != nil && instance.Spec.CollectD.Enabled {
+		listeners = append(listeners, metricsListener{section: CollectD, spec: instance.Spec.CollectD})
+	}
+	return listeners
+}
+
+func (l metricsListener) port() int32 {
+	if l.spec.Port != 0 {
+		return l.spec.Port
+	}
+	return receiverDefaultPortsMap[l.section]
+}
+
+// withMetricsListeners merges the service addresses of the enabled listeners into the agent configuration of the
+// instance, so that the container and Service ports are derived from it like the ones set by the user.
+func withMetricsListeners(params manifests.Params) (manifests.Params, error) {
+	listeners := metricsListeners(params.OtelCol)
+	if len(listeners) == 0 {
+		return params, nil
+	}
+	collected := map[string]interface{}{}
+	for _, l := range listeners {
+		collected[l.section] = map[string]interface{}{"service_address": fmt.Sprintf(":%d", l.port())}
+	}
+	fragment, err := json.Marshal(map[string]interface{}{"metrics": map[string]interface{}{"metrics_collected": collected}})
+	if err != nil {
+		return params, err
+	}
+	merged, err := mergeJSONConfig(params.OtelCol.Spec.Config, string(fragment))
+	if err != nil {
+		return params, err
+	}
+
+	instance := *params.OtelCol.DeepCopy()
+	instance.Spec.Config = merged
+	listenerParams := params
+	listenerParams.OtelCol = instance
+	return listenerParams, nil
+}
+
+// setListenerHostPorts binds the ports of the listeners to the ports of the node. Host ports are only set in
+// daemonset mode, where a single agent runs per node.
+func setListenerHostPorts(instance v1alpha1.AmazonCloudWatchAgent, ports []corev1.ContainerPort) {
+	if instance.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return
+	}
+	for _, l := range metricsListeners(instance) {
+		if !l.spec.HostPort {
+			continue
+		}
+		for i := range ports {
+			if ports[i].ContainerPort == l.port() && ports[i].Protocol == corev1.ProtocolUDP {
+				ports[i].HostPort = ports[i].ContainerPort
+			}
+		}
+	}
+}
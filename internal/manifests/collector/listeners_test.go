@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestMetricsListeners(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Config = `{"metrics":{"metrics_collected":{"statsd":{"service_address":":9000","metrics_aggregation_interval":"60s"}}}}`
+	params.OtelCol.Spec.StatsD = &v1alpha1.MetricsListenerSpec{Enabled: true, HostPort: true}
+	params.OtelCol.Spec.CollectD = &v1alpha1.MetricsListenerSpec{Enabled: true, Port: 25000}
+
+	listenerParams, err := withMetricsListeners(params)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metrics":{"metrics_collected":{
+		"statsd":{"service_address":":8125","metrics_aggregation_interval":"60s"},
+		"collectd":{"service_address":":25000"}}}}`, listenerParams.OtelCol.Spec.Config)
+	assert.NotEqual(t, params.OtelCol.Spec.Config, listenerParams.OtelCol.Spec.Config, "the instance must not be modified")
+
+	ports := portMapToContainerPortList(getContainerPorts(logr.Discard(), listenerParams.OtelCol.Spec.Config, "", nil))
+	setListenerHostPorts(listenerParams.OtelCol, ports)
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: "cwa-collectd", ContainerPort: 25000, Protocol: corev1.ProtocolUDP},
+		{Name: "cwa-statsd", ContainerPort: 8125, HostPort: 8125, Protocol: corev1.ProtocolUDP},
+	}, ports)
+}
+
+func TestMetricsListenersHostPortOnlyInDaemonSetMode(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDeployment)
+	params.OtelCol.Spec.StatsD = &v1alpha1.MetricsListenerSpec{Enabled: true, HostPort: true}
+
+	listenerParams, err := withMetricsListeners(params)
+	require.NoError(t, err)
+	ports := portMapToContainerPortList(getContainerPorts(logr.Discard(), listenerParams.OtelCol.Spec.Config, "", nil))
+	setListenerHostPorts(listenerParams.OtelCol, ports)
+	assert.Equal(t, []corev1.ContainerPort{{Name: "cwa-statsd", ContainerPort: 8125, Protocol: corev1.ProtocolUDP}}, ports)
+}
+
+func TestMetricsListenersDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Config = `{"agent":{"region":"us-west-2"}}`
+	params.OtelCol.Spec.StatsD = &v1alpha1.MetricsListenerSpec{Port: 9000}
+
+	listenerParams, err := withMetricsListeners(params)
+	require.NoError(t, err)
+	assert.Equal(t, params.OtelCol.Spec.Config, listenerParams.OtelCol.Spec.Config)
+}
@@ -0,0 +1,204 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/neuronmonitor"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// ComponentNeuronMonitor is the component of the Neuron monitor pods exposing the metrics of the Inferentia and
+	// Trainium accelerators.
+	ComponentNeuronMonitor = "neuron-monitor"
+
+	neuronMonitorPort = 8000
+
+	neuronPipeline = "neuron"
+)
+
+var errNoNeuronMetricsClusterName = errors.New("the cluster of the Neuron metrics is unknown, set the clusterName of neuronMetrics")
+
+// neuronMonitorConfig collects the metrics of the Neuron runtimes and devices of Container Insights.
+const neuronMonitorConfig = `{
+  "period": "5s",
+  "neuron_runtimes": [
+    {
+      "tag_filter": ".*",
+      "metrics": [
+        {"type": "neuroncore_counters"},
+        {"type": "memory_used"},
+        {"type": "neuron_runtime_vcpu_usage"},
+        {"type": "execution_stats"}
+      ]
+    }
+  ],
+  "system_metrics": [
+    {"type": "vcpu_usage"},
+    {"type": "memory_info"},
+    {"type": "neuron_hw_counters"}
+  ]
+}
+`
+
+// neuronInstanceTypes are the instance types with AWS Inferentia and Trainium accelerators.
+var neuronInstanceTypes = []string{
+	"inf1.xlarge", "inf1.2xlarge", "inf1.6xlarge", "inf1.24xlarge",
+	"inf2.xlarge", "inf2.8xlarge", "inf2.24xlarge", "inf2.48xlarge",
+	"trn1.2xlarge", "trn1.32xlarge", "trn1n.32xlarge", "trn2.48xlarge",
+}
+
+// neuronNodeLabels are the labels of the nodes with Neuron devices: their instance type, and the accelerator
+// manufacturer of the instance types of Karpenter.
+var neuronNodeLabels = []corev1.NodeSelectorRequirement{
+	{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpIn, Values: neuronInstanceTypes},
+	{Key: "karpenter.k8s.aws/instance-accelerator-manufacturer", Operator: corev1.NodeSelectorOpIn, Values: []string{"aws"}},
+}
+
+// neuronMetricsPipeline exports the Neuron metrics per instance and per NeuronCore.
+var neuronMetricsPipeline = nodeExporterPipeline{
+	name:            neuronPipeline,
+	metricSelectors: []string{"^neuron", "^execution_", "^hardware_ecc_"},
+	dimensions:      [][]string{{"ClusterName"}, {"ClusterName", "instance_id"}, {"ClusterName", "instance_id", "neuroncore"}},
+}
+
+// NeuronMonitorObjects returns the resources of the Neuron monitor of the instance on the nodes with Neuron devices,
+// which are the ones of a NeuronMonitor resource. Returns nil when the instance does not collect the Neuron metrics, or
+// when a NeuronMonitor resource runs the Neuron monitor on the nodes already.
+func NeuronMonitorObjects(params manifests.Params) ([]client.Object, error) {
+	if !deploysNeuronMonitor(params) {
+		return nil, nil
+	}
+	params.NeuronExp = neuronMonitor(params)
+	objects, err := neuronmonitor.Build(params)
+	if err != nil {
+		return nil, err
+	}
+	// the resources are labeled as the ones of the instance, which prunes them once the Neuron metrics are disabled
+	for _, obj := range objects {
+		labels := maps.Clone(obj.GetLabels())
+		maps.Copy(labels, manifestutils.SelectorLabelsForAllOperatorManaged(params.OtelCol.ObjectMeta))
+		obj.SetLabels(labels)
+	}
+	return objects, nil
+}
+
+func neuronMetricsEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode == v1alpha1.ModeDaemonSet && instance.Spec.NeuronMetrics != nil && instance.Spec.NeuronMetrics.Enabled
+}
+
+// deploysNeuronMonitor returns whether the instance deploys its Neuron monitor, which it does not when a NeuronMonitor
+// resource runs one on the Neuron nodes already.
+func deploysNeuronMonitor(params manifests.Params) bool {
+	return neuronMetricsEnabled(params.OtelCol) && !params.NeuronMonitorDeployed
+}
+
+// NeuronMonitorDeployed returns whether a NeuronMonitor resource of the namespace, or of all namespaces when it is
+// empty, deploys the Neuron monitor, or false when the instance does not collect the Neuron metrics.
+func NeuronMonitorDeployed(ctx context.Context, c client.Reader, instance v1alpha1.AmazonCloudWatchAgent, namespace string) (bool, error) {
+	if !neuronMetricsEnabled(instance) {
+		return false, nil
+	}
+	var monitors v1alpha1.NeuronMonitorList
+	if err := c.List(ctx, &monitors, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	return len(monitors.Items) > 0, nil
+}
+
+// neuronMonitor returns the NeuronMonitor resource of the Neuron monitor of the instance.
+func neuronMonitor(params manifests.Params) v1alpha1.NeuronMonitor {
+	instance := params.OtelCol
+	neuron := instance.Spec.NeuronMetrics
+	image := neuron.Image
+	if image == "" {
+		image = params.Config.NeuronMonitorImage()
+	}
+	config := neuron.MonitorConfig
+	if config == "" {
+		config = neuronMonitorConfig
+	}
+	affinity := neuron.Affinity
+	if affinity == nil {
+		affinity = nodeAffinity(neuronNodeLabels...)
+	}
+	resources := neuron.Resources
+	if len(resources.Limits) == 0 && len(resources.Requests) == 0 {
+		resources = corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("250Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("256m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		}
+	}
+	nodeSelector := maps.Clone(instance.Spec.NodeSelector)
+	if nodeSelector == nil {
+		nodeSelector = map[string]string{}
+	}
+	nodeSelector["kubernetes.io/os"] = "linux"
+	// the Neuron monitor reads the counters of the devices of the node
+	privileged := true
+
+	return v1alpha1.NeuronMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        naming.NeuronMonitor(instance.Name),
+			Namespace:   instance.Namespace,
+			Annotations: instance.Annotations,
+		},
+		Spec: v1alpha1.NeuronMonitorSpec{
+			Image:         image,
+			MonitorConfig: config,
+			Command:       []string{"/opt/bin/entrypoint.sh"},
+			Env: []corev1.EnvVar{
+				{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+			},
+			Ports:           []corev1.ServicePort{{Name: "metrics", Port: neuronMonitorPort, Protocol: corev1.ProtocolTCP}},
+			SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			Resources:       resources,
+			NodeSelector:    nodeSelector,
+			Affinity:        affinity,
+			// the Neuron nodes are commonly tainted to keep the other workloads away
+			Tolerations: append([]corev1.Toleration{{Key: "aws.amazon.com/neuron", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				instance.Spec.Tolerations...),
+		},
+	}
+}
+
+// withNeuronMetrics merges the pipeline scraping the Neuron monitor into the OpenTelemetry configuration of the
+// instance.
+func withNeuronMetrics(params manifests.Params) (manifests.Params, error) {
+	if !deploysNeuronMonitor(params) {
+		return params, nil
+	}
+	cluster := clusterName(params.OtelCol, params.OtelCol.Spec.NeuronMetrics.ClusterName)
+	if cluster == "" {
+		return params, errNoNeuronMetricsClusterName
+	}
+	params.NeuronExp = neuronMonitor(params)
+	service, err := neuronmonitor.Service(params)
+	if err != nil {
+		return params, err
+	}
+	preset, err := nodeExporterOtelConfig(params.OtelCol, ComponentNeuronMonitor, []string{serviceTarget(service)}, neuronMetricsPipeline, cluster)
+	if err != nil {
+		return params, err
+	}
+	return withOtelConfigPreset(params, preset)
+}
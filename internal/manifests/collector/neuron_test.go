@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func neuronMetricsParams() manifests.Params {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.Config = config.New(config.WithCollectorImage(defaultCollectorImage), config.WithNeuronMonitorImage("public.ecr.aws/neuron/neuron-monitor:1.0.1"))
+	params.OtelCol.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"my-cluster"}}}}`
+	params.OtelCol.Spec.NeuronMetrics = &v1alpha1.NeuronMetricsSpec{Enabled: true}
+	return params
+}
+
+func TestNeuronMonitorObjects(t *testing.T) {
+	params := neuronMetricsParams()
+
+	objects, err := NeuronMonitorObjects(params)
+	require.NoError(t, err)
+	require.Len(t, objects, 4)
+	for _, obj := range objects {
+		assert.Equal(t, "default.test", obj.GetLabels()["app.kubernetes.io/instance"])
+		assert.Equal(t, "amazon-cloudwatch-agent-operator", obj.GetLabels()["app.kubernetes.io/managed-by"])
+	}
+
+	ds := objects[0].(*appsv1.DaemonSet)
+	assert.Equal(t, "test-neuron-monitor", ds.Name)
+	assert.Equal(t, ds.Spec.Selector.MatchLabels["app.kubernetes.io/instance"], ds.Spec.Template.Labels["app.kubernetes.io/instance"])
+	podSpec := ds.Spec.Template.Spec
+	terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 2)
+	assert.Contains(t, terms[0].MatchExpressions[0].Values, "trn1.32xlarge")
+	assert.Equal(t, []corev1.Toleration{{Key: "aws.amazon.com/neuron", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}, podSpec.Tolerations)
+	assert.Equal(t, "linux", podSpec.NodeSelector["kubernetes.io/os"])
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, "public.ecr.aws/neuron/neuron-monitor:1.0.1", container.Image)
+	assert.Equal(t, []string{"--neuron-monitor-config", "/etc/neuron-monitor-config/monitor.json"}, container.Args)
+	assert.True(t, *container.SecurityContext.Privileged)
+
+	configMap := objects[1].(*corev1.ConfigMap)
+	assert.JSONEq(t, neuronMonitorConfig, configMap.Data["monitor.json"])
+
+	service := objects[3].(*corev1.Service)
+	assert.Equal(t, "test-neuron-monitor-service", service.Name)
+	assert.Equal(t, int32(8000), service.Spec.Ports[0].Port)
+	assert.Equal(t, ds.Spec.Selector.MatchLabels, service.Spec.Selector)
+}
+
+func TestNeuronMetricsDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	objects, err := NeuronMonitorObjects(params)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	params = neuronMetricsParams()
+	params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+	objects, err = NeuronMonitorObjects(params)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestNeuronMetricsNeuronMonitorDeployed(t *testing.T) {
+	params := neuronMetricsParams()
+	params.NeuronMonitorDeployed = true
+	objects, err := NeuronMonitorObjects(params)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+	presetParams, err := withNeuronMetrics(params)
+	require.NoError(t, err)
+	assert.Equal(t, params.OtelCol.Spec.OtelConfig, presetParams.OtelCol.Spec.OtelConfig)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	deployed, err := NeuronMonitorDeployed(context.Background(), fake.NewClientBuilder().WithScheme(scheme).Build(), params.OtelCol, "")
+	require.NoError(t, err)
+	assert.False(t, deployed)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.NeuronMonitor{ObjectMeta: metav1.ObjectMeta{Name: "neuron-monitor", Namespace: "amazon-cloudwatch"}},
+	).Build()
+	deployed, err = NeuronMonitorDeployed(context.Background(), c, params.OtelCol, "")
+	require.NoError(t, err)
+	assert.True(t, deployed)
+	deployed, err = NeuronMonitorDeployed(context.Background(), c, params.OtelCol, "default")
+	require.NoError(t, err)
+	assert.False(t, deployed)
+}
+
+func TestNeuronMetricsScrapeConfig(t *testing.T) {
+	params := neuronMetricsParams()
+	params.OtelCol.Spec.GPUMetrics = &v1alpha1.GPUMetricsSpec{Enabled: true}
+
+	presetParams, err := withGPUMetrics(params)
+	require.NoError(t, err)
+	presetParams, err = withNeuronMetrics(presetParams)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	pipelines := conf["service"].(map[interface{}]interface{})["pipelines"].(map[interface{}]interface{})
+	assert.Contains(t, pipelines, "metrics/dcgm")
+	assert.Contains(t, pipelines, "metrics/neuron")
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "test-neuron-monitor-service.default.svc:8000")
+
+	params.OtelCol.Spec.Config = ""
+	_, err = withNeuronMetrics(params)
+	assert.ErrorIs(t, err, errNoNeuronMetricsClusterName)
+}
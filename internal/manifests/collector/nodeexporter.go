@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+// nodeExporter is an exporter of the metrics of the devices of the nodes, which the operator runs on the nodes with
+// these devices and the agent of each node scrapes through a node local Service.
+type nodeExporter struct {
+	component string
+	name      string
+	image     string
+	port      int32
+	// configDir is where the configuration of the exporter is mounted, configEntry and config being its file and
+	// content.
+	configDir   string
+	configEntry string
+	config      string
+	// container holds the command, arguments, environment, security context and additional mounts of the exporter.
+	container corev1.Container
+	volumes   []corev1.Volume
+	// nodeSelector narrows down the nodes selected by the affinity.
+	nodeSelector map[string]string
+	affinity     *corev1.Affinity
+	tolerations  []corev1.Toleration
+	resources    corev1.ResourceRequirements
+}
+
+// nodeExporterPipeline is the pipeline of the agent scraping a node exporter, and exporting its metrics as Container
+// Insights Prometheus metrics.
+type nodeExporterPipeline struct {
+	// name suffixes the names of the components of the pipeline.
+	name            string
+	metricSelectors []string
+	dimensions      [][]string
+}
+
+// nodeAffinity selects the nodes matching any of the requirements.
+func nodeAffinity(requirements ...corev1.NodeSelectorRequirement) *corev1.Affinity {
+	terms := make([]corev1.NodeSelectorTerm, 0, len(requirements))
+	for _, requirement := range requirements {
+		terms = append(terms, corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}})
+	}
+	return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+	}}
+}
+
+// nodeExporterDaemonSet returns the DaemonSet of a node exporter.
+func nodeExporterDaemonSet(params manifests.Params, exporter nodeExporter) *appsv1.DaemonSet {
+	instance := params.OtelCol
+	labels := manifestutils.Labels(instance.ObjectMeta, exporter.name, exporter.image, exporter.component, params.Config.LabelsFilter())
+	podAnnotations := map[string]string{
+		"amazon-cloudwatch-agent-operator-config/sha256": fmt.Sprintf("%x", sha256.Sum256([]byte(exporter.config))),
+	}
+	nodeSelector := maps.Clone(instance.Spec.NodeSelector)
+	if nodeSelector == nil {
+		nodeSelector = map[string]string{}
+	}
+	maps.Copy(nodeSelector, exporter.nodeSelector)
+	nodeSelector["kubernetes.io/os"] = "linux"
+
+	container := exporter.container
+	container.Name = exporter.component
+	container.Image = exporter.image
+	container.ImagePullPolicy = instance.Spec.ImagePullPolicy
+	container.Ports = []corev1.ContainerPort{{Name: "metrics", ContainerPort: exporter.port, Protocol: corev1.ProtocolTCP}}
+	container.Resources = exporter.resources
+	container.VolumeMounts = append([]corev1.VolumeMount{{Name: exporter.configVolume(), MountPath: exporter.configDir}}, container.VolumeMounts...)
+	volumes := append([]corev1.Volume{{
+		Name: exporter.configVolume(),
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: exporter.name}},
+		},
+	}}, exporter.volumes...)
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        exporter.name,
+			Namespace:   instance.Namespace,
+			Labels:      labels,
+			Annotations: instance.Annotations,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: manifestutils.SelectorLabels(instance.ObjectMeta, exporter.component),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ServiceAccountName(instance),
+					Containers:         []corev1.Container{container},
+					Volumes:            volumes,
+					NodeSelector:       nodeSelector,
+					// the nodes with devices are commonly tainted to keep the other workloads away
+					Tolerations:       append(exporter.tolerations, instance.Spec.Tolerations...),
+					Affinity:          exporter.affinity,
+					PriorityClassName: instance.Spec.PriorityClassName,
+				},
+			},
+		},
+	}
+}
+
+// nodeExporterService returns the Service the agents scrape the node exporter of their node through.
+func nodeExporterService(params manifests.Params, exporter nodeExporter) *corev1.Service {
+	instance := params.OtelCol
+	// the traffic is kept on the node, so that every agent scrapes the devices of its own node
+	trafficPolicy := corev1.ServiceInternalTrafficPolicyLocal
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        exporter.name,
+			Namespace:   instance.Namespace,
+			Labels:      manifestutils.Labels(instance.ObjectMeta, exporter.name, "", exporter.component, []string{}),
+			Annotations: instance.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeClusterIP,
+			InternalTrafficPolicy: &trafficPolicy,
+			Selector:              manifestutils.SelectorLabels(instance.ObjectMeta, exporter.component),
+			Ports: []corev1.ServicePort{{
+				Name:       "metrics",
+				Port:       exporter.port,
+				TargetPort: intstr.FromInt32(exporter.port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// nodeExporterConfigMap returns the configuration of a node exporter.
+func nodeExporterConfigMap(params manifests.Params, exporter nodeExporter) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        exporter.name,
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      manifestutils.Labels(params.OtelCol.ObjectMeta, exporter.name, "", exporter.component, []string{}),
+			Annotations: params.OtelCol.Annotations,
+		},
+		Data: map[string]string{exporter.configEntry: exporter.config},
+	}
+}
+
+func (e nodeExporter) configVolume() string {
+	return e.component + "-config"
+}
+
+// serviceTarget returns the scrape target of the first port of the Service.
+func serviceTarget(service *corev1.Service) string {
+	return fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, service.Spec.Ports[0].Port)
+}
+
+// nodeExporterOtelConfig renders the pipeline scraping the job of the node exporters of the node of the agent through
+// the targets of their node local Services.
+func nodeExporterOtelConfig(instance v1alpha1.AmazonCloudWatchAgent, job string, targets []string, pipeline nodeExporterPipeline, cluster string) (map[interface{}]interface{}, error) {
+	receiver := "prometheus/" + pipeline.name
+	processor := "resource/" + pipeline.name
+	emfExporter := "awsemf/" + pipeline.name
+
+	emf := map[string]interface{}{
+		"namespace":                        "ContainerInsights/Prometheus",
+		"log_group_name":                   fmt.Sprintf("/aws/containerinsights/%s/prometheus", cluster),
+		"log_stream_name":                  pipeline.name,
+		"dimension_rollup_option":          "NoDimensionRollup",
+		"resource_to_telemetry_conversion": map[string]interface{}{"enabled": true},
+		"metric_declarations": []interface{}{map[string]interface{}{
+			"dimensions":            pipeline.dimensions,
+			"metric_name_selectors": pipeline.metricSelectors,
+		}},
+	}
+	setExporterAWSConfig(emf, instance.Spec.AWS)
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"receivers": map[string]interface{}{
+			receiver: map[string]interface{}{
+				"config": map[string]interface{}{"scrape_configs": []interface{}{map[string]interface{}{
+					"job_name":       job,
+					"static_configs": []interface{}{map[string]interface{}{"targets": targets}},
+				}}},
+			},
+		},
+		"processors": map[string]interface{}{
+			processor: map[string]interface{}{
+				"attributes": []interface{}{
+					map[string]interface{}{"key": "ClusterName", "value": cluster, "action": "upsert"},
+				},
+			},
+		},
+		"exporters": map[string]interface{}{emfExporter: emf},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"metrics/" + pipeline.name: map[string]interface{}{
+					"receivers":  []string{receiver},
+					"processors": []string{processor},
+					"exporters":  []string{emfExporter},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adapters.ConfigFromString(string(out))
+}
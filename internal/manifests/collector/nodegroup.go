@@ -0,0 +1,210 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// NodeGroupLabel is set on the DaemonSets and pods rendered for a node group, with the node group name as value.
+	NodeGroupLabel = "cloudwatch.aws.amazon.com/node-group"
+	// WindowsNodeGroup is the name of the node group rendered for the Windows section of the instance.
+	WindowsNodeGroup = "windows"
+	// HybridNodeGroup is the name of the node group rendered for the hybrid section of the instance.
+	HybridNodeGroup = "hybrid"
+
+	// hybridComputeTypeLabel is the node label of the EKS compute type, hybrid on the EKS Hybrid Nodes.
+	hybridComputeTypeLabel = "eks.amazonaws.com/compute-type"
+	hybridComputeType      = "hybrid"
+	// hybridCredentialsMountPath is where the credentials file of the hybrid nodes is mounted in the agent container.
+	hybridCredentialsMountPath = "/var/run/aws/credentials"
+
+	envEC2MetadataDisabled   = "AWS_EC2_METADATA_DISABLED"
+	envSharedCredentialsFile = "AWS_SHARED_CREDENTIALS_FILE"
+)
+
+// NodeGroupDaemonSets builds a DaemonSet for each node group of the instance.
+func NodeGroupDaemonSets(params manifests.Params) ([]*appsv1.DaemonSet, error) {
+	var daemonSets []*appsv1.DaemonSet
+	for _, group := range nodeGroups(params.OtelCol) {
+		groupParams, err := nodeGroupParams(params, group)
+		if err != nil {
+			return nil, err
+		}
+		name := naming.NodeGroup(params.OtelCol.Name, group.Name)
+		ds := daemonSet(groupParams, name, name)
+		// the labels map is shared by the DaemonSet and its pod template
+		ds.Labels[NodeGroupLabel] = group.Name
+		ds.Spec.Selector.MatchLabels[NodeGroupLabel] = group.Name
+		daemonSets = append(daemonSets, ds)
+	}
+	return daemonSets, nil
+}
+
+// DaemonSetNames returns the names of the DaemonSets rendered for the instance, the default one first.
+func DaemonSetNames(instance v1alpha1.AmazonCloudWatchAgent) []string {
+	names := []string{naming.Collector(instance.Name)}
+	for _, group := range nodeGroups(instance) {
+		names = append(names, naming.NodeGroup(instance.Name, group.Name))
+	}
+	return names
+}
+
+// nodeGroups returns the node groups of the instance, which are only rendered in daemonset mode. The Windows
+// section is rendered as a node group selecting the Windows nodes, so that the default DaemonSet, whose image
+// and paths are the Linux ones, never lands on them. The hybrid section likewise keeps the default DaemonSet, which
+// relies on the instance metadata, off the hybrid nodes.
+func nodeGroups(instance v1alpha1.AmazonCloudWatchAgent) []v1alpha1.NodeGroup {
+	if instance.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return nil
+	}
+	groups := instance.Spec.NodeGroups
+	if windows := instance.Spec.Windows; windows != nil {
+		groups = append(slices.Clip(groups), v1alpha1.NodeGroup{
+			Name:         WindowsNodeGroup,
+			NodeSelector: map[string]string{corev1.LabelOSStable: string(corev1.Windows)},
+			Config:       windows.Config,
+			Tolerations:  windows.Tolerations,
+			Resources:    windows.Resources,
+		})
+	}
+	if hybrid := instance.Spec.Hybrid; hybrid != nil {
+		nodeSelector := hybrid.NodeSelector
+		if len(nodeSelector) == 0 {
+			nodeSelector = map[string]string{hybridComputeTypeLabel: hybridComputeType}
+		}
+		groups = append(slices.Clip(groups), v1alpha1.NodeGroup{
+			Name:             HybridNodeGroup,
+			NodeSelector:     nodeSelector,
+			Config:           hybrid.Config,
+			Tolerations:      hybrid.Tolerations,
+			Resources:        hybrid.Resources,
+			ContainerRuntime: hybrid.ContainerRuntime,
+		})
+	}
+	return groups
+}
+
+// nodeGroupParams returns the params as seen by the agents of the node group: the group configuration is merged
+// into the agent configuration and the group scheduling settings are added to the agent ones.
+func nodeGroupParams(params manifests.Params, group v1alpha1.NodeGroup) (manifests.Params, error) {
+	instance := *params.OtelCol.DeepCopy()
+	instance.Spec.NodeGroups = nil
+	if group.Config != "" {
+		merged, err := mergeJSONConfig(instance.Spec.Config, group.Config)
+		if err != nil {
+			return params, err
+		}
+		instance.Spec.Config = merged
+	}
+	if instance.Spec.NodeSelector == nil {
+		instance.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range group.NodeSelector {
+		instance.Spec.NodeSelector[k] = v
+	}
+	instance.Spec.Tolerations = append(instance.Spec.Tolerations, group.Tolerations...)
+	if group.Resources != nil {
+		instance.Spec.Resources = *group.Resources.DeepCopy()
+	}
+	if group.ContainerRuntime != nil {
+		instance.Spec.ContainerRuntime = group.ContainerRuntime.DeepCopy()
+	}
+	// the Windows node selector only holds the OS label so that the exclusion from the default DaemonSet covers
+	// every Windows node, the additional selector only narrows down the Windows DaemonSet
+	if windows := params.OtelCol.Spec.Windows; windows != nil && group.Name == WindowsNodeGroup {
+		for k, v := range windows.NodeSelector {
+			instance.Spec.NodeSelector[k] = v
+		}
+		if windows.Image != "" {
+			instance.Spec.Image = windows.Image
+		}
+		// the runtime sockets and host paths of the Linux nodes do not exist on Windows
+		instance.Spec.ContainerRuntime = nil
+		instance.Spec.HostMounts = nil
+		// the Linux user settings of the hardened agent cannot be applied to Windows containers
+		instance.Spec.Hardening = nil
+	}
+	if hybrid := params.OtelCol.Spec.Hybrid; hybrid != nil && group.Name == HybridNodeGroup {
+		hybridSpec(&instance, *hybrid, params.Config.AWSRegion())
+	}
+
+	groupParams := params
+	groupParams.OtelCol = instance
+	return groupParams, nil
+}
+
+// hybridSpec adapts the instance to the hybrid nodes, which have their own host paths and no instance metadata, so
+// that the agent neither waits for it nor finds its credentials and region there. The region detected by the
+// operator is the one of the cluster, which the agent sends its telemetry to unless it is set.
+func hybridSpec(instance *v1alpha1.AmazonCloudWatchAgent, hybrid v1alpha1.HybridSpec, region string) {
+	instance.Spec.HostMounts = slices.Clone(hybrid.HostMounts)
+	instance.Spec.Env = append(instance.Spec.Env, corev1.EnvVar{Name: envEC2MetadataDisabled, Value: "true"})
+	if hybrid.CredentialsFile != "" {
+		instance.Spec.HostMounts = append(instance.Spec.HostMounts, v1alpha1.HostMount{
+			Name:      naming.AWSCredentialsVolume(),
+			Path:      hybrid.CredentialsFile,
+			MountPath: hybridCredentialsMountPath,
+		})
+		instance.Spec.Env = append(instance.Spec.Env, corev1.EnvVar{Name: envSharedCredentialsFile, Value: hybridCredentialsMountPath})
+	}
+	if region != "" && agentRegion(*instance) == "" {
+		if instance.Spec.AWS == nil {
+			instance.Spec.AWS = &v1alpha1.AWSSpec{}
+		}
+		instance.Spec.AWS.Region = region
+	}
+}
+
+// excludeNodeGroups adds a required node affinity to the given affinity so that the pods do not run on the nodes
+// of any node group. A node is in a group when it has every label of the group selector, so it is excluded when
+// it misses at least one label of each group, which expands into one selector term per combination.
+func excludeNodeGroups(affinity *corev1.Affinity, groups []v1alpha1.NodeGroup) *corev1.Affinity {
+	if len(groups) == 0 {
+		return affinity
+	}
+
+	exclusions := [][]corev1.NodeSelectorRequirement{{}}
+	for _, group := range groups {
+		var next [][]corev1.NodeSelectorRequirement
+		for _, exclusion := range exclusions {
+			for _, key := range slices.Sorted(maps.Keys(group.NodeSelector)) {
+				requirement := corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: []string{group.NodeSelector[key]}}
+				next = append(next, append(slices.Clone(exclusion), requirement))
+			}
+		}
+		exclusions = next
+	}
+
+	result := affinity.DeepCopy()
+	if result == nil {
+		result = &corev1.Affinity{}
+	}
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+	}
+	var terms []corev1.NodeSelectorTerm
+	for _, term := range required.NodeSelectorTerms {
+		for _, exclusion := range exclusions {
+			combined := *term.DeepCopy()
+			combined.MatchExpressions = append(combined.MatchExpressions, exclusion...)
+			terms = append(terms, combined)
+		}
+	}
+	result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: terms}
+	return result
+}
@@ -0,0 +1,179 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestNodeGroups(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Config = `{"agent":{"region":"us-west-2"}}`
+	params.OtelCol.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	params.OtelCol.Spec.NodeGroups = []v1alpha1.NodeGroup{{
+		Name:         "gpu",
+		NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p4d.24xlarge"},
+		Config:       `{"logs":{"metrics_collected":{"kubernetes":{"accelerated_compute_metrics":true}}}}`,
+		Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+	}}
+
+	daemonSets, err := NodeGroupDaemonSets(params)
+	require.NoError(t, err)
+	require.Len(t, daemonSets, 1)
+	ds := daemonSets[0]
+	assert.Equal(t, "test-gpu", ds.Name)
+	assert.Equal(t, "gpu", ds.Spec.Selector.MatchLabels[NodeGroupLabel])
+	assert.Equal(t, "gpu", ds.Spec.Template.Labels[NodeGroupLabel])
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "node.kubernetes.io/instance-type": "p4d.24xlarge"}, ds.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, params.OtelCol.Spec.NodeGroups[0].Tolerations, ds.Spec.Template.Spec.Tolerations)
+	assert.Nil(t, ds.Spec.Template.Spec.Affinity)
+	for _, v := range ds.Spec.Template.Spec.Volumes {
+		if v.Name == naming.ConfigMapVolume() {
+			assert.Equal(t, "test-gpu", v.ConfigMap.Name)
+		}
+	}
+	assert.NotEqual(t, DaemonSet(params).Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"],
+		ds.Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, params.OtelCol.Spec.NodeSelector)
+
+	configMaps, err := ConfigMaps(params)
+	require.NoError(t, err)
+	require.Len(t, configMaps, 2)
+	assert.Equal(t, "test-gpu", configMaps[1].Name)
+	assert.JSONEq(t, `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"kubernetes":{"accelerated_compute_metrics":true}}}}`,
+		configMaps[1].Data[params.Config.CollectorConfigMapEntry()])
+
+	nodeAffinity := DaemonSet(params).Spec.Template.Spec.Affinity.NodeAffinity
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"p4d.24xlarge"}},
+		},
+	}}, nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeDeployment
+	daemonSets, err = NodeGroupDaemonSets(params)
+	require.NoError(t, err)
+	assert.Empty(t, daemonSets)
+}
+
+func TestHybridNodeGroup(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.Config = config.New(config.WithCollectorImage(defaultCollectorImage), config.WithAWSResource("us-west-2", ""))
+	params.OtelCol.Spec.HostMounts = []v1alpha1.HostMount{{Name: "var-log", Path: "/var/log"}}
+	params.OtelCol.Spec.Hybrid = &v1alpha1.HybridSpec{
+		HostMounts:      []v1alpha1.HostMount{{Name: "logs", Path: "/opt/logs"}},
+		CredentialsFile: "/root/.aws/credentials",
+	}
+
+	daemonSets, err := NodeGroupDaemonSets(params)
+	require.NoError(t, err)
+	require.Len(t, daemonSets, 1)
+	ds := daemonSets[0]
+	assert.Equal(t, "test-hybrid", ds.Name)
+	assert.Equal(t, map[string]string{"eks.amazonaws.com/compute-type": "hybrid"}, ds.Spec.Template.Spec.NodeSelector)
+	var hostPaths []string
+	for _, v := range ds.Spec.Template.Spec.Volumes {
+		if v.HostPath != nil {
+			hostPaths = append(hostPaths, v.HostPath.Path)
+		}
+	}
+	assert.Equal(t, []string{"/opt/logs", "/root/.aws/credentials"}, hostPaths)
+	agent := ds.Spec.Template.Spec.Containers[len(ds.Spec.Template.Spec.Containers)-1]
+	assert.Contains(t, agent.Env, corev1.EnvVar{Name: "AWS_EC2_METADATA_DISABLED", Value: "true"})
+	assert.Contains(t, agent.Env, corev1.EnvVar{Name: "AWS_SHARED_CREDENTIALS_FILE", Value: "/var/run/aws/credentials"})
+	assert.Contains(t, agent.VolumeMounts, corev1.VolumeMount{Name: naming.AWSCredentialsVolume(), MountPath: "/var/run/aws/credentials", ReadOnly: true})
+
+	// the region detected by the operator is only set for the hybrid nodes, which can't detect it
+	configMaps, err := ConfigMaps(params)
+	require.NoError(t, err)
+	regions := map[string]string{}
+	for _, cm := range configMaps {
+		if cm.Name != "test" && cm.Name != "test-hybrid" {
+			continue
+		}
+		conf, err := adapters.ConfigStructFromJSONString(cm.Data[params.Config.CollectorConfigMapEntry()])
+		require.NoError(t, err)
+		regions[cm.Name] = conf.GetRegion()
+	}
+	assert.Equal(t, map[string]string{"test": "", "test-hybrid": "us-west-2"}, regions)
+
+	// the default DaemonSet keeps its host mounts and stays off the hybrid nodes
+	defaultDS := DaemonSet(params)
+	assert.Equal(t, "/var/log", defaultDS.Spec.Template.Spec.Volumes[len(defaultDS.Spec.Template.Spec.Volumes)-1].HostPath.Path)
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "eks.amazonaws.com/compute-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"hybrid"}},
+		},
+	}}, defaultDS.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func TestExcludeNodeGroups(t *testing.T) {
+	groups := []v1alpha1.NodeGroup{
+		{Name: "a", NodeSelector: map[string]string{"pool": "a", "arch": "arm64"}},
+		{Name: "b", NodeSelector: map[string]string{"pool": "b"}},
+	}
+	notIn := func(key, value string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: []string{value}}
+	}
+	zone := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+		},
+	}}
+
+	result := excludeNodeGroups(affinity, groups)
+
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{zone, notIn("arch", "arm64"), notIn("pool", "b")}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{zone, notIn("pool", "a"), notIn("pool", "b")}},
+	}, result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	assert.Len(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+	assert.Same(t, affinity, excludeNodeGroups(affinity, nil))
+}
+
+func TestWindowsNodeGroup(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.Image = "cloudwatch-agent:linux"
+	params.OtelCol.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	params.OtelCol.Spec.NodeGroups = []v1alpha1.NodeGroup{{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}}}
+	params.OtelCol.Spec.Windows = &v1alpha1.WindowsSpec{
+		Image:        "cloudwatch-agent:windows",
+		NodeSelector: map[string]string{"pool": "windows"},
+		Tolerations:  []corev1.Toleration{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}},
+	}
+
+	daemonSets, err := NodeGroupDaemonSets(params)
+	require.NoError(t, err)
+	require.Len(t, daemonSets, 2)
+	ds := daemonSets[1]
+	assert.Equal(t, "test-windows", ds.Name)
+	assert.Equal(t, WindowsNodeGroup, ds.Spec.Selector.MatchLabels[NodeGroupLabel])
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "windows", "pool": "windows"}, ds.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, params.OtelCol.Spec.Windows.Tolerations, ds.Spec.Template.Spec.Tolerations)
+	agent := ds.Spec.Template.Spec.Containers[len(ds.Spec.Template.Spec.Containers)-1]
+	assert.Equal(t, "cloudwatch-agent:windows", agent.Image)
+	assert.Contains(t, agent.VolumeMounts, corev1.VolumeMount{
+		Name:      naming.ConfigMapVolume(),
+		MountPath: "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\cwagentconfig",
+	})
+	assert.Len(t, params.OtelCol.Spec.NodeGroups, 1)
+
+	nodeAffinity := DaemonSet(params).Spec.Template.Spec.Affinity.NodeAffinity
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+			{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"windows"}},
+		},
+	}}, nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
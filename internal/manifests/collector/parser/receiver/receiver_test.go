@@ -62,6 +62,9 @@ func TestReceiverParsePortFromEndpoint(t *testing.T) {
 		{"absolute with path", "http://localhost:1234/server-status?auto", 1234, false},
 		{"no protocol", "0.0.0.0:1234", 1234, false},
 		{"just port", ":1234", 1234, false},
+		{"ipv6 loopback", "[::1]:1234", 1234, false},
+		{"ipv6 with protocol and path", "http://[2001:db8::1]:1234/metrics", 1234, false},
+		{"ipv6 without port", "[::1]", 0, true},
 		{"no port at all", "http://localhost", 0, true},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
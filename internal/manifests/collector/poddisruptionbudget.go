@@ -35,8 +35,9 @@ func PodDisruptionBudget(params manifests.Params) *policyV1.PodDisruptionBudget
 		Spec: policyV1.PodDisruptionBudgetSpec{
 			MinAvailable:   params.OtelCol.Spec.PodDisruptionBudget.MinAvailable,
 			MaxUnavailable: params.OtelCol.Spec.PodDisruptionBudget.MaxUnavailable,
+			// the version label changes on upgrades, so only the stable selector labels are used
 			Selector: &metav1.LabelSelector{
-				MatchLabels: objectMeta.Labels,
+				MatchLabels: manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
 			},
 		},
 	}
@@ -80,6 +80,8 @@ func TestPDB(t *testing.T) {
 				assert.Equal(t, "my-instance", pdb.Labels["app.kubernetes.io/name"])
 				assert.Equal(t, test.MinAvailable, pdb.Spec.MinAvailable)
 				assert.Equal(t, test.MaxUnavailable, pdb.Spec.MaxUnavailable)
+				assert.NotContains(t, pdb.Spec.Selector.MatchLabels, "app.kubernetes.io/version")
+				assert.Equal(t, "amazon-cloudwatch-agent", pdb.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
 			})
 		}
 	}
@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// PodIdentityAssociationGVK is the kind of the EKS Pod Identity associations managed by the EKS controller of AWS
+// Controllers for Kubernetes.
+var PodIdentityAssociationGVK = schema.GroupVersionKind{
+	Group:   "eks.services.k8s.aws",
+	Version: "v1alpha1",
+	Kind:    "PodIdentityAssociation",
+}
+
+// PodIdentityAssociation returns the EKS Pod Identity association binding the service account of the instance to
+// its IAM role, or nil when the instance does not use Pod Identity.
+func PodIdentityAssociation(params manifests.Params) *unstructured.Unstructured {
+	association := params.OtelCol.Spec.PodIdentityAssociation
+	if association == nil {
+		return nil
+	}
+	name := naming.PodIdentityAssociation(params.OtelCol.Name)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"clusterName":    association.ClusterName,
+				"namespace":      params.OtelCol.Namespace,
+				"serviceAccount": ServiceAccountName(params.OtelCol),
+				"roleARN":        association.RoleARN,
+			},
+		},
+	}
+	obj.SetGroupVersionKind(PodIdentityAssociationGVK)
+	obj.SetName(name)
+	obj.SetNamespace(params.OtelCol.Namespace)
+	obj.SetLabels(labels)
+	return obj
+}
@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+func TestPodIdentityAssociation(t *testing.T) {
+	params := manifests.Params{
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-instance",
+				Namespace: "amazon-cloudwatch",
+			},
+		},
+	}
+	assert.Nil(t, PodIdentityAssociation(params))
+
+	params.OtelCol.Spec.ServiceAccount = "cloudwatch-agent"
+	params.OtelCol.Spec.PodIdentityAssociation = &v1alpha1.PodIdentityAssociationSpec{
+		ClusterName: "my-cluster",
+		RoleARN:     "arn:aws:iam::123456789012:role/cloudwatch-agent",
+	}
+	association := PodIdentityAssociation(params)
+	require.NotNil(t, association)
+
+	assert.Equal(t, PodIdentityAssociationGVK, association.GroupVersionKind())
+	assert.Equal(t, "my-instance", association.GetName())
+	assert.Equal(t, "amazon-cloudwatch", association.GetNamespace())
+	assert.Equal(t, map[string]interface{}{
+		"clusterName":    "my-cluster",
+		"namespace":      "amazon-cloudwatch",
+		"serviceAccount": "cloudwatch-agent",
+		"roleARN":        "arn:aws:iam::123456789012:role/cloudwatch-agent",
+	}, association.Object["spec"])
+}
@@ -9,7 +9,6 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/go-logr/logr"
 	"go.uber.org/zap"
@@ -302,10 +301,11 @@ func portFromEndpoint(endpoint string) (int32, error) {
 	var err error
 	var port int64
 
-	r := regexp.MustCompile(":[0-9]+")
+	// the port is the last colon separated segment of the host, so IPv6 literals like [::1]:4317 are skipped
+	r := regexp.MustCompile(`:([0-9]+)(?:[/?]|$)`)
 
-	if r.MatchString(endpoint) {
-		port, err = strconv.ParseInt(strings.Replace(r.FindString(endpoint), ":", "", -1), 10, 32)
+	if match := r.FindStringSubmatch(endpoint); match != nil {
+		port, err = strconv.ParseInt(match[1], 10, 32)
 
 		if err != nil {
 			return 0, err
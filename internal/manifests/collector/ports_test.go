@@ -522,3 +522,19 @@ func getJSONStringFromFile(path string) string {
 	}
 	return string(buf)
 }
+
+func TestPortFromEndpoint(t *testing.T) {
+	for endpoint, expected := range map[string]int32{
+		":8125":             8125,
+		"0.0.0.0:8125":      8125,
+		"udp://[::]:25888":  25888,
+		"[::1]:8125":        8125,
+		"[2001:db8::1]:443": 443,
+	} {
+		port, err := portFromEndpoint(endpoint)
+		assert.NoError(t, err, endpoint)
+		assert.Equal(t, expected, port, endpoint)
+	}
+	_, err := portFromEndpoint("[::1]")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// reloadsPrometheusConfig tells whether the agent pods run the sidecar reloading their Prometheus configuration,
+// instead of rolling on its changes. The Windows agents have no shared process namespace and still roll.
+func reloadsPrometheusConfig(agent v1alpha1.AmazonCloudWatchAgent) bool {
+	return agent.Spec.PrometheusConfigReload != nil && agent.Spec.PrometheusConfigReload.Enabled &&
+		!agent.Spec.Prometheus.IsEmpty() && agent.Spec.NodeSelector["kubernetes.io/os"] != "windows"
+}
+
+// shareProcessNamespace shares the process namespace of the agent pods running the reloader of their Prometheus
+// configuration, which signals the agent.
+func shareProcessNamespace(agent v1alpha1.AmazonCloudWatchAgent) *bool {
+	if !reloadsPrometheusConfig(agent) {
+		return nil
+	}
+	share := true
+	return &share
+}
+
+// prometheusConfigReloaderContainer returns the sidecar which sends SIGHUP to the agent once the kubelet updates the
+// file of the mounted Prometheus configuration ConfigMap. It sees the agent through the shared process namespace of
+// the pod and runs as the user of the agent, root unless set otherwise, as only this user can signal it.
+func prometheusConfigReloaderContainer(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent, agentContainer corev1.Container) corev1.Container {
+	volumeMount := getPrometheusVolumeMounts("linux")
+	volumeMount.ReadOnly = true
+
+	allowPrivilegeEscalation, readOnlyRootFilesystem := false, true
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	switch podSecurityContext := podSecurityContext(agent); {
+	case agentContainer.SecurityContext != nil && agentContainer.SecurityContext.RunAsUser != nil:
+		securityContext.RunAsUser = agentContainer.SecurityContext.RunAsUser
+		securityContext.RunAsGroup = agentContainer.SecurityContext.RunAsGroup
+	case podSecurityContext != nil && podSecurityContext.RunAsUser != nil:
+		// the user of the pod applies to both containers
+	default:
+		// the agent image runs as root, unlike the image of the reloader
+		root := int64(0)
+		securityContext.RunAsUser = &root
+	}
+
+	return corev1.Container{
+		Name:            naming.PrometheusConfigReloaderContainer(),
+		Image:           cfg.PrometheusConfigReloaderImage(),
+		ImagePullPolicy: agent.Spec.ImagePullPolicy,
+		Args:            []string{"--reload-prometheus-config=" + path.Join(volumeMount.MountPath, cfg.PrometheusConfigMapEntry())},
+		VolumeMounts:    []corev1.VolumeMount{volumeMount},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+		SecurityContext: securityContext,
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envHTTPSProxy = "HTTPS_PROXY"
+	envHTTPProxy  = "HTTP_PROXY"
+	envNoProxy    = "NO_PROXY"
+)
+
+// agentNoProxyHosts are the hosts the agent reaches without leaving the node or the cluster: the instance metadata
+// service and the EKS Pod Identity agent on their IPv4 and IPv6 link-local addresses, and the cluster services.
+var agentNoProxyHosts = []string{
+	"localhost",
+	"127.0.0.1",
+	"169.254.169.254",
+	"fd00:ec2::254",
+	"169.254.170.23",
+	"fd00:ec2::23",
+	".svc",
+	".cluster.local",
+}
+
+// proxyEnvVars sets the proxy env vars of the instance. Proxies already set in the env vars of the spec are kept,
+// while the hosts of the spec and the hosts of the node and the cluster are appended to its NO_PROXY.
+func proxyEnvVars(proxy *v1alpha1.Proxy, envVars []corev1.EnvVar) []corev1.EnvVar {
+	if proxy == nil || (proxy.HTTPSProxy == "" && proxy.HTTPProxy == "") {
+		return envVars
+	}
+	if proxy.HTTPSProxy != "" && !hasEnvVar(envVars, envHTTPSProxy) {
+		envVars = append(envVars, corev1.EnvVar{Name: envHTTPSProxy, Value: proxy.HTTPSProxy})
+	}
+	if proxy.HTTPProxy != "" && !hasEnvVar(envVars, envHTTPProxy) {
+		envVars = append(envVars, corev1.EnvVar{Name: envHTTPProxy, Value: proxy.HTTPProxy})
+	}
+
+	noProxy := append(slices.Clone(proxy.NoProxy), agentNoProxyHosts...)
+	idx := slices.IndexFunc(envVars, func(env corev1.EnvVar) bool { return env.Name == envNoProxy })
+	switch {
+	case idx == -1:
+		envVars = append(envVars, corev1.EnvVar{Name: envNoProxy, Value: joinHosts(nil, noProxy)})
+	case envVars[idx].ValueFrom == nil:
+		envVars[idx].Value = joinHosts(strings.Split(envVars[idx].Value, ","), noProxy)
+	}
+	return envVars
+}
+
+// joinHosts appends the hosts missing from the existing list and joins them into a NO_PROXY value.
+func joinHosts(existing []string, hosts []string) string {
+	joined := make([]string, 0, len(existing)+len(hosts))
+	for _, host := range append(existing, hosts...) {
+		if host = strings.TrimSpace(host); host != "" && !slices.Contains(joined, host) {
+			joined = append(joined, host)
+		}
+	}
+	return strings.Join(joined, ",")
+}
@@ -0,0 +1,356 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// The operator holds every permission it grants to the agents, as Kubernetes does not let it grant more.
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=endpoints;events;namespaces;nodes;pods;replicationcontrollers;resourcequotas;services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes/proxy;nodes/stats,verbs=get
+// +kubebuilder:rbac:groups="",resources=configmaps;events,verbs=create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;replicasets;statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metrics.eks.amazonaws.com,resources=kcm/metrics;ksh/metrics,verbs=get
+// +kubebuilder:rbac:urls=/metrics,verbs=get
+
+var readVerbs = []string{"get", "list", "watch"}
+
+// leaderElectionConfigMap is the ConfigMap the agents collecting cluster level metrics elect their leader with.
+const leaderElectionConfigMap = "cwagent-clusterleader"
+
+// legacyAgentRules are the permissions of every agent feature, which all the agents are granted when the operator
+// keeps the legacy agent RBAC.
+var legacyAgentRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{""}, Resources: []string{"pods", "nodes", "namespaces", "endpoints"}, Verbs: readVerbs},
+	{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: readVerbs},
+	{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"list", "watch"}},
+	{APIGroups: []string{"apps"}, Resources: []string{"replicasets", "daemonsets", "deployments", "statefulsets"}, Verbs: readVerbs},
+	{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"nodes/proxy"}, Verbs: []string{"get"}},
+	{APIGroups: []string{""}, Resources: []string{"nodes/stats", "configmaps", "events"}, Verbs: []string{"create", "get"}},
+	{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{leaderElectionConfigMap}, Verbs: []string{"get", "update"}},
+	{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+}
+
+// ClusterRole returns the cluster role granting the agent of the instance the cluster permissions its configuration
+// needs, or nil when it needs none or when the operator is namespace-scoped.
+func ClusterRole(params manifests.Params) *rbacv1.ClusterRole {
+	rules := agentRules(params)
+	if len(rules) == 0 || params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.ClusterRole(params.OtelCol.Name, params.OtelCol.Namespace)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Rules: rules,
+	}
+}
+
+// ClusterRoleBinding returns the binding of the cluster role of the instance to its service account, or nil when
+// the instance has no cluster role.
+func ClusterRoleBinding(params manifests.Params) *rbacv1.ClusterRoleBinding {
+	if len(agentRules(params)) == 0 || params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.ClusterRoleBinding(params.OtelCol.Name, params.OtelCol.Namespace)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     naming.ClusterRole(params.OtelCol.Name, params.OtelCol.Namespace),
+		},
+	}
+}
+
+// Role returns the role granting the agent of the instance the permissions its configuration needs on the objects
+// of its namespace, when the operator is namespace-scoped and cannot grant cluster permissions. It returns nil
+// otherwise, or when the agent needs no namespaced permission.
+func Role(params manifests.Params) *rbacv1.Role {
+	rules := manifestutils.NamespacedRules(agentRules(params))
+	if len(rules) == 0 || !params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.Role(params.OtelCol.Name)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    labels,
+		},
+		Rules: rules,
+	}
+}
+
+// RoleBinding returns the binding of the role of the instance to its service account, or nil when the instance has
+// no role.
+func RoleBinding(params manifests.Params) *rbacv1.RoleBinding {
+	if Role(params) == nil {
+		return nil
+	}
+	name := naming.RoleBinding(params.OtelCol.Name)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    labels,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     naming.Role(params.OtelCol.Name),
+		},
+	}
+}
+
+// agentRules returns the permissions of the agent of the instance. Sidecars run with the service account of the
+// workload they are injected in, so they are not granted any.
+func agentRules(params manifests.Params) []rbacv1.PolicyRule {
+	if params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
+		return nil
+	}
+	if params.Config.LegacyAgentRBAC() {
+		return legacyAgentRules
+	}
+
+	grants := permissions{}
+	grants.agentConfig(params.OtelCol.Spec.Config)
+	grants.otelConfig(params.OtelCol.Spec.OtelConfig)
+	if params.OtelCol.Spec.Prometheus.Config != nil {
+		grants.prometheus()
+	}
+	if kubernetesEventsEnabled(params.OtelCol) {
+		grants.kubernetesEvents()
+	}
+	if controlPlaneMetricsEnabled(params.OtelCol) {
+		grants.controlPlaneMetrics()
+	}
+	if fluentBitEnabled(params.OtelCol) {
+		grants.fluentBit()
+	}
+	return grants.rules()
+}
+
+// permission is a resource of an API group, optionally restricted to a single object, or a non-resource URL.
+type permission struct {
+	apiGroup       string
+	resource       string
+	resourceName   string
+	nonResourceURL string
+}
+
+// permissions maps the permissions of an agent to the verbs it is granted on them.
+type permissions map[permission][]string
+
+func (p permissions) grant(apiGroup string, verbs []string, resources ...string) {
+	for _, resource := range resources {
+		key := permission{apiGroup: apiGroup, resource: resource}
+		p[key] = append(p[key], verbs...)
+	}
+}
+
+// agentConfig grants the permissions of the features enabled in the agent JSON configuration.
+func (p permissions) agentConfig(config string) {
+	conf, err := adapters.ConfigStructFromJSONString(config)
+	if err != nil || conf == nil {
+		return
+	}
+	if conf.Logs != nil && conf.Logs.LogMetricsCollected != nil {
+		if conf.Logs.LogMetricsCollected.Kubernetes != nil {
+			p.containerInsights()
+		}
+		if conf.Logs.LogMetricsCollected.Prometheus != nil {
+			p.prometheus()
+		}
+	}
+	if conf.GetApplicationSignalsMetricsConfig() != nil || conf.GetApplicationSignalsTracesConfig() != nil {
+		p.applicationSignals()
+	}
+}
+
+// otelConfig grants the permissions of the Kubernetes receivers and processors of the OpenTelemetry configuration.
+// The objects watched by the k8sobjects receiver are only known at runtime, so they are left to the user to grant.
+func (p permissions) otelConfig(config string) {
+	if config == "" {
+		return
+	}
+	conf, err := adapters.ConfigFromString(config)
+	if err != nil {
+		return
+	}
+	for _, component := range append(componentTypes(conf, "receivers"), componentTypes(conf, "processors")...) {
+		switch component {
+		case "awscontainerinsightreceiver":
+			p.containerInsights()
+		case "kubeletstats":
+			p.kubelet()
+		case "prometheus":
+			p.prometheus()
+		case "k8s_cluster":
+			p.grant("", readVerbs, "events", "namespaces", "nodes", "pods", "replicationcontrollers", "resourcequotas", "services")
+			p.grant("apps", readVerbs, "daemonsets", "deployments", "replicasets", "statefulsets")
+			p.grant("batch", readVerbs, "cronjobs", "jobs")
+			p.grant("autoscaling", readVerbs, "horizontalpodautoscalers")
+		case "k8s_events":
+			p.kubernetesEvents()
+		case "k8sattributes":
+			p.grant("", readVerbs, "namespaces", "nodes", "pods")
+			p.grant("apps", readVerbs, "replicasets")
+		}
+	}
+}
+
+func (p permissions) containerInsights() {
+	p.kubelet()
+	p.grant("", readVerbs, "endpoints", "namespaces", "nodes", "pods", "services")
+	p.grant("apps", readVerbs, "daemonsets", "deployments", "replicasets", "statefulsets")
+	p.grant("batch", readVerbs, "jobs")
+	p.grant("discovery.k8s.io", readVerbs, "endpointslices")
+	// the agents elect the one collecting the cluster level metrics, and record the election in events
+	p.grant("", []string{"create"}, "configmaps", "events")
+	key := permission{resource: "configmaps", resourceName: leaderElectionConfigMap}
+	p[key] = append(p[key], "get", "update")
+}
+
+func (p permissions) kubelet() {
+	p.grant("", readVerbs, "nodes")
+	p.grant("", []string{"get"}, "nodes/proxy", "nodes/stats")
+}
+
+func (p permissions) kubernetesEvents() {
+	p.grant("", readVerbs, "events", "namespaces")
+}
+
+func (p permissions) prometheus() {
+	p.grant("", readVerbs, "endpoints", "nodes", "pods", "services")
+	p.grant("discovery.k8s.io", readVerbs, "endpointslices")
+	key := permission{nonResourceURL: "/metrics"}
+	p[key] = append(p[key], "get")
+}
+
+// controlPlaneMetrics grants the metrics of the API server, and the ones of the scheduler and the controller manager
+// which EKS serves as resources of the metrics.eks.amazonaws.com group.
+func (p permissions) controlPlaneMetrics() {
+	p.grant("metrics.eks.amazonaws.com", []string{"get"}, "kcm/metrics", "ksh/metrics")
+	key := permission{nonResourceURL: "/metrics"}
+	p[key] = append(p[key], "get")
+}
+
+// fluentBit grants the Kubernetes filter of Fluent Bit the metadata of the pods, which it queries from the kubelet.
+func (p permissions) fluentBit() {
+	p.grant("", readVerbs, "namespaces", "pods")
+	p.grant("", []string{"get"}, "nodes/proxy")
+}
+
+func (p permissions) applicationSignals() {
+	p.grant("", readVerbs, "endpoints", "namespaces", "nodes", "pods", "services")
+	p.grant("apps", readVerbs, "daemonsets", "deployments", "replicasets", "statefulsets")
+	p.grant("discovery.k8s.io", readVerbs, "endpointslices")
+}
+
+// rules merges the permissions sharing an API group, an object and verbs into a rule, in a stable order so that
+// the cluster role is not updated when the permissions did not change.
+func (p permissions) rules() []rbacv1.PolicyRule {
+	type ruleKey struct {
+		apiGroup     string
+		resourceName string
+		verbs        string
+	}
+	resources := map[ruleKey][]string{}
+	nonResourceURLs := map[string][]string{}
+	for perm, verbs := range p {
+		verbs = slices.Clone(verbs)
+		slices.Sort(verbs)
+		joined := strings.Join(slices.Compact(verbs), ",")
+		if perm.nonResourceURL != "" {
+			nonResourceURLs[joined] = append(nonResourceURLs[joined], perm.nonResourceURL)
+			continue
+		}
+		key := ruleKey{apiGroup: perm.apiGroup, resourceName: perm.resourceName, verbs: joined}
+		resources[key] = append(resources[key], perm.resource)
+	}
+
+	var rules []rbacv1.PolicyRule
+	keys := slices.SortedFunc(maps.Keys(resources), func(a, b ruleKey) int {
+		return cmp.Or(cmp.Compare(a.apiGroup, b.apiGroup), cmp.Compare(a.resourceName, b.resourceName), cmp.Compare(a.verbs, b.verbs))
+	})
+	for _, key := range keys {
+		rule := rbacv1.PolicyRule{
+			APIGroups: []string{key.apiGroup},
+			Resources: slices.Sorted(slices.Values(resources[key])),
+			Verbs:     strings.Split(key.verbs, ","),
+		}
+		if key.resourceName != "" {
+			rule.ResourceNames = []string{key.resourceName}
+		}
+		rules = append(rules, rule)
+	}
+	for _, verbs := range slices.Sorted(maps.Keys(nonResourceURLs)) {
+		rules = append(rules, rbacv1.PolicyRule{
+			NonResourceURLs: slices.Sorted(slices.Values(nonResourceURLs[verbs])),
+			Verbs:           strings.Split(verbs, ","),
+		})
+	}
+	return rules
+}
+
+// componentTypes returns the types of the components of the given kind in the OpenTelemetry configuration, the
+// part of their names before the slash.
+func componentTypes(conf map[interface{}]interface{}, kind string) []string {
+	components, ok := conf[kind].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	var types []string
+	for name := range components {
+		if name, ok := name.(string); ok {
+			componentType, _, _ := strings.Cut(name, "/")
+			types = append(types, componentType)
+		}
+	}
+	return types
+}
@@ -0,0 +1,177 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+func rbacParams(cfg config.Config, spec v1alpha1.AmazonCloudWatchAgentSpec) manifests.Params {
+	return manifests.Params{
+		Config: cfg,
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+			Spec:       spec,
+		},
+	}
+}
+
+func TestClusterRoleContainerInsights(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:   v1alpha1.ModeDaemonSet,
+		Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Equal(t, "cloudwatch-agent-amazon-cloudwatch-cluster-role", cr.Name)
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes/proxy", "nodes/stats"}, Verbs: []string{"get"}})
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"cwagent-clusterleader"}, Verbs: []string{"get", "update"}})
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"get", "list", "watch"}})
+	for _, rule := range cr.Rules {
+		assert.Empty(t, rule.NonResourceURLs)
+	}
+
+	crb := ClusterRoleBinding(params)
+	require.NotNil(t, crb)
+	assert.Equal(t, cr.Name, crb.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"}}, crb.Subjects)
+}
+
+func TestClusterRoleApplicationSignals(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:   v1alpha1.ModeDeployment,
+		Config: `{"traces":{"traces_collected":{"application_signals":{}}}}`,
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"endpoints", "namespaces", "nodes", "pods", "services"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"daemonsets", "deployments", "replicasets", "statefulsets"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: []string{"get", "list", "watch"}},
+	}, cr.Rules)
+}
+
+func TestClusterRoleOtelConfig(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode: v1alpha1.ModeDeployment,
+		OtelConfig: `
+receivers:
+  prometheus/pods:
+    config: {}
+processors:
+  k8sattributes:
+exporters:
+  debug:
+`,
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"endpoints", "namespaces", "nodes", "pods", "services"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"replicasets"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: []string{"get", "list", "watch"}},
+		{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+	}, cr.Rules)
+}
+
+func TestClusterRoleControlPlaneMetrics(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:                v1alpha1.ModeDeployment,
+		ControlPlaneMetrics: &v1alpha1.ControlPlaneMetricsSpec{Enabled: true, ClusterName: "my-cluster"},
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{"metrics.eks.amazonaws.com"}, Resources: []string{"kcm/metrics", "ksh/metrics"}, Verbs: []string{"get"}},
+		{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+	}, cr.Rules)
+}
+
+func TestClusterRoleFluentBit(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:   v1alpha1.ModeDaemonSet,
+		Config: `{"metrics":{"metrics_collected":{"statsd":{}}}}`,
+		Logs:   &v1alpha1.LogsSpec{Enabled: true},
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"nodes/proxy"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"namespaces", "pods"}, Verbs: []string{"get", "list", "watch"}},
+	}, cr.Rules)
+}
+
+func TestClusterRoleWithoutPermissions(t *testing.T) {
+	params := rbacParams(config.New(), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:   v1alpha1.ModeDeployment,
+		Config: `{"metrics":{"metrics_collected":{"statsd":{}}}}`,
+	})
+
+	assert.Nil(t, ClusterRole(params))
+	assert.Nil(t, ClusterRoleBinding(params))
+}
+
+func TestClusterRoleLegacy(t *testing.T) {
+	params := rbacParams(config.New(config.WithLegacyAgentRBAC(true)), v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:           v1alpha1.ModeDaemonSet,
+		ServiceAccount: "agent",
+	})
+
+	cr := ClusterRole(params)
+	require.NotNil(t, cr)
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes/proxy"}, Verbs: []string{"get"}})
+
+	crb := ClusterRoleBinding(params)
+	require.NotNil(t, crb)
+	assert.Equal(t, "agent", crb.Subjects[0].Name)
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+	assert.Nil(t, ClusterRole(params))
+}
+
+func TestRoleNamespaceScoped(t *testing.T) {
+	spec := v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:   v1alpha1.ModeDaemonSet,
+		Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+	}
+	assert.Nil(t, Role(rbacParams(config.New(), spec)))
+	assert.Nil(t, RoleBinding(rbacParams(config.New(), spec)))
+
+	params := rbacParams(config.New(config.WithWatchNamespaces([]string{"amazon-cloudwatch"})), spec)
+	assert.Nil(t, ClusterRole(params))
+	assert.Nil(t, ClusterRoleBinding(params))
+
+	role := Role(params)
+	require.NotNil(t, role)
+	assert.Equal(t, "cloudwatch-agent-role", role.Name)
+	assert.Equal(t, "amazon-cloudwatch", role.Namespace)
+	assert.Contains(t, role.Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"cwagent-clusterleader"}, Verbs: []string{"get", "update"}})
+	for _, rule := range role.Rules {
+		assert.Empty(t, rule.NonResourceURLs)
+		assert.NotContains(t, rule.Resources, "nodes")
+		assert.NotContains(t, rule.Resources, "nodes/proxy")
+	}
+
+	rb := RoleBinding(params)
+	require.NotNil(t, rb)
+	assert.Equal(t, "cloudwatch-agent-role-binding", rb.Name)
+	assert.Equal(t, "amazon-cloudwatch", rb.Namespace)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "cloudwatch-agent-role"}, rb.RoleRef)
+	assert.Equal(t, "amazon-cloudwatch", rb.Subjects[0].Namespace)
+}
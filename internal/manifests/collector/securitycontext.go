@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// hardenedID is the UID and GID of the hardened agent when the instance does not set them.
+const hardenedID int64 = 10001
+
+// containerSecurityContext returns the security context of the agent container. Without hardening it is the one
+// set by the user. Hardened agents run as non-root, cannot escalate privileges and drop every capability but the
+// ones required by the enabled features: binding a port below 1024 and reading host files owned by other users.
+func containerSecurityContext(agent v1alpha1.AmazonCloudWatchAgent, ports []corev1.ContainerPort) *corev1.SecurityContext {
+	if agent.Spec.Hardening == nil {
+		return agent.Spec.SecurityContext
+	}
+
+	securityContext := agent.Spec.SecurityContext.DeepCopy()
+	if securityContext == nil {
+		securityContext = &corev1.SecurityContext{}
+	}
+	runAsUser, runAsGroup := hardenedIDs(agent.Spec.Hardening)
+	runAsNonRoot, allowPrivilegeEscalation, privileged := true, false, false
+	securityContext.RunAsNonRoot = &runAsNonRoot
+	securityContext.RunAsUser = &runAsUser
+	securityContext.RunAsGroup = &runAsGroup
+	securityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+	securityContext.Privileged = &privileged
+	securityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+
+	var capabilities []corev1.Capability
+	for _, port := range ports {
+		if port.ContainerPort < 1024 {
+			capabilities = append(capabilities, "NET_BIND_SERVICE")
+			break
+		}
+	}
+	if len(agent.Spec.HostMounts) > 0 {
+		capabilities = append(capabilities, "DAC_READ_SEARCH")
+	}
+	securityContext.Capabilities = &corev1.Capabilities{
+		Add:  capabilities,
+		Drop: []corev1.Capability{"ALL"},
+	}
+	return securityContext
+}
+
+// podSecurityContext returns the security context of the agent pods, which runs the hardened agents as non-root
+// and makes their volumes readable through the fsGroup.
+func podSecurityContext(otelcol v1alpha1.AmazonCloudWatchAgent) *corev1.PodSecurityContext {
+	if otelcol.Spec.Hardening == nil {
+		return otelcol.Spec.PodSecurityContext
+	}
+
+	securityContext := otelcol.Spec.PodSecurityContext.DeepCopy()
+	if securityContext == nil {
+		securityContext = &corev1.PodSecurityContext{}
+	}
+	runAsUser, runAsGroup := hardenedIDs(otelcol.Spec.Hardening)
+	runAsNonRoot := true
+	securityContext.RunAsNonRoot = &runAsNonRoot
+	securityContext.RunAsUser = &runAsUser
+	securityContext.RunAsGroup = &runAsGroup
+	securityContext.FSGroup = &runAsGroup
+	securityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	return securityContext
+}
+
+func hardenedIDs(hardening *v1alpha1.HardeningSpec) (int64, int64) {
+	runAsUser, runAsGroup := hardenedID, hardenedID
+	if hardening.RunAsUser != nil {
+		runAsUser = *hardening.RunAsUser
+	}
+	if hardening.RunAsGroup != nil {
+		runAsGroup = *hardening.RunAsGroup
+	}
+	return runAsUser, runAsGroup
+}
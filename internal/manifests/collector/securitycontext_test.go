@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestContainerSecurityContext(t *testing.T) {
+	readOnlyRootFilesystem := true
+	uid := int64(2000)
+
+	tests := []struct {
+		name                 string
+		spec                 v1alpha1.AmazonCloudWatchAgentSpec
+		ports                []corev1.ContainerPort
+		expectedUser         int64
+		expectedCapabilities []corev1.Capability
+	}{
+		{
+			name:         "defaults",
+			spec:         v1alpha1.AmazonCloudWatchAgentSpec{Hardening: &v1alpha1.HardeningSpec{}},
+			ports:        []corev1.ContainerPort{{Name: "otlp-grpc", ContainerPort: 4317}},
+			expectedUser: 10001,
+		},
+		{
+			name:                 "privileged port",
+			spec:                 v1alpha1.AmazonCloudWatchAgentSpec{Hardening: &v1alpha1.HardeningSpec{RunAsUser: &uid}},
+			ports:                []corev1.ContainerPort{{Name: "statsd", ContainerPort: 8125}, {Name: "syslog", ContainerPort: 514}},
+			expectedUser:         2000,
+			expectedCapabilities: []corev1.Capability{"NET_BIND_SERVICE"},
+		},
+		{
+			name: "host mounts",
+			spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Hardening:  &v1alpha1.HardeningSpec{},
+				HostMounts: []v1alpha1.HostMount{{Name: "logs", Path: "/var/log/pods"}},
+			},
+			expectedUser:         10001,
+			expectedCapabilities: []corev1.Capability{"DAC_READ_SEARCH"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnlyRootFilesystem}
+			securityContext := containerSecurityContext(v1alpha1.AmazonCloudWatchAgent{Spec: tt.spec}, tt.ports)
+
+			assert.True(t, *securityContext.RunAsNonRoot)
+			assert.Equal(t, tt.expectedUser, *securityContext.RunAsUser)
+			assert.Equal(t, int64(10001), *securityContext.RunAsGroup)
+			assert.False(t, *securityContext.AllowPrivilegeEscalation)
+			assert.Equal(t, []corev1.Capability{"ALL"}, securityContext.Capabilities.Drop)
+			assert.Equal(t, tt.expectedCapabilities, securityContext.Capabilities.Add)
+			assert.Equal(t, &readOnlyRootFilesystem, securityContext.ReadOnlyRootFilesystem)
+			assert.Nil(t, tt.spec.SecurityContext.RunAsNonRoot)
+		})
+	}
+}
+
+func TestPodSecurityContext(t *testing.T) {
+	gid := int64(3000)
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			PodSecurityContext: &corev1.PodSecurityContext{SupplementalGroups: []int64{4000}},
+		},
+	}
+	assert.Same(t, otelcol.Spec.PodSecurityContext, podSecurityContext(otelcol))
+
+	otelcol.Spec.Hardening = &v1alpha1.HardeningSpec{RunAsGroup: &gid}
+	securityContext := podSecurityContext(otelcol)
+	assert.True(t, *securityContext.RunAsNonRoot)
+	assert.Equal(t, int64(10001), *securityContext.RunAsUser)
+	assert.Equal(t, int64(3000), *securityContext.RunAsGroup)
+	assert.Equal(t, int64(3000), *securityContext.FSGroup)
+	assert.Equal(t, []int64{4000}, securityContext.SupplementalGroups)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, securityContext.SeccompProfile.Type)
+}
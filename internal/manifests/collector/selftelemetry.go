@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+const (
+	selfTelemetryPipeline  = "metrics/self_telemetry"
+	selfTelemetryReceiver  = "prometheus/self_telemetry"
+	selfTelemetryProcessor = "resource/self_telemetry"
+	selfTelemetryExporter  = "awsemf/self_telemetry"
+	selfTelemetryNamespace = "CWAgent/SelfTelemetry"
+	selfTelemetryLogGroup  = "/aws/cwagent/self-telemetry"
+
+	selfTelemetryDefaultPort  = 8888
+	selfTelemetryDefaultLevel = "normal"
+
+	// monitoringPortName is the name of the port of the internal metrics, scraped by the ServiceMonitor and
+	// PodMonitor of the instance.
+	monitoringPortName = "monitoring"
+)
+
+// selfTelemetryMetrics are the internal metrics sent to CloudWatch, the ones reporting the telemetry received and
+// sent by the agent, and the telemetry it lost.
+var selfTelemetryMetrics = []string{
+	"otelcol_exporter_enqueue_failed_.+",
+	"otelcol_exporter_queue_size",
+	"otelcol_exporter_send_failed_.+",
+	"otelcol_exporter_sent_.+",
+	"otelcol_processor_dropped_.+",
+	"otelcol_receiver_accepted_.+",
+	"otelcol_receiver_refused_.+",
+}
+
+// selfTelemetryPort returns the port of the internal metrics of the agent, and whether they are exposed.
+func selfTelemetryPort(instance v1alpha1.AmazonCloudWatchAgent) (int32, bool) {
+	selfTelemetry := instance.Spec.SelfTelemetry
+	if selfTelemetry == nil || !selfTelemetry.Enabled {
+		return 0, false
+	}
+	if selfTelemetry.Port != 0 {
+		return selfTelemetry.Port, true
+	}
+	return selfTelemetryDefaultPort, true
+}
+
+func selfTelemetryCloudWatchEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	_, enabled := selfTelemetryPort(instance)
+	return enabled && instance.Spec.SelfTelemetry.CloudWatch
+}
+
+// withSelfTelemetry merges the service telemetry of the agent, and the pipeline sending it to CloudWatch when
+// enabled, into the OpenTelemetry configuration of the instance.
+func withSelfTelemetry(params manifests.Params) (manifests.Params, error) {
+	if _, enabled := selfTelemetryPort(params.OtelCol); !enabled {
+		return params, nil
+	}
+	preset, err := selfTelemetryOtelConfig(params.OtelCol)
+	if err != nil {
+		return params, err
+	}
+	return withOtelConfigPreset(params, preset)
+}
+
+// selfTelemetryOtelConfig renders the service telemetry of the agent. When the metrics are sent to CloudWatch, the
+// agent scrapes its own endpoint and tags the metrics with the names of its node and pod.
+func selfTelemetryOtelConfig(instance v1alpha1.AmazonCloudWatchAgent) (map[interface{}]interface{}, error) {
+	port, _ := selfTelemetryPort(instance)
+	level := instance.Spec.SelfTelemetry.Level
+	if level == "" {
+		level = selfTelemetryDefaultLevel
+	}
+	config := map[string]interface{}{
+		"service": map[string]interface{}{
+			"telemetry": map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"level":   level,
+					"address": fmt.Sprintf("0.0.0.0:%d", port),
+				},
+			},
+		},
+	}
+
+	if instance.Spec.SelfTelemetry.CloudWatch {
+		namespace := instance.Spec.SelfTelemetry.Namespace
+		if namespace == "" {
+			namespace = selfTelemetryNamespace
+		}
+		exporter := map[string]interface{}{
+			"namespace":                        namespace,
+			"log_group_name":                   selfTelemetryLogGroup,
+			"log_stream_name":                  "${env:POD_NAME}",
+			"dimension_rollup_option":          "NoDimensionRollup",
+			"resource_to_telemetry_conversion": map[string]interface{}{"enabled": true},
+			"metric_declarations": []interface{}{map[string]interface{}{
+				"dimensions":            [][]string{{"NodeName"}, {"NodeName", "PodName"}},
+				"metric_name_selectors": []string{fmt.Sprintf("^(%s)$", strings.Join(selfTelemetryMetrics, "|"))},
+			}},
+		}
+		setExporterAWSConfig(exporter, instance.Spec.AWS)
+
+		config["receivers"] = map[string]interface{}{
+			selfTelemetryReceiver: map[string]interface{}{
+				"config": map[string]interface{}{"scrape_configs": []interface{}{map[string]interface{}{
+					"job_name":        "cloudwatch-agent",
+					"scrape_interval": "60s",
+					"static_configs":  []interface{}{map[string]interface{}{"targets": []string{fmt.Sprintf("localhost:%d", port)}}},
+				}}},
+			},
+		}
+		config["processors"] = map[string]interface{}{
+			selfTelemetryProcessor: map[string]interface{}{
+				"attributes": []interface{}{
+					map[string]interface{}{"key": "NodeName", "value": "${env:K8S_NODE_NAME}", "action": "upsert"},
+					map[string]interface{}{"key": "PodName", "value": "${env:POD_NAME}", "action": "upsert"},
+				},
+			},
+		}
+		config["exporters"] = map[string]interface{}{selfTelemetryExporter: exporter}
+		config["service"].(map[string]interface{})["pipelines"] = map[string]interface{}{
+			selfTelemetryPipeline: map[string]interface{}{
+				"receivers":  []string{selfTelemetryReceiver},
+				"processors": []string{selfTelemetryProcessor},
+				"exporters":  []string{selfTelemetryExporter},
+			},
+		}
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return adapters.ConfigFromString(string(out))
+}
@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestSelfTelemetry(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{Enabled: true}
+
+	presetParams, err := withSelfTelemetry(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	metrics := conf["service"].(map[interface{}]interface{})["telemetry"].(map[interface{}]interface{})["metrics"].(map[interface{}]interface{})
+	assert.Equal(t, "normal", metrics["level"])
+	assert.Equal(t, "0.0.0.0:8888", metrics["address"])
+	assert.NotContains(t, conf, "exporters")
+
+	port, err := adapters.ConfigToMetricsPort(logr.Discard(), conf)
+	require.NoError(t, err)
+	assert.Equal(t, int32(8888), port)
+}
+
+func TestSelfTelemetryToCloudWatch(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.OtelConfig = `
+service:
+  telemetry:
+    metrics:
+      level: detailed
+`
+	params.OtelCol.Spec.AWS = &v1alpha1.AWSSpec{Region: "eu-west-1"}
+	params.OtelCol.Spec.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{Enabled: true, Port: 9999, Level: "basic", CloudWatch: true}
+
+	presetParams, err := withSelfTelemetry(params)
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(presetParams.OtelCol.Spec.OtelConfig)
+	require.NoError(t, err)
+
+	service := conf["service"].(map[interface{}]interface{})
+	metrics := service["telemetry"].(map[interface{}]interface{})["metrics"].(map[interface{}]interface{})
+	assert.Equal(t, "detailed", metrics["level"], "the settings of the user take precedence")
+	assert.Equal(t, "0.0.0.0:9999", metrics["address"])
+	assert.Contains(t, service["pipelines"], selfTelemetryPipeline)
+
+	exporter := conf["exporters"].(map[interface{}]interface{})[selfTelemetryExporter].(map[interface{}]interface{})
+	assert.Equal(t, selfTelemetryNamespace, exporter["namespace"])
+	assert.Equal(t, "eu-west-1", exporter["region"])
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "localhost:9999")
+	assert.Contains(t, presetParams.OtelCol.Spec.OtelConfig, "${env:K8S_NODE_NAME}")
+	assert.True(t, selfTelemetryCloudWatchEnabled(presetParams.OtelCol))
+}
+
+func TestSelfTelemetryDisabled(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{CloudWatch: true}
+
+	presetParams, err := withSelfTelemetry(params)
+	require.NoError(t, err)
+	assert.Empty(t, presetParams.OtelCol.Spec.OtelConfig)
+	assert.False(t, selfTelemetryCloudWatchEnabled(presetParams.OtelCol))
+}
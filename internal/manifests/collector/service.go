@@ -5,6 +5,7 @@ package collector
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -32,15 +33,20 @@ func HeadlessService(params manifests.Params) (*corev1.Service, error) {
 	h.Name = naming.HeadlessService(params.OtelCol.Name)
 	h.Labels[headlessLabel] = headlessExists
 
-	// copy to avoid modifying params.OtelCol.Annotations
+	// copy to avoid modifying params.OtelCol.Annotations, the service block only applies to the exposed service
 	annotations := map[string]string{
 		"service.beta.openshift.io/serving-cert-secret-name": fmt.Sprintf("%s-tls", h.Name),
 	}
-	for k, v := range h.Annotations {
+	for k, v := range params.OtelCol.Annotations {
 		annotations[k] = v
 	}
 	h.Annotations = annotations
 
+	h.Spec.Type = corev1.ServiceTypeClusterIP
+	h.Spec.LoadBalancerSourceRanges = nil
+	for i := range h.Spec.Ports {
+		h.Spec.Ports[i].NodePort = 0
+	}
 	h.Spec.ClusterIP = "None"
 	return h, nil
 }
@@ -59,6 +65,9 @@ func MonitoringService(params manifests.Params) (*corev1.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if port, ok := selfTelemetryPort(params.OtelCol); ok {
+		metricsPort = port
+	}
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -71,9 +80,11 @@ func MonitoringService(params manifests.Params) (*corev1.Service, error) {
 			Selector:  manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
 			ClusterIP: "",
 			Ports: []corev1.ServicePort{{
-				Name: "monitoring",
+				Name: monitoringPortName,
 				Port: metricsPort,
 			}},
+			IPFamilies:     params.OtelCol.Spec.Service.IPFamilies,
+			IPFamilyPolicy: params.OtelCol.Spec.Service.IPFamilyPolicy,
 		},
 	}, nil
 }
@@ -92,22 +103,63 @@ func Service(params manifests.Params) (*corev1.Service, error) {
 	}
 
 	trafficPolicy := corev1.ServiceInternalTrafficPolicyCluster
-	if params.OtelCol.Spec.Mode == v1alpha1.ModeDaemonSet {
+	if params.OtelCol.Spec.Service.InternalTrafficPolicy != nil {
+		trafficPolicy = *params.OtelCol.Spec.Service.InternalTrafficPolicy
+	} else if params.OtelCol.Spec.Mode == v1alpha1.ModeDaemonSet {
 		trafficPolicy = corev1.ServiceInternalTrafficPolicyLocal
 	}
 
+	serviceType := params.OtelCol.Spec.Service.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+
+	// copy to avoid modifying params.OtelCol.Annotations
+	var annotations map[string]string
+	if len(params.OtelCol.Annotations) > 0 || len(params.OtelCol.Spec.Service.Annotations) > 0 {
+		annotations = map[string]string{}
+		for k, v := range params.OtelCol.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range params.OtelCol.Spec.Service.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	servicePorts := containerPortsToServicePortList(ports)
+	if serviceType != corev1.ServiceTypeClusterIP {
+		// keep the node ports requested in the CR so they survive reconciliation
+		for i := range servicePorts {
+			for _, specPort := range params.OtelCol.Spec.Ports {
+				if specPort.Name == servicePorts[i].Name {
+					servicePorts[i].NodePort = specPort.NodePort
+				}
+			}
+		}
+	}
+
+	var loadBalancerSourceRanges []string
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		loadBalancerSourceRanges = params.OtelCol.Spec.Service.LoadBalancerSourceRanges
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        naming.Service(params.OtelCol.Name),
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			InternalTrafficPolicy: &trafficPolicy,
-			Selector:              manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
-			ClusterIP:             "",
-			Ports:                 containerPortsToServicePortList(ports),
+			Type:                     serviceType,
+			InternalTrafficPolicy:    &trafficPolicy,
+			Selector:                 manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
+			ClusterIP:                "",
+			Ports:                    servicePorts,
+			LoadBalancerSourceRanges: loadBalancerSourceRanges,
+			TrafficDistribution:      params.OtelCol.Spec.Service.TrafficDistribution,
+			IPFamilies:               params.OtelCol.Spec.Service.IPFamilies,
+			IPFamilyPolicy:           params.OtelCol.Spec.Service.IPFamilyPolicy,
 		},
 	}, nil
 }
@@ -121,6 +173,9 @@ func containerPortsToServicePortList(portMap map[string]corev1.ContainerPort) []
 			Protocol: p.Protocol,
 		})
 	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Name < ports[j].Name
+	})
 	return ports
 }
 
@@ -161,6 +161,45 @@ func TestDesiredService(t *testing.T) {
 		assert.Equal(t, expected, *actual)
 	})
 
+	t.Run("should apply the service block", func(t *testing.T) {
+		params := deploymentParams()
+		params.OtelCol.Annotations = map[string]string{"owner": "observability"}
+		params.OtelCol.Spec.Service = v1alpha1.ServiceSpec{
+			Type:                     v1.ServiceTypeLoadBalancer,
+			Annotations:              map[string]string{"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"},
+			LoadBalancerSourceRanges: []string{"10.0.0.0/16"},
+		}
+
+		actual, err := Service(params)
+		assert.NoError(t, err)
+		assert.Equal(t, v1.ServiceTypeLoadBalancer, actual.Spec.Type)
+		assert.Equal(t, []string{"10.0.0.0/16"}, actual.Spec.LoadBalancerSourceRanges)
+		assert.Equal(t, map[string]string{
+			"owner": "observability",
+			"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal",
+		}, actual.Annotations)
+		assert.Len(t, params.OtelCol.Annotations, 1)
+
+		headless, err := HeadlessService(params)
+		assert.NoError(t, err)
+		assert.Equal(t, v1.ServiceTypeClusterIP, headless.Spec.Type)
+		assert.Nil(t, headless.Spec.LoadBalancerSourceRanges)
+		assert.NotContains(t, headless.Annotations, "service.beta.kubernetes.io/aws-load-balancer-scheme")
+	})
+
+	t.Run("should use the configured internal traffic policy and distribution", func(t *testing.T) {
+		cluster := v1.ServiceInternalTrafficPolicyCluster
+		preferClose := v1.ServiceTrafficDistributionPreferClose
+		p := paramsWithMode(v1alpha1.ModeDaemonSet)
+		p.OtelCol.Spec.Service.InternalTrafficPolicy = &cluster
+		p.OtelCol.Spec.Service.TrafficDistribution = &preferClose
+
+		actual, err := Service(p)
+		assert.NoError(t, err)
+		assert.Equal(t, v1.ServiceInternalTrafficPolicyCluster, *actual.Spec.InternalTrafficPolicy)
+		assert.Equal(t, v1.ServiceTrafficDistributionPreferClose, *actual.Spec.TrafficDistribution)
+	})
+
 	t.Run("should return nil unable to parse config", func(t *testing.T) {
 		params := manifests.Params{
 			Config: config.Config{},
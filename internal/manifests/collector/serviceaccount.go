@@ -4,6 +4,8 @@
 package collector
 
 import (
+	"maps"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -30,12 +32,21 @@ func ServiceAccount(params manifests.Params) *corev1.ServiceAccount {
 	name := naming.ServiceAccount(params.OtelCol.Name)
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
+	annotations := params.OtelCol.Annotations
+	if len(params.OtelCol.Spec.ServiceAccountAnnotations) > 0 {
+		annotations = maps.Clone(params.OtelCol.Annotations)
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		maps.Copy(annotations, params.OtelCol.Spec.ServiceAccountAnnotations)
+	}
+
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
+			Annotations: annotations,
 		},
 	}
 }
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 )
 
@@ -45,3 +46,28 @@ func TestServiceAccountOverride(t *testing.T) {
 	// verify
 	assert.Equal(t, "my-special-sa", sa)
 }
+
+func TestServiceAccountAnnotations(t *testing.T) {
+	// prepare
+	params := manifests.Params{
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-instance",
+				Annotations: map[string]string{"team": "observability"},
+			},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/cloudwatch-agent"},
+			},
+		},
+	}
+
+	// test
+	sa := ServiceAccount(params)
+
+	// verify
+	assert.Equal(t, map[string]string{
+		"team":                       "observability",
+		"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/cloudwatch-agent",
+	}, sa.Annotations)
+	assert.Equal(t, map[string]string{"team": "observability"}, params.OtelCol.Annotations)
+}
@@ -39,23 +39,28 @@ func StatefulSet(params manifests.Params) *appsv1.StatefulSet {
 					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName:        ServiceAccountName(params.OtelCol),
-					InitContainers:            params.OtelCol.Spec.InitContainers,
-					Containers:                append(params.OtelCol.Spec.AdditionalContainers, Container(params.Config, params.Log, params.OtelCol, true)),
-					Volumes:                   Volumes(params.Config, params.OtelCol),
-					DNSPolicy:                 getDNSPolicy(params.OtelCol),
-					HostNetwork:               params.OtelCol.Spec.HostNetwork,
-					Tolerations:               params.OtelCol.Spec.Tolerations,
-					NodeSelector:              params.OtelCol.Spec.NodeSelector,
-					SecurityContext:           params.OtelCol.Spec.PodSecurityContext,
-					PriorityClassName:         params.OtelCol.Spec.PriorityClassName,
-					Affinity:                  params.OtelCol.Spec.Affinity,
-					TopologySpreadConstraints: params.OtelCol.Spec.TopologySpreadConstraints,
+					ServiceAccountName:            ServiceAccountName(params.OtelCol),
+					InitContainers:                params.OtelCol.Spec.InitContainers,
+					Containers:                    podContainers(params.Config, params.Log, params.OtelCol),
+					ShareProcessNamespace:         shareProcessNamespace(params.OtelCol),
+					Volumes:                       Volumes(params.Config, params.OtelCol),
+					DNSPolicy:                     getDNSPolicy(params.OtelCol),
+					DNSConfig:                     params.OtelCol.Spec.DNSConfig,
+					HostNetwork:                   params.OtelCol.Spec.HostNetwork,
+					Tolerations:                   params.OtelCol.Spec.Tolerations,
+					NodeSelector:                  params.OtelCol.Spec.NodeSelector,
+					SecurityContext:               podSecurityContext(params.OtelCol),
+					PriorityClassName:             params.OtelCol.Spec.PriorityClassName,
+					Affinity:                      params.OtelCol.Spec.Affinity,
+					TopologySpreadConstraints:     TopologySpreadConstraints(params.OtelCol),
+					TerminationGracePeriodSeconds: params.OtelCol.Spec.TerminationGracePeriodSeconds,
 				},
 			},
-			Replicas:             params.OtelCol.Spec.Replicas,
-			PodManagementPolicy:  "Parallel",
-			VolumeClaimTemplates: VolumeClaimTemplates(params.OtelCol),
+			Replicas:                             params.OtelCol.Spec.Replicas,
+			PodManagementPolicy:                  "Parallel",
+			MinReadySeconds:                      params.OtelCol.Spec.MinReadySeconds,
+			VolumeClaimTemplates:                 VolumeClaimTemplates(params.OtelCol),
+			PersistentVolumeClaimRetentionPolicy: PersistentVolumeClaimRetentionPolicy(params.OtelCol),
 		},
 	}
 }
@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+const zoneTopologyKey = "topology.kubernetes.io/zone"
+
+// TopologySpreadConstraints returns the topology spread constraints of the collector pods. Constraints without a
+// label selector select the pods of the instance, and a zone constraint is added when spreadAcrossZones is set.
+func TopologySpreadConstraints(otelcol v1alpha1.AmazonCloudWatchAgent) []corev1.TopologySpreadConstraint {
+	selector := &metav1.LabelSelector{
+		MatchLabels: manifestutils.SelectorLabels(otelcol.ObjectMeta, ComponentAmazonCloudWatchAgent),
+	}
+	var constraints []corev1.TopologySpreadConstraint
+	hasZoneConstraint := false
+	for _, constraint := range otelcol.Spec.TopologySpreadConstraints {
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = selector
+		}
+		if constraint.TopologyKey == zoneTopologyKey {
+			hasZoneConstraint = true
+		}
+		constraints = append(constraints, constraint)
+	}
+	if otelcol.Spec.SpreadAcrossZones && !hasZoneConstraint {
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       zoneTopologyKey,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     selector,
+		})
+	}
+	return constraints
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+func TestTopologySpreadConstraints(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "amazon-cloudwatch",
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode: v1alpha1.ModeDeployment,
+		},
+	}
+	assert.Empty(t, TopologySpreadConstraints(otelcol))
+
+	otelcol.Spec.SpreadAcrossZones = true
+	otelcol.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "kubernetes.io/hostname",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}}
+	constraints := TopologySpreadConstraints(otelcol)
+	assert.Len(t, constraints, 2)
+	for _, constraint := range constraints {
+		assert.Equal(t, "amazon-cloudwatch.gateway", constraint.LabelSelector.MatchLabels["app.kubernetes.io/instance"])
+	}
+	assert.Equal(t, "topology.kubernetes.io/zone", constraints[1].TopologyKey)
+	assert.Equal(t, corev1.ScheduleAnyway, constraints[1].WhenUnsatisfiable)
+	// the user defined constraint is not modified
+	assert.Nil(t, otelcol.Spec.TopologySpreadConstraints[0].LabelSelector)
+
+	otelcol.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           2,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}}
+	constraints = TopologySpreadConstraints(otelcol)
+	assert.Len(t, constraints, 1)
+	assert.Equal(t, int32(2), constraints[0].MaxSkew)
+}
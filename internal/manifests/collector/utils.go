@@ -10,6 +10,9 @@ import (
 )
 
 func getDNSPolicy(otelcol v1alpha1.AmazonCloudWatchAgent) corev1.DNSPolicy {
+	if otelcol.Spec.DNSPolicy != "" {
+		return otelcol.Spec.DNSPolicy
+	}
 	dnsPolicy := corev1.DNSClusterFirst
 	if otelcol.Spec.HostNetwork {
 		dnsPolicy = corev1.DNSClusterFirstWithHostNet
@@ -14,6 +14,11 @@ import (
 
 // Volumes builds the volumes for the given instance, including the config map volume.
 func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	return volumes(cfg, otelcol, naming.ConfigMap(otelcol.Name))
+}
+
+// volumes builds the volumes of the instance, mounting the agent configuration from the given ConfigMap.
+func volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent, configMapName string) []corev1.Volume {
 	items := []corev1.KeyToPath{
 		{
 			Key:  cfg.CollectorConfigMapEntry(),
@@ -28,15 +33,36 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 		})
 	}
 
-	volumes := []corev1.Volume{{
+	configVolume := corev1.Volume{
 		Name: naming.ConfigMapVolume(),
 		VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
-				LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigMap(otelcol.Name)},
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
 				Items:                items,
 			},
 		},
-	}}
+	}
+
+	// the secret has to land in the same directory as the rendered config for the agent to merge them
+	if otelcol.Spec.ConfigSecret != nil {
+		configVolume.VolumeSource = corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+							Items:                items,
+						},
+					},
+					{
+						Secret: otelcol.Spec.ConfigSecret.DeepCopy(),
+					},
+				},
+			},
+		}
+	}
+
+	volumes := []corev1.Volume{configVolume}
 
 	if !otelcol.Spec.Prometheus.IsEmpty() {
 		volumes = append(volumes, corev1.Volume{
@@ -55,6 +81,19 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 		})
 	}
 
+	if volume, ok := containerRuntimeVolume(otelcol); ok {
+		volumes = append(volumes, volume)
+	}
+
+	for _, hostMount := range otelcol.Spec.HostMounts {
+		volumes = append(volumes, corev1.Volume{
+			Name: hostMount.Name,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: hostMount.Path},
+			},
+		})
+	}
+
 	if len(otelcol.Spec.Volumes) > 0 {
 		volumes = append(volumes, otelcol.Spec.Volumes...)
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -119,3 +120,61 @@ func TestVolumeNoPrometheus(t *testing.T) {
 	// check that it's not the prometheus-config volume, with the config map
 	assert.NotEqual(t, naming.PrometheusConfigMapVolume(), volumes[0].Name)
 }
+
+func TestVolumeConfigSecret(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ConfigSecret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "agent-credentials"},
+				Items:                []corev1.KeyToPath{{Key: "outputs", Path: "outputs.json"}},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	volumes := Volumes(cfg, otelcol)
+
+	// verify
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, naming.ConfigMapVolume(), volumes[0].Name)
+	assert.Nil(t, volumes[0].ConfigMap)
+	projected := volumes[0].Projected
+	assert.NotNil(t, projected)
+	assert.Len(t, projected.Sources, 2)
+	assert.Equal(t, naming.ConfigMap("my-instance"), projected.Sources[0].ConfigMap.Name)
+	assert.Equal(t, cfg.CollectorConfigMapEntry(), projected.Sources[0].ConfigMap.Items[0].Path)
+	assert.Equal(t, otelcol.Spec.ConfigSecret, projected.Sources[1].Secret)
+	assert.NotSame(t, otelcol.Spec.ConfigSecret, projected.Sources[1].Secret)
+}
+
+func TestVolumeHostMounts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			HostMounts: []v1alpha1.HostMount{
+				{Name: "proc", Path: "/proc", MountPath: "/rootfs/proc"},
+				{Name: "app-logs", Path: "/var/log/app"},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	volumes := Volumes(cfg, otelcol)
+
+	// verify
+	assert.Len(t, volumes, 3)
+	assert.Equal(t, corev1.Volume{
+		Name:         "proc",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/proc"}},
+	}, volumes[1])
+	assert.Equal(t, "/var/log/app", volumes[2].HostPath.Path)
+}
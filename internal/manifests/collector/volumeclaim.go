@@ -5,6 +5,7 @@
 package collector
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -20,3 +21,12 @@ func VolumeClaimTemplates(otelcol v1alpha1.AmazonCloudWatchAgent) []corev1.Persi
 	// Add all user specified claims.
 	return otelcol.Spec.VolumeClaimTemplates
 }
+
+// PersistentVolumeClaimRetentionPolicy returns the retention policy of the claims created from the
+// volumeClaimTemplates of the given instance.
+func PersistentVolumeClaimRetentionPolicy(otelcol v1alpha1.AmazonCloudWatchAgent) *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	if otelcol.Spec.Mode != "statefulset" {
+		return nil
+	}
+	return otelcol.Spec.PersistentVolumeClaimRetentionPolicy
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -75,3 +76,20 @@ func TestVolumeClaimChecksForStatefulset(t *testing.T) {
 	// verify that volume claim replaces
 	assert.Len(t, volumeClaims, 0)
 }
+
+func TestPersistentVolumeClaimRetentionPolicy(t *testing.T) {
+	policy := &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:                                 "statefulset",
+			PersistentVolumeClaimRetentionPolicy: policy,
+		},
+	}
+	assert.Equal(t, policy, PersistentVolumeClaimRetentionPolicy(otelcol))
+
+	otelcol.Spec.Mode = "deployment"
+	assert.Nil(t, PersistentVolumeClaimRetentionPolicy(otelcol))
+}
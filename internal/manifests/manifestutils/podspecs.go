@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodSpecs returns the pod specs of the templates of the Deployments, DaemonSets and StatefulSets of the objects.
+func PodSpecs(objects []client.Object) []*corev1.PodSpec {
+	var podSpecs []*corev1.PodSpec
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			podSpecs = append(podSpecs, &o.Spec.Template.Spec)
+		case *appsv1.DaemonSet:
+			podSpecs = append(podSpecs, &o.Spec.Template.Spec)
+		case *appsv1.StatefulSet:
+			podSpecs = append(podSpecs, &o.Spec.Template.Spec)
+		}
+	}
+	return podSpecs
+}
+
+// PodImages returns the distinct images of the containers and init containers of the pod spec.
+func PodImages(podSpec corev1.PodSpec) []string {
+	var images []string
+	for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+		if !slices.Contains(images, container.Image) {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
+
+// AddedImages returns the distinct images of the containers and init containers of the mutated pod spec which are not
+// in the original one, matching the containers by name.
+func AddedImages(original, mutated corev1.PodSpec) []string {
+	var names []string
+	for _, container := range slices.Concat(original.InitContainers, original.Containers) {
+		names = append(names, container.Name)
+	}
+	var images []string
+	for _, container := range slices.Concat(mutated.InitContainers, mutated.Containers) {
+		if !slices.Contains(names, container.Name) && !slices.Contains(images, container.Image) {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
+
+// PinImages replaces the images of the containers and init containers of the pod specs by their references by digest.
+func PinImages(podSpecs []*corev1.PodSpec, pinned map[string]string) {
+	for _, podSpec := range podSpecs {
+		for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+			for i := range containers {
+				if ref, ok := pinned[containers[i].Image]; ok {
+					containers[i].Image = ref
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPodImages(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: "busybox:1.0"}},
+		Containers:     []corev1.Container{{Name: "agent", Image: "cloudwatch-agent:1.0"}, {Name: "init-check", Image: "busybox:1.0"}},
+	}
+
+	assert.Equal(t, []string{"busybox:1.0", "cloudwatch-agent:1.0"}, PodImages(podSpec))
+}
+
+func TestPodSpecs(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{}
+	deployment := &appsv1.Deployment{}
+
+	podSpecs := PodSpecs([]client.Object{daemonSet, &corev1.ServiceAccount{}, deployment})
+
+	// the pod specs are the ones of the objects, which are pinned in place
+	require.Len(t, podSpecs, 2)
+	assert.Same(t, &daemonSet.Spec.Template.Spec, podSpecs[0])
+	assert.Same(t, &deployment.Spec.Template.Spec, podSpecs[1])
+}
+
+func TestAddedImages(t *testing.T) {
+	original := corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1.0"}}}
+	mutated := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java", Image: "adot-java:1.0"}},
+		Containers:     []corev1.Container{{Name: "app", Image: "app:1.0"}, {Name: "otc-container", Image: "cloudwatch-agent:1.0"}},
+	}
+
+	assert.Equal(t, []string{"adot-java:1.0", "cloudwatch-agent:1.0"}, AddedImages(original, mutated))
+	assert.Empty(t, AddedImages(original, original))
+}
+
+func TestPinImages(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{}
+	daemonSet.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: "busybox:1.0"}},
+		Containers:     []corev1.Container{{Name: "agent", Image: "cloudwatch-agent:1.0"}, {Name: "app", Image: "app:1.0"}},
+	}
+
+	PinImages(PodSpecs([]client.Object{daemonSet}), map[string]string{
+		"busybox:1.0":          "busybox:1.0@sha256:b",
+		"cloudwatch-agent:1.0": "cloudwatch-agent:1.0@sha256:c",
+	})
+
+	podSpec := daemonSet.Spec.Template.Spec
+	assert.Equal(t, "busybox:1.0@sha256:b", podSpec.InitContainers[0].Image)
+	assert.Equal(t, "cloudwatch-agent:1.0@sha256:c", podSpec.Containers[0].Image)
+	assert.Equal(t, "app:1.0", podSpec.Containers[1].Image)
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImagePullSecrets returns the image pull secrets with the named secrets appended, skipping the ones already
+// referenced. The given slice is left untouched.
+func ImagePullSecrets(existing []corev1.LocalObjectReference, names []string) []corev1.LocalObjectReference {
+	existing = slices.Clip(existing)
+	for _, name := range names {
+		if name == "" || HasImagePullSecret(existing, name) {
+			continue
+		}
+		existing = append(existing, corev1.LocalObjectReference{Name: name})
+	}
+	return existing
+}
+
+// HasImagePullSecret returns whether the image pull secrets reference the named secret.
+func HasImagePullSecret(secrets []corev1.LocalObjectReference, name string) bool {
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithImagePullSecrets attaches the named image pull secrets to the pod templates and the service accounts of the
+// objects, so that the operator's workloads can pull their images from registries requiring authentication.
+func WithImagePullSecrets(objects []client.Object, names []string) []client.Object {
+	if len(names) == 0 {
+		return objects
+	}
+	for _, podSpec := range PodSpecs(objects) {
+		podSpec.ImagePullSecrets = ImagePullSecrets(podSpec.ImagePullSecrets, names)
+	}
+	for _, obj := range objects {
+		if sa, ok := obj.(*corev1.ServiceAccount); ok {
+			sa.ImagePullSecrets = ImagePullSecrets(sa.ImagePullSecrets, names)
+		}
+	}
+	return objects
+}
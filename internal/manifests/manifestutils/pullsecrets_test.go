@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestImagePullSecrets(t *testing.T) {
+	existing := make([]corev1.LocalObjectReference, 1, 2)
+	existing[0] = corev1.LocalObjectReference{Name: "team-registry"}
+
+	secrets := ImagePullSecrets(existing, []string{"mirror", "team-registry", ""})
+
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "team-registry"}, {Name: "mirror"}}, secrets)
+	// the spare capacity of the given secrets is left untouched
+	assert.Empty(t, existing[:2][1].Name)
+}
+
+func TestWithImagePullSecrets(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{}
+	deployment := &appsv1.Deployment{}
+	statefulSet := &appsv1.StatefulSet{}
+	serviceAccount := &corev1.ServiceAccount{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mirror"}}}
+	configMap := &corev1.ConfigMap{}
+
+	objects := WithImagePullSecrets([]client.Object{daemonSet, deployment, statefulSet, serviceAccount, configMap}, []string{"mirror"})
+
+	assert.Len(t, objects, 5)
+	want := []corev1.LocalObjectReference{{Name: "mirror"}}
+	assert.Equal(t, want, daemonSet.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, want, deployment.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, want, statefulSet.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, want, serviceAccount.ImagePullSecrets)
+}
+
+func TestWithoutImagePullSecrets(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{}
+
+	WithImagePullSecrets([]client.Object{daemonSet}, nil)
+
+	assert.Nil(t, daemonSet.Spec.Template.Spec.ImagePullSecrets)
+}
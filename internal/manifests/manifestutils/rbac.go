@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// clusterScopedResources are the cluster-scoped resources, and their subresources, that the agents and the target
+// allocators read.
+var clusterScopedResources = map[string]bool{
+	"namespaces":        true,
+	"nodes":             true,
+	"nodes/metrics":     true,
+	"nodes/proxy":       true,
+	"nodes/stats":       true,
+	"persistentvolumes": true,
+	"kcm/metrics":       true,
+	"ksh/metrics":       true,
+}
+
+// NamespacedRules returns the rules on the namespaced resources, which a role can grant, out of the given rules.
+func NamespacedRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	var namespaced []rbacv1.PolicyRule
+	for _, rule := range rules {
+		if len(rule.NonResourceURLs) > 0 {
+			continue
+		}
+		var resources []string
+		for _, resource := range rule.Resources {
+			if !clusterScopedResources[resource] {
+				resources = append(resources, resource)
+			}
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		rule.Resources = resources
+		namespaced = append(namespaced, rule)
+	}
+	return namespaced
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 
 	"dario.cat/mergo"
 	routev1 "github.com/openshift/api/route/v1"
@@ -18,6 +19,7 @@ import (
 	policyV1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -44,6 +46,7 @@ var (
 // - HorizontalPodAutoscaler
 // - Route
 // - Secret
+// - Unstructured, for the kinds of other operators such as the EKS Pod Identity associations
 // In order for the operator to reconcile other types, they must be added here.
 // The function returned takes no arguments but instead uses the existing and desired inputs here. Existing is expected
 // to be set by the controller-runtime package through a client get call.
@@ -155,6 +158,11 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantPr := desired.(*corev1.Secret)
 			mutateSecret(pr, wantPr)
 
+		case *unstructured.Unstructured:
+			u := existing.(*unstructured.Unstructured)
+			wantU := desired.(*unstructured.Unstructured)
+			mutateUnstructured(u, wantU)
+
 		default:
 			t := reflect.TypeOf(existing).String()
 			return fmt.Errorf("missing mutate implementation for resource type: %s", t)
@@ -167,6 +175,10 @@ func mergeWithOverride(dst, src interface{}) error {
 	return mergo.Merge(dst, src, mergo.WithOverride)
 }
 
+func mutateUnstructured(existing, desired *unstructured.Unstructured) {
+	existing.Object["spec"] = desired.Object["spec"]
+}
+
 func mutateSecret(existing, desired *corev1.Secret) {
 	existing.Labels = desired.Labels
 	existing.Annotations = desired.Annotations
@@ -181,6 +193,13 @@ func mutateConfigMap(existing, desired *corev1.ConfigMap) {
 func mutateServiceAccount(existing, desired *corev1.ServiceAccount) {
 	existing.Annotations = desired.Annotations
 	existing.Labels = desired.Labels
+	// keep the image pull secrets other controllers attach to the service account, such as the dockercfg secrets of
+	// OpenShift
+	for _, secret := range desired.ImagePullSecrets {
+		if !slices.Contains(existing.ImagePullSecrets, secret) {
+			existing.ImagePullSecrets = append(existing.ImagePullSecrets, secret)
+		}
+	}
 }
 
 func mutateClusterRole(existing, desired *rbacv1.ClusterRole) {
@@ -247,6 +266,21 @@ func mutatePodMonitor(existing, desired *monitoringv1.PodMonitor) {
 
 func mutateService(existing, desired *corev1.Service) error {
 	existing.Spec.Ports = desired.Spec.Ports
+	if desired.Spec.Type != "" {
+		existing.Spec.Type = desired.Spec.Type
+	}
+	existing.Spec.LoadBalancerSourceRanges = desired.Spec.LoadBalancerSourceRanges
+	if desired.Spec.InternalTrafficPolicy != nil {
+		existing.Spec.InternalTrafficPolicy = desired.Spec.InternalTrafficPolicy
+	}
+	existing.Spec.TrafficDistribution = desired.Spec.TrafficDistribution
+	// the API server fills in the families when they are not set, only override explicit choices
+	if desired.Spec.IPFamilyPolicy != nil {
+		existing.Spec.IPFamilyPolicy = desired.Spec.IPFamilyPolicy
+	}
+	if len(desired.Spec.IPFamilies) > 0 {
+		existing.Spec.IPFamilies = desired.Spec.IPFamilies
+	}
 	if err := mergeWithOverride(&existing.Spec.Selector, desired.Spec.Selector); err != nil {
 		return err
 	}
@@ -266,6 +300,12 @@ func mutateDaemonset(existing, desired *appsv1.DaemonSet) error {
 	if err := mergeWithOverride(&existing.Spec, desired.Spec); err != nil {
 		return err
 	}
+	// merging keeps the existing rollingUpdate when switching to OnDelete, which the API server rejects
+	if desired.Spec.UpdateStrategy.Type != "" {
+		existing.Spec.UpdateStrategy = desired.Spec.UpdateStrategy
+	}
+	// merging would keep the node group exclusions of removed node groups, leaving their nodes without an agent
+	existing.Spec.Template.Spec.Affinity = desired.Spec.Template.Spec.Affinity
 	return nil
 }
 
@@ -279,11 +319,16 @@ func mutateDeployment(existing, desired *appsv1.Deployment) error {
 		existing.Spec.Selector = desired.Spec.Selector
 	}
 	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.MinReadySeconds = desired.Spec.MinReadySeconds
+	if desired.Spec.ProgressDeadlineSeconds != nil {
+		existing.Spec.ProgressDeadlineSeconds = desired.Spec.ProgressDeadlineSeconds
+	}
 	if err := mergeWithOverride(&existing.Spec.Template, desired.Spec.Template); err != nil {
 		return err
 	}
-	if err := mergeWithOverride(&existing.Spec.Strategy, desired.Spec.Strategy); err != nil {
-		return err
+	// merging keeps the existing rollingUpdate when switching to Recreate, which the API server rejects
+	if desired.Spec.Strategy.Type != "" {
+		existing.Spec.Strategy = desired.Spec.Strategy
 	}
 	return nil
 }
@@ -298,7 +343,9 @@ func mutateStatefulSet(existing, desired *appsv1.StatefulSet) error {
 		existing.Spec.Selector = desired.Spec.Selector
 	}
 	existing.Spec.PodManagementPolicy = desired.Spec.PodManagementPolicy
+	existing.Spec.MinReadySeconds = desired.Spec.MinReadySeconds
 	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.PersistentVolumeClaimRetentionPolicy = desired.Spec.PersistentVolumeClaimRetentionPolicy
 
 	for i := range existing.Spec.VolumeClaimTemplates {
 		existing.Spec.VolumeClaimTemplates[i].TypeMeta = desired.Spec.VolumeClaimTemplates[i].TypeMeta
@@ -23,4 +23,12 @@ type Params struct {
 	DcgmExp   v1alpha1.DcgmExporter
 	NeuronExp v1alpha1.NeuronMonitor
 	Config    config.Config
+	// EFANodes are the names of the nodes exposing EFA devices, resolved when the instance collects the EFA metrics.
+	EFANodes []string
+	// DcgmExporter is the DcgmExporter resource deploying the DCGM exporter already, if any, whose Service the instance
+	// collecting the GPU metrics scrapes instead of deploying a second exporter.
+	DcgmExporter *v1alpha1.DcgmExporter
+	// NeuronMonitorDeployed is whether a NeuronMonitor resource deploys the Neuron monitor already, in which case the
+	// instance collecting the Neuron metrics does not deploy a second one.
+	NeuronMonitorDeployed bool
 }
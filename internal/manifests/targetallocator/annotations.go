@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 
+	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -23,21 +24,38 @@ func Annotations(instance v1alpha1.AmazonCloudWatchAgent, configMap *v1.ConfigMa
 	}
 
 	if configMap != nil {
-		cmHash := getConfigMapSHA(configMap)
+		cmHash := getConfigMapSHA(configMap, prometheusConfigReloadEnabled(instance))
 		if cmHash != "" {
-			annotations[configMapHashAnnotationKey] = getConfigMapSHA(configMap)
+			annotations[configMapHashAnnotationKey] = cmHash
 		}
 	}
 
 	return annotations
 }
 
-// getConfigMapSHA returns the hash of the content of the TA ConfigMap.
-func getConfigMapSHA(configMap *v1.ConfigMap) string {
+// getConfigMapSHA returns the hash of the content of the TA ConfigMap. The target allocator reloads the scrape
+// configurations of its configuration file when the file changes, so they are left out when it is asked to.
+func getConfigMapSHA(configMap *v1.ConfigMap, withoutScrapeConfigs bool) string {
 	configString, ok := configMap.Data[targetAllocatorFilename]
 	if !ok {
 		return ""
 	}
+	if withoutScrapeConfigs {
+		taConfig := map[interface{}]interface{}{}
+		if err := yaml.Unmarshal([]byte(configString), &taConfig); err != nil {
+			return ""
+		}
+		delete(taConfig, "config")
+		out, err := yaml.Marshal(taConfig)
+		if err != nil {
+			return ""
+		}
+		configString = string(out)
+	}
 	h := sha256.Sum256([]byte(configString))
 	return fmt.Sprintf("%x", h)
 }
+
+func prometheusConfigReloadEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.PrometheusConfigReload != nil && instance.Spec.PrometheusConfigReload.Enabled
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
@@ -44,6 +45,34 @@ func TestConfigMapHash(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%x", expectedHash), cmHash)
 }
 
+func TestConfigMapHashWithPrometheusConfigReload(t *testing.T) {
+	instance := collectorInstance()
+	instance.Spec.PrometheusConfigReload = &v1alpha1.PrometheusConfigReloadSpec{Enabled: true}
+	params := manifests.Params{
+		OtelCol: instance,
+		Config:  config.New(),
+		Log:     logr.Discard(),
+	}
+	configMap, err := ConfigMap(params)
+	require.NoError(t, err)
+	hash := Annotations(instance, configMap)[configMapHashAnnotationKey]
+	require.NotEmpty(t, hash)
+
+	// the scrape configurations are reloaded by the target allocator
+	instance.Spec.Prometheus.Config.Object["scrape_configs"] = []interface{}{map[string]interface{}{"job_name": "other"}}
+	params.OtelCol = instance
+	configMap, err = ConfigMap(params)
+	require.NoError(t, err)
+	assert.Equal(t, hash, Annotations(instance, configMap)[configMapHashAnnotationKey])
+
+	// the allocation strategy is not
+	instance.Spec.TargetAllocator.AllocationStrategy = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted
+	params.OtelCol = instance
+	configMap, err = ConfigMap(params)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, Annotations(instance, configMap)[configMapHashAnnotationKey])
+}
+
 func TestInvalidConfigNoHash(t *testing.T) {
 	instance := collectorInstance()
 	instance.Spec.Config = ""
@@ -52,6 +52,9 @@ func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 	}
 
 	taConfig["allocation_strategy"] = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing
+	if params.OtelCol.Spec.TargetAllocator.AllocationStrategy != "" {
+		taConfig["allocation_strategy"] = params.OtelCol.Spec.TargetAllocator.AllocationStrategy
+	}
 
 	if len(params.OtelCol.Spec.TargetAllocator.FilterStrategy) > 0 {
 		taConfig["filter_strategy"] = params.OtelCol.Spec.TargetAllocator.FilterStrategy
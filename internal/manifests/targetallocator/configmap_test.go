@@ -4,6 +4,7 @@
 package targetallocator
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
@@ -145,5 +147,17 @@ prometheus_cr:
 		assert.Equal(t, expectedData, actual.Data)
 
 	})
+	t.Run("should return expected target allocator config map with the allocation strategy set", func(t *testing.T) {
+		collector := collectorInstance()
+		collector.Spec.TargetAllocator.AllocationStrategy = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  config.New(),
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(actual.Data["targetallocator.yaml"], "allocation_strategy: least-weighted\n"))
+	})
 
 }
@@ -71,6 +71,9 @@ func Container(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) corev1
 	if otelcol.Spec.TargetAllocator.PrometheusCR.Enabled {
 		args = append(args, "--enable-prometheus-cr-watcher")
 	}
+	if prometheusConfigReloadEnabled(otelcol) {
+		args = append(args, "--reload-config")
+	}
 
 	return corev1.Container{
 		Name:         naming.TAContainer(),
@@ -263,3 +263,12 @@ func TestContainerDoesNotOverrideEnvVars(t *testing.T) {
 	// verify
 	assert.Equal(t, expected, c)
 }
+
+func TestContainerReloadsConfig(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{}
+	cfg := config.New()
+	assert.Empty(t, Container(cfg, otelcol).Args)
+
+	otelcol.Spec.PrometheusConfigReload = &v1alpha1.PrometheusConfigReloadSpec{Enabled: true}
+	assert.Equal(t, []string{"--reload-config"}, Container(cfg, otelcol).Args)
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// The operator reads the secrets and config maps referenced by the monitors for the target allocators it grants.
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+var readVerbs = []string{"get", "list", "watch"}
+
+// discoveryRules let the target allocator discover the collectors and the targets of the Kubernetes service
+// discovery configurations.
+var discoveryRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{""}, Resources: []string{"endpoints", "namespaces", "nodes", "pods", "services"}, Verbs: readVerbs},
+	{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: readVerbs},
+}
+
+// prometheusCRRules let the target allocator watch the ServiceMonitors and PodMonitors, and read the credentials
+// and certificates they reference.
+var prometheusCRRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"monitoring.coreos.com"}, Resources: []string{"podmonitors", "servicemonitors"}, Verbs: readVerbs},
+}
+
+// rules returns the permissions of the target allocator of the instance, including the ones on the Prometheus
+// Operator custom resources when they are enabled.
+func rules(params manifests.Params) []rbacv1.PolicyRule {
+	rules := append([]rbacv1.PolicyRule{}, discoveryRules...)
+	if params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled {
+		rules = append(rules, prometheusCRRules...)
+	}
+	return rules
+}
+
+// ClusterRole returns the cluster role granting the target allocator of the instance the permissions to discover
+// its targets, or nil when the operator is namespace-scoped.
+func ClusterRole(params manifests.Params) *rbacv1.ClusterRole {
+	if params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.TAClusterRole(params.OtelCol.Name, params.OtelCol.Namespace)
+
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: Labels(params.OtelCol, name),
+		},
+		Rules: rules(params),
+	}
+}
+
+// ClusterRoleBinding returns the binding of the cluster role of the target allocator to its service account, or nil
+// when the operator is namespace-scoped.
+func ClusterRoleBinding(params manifests.Params) *rbacv1.ClusterRoleBinding {
+	if params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.TAClusterRoleBinding(params.OtelCol.Name, params.OtelCol.Namespace)
+
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: Labels(params.OtelCol, name),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     naming.TAClusterRole(params.OtelCol.Name, params.OtelCol.Namespace),
+		},
+	}
+}
+
+// Role returns the role granting the target allocator of the instance the permissions to discover its targets in
+// the namespace of the instance, when the operator is namespace-scoped. It returns nil otherwise.
+func Role(params manifests.Params) *rbacv1.Role {
+	if !params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.TARole(params.OtelCol.Name)
+
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    Labels(params.OtelCol, name),
+		},
+		Rules: manifestutils.NamespacedRules(rules(params)),
+	}
+}
+
+// RoleBinding returns the binding of the role of the target allocator to its service account, or nil when the
+// operator is not namespace-scoped.
+func RoleBinding(params manifests.Params) *rbacv1.RoleBinding {
+	if !params.Config.NamespaceScoped() {
+		return nil
+	}
+	name := naming.TARoleBinding(params.OtelCol.Name)
+
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    Labels(params.OtelCol, name),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     naming.TARole(params.OtelCol.Name),
+		},
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestClusterRole(t *testing.T) {
+	params := manifests.Params{
+		OtelCol: collectorInstance(),
+		Config:  config.New(),
+	}
+
+	cr := ClusterRole(params)
+	assert.Equal(t, "my-instance-default-target-allocator-cluster-role", cr.Name)
+	assert.Equal(t, "amazon-cloudwatch-agent-target-allocator", cr.Labels["app.kubernetes.io/component"])
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"endpoints", "namespaces", "nodes", "pods", "services"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: []string{"get", "list", "watch"}},
+	}, cr.Rules)
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled = true
+	cr = ClusterRole(params)
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{"monitoring.coreos.com"}, Resources: []string{"podmonitors", "servicemonitors"}, Verbs: []string{"get", "list", "watch"}})
+	assert.Contains(t, cr.Rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets"}, Verbs: []string{"get"}})
+}
+
+func TestClusterRoleBinding(t *testing.T) {
+	params := manifests.Params{
+		OtelCol: collectorInstance(),
+		Config:  config.New(),
+	}
+
+	crb := ClusterRoleBinding(params)
+	assert.Equal(t, "my-instance-default-target-allocator-cluster-role-binding", crb.Name)
+	assert.Equal(t, ClusterRole(params).Name, crb.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "target-allocator-service-acct", Namespace: "default"}}, crb.Subjects)
+
+	params.OtelCol.Spec.TargetAllocator.ServiceAccount = "my-special-sa"
+	assert.Equal(t, "my-special-sa", ClusterRoleBinding(params).Subjects[0].Name)
+}
+
+func TestRoleNamespaceScoped(t *testing.T) {
+	params := manifests.Params{
+		OtelCol: collectorInstance(),
+		Config:  config.New(),
+	}
+	assert.Nil(t, Role(params))
+	assert.Nil(t, RoleBinding(params))
+
+	params.Config = config.New(config.WithWatchNamespaces([]string{"default"}))
+	assert.Nil(t, ClusterRole(params))
+	assert.Nil(t, ClusterRoleBinding(params))
+
+	role := Role(params)
+	assert.Equal(t, "my-instance-target-allocator-role", role.Name)
+	assert.Equal(t, "default", role.Namespace)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"endpoints", "pods", "services"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: []string{"get", "list", "watch"}},
+	}, role.Rules)
+
+	rb := RoleBinding(params)
+	assert.Equal(t, "my-instance-target-allocator-role-binding", rb.Name)
+	assert.Equal(t, role.Name, rb.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "target-allocator-service-acct", Namespace: "default"}}, rb.Subjects)
+}
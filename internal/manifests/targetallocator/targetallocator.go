@@ -20,6 +20,10 @@ func Build(params manifests.Params) ([]client.Object, error) {
 		manifests.Factory(Deployment),
 		manifests.FactoryWithoutError(ServiceAccount),
 		manifests.FactoryWithoutError(Service),
+		manifests.FactoryWithoutError(ClusterRole),
+		manifests.FactoryWithoutError(ClusterRoleBinding),
+		manifests.FactoryWithoutError(Role),
+		manifests.FactoryWithoutError(RoleBinding),
 	}
 	for _, factory := range resourceFactories {
 		res, err := factory(params)
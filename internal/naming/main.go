@@ -47,6 +47,31 @@ func PrometheusConfigMapVolume() string {
 	return "prometheus-config"
 }
 
+// ContainerRuntimeSocketVolume returns the name to use for the container runtime socket's volume in the pod.
+func ContainerRuntimeSocketVolume() string {
+	return "container-runtime-socket"
+}
+
+// EFADevicesVolume returns the name to use for the volume of the EFA devices in the sysfs of the node.
+func EFADevicesVolume() string {
+	return "efa-devices"
+}
+
+// AWSCredentialsVolume returns the name to use for the volume of the AWS credentials file of the node.
+func AWSCredentialsVolume() string {
+	return "aws-credentials"
+}
+
+// SysDevicesVolume returns the name to use for the volume of the devices in the sysfs of the node.
+func SysDevicesVolume() string {
+	return "sys-devices"
+}
+
+// PodResourcesVolume returns the name to use for the volume of the pod resources socket of the kubelet.
+func PodResourcesVolume() string {
+	return "pod-resources"
+}
+
 // Container returns the name to use for the container in the pod.
 func Container() string {
 	return "otc-container"
@@ -57,11 +82,47 @@ func TAContainer() string {
 	return "ta-container"
 }
 
+// PrometheusConfigReloaderContainer returns the name to use for the container reloading the Prometheus configuration
+// of the agent in the pod.
+func PrometheusConfigReloaderContainer() string {
+	return "prometheus-config-reloader"
+}
+
 // Collector builds the collector (deployment/daemonset) name based on the instance.
 func Collector(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// NodeGroup builds the name of the DaemonSet and ConfigMap rendered for a node group of the instance.
+func NodeGroup(otelcol, group string) string {
+	return DNSName(Truncate("%s-%s", 63, otelcol, group))
+}
+
+// KubernetesEvents builds the name of the Deployment and ConfigMap of the agent collecting the Kubernetes events.
+func KubernetesEvents(otelcol string) string {
+	return DNSName(Truncate("%s-k8s-events", 63, otelcol))
+}
+
+// FluentBit builds the name of the DaemonSet and ConfigMap of Fluent Bit collecting the logs of the nodes.
+func FluentBit(otelcol string) string {
+	return DNSName(Truncate("%s-fluent-bit", 63, otelcol))
+}
+
+// DcgmExporter builds the name of the DaemonSet, Service and ConfigMap of the DCGM exporter of the GPU metrics.
+func DcgmExporter(otelcol string) string {
+	return DNSName(Truncate("%s-dcgm-exporter", 63, otelcol))
+}
+
+// DcgmExporterMIG builds the name of the DaemonSet and ConfigMap of the DCGM exporter of the nodes with MIG enabled.
+func DcgmExporterMIG(otelcol string) string {
+	return DNSName(Truncate("%s-dcgm-exporter-mig", 63, otelcol))
+}
+
+// NeuronMonitor builds the name of the DaemonSet, Service and ConfigMap of the Neuron monitor of the Neuron metrics.
+func NeuronMonitor(otelcol string) string {
+	return DNSName(Truncate("%s-neuron-monitor", 63, otelcol))
+}
+
 // HorizontalPodAutoscaler builds the autoscaler name based on the instance.
 func HorizontalPodAutoscaler(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
@@ -117,6 +178,27 @@ func TAService(otelcol string) string {
 	return DNSName(Truncate("%s-target-allocator-service", 63, otelcol))
 
 }
+
+// TAClusterRole builds the name of the cluster role of the TargetAllocator of the instance.
+func TAClusterRole(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-target-allocator-cluster-role", 63, otelcol, namespace))
+}
+
+// TAClusterRoleBinding builds the name of the cluster role binding of the TargetAllocator of the instance.
+func TAClusterRoleBinding(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-target-allocator-cluster-role-binding", 63, otelcol, namespace))
+}
+
+// TARole builds the name of the role of the TargetAllocator of the instance, when the operator is namespace-scoped.
+func TARole(otelcol string) string {
+	return DNSName(Truncate("%s-target-allocator-role", 63, otelcol))
+}
+
+// TARoleBinding builds the name of the role binding of the TargetAllocator of the instance.
+func TARoleBinding(otelcol string) string {
+	return DNSName(Truncate("%s-target-allocator-role-binding", 63, otelcol))
+}
+
 func TAPodDestination(otelcol string) string {
 	return DNSName(Truncate("%s-target-allocator", 63, otelcol))
 }
@@ -126,6 +208,32 @@ func ServiceAccount(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// PodIdentityAssociation builds the name of the EKS Pod Identity association of the instance.
+func PodIdentityAssociation(otelcol string) string {
+	return DNSName(Truncate("%s", 63, otelcol))
+}
+
+// ClusterRole builds the name of the cluster role of the instance, which includes its namespace as cluster roles are
+// not namespaced.
+func ClusterRole(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-cluster-role", 63, otelcol, namespace))
+}
+
+// ClusterRoleBinding builds the name of the cluster role binding of the instance.
+func ClusterRoleBinding(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-cluster-role-binding", 63, otelcol, namespace))
+}
+
+// Role builds the name of the role of the instance, when the operator is namespace-scoped.
+func Role(otelcol string) string {
+	return DNSName(Truncate("%s-role", 63, otelcol))
+}
+
+// RoleBinding builds the name of the role binding of the instance.
+func RoleBinding(otelcol string) string {
+	return DNSName(Truncate("%s-role-binding", 63, otelcol))
+}
+
 // ServiceMonitor builds the service Monitor name based on the instance.
 func ServiceMonitor(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
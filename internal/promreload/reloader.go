@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package promreload reloads the Prometheus configuration of the CloudWatch Agent of a pod without restarting it. It
+// runs in a sidecar of the agent pods sharing their process namespace, which signals the agent with SIGHUP once the
+// kubelet updates the file of the Prometheus configuration ConfigMap mounted in the pod.
+package promreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// DefaultInterval is the interval between two reads of the Prometheus configuration file.
+	DefaultInterval = 10 * time.Second
+
+	// AgentProcess is the name of the executable of the CloudWatch Agent.
+	AgentProcess = "amazon-cloudwatch-agent"
+)
+
+// Reloader reads the Prometheus configuration file at every interval, and sends SIGHUP to the processes of the agent
+// when its content changes, which makes the Prometheus receiver of the agent reload its scrape configuration.
+type Reloader struct {
+	file     string
+	process  string
+	interval time.Duration
+	log      logr.Logger
+
+	// procDir is where the processes of the pod are listed, and signal sends the signal to one of them.
+	procDir string
+	signal  func(pid int, sig syscall.Signal) error
+
+	// sum is the checksum of the content of the file which was read last.
+	sum    [sha256.Size]byte
+	loaded bool
+}
+
+// New returns the reloader of the Prometheus configuration file of the processes of the executable.
+func New(file, process string, interval time.Duration, log logr.Logger) *Reloader {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Reloader{
+		file:     file,
+		process:  process,
+		interval: interval,
+		log:      log,
+		procDir:  "/proc",
+		signal:   syscall.Kill,
+	}
+}
+
+// Start reloads the configuration at every interval until the context is done.
+func (r *Reloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Reload(); err != nil {
+			r.log.Error(err, "failed to reload the Prometheus configuration", "file", r.file)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reload signals the processes of the agent when the configuration changed since it was read last. The first read
+// only records the configuration, which the agent loaded when it started.
+func (r *Reloader) Reload() error {
+	content, err := os.ReadFile(r.file)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(content)
+	if !r.loaded {
+		r.sum, r.loaded = sum, true
+		return nil
+	}
+	if sum == r.sum {
+		return nil
+	}
+
+	pids, err := r.processes()
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		// the agent is restarting, and loads the new configuration when it starts
+		r.sum = sum
+		return nil
+	}
+	var errs []error
+	for _, pid := range pids {
+		if err := r.signal(pid, syscall.SIGHUP); err != nil {
+			errs = append(errs, fmt.Errorf("failed to signal the process %d: %w", pid, err))
+		}
+	}
+	if len(errs) > 0 {
+		// the configuration is reloaded again at the next interval
+		return errors.Join(errs...)
+	}
+	r.sum = sum
+	r.log.Info("reloaded the Prometheus configuration", "file", r.file, "processes", pids)
+	return nil
+}
+
+// processes returns the processes running the executable, found by the first argument of their command line.
+func (r *Reloader) processes() ([]int, error) {
+	entries, err := os.ReadDir(r.procDir)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		// the processes may have exited since the directory was listed
+		cmdline, err := os.ReadFile(filepath.Join(r.procDir, entry.Name(), "cmdline"))
+		if err != nil {
+			continue
+		}
+		argv0, _, _ := bytes.Cut(cmdline, []byte{0})
+		if filepath.Base(string(argv0)) == r.process {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package promreload
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prometheus.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("scrape_configs: []\n"), 0o600))
+
+	procDir := filepath.Join(dir, "proc")
+	writeProcess(t, procDir, "1", "/pause\x00")
+	writeProcess(t, procDir, "7", "/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent\x00-config\x00/etc/cwagentconfig/cwagentconfig.json\x00")
+	writeProcess(t, procDir, "12", "/opt/aws/amazon-cloudwatch-agent/bin/start-amazon-cloudwatch-agent\x00")
+	require.NoError(t, os.MkdirAll(filepath.Join(procDir, "self"), 0o700))
+
+	var signaled []int
+	var signalErr error
+	reloader := New(file, AgentProcess, 0, logr.Discard())
+	reloader.procDir = procDir
+	reloader.signal = func(pid int, sig syscall.Signal) error {
+		assert.Equal(t, syscall.SIGHUP, sig)
+		signaled = append(signaled, pid)
+		return signalErr
+	}
+
+	// the agent loaded the configuration when it started
+	require.NoError(t, reloader.Reload())
+	assert.Empty(t, signaled)
+
+	// the unchanged configuration is not reloaded
+	require.NoError(t, reloader.Reload())
+	assert.Empty(t, signaled)
+
+	// the change of the configuration is reloaded by the agent only
+	require.NoError(t, os.WriteFile(file, []byte("scrape_configs:\n- job_name: app\n"), 0o600))
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, []int{7}, signaled)
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, []int{7}, signaled)
+
+	// the failed reloads are retried
+	signaled, signalErr = nil, errors.New("operation not permitted")
+	require.NoError(t, os.WriteFile(file, []byte("scrape_configs: []\n"), 0o600))
+	assert.Error(t, reloader.Reload())
+	signalErr = nil
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, []int{7, 7}, signaled)
+}
+
+func TestReloadMissingFile(t *testing.T) {
+	reloader := New(filepath.Join(t.TempDir(), "prometheus.yaml"), AgentProcess, 0, logr.Discard())
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, DefaultInterval, reloader.interval)
+}
+
+func writeProcess(t *testing.T, procDir, pid, cmdline string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(procDir, pid), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(procDir, pid, "cmdline"), []byte(cmdline), 0o600))
+}
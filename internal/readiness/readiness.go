@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package readiness checks that a replica of the operator can serve the admission requests, so that the replicas whose
+// webhooks would fail are removed from the webhook service by their readiness probe, instead of failing the admission
+// of the pods of the cluster.
+package readiness
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// DefaultCertMinValidity is the remaining validity under which the serving certificate is reported as expiring,
+	// well after cert-manager and the self-managed certificates renew it.
+	DefaultCertMinValidity = 24 * time.Hour
+	// DefaultInterval is the interval between two reads of the CustomResourceDefinitions.
+	DefaultInterval = time.Minute
+
+	// the names of the serving certificate and key in the certificate directory of the webhook server
+	certName = "tls.crt"
+	keyName  = "tls.key"
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// WebhookCertificate returns the checker of the serving certificate of the certificate directory, which fails when
+// the certificate or its key can't be read, or when the certificate isn't valid yet or expires within minValidity.
+func WebhookCertificate(certDir string, minValidity time.Duration) healthz.Checker {
+	return webhookCertificate(certDir, minValidity, time.Now)
+}
+
+func webhookCertificate(certDir string, minValidity time.Duration, now func() time.Time) healthz.Checker {
+	return func(_ *http.Request) error {
+		pair, err := tls.LoadX509KeyPair(filepath.Join(certDir, certName), filepath.Join(certDir, keyName))
+		if err != nil {
+			return fmt.Errorf("invalid webhook serving certificate: %w", err)
+		}
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("invalid webhook serving certificate: %w", err)
+		}
+		switch t := now(); {
+		case t.Before(cert.NotBefore):
+			return fmt.Errorf("the webhook serving certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+		case t.Add(minValidity).After(cert.NotAfter):
+			return fmt.Errorf("the webhook serving certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// CustomResourceDefinitions returns the checker of the CustomResourceDefinitions, which fails when one of them isn't
+// installed or established. They are read unstructured at most once per interval. The errors of the API server keep
+// the previous result, so that the replicas are not all removed from the webhook service when it is unavailable.
+func CustomResourceDefinitions(reader client.Reader, names []string, interval time.Duration, log logr.Logger) healthz.Checker {
+	c := &crdChecker{reader: reader, names: names, interval: interval, log: log, now: time.Now}
+	return c.check
+}
+
+type crdChecker struct {
+	reader   client.Reader
+	names    []string
+	interval time.Duration
+	log      logr.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *crdChecker) check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && c.now().Sub(c.checked) < c.interval {
+		return c.err
+	}
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+	var errs []error
+	for _, name := range c.names {
+		established, err := c.established(ctx, name)
+		switch {
+		case apierrors.IsNotFound(err):
+			errs = append(errs, fmt.Errorf("the CustomResourceDefinition %s is not installed", name))
+		case err != nil:
+			c.log.Error(err, "unable to read the CustomResourceDefinition, keeping the previous readiness", "crd", name)
+			return c.err
+		case !established:
+			errs = append(errs, fmt.Errorf("the CustomResourceDefinition %s is not established", name))
+		}
+	}
+	c.checked, c.err = c.now(), errors.Join(errs...)
+	return c.err
+}
+
+// established returns whether the Established condition of the CustomResourceDefinition is true.
+func (c *crdChecker) established(ctx context.Context, name string) (bool, error) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	if err := c.reader.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		return false, err
+	}
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if ok && condition["type"] == "Established" {
+			return condition["status"] == "True", nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package readiness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const agentsCRD = "amazoncloudwatchagents.cloudwatch.aws.amazon.com"
+
+func writeCertificate(t *testing.T, dir string, notBefore, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notBefore, NotAfter: notAfter, DNSNames: []string{"webhook"}}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, certName), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, keyName), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func TestWebhookCertificate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	check := webhookCertificate(dir, DefaultCertMinValidity, func() time.Time { return now })
+	assert.ErrorContains(t, check(nil), "invalid webhook serving certificate")
+
+	writeCertificate(t, dir, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	assert.NoError(t, check(nil))
+
+	writeCertificate(t, dir, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.EqualError(t, check(nil), "the webhook serving certificate expires at 2025-01-01T01:00:00Z")
+
+	writeCertificate(t, dir, now.Add(time.Hour), now.Add(30*24*time.Hour))
+	assert.EqualError(t, check(nil), "the webhook serving certificate is not valid before 2025-01-01T01:00:00Z")
+}
+
+func newCRD(name string, conditions ...interface{}) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": conditions},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(name)
+	return crd
+}
+
+func newTestClient(funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(crdGVK, meta.RESTScopeRoot)
+	return interceptor.NewClient(fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithRESTMapper(mapper).WithObjects(objs...).Build(), funcs)
+}
+
+func TestCustomResourceDefinitions(t *testing.T) {
+	established := map[string]interface{}{"type": "Established", "status": "True"}
+	notEstablished := map[string]interface{}{"type": "Established", "status": "False"}
+
+	for _, tt := range []struct {
+		name    string
+		objs    []client.Object
+		wantErr string
+	}{
+		{name: "established", objs: []client.Object{newCRD(agentsCRD, established)}},
+		{name: "not established", objs: []client.Object{newCRD(agentsCRD, notEstablished)}, wantErr: "the CustomResourceDefinition " + agentsCRD + " is not established"},
+		{name: "no conditions", objs: []client.Object{newCRD(agentsCRD)}, wantErr: "the CustomResourceDefinition " + agentsCRD + " is not established"},
+		{name: "not installed", wantErr: "the CustomResourceDefinition " + agentsCRD + " is not installed"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CustomResourceDefinitions(newTestClient(interceptor.Funcs{}, tt.objs...), []string{agentsCRD}, DefaultInterval, logr.Discard())
+			if tt.wantErr == "" {
+				assert.NoError(t, check(nil))
+			} else {
+				assert.EqualError(t, check(nil), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomResourceDefinitions_Interval(t *testing.T) {
+	now := time.Now()
+	var unavailable bool
+	c := newTestClient(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if unavailable {
+				return errors.New("connection refused")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+	checker := &crdChecker{reader: c, names: []string{agentsCRD}, interval: time.Minute, log: logr.Discard(), now: func() time.Time { return now }}
+	assert.Error(t, checker.check(nil))
+
+	// the result is kept until the next interval
+	require.NoError(t, c.Create(context.Background(), newCRD(agentsCRD, map[string]interface{}{"type": "Established", "status": "True"})))
+	assert.Error(t, checker.check(nil))
+	now = now.Add(time.Minute)
+	assert.NoError(t, checker.check(nil))
+
+	// the errors of the API server keep the previous result
+	unavailable = true
+	now = now.Add(time.Minute)
+	assert.NoError(t, checker.check(nil))
+}
@@ -0,0 +1,279 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package render prints the manifests the operator creates for its custom resources without a cluster, so that the
+// changes of the resources or of the operator can be reviewed before they are applied.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1beta1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/controllers"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// Options are the settings of the operator the manifests are rendered with.
+type Options struct {
+	Config config.Config
+	// Scheme knows the kinds of the custom resources and of the manifests.
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+}
+
+// Render reads the YAML documents of the custom resources, and writes the manifests the operator creates for them as
+// YAML documents. The AmazonCloudWatchAgents and the Instrumentations are defaulted and validated as by the admission
+// webhooks, and the Instrumentations, which only configure the pod webhook, are written as defaulted. The ConfigMaps
+// among the documents are read by the config sources of the agents. The resources without a namespace are rendered in
+// the default namespace.
+func Render(ctx context.Context, opts Options, in io.Reader, out io.Writer) error {
+	objs, err := decode(opts.Scheme, in)
+	if err != nil {
+		return err
+	}
+	configMaps := configMapReader{}
+	for _, obj := range objs {
+		if configMap, ok := obj.(*corev1.ConfigMap); ok {
+			configMaps[client.ObjectKeyFromObject(configMap)] = configMap
+		}
+	}
+
+	r := &renderer{opts: opts, configMaps: configMaps, out: out}
+	for _, obj := range objs {
+		if err := r.render(ctx, obj); err != nil {
+			return fmt.Errorf("failed to render the %T %s: %w", obj, client.ObjectKeyFromObject(obj.(client.Object)), err)
+		}
+	}
+	return nil
+}
+
+// decode returns the objects of the YAML documents. The v1beta1 custom resources are converted to v1alpha1.
+func decode(scheme *runtime.Scheme, in io.Reader) ([]runtime.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	var objs []runtime.Object
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		switch src := obj.(type) {
+		case *v1beta1.AmazonCloudWatchAgent:
+			dst := &v1alpha1.AmazonCloudWatchAgent{}
+			if err := src.ConvertTo(dst); err != nil {
+				return nil, err
+			}
+			obj = dst
+		case *v1beta1.Instrumentation:
+			dst := &v1alpha1.Instrumentation{}
+			if err := src.ConvertTo(dst); err != nil {
+				return nil, err
+			}
+			obj = dst
+		case *v1alpha1.AmazonCloudWatchAgent, *v1alpha1.Instrumentation, *v1alpha1.DcgmExporter, *v1alpha1.NeuronMonitor, *corev1.ConfigMap:
+		default:
+			return nil, fmt.Errorf("unsupported kind %s", obj.GetObjectKind().GroupVersionKind())
+		}
+		if o := obj.(client.Object); o.GetNamespace() == "" {
+			o.SetNamespace("default")
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// admissionWebhook defaults and validates a kind of custom resource.
+type admissionWebhook interface {
+	admission.CustomDefaulter
+	admission.CustomValidator
+}
+
+type renderer struct {
+	opts       Options
+	configMaps configMapReader
+	out        io.Writer
+}
+
+func (r *renderer) render(ctx context.Context, obj runtime.Object) error {
+	switch instance := obj.(type) {
+	case *v1alpha1.AmazonCloudWatchAgent:
+		webhook := v1alpha1.NewCollectorWebhook(r.opts.Log, r.opts.Scheme, r.opts.Config)
+		if err := r.admit(ctx, webhook, instance); err != nil {
+			return err
+		}
+		resolved, err := collector.ResolveConfigSources(ctx, r.configMaps, *instance)
+		if err != nil {
+			return err
+		}
+		params := r.params()
+		params.OtelCol = resolved
+		objs, err := controllers.BuildCollector(params)
+		if err != nil {
+			return err
+		}
+		return r.write(instance, objs)
+	case *v1alpha1.Instrumentation:
+		webhook := v1alpha1.NewInstrumentationWebhook(r.opts.Log, r.opts.Scheme, r.opts.Config)
+		if err := r.admit(ctx, webhook, instance); err != nil {
+			return err
+		}
+		return r.write(instance, []client.Object{instance})
+	case *v1alpha1.DcgmExporter:
+		params := r.params()
+		params.DcgmExp = *instance
+		objs, err := controllers.BuildDcgmExporter(params)
+		if err != nil {
+			return err
+		}
+		return r.write(instance, objs)
+	case *v1alpha1.NeuronMonitor:
+		params := r.params()
+		params.NeuronExp = *instance
+		objs, err := controllers.BuildNeuronMonitor(params)
+		if err != nil {
+			return err
+		}
+		return r.write(instance, objs)
+	}
+	return nil
+}
+
+func (r *renderer) params() manifests.Params {
+	return manifests.Params{
+		Config: r.opts.Config,
+		Scheme: r.opts.Scheme,
+		Log:    r.opts.Log,
+		// the events of the builders are dropped
+		Recorder: &record.FakeRecorder{},
+	}
+}
+
+// admit defaults and validates the custom resource as the admission webhooks do, and logs the warnings.
+func (r *renderer) admit(ctx context.Context, webhook admissionWebhook, obj client.Object) error {
+	if err := webhook.Default(ctx, obj); err != nil {
+		return err
+	}
+	warnings, err := webhook.ValidateCreate(ctx, obj)
+	for _, warning := range warnings {
+		r.opts.Log.Info("warning: "+warning, "kind", fmt.Sprintf("%T", obj), "resource", client.ObjectKeyFromObject(obj).String())
+	}
+	return err
+}
+
+// write writes the objects as YAML documents, sorted by kind, namespace and name, after a comment naming the custom
+// resource they were rendered for. The fields set by the API server are omitted.
+func (r *renderer) write(instance client.Object, objs []client.Object) error {
+	type document struct {
+		kind, namespace, name string
+		data                  []byte
+	}
+	var docs []document
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, r.opts.Scheme)
+		if err != nil {
+			return err
+		}
+		fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		fields["apiVersion"], fields["kind"] = gvk.GroupVersion().String(), gvk.Kind
+		delete(fields, "status")
+		pruneNulls(fields)
+		data, err := yaml.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, document{kind: gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName(), data: data})
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		if docs[i].kind != docs[j].kind {
+			return docs[i].kind < docs[j].kind
+		}
+		if docs[i].namespace != docs[j].namespace {
+			return docs[i].namespace < docs[j].namespace
+		}
+		return docs[i].name < docs[j].name
+	})
+
+	gvk, err := apiutil.GVKForObject(instance, r.opts.Scheme)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if _, err := fmt.Fprintf(r.out, "---\n# Source: %s %s/%s\n%s", gvk.Kind, instance.GetNamespace(), instance.GetName(), doc.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneNulls removes the null fields, such as the unset creation timestamps.
+func pruneNulls(fields map[string]interface{}) {
+	for k, v := range fields {
+		switch value := v.(type) {
+		case nil:
+			delete(fields, k)
+		case map[string]interface{}:
+			pruneNulls(value)
+		case []interface{}:
+			for _, item := range value {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneNulls(m)
+				}
+			}
+		}
+	}
+}
+
+// configMapReader serves the ConfigMaps of the rendered documents to the config sources of the agents.
+type configMapReader map[types.NamespacedName]*corev1.ConfigMap
+
+func (c configMapReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return fmt.Errorf("unsupported %T", obj)
+	}
+	src, found := c[key]
+	if !found {
+		return apierrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+	}
+	src.DeepCopyInto(configMap)
+	return nil
+}
+
+func (c configMapReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return errors.New("listing is not supported when rendering")
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1beta1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func testOptions(t *testing.T) Options {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	return Options{
+		Config: config.New(config.WithDcgmExporterImage("nvcr.io/nvidia/k8s/dcgm-exporter:3.3.5")),
+		Scheme: scheme,
+		Log:    logr.Discard(),
+	}
+}
+
+func TestRender(t *testing.T) {
+	in := `apiVersion: cloudwatch.aws.amazon.com/v1alpha1
+kind: DcgmExporter
+metadata:
+  name: dcgm-exporter
+  namespace: amazon-cloudwatch
+---
+apiVersion: cloudwatch.aws.amazon.com/v1beta1
+kind: Instrumentation
+metadata:
+  name: java
+spec:
+  exporter:
+    endpoint: http://cloudwatch-agent.amazon-cloudwatch:4316
+`
+	out := &bytes.Buffer{}
+	require.NoError(t, Render(context.Background(), testOptions(t), strings.NewReader(in), out))
+	rendered := out.String()
+
+	assert.Contains(t, rendered, "---\n# Source: DcgmExporter amazon-cloudwatch/dcgm-exporter\napiVersion: apps/v1\nkind: DaemonSet\n")
+	assert.Contains(t, rendered, "---\n# Source: DcgmExporter amazon-cloudwatch/dcgm-exporter\napiVersion: v1\nkind: Service\n")
+	// the DaemonSet is written before the Service
+	assert.Less(t, strings.Index(rendered, "kind: DaemonSet"), strings.Index(rendered, "kind: Service"))
+	// the Instrumentation is converted, rendered in the default namespace and defaulted
+	assert.Contains(t, rendered, "# Source: Instrumentation default/java\napiVersion: cloudwatch.aws.amazon.com/v1alpha1\nkind: Instrumentation\n")
+	assert.Contains(t, rendered, "app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator")
+	assert.NotContains(t, rendered, "creationTimestamp")
+	assert.NotContains(t, rendered, "status:")
+}
+
+func TestRenderUnsupportedKind(t *testing.T) {
+	in := `apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+`
+	err := Render(context.Background(), testOptions(t), strings.NewReader(in), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "unsupported kind /v1, Kind=Secret")
+}
+
+func TestConfigMapReader(t *testing.T) {
+	ctx := context.Background()
+	reader := configMapReader{
+		{Namespace: "amazon-cloudwatch", Name: "fragment"}: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch", Name: "fragment"},
+			Data:       map[string]string{"config.json": "{}"},
+		},
+	}
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, reader.Get(ctx, types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "fragment"}, configMap))
+	assert.Equal(t, "{}", configMap.Data["config.json"])
+
+	err := reader.Get(ctx, types.NamespacedName{Namespace: "default", Name: "fragment"}, configMap)
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package signature
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/tlog"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// The annotations of the layers of the cosign signature manifests.
+const (
+	signatureAnnotation   = "dev.cosignproject.cosign/signature"
+	certificateAnnotation = "dev.sigstore.cosign/certificate"
+	bundleAnnotation      = "dev.sigstore.cosign/bundle"
+
+	simpleSigningType = "cosign container image signature"
+)
+
+// simpleSigningPayload is the payload cosign signs, which binds the signature to the digest of the image.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the inclusion of the signature in the Rekor transparency log, whose signed entry timestamp (SET)
+// proves the time the short-lived certificate of the keyless signature was used at.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+	Payload              struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogIndex       int64  `json:"logIndex"`
+		LogID          string `json:"logID"`
+	} `json:"Payload"`
+}
+
+// cosignSignature is a layer of a cosign signature manifest: the signed payload and the annotations holding its
+// signature and, for the keyless signatures, its certificate and Rekor bundle.
+type cosignSignature struct {
+	payload     []byte
+	annotations map[string]string
+}
+
+// verifyPayload checks that the signed payload is a cosign signature of the manifest with the digest.
+func verifyPayload(payload []byte, digest string) error {
+	signed := simpleSigningPayload{}
+	if err := json.Unmarshal(payload, &signed); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if signed.Critical.Type != simpleSigningType {
+		return fmt.Errorf("unexpected signature type %q", signed.Critical.Type)
+	}
+	if signed.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("the signature is for digest %s", signed.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// entity returns the signature as the entity sigstore-go verifies. The certificate and the Rekor bundle of the keyless
+// signatures are ignored for the signatures of a public key.
+func (s cosignSignature) entity(keyless bool) (verify.SignedEntity, error) {
+	signature, err := base64.StdEncoding.DecodeString(s.annotations[signatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return nil, errors.New("the signature layer has no signature")
+	}
+	digest := sha256.Sum256(s.payload)
+	entity := &signedEntity{signature: bundle.NewMessageSignature(digest[:], "SHA2_256", signature)}
+	if !keyless {
+		entity.verificationContent = &bundle.PublicKey{}
+		return entity, nil
+	}
+
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(s.annotations[certificateAnnotation]))
+	if err != nil || len(certs) != 1 {
+		return nil, errors.New("the signature has no valid certificate")
+	}
+	entity.verificationContent = bundle.NewCertificate(certs[0])
+	if s.annotations[bundleAnnotation] == "" {
+		return nil, errors.New("the signature has no Rekor bundle")
+	}
+	rekor := rekorBundle{}
+	if err = json.Unmarshal([]byte(s.annotations[bundleAnnotation]), &rekor); err != nil {
+		return nil, fmt.Errorf("invalid Rekor bundle: %w", err)
+	}
+	body, err := base64.StdEncoding.DecodeString(rekor.Payload.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Rekor entry: %w", err)
+	}
+	logID, err := hex.DecodeString(rekor.Payload.LogID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Rekor log ID: %w", err)
+	}
+	entity.tlogEntry, err = tlog.NewEntry(body, rekor.Payload.IntegratedTime, rekor.Payload.LogIndex, logID, rekor.SignedEntryTimestamp, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Rekor entry: %w", err)
+	}
+	return entity, nil
+}
+
+// signedEntity is a cosign signature of a payload, with the Rekor entry of the keyless signatures, whose integrated
+// time is the one their certificate is verified at.
+type signedEntity struct {
+	signature           *bundle.MessageSignature
+	verificationContent verify.VerificationContent
+	tlogEntry           *tlog.Entry
+}
+
+var _ verify.SignedEntity = &signedEntity{}
+
+func (e *signedEntity) HasInclusionPromise() bool {
+	return e.tlogEntry != nil
+}
+
+func (e *signedEntity) HasInclusionProof() bool {
+	return false
+}
+
+func (e *signedEntity) SignatureContent() (verify.SignatureContent, error) {
+	return e.signature, nil
+}
+
+func (e *signedEntity) Timestamps() ([][]byte, error) {
+	return nil, nil
+}
+
+func (e *signedEntity) TlogEntries() ([]*tlog.Entry, error) {
+	if e.tlogEntry == nil {
+		return nil, nil
+	}
+	return []*tlog.Entry{e.tlogEntry}, nil
+}
+
+func (e *signedEntity) VerificationContent() (verify.VerificationContent, error) {
+	return e.verificationContent, nil
+}
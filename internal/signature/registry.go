@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package signature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// maxPayloadSize bounds the signature payloads read from the registries.
+const maxPayloadSize = 1 << 20
+
+var errNotSigned = errors.New("the image is not signed")
+
+// resolveDigest returns the digest of the manifest the tag of the reference resolves to, the index of the
+// multi-platform images included, which is the manifest cosign signs.
+func (v *Verifier) resolveDigest(ctx context.Context, ref name.Reference, keychain func() (authn.Keychain, error)) (string, error) {
+	opts, err := v.remoteOptions(ctx, keychain)
+	if err != nil {
+		return "", err
+	}
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// signatures returns the cosign signatures of the manifest with the digest, which cosign pushes as the layers of the
+// manifest of the tag sha256-<hex>.sig of the repository of the image.
+func (v *Verifier) signatures(ctx context.Context, ref name.Digest, keychain func() (authn.Keychain, error)) ([]cosignSignature, error) {
+	opts, err := v.remoteOptions(ctx, keychain)
+	if err != nil {
+		return nil, err
+	}
+	image, err := remote.Image(ref.Context().Tag(strings.Replace(ref.DigestStr(), ":", "-", 1)+".sig"), opts...)
+	var transportErr *transport.Error
+	if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+		return nil, errNotSigned
+	} else if err != nil {
+		return nil, err
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []cosignSignature
+	for _, desc := range manifest.Layers {
+		if desc.Size > maxPayloadSize {
+			return nil, fmt.Errorf("the signature payload %s is too large", desc.Digest)
+		}
+		layer, err := image.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		// the content of the layer is checked against its digest once read
+		content, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := io.ReadAll(io.LimitReader(content, maxPayloadSize))
+		_ = content.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the signature payload %s: %w", desc.Digest, err)
+		}
+		signatures = append(signatures, cosignSignature{payload: payload, annotations: desc.Annotations})
+	}
+	return signatures, nil
+}
+
+// remoteOptions return the options reading the registries with the credentials of the keychain.
+func (v *Verifier) remoteOptions(ctx context.Context, keychain func() (authn.Keychain, error)) ([]remote.Option, error) {
+	credentials, err := keychain()
+	if err != nil {
+		return nil, err
+	}
+	return []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(credentials), remote.WithTransport(v.transport)}, nil
+}
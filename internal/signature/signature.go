@@ -0,0 +1,392 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signature verifies the cosign signatures of the images the operator deploys or injects into the workloads
+// with sigstore-go, either with a public key or, for the keyless signatures, with the identity of their Fulcio
+// certificate logged in Rekor. The signatures are read from the registries of the images with the credentials the
+// pods pull them with: the image pull secrets of the pods and of their service account, then the credentials of the
+// operator.
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	kauth "github.com/google/go-containerregistry/pkg/authn/kubernetes"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	sigstoresignature "github.com/sigstore/sigstore/pkg/signature"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+// The operator reads the image pull secrets of the pods and of their service accounts the registries are read with.
+// +kubebuilder:rbac:groups="",resources=secrets;serviceaccounts,verbs=get
+
+const (
+	// verifiedTTL and failedTTL are how long the outcome of the verification of a digest is kept, the signatures
+	// being removable and the failures possibly transient. The digests the tags resolve to are kept as long as the
+	// verified outcomes, the tags being movable.
+	verifiedTTL = 10 * time.Minute
+	failedTTL   = time.Minute
+
+	// RefreshInterval is how often the images verified ahead of their deployments are refreshed, so that their
+	// verifications never expire.
+	RefreshInterval = verifiedTTL / 2
+
+	defaultTimeout = 10 * time.Second
+)
+
+// Options configure the verification of the signatures, with a public key or with a keyless identity.
+type Options struct {
+	// PublicKeyFile is the PEM public key the images are signed with.
+	PublicKeyFile string
+	// CertificateIdentity and CertificateOIDCIssuer are the identity, an email address or URI, and its OIDC issuer
+	// the Fulcio certificates of the keyless signatures must be issued to.
+	CertificateIdentity   string
+	CertificateOIDCIssuer string
+	// FulcioRootsFile holds the PEM root and intermediate certificates of Fulcio the keyless certificates chain to.
+	FulcioRootsFile string
+	// RekorPublicKeyFile is the PEM public key of the Rekor transparency log the keyless signatures are logged in.
+	RekorPublicKeyFile string
+}
+
+// Enabled returns whether the options configure a verification.
+func (o Options) Enabled() bool {
+	return o.PublicKeyFile != "" || o.CertificateIdentity != ""
+}
+
+// Verifier verifies the cosign signatures of the images.
+type Verifier struct {
+	verifier *verify.SignedEntityVerifier
+	// identity is the identity of the keyless signatures, or nil for the ones of the public key
+	identity *verify.CertificateIdentity
+	// clientset reads the image pull secrets, and transport the registries
+	clientset kubernetes.Interface
+	transport http.RoundTripper
+	now       func() time.Time
+
+	mu sync.Mutex
+	// digests are the digests the tags of the images resolve to, by image, and results the outcomes of the
+	// verifications of the digests, by repository and digest.
+	digests map[string]resolution
+	results map[string]result
+}
+
+type resolution struct {
+	digest  string
+	expires time.Time
+}
+
+type result struct {
+	err     error
+	expires time.Time
+}
+
+// New returns a verifier with the key or the keyless identity of the options, which reads the image pull secrets with
+// the clientset and the registries with the transport, or the default one when it is nil.
+func New(opts Options, clientset kubernetes.Interface, transport http.RoundTripper) (*Verifier, error) {
+	if transport == nil {
+		transport = remote.DefaultTransport
+	}
+	v := &Verifier{
+		clientset: clientset,
+		transport: transport,
+		now:       time.Now,
+		digests:   map[string]resolution{},
+		results:   map[string]result{},
+	}
+	var err error
+	switch {
+	case opts.PublicKeyFile != "" && opts.CertificateIdentity != "":
+		return nil, errors.New("the images are verified either with a public key or with a keyless identity")
+	case opts.PublicKeyFile != "":
+		v.verifier, err = newKeyVerifier(opts.PublicKeyFile)
+	case opts.CertificateIdentity != "":
+		if opts.CertificateOIDCIssuer == "" || opts.FulcioRootsFile == "" || opts.RekorPublicKeyFile == "" {
+			return nil, errors.New("the keyless verification needs the OIDC issuer of the identity, the Fulcio roots and the Rekor public key")
+		}
+		identity, identityErr := verify.NewShortCertificateIdentity(opts.CertificateOIDCIssuer, "", opts.CertificateIdentity, "")
+		if identityErr != nil {
+			return nil, fmt.Errorf("invalid keyless identity: %w", identityErr)
+		}
+		v.identity = &identity
+		v.verifier, err = newKeylessVerifier(opts.FulcioRootsFile, opts.RekorPublicKeyFile)
+	default:
+		return nil, errors.New("neither a public key nor a keyless identity is configured")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// newKeyVerifier returns the verifier of the signatures of the public key, at the current time.
+func newKeyVerifier(file string) (*verify.SignedEntityVerifier, error) {
+	key, err := readPublicKey(file)
+	if err != nil {
+		return nil, err
+	}
+	keyVerifier, err := sigstoresignature.LoadVerifier(key, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported public key in %s: %w", file, err)
+	}
+	trustedMaterial := root.NewTrustedPublicKeyMaterial(func(string) (root.TimeConstrainedVerifier, error) {
+		return root.NewExpiringKey(keyVerifier, time.Time{}, time.Time{}), nil
+	})
+	return verify.NewSignedEntityVerifier(trustedMaterial, verify.WithCurrentTime())
+}
+
+// newKeylessVerifier returns the verifier of the keyless signatures, whose certificates must chain to the Fulcio
+// roots when the signatures were logged in Rekor.
+func newKeylessVerifier(rootsFile, rekorKeyFile string) (*verify.SignedEntityVerifier, error) {
+	authorities, err := readCertificateAuthorities(rootsFile)
+	if err != nil {
+		return nil, err
+	}
+	rekorKey, err := readPublicKey(rekorKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	// the entries of the log are identified by the SHA-256 digest of its DER public key
+	der, err := x509.MarshalPKIXPublicKey(rekorKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Rekor public key in %s: %w", rekorKeyFile, err)
+	}
+	logID := sha256.Sum256(der)
+	rekor := &root.TransparencyLog{
+		ID:                logID[:],
+		PublicKey:         rekorKey,
+		HashFunc:          crypto.SHA256,
+		SignatureHashFunc: crypto.SHA256,
+		// the key is trusted for all the entries of the log
+		ValidityPeriodStart: time.Unix(0, 0),
+	}
+	trustedRoot, err := root.NewTrustedRoot(root.TrustedRootMediaType01, authorities, nil, nil,
+		map[string]*root.TransparencyLog{hex.EncodeToString(logID[:]): rekor})
+	if err != nil {
+		return nil, err
+	}
+	return verify.NewSignedEntityVerifier(trustedRoot, verify.WithTransparencyLog(1), verify.WithIntegratedTimestamps(1))
+}
+
+// Verify returns the reference of the image by the digest it resolves to, or an error unless the digest has a cosign
+// signature which verifies with the key or the keyless identity of the verifier. The registry of the image is read
+// with the credentials the pods pull it with. The image is deployed by this reference, so that a tag moved after the
+// verification doesn't pull an unverified image. The digests of the tags and the outcomes of the verifications of the
+// digests are cached for a while.
+func (v *Verifier) Verify(ctx context.Context, image string, pull config.ImagePull) (string, error) {
+	pinned, err := v.verifyImage(ctx, image, pull, true)
+	if err != nil {
+		return "", fmt.Errorf("the signature of image %s can't be verified: %w", image, err)
+	}
+	return pinned, nil
+}
+
+// Refresh verifies the images again with the credentials of the operator, bypassing the cache, which keeps their
+// verifications cached for the callers of Verify as long as it is called every RefreshInterval.
+func (v *Verifier) Refresh(ctx context.Context, images []string) error {
+	var errs []error
+	for _, image := range images {
+		if _, err := v.verifyImage(ctx, image, config.ImagePull{}, false); err != nil {
+			errs = append(errs, fmt.Errorf("the signature of image %s can't be verified: %w", image, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Verifier) verifyImage(ctx context.Context, image string, pull config.ImagePull, cache bool) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	// the image pull secrets are only read when the registry is
+	keychain := sync.OnceValues(func() (authn.Keychain, error) {
+		return v.keychain(ctx, pull)
+	})
+	digest, err := v.digest(ctx, image, ref, keychain, cache)
+	if err != nil {
+		return "", err
+	}
+
+	// the verified outcomes hold for all the callers, whereas the failures may be due to the credentials of one
+	key := ref.Context().Name() + "@" + digest
+	failedKey := key + " " + pull.Namespace + "/" + pull.ServiceAccountName + "/" + strings.Join(pull.ImagePullSecrets, ",")
+	v.mu.Lock()
+	cached, ok := v.results[key]
+	if !ok {
+		cached, ok = v.results[failedKey]
+	}
+	v.mu.Unlock()
+	if !cache || !ok || !v.now().Before(cached.expires) {
+		cached.err = v.verify(ctx, ref.Context().Digest(digest), keychain)
+		// the verifications interrupted by the caller are not cached
+		if ctx.Err() == nil {
+			v.mu.Lock()
+			if cached.err == nil {
+				cached.expires = v.now().Add(verifiedTTL)
+				v.results[key] = cached
+				delete(v.results, failedKey)
+			} else {
+				cached.expires = v.now().Add(failedTTL)
+				v.results[failedKey] = cached
+				delete(v.results, key)
+			}
+			v.mu.Unlock()
+		}
+	}
+	if cached.err != nil {
+		return "", cached.err
+	}
+	return pinnedReference(image, digest), nil
+}
+
+// digest returns the digest of the image, resolving its tag unless the digest it resolves to is cached.
+func (v *Verifier) digest(ctx context.Context, image string, ref name.Reference, keychain func() (authn.Keychain, error), cache bool) (string, error) {
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.DigestStr(), nil
+	}
+	v.mu.Lock()
+	cached, ok := v.digests[image]
+	v.mu.Unlock()
+	if cache && ok && v.now().Before(cached.expires) {
+		return cached.digest, nil
+	}
+	digest, err := v.resolveDigest(ctx, ref, keychain)
+	if err != nil {
+		return "", err
+	}
+	v.mu.Lock()
+	v.digests[image] = resolution{digest: digest, expires: v.now().Add(verifiedTTL)}
+	v.mu.Unlock()
+	return digest, nil
+}
+
+// verify verifies the signatures of the manifest with the digest.
+func (v *Verifier) verify(ctx context.Context, ref name.Digest, keychain func() (authn.Keychain, error)) error {
+	signatures, err := v.signatures(ctx, ref, keychain)
+	if err != nil {
+		return err
+	}
+	if len(signatures) == 0 {
+		return errNotSigned
+	}
+
+	// the image is verified by any of its signatures
+	var errs []error
+	for _, signature := range signatures {
+		if err = v.verifySignature(signature, ref.DigestStr()); err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// verifySignature verifies that the payload of the signature is signed with the key or the keyless identity of the
+// verifier, and that it is the cosign payload of the manifest with the digest.
+func (v *Verifier) verifySignature(signature cosignSignature, digest string) error {
+	if err := verifyPayload(signature.payload, digest); err != nil {
+		return err
+	}
+	entity, err := signature.entity(v.identity != nil)
+	if err != nil {
+		return err
+	}
+	policy := verify.WithKey()
+	if v.identity != nil {
+		policy = verify.WithCertificateIdentity(*v.identity)
+	}
+	if _, err = v.verifier.Verify(entity, verify.NewPolicy(verify.WithArtifact(bytes.NewReader(signature.payload)), policy)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// keychain returns the credentials the images are pulled with: the image pull secrets of the pods and of their
+// service account in their namespace, then the credentials of the operator.
+func (v *Verifier) keychain(ctx context.Context, pull config.ImagePull) (authn.Keychain, error) {
+	if v.clientset == nil || pull.Namespace == "" {
+		return authn.DefaultKeychain, nil
+	}
+	pullSecrets, err := kauth.New(ctx, v.clientset, kauth.Options{
+		Namespace:          pull.Namespace,
+		ServiceAccountName: pull.ServiceAccountName,
+		ImagePullSecrets:   pull.ImagePullSecrets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the image pull secrets of namespace %s: %w", pull.Namespace, err)
+	}
+	return authn.NewMultiKeychain(pullSecrets, authn.DefaultKeychain), nil
+}
+
+// pinnedReference returns the reference of the image by the digest, which keeps its tag for the version label of the
+// workloads.
+func pinnedReference(image, digest string) string {
+	name, _, _ := strings.Cut(image, "@")
+	return name + "@" + digest
+}
+
+func readPublicKey(file string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM public key in %s", file)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", file, err)
+	}
+	return key, nil
+}
+
+// readCertificateAuthorities returns the Fulcio certificate authorities of the PEM certificates of the file, one by
+// self-signed root, which the other certificates are the intermediates of.
+func readCertificateAuthorities(file string) ([]root.CertificateAuthority, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Fulcio roots: %w", err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Fulcio roots in %s: %w", file, err)
+	}
+	var roots, intermediates []*x509.Certificate
+	for _, cert := range certs {
+		if cert.CheckSignatureFrom(cert) == nil {
+			roots = append(roots, cert)
+		} else {
+			intermediates = append(intermediates, cert)
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no root certificate in %s", file)
+	}
+	var authorities []root.CertificateAuthority
+	for _, cert := range roots {
+		authorities = append(authorities, &root.FulcioCertificateAuthority{Root: cert, Intermediates: intermediates})
+	}
+	return authorities, nil
+}
@@ -0,0 +1,579 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package signature
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+const ociManifestType = "application/vnd.oci.image.manifest.v1+json"
+
+// The OIDC issuer extension of the Fulcio certificates.
+var oidcIssuerExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+// fakeRegistry serves the manifests and blobs of the repository test/image, handing out a pull token, to the
+// holders of its credentials when it has some.
+type fakeRegistry struct {
+	*httptest.Server
+	username, password string
+
+	mu        sync.Mutex
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *fakeRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		username, password, _ := req.BasicAuth()
+		if username != r.username || password != r.password || !strings.Contains(req.URL.Query().Get("scope"), "repository:test/image:pull") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer pull-token" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="fake"`, r.host()))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if req.URL.Path == "/v2/" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var content []byte
+	if reference, ok := strings.CutPrefix(req.URL.Path, "/v2/test/image/manifests/"); ok {
+		if content = r.manifests[reference]; content != nil {
+			w.Header().Set("Content-Type", ociManifestType)
+			w.Header().Set("Docker-Content-Digest", sha256Digest(content))
+		}
+	} else if digest, ok := strings.CutPrefix(req.URL.Path, "/v2/test/image/blobs/"); ok {
+		content = r.blobs[digest]
+	}
+	if content == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(content)
+	}
+}
+
+// host is the host of the registry, a name of the certificate of the test server rather than a loopback address,
+// which the registries are read from over plain HTTP.
+func (r *fakeRegistry) host() string {
+	u, _ := url.Parse(r.URL)
+	return "example.com:" + u.Port()
+}
+
+func (r *fakeRegistry) image() string {
+	return r.host() + "/test/image:1.0"
+}
+
+// transport connects to the test server whichever the host.
+func (r *fakeRegistry) transport() http.RoundTripper {
+	transport := r.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, r.Listener.Addr().String())
+	}
+	return transport
+}
+
+// push pushes the image manifest under its tag and returns its digest.
+func (r *fakeRegistry) push() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","layers":[]}`)
+	r.manifests["1.0"] = manifest
+	return sha256Digest(manifest)
+}
+
+// sign pushes the signature layer of the payload of the digest, whose annotations are completed by annotate.
+func (r *fakeRegistry) sign(digest string, annotate func(payload []byte) map[string]string) {
+	r.signPayload(digest, signedPayload(digest), annotate)
+}
+
+// signPayload pushes the signature layer of the payload under the signature tag of the digest.
+func (r *fakeRegistry) signPayload(digest string, payload []byte, annotate func(payload []byte) map[string]string) {
+	layer := map[string]any{
+		"mediaType":   "application/vnd.dev.cosign.simplesigning.v1+json",
+		"digest":      sha256Digest(payload),
+		"size":        len(payload),
+		"annotations": annotate(payload),
+	}
+	manifest, _ := json.Marshal(map[string]any{"schemaVersion": 2, "mediaType": ociManifestType, "layers": []any{layer}})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[sha256Digest(payload)] = payload
+	r.manifests[signatureTag(digest)] = manifest
+}
+
+// unsign removes the signatures of the digest.
+func (r *fakeRegistry) unsign(digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.manifests, signatureTag(digest))
+}
+
+func signatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func signedPayload(digest string) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"test/image"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+}
+
+func signWith(t *testing.T, key *ecdsa.PrivateKey) func(payload []byte) map[string]string {
+	return func(payload []byte) map[string]string {
+		return map[string]string{signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload))}
+	}
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	digest := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func writePublicKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return writePEM(t, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func writePEM(t *testing.T, name string, data []byte) string {
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, data, 0600))
+	return file
+}
+
+func TestVerifyWithPublicKey(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	digest := registry.push()
+	registry.sign(digest, signWith(t, key))
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	pinned, err := verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	require.NoError(t, err)
+	assert.Equal(t, registry.image()+"@"+digest, pinned)
+	pinned, err = verifier.Verify(context.Background(), registry.host()+"/test/image@"+digest, config.ImagePull{})
+	require.NoError(t, err)
+	assert.Equal(t, registry.host()+"/test/image@"+digest, pinned)
+
+	other, err := New(Options{PublicKeyFile: writePublicKey(t, generateKey(t))}, nil, registry.transport())
+	require.NoError(t, err)
+	_, err = other.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "failed to verify signature")
+}
+
+func TestVerifyUnsignedImage(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	registry.push()
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	pinned, err := verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "the image is not signed")
+	assert.ErrorContains(t, err, registry.image())
+	assert.Empty(t, pinned)
+}
+
+func TestVerifySignatureOfAnotherDigest(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	digest := registry.push()
+	// the signature of another image copied under the signature tag of the image
+	registry.signPayload(digest, signedPayload("sha256:other"), signWith(t, key))
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "the signature is for digest sha256:other")
+}
+
+func TestVerifyPrivateRegistry(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	registry.username, registry.password = "app", "secret"
+	digest := registry.push()
+	registry.sign(digest, signWith(t, key))
+
+	dockerConfig, err := json.Marshal(map[string]any{"auths": map[string]any{
+		registry.host(): map[string]string{"username": "app", "password": "secret"},
+	}})
+	require.NoError(t, err)
+	clientset := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "app"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "app", Namespace: "app"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		},
+	)
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, clientset, registry.transport())
+	require.NoError(t, err)
+
+	// the registry is read with the image pull secrets of the pods or of their service account
+	pinned, err := verifier.Verify(context.Background(), registry.image(), config.ImagePull{Namespace: "app", ImagePullSecrets: []string{"registry"}})
+	require.NoError(t, err)
+	assert.Equal(t, registry.image()+"@"+digest, pinned)
+	_, err = verifier.Verify(context.Background(), registry.host()+"/test/image@"+digest, config.ImagePull{Namespace: "app", ServiceAccountName: "app"})
+	assert.NoError(t, err)
+
+	// the verified digest holds for the pods without credentials, which can't pull it, unlike the digests of the tags
+	_, err = verifier.Verify(context.Background(), registry.host()+"/test/image@"+digest, config.ImagePull{Namespace: "other"})
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), registry.host()+"/test/image:2.0", config.ImagePull{Namespace: "other"})
+	assert.ErrorContains(t, err, "401 Unauthorized")
+}
+
+func TestVerifyCachesOutcome(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	digest := registry.push()
+	registry.sign(digest, signWith(t, key))
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	require.NoError(t, err)
+
+	// the signature is removed, the outcome is kept until it expires
+	registry.unsign(digest)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.NoError(t, err)
+	now = now.Add(verifiedTTL)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "the image is not signed")
+
+	// the failure is kept for the credentials it was read with only
+	registry.sign(digest, signWith(t, key))
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "the image is not signed")
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{Namespace: "app"})
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.NoError(t, err)
+}
+
+func TestVerifyCachesOutcomeByDigest(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	digest := registry.push()
+	registry.sign(digest, signWith(t, key))
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	require.NoError(t, err)
+
+	// another tag of the digest shares its outcome, the signature being removed
+	registry.mu.Lock()
+	registry.manifests["2.0"] = registry.manifests["1.0"]
+	registry.mu.Unlock()
+	registry.unsign(digest)
+	pinned, err := verifier.Verify(context.Background(), registry.host()+"/test/image:2.0", config.ImagePull{})
+	require.NoError(t, err)
+	assert.Equal(t, registry.host()+"/test/image:2.0@"+digest, pinned)
+
+	// the tag is moved to an unsigned manifest, which is verified once the digest of the tag expires
+	registry.mu.Lock()
+	registry.manifests["1.0"] = []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","layers":[{}]}`)
+	registry.mu.Unlock()
+	pinned, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	require.NoError(t, err)
+	assert.Equal(t, registry.image()+"@"+digest, pinned)
+	now = now.Add(verifiedTTL)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+	assert.ErrorContains(t, err, "the image is not signed")
+}
+
+func TestRefresh(t *testing.T) {
+	key := generateKey(t)
+	registry := newFakeRegistry(t)
+	digest := registry.push()
+	registry.sign(digest, signWith(t, key))
+
+	verifier, err := New(Options{PublicKeyFile: writePublicKey(t, key)}, nil, registry.transport())
+	require.NoError(t, err)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+	require.NoError(t, verifier.Refresh(context.Background(), []string{registry.image()}))
+
+	// the refreshed outcome is cached past the expiry of the first one
+	now = now.Add(RefreshInterval)
+	require.NoError(t, verifier.Refresh(context.Background(), []string{registry.image()}))
+	registry.unsign(digest)
+	now = now.Add(RefreshInterval)
+	_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{Namespace: "app"})
+	assert.NoError(t, err)
+
+	err = verifier.Refresh(context.Background(), []string{registry.image()})
+	assert.ErrorContains(t, err, "the image is not signed")
+	assert.ErrorContains(t, err, registry.image())
+}
+
+// keylessSigner issues Fulcio-like certificates and logs the signatures in a Rekor-like log.
+type keylessSigner struct {
+	t        *testing.T
+	rootKey  *ecdsa.PrivateKey
+	root     *x509.Certificate
+	rekorKey *ecdsa.PrivateKey
+}
+
+func newKeylessSigner(t *testing.T) *keylessSigner {
+	rootKey := generateKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &keylessSigner{t: t, rootKey: rootKey, root: root, rekorKey: generateKey(t)}
+}
+
+func (s *keylessSigner) options(identity, issuer string) Options {
+	der, err := x509.MarshalPKIXPublicKey(&s.rekorKey.PublicKey)
+	require.NoError(s.t, err)
+	return Options{
+		CertificateIdentity:   identity,
+		CertificateOIDCIssuer: issuer,
+		FulcioRootsFile:       writePEM(s.t, "roots.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.root.Raw})),
+		RekorPublicKeyFile:    writePEM(s.t, "rekor.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+}
+
+// sign signs the payload with a certificate of the identity valid for ten minutes from notBefore, logged at
+// integratedTime.
+func (s *keylessSigner) sign(identity, issuer string, notBefore, integratedTime time.Time) func(payload []byte) map[string]string {
+	return func(payload []byte) map[string]string {
+		key := generateKey(s.t)
+		issuerExtension, err := asn1.Marshal(issuer)
+		require.NoError(s.t, err)
+		identityURI, err := url.Parse(identity)
+		require.NoError(s.t, err)
+		template := &x509.Certificate{
+			SerialNumber:    big.NewInt(2),
+			NotBefore:       notBefore,
+			NotAfter:        notBefore.Add(10 * time.Minute),
+			KeyUsage:        x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+			URIs:            []*url.URL{identityURI},
+			ExtraExtensions: []pkix.Extension{{Id: oidcIssuerExtension, Value: issuerExtension}},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, s.root, &key.PublicKey, s.rootKey)
+		require.NoError(s.t, err)
+		cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+		signature := sign(s.t, key, payload)
+		hash := sha256.Sum256(payload)
+		body, err := json.Marshal(map[string]any{
+			"apiVersion": "0.0.1",
+			"kind":       "hashedrekord",
+			"spec": map[string]any{
+				"data":      map[string]any{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(hash[:])}},
+				"signature": map[string]any{"content": signature, "publicKey": map[string]any{"content": cert}},
+			},
+		})
+		require.NoError(s.t, err)
+		rekorDER, err := x509.MarshalPKIXPublicKey(&s.rekorKey.PublicKey)
+		require.NoError(s.t, err)
+		logID := sha256.Sum256(rekorDER)
+		// the keys of the canonical JSON of the payload of the SET are sorted
+		bundlePayload := map[string]any{
+			"body":           base64.StdEncoding.EncodeToString(body),
+			"integratedTime": integratedTime.Unix(),
+			"logID":          hex.EncodeToString(logID[:]),
+			"logIndex":       42,
+		}
+		canonical, err := json.Marshal(bundlePayload)
+		require.NoError(s.t, err)
+		bundle, err := json.Marshal(map[string]any{"SignedEntryTimestamp": sign(s.t, s.rekorKey, canonical), "Payload": bundlePayload})
+		require.NoError(s.t, err)
+
+		return map[string]string{
+			signatureAnnotation:   base64.StdEncoding.EncodeToString(signature),
+			certificateAnnotation: string(cert),
+			bundleAnnotation:      string(bundle),
+		}
+	}
+}
+
+func TestVerifyKeyless(t *testing.T) {
+	const (
+		identity = "https://github.com/aws/amazon-cloudwatch-agent-operator/.github/workflows/release.yml@refs/heads/main"
+		issuer   = "https://token.actions.githubusercontent.com"
+	)
+	signer := newKeylessSigner(t)
+	// the certificate expired long before the verification, when the signature was logged it was valid
+	signedAt := time.Now().Add(-30 * time.Minute)
+
+	for _, tt := range []struct {
+		name     string
+		sign     func(payload []byte) map[string]string
+		identity string
+		issuer   string
+		err      string
+	}{
+		{
+			name:     "verified",
+			sign:     signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute)),
+			identity: identity,
+			issuer:   issuer,
+		},
+		{
+			name:     "other identity",
+			sign:     signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute)),
+			identity: "https://github.com/other/repository/.github/workflows/release.yml@refs/heads/main",
+			issuer:   issuer,
+			err:      "failed to verify certificate identity",
+		},
+		{
+			name:     "other issuer",
+			sign:     signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute)),
+			identity: identity,
+			issuer:   "https://accounts.google.com",
+			err:      "failed to verify certificate identity",
+		},
+		{
+			name:     "logged after the expiry of the certificate",
+			sign:     signer.sign(identity, issuer, signedAt, signedAt.Add(20*time.Minute)),
+			identity: identity,
+			issuer:   issuer,
+			err:      "integrated time outside certificate validity",
+		},
+		{
+			name: "bundle of another signature",
+			sign: func(payload []byte) map[string]string {
+				annotations := signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute))(payload)
+				annotations[bundleAnnotation] = signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute))([]byte("other"))[bundleAnnotation]
+				return annotations
+			},
+			identity: identity,
+			issuer:   issuer,
+			err:      "transparency log signature does not match",
+		},
+		{
+			name: "bundle of another log",
+			sign: func(payload []byte) map[string]string {
+				return newKeylessSigner(t).sign(identity, issuer, signedAt, signedAt.Add(time.Minute))(payload)
+			},
+			identity: identity,
+			issuer:   issuer,
+			err:      "not enough verified log entries",
+		},
+		{
+			name: "no bundle",
+			sign: func(payload []byte) map[string]string {
+				annotations := signer.sign(identity, issuer, signedAt, signedAt.Add(time.Minute))(payload)
+				delete(annotations, bundleAnnotation)
+				return annotations
+			},
+			identity: identity,
+			issuer:   issuer,
+			err:      "the signature has no Rekor bundle",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newFakeRegistry(t)
+			registry.sign(registry.push(), tt.sign)
+
+			verifier, err := New(signer.options(tt.identity, tt.issuer), nil, registry.transport())
+			require.NoError(t, err)
+			_, err = verifier.Verify(context.Background(), registry.image(), config.ImagePull{})
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	key := writePublicKey(t, generateKey(t))
+
+	_, err := New(Options{}, nil, nil)
+	assert.Error(t, err)
+	_, err = New(Options{PublicKeyFile: key, CertificateIdentity: "ci@example.com"}, nil, nil)
+	assert.ErrorContains(t, err, "either with a public key or with a keyless identity")
+	_, err = New(Options{CertificateIdentity: "ci@example.com"}, nil, nil)
+	assert.ErrorContains(t, err, "the keyless verification needs")
+	_, err = New(Options{PublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")}, nil, nil)
+	assert.ErrorContains(t, err, "failed to read the public key")
+	_, err = New(Options{PublicKeyFile: writePEM(t, "invalid.pem", []byte("invalid"))}, nil, nil)
+	assert.ErrorContains(t, err, "no PEM public key")
+	_, err = New(Options{PublicKeyFile: key}, nil, nil)
+	assert.NoError(t, err)
+}
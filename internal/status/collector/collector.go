@@ -9,6 +9,8 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -19,15 +21,28 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
+const (
+	reasonRolloutComplete   = "RolloutComplete"
+	reasonRolloutInProgress = "RolloutInProgress"
+	reasonSidecar           = "Sidecar"
+)
+
 func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent) error {
-	if changed.Status.Version == "" {
-		// a version is not set, otherwise let the upgrade mechanism take care of it!
+	if managed := collector.ManagedVersion(*changed); managed != "" {
+		changed.Status.Version = managed
+	} else if changed.Status.Version == "" || changed.Spec.Image == "" {
+		// instances following the operator move to its agent version, custom images keep the one first recorded
 		changed.Status.Version = version.AmazonCloudWatchAgent()
 	}
 	mode := changed.Spec.Mode
 	if mode != v1alpha1.ModeDeployment && mode != v1alpha1.ModeStatefulSet {
 		changed.Status.Scale.Replicas = 0
 		changed.Status.Scale.Selector = ""
+		if mode == v1alpha1.ModeDaemonSet {
+			return updateDaemonSetStatus(ctx, cli, changed)
+		}
+		// the rollout of the pods the agent is injected into is not driven by the operator
+		setCondition(changed, v1alpha1.ConditionTypeAvailable, metav1.ConditionTrue, reasonSidecar, "the agent is injected into the pods requesting it")
 		return nil
 	}
 
@@ -51,6 +66,7 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 	var readyReplicas int32
 	var statusReplicas string
 	var statusImage string
+	var rollout rolloutStatus
 
 	switch mode { // nolint:exhaustive
 	case v1alpha1.ModeDeployment:
@@ -61,7 +77,13 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		statusImage = agentImage(obj.Spec.Template.Spec)
+		rollout = rolloutStatus{
+			observed:  obj.Status.ObservedGeneration >= obj.Generation,
+			desired:   desiredReplicas(obj.Spec.Replicas),
+			updated:   obj.Status.UpdatedReplicas,
+			available: obj.Status.AvailableReplicas,
+		}
 
 	case v1alpha1.ModeStatefulSet:
 		obj := &appsv1.StatefulSet{}
@@ -71,18 +93,83 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
-
-	case v1alpha1.ModeDaemonSet:
-		obj := &appsv1.DaemonSet{}
-		if err := cli.Get(ctx, objKey, obj); err != nil {
-			return fmt.Errorf("failed to get daemonSet status.replicas: %w", err)
+		statusImage = agentImage(obj.Spec.Template.Spec)
+		rollout = rolloutStatus{
+			observed:  obj.Status.ObservedGeneration >= obj.Generation,
+			desired:   desiredReplicas(obj.Spec.Replicas),
+			updated:   obj.Status.UpdatedReplicas,
+			available: obj.Status.AvailableReplicas,
 		}
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
 	}
 	changed.Status.Scale.Replicas = replicas
 	changed.Status.Image = statusImage
 	changed.Status.Scale.StatusReplicas = statusReplicas
+	rollout.setAvailable(changed)
 
 	return nil
 }
+
+// updateDaemonSetStatus reports the image of the default DaemonSet and the rollout of every DaemonSet of the
+// instance, node groups included.
+func updateDaemonSetStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent) error {
+	rollout := rolloutStatus{observed: true}
+	for i, name := range collector.DaemonSetNames(*changed) {
+		obj := &appsv1.DaemonSet{}
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: changed.GetNamespace(), Name: name}, obj); err != nil {
+			return fmt.Errorf("failed to get daemonSet %s: %w", name, err)
+		}
+		if i == 0 {
+			changed.Status.Image = agentImage(obj.Spec.Template.Spec)
+		}
+		rollout.observed = rollout.observed && obj.Status.ObservedGeneration >= obj.Generation
+		rollout.desired += obj.Status.DesiredNumberScheduled
+		rollout.updated += obj.Status.UpdatedNumberScheduled
+		rollout.available += obj.Status.NumberAvailable
+	}
+	rollout.setAvailable(changed)
+	return nil
+}
+
+// rolloutStatus sums up how far the agent workloads are in rolling out their current spec.
+type rolloutStatus struct {
+	observed  bool
+	desired   int32
+	updated   int32
+	available int32
+}
+
+func (r rolloutStatus) setAvailable(changed *v1alpha1.AmazonCloudWatchAgent) {
+	message := fmt.Sprintf("%d of %d pods run the current spec, %d are available", r.updated, r.desired, r.available)
+	if r.observed && r.updated == r.desired && r.available == r.desired {
+		setCondition(changed, v1alpha1.ConditionTypeAvailable, metav1.ConditionTrue, reasonRolloutComplete, message)
+		return
+	}
+	setCondition(changed, v1alpha1.ConditionTypeAvailable, metav1.ConditionFalse, reasonRolloutInProgress, message)
+}
+
+func setCondition(changed *v1alpha1.AmazonCloudWatchAgent, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: changed.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// agentImage returns the image of the agent container, which comes after the additional containers.
+func agentImage(spec corev1.PodSpec) string {
+	for _, container := range spec.Containers {
+		if container.Name == naming.Container() {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
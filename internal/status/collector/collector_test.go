@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func daemonSet(name string, desired, updated, available int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "fluent-bit", Image: "fluent-bit:3"},
+						{Name: naming.Container(), Image: "cloudwatch-agent:1.300"},
+					},
+				},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: desired,
+			UpdatedNumberScheduled: updated,
+			NumberAvailable:        available,
+		},
+	}
+}
+
+func TestUpdateCollectorStatusDaemonSet(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 5},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:       v1alpha1.ModeDaemonSet,
+			NodeGroups: []v1alpha1.NodeGroup{{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}}},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithObjects(daemonSet("agent", 3, 3, 3), daemonSet("agent-gpu", 2, 1, 2)).Build()
+	require.NoError(t, UpdateCollectorStatus(context.Background(), cli, agent))
+
+	assert.Equal(t, "cloudwatch-agent:1.300", agent.Status.Image)
+	available := meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, metav1.ConditionFalse, available.Status)
+	assert.Equal(t, reasonRolloutInProgress, available.Reason)
+	assert.Equal(t, "4 of 5 pods run the current spec, 5 are available", available.Message)
+	assert.Equal(t, int64(5), available.ObservedGeneration)
+
+	cli = fake.NewClientBuilder().WithObjects(daemonSet("agent", 3, 3, 3), daemonSet("agent-gpu", 2, 2, 2)).Build()
+	require.NoError(t, UpdateCollectorStatus(context.Background(), cli, agent))
+	assert.True(t, meta.IsStatusConditionTrue(agent.Status.Conditions, v1alpha1.ConditionTypeAvailable))
+}
+
+func TestUpdateCollectorStatusVersion(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		Spec:   v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeSidecar, Version: "1.300049.1"},
+		Status: v1alpha1.AmazonCloudWatchAgentStatus{Version: "1.300030.0"},
+	}
+	require.NoError(t, UpdateCollectorStatus(context.Background(), fake.NewClientBuilder().Build(), agent))
+	assert.Equal(t, "1.300049.1", agent.Status.Version)
+
+	agent.Spec.Version = ""
+	agent.Spec.UpgradeStrategy = v1alpha1.UpgradeStrategyNone
+	require.NoError(t, UpdateCollectorStatus(context.Background(), fake.NewClientBuilder().Build(), agent))
+	assert.Equal(t, "1.300049.1", agent.Status.Version)
+
+	agent.Spec.UpgradeStrategy = v1alpha1.UpgradeStrategyAutomatic
+	require.NoError(t, UpdateCollectorStatus(context.Background(), fake.NewClientBuilder().Build(), agent))
+	assert.Equal(t, version.AmazonCloudWatchAgent(), agent.Status.Version)
+}
+
+func TestHandleReconcileStatus(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 3},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeSidecar},
+	}
+	cli := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(agent).WithStatusSubresource(agent).Build()
+	params := manifests.Params{
+		Client:   cli,
+		Recorder: record.NewFakeRecorder(10),
+		OtelCol:  *agent,
+	}
+
+	reconcileErr := &InvalidConfigError{Err: errors.New("invalid character 'a' looking for beginning of value")}
+	_, err := HandleReconcileStatus(context.Background(), logr.Discard(), params, reconcileErr)
+	assert.ErrorIs(t, err, reconcileErr)
+
+	got := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, cli.Get(context.Background(), client.ObjectKeyFromObject(agent), got))
+	assert.Equal(t, int64(3), got.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.ConditionTypeConfigValid))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeDegraded))
+
+	params.OtelCol = *got
+	_, err = HandleReconcileStatus(context.Background(), logr.Discard(), params, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, cli.Get(context.Background(), client.ObjectKeyFromObject(agent), got))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeConfigValid))
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.ConditionTypeDegraded))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeAvailable))
+}
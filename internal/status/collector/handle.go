@@ -5,12 +5,15 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
 
@@ -21,22 +24,52 @@ const (
 	reasonError         = "Error"
 	reasonStatusFailure = "StatusFailure"
 	reasonInfo          = "Info"
+
+	reasonInvalidConfig  = "InvalidConfig"
+	reasonReconcileError = "ReconcileError"
+	reasonRendered       = "Rendered"
+	reasonReconciled     = "Reconciled"
 )
 
+// InvalidConfigError is a reconcile error caused by the configuration of the instance, which is reported through
+// the ConfigValid condition.
+type InvalidConfigError struct {
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
 // HandleReconcileStatus handles updating the status of the CRDs managed by the operator.
-// TODO: make the status more useful https://github.com/open-telemetry/opentelemetry-operator/issues/1972
 func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, err error) (ctrl.Result, error) {
 	log.V(2).Info("updating collector status")
+	changed := params.OtelCol.DeepCopy()
+	changed.Status.ObservedGeneration = changed.Generation
 	if err != nil {
 		params.Recorder.Event(&params.OtelCol, eventTypeWarning, reasonError, err.Error())
+		var configErr *InvalidConfigError
+		if errors.As(err, &configErr) {
+			setCondition(changed, v1alpha1.ConditionTypeConfigValid, metav1.ConditionFalse, reasonInvalidConfig, err.Error())
+		}
+		setCondition(changed, v1alpha1.ConditionTypeDegraded, metav1.ConditionTrue, reasonReconcileError, err.Error())
+		// the status is best effort here, the reconcile error is what gets the request retried
+		if statusErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.OtelCol)); statusErr != nil {
+			log.Error(statusErr, "failed to record the reconcile error in the AmazonCloudWatchAgent status")
+		}
 		return ctrl.Result{}, err
 	}
-	changed := params.OtelCol.DeepCopy()
 	statusErr := UpdateCollectorStatus(ctx, params.Client, changed)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
 		return ctrl.Result{}, statusErr
 	}
+	setCondition(changed, v1alpha1.ConditionTypeConfigValid, metav1.ConditionTrue, reasonRendered, "the configuration was rendered into the agent resources")
+	setCondition(changed, v1alpha1.ConditionTypeDegraded, metav1.ConditionFalse, reasonReconciled, "the agent resources are up to date")
 	statusPatch := client.MergeFrom(&params.OtelCol)
 	if err := params.Client.Status().Patch(ctx, changed, statusPatch); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", err)
@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storagemigration rewrites the stored resources of the CustomResourceDefinitions of the operator in their
+// storage version, so that the versions which are no longer stored can be removed by the next upgrades.
+package storagemigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// pageSize is the number of resources listed at once.
+	pageSize = 100
+
+	// maxReportedResources is the number of resources which failed to be rewritten named by the status condition.
+	maxReportedResources = 10
+
+	// conditionStorageVersionMigrated is the condition of the CustomResourceDefinitions reporting whether all their
+	// resources were rewritten in the storage version.
+	conditionStorageVersionMigrated = "StorageVersionMigrated"
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// CustomResourceDefinitions are the names of the CustomResourceDefinitions of the operator.
+var CustomResourceDefinitions = []string{
+	"amazoncloudwatchagents.cloudwatch.aws.amazon.com",
+	"instrumentations.cloudwatch.aws.amazon.com",
+	"dcgmexporters.cloudwatch.aws.amazon.com",
+	"neuronmonitors.cloudwatch.aws.amazon.com",
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=get;update;patch
+
+var _ manager.LeaderElectionRunnable = (*Migrator)(nil)
+
+// Migrator migrates the stored resources of CustomResourceDefinitions once, when the leader starts. The
+// CustomResourceDefinitions are read unstructured, so that the client doesn't need their scheme.
+type Migrator struct {
+	client client.Client
+	crds   []string
+	log    logr.Logger
+}
+
+// New returns the migrator of the stored resources of the CustomResourceDefinitions.
+func New(c client.Client, crds []string, log logr.Logger) *Migrator {
+	return &Migrator{client: c, crds: crds, log: log}
+}
+
+// NeedLeaderElection is true, as the resources only need to be migrated once.
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start migrates the CustomResourceDefinitions. The failed migrations are retried on the next start of a leader.
+func (m *Migrator) Start(ctx context.Context) error {
+	if err := m.Migrate(ctx); err != nil {
+		m.log.Error(err, "failed to migrate the stored resources to their storage version")
+	}
+	return nil
+}
+
+// Migrate migrates each of the CustomResourceDefinitions storing resources in other versions than their storage
+// version. The CustomResourceDefinitions which are not installed are skipped.
+func (m *Migrator) Migrate(ctx context.Context) error {
+	var errs []error
+	for _, name := range m.crds {
+		if err := m.migrate(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to migrate the CustomResourceDefinition %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Migrator) migrate(ctx context.Context, name string) error {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	if err := m.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	storageVersion, err := storageVersion(crd)
+	if err != nil {
+		return err
+	}
+	storedVersions, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if slices.Equal(storedVersions, []string{storageVersion}) {
+		return nil
+	}
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	gvk := schema.GroupVersionKind{Group: group, Version: storageVersion, Kind: kind}
+	m.log.Info("migrating the stored resources to their storage version", "crd", name, "storedVersions", storedVersions, "storageVersion", storageVersion)
+	count, failed, err := m.rewrite(ctx, gvk)
+	if err != nil {
+		return err
+	}
+
+	// the versions which are no longer stored are only removed once all the resources were rewritten, the status
+	// reports the resources which still have to be fixed otherwise
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+		if len(failed) == 0 {
+			if err := unstructured.SetNestedStringSlice(crd.Object, []string{storageVersion}, "status", "storedVersions"); err != nil {
+				return err
+			}
+		}
+		if err := setMigratedCondition(crd, storageVersion, failed); err != nil {
+			return err
+		}
+		return m.client.Status().Update(ctx, crd)
+	})
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of the %d resources failed to be rewritten in the storage version %s, the versions they are stored in are kept", len(failed), count+len(failed), storageVersion)
+	}
+	m.log.Info("migrated the stored resources to their storage version", "crd", name, "resources", count, "storageVersion", storageVersion)
+	return nil
+}
+
+// rewrite updates all the resources of the kind without changing them, which has the API server store them in the
+// storage version. It returns the number of resources which were rewritten, and the namespaced names of the ones
+// which failed to be rewritten, such as the ones which are invalid in the storage version, which are logged and
+// skipped so that the other resources are still migrated.
+func (m *Migrator) rewrite(ctx context.Context, gvk schema.GroupVersionKind) (int, []string, error) {
+	count := 0
+	var failed []string
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	for {
+		if err := m.client.List(ctx, list, client.Limit(pageSize), client.Continue(list.GetContinue())); err != nil {
+			return count, failed, err
+		}
+		for i := range list.Items {
+			err := m.client.Update(ctx, &list.Items[i])
+			// the resources which changed since they were listed are already stored in the storage version
+			if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+				key := client.ObjectKeyFromObject(&list.Items[i]).String()
+				m.log.Error(err, "failed to rewrite the resource in the storage version", "kind", gvk.Kind, "resource", key, "storageVersion", gvk.Version)
+				failed = append(failed, key)
+				continue
+			}
+			count++
+		}
+		if list.GetContinue() == "" {
+			return count, failed, nil
+		}
+	}
+}
+
+// setMigratedCondition sets the condition of the CustomResourceDefinition reporting the resources which failed to be
+// rewritten in the storage version, keeping its transition time while its status is unchanged.
+func setMigratedCondition(crd *unstructured.Unstructured, storageVersion string, failed []string) error {
+	condition := map[string]interface{}{
+		"type":    conditionStorageVersionMigrated,
+		"status":  "True",
+		"reason":  "Migrated",
+		"message": fmt.Sprintf("all the resources are stored in %s", storageVersion),
+	}
+	if len(failed) > 0 {
+		names := failed
+		if len(names) > maxReportedResources {
+			names = append(slices.Clone(names[:maxReportedResources]), "...")
+		}
+		condition["status"] = "False"
+		condition["reason"] = "InvalidResources"
+		condition["message"] = fmt.Sprintf("%d resources failed to be rewritten in %s and must be fixed: %s", len(failed), storageVersion, strings.Join(names, ", "))
+	}
+
+	conditions, _, err := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(conditions, func(c interface{}) bool {
+		fields, ok := c.(map[string]interface{})
+		return ok && fields["type"] == conditionStorageVersionMigrated
+	})
+	condition["lastTransitionTime"] = time.Now().UTC().Format(time.RFC3339)
+	if index < 0 {
+		conditions = append(conditions, condition)
+	} else {
+		if previous, ok := conditions[index].(map[string]interface{}); ok && previous["status"] == condition["status"] && previous["lastTransitionTime"] != nil {
+			condition["lastTransitionTime"] = previous["lastTransitionTime"]
+		}
+		conditions[index] = condition
+	}
+	return unstructured.SetNestedSlice(crd.Object, conditions, "status", "conditions")
+}
+
+// storageVersion returns the storage version of the CustomResourceDefinition.
+func storageVersion(crd *unstructured.Unstructured) (string, error) {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, version := range versions {
+		fields, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := fields["storage"].(bool); storage {
+			name, _ := fields["name"].(string)
+			return name, nil
+		}
+	}
+	return "", errors.New("no storage version")
+}
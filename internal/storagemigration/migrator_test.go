@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storagemigration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const agentsCRD = "amazoncloudwatchagents.cloudwatch.aws.amazon.com"
+
+func newCRD(storedVersions ...interface{}) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": "cloudwatch.aws.amazon.com",
+			"names": map[string]interface{}{"kind": "AmazonCloudWatchAgent"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true},
+				map[string]interface{}{"name": "v1beta1", "served": true, "storage": false},
+			},
+		},
+		"status": map[string]interface{}{"storedVersions": storedVersions},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(agentsCRD)
+	return crd
+}
+
+func newTestClient(t *testing.T, objs ...client.Object) client.WithWatch {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(v1alpha1.GroupVersion.WithKind("AmazonCloudWatchAgent"), meta.RESTScopeNamespace)
+	mapper.Add(crdGVK, meta.RESTScopeRoot)
+	return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).
+		WithStatusSubresource(newCRD()).Build()
+}
+
+func getStoredVersions(t *testing.T, c client.Client) []string {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: agentsCRD}, crd))
+	storedVersions, _, err := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	require.NoError(t, err)
+	return storedVersions
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	objs := []client.Object{newCRD("v1beta1", "v1alpha1")}
+	for i := 0; i < pageSize+1; i++ {
+		objs = append(objs, &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("agent-%d", i)}})
+	}
+	c := newTestClient(t, objs...)
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	require.NoError(t, c.List(ctx, agents))
+	resourceVersions := map[string]string{}
+	for _, agent := range agents.Items {
+		resourceVersions[agent.Name] = agent.ResourceVersion
+	}
+
+	require.NoError(t, New(c, []string{agentsCRD, "neuronmonitors.cloudwatch.aws.amazon.com"}, logr.Discard()).Migrate(ctx))
+	assert.Equal(t, []string{"v1alpha1"}, getStoredVersions(t, c))
+	// every resource was rewritten
+	require.NoError(t, c.List(ctx, agents))
+	require.Len(t, agents.Items, pageSize+1)
+	for _, agent := range agents.Items {
+		assert.NotEqual(t, resourceVersions[agent.Name], agent.ResourceVersion, agent.Name)
+	}
+}
+
+func TestMigrateInvalidResources(t *testing.T) {
+	ctx := context.Background()
+	objs := []client.Object{newCRD("v1beta1", "v1alpha1")}
+	for i := 0; i < 3; i++ {
+		objs = append(objs, &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("agent-%d", i)}})
+	}
+	invalid := true
+	c := interceptor.NewClient(newTestClient(t, objs...), interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if invalid && obj.GetName() == "agent-1" {
+				return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("AmazonCloudWatchAgent").GroupKind(), obj.GetName(), nil)
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	})
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	require.NoError(t, c.List(ctx, agents))
+	resourceVersions := map[string]string{}
+	for _, agent := range agents.Items {
+		resourceVersions[agent.Name] = agent.ResourceVersion
+	}
+
+	// the invalid resource is skipped, and keeps the versions it may be stored in
+	err := New(c, []string{agentsCRD}, logr.Discard()).Migrate(ctx)
+	assert.ErrorContains(t, err, "1 of the 3 resources failed to be rewritten in the storage version v1alpha1")
+	assert.Equal(t, []string{"v1beta1", "v1alpha1"}, getStoredVersions(t, c))
+	require.NoError(t, c.List(ctx, agents))
+	for _, agent := range agents.Items {
+		assert.Equal(t, agent.Name == "agent-1", resourceVersions[agent.Name] == agent.ResourceVersion, agent.Name)
+	}
+	condition := getMigratedCondition(t, c)
+	assert.Equal(t, "False", condition["status"])
+	assert.Equal(t, "InvalidResources", condition["reason"])
+	assert.Equal(t, "1 resources failed to be rewritten in v1alpha1 and must be fixed: default/agent-1", condition["message"])
+
+	// the fixed resource is rewritten by the next migration
+	invalid = false
+	require.NoError(t, New(c, []string{agentsCRD}, logr.Discard()).Migrate(ctx))
+	assert.Equal(t, []string{"v1alpha1"}, getStoredVersions(t, c))
+	condition = getMigratedCondition(t, c)
+	assert.Equal(t, "True", condition["status"])
+	assert.Equal(t, "all the resources are stored in v1alpha1", condition["message"])
+}
+
+func getMigratedCondition(t *testing.T, c client.Client) map[string]interface{} {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: agentsCRD}, crd))
+	conditions, _, err := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+	return conditions[0].(map[string]interface{})
+}
+
+func TestMigrateUpToDate(t *testing.T) {
+	ctx := context.Background()
+	agent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "agent"}}
+	c := newTestClient(t, newCRD("v1alpha1"), agent)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), agent))
+	resourceVersion := agent.ResourceVersion
+	require.NoError(t, New(c, []string{agentsCRD}, logr.Discard()).Migrate(ctx))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), agent))
+	assert.Equal(t, resourceVersion, agent.ResourceVersion)
+}
+
+func TestStorageVersion(t *testing.T) {
+	version, err := storageVersion(newCRD())
+	require.NoError(t, err)
+	assert.Equal(t, "v1alpha1", version)
+
+	_, err = storageVersion(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const diagnosticsShutdownTimeout = 5 * time.Second
+
+var publishDiagnostics sync.Once
+
+// NewDiagnosticsServer returns the server of the pprof profiles, under /debug/pprof/, and of the expvar variables,
+// under /debug/vars, of the operator. An address without host binds to the loopback interface only, so that the
+// profiles are only reachable with kubectl port-forward unless a host is explicitly given.
+func NewDiagnosticsServer(addr string, version interface{}) (*manager.Server, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diagnostics address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	publishDiagnostics.Do(func() {
+		expvar.Publish("version", expvar.Func(func() any { return version }))
+		expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+		expvar.Publish("gomemlimit", expvar.Func(func() any { return debug.SetMemoryLimit(-1) }))
+	})
+
+	timeout := diagnosticsShutdownTimeout
+	return &manager.Server{
+		Name: "diagnostics",
+		Server: &http.Server{
+			Addr:              net.JoinHostPort(host, port),
+			Handler:           diagnosticsHandler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		ShutdownTimeout: &timeout,
+	}, nil
+}
+
+func diagnosticsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
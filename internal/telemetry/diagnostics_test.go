@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiagnosticsServer(t *testing.T) {
+	_, err := NewDiagnosticsServer("6060", "1.0.0")
+	assert.ErrorContains(t, err, `invalid diagnostics address "6060"`)
+
+	server, err := NewDiagnosticsServer(":6060", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:6060", server.Server.Addr)
+
+	server, err = NewDiagnosticsServer("0.0.0.0:6060", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:6060", server.Server.Addr)
+
+	rec := httptest.NewRecorder()
+	server.Server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	vars := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	assert.Equal(t, "1.0.0", vars["version"])
+	assert.Contains(t, vars, "goroutines")
+	assert.Contains(t, vars, "memstats")
+
+	rec = httptest.NewRecorder()
+	server.Server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,216 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// DefaultEMFNamespace is the CloudWatch namespace of the metrics of the operator.
+	DefaultEMFNamespace = "CWAgentOperator"
+	// DefaultEMFLogGroupName is the log group the agent writes the EMF events of the operator to.
+	DefaultEMFLogGroupName = "/aws/cwagent-operator/metrics"
+	// DefaultEMFInterval is the interval between two reports of the metrics of the operator.
+	DefaultEMFInterval = time.Minute
+
+	emfDialTimeout = 5 * time.Second
+)
+
+// emfReportedFamilies are the metric families of controller-runtime reported next to the ones of the operator.
+var emfReportedFamilies = map[string]bool{
+	"controller_runtime_reconcile_total":        true,
+	"controller_runtime_reconcile_errors_total": true,
+}
+
+// EMFOptions configures the report of the metrics of the operator in the CloudWatch embedded metric format.
+type EMFOptions struct {
+	// Endpoint is the EMF listener of the agent, such as tcp://cloudwatch-agent.amazon-cloudwatch:25888.
+	Endpoint string
+	// Namespace is the CloudWatch namespace of the metrics.
+	Namespace string
+	// LogGroupName is the log group of the EMF events.
+	LogGroupName string
+	// Interval is the interval between two reports.
+	Interval time.Duration
+}
+
+var _ manager.Runnable = (*EMFReporter)(nil)
+
+// EMFReporter periodically sends the counters and gauges of the operator as EMF events to the EMF listener of an
+// agent, which publishes them to CloudWatch. The counters are reported as their increase since the previous report.
+type EMFReporter struct {
+	gatherer prometheus.Gatherer
+	opts     EMFOptions
+	network  string
+	address  string
+	log      logr.Logger
+
+	// previous holds the values of the counters at the previous report, by metric family and labels.
+	previous map[string]float64
+}
+
+// NewEMFReporter returns the reporter of the metrics of the given gatherer.
+func NewEMFReporter(gatherer prometheus.Gatherer, opts EMFOptions, log logr.Logger) (*EMFReporter, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMF endpoint %q: %w", opts.Endpoint, err)
+	}
+	if endpoint.Scheme != "tcp" && endpoint.Scheme != "udp" {
+		return nil, fmt.Errorf("invalid EMF endpoint %q: the scheme has to be tcp or udp", opts.Endpoint)
+	}
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid EMF endpoint %q: the host is missing", opts.Endpoint)
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultEMFNamespace
+	}
+	if opts.LogGroupName == "" {
+		opts.LogGroupName = DefaultEMFLogGroupName
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultEMFInterval
+	}
+	return &EMFReporter{
+		gatherer: gatherer,
+		opts:     opts,
+		network:  endpoint.Scheme,
+		address:  endpoint.Host,
+		log:      log,
+		previous: map[string]float64{},
+	}, nil
+}
+
+// NeedLeaderElection is false, as every replica of the operator reports the admissions it handled.
+func (r *EMFReporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reports the metrics at every interval until the context is done.
+func (r *EMFReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.report(ctx); err != nil {
+				r.log.Error(err, "failed to report the operator metrics", "endpoint", r.opts.Endpoint)
+			}
+		}
+	}
+}
+
+// report sends one EMF event per metric and set of labels to the endpoint.
+func (r *EMFReporter) report(ctx context.Context) error {
+	events, err := r.events(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	dialer := net.Dialer{Timeout: emfDialTimeout}
+	conn, err := dialer.DialContext(ctx, r.network, r.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, event := range events {
+		// each event is a datagram of its own with UDP, and a line of the stream with TCP
+		if _, err := conn.Write(append(event, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// events gathers the metrics and returns their EMF events.
+func (r *EMFReporter) events(now time.Time) ([][]byte, error) {
+	families, err := r.gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather the operator metrics: %w", err)
+	}
+
+	var events [][]byte
+	for _, family := range families {
+		name := family.GetName()
+		if !strings.HasPrefix(name, Namespace+"_") && !emfReportedFamilies[name] {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			var value float64
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				key := seriesKey(name, m.GetLabel())
+				total := m.GetCounter().GetValue()
+				value = total - r.previous[key]
+				r.previous[key] = total
+			case dto.MetricType_GAUGE:
+				value = m.GetGauge().GetValue()
+			default:
+				continue
+			}
+			event, err := json.Marshal(r.event(now, name, m.GetLabel(), value, family.GetType() == dto.MetricType_COUNTER))
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// event returns the EMF event of a single value, whose labels are the dimensions of the metric.
+func (r *EMFReporter) event(now time.Time, name string, labels []*dto.LabelPair, value float64, counter bool) map[string]interface{} {
+	dimensions := []string{}
+	event := map[string]interface{}{}
+	for _, label := range labels {
+		if label.GetValue() == "" {
+			continue
+		}
+		dimensions = append(dimensions, label.GetName())
+		event[label.GetName()] = label.GetValue()
+	}
+	sort.Strings(dimensions)
+
+	metric := map[string]string{"Name": name}
+	if counter {
+		metric["Unit"] = "Count"
+	}
+	event[name] = value
+	event["_aws"] = map[string]interface{}{
+		"Timestamp":    now.UnixMilli(),
+		"LogGroupName": r.opts.LogGroupName,
+		"CloudWatchMetrics": []map[string]interface{}{{
+			"Namespace":  r.opts.Namespace,
+			"Dimensions": [][]string{dimensions},
+			"Metrics":    []map[string]string{metric},
+		}},
+	}
+	return event
+}
+
+// seriesKey identifies a series of a metric family by its labels.
+func seriesKey(name string, labels []*dto.LabelPair) string {
+	key := name
+	for _, label := range labels {
+		key += "," + label.GetName() + "=" + label.GetValue()
+	}
+	return key
+}
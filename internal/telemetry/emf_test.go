@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEMFReporter(t *testing.T) {
+	for _, endpoint := range []string{"cloudwatch-agent:25888", "http://cloudwatch-agent:25888", "tcp://"} {
+		_, err := NewEMFReporter(prometheus.NewRegistry(), EMFOptions{Endpoint: endpoint}, logr.Discard())
+		assert.ErrorContains(t, err, "invalid EMF endpoint", endpoint)
+	}
+
+	r, err := NewEMFReporter(prometheus.NewRegistry(), EMFOptions{Endpoint: "udp://cloudwatch-agent:25888"}, logr.Discard())
+	require.NoError(t, err)
+	assert.Equal(t, "udp", r.network)
+	assert.Equal(t, "cloudwatch-agent:25888", r.address)
+	assert.Equal(t, EMFOptions{Endpoint: "udp://cloudwatch-agent:25888", Namespace: DefaultEMFNamespace, LogGroupName: DefaultEMFLogGroupName, Interval: DefaultEMFInterval}, r.opts)
+}
+
+func TestEMFReporterReport(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	injections := prometheus.NewCounterVec(prometheus.CounterOpts{Name: Namespace + "_pod_mutations_total"}, []string{"mutator", "result"})
+	agents := prometheus.NewGauge(prometheus.GaugeOpts{Name: Namespace + "_managed_agents"})
+	ignored := prometheus.NewCounter(prometheus.CounterOpts{Name: "go_gc_cycles_total"})
+	registry.MustRegister(injections, agents, ignored)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	received := make(chan map[string]interface{}, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				event := map[string]interface{}{}
+				if json.Unmarshal(scanner.Bytes(), &event) == nil {
+					received <- event
+				}
+			}
+			conn.Close()
+		}
+	}()
+	receive := func() map[string]interface{} {
+		select {
+		case event := <-received:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no EMF event received")
+			return nil
+		}
+	}
+
+	r, err := NewEMFReporter(registry, EMFOptions{Endpoint: "tcp://" + listener.Addr().String(), Namespace: "Test"}, logr.Discard())
+	require.NoError(t, err)
+
+	injections.WithLabelValues("instrumentation", "injected").Add(3)
+	agents.Set(2)
+	require.NoError(t, r.report(context.Background()))
+
+	agentsEvent := receive()
+	assert.Equal(t, 2.0, agentsEvent[Namespace+"_managed_agents"])
+	injectionsEvent := receive()
+	assert.Equal(t, 3.0, injectionsEvent[Namespace+"_pod_mutations_total"])
+	assert.Equal(t, "instrumentation", injectionsEvent["mutator"])
+	metadata := injectionsEvent["_aws"].(map[string]interface{})
+	assert.Equal(t, DefaultEMFLogGroupName, metadata["LogGroupName"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"Namespace":  "Test",
+		"Dimensions": []interface{}{[]interface{}{"mutator", "result"}},
+		"Metrics":    []interface{}{map[string]interface{}{"Name": Namespace + "_pod_mutations_total", "Unit": "Count"}},
+	}}, metadata["CloudWatchMetrics"])
+
+	// the counters are reported as their increase since the previous report
+	injections.WithLabelValues("instrumentation", "injected").Add(2)
+	require.NoError(t, r.report(context.Background()))
+	assert.Equal(t, 2.0, receive()[Namespace+"_managed_agents"])
+	assert.Equal(t, 2.0, receive()[Namespace+"_pod_mutations_total"])
+	assert.Empty(t, received)
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package telemetry holds the metrics the operator reports about itself, next to the reconcile and webhook metrics
+// of controller-runtime.
+package telemetry
+
+import (
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Namespace prefixes the names of the metrics of the operator.
+const Namespace = "cloudwatch_agent_operator"
+
+var (
+	admissionValidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "admission_validations_total",
+		Help:      "Number of objects validated by the admission webhooks of the operator, by kind, operation and result.",
+	}, []string{"kind", "operation", "result"})
+
+	validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "config_validation_failures_total",
+		Help:      "Number of objects rejected by the admission webhooks of the operator, by kind and section of the spec.",
+	}, []string{"kind", "section"})
+
+	podMutations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "pod_mutations_total",
+		Help:      "Number of pods handled by the mutators of the pod webhook, by mutator and result.",
+	}, []string{"mutator", "result"})
+)
+
+// The results of the pod mutations.
+const (
+	MutationInjected  = "injected"
+	MutationUnchanged = "unchanged"
+	MutationFailed    = "failed"
+)
+
+func init() {
+	metrics.Registry.MustRegister(admissionValidations, validationFailures, podMutations)
+}
+
+// specSection matches the section of the spec named by the validation errors of the webhooks.
+var specSection = regexp.MustCompile(`^the OpenTelemetry Spec (\S+) configuration`)
+
+// RecordValidation records the result of the validation of an object of the given kind by an admission webhook.
+func RecordValidation(kind, operation string, err error) {
+	if err == nil {
+		admissionValidations.WithLabelValues(kind, operation, "allowed").Inc()
+		return
+	}
+	admissionValidations.WithLabelValues(kind, operation, "denied").Inc()
+	section := "other"
+	if match := specSection.FindStringSubmatch(err.Error()); match != nil {
+		section = match[1]
+	}
+	validationFailures.WithLabelValues(kind, section).Inc()
+}
+
+// RecordPodMutation records the result of the mutation of a pod by the given mutator of the pod webhook.
+func RecordPodMutation(mutator, result string) {
+	podMutations.WithLabelValues(mutator, result).Inc()
+}
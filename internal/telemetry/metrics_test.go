@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, c.Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestRecordValidation(t *testing.T) {
+	allowed := admissionValidations.WithLabelValues("AmazonCloudWatchAgent", "create", "allowed")
+	denied := admissionValidations.WithLabelValues("AmazonCloudWatchAgent", "create", "denied")
+	emf := validationFailures.WithLabelValues("AmazonCloudWatchAgent", "emf")
+	other := validationFailures.WithLabelValues("AmazonCloudWatchAgent", "other")
+	before := []float64{counterValue(t, allowed), counterValue(t, denied), counterValue(t, emf), counterValue(t, other)}
+
+	RecordValidation("AmazonCloudWatchAgent", "create", nil)
+	RecordValidation("AmazonCloudWatchAgent", "create", errors.New("the OpenTelemetry Spec emf configuration is incorrect, namespace is invalid"))
+	RecordValidation("AmazonCloudWatchAgent", "create", errors.New("the OpenTelemetry Collector mode is set to sidecar"))
+
+	assert.Equal(t, before[0]+1, counterValue(t, allowed))
+	assert.Equal(t, before[1]+2, counterValue(t, denied))
+	assert.Equal(t, before[2]+1, counterValue(t, emf))
+	assert.Equal(t, before[3]+1, counterValue(t, other))
+}
+
+func TestRecordPodMutation(t *testing.T) {
+	injected := podMutations.WithLabelValues("instrumentation", MutationInjected)
+	before := counterValue(t, injected)
+
+	RecordPodMutation("instrumentation", MutationInjected)
+	assert.Equal(t, before+1, counterValue(t, injected))
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// serviceName is the name of the operator in the resource of its spans.
+	serviceName = "amazon-cloudwatch-agent-operator"
+	// tracerName is the instrumentation scope of the spans of the operator.
+	tracerName = "github.com/aws/amazon-cloudwatch-agent-operator"
+)
+
+// Tracer returns the tracer of the operator. Its spans are dropped unless SetupTracing has been called.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// SetupTracing exports the spans of the operator with OTLP over HTTP to the given endpoint, usually the OTLP
+// receiver of an agent managed by the operator, keeping the given ratio of the traces. The returned function
+// flushes the pending spans and has to be called before the operator exits.
+func SetupTracing(ctx context.Context, endpoint string, sampleRatio float64, version string) (func(context.Context) error, error) {
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("the tracing sample ratio %v is not between 0 and 1", sampleRatio)
+	}
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// tracedWebhookServer traces the requests received by the webhooks registered on the server.
+type tracedWebhookServer struct {
+	webhook.Server
+}
+
+// TracedWebhookServer returns the webhook server starting a span for each admission request, named after the
+// path of its webhook, so that the latency of the webhooks can be broken down per request.
+func TracedWebhookServer(server webhook.Server) webhook.Server {
+	return &tracedWebhookServer{Server: server}
+}
+
+func (s *tracedWebhookServer) Register(path string, hook http.Handler) {
+	s.Server.Register(path, otelhttp.NewHandler(hook, path))
+}
+
+// tracedReconciler starts a span for each reconcile of the wrapped reconciler.
+type tracedReconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+// TracedReconciler returns the reconciler of the given controller starting a span for each reconcile, which
+// records the error of the reconcile.
+func TracedReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracedReconciler{controller: controller, reconciler: r}
+}
+
+func (t *tracedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Tracer().Start(ctx, "Reconcile "+t.controller, trace.WithAttributes(
+		attribute.String("controller", t.controller),
+		semconv.K8SNamespaceName(req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	))
+	defer span.End()
+
+	result, err := t.reconciler.Reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestSetupTracing(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	_, err := SetupTracing(context.Background(), "http://localhost:4316/v1/traces", 2, "1.0.0")
+	assert.ErrorContains(t, err, "the tracing sample ratio 2 is not between 0 and 1")
+
+	shutdown, err := SetupTracing(context.Background(), "http://localhost:4316/v1/traces", 0, "1.0.0")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestTracedReconciler(t *testing.T) {
+	recorder := recordSpans(t)
+	reconcileErr := errors.New("failed to create the daemonset")
+	r := TracedReconciler("AmazonCloudWatchAgent", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, reconcileErr
+	}))
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "cloudwatch-agent"}})
+	assert.Equal(t, reconcileErr, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "Reconcile AmazonCloudWatchAgent", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Subset(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.String("controller", "AmazonCloudWatchAgent"),
+		attribute.String("k8s.namespace.name", "amazon-cloudwatch"),
+		attribute.String("k8s.object.name", "cloudwatch-agent"),
+	})
+}
+
+func TestTracedWebhookServer(t *testing.T) {
+	recorder := recordSpans(t)
+	server := TracedWebhookServer(webhook.NewServer(webhook.Options{}))
+	server.Register("/mutate-v1-pod", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	server.WebhookMux().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mutate-v1-pod", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/mutate-v1-pod", spans[0].Name())
+}
@@ -23,6 +23,7 @@ var (
 	dcgmExporter                   string
 	neuronMonitor                  string
 	targetAllocator                string
+	fluentBit                      string
 )
 
 // Version holds this Operator's version as well as the version of some of the components it uses.
@@ -41,6 +42,7 @@ type Version struct {
 	DcgmExporter                   string `json:"dcgm-exporter-version"`
 	NeuronMonitor                  string `json:"neuron-monitor-version"`
 	TargetAllocator                string `json:"target-allocator-version"`
+	FluentBit                      string `json:"fluent-bit-version"`
 }
 
 // Get returns the Version object with the relevant information.
@@ -60,12 +62,13 @@ func Get() Version {
 		DcgmExporter:                   DcgmExporter(),
 		NeuronMonitor:                  NeuronMonitor(),
 		TargetAllocator:                TargetAllocator(),
+		FluentBit:                      FluentBit(),
 	}
 }
 
 func (v Version) String() string {
 	return fmt.Sprintf(
-		"Version(Operator='%v', BuildDate='%v', AmazonCloudWatchAgent='%v', Go='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v', DcgmExporter='%v', NeuronMonitor='%v', TargetAllocator='%v')",
+		"Version(Operator='%v', BuildDate='%v', AmazonCloudWatchAgent='%v', Go='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v', DcgmExporter='%v', NeuronMonitor='%v', TargetAllocator='%v', FluentBit='%v')",
 		v.Operator,
 		v.BuildDate,
 		v.AmazonCloudWatchAgent,
@@ -80,6 +83,7 @@ func (v Version) String() string {
 		v.DcgmExporter,
 		v.NeuronMonitor,
 		v.TargetAllocator,
+		v.FluentBit,
 	)
 }
 
@@ -169,3 +173,14 @@ func TargetAllocator() string {
 	// fallback value, useful for tests
 	return "0.0.0"
 }
+
+// FluentBit returns the default version of the AWS for Fluent Bit image collecting the container logs.
+func FluentBit() string {
+	if len(fluentBit) > 0 {
+		// this should always be set, as it's specified during the build
+		return fluentBit
+	}
+
+	// fallback value, useful for tests
+	return "0.0.0"
+}
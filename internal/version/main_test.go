@@ -39,6 +39,21 @@ func TestTargetAllocatorVersionFromBuild(t *testing.T) {
 	assert.Contains(t, Get().String(), targetAllocator)
 }
 
+func TestFluentBitFallbackVersion(t *testing.T) {
+	assert.Equal(t, "0.0.0", FluentBit())
+}
+
+func TestFluentBitVersionFromBuild(t *testing.T) {
+	// prepare
+	fluentBit = "2.32.2" // set during the build
+	defer func() {
+		fluentBit = ""
+	}()
+
+	assert.Equal(t, fluentBit, FluentBit())
+	assert.Contains(t, Get().String(), fluentBit)
+}
+
 func TestAutoInstrumentationJavaFallbackVersion(t *testing.T) {
 	assert.Equal(t, "0.0.0", AutoInstrumentationJava())
 }
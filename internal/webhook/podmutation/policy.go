@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;watch;update;patch
+
+// WebhookName is the name of the pod webhook in the MutatingWebhookConfiguration of the operator.
+const WebhookName = "mpod.kb.io"
+
+// Policy holds the admission settings of the pod webhook: whether the pods are admitted when the webhook fails, how
+// long the API server waits for it, which pods it is called for, and how many shards serve them.
+type Policy struct {
+	FailurePolicy     admissionregistrationv1.FailurePolicyType
+	TimeoutSeconds    int32
+	NamespaceSelector *metav1.LabelSelector
+	ObjectSelector    *metav1.LabelSelector
+	// Shards is the number of pod webhooks, each called for the namespaces of its shard through its own service. The
+	// pod webhook isn't sharded when it is 0 or 1.
+	Shards int
+}
+
+// Validate returns an error when the API server would reject the policy.
+func (p Policy) Validate() error {
+	if p.FailurePolicy != admissionregistrationv1.Ignore && p.FailurePolicy != admissionregistrationv1.Fail {
+		return fmt.Errorf("invalid failure policy %q, expected %s or %s", p.FailurePolicy, admissionregistrationv1.Ignore, admissionregistrationv1.Fail)
+	}
+	if p.TimeoutSeconds < 1 || p.TimeoutSeconds > 30 {
+		return fmt.Errorf("invalid timeout of %d seconds, expected between 1 and 30 seconds", p.TimeoutSeconds)
+	}
+	if p.Shards < 0 {
+		return fmt.Errorf("invalid number of shards %d, expected at least 1", p.Shards)
+	}
+	for _, selector := range []*metav1.LabelSelector{p.NamespaceSelector, p.ObjectSelector} {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+	return nil
+}
+
+// ApplyPolicy sets the policy of the pod webhook of the MutatingWebhookConfiguration, which is left untouched when
+// it already has it. Empty selectors match all the pods. The webhook is always reinvoked when a later webhook changes
+// the pod, as the mutators leave the pods they already mutated unchanged. When the policy has several shards, the pod
+// webhook is copied for each shard, and the copies of the shards which no longer exist are removed.
+func ApplyPolicy(ctx context.Context, c client.Client, configuration string, policy Policy) error {
+	namespaceSelector, objectSelector := policy.NamespaceSelector, policy.ObjectSelector
+	if namespaceSelector == nil {
+		namespaceSelector = &metav1.LabelSelector{}
+	}
+	if objectSelector == nil {
+		objectSelector = &metav1.LabelSelector{}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Name: configuration}, mwc); err != nil {
+			return err
+		}
+		var hook *admissionregistrationv1.MutatingWebhook
+		for i := range mwc.Webhooks {
+			if mwc.Webhooks[i].Name == WebhookName {
+				hook = &mwc.Webhooks[i]
+			}
+		}
+		if hook == nil {
+			return fmt.Errorf("the MutatingWebhookConfiguration %s has no %s webhook", configuration, WebhookName)
+		}
+
+		desired := hook.DeepCopy()
+		desired.FailurePolicy = &policy.FailurePolicy
+		desired.TimeoutSeconds = &policy.TimeoutSeconds
+		desired.NamespaceSelector = namespaceSelector
+		desired.ObjectSelector = objectSelector
+		desired.ReinvocationPolicy = ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy)
+		webhooks := shardWebhooks(mwc.Webhooks, desired, policy.Shards)
+		if equality.Semantic.DeepEqual(mwc.Webhooks, webhooks) {
+			return nil
+		}
+		mwc.Webhooks = webhooks
+		return c.Update(ctx, mwc)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	assert.NoError(t, Policy{FailurePolicy: admissionregistrationv1.Fail, TimeoutSeconds: 10}.Validate())
+	assert.Error(t, Policy{FailurePolicy: "Block", TimeoutSeconds: 10}.Validate())
+	assert.Error(t, Policy{FailurePolicy: admissionregistrationv1.Ignore, TimeoutSeconds: 31}.Validate())
+	assert.Error(t, Policy{FailurePolicy: admissionregistrationv1.Ignore, TimeoutSeconds: 10, ObjectSelector: &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}},
+	}}.Validate())
+}
+
+func TestApplyPolicy(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-mutating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "minstrumentation.kb.io", FailurePolicy: &ignore},
+			{Name: WebhookName, FailurePolicy: &ignore},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(mwc).Build()
+	policy := Policy{
+		FailurePolicy:     admissionregistrationv1.Fail,
+		TimeoutSeconds:    5,
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"injection": "enabled"}},
+	}
+
+	require.NoError(t, ApplyPolicy(context.Background(), c, mwc.Name, policy))
+
+	actual := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: mwc.Name}, actual))
+	assert.Equal(t, admissionregistrationv1.Ignore, *actual.Webhooks[0].FailurePolicy)
+	assert.Equal(t, admissionregistrationv1.Fail, *actual.Webhooks[1].FailurePolicy)
+	assert.Equal(t, int32(5), *actual.Webhooks[1].TimeoutSeconds)
+	assert.Equal(t, policy.NamespaceSelector, actual.Webhooks[1].NamespaceSelector)
+	assert.Equal(t, &metav1.LabelSelector{}, actual.Webhooks[1].ObjectSelector)
+	assert.Equal(t, admissionregistrationv1.IfNeededReinvocationPolicy, *actual.Webhooks[1].ReinvocationPolicy)
+	assert.Nil(t, actual.Webhooks[0].ReinvocationPolicy)
+
+	// the configuration is not updated when it already has the policy
+	require.NoError(t, ApplyPolicy(context.Background(), c, mwc.Name, policy))
+	unchanged := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: mwc.Name}, unchanged))
+	assert.Equal(t, actual.ResourceVersion, unchanged.ResourceVersion)
+
+	assert.Error(t, ApplyPolicy(context.Background(), c, "missing", policy))
+}
@@ -0,0 +1,181 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// ShardLabel is the label assigning a namespace to a shard of the pod webhook. The namespaces without it belong to
+	// the first shard.
+	ShardLabel = "cloudwatch.aws.amazon.com/webhook-shard"
+	// DefaultShardLabelInterval is the interval between two labellings of the namespaces.
+	DefaultShardLabelInterval = time.Minute
+
+	// namespacePageSize is the number of namespaces listed at once.
+	namespacePageSize = 500
+)
+
+// NamespaceShard returns the shard of the pod webhook the namespace is assigned to.
+func NamespaceShard(namespace string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ShardService returns the service of the shard of the pod webhook: the service of the first shard is the webhook
+// service, the service of shard i is named after it with the -shard-i suffix.
+func ShardService(service types.NamespacedName, shard int) types.NamespacedName {
+	if shard == 0 {
+		return service
+	}
+	return types.NamespacedName{Namespace: service.Namespace, Name: fmt.Sprintf("%s-shard-%d", service.Name, shard)}
+}
+
+// shardWebhookName returns the name of the pod webhook of the shard in the MutatingWebhookConfiguration.
+func shardWebhookName(shard int) string {
+	if shard == 0 {
+		return WebhookName
+	}
+	return fmt.Sprintf("mpod-shard-%d.kb.io", shard)
+}
+
+func isShardWebhook(name string) bool {
+	return strings.HasPrefix(name, "mpod-shard-") && strings.HasSuffix(name, ".kb.io")
+}
+
+// shardNamespaceSelector restricts the namespace selector to the namespaces of the shard. The first shard selects the
+// namespaces which are not labelled with another shard, so that the new namespaces are admitted before they are
+// labelled.
+func shardNamespaceSelector(selector *metav1.LabelSelector, shard, shards int) *metav1.LabelSelector {
+	selector = selector.DeepCopy()
+	if shards <= 1 {
+		return selector
+	}
+	requirement := metav1.LabelSelectorRequirement{Key: ShardLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{strconv.Itoa(shard)}}
+	if shard == 0 {
+		requirement = metav1.LabelSelectorRequirement{Key: ShardLabel, Operator: metav1.LabelSelectorOpNotIn}
+		for other := 1; other < shards; other++ {
+			requirement.Values = append(requirement.Values, strconv.Itoa(other))
+		}
+	}
+	selector.MatchExpressions = append(selector.MatchExpressions, requirement)
+	return selector
+}
+
+// shardWebhooks returns the webhooks of the configuration with a pod webhook per shard, each calling the service of
+// its shard for the namespaces of its shard.
+func shardWebhooks(webhooks []admissionregistrationv1.MutatingWebhook, desired *admissionregistrationv1.MutatingWebhook, shards int) []admissionregistrationv1.MutatingWebhook {
+	var result []admissionregistrationv1.MutatingWebhook
+	for _, hook := range webhooks {
+		if isShardWebhook(hook.Name) {
+			continue
+		}
+		if hook.Name != WebhookName {
+			result = append(result, hook)
+			continue
+		}
+		for shard := 0; shard < max(shards, 1); shard++ {
+			shardHook := *desired.DeepCopy()
+			shardHook.Name = shardWebhookName(shard)
+			shardHook.NamespaceSelector = shardNamespaceSelector(desired.NamespaceSelector, shard, shards)
+			if service := shardHook.ClientConfig.Service; service != nil {
+				name := ShardService(types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, shard)
+				service.Name = name.Name
+			}
+			result = append(result, shardHook)
+		}
+	}
+	return result
+}
+
+var _ manager.LeaderElectionRunnable = (*ShardLabeler)(nil)
+
+// ShardLabeler labels the namespaces with their shard of the pod webhook at every interval.
+type ShardLabeler struct {
+	reader   client.Reader
+	client   client.Client
+	shards   int
+	interval time.Duration
+	log      logr.Logger
+}
+
+// NewShardLabeler returns the labeler of the namespaces for the number of shards. The namespaces are read with the
+// reader, so that the client doesn't cache them.
+func NewShardLabeler(reader client.Reader, c client.Client, shards int, interval time.Duration, log logr.Logger) *ShardLabeler {
+	if interval <= 0 {
+		interval = DefaultShardLabelInterval
+	}
+	return &ShardLabeler{reader: reader, client: c, shards: shards, interval: interval, log: log}
+}
+
+// NeedLeaderElection is true, as only the leader labels the namespaces.
+func (l *ShardLabeler) NeedLeaderElection() bool {
+	return true
+}
+
+// Start labels the namespaces at every interval until the context is done.
+func (l *ShardLabeler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		if err := l.Label(ctx); err != nil {
+			l.log.Error(err, "failed to label the namespaces with their webhook shard")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Label sets the shard label of the namespaces whose label is missing or assigns them to another shard.
+func (l *ShardLabeler) Label(ctx context.Context) error {
+	var errs []error
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NamespaceList"))
+	for {
+		if err := l.reader.List(ctx, list, client.Limit(namespacePageSize), client.Continue(list.GetContinue())); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			namespace := &list.Items[i]
+			shard := strconv.Itoa(NamespaceShard(namespace.Name, l.shards))
+			if namespace.Labels[ShardLabel] == shard {
+				continue
+			}
+			namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+			patch := client.MergeFrom(namespace.DeepCopy())
+			if namespace.Labels == nil {
+				namespace.Labels = map[string]string{}
+			}
+			namespace.Labels[ShardLabel] = shard
+			if err := l.client.Patch(ctx, namespace, patch); err != nil {
+				errs = append(errs, fmt.Errorf("failed to label the namespace %s: %w", namespace.Name, err))
+			}
+		}
+		if list.GetContinue() == "" {
+			return errors.Join(errs...)
+		}
+	}
+}
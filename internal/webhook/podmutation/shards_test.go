@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+)
+
+func TestNamespaceShard(t *testing.T) {
+	assert.Equal(t, 0, NamespaceShard("default", 1))
+	counts := map[int]int{}
+	for i := 0; i < 100; i++ {
+		shard := NamespaceShard("namespace-"+strconv.Itoa(i), 3)
+		assert.Equal(t, shard, NamespaceShard("namespace-"+strconv.Itoa(i), 3))
+		counts[shard]++
+	}
+	assert.Len(t, counts, 3)
+}
+
+func TestShardService(t *testing.T) {
+	service := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "cloudwatch-webhook-service"}
+	assert.Equal(t, service, ShardService(service, 0))
+	assert.Equal(t, types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "cloudwatch-webhook-service-shard-2"}, ShardService(service, 2))
+}
+
+func TestApplyPolicyShards(t *testing.T) {
+	ctx := context.Background()
+	ignore := admissionregistrationv1.Ignore
+	mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-mutating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "minstrumentation.kb.io", FailurePolicy: &ignore},
+			{Name: WebhookName, FailurePolicy: &ignore, ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: "amazon-cloudwatch", Name: "cloudwatch-webhook-service"},
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(mwc).Build()
+	policy := Policy{
+		FailurePolicy:     admissionregistrationv1.Ignore,
+		TimeoutSeconds:    10,
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"injection": "enabled"}},
+		Shards:            3,
+	}
+	require.NoError(t, ApplyPolicy(ctx, c, mwc.Name, policy))
+
+	actual := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: mwc.Name}, actual))
+	require.Len(t, actual.Webhooks, 4)
+	assert.Equal(t, "minstrumentation.kb.io", actual.Webhooks[0].Name)
+	for shard, name := range []string{WebhookName, "mpod-shard-1.kb.io", "mpod-shard-2.kb.io"} {
+		hook := actual.Webhooks[shard+1]
+		assert.Equal(t, name, hook.Name)
+		assert.Equal(t, ShardService(types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "cloudwatch-webhook-service"}, shard).Name, hook.ClientConfig.Service.Name)
+		selector, err := metav1.LabelSelectorAsSelector(hook.NamespaceSelector)
+		require.NoError(t, err)
+		// each namespace is selected by the webhook of its shard only
+		for other := 0; other < 3; other++ {
+			namespaceLabels := labels.Set{"injection": "enabled", ShardLabel: strconv.Itoa(other)}
+			assert.Equal(t, shard == other, selector.Matches(namespaceLabels))
+		}
+		// the namespaces which are not labelled yet belong to the first shard
+		assert.Equal(t, shard == 0, selector.Matches(labels.Set{"injection": "enabled"}))
+		assert.False(t, selector.Matches(labels.Set{ShardLabel: strconv.Itoa(shard)}))
+	}
+
+	// the webhooks of the removed shards are removed
+	policy.Shards = 1
+	require.NoError(t, ApplyPolicy(ctx, c, mwc.Name, policy))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: mwc.Name}, actual))
+	require.Len(t, actual.Webhooks, 2)
+	assert.Equal(t, policy.NamespaceSelector, actual.Webhooks[1].NamespaceSelector)
+	assert.Equal(t, "cloudwatch-webhook-service", actual.Webhooks[1].ClientConfig.Service.Name)
+}
+
+func TestShardLabeler(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{ShardLabel: "7", "team": "a"}}},
+	).Build()
+	require.NoError(t, NewShardLabeler(c, c, 4, 0, logr.Discard()).Label(ctx))
+
+	for _, name := range []string{"default", "team-a"} {
+		namespace := &corev1.Namespace{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: name}, namespace))
+		assert.Equal(t, strconv.Itoa(NamespaceShard(name, 4)), namespace.Labels[ShardLabel])
+	}
+	namespace := &corev1.Namespace{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "team-a"}, namespace))
+	assert.Equal(t, "a", namespace.Labels["team"])
+}
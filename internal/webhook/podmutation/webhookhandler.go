@@ -7,23 +7,35 @@ package podmutation
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"net/http"
+	"path"
+	"reflect"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 )
 
-// +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=none,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=none,admissionReviewVersions=v1,reinvocationPolicy=IfNeeded
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="apps",resources=replicasets,verbs=get;list;watch
 
+// InjectionStatusAnnotation records on the pods requesting an auto-instrumentation the outcome of the injection:
+// injected, or skipped with the reason. It is not counted as an injection by the metrics of the webhook.
+const InjectionStatusAnnotation = "cloudwatch.aws.amazon.com/instrumentation-status"
+
 var _ WebhookHandler = (*podMutationWebhook)(nil)
 
 // WebhookHandler is a webhook handler that analyzes new pods and injects appropriate sidecars into it.
@@ -63,6 +75,14 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	// a namespace-scoped operator has no instrumentations nor agents to inject outside of the namespaces it watches
+	if !p.config.Watches(req.Namespace) {
+		return admission.Allowed("the namespace isn't watched by the operator")
+	}
+	if p.config.DeniesNamespace(req.Namespace) {
+		return admission.Allowed("the namespace is denied by the operator configuration")
+	}
+
 	// we use the req.Namespace here because the pod might have not been created yet
 	ns := corev1.Namespace{}
 	err = p.client.Get(ctx, types.NamespacedName{Name: req.Namespace, Namespace: ""}, &ns)
@@ -78,7 +98,7 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 	}
 
 	for _, m := range p.podMutators {
-		pod, err = m.Mutate(ctx, ns, pod)
+		pod, err = p.mutate(ctx, m, ns, pod)
 		if err != nil {
 			res := admission.Errored(http.StatusInternalServerError, err)
 			res.Allowed = true
@@ -94,3 +114,46 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 	}
 	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
 }
+
+// mutate applies the mutator to the pod in a span, so that the slow mutators stand out in the traces of the webhook,
+// and counts the pods it injected.
+func (p *podMutationWebhook) mutate(ctx context.Context, m PodMutator, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+	name := mutatorName(m)
+	ctx, span := telemetry.Tracer().Start(ctx, "Mutate", trace.WithAttributes(attribute.String("mutator", name)))
+	defer span.End()
+
+	mutated, err := m.Mutate(ctx, ns, pod)
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordPodMutation(name, telemetry.MutationFailed)
+	case equality.Semantic.DeepEqual(withoutInjectionStatus(pod), withoutInjectionStatus(mutated)):
+		telemetry.RecordPodMutation(name, telemetry.MutationUnchanged)
+	default:
+		telemetry.RecordPodMutation(name, telemetry.MutationInjected)
+	}
+	return mutated, err
+}
+
+// withoutInjectionStatus returns the pod without its InjectionStatusAnnotation.
+func withoutInjectionStatus(pod corev1.Pod) corev1.Pod {
+	if _, found := pod.Annotations[InjectionStatusAnnotation]; !found {
+		return pod
+	}
+	pod.Annotations = maps.Clone(pod.Annotations)
+	delete(pod.Annotations, InjectionStatusAnnotation)
+	if len(pod.Annotations) == 0 {
+		pod.Annotations = nil
+	}
+	return pod
+}
+
+// mutatorName returns the name of the package of the mutator, such as sidecar or instrumentation.
+func mutatorName(m PodMutator) string {
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
+}
@@ -76,3 +76,55 @@ func TestFailOnInvalidRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipUnwatchedNamespace(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{}
+	encoded, err := json.Marshal(pod)
+	require.NoError(t, err)
+	req := admission.Request{
+		AdmissionRequest: admv1.AdmissionRequest{
+			Namespace: "unwatched",
+			Object: runtime.RawExtension{
+				Raw: encoded,
+			},
+		},
+	}
+	cfg := config.New(config.WithWatchNamespaces([]string{"watched"}))
+	decoder := admission.NewDecoder(scheme.Scheme)
+	injector := NewWebhookHandler(cfg, logger, decoder, k8sClient, []PodMutator{sidecar.NewMutator(logger, cfg, k8sClient)})
+
+	// test
+	res := injector.Handle(context.Background(), req)
+
+	// verify
+	assert.True(t, res.Allowed)
+	assert.Empty(t, res.Patches)
+	assert.Equal(t, int32(http.StatusOK), res.AdmissionResponse.Result.Code)
+}
+
+func TestSkipDeniedNamespace(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{}
+	encoded, err := json.Marshal(pod)
+	require.NoError(t, err)
+	req := admission.Request{
+		AdmissionRequest: admv1.AdmissionRequest{
+			Namespace: "kube-system",
+			Object: runtime.RawExtension{
+				Raw: encoded,
+			},
+		},
+	}
+	cfg := config.New()
+	cfg.SetDefaults(config.Defaults{DeniedNamespaces: []string{"kube-*"}})
+	decoder := admission.NewDecoder(scheme.Scheme)
+	injector := NewWebhookHandler(cfg, logger, decoder, k8sClient, []PodMutator{sidecar.NewMutator(logger, cfg, k8sClient)})
+
+	// test
+	res := injector.Handle(context.Background(), req)
+
+	// verify
+	assert.True(t, res.Allowed)
+	assert.Empty(t, res.Patches)
+}
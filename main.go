@@ -9,7 +9,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,22 +19,46 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/spf13/pflag"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	"golang.org/x/time/rate"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/util/workqueue"
 	k8sapiflag "k8s.io/component-base/cli/flag"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	otelv1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	otelv1beta1 "github.com/aws/amazon-cloudwatch-agent-operator/apis/v1beta1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/controllers"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/adoption"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/availability"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/awsresource"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/certrotation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/cleanup"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promreload"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/readiness"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/render"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/signature"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/storagemigration"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/telemetry"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/namespacemutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
@@ -40,6 +66,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/upgrade"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/sidecar"
 	// +kubebuilder:scaffold:imports
 )
@@ -53,8 +80,13 @@ const (
 	dcgmExporterImageRepository              = "nvcr.io/nvidia/k8s/dcgm-exporter"
 	neuronMonitorImageRepository             = "public.ecr.aws/neuron"
 	targetAllocatorImageRepository           = "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-target-allocator"
+	fluentBitImageRepository                 = "public.ecr.aws/aws-observability/aws-for-fluent-bit"
+	operatorImageRepository                  = "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-operator"
 )
 
+// controllerNames are the names of the controllers of the operator, as set in --max-concurrent-reconciles.
+var controllerNames = []string{"AmazonCloudWatchAgent", "DcgmExporter", "NeuronMonitor"}
+
 var (
 	scheme   = k8sruntime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -68,6 +100,7 @@ type tlsConfig struct {
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(otelv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(otelv1beta1.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -123,6 +156,7 @@ func main() {
 		probeAddr                    string
 		pprofAddr                    string
 		agentImage                   string
+		agentFIPSImage               string
 		autoInstrumentationJava      string
 		autoInstrumentationPython    string
 		autoInstrumentationDotNet    string
@@ -135,12 +169,64 @@ func main() {
 		dcgmExporterImage            string
 		neuronMonitorImage           string
 		targetAllocatorImage         string
+		fluentBitImage               string
+		promConfigReloaderImage      string
+		imageRegistry                string
+		imagePullSecrets             []string
+		ecrPullThroughCachePrefix    string
+		imageVerification            signature.Options
+		upgradeChannel               string
+		legacyAgentRBAC              bool
+		fargateAgent                 string
+		cloudProvider                string
+		clusterName                  string
+		injectAWSResource            bool
+		awsResource                  awsresource.Resource
+		tracingEndpoint              string
+		tracingSampleRatio           float64
+		emfOptions                   telemetry.EMFOptions
+		leaderElection               bool
+		leaderElectionID             string
+		leaseDuration                time.Duration
+		renewDeadline                time.Duration
+		retryPeriod                  time.Duration
+		maxConcurrentReconciles      map[string]int
+		kubeAPIQPS                   float32
+		kubeAPIBurst                 int
+		reconcileBaseDelay           time.Duration
+		reconcileMaxDelay            time.Duration
+		reconcileQPS                 float64
+		reconcileBurst               int
+		operatorConfig               string
+		operatorConfigMap            string
+		operatorConfigReloadInterval time.Duration
+		podWebhookConfiguration      string
+		podWebhookFailurePolicy      string
+		podWebhookTimeoutSeconds     int32
+		podWebhookNamespaceSelector  string
+		podWebhookObjectSelector     string
+		podWebhookShards             int
+		operatorDeployment           string
+		availabilityOptions          availability.Options
+		operatorPDBMinAvailable      string
+		webhookCertSecret            string
+		webhookCertService           string
+		webhookCertConfigurations    []string
+		webhookCertCRDs              []string
+		webhookCertDir               string
+		webhookCertMinValidity       time.Duration
+		adoptOpenTelemetryResources  bool
+		migrateStorageVersions       bool
+		orphanSweepInterval          time.Duration
+		renderFile                   string
+		reloadPrometheusConfig       string
 	)
 
 	pflag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	pflag.StringVar(&probeAddr, "health-probe-addr", ":8081", "The address the probe endpoint binds to.")
-	pflag.StringVar(&pprofAddr, "pprof-addr", "", "The address to expose the pprof server. Default is empty string which disables the pprof server.")
+	pflag.StringVar(&pprofAddr, "pprof-addr", "", "The address to expose the pprof profiles and the expvar runtime diagnostics. An address without host, such as :6060, only binds to the loopback interface, reachable with kubectl port-forward. Default is empty string which disables the diagnostics server.")
 	stringFlagOrEnv(&agentImage, "agent-image", "RELATED_IMAGE_COLLECTOR", fmt.Sprintf("%s:%s", cloudwatchAgentImageRepository, v.AmazonCloudWatchAgent), "The default CloudWatch Agent image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&agentFIPSImage, "agent-fips-image", "RELATED_IMAGE_COLLECTOR_FIPS", "", "The FIPS validated CloudWatch Agent image. This image is used by the CustomResources running in FIPS mode which do not specify an image.")
 	stringFlagOrEnv(&autoInstrumentationJava, "auto-instrumentation-java-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_JAVA", fmt.Sprintf("%s:%s", autoInstrumentationJavaImageRepository, v.AutoInstrumentationJava), "The default OpenTelemetry Java instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationPython, "auto-instrumentation-python-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_PYTHON", fmt.Sprintf("%s:%s", autoInstrumentationPythonImageRepository, v.AutoInstrumentationPython), "The default OpenTelemetry Python instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationDotNet, "auto-instrumentation-dotnet-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_DOTNET", fmt.Sprintf("%s:%s", autoInstrumentationDotNetImageRepository, v.AutoInstrumentationDotNet), "The default OpenTelemetry Dotnet instrumentation image. This image is used when no image is specified in the CustomResource.")
@@ -151,6 +237,66 @@ func main() {
 	stringFlagOrEnv(&dcgmExporterImage, "dcgm-exporter-image", "RELATED_IMAGE_DCGM_EXPORTER", fmt.Sprintf("%s:%s", dcgmExporterImageRepository, v.DcgmExporter), "The default DCGM Exporter image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&neuronMonitorImage, "neuron-monitor-image", "RELATED_IMAGE_NEURON_MONITOR", fmt.Sprintf("%s:%s", neuronMonitorImageRepository, v.NeuronMonitor), "The default Neuron monitor image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&targetAllocatorImage, "target-allocator-image", "RELATED_IMAGE_TARGET_ALLOCATOR", fmt.Sprintf("%s:%s", targetAllocatorImageRepository, v.TargetAllocator), "The default AmazonCloudWatchAgent target allocator image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&fluentBitImage, "fluent-bit-image", "RELATED_IMAGE_FLUENT_BIT", fmt.Sprintf("%s:%s", fluentBitImageRepository, v.FluentBit), "The default Fluent Bit image collecting the container logs. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&promConfigReloaderImage, "prometheus-config-reloader-image", "RELATED_IMAGE_PROMETHEUS_CONFIG_RELOADER", fmt.Sprintf("%s:%s", operatorImageRepository, v.Operator), "The image of the sidecar reloading the Prometheus configuration of the agents whose prometheusConfigReload is enabled, which runs this operator with --reload-prometheus-config.")
+	pflag.StringVar(&imageRegistry, "image-registry", "", "The registry mirror, such as registry.example.com/mirror, the default images of the operator are pulled from in air-gapped clusters. It replaces the registry of the images and keeps their repository, so that public.ecr.aws/cloudwatch-agent/cloudwatch-agent is pulled from registry.example.com/mirror/cloudwatch-agent/cloudwatch-agent. Default is empty string which pulls the images from their own registries.")
+	pflag.StringVar(&ecrPullThroughCachePrefix, "ecr-pull-through-cache-prefix", "", "The repository prefix, such as ecr-public, of the ECR pull through cache rule of ECR Public in the private registry of the account of the cluster. When set, the default images hosted on public.ecr.aws are pulled from the registry in the region of the operator instead, <account>.dkr.ecr.<region>.amazonaws.com/<prefix>, whose region and account are detected like the ones of --inject-aws-resource-attributes. Default is empty string which pulls the images from ECR Public.")
+	pflag.StringSliceVar(&imagePullSecrets, "image-pull-secrets", nil, "The names of the image pull secrets attached to the pods and service accounts the operator creates and to the pods it instruments, for clusters which only pull from registries requiring authentication. The secrets must exist in the namespace of each workload.")
+	pflag.StringVar(&imageVerification.PublicKeyFile, "cosign-public-key", "", "The PEM public key file the cosign signatures of the images are verified with. When set, the operator refuses to deploy the agents, target allocators and exporters and to inject the auto-instrumentations and agent sidecars whose images aren't signed with the key. Default is empty string which doesn't verify the images, unless a keyless identity is set.")
+	pflag.StringVar(&imageVerification.CertificateIdentity, "cosign-certificate-identity", "", "The identity, an email address or URI, the Fulcio certificates of the keyless cosign signatures of the images must be issued to. When set, the images are verified like with --cosign-public-key, with the certificates of their signatures instead of a key.")
+	pflag.StringVar(&imageVerification.CertificateOIDCIssuer, "cosign-certificate-oidc-issuer", "", "The OIDC issuer of the identity of --cosign-certificate-identity, such as https://token.actions.githubusercontent.com.")
+	pflag.StringVar(&imageVerification.FulcioRootsFile, "cosign-fulcio-roots", "", "The PEM file of the root and intermediate certificates of Fulcio the certificates of the keyless signatures must chain to.")
+	pflag.StringVar(&imageVerification.RekorPublicKeyFile, "cosign-rekor-public-key", "", "The PEM public key file of the Rekor transparency log the keyless signatures must be logged in.")
+	pflag.StringVar(&upgradeChannel, "upgrade-channel", string(upgrade.ChannelStable), "The upgrade channel (stable, latest or pinned) of the AmazonCloudWatchAgent and Instrumentation instances which do not opt into one through the cloudwatch.aws.amazon.com/upgrade-channel annotation.")
+	pflag.BoolVar(&legacyAgentRBAC, "legacy-agent-rbac", false, "Grant every CloudWatch Agent the cluster permissions of all the agent features, instead of only the permissions its configuration needs.")
+	pflag.StringVar(&fargateAgent, "fargate-agent", "amazon-cloudwatch/cloudwatch-agent-fargate", "The namespace/name of the sidecar mode AmazonCloudWatchAgent injected into the instrumented pods of the EKS Fargate profiles, which have no agent on their node to send their telemetry to. The pods are not given an agent sidecar when it is empty or the agent is not found.")
+	pflag.StringVar(&cloudProvider, "cloud-provider", config.CloudProviderAWS, "The cloud provider of the cluster: aws, gcp, azure or on-premises. Outside of AWS, the resource detectors of the injected SDKs which read the instance metadata are disabled.")
+	pflag.StringVar(&clusterName, "cluster-name", "", "The name of the cluster, added to the resources of the injected SDKs as k8s.cluster.name, for the clusters whose name can't be detected. Default is empty string which leaves the detection to the SDKs.")
+	pflag.BoolVar(&injectAWSResource, "inject-aws-resource-attributes", false, "Inject the AWS region, account and, for the pods pinned to a zone, availability zone into the resources of the injected SDKs and disable their AWS resource detectors, so that the workloads don't need the instance metadata (IMDSv2), which the pods can't reach when the hop limit of the nodes is 1.")
+	pflag.StringVar(&awsResource.Region, "aws-region", "", "The AWS region injected with --inject-aws-resource-attributes and of the registry of --ecr-pull-through-cache-prefix. Default is empty string which detects it from the env vars of the operator or the topology.kubernetes.io/region label of the nodes.")
+	pflag.StringVar(&awsResource.AccountID, "aws-account-id", "", "The AWS account injected with --inject-aws-resource-attributes and of the registry of --ecr-pull-through-cache-prefix. Default is empty string which detects it from the IAM role for service accounts of the operator.")
+	pflag.StringVar(&tracingEndpoint, "tracing-otlp-endpoint", "", "The OTLP/HTTP endpoint the traces of the webhooks and reconcilers of the operator are exported to, such as http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces. Default is empty string which disables the tracing.")
+	pflag.Float64Var(&tracingSampleRatio, "tracing-sample-ratio", 0.1, "The ratio of the traces of the operator which are sampled, between 0 and 1.")
+	pflag.StringVar(&emfOptions.Endpoint, "emf-endpoint", "", "The EMF listener of a CloudWatch Agent, such as tcp://cloudwatch-agent.amazon-cloudwatch:25888, the metrics of the operator are sent to as EMF events. Default is empty string which disables the report.")
+	pflag.StringVar(&emfOptions.Namespace, "emf-namespace", telemetry.DefaultEMFNamespace, "The CloudWatch namespace of the metrics of the operator sent as EMF events.")
+	pflag.StringVar(&emfOptions.LogGroupName, "emf-log-group-name", telemetry.DefaultEMFLogGroupName, "The log group of the EMF events of the operator.")
+	pflag.DurationVar(&emfOptions.Interval, "emf-interval", telemetry.DefaultEMFInterval, "The interval between two reports of the metrics of the operator as EMF events.")
+	pflag.BoolVar(&leaderElection, "leader-elect", false, "Elect a leader among the replicas of the operator, so that only one of them reconciles the instances while all of them serve the webhooks.")
+	pflag.StringVar(&leaderElectionID, "leader-election-id", "amazon-cloudwatch-agent-operator-lock", "The name of the lease the replicas of the operator elect their leader with.")
+	pflag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "The duration the replicas which are not the leader wait before acquiring the lease of a leader which stopped renewing it.")
+	pflag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second, "The duration the leader retries renewing its lease before giving up the leadership.")
+	pflag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second, "The duration between two attempts of the replicas to acquire or renew the lease.")
+	pflag.StringToIntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", map[string]int{}, "The number of instances reconciled concurrently by each controller, such as AmazonCloudWatchAgent=4,DcgmExporter=1,NeuronMonitor=1. The controllers reconcile one instance at a time by default.")
+	pflag.Float32Var(&kubeAPIQPS, "kube-api-qps", 20, "The number of requests per second the operator sends to the API server, beyond its burst.")
+	pflag.IntVar(&kubeAPIBurst, "kube-api-burst", 30, "The number of requests the operator sends to the API server in a burst.")
+	pflag.DurationVar(&reconcileBaseDelay, "reconcile-base-delay", 5*time.Millisecond, "The delay before the first retry of a failed reconcile, doubled after each failure of the same instance.")
+	pflag.DurationVar(&reconcileMaxDelay, "reconcile-max-delay", 1000*time.Second, "The maximum delay between two retries of the failed reconciles of an instance.")
+	pflag.Float64Var(&reconcileQPS, "reconcile-qps", 10, "The number of reconciles per second each controller starts, beyond its burst.")
+	pflag.IntVar(&reconcileBurst, "reconcile-burst", 100, "The number of reconciles each controller starts in a burst.")
+	pflag.StringVar(&operatorConfig, "operator-config", "", "The operator configuration document, in YAML or JSON, such as the one of the EKS add-on configuration, holding the same defaults as the operator configuration ConfigMap, which overrides them. Default is empty string which keeps the defaults of the flags.")
+	pflag.StringVar(&operatorConfigMap, "operator-config-map", "", "The namespace/name of the ConfigMap holding the defaults of the operator, such as the auto-instrumentation images, which are reloaded at runtime. Default is empty string which keeps the defaults of the flags.")
+	pflag.DurationVar(&operatorConfigReloadInterval, "operator-config-reload-interval", config.DefaultReloadInterval, "The interval between two reads of the operator configuration ConfigMap.")
+	pflag.StringVar(&podWebhookConfiguration, "pod-webhook-configuration", "", "The name of the MutatingWebhookConfiguration of the operator, such as cloudwatch-mutating-webhook-configuration, whose pod webhook is given the policy of the --pod-webhook-* flags. Default is empty string which leaves the policy of the deployed configuration.")
+	pflag.StringVar(&podWebhookFailurePolicy, "pod-webhook-failure-policy", string(admissionregistrationv1.Ignore), "Whether the pods are admitted without injection (Ignore) or rejected (Fail) when the pod webhook fails or times out.")
+	pflag.Int32Var(&podWebhookTimeoutSeconds, "pod-webhook-timeout-seconds", 10, "The number of seconds, between 1 and 30, the API server waits for the pod webhook.")
+	pflag.StringVar(&podWebhookNamespaceSelector, "pod-webhook-namespace-selector", "", "The label selector of the namespaces whose pods are sent to the pod webhook, such as 'injection notin (disabled)'. Default is empty string which selects all the namespaces, or the watched ones when WATCH_NAMESPACE is set.")
+	pflag.StringVar(&podWebhookObjectSelector, "pod-webhook-object-selector", "", "The label selector of the pods sent to the pod webhook. Default is empty string which selects all the pods.")
+	pflag.IntVar(&podWebhookShards, "pod-webhook-shards", 1, "The number of shards of the pod webhook, each called for its share of the namespaces through the webhook service suffixed with -shard-<n>, such as cloudwatch-webhook-service-shard-1, except the first one. Requires --pod-webhook-configuration.")
+	pflag.StringVar(&operatorDeployment, "operator-deployment", "", "The namespace/name of the deployment of the operator, such as amazon-cloudwatch/cloudwatch-controller-manager, whose replicas, pod anti-affinity and PodDisruptionBudget are managed by the operator to keep its webhooks available. Default is empty string which leaves the deployment as deployed.")
+	pflag.Int32Var(&availabilityOptions.Replicas, "operator-replicas", availability.DefaultReplicas, "The number of replicas of the deployment of the operator, which requires --leader-elect when more than 1.")
+	pflag.StringVar(&operatorPDBMinAvailable, "operator-pdb-min-available", "1", "The number or percentage of the replicas of the operator the PodDisruptionBudget keeps available during the node drains.")
+	pflag.BoolVar(&availabilityOptions.AntiAffinity, "operator-anti-affinity", true, "Prefer scheduling the replicas of the operator on different nodes.")
+	pflag.StringVar(&webhookCertSecret, "webhook-cert-secret", "", "The namespace/name of the Secret, such as amazon-cloudwatch/cloudwatch-webhook-server-cert, the operator issues and rotates the certificates of its webhooks in, for the clusters without cert-manager. Default is empty string which reads the certificates provisioned by cert-manager from the webhook certificate directory.")
+	pflag.StringVar(&webhookCertService, "webhook-cert-service", "amazon-cloudwatch/cloudwatch-webhook-service", "The namespace/name of the webhook service the self-managed serving certificate is issued for.")
+	pflag.StringSliceVar(&webhookCertConfigurations, "webhook-cert-configurations", []string{"cloudwatch-mutating-webhook-configuration", "cloudwatch-validating-webhook-configuration"}, "The names of the webhook configurations whose CA bundle is managed along with the self-managed certificates.")
+	pflag.StringSliceVar(&webhookCertCRDs, "webhook-cert-crds", []string{"amazoncloudwatchagents.cloudwatch.aws.amazon.com", "instrumentations.cloudwatch.aws.amazon.com"}, "The names of the CustomResourceDefinitions whose conversion webhook CA bundle is managed along with the self-managed certificates.")
+	pflag.StringVar(&webhookCertDir, "webhook-cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"), "The directory the webhook server reads its serving certificate from, which has to be writable when --webhook-cert-secret is set.")
+	pflag.DurationVar(&webhookCertMinValidity, "webhook-cert-min-validity", readiness.DefaultCertMinValidity, "The remaining validity of the webhook serving certificate under which the replicas of the operator report not ready.")
+	pflag.BoolVar(&adoptOpenTelemetryResources, "adopt-opentelemetry-resources", false, "Convert the opentelemetry.io Instrumentation and OpenTelemetryCollector resources of the OpenTelemetry operator to Instrumentation and AmazonCloudWatchAgent resources of the same name, to migrate from the OpenTelemetry operator.")
+	pflag.BoolVar(&migrateStorageVersions, "migrate-storage-versions", true, "Rewrite the resources of the operator stored in other versions than the storage version of their CustomResourceDefinition when the leader starts, so that the versions which are no longer stored can be removed by the next upgrades. The cluster-scoped operators only.")
+	pflag.DurationVar(&orphanSweepInterval, "orphan-sweep-interval", cleanup.DefaultInterval, "The interval between two sweeps of the resources created for the custom resources which no longer exist. The sweeps are disabled when it is 0, the resources of a deleted AmazonCloudWatchAgent are still deleted by its finalizer.")
+	pflag.StringVar(&reloadPrometheusConfig, "reload-prometheus-config", "", "Run as the sidecar of an agent pod, which signals the agent to reload the Prometheus configuration of the file whenever it changes, instead of starting the operator.")
+	pflag.StringVar(&renderFile, "render", "", "Print the manifests the operator creates for the custom resources of the YAML file, or of the standard input when it is -, and exit without starting the operator.")
 	pflag.Parse()
 
 	// set instrumentation cpu and memory limits in environment variables to be used for default instrumentation; default values received from https://github.com/open-telemetry/opentelemetry-operator/blob/main/apis/v1alpha1/instrumentation_webhook.go
@@ -181,9 +327,19 @@ func main() {
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 
+	if reloadPrometheusConfig != "" {
+		reloader := promreload.New(reloadPrometheusConfig, promreload.AgentProcess, promreload.DefaultInterval, logger.WithName("prometheus-config-reloader"))
+		if err = reloader.Start(ctrl.SetupSignalHandler()); err != nil {
+			setupLog.Error(err, "unable to reload the Prometheus configuration")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	logger.Info("Starting the Amazon CloudWatch Agent Operator",
 		"amazon-cloudwatch-agent-operator", v.Operator,
 		"cloudwatch-agent", agentImage,
+		"cloudwatch-agent-fips", agentFIPSImage,
 		"auto-instrumentation-java", autoInstrumentationJava,
 		"auto-instrumentation-python", autoInstrumentationPython,
 		"auto-instrumentation-dotnet", autoInstrumentationDotNet,
@@ -191,16 +347,105 @@ func main() {
 		"dcgm-exporter", dcgmExporterImage,
 		"neuron-monitor", neuronMonitorImage,
 		"amazon-cloudwatch-agent-target-allocator", targetAllocatorImage,
+		"fluent-bit", fluentBitImage,
+		"prometheus-config-reloader", promConfigReloaderImage,
 		"build-date", v.BuildDate,
 		"go-version", v.Go,
 		"go-arch", runtime.GOARCH,
 		"go-os", runtime.GOOS,
 	)
 
+	var watchNamespaces []string
+	if watchNamespace, found := os.LookupEnv("WATCH_NAMESPACE"); found {
+		for _, ns := range strings.Split(watchNamespace, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				watchNamespaces = append(watchNamespaces, ns)
+			}
+		}
+	}
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("watching namespace(s), the operator is namespace-scoped", "namespaces", watchNamespaces)
+	} else {
+		setupLog.Info("the env var WATCH_NAMESPACE isn't set, watching all namespaces")
+	}
+
+	if !slices.Contains(config.CloudProviders, cloudProvider) {
+		setupLog.Error(fmt.Errorf("unknown cloud provider %q, expected one of %s", cloudProvider, strings.Join(config.CloudProviders, ", ")), "invalid cloud provider")
+		os.Exit(1)
+	}
+
+	if ecrPullThroughCachePrefix != "" && imageRegistry != "" {
+		setupLog.Info("ignoring --ecr-pull-through-cache-prefix, the default images are pulled from --image-registry")
+		ecrPullThroughCachePrefix = ""
+	}
+	if injectAWSResource || ecrPullThroughCachePrefix != "" {
+		reader, clientErr := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if clientErr != nil {
+			setupLog.Error(clientErr, "unable to create the client detecting the AWS resource")
+			os.Exit(1)
+		}
+		if awsResource, err = awsresource.Detect(context.Background(), reader, awsResource, os.Getenv); err != nil {
+			setupLog.Error(err, "unable to detect the AWS region, set it with --aws-region")
+			os.Exit(1)
+		}
+	} else if awsResource != (awsresource.Resource{}) {
+		setupLog.Info("ignoring --aws-region and --aws-account-id without --inject-aws-resource-attributes or --ecr-pull-through-cache-prefix")
+		awsResource = awsresource.Resource{}
+	}
+	var injectedAWSResource awsresource.Resource
+	if injectAWSResource {
+		injectedAWSResource = awsResource
+		setupLog.Info("injecting the AWS resource attributes", "region", awsResource.Region, "account", awsResource.AccountID)
+	}
+	var regionalRegistry string
+	if ecrPullThroughCachePrefix != "" {
+		if awsResource.AccountID == "" {
+			setupLog.Error(fmt.Errorf("the operator has no IAM role for service accounts to detect the account from"), "unable to detect the AWS account, set it with --aws-account-id")
+			os.Exit(1)
+		}
+		regionalRegistry = config.RegionalECRRegistry(awsResource.Region, awsResource.AccountID, ecrPullThroughCachePrefix)
+		setupLog.Info("pulling the default images hosted on ECR Public from the regional ECR registry", "registry", regionalRegistry)
+	}
+
+	var imageVerifier config.ImageVerifier
+	var signatureVerifier *signature.Verifier
+	if imageVerification.Enabled() {
+		// the image pull secrets the registries are read with are only read when the images are verified, uncached
+		clientset, clientErr := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+		if clientErr != nil {
+			setupLog.Error(clientErr, "unable to create the client reading the image pull secrets")
+			os.Exit(1)
+		}
+		verifier, verifierErr := signature.New(imageVerification, clientset, nil)
+		if verifierErr != nil {
+			setupLog.Error(verifierErr, "invalid image signature verification")
+			os.Exit(1)
+		}
+		imageVerifier, signatureVerifier = verifier, verifier
+		setupLog.Info("verifying the cosign signatures of the images before deploying or injecting them")
+	}
+
+	var operatorDefaults config.Defaults
+	if operatorConfig != "" {
+		if operatorDefaults, err = config.ParseDefaults(operatorConfig); err != nil {
+			setupLog.Error(err, "invalid operator configuration")
+			os.Exit(1)
+		}
+		// the feature gates of the document override the ones of --feature-gates, as those of the ConfigMap do
+		for _, gate := range operatorDefaults.FeatureGates {
+			id, enabled := config.ParseFeatureGate(gate)
+			if err = colfeaturegate.GlobalRegistry().Set(id, enabled); err != nil {
+				setupLog.Error(err, "invalid feature gate of the operator configuration", "gate", gate)
+				os.Exit(1)
+			}
+		}
+	}
+
 	cfg := config.New(
 		config.WithLogger(ctrl.Log.WithName("config")),
 		config.WithVersion(v),
 		config.WithCollectorImage(agentImage),
+		config.WithCollectorFIPSImage(agentFIPSImage),
 		config.WithAutoInstrumentationJavaImage(autoInstrumentationJava),
 		config.WithAutoInstrumentationPythonImage(autoInstrumentationPython),
 		config.WithAutoInstrumentationDotNetImage(autoInstrumentationDotNet),
@@ -208,56 +453,148 @@ func main() {
 		config.WithDcgmExporterImage(dcgmExporterImage),
 		config.WithNeuronMonitorImage(neuronMonitorImage),
 		config.WithTargetAllocatorImage(targetAllocatorImage),
+		config.WithFluentBitImage(fluentBitImage),
+		config.WithPrometheusConfigReloaderImage(promConfigReloaderImage),
+		config.WithImageRegistry(imageRegistry),
+		config.WithImagePullSecrets(imagePullSecrets),
+		config.WithRegionalRegistry(regionalRegistry),
+		config.WithImageVerifier(imageVerifier),
+		config.WithLegacyAgentRBAC(legacyAgentRBAC),
+		config.WithWatchNamespaces(watchNamespaces),
+		config.WithFargateAgent(fargateAgent),
+		config.WithDefaults(operatorDefaults),
+		config.WithCloudProvider(cloudProvider),
+		config.WithClusterName(clusterName),
+		config.WithAWSResource(injectedAWSResource.Region, injectedAWSResource.AccountID),
 	)
 
-	watchNamespace, found := os.LookupEnv("WATCH_NAMESPACE")
-	if found {
-		setupLog.Info("watching namespace(s)", "namespaces", watchNamespace)
-	} else {
-		setupLog.Info("the env var WATCH_NAMESPACE isn't set, watching all namespaces")
+	if renderFile != "" {
+		if err = renderManifests(renderFile, cfg); err != nil {
+			setupLog.Error(err, "unable to render the manifests")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	shutdownTracing := func(context.Context) error { return nil }
+	if tracingEndpoint != "" {
+		shutdownTracing, err = telemetry.SetupTracing(context.Background(), tracingEndpoint, tracingSampleRatio, v.Operator)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		setupLog.Info("Tracing is enabled", "endpoint", tracingEndpoint, "sample-ratio", tracingSampleRatio)
 	}
 
 	optionsTlSOptsFuncs := []func(*tls.Config){
 		func(config *tls.Config) { tlsConfigSetting(config, tlsOpt) },
 	}
 	var namespaces map[string]cache.Config
-	if strings.Contains(watchNamespace, ",") {
+	if len(watchNamespaces) > 0 {
 		namespaces = map[string]cache.Config{}
-		for _, ns := range strings.Split(watchNamespace, ",") {
+		for _, ns := range watchNamespaces {
 			namespaces[ns] = cache.Config{}
 		}
 	}
 
+	for name, count := range maxConcurrentReconciles {
+		if !slices.Contains(controllerNames, name) {
+			setupLog.Error(fmt.Errorf("unknown controller %q, expected one of %v", name, controllerNames), "invalid max concurrent reconciles")
+			os.Exit(1)
+		}
+		if count < 1 {
+			setupLog.Error(fmt.Errorf("the max concurrent reconciles of the controller %s is %d, it has to be at least 1", name, count), "invalid max concurrent reconciles")
+			os.Exit(1)
+		}
+	}
+
+	if reconcileBaseDelay <= 0 || reconcileMaxDelay < reconcileBaseDelay || reconcileQPS <= 0 || reconcileBurst < 1 {
+		setupLog.Error(fmt.Errorf("--reconcile-base-delay has to be positive and at most --reconcile-max-delay, --reconcile-qps and --reconcile-burst positive"), "invalid reconcile rate limits")
+		os.Exit(1)
+	}
+
 	mgrOptions := ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
 		HealthProbeBindAddress: probeAddr,
-		PprofBindAddress:       pprofAddr,
-		WebhookServer: webhook.NewServer(webhook.Options{
+		WebhookServer: telemetry.TracedWebhookServer(webhook.NewServer(webhook.Options{
 			Port:    webhookPort,
+			CertDir: webhookCertDir,
 			TLSOpts: optionsTlSOptsFuncs,
-		}),
+		})),
 		Cache: cache.Options{
 			DefaultNamespaces: namespaces,
 		},
+		LeaderElection:                leaderElection,
+		LeaderElectionID:              leaderElectionID,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = kubeAPIQPS
+	restConfig.Burst = kubeAPIBurst
+	mgr, err := ctrl.NewManager(restConfig, mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// the reconcile and admission webhook metrics are reported by controller-runtime, the managed instances are
+	// counted on each scrape
+	metrics.Registry.MustRegister(controllers.NewManagedObjectsCollector(mgr.GetClient(), ctrl.Log.WithName("metrics")))
+	if pprofAddr != "" {
+		diagnostics, err := telemetry.NewDiagnosticsServer(pprofAddr, v)
+		if err != nil {
+			setupLog.Error(err, "unable to set up the diagnostics server")
+			os.Exit(1)
+		}
+		if err = mgr.Add(diagnostics); err != nil {
+			setupLog.Error(err, "unable to register the diagnostics server")
+			os.Exit(1)
+		}
+	}
+	if emfOptions.Endpoint != "" {
+		reporter, err := telemetry.NewEMFReporter(metrics.Registry, emfOptions, ctrl.Log.WithName("emf"))
+		if err != nil {
+			setupLog.Error(err, "unable to set up the EMF report of the operator metrics")
+			os.Exit(1)
+		}
+		if err = mgr.Add(reporter); err != nil {
+			setupLog.Error(err, "unable to register the EMF report of the operator metrics")
+			os.Exit(1)
+		}
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 
+	// every replica serves the webhooks, which find the default images verified rather than reaching the registries
+	if signatureVerifier != nil {
+		go refreshImageVerifications(ctx, signatureVerifier, cfg)
+	}
+
+	sweeper := cleanup.New(mgr.GetAPIReader(), mgr.GetClient(), watchNamespaces, orphanSweepInterval, ctrl.Log.WithName("cleanup"))
+	if orphanSweepInterval > 0 {
+		if err = mgr.Add(sweeper); err != nil {
+			setupLog.Error(err, "unable to register the sweep of the orphaned resources")
+			os.Exit(1)
+		}
+	}
+
 	if err = controllers.NewReconciler(controllers.Params{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("AmazonCloudWatchAgent"),
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
 		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+		Sweeper:  sweeper,
+
+		MaxConcurrentReconciles: maxConcurrentReconciles["AmazonCloudWatchAgent"],
+		RateLimiter:             reconcileRateLimiter(reconcileBaseDelay, reconcileMaxDelay, reconcileQPS, reconcileBurst),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AmazonCloudWatchAgent")
 		os.Exit(1)
@@ -269,6 +606,9 @@ func main() {
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
 		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+
+		MaxConcurrentReconciles: maxConcurrentReconciles["DcgmExporter"],
+		RateLimiter:             reconcileRateLimiter(reconcileBaseDelay, reconcileMaxDelay, reconcileQPS, reconcileBurst),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DcgmExporter")
 		os.Exit(1)
@@ -280,14 +620,192 @@ func main() {
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
 		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+
+		MaxConcurrentReconciles: maxConcurrentReconciles["NeuronMonitor"],
+		RateLimiter:             reconcileRateLimiter(reconcileBaseDelay, reconcileMaxDelay, reconcileQPS, reconcileBurst),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NeuronMonitor")
 		os.Exit(1)
 	}
 
+	channel, err := upgrade.ParseChannel(upgradeChannel)
+	if err != nil {
+		setupLog.Error(err, "invalid upgrade channel")
+		os.Exit(1)
+	}
+	upgradeInstrumentations := func(c context.Context) {
+		instrumentationUpgrade := &upgrade.InstrumentationUpgrade{
+			Client:                     mgr.GetClient(),
+			Logger:                     ctrl.Log.WithName("instrumentation-upgrade"),
+			Recorder:                   mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			DefaultAutoInstJava:        cfg.AutoInstrumentationJavaImage(),
+			DefaultAutoInstNodeJS:      cfg.AutoInstrumentationNodeJSImage(),
+			DefaultAutoInstPython:      cfg.AutoInstrumentationPythonImage(),
+			DefaultAutoInstDotNet:      cfg.AutoInstrumentationDotNetImage(),
+			DefaultAutoInstApacheHttpd: cfg.AutoInstrumentationApacheHttpdImage(),
+			DefaultAutoInstNginx:       cfg.AutoInstrumentationNginxImage(),
+			DefaultAutoInstGo:          cfg.AutoInstrumentationGoImage(),
+			DefaultChannel:             channel,
+		}
+		if err := instrumentationUpgrade.ManagedInstances(c); err != nil {
+			setupLog.Error(err, "failed to upgrade Instrumentation instances")
+		}
+	}
+	err = mgr.Add(manager.RunnableFunc(func(c context.Context) error {
+		agentUpgrade := &upgrade.AgentUpgrade{
+			Client:         mgr.GetClient(),
+			Logger:         ctrl.Log.WithName("agent-upgrade"),
+			Recorder:       mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			DefaultImage:   cfg.CollectorImage(),
+			DefaultChannel: channel,
+		}
+		if err := agentUpgrade.ManagedInstances(c); err != nil {
+			setupLog.Error(err, "failed to upgrade AmazonCloudWatchAgent instances")
+		}
+		upgradeInstrumentations(c)
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to register the upgrade of managed instances")
+		os.Exit(1)
+	}
+
+	if podWebhookShards > 1 && (podWebhookConfiguration == "" || cfg.NamespaceScoped()) {
+		setupLog.Error(fmt.Errorf("--pod-webhook-shards requires --pod-webhook-configuration and a cluster-scoped operator"), "invalid pod webhook shards")
+		os.Exit(1)
+	}
+	if podWebhookConfiguration != "" {
+		policy, err := podWebhookPolicy(podWebhookFailurePolicy, podWebhookTimeoutSeconds, podWebhookNamespaceSelector, podWebhookObjectSelector, podWebhookShards, watchNamespaces)
+		if err != nil {
+			setupLog.Error(err, "invalid pod webhook policy")
+			os.Exit(1)
+		}
+		err = mgr.Add(manager.RunnableFunc(func(c context.Context) error {
+			if err := podmutation.ApplyPolicy(c, mgr.GetClient(), podWebhookConfiguration, policy); err != nil {
+				setupLog.Error(err, "failed to apply the pod webhook policy", "configuration", podWebhookConfiguration)
+			}
+			return nil
+		}))
+		if err != nil {
+			setupLog.Error(err, "unable to register the pod webhook policy")
+			os.Exit(1)
+		}
+		if podWebhookShards > 1 {
+			labeler := podmutation.NewShardLabeler(mgr.GetAPIReader(), mgr.GetClient(), podWebhookShards, podmutation.DefaultShardLabelInterval, ctrl.Log.WithName("webhook-shards"))
+			if err = mgr.Add(labeler); err != nil {
+				setupLog.Error(err, "unable to register the labelling of the namespaces with their webhook shard")
+				os.Exit(1)
+			}
+		}
+	}
+
+	if operatorDeployment != "" {
+		key, err := parseNamespacedName(operatorDeployment)
+		if err != nil {
+			setupLog.Error(err, "invalid operator deployment")
+			os.Exit(1)
+		}
+		availabilityOptions.MinAvailable = intstr.Parse(operatorPDBMinAvailable)
+		if err = availabilityOptions.Validate(); err != nil {
+			setupLog.Error(err, "invalid operator availability")
+			os.Exit(1)
+		}
+		if availabilityOptions.Replicas > 1 && !leaderElection {
+			setupLog.Error(fmt.Errorf("%d replicas of the operator require --leader-elect", availabilityOptions.Replicas), "invalid operator availability")
+			os.Exit(1)
+		}
+		err = mgr.Add(manager.RunnableFunc(func(c context.Context) error {
+			if err := availability.Apply(c, mgr.GetClient(), key, availabilityOptions); err != nil {
+				setupLog.Error(err, "failed to apply the availability settings of the operator")
+			}
+			return nil
+		}))
+		if err != nil {
+			setupLog.Error(err, "unable to register the availability settings of the operator")
+			os.Exit(1)
+		}
+	}
+
+	if operatorConfigMap != "" {
+		key, err := parseNamespacedName(operatorConfigMap)
+		if err != nil {
+			setupLog.Error(err, "invalid operator configuration ConfigMap")
+			os.Exit(1)
+		}
+		// the managed instrumentations which use the default images follow the new defaults
+		cfg.RegisterChangeCallback(func() error {
+			upgradeInstrumentations(ctx)
+			return nil
+		})
+		reloader := config.NewDefaultsReloader(mgr.GetAPIReader(), key, cfg, colfeaturegate.GlobalRegistry(), operatorConfigReloadInterval, ctrl.Log.WithName("operator-config"))
+		if err = mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to register the reload of the operator configuration")
+			os.Exit(1)
+		}
+	}
+
+	var certRotator *certrotation.Rotator
+	if webhookCertSecret != "" && os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		secret, err := parseNamespacedName(webhookCertSecret)
+		if err != nil {
+			setupLog.Error(err, "invalid webhook certificate Secret")
+			os.Exit(1)
+		}
+		service, err := parseNamespacedName(webhookCertService)
+		if err != nil {
+			setupLog.Error(err, "invalid webhook certificate service")
+			os.Exit(1)
+		}
+		// the manager client reads from its cache, which is only started along with the webhook server that needs
+		// the certificates
+		certClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			setupLog.Error(err, "unable to create the webhook certificate client")
+			os.Exit(1)
+		}
+		var shardServices []types.NamespacedName
+		for shard := 1; shard < podWebhookShards; shard++ {
+			shardServices = append(shardServices, podmutation.ShardService(service, shard))
+		}
+		certRotator = certrotation.New(certClient, certrotation.Options{
+			Secret:                    secret,
+			Service:                   service,
+			AdditionalServices:        shardServices,
+			WebhookConfigurations:     webhookCertConfigurations,
+			CustomResourceDefinitions: webhookCertCRDs,
+			CertDir:                   webhookCertDir,
+		}, ctrl.Log.WithName("webhook-certs"))
+		if err = mgr.Add(certRotator); err != nil {
+			setupLog.Error(err, "unable to register the webhook certificate rotation")
+			os.Exit(1)
+		}
+		if err = mgr.Add(certRotator.Syncer()); err != nil {
+			setupLog.Error(err, "unable to register the webhook certificate sync")
+			os.Exit(1)
+		}
+	}
+
+	if adoptOpenTelemetryResources {
+		adopter := adoption.New(mgr.GetAPIReader(), mgr.GetClient(), watchNamespaces, adoption.DefaultInterval, ctrl.Log.WithName("adoption"))
+		if err = mgr.Add(adopter); err != nil {
+			setupLog.Error(err, "unable to register the adoption of the OpenTelemetry operator resources")
+			os.Exit(1)
+		}
+	}
+
+	// a namespace-scoped operator can neither rewrite the resources of the other namespaces, nor update the
+	// CustomResourceDefinitions
+	if migrateStorageVersions && !cfg.NamespaceScoped() {
+		migrator := storagemigration.New(mgr.GetClient(), storagemigration.CustomResourceDefinitions, ctrl.Log.WithName("storage-migration"))
+		if err = mgr.Add(migrator); err != nil {
+			setupLog.Error(err, "unable to register the storage version migration")
+			os.Exit(1)
+		}
+	}
+
 	decoder := admission.NewDecoder(mgr.GetScheme())
 
-	instrumentationAnnotator := auto.CreateInstrumentationAnnotator(autoMonitorConfigStr, autoAnnotationConfigStr, ctx, mgr.GetClient(), mgr.GetAPIReader(), setupLog)
+	instrumentationAnnotator := auto.CreateInstrumentationAnnotator(autoMonitorConfigStr, autoAnnotationConfigStr, ctx, mgr.GetConfig(), mgr.GetClient(), mgr.GetAPIReader(), setupLog)
 
 	if instrumentationAnnotator != nil {
 		mgr.GetWebhookServer().Register("/mutate-v1-workload", &webhook.Admission{
@@ -323,7 +841,7 @@ func main() {
 			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(),
 				[]podmutation.PodMutator{
 					sidecar.NewMutator(logger, cfg, mgr.GetClient()),
-					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")),
+					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"), cfg),
 				}),
 		})
 	} else {
@@ -335,16 +853,47 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	// the replicas are only ready, and thus only sent admission requests by the webhook service, once they serve them
+	readyChecker := healthz.Ping
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		readyChecker = mgr.GetWebhookServer().StartedChecker()
+	}
+	if err := mgr.AddReadyzCheck("readyz", readyChecker); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// the webhook server fails the TLS handshakes of the API server without a valid serving certificate
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := mgr.AddReadyzCheck("webhook-cert", readiness.WebhookCertificate(webhookCertDir, webhookCertMinValidity)); err != nil {
+			setupLog.Error(err, "unable to set up the webhook certificate ready check")
+			os.Exit(1)
+		}
+	}
+	// the webhooks read the Instrumentation and agent resources, the CustomResourceDefinitions can't be read by a
+	// namespace-scoped operator
+	if !cfg.NamespaceScoped() {
+		crdChecker := readiness.CustomResourceDefinitions(mgr.GetAPIReader(), storagemigration.CustomResourceDefinitions, readiness.DefaultInterval, ctrl.Log.WithName("readiness"))
+		if err := mgr.AddReadyzCheck("crds", crdChecker); err != nil {
+			setupLog.Error(err, "unable to set up the CustomResourceDefinitions ready check")
+			os.Exit(1)
+		}
+	}
+
+	if certRotator != nil {
+		if err := certRotator.Bootstrap(ctx); err != nil {
+			setupLog.Error(err, "unable to bootstrap the webhook certificates")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "failed to flush the traces")
+	}
 }
 
 func waitForWebhookServerStart(ctx context.Context, checker healthz.Checker, callback func(context.Context)) {
@@ -367,6 +916,45 @@ func waitForWebhookServerStart(ctx context.Context, checker healthz.Checker, cal
 // This function get the option from command argument (tlsConfig), check the validity through k8sapiflag
 // and set the config for webhook server.
 // refer to https://pkg.go.dev/k8s.io/component-base/cli/flag
+// podWebhookPolicy returns the policy of the pod webhook of the flags. The namespace selector of a namespace-scoped
+// operator defaults to its watched namespaces.
+func podWebhookPolicy(failurePolicy string, timeoutSeconds int32, namespaceSelector, objectSelector string, shards int, watchNamespaces []string) (podmutation.Policy, error) {
+	policy := podmutation.Policy{
+		FailurePolicy:  admissionregistrationv1.FailurePolicyType(failurePolicy),
+		TimeoutSeconds: timeoutSeconds,
+		Shards:         shards,
+	}
+	var err error
+	if namespaceSelector != "" {
+		if policy.NamespaceSelector, err = metav1.ParseToLabelSelector(namespaceSelector); err != nil {
+			return policy, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+	} else if len(watchNamespaces) > 0 {
+		policy.NamespaceSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      corev1.LabelMetadataName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   watchNamespaces,
+			}},
+		}
+	}
+	if objectSelector != "" {
+		if policy.ObjectSelector, err = metav1.ParseToLabelSelector(objectSelector); err != nil {
+			return policy, fmt.Errorf("invalid object selector: %w", err)
+		}
+	}
+	return policy, policy.Validate()
+}
+
+// parseNamespacedName parses a namespace/name reference.
+func parseNamespacedName(s string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(s, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("expected namespace/name, got %q", s)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
 func tlsConfigSetting(cfg *tls.Config, tlsOpt tlsConfig) {
 	// TLSVersion helper function returns the TLS Version ID for the version name passed.
 	tlsVersion, err := k8sapiflag.TLSVersion(tlsOpt.minVersion)
@@ -382,3 +970,47 @@ func tlsConfigSetting(cfg *tls.Config, tlsOpt tlsConfig) {
 	}
 	cfg.CipherSuites = cipherSuiteIDs
 }
+
+// renderManifests prints the manifests of the custom resources of the file, or of the standard input when it is -.
+func renderManifests(file string, cfg config.Config) error {
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return render.Render(context.Background(), render.Options{
+		Config: cfg,
+		Scheme: scheme,
+		Log:    ctrl.Log.WithName("render"),
+	}, in, os.Stdout)
+}
+
+// reconcileRateLimiter returns the rate limiter of a controller, which retries the failed reconciles of an instance
+// with an exponential backoff and bounds the reconciles of all the instances with a token bucket.
+func reconcileRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
+
+// refreshImageVerifications verifies the default images at startup and then periodically, so that their
+// verifications stay cached for the webhooks injecting them.
+func refreshImageVerifications(ctx context.Context, verifier *signature.Verifier, cfg config.Config) {
+	ticker := time.NewTicker(signature.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := verifier.Refresh(ctx, cfg.DefaultImages()); err != nil && ctx.Err() == nil {
+			setupLog.Error(err, "failed to verify the signatures of the default images")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
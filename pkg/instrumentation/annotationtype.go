@@ -49,6 +49,8 @@ const (
 
 var (
 	SupportedTypes = NewTypeSet(TypeJava, TypeNodeJS, TypePython, TypeDotNet)
+	// WindowsSupportedTypes are the types that can be injected into Windows pods.
+	WindowsSupportedTypes = NewTypeSet(TypeJava, TypeDotNet)
 )
 
 // InjectAnnotationKey maps the instrumentation type to the inject annotation.
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	// annotationAWSApplication is the namespace annotation holding the application ARN. An annotation is used
+	// instead of a label because label values cannot contain an ARN.
+	annotationAWSApplication = "cloudwatch.aws.amazon.com/aws-application"
+
+	awsApplicationAttribute = "aws.application"
+)
+
+// applicationARN returns the myApplications ARN of the workloads instrumented by otelinst in the namespace.
+func applicationARN(otelinst v1alpha1.Instrumentation, ns corev1.Namespace) string {
+	if otelinst.Spec.Resource.ApplicationARN != "" {
+		return otelinst.Spec.Resource.ApplicationARN
+	}
+	return ns.Annotations[annotationAWSApplication]
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestApplicationARN(t *testing.T) {
+	const (
+		instARN = "arn:aws:resource-groups:us-west-2:123456789012:group/checkout/0abc"
+		nsARN   = "arn:aws:resource-groups:us-west-2:123456789012:group/payments/0def"
+	)
+	inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{Resource: v1alpha1.Resource{ApplicationARN: instARN}}}
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationAWSApplication: nsARN}}}
+
+	assert.Equal(t, "", applicationARN(v1alpha1.Instrumentation{}, corev1.Namespace{}))
+	assert.Equal(t, instARN, applicationARN(inst, ns))
+	assert.Equal(t, nsARN, applicationARN(v1alpha1.Instrumentation{}, ns))
+}
+
+func TestCreateResourceMapApplicationARN(t *testing.T) {
+	const arn = "arn:aws:resource-groups:us-west-2:123456789012:group/checkout/0abc"
+	injector := &sdkInjector{logger: logr.Discard()}
+	inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{Resource: v1alpha1.Resource{ApplicationARN: arn}}}
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+
+	res, _ := injector.createResourceMap(context.Background(), inst, ns, pod, 0)
+	assert.Equal(t, arn, res[awsApplicationAttribute])
+
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "aws.application=custom"}}
+	res, existing := injector.createResourceMap(context.Background(), inst, ns, pod, 0)
+	assert.NotContains(t, res, awsApplicationAttribute)
+	assert.True(t, existing[awsApplicationAttribute])
+}
@@ -21,6 +21,9 @@ import (
 const (
 	autoAnnotatePrefix     = "cloudwatch.aws.amazon.com/auto-annotate-"
 	defaultAnnotationValue = "true"
+	// listPageSize is the number of objects listed per request, so that only a page of the workloads of a large
+	// cluster is held in memory at a time.
+	listPageSize = 500
 )
 
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;patch
@@ -79,13 +82,24 @@ func (m *AnnotationMutators) mutateObject(obj client.Object, _ any) (any, bool)
 	}
 }
 
+// rangeObjectList calls fn for each object of the list, which is listed a page at a time.
 func rangeObjectList(m InstrumentationAnnotator, ctx context.Context, list client.ObjectList, option client.ListOption, fn objectCallbackFunc) {
-	if err := m.GetReader().List(ctx, list, option); err != nil {
-		m.GetLogger().Error(err, "Unable to list objects",
-			"kind", fmt.Sprintf("%T", list),
-		)
-		return
+	var continueToken string
+	for {
+		if err := m.GetReader().List(ctx, list, option, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			m.GetLogger().Error(err, "Unable to list objects",
+				"kind", fmt.Sprintf("%T", list),
+			)
+			return
+		}
+		rangeObjects(list, fn)
+		if continueToken = list.GetContinue(); continueToken == "" {
+			return
+		}
 	}
+}
+
+func rangeObjects(list client.ObjectList, fn objectCallbackFunc) {
 	switch l := list.(type) {
 	case *corev1.NamespaceList:
 		for _, item := range l.Items {
@@ -6,6 +6,7 @@ package auto
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -502,6 +503,47 @@ func TestAnnotationMutators_ClientErrors(t *testing.T) {
 	errClient.AssertCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
+// pagedReader lists the deployments pageSize at a time, the continue token being the index of the next page.
+type pagedReader struct {
+	client.Reader
+	deployments []appsv1.Deployment
+	pageSize    int
+	limits      []int64
+}
+
+func (r *pagedReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+	r.limits = append(r.limits, listOptions.Limit)
+	start := 0
+	if listOptions.Continue != "" {
+		start, _ = strconv.Atoi(listOptions.Continue)
+	}
+	end := min(start+r.pageSize, len(r.deployments))
+	deploymentList := list.(*appsv1.DeploymentList)
+	deploymentList.Items = r.deployments[start:end]
+	deploymentList.Continue = ""
+	if end < len(r.deployments) {
+		deploymentList.Continue = strconv.Itoa(end)
+	}
+	return nil
+}
+
+func TestRangeObjectList_Pages(t *testing.T) {
+	reader := &pagedReader{pageSize: 2}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		reader.deployments = append(reader.deployments, appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}})
+	}
+	mutators := NewAnnotationMutators(nil, reader, logr.Discard(), AnnotationConfig{}, instrumentation.NewTypeSet(instrumentation.TypeJava))
+
+	var names []string
+	rangeObjectList(mutators, context.Background(), &appsv1.DeploymentList{}, &client.ListOptions{}, func(obj client.Object, _ any) (any, bool) {
+		names = append(names, obj.GetName())
+		return nil, true
+	})
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+	assert.Equal(t, []int64{listPageSize, listPageSize, listPageSize}, reader.limits)
+}
+
 func TestAnnotateKey(t *testing.T) {
 	testCases := []struct {
 		instType instrumentation.Type
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExclusionConfig describes workloads that monitorAllServices never selects. Unlike MonitorConfig.Exclude, the
+// exclusions apply to all languages. Workloads selected through the custom selector are not affected.
+type ExclusionConfig struct {
+	// NamespaceSelector excludes the workloads in namespaces whose labels match the selector.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// WorkloadSelector excludes the workloads whose labels, or pod template labels, match the selector.
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+	// Workloads excludes workloads by namespaced name (namespace/name). Both parts may contain shell patterns,
+	// e.g. "batch-*/*" or "*/nightly-*".
+	Workloads []string `json:"workloads,omitempty"`
+	// Kinds excludes all workloads of the given kinds, e.g. DaemonSet.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// workloadExcluder is the compiled form of ExclusionConfig.
+type workloadExcluder struct {
+	namespaceSelector labels.Selector
+	workloadSelector  labels.Selector
+	workloads         []string
+	kinds             []string
+}
+
+func newWorkloadExcluder(cfg ExclusionConfig) (*workloadExcluder, error) {
+	excluder := &workloadExcluder{
+		workloads: cfg.Workloads,
+		kinds:     cfg.Kinds,
+	}
+	var err error
+	if cfg.NamespaceSelector != nil {
+		if excluder.namespaceSelector, err = metav1.LabelSelectorAsSelector(cfg.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("invalid exclusion namespace selector: %w", err)
+		}
+	}
+	if cfg.WorkloadSelector != nil {
+		if excluder.workloadSelector, err = metav1.LabelSelectorAsSelector(cfg.WorkloadSelector); err != nil {
+			return nil, fmt.Errorf("invalid exclusion workload selector: %w", err)
+		}
+	}
+	for _, pattern := range cfg.Workloads {
+		if _, err = path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclusion workload pattern %q: %w", pattern, err)
+		}
+	}
+	return excluder, nil
+}
+
+// hasNamespaceSelector returns true if namespace labels are needed to evaluate the exclusions.
+func (e *workloadExcluder) hasNamespaceSelector() bool {
+	return e != nil && e.namespaceSelector != nil
+}
+
+// excludes returns true if the workload matches any of the exclusions. namespaceLabels are only consulted when a
+// namespace selector is configured.
+func (e *workloadExcluder) excludes(obj client.Object, namespaceLabels labels.Set) bool {
+	if e == nil {
+		return false
+	}
+	if e.namespaceSelector != nil && e.namespaceSelector.Matches(namespaceLabels) {
+		return true
+	}
+	if e.workloadSelector != nil && (e.workloadSelector.Matches(labels.Set(obj.GetLabels())) || e.workloadSelector.Matches(getTemplateSpecLabels(obj))) {
+		return true
+	}
+	kind := workloadKind(obj)
+	for _, k := range e.kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	name := namespacedName(obj)
+	for _, pattern := range e.workloads {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func workloadKind(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return "Deployment"
+	case *appsv1.StatefulSet:
+		return "StatefulSet"
+	case *appsv1.DaemonSet:
+		return "DaemonSet"
+	default:
+		return ""
+	}
+}
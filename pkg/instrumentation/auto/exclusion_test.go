@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func TestNewWorkloadExcluder(t *testing.T) {
+	_, err := newWorkloadExcluder(ExclusionConfig{})
+	assert.NoError(t, err)
+
+	_, err = newWorkloadExcluder(ExclusionConfig{
+		WorkloadSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "telemetry", Operator: "Bogus"}},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = newWorkloadExcluder(ExclusionConfig{Workloads: []string{"default/[nightly"}})
+	assert.Error(t, err)
+}
+
+func TestWorkloadExcluder_excludes(t *testing.T) {
+	excluder, err := newWorkloadExcluder(ExclusionConfig{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "batch"}},
+		WorkloadSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"telemetry": "custom"}},
+		Workloads:         []string{"jobs-*/*", "*/nightly-*"},
+		Kinds:             []string{"daemonset"},
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name            string
+		namespaceLabels labels.Set
+		workloadLabels  map[string]string
+		kind            string
+		expected        bool
+	}{
+		{name: "not excluded", expected: false},
+		{name: "namespace selector", namespaceLabels: labels.Set{"team": "batch"}, expected: true},
+		{name: "workload labels", workloadLabels: map[string]string{"telemetry": "custom"}, expected: true},
+		{name: "kind", kind: "DaemonSet", expected: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, wt := range workloadTypes {
+				if tt.kind != "" && tt.kind != wt.name {
+					continue
+				}
+				obj := wt.create("app", defaultNs, nil, nil)
+				obj.SetLabels(tt.workloadLabels)
+				expected := tt.expected || wt.name == "DaemonSet"
+				assert.Equal(t, expected, excluder.excludes(obj, tt.namespaceLabels), wt.name)
+			}
+		})
+	}
+
+	t.Run("pod template labels", func(t *testing.T) {
+		obj := newTestDeployment("app", defaultNs, map[string]string{"telemetry": "custom"}, nil)
+		assert.True(t, excluder.excludes(obj, nil))
+	})
+	t.Run("workload name patterns", func(t *testing.T) {
+		assert.True(t, excluder.excludes(newTestDeployment("app", "jobs-eu", nil, nil), nil))
+		assert.True(t, excluder.excludes(newTestStatefulSet("nightly-report", defaultNs, nil, nil), nil))
+		assert.False(t, excluder.excludes(newTestStatefulSet("report", defaultNs, nil, nil), nil))
+	})
+	t.Run("nil excluder", func(t *testing.T) {
+		var nilExcluder *workloadExcluder
+		assert.False(t, nilExcluder.excludes(newTestDeployment("app", defaultNs, nil, nil), nil))
+	})
+}
+
+func TestMonitor_ExclusionNamespaceSelector(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset()
+	c := fake2.NewFakeClient()
+
+	_, err := clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "batch",
+		Labels: map[string]string{"team": "batch"},
+	}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	createNamespace(t, clientset, ctx, "web")
+
+	config := simpleConfig(true, true, none, none)
+	config.Exclusions = ExclusionConfig{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "batch"}},
+	}
+	monitor := NewMonitor(ctx, config, clientset, c, c, testr.New(t))
+	require.NotNil(t, monitor)
+
+	podLabels := map[string]string{"app": "test"}
+	for _, ns := range []string{"batch", "web"} {
+		_, err = clientset.CoreV1().Services(ns).Create(ctx, newTestService("service", ns, podLabels), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, waitForInformerUpdate(monitor, func(numKeys int) bool { return numKeys == 2 }))
+	require.NoError(t, wait.PollUntilContextTimeout(ctx, time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return len(monitor.namespaceInformer.GetStore().ListKeys()) == 2, nil
+	}))
+
+	assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, newTestDeployment("app", "batch", podLabels, nil)))
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), monitor.MutateObject(nil, newTestDeployment("app", "web", podLabels, nil)))
+}
+
+func TestMonitor_InvalidExclusions(t *testing.T) {
+	config := simpleConfig(true, true, none, none)
+	config.Exclusions = ExclusionConfig{Workloads: []string{"["}}
+	c := fake2.NewFakeClient()
+	assert.Nil(t, NewMonitor(context.TODO(), config, fake.NewSimpleClientset(), c, c, testr.New(t)))
+}
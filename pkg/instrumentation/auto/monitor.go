@@ -17,6 +17,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -43,6 +44,7 @@ var excludedNamespaces = []string{
 	"external-dns",  // ExternalDNS controller
 
 	// --- Cloud Integrations ---
+	"amazon-cloudwatch",            // CloudWatch Agent, Fluent Bit and this operator
 	"aws-load-balancer-controller", // AWS Load Balancer Controller
 	"kube-system",                  // Cluster Autoscaler, CSI Drivers, Metrics Server
 	"external-dns",                 // ExternalDNS (sometimes deployed here too)
@@ -93,6 +95,10 @@ type Monitor struct {
 	deploymentInformer  cache.SharedIndexInformer
 	daemonsetInformer   cache.SharedIndexInformer
 	statefulsetInformer cache.SharedIndexInformer
+	namespaceInformer   cache.SharedIndexInformer
+	excluder            *workloadExcluder
+	selectors           languageSelectors
+	scheduler           *restartScheduler
 }
 
 func (m *Monitor) MutateAndPatchAll(ctx context.Context) {
@@ -111,6 +117,9 @@ func (m *Monitor) GetReader() client.Reader {
 }
 
 func (m *Monitor) GetWriter() client.Writer {
+	if m.scheduler != nil {
+		return &scheduledWriter{Writer: m.clientWriter, scheduler: m.scheduler}
+	}
 	return m.clientWriter
 }
 
@@ -121,10 +130,20 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 		logger.V(1).Info("Setting languages to default", "languages", instrumentation.SupportedTypes)
 		config.Languages = instrumentation.SupportedTypes
 	}
+	for t := range config.LanguageToggles {
+		if _, ok := instrumentation.SupportedTypes[t]; !ok {
+			logger.Info("W! Ignoring toggle for unsupported language", "language", t)
+		}
+	}
 
 	logger.V(1).Info("AutoMonitor starting...")
-	serviceFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod)
-	workloadFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod)
+	// the services and workloads of the excluded namespaces are never auto monitored, they are not watched
+	withoutExcludedNamespaces := informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.FieldSelector = excludedNamespacesFieldSelector().String()
+	})
+	serviceFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod, withoutExcludedNamespaces)
+	workloadFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod, withoutExcludedNamespaces)
+	namespaceFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod)
 
 	serviceInformer := serviceFactory.Core().V1().Services().Informer()
 	err := serviceInformer.SetTransform(func(obj interface{}) (interface{}, error) {
@@ -166,6 +185,37 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 
 	warnNonNamespacedNames(config.Exclude, logger)
 
+	excluder, err := newWorkloadExcluder(config.Exclusions)
+	if err != nil {
+		logger.Error(err, "failed to start auto monitor")
+		return nil
+	}
+
+	selectors, err := newLanguageSelectors(config.LabelSelector)
+	if err != nil {
+		logger.Error(err, "failed to start auto monitor")
+		return nil
+	}
+
+	var scheduler *restartScheduler
+	if config.Restart.enabled() {
+		scheduler, err = newRestartScheduler(config.Restart, logger.WithName("restart_scheduler"))
+		if err != nil {
+			logger.Error(err, "failed to start auto monitor")
+			return nil
+		}
+		scheduler.status = w
+	}
+
+	// namespace labels are only needed to evaluate the exclusion namespace selector
+	var namespaceInformer cache.SharedIndexInformer
+	if excluder.hasNamespaceSelector() {
+		namespaceInformer, err = createNamespaceInformer(namespaceFactory)
+		if err != nil {
+			logger.Error(err, "Creating namespace informer failed")
+		}
+	}
+
 	m := &Monitor{
 		serviceInformer:     serviceInformer,
 		ctx:                 ctx,
@@ -177,6 +227,10 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 		deploymentInformer:  deploymentInformer,
 		daemonsetInformer:   daemonsetInformer,
 		statefulsetInformer: statefulSetInformer,
+		namespaceInformer:   namespaceInformer,
+		excluder:            excluder,
+		selectors:           selectors,
+		scheduler:           scheduler,
 	}
 
 	_, err = serviceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -197,7 +251,7 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 
 	// initialize workload factory before service factory so workloads are available during onServiceEvent calls when
 	// service informer is initialized
-	factories := []informers.SharedInformerFactory{workloadFactory, serviceFactory}
+	factories := []informers.SharedInformerFactory{namespaceFactory, workloadFactory, serviceFactory}
 
 	for _, factory := range factories {
 		factory.Start(ctx.Done())
@@ -209,10 +263,38 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 		}
 	}
 
+	if scheduler != nil {
+		go scheduler.Run(ctx)
+	}
+
 	logger.V(1).Info("Initialization complete!")
 	return m
 }
 
+// excludedNamespacesFieldSelector selects the objects which are not in the excluded namespaces.
+func excludedNamespacesFieldSelector() fields.Selector {
+	selectors := make([]fields.Selector, 0, len(excludedNamespaces))
+	for _, namespace := range excludedNamespaces {
+		selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.namespace", namespace))
+	}
+	return fields.AndSelectors(selectors...)
+}
+
+// trimPodTemplate keeps the parts of the pod template the auto monitor reads, its metadata and the operating system of
+// its pods, so that the informers do not hold the containers of all the workloads of the cluster.
+func trimPodTemplate(template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: corev1.PodSpec{
+			OS:           template.Spec.OS,
+			NodeSelector: template.Spec.NodeSelector,
+		},
+	}
+}
+
 func createDaemonsetInformer(workloadFactory informers.SharedInformerFactory) (cache.SharedIndexInformer, error) {
 	daemonsetInformer := workloadFactory.Apps().V1().DaemonSets().Informer()
 	err := daemonsetInformer.SetTransform(func(obj interface{}) (interface{}, error) {
@@ -224,9 +306,10 @@ func createDaemonsetInformer(workloadFactory informers.SharedInformerFactory) (c
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      daemonset.Name,
 				Namespace: daemonset.Namespace,
+				Labels:    daemonset.Labels,
 			},
 			Spec: appsv1.DaemonSetSpec{
-				Template: daemonset.Spec.Template,
+				Template: trimPodTemplate(daemonset.Spec.Template),
 			},
 		}, nil
 	})
@@ -253,9 +336,10 @@ func createStatefulsetInformer(workloadFactory informers.SharedInformerFactory)
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      statefulSet.Name,
 				Namespace: statefulSet.Namespace,
+				Labels:    statefulSet.Labels,
 			},
 			Spec: appsv1.StatefulSetSpec{
-				Template: statefulSet.Spec.Template,
+				Template: trimPodTemplate(statefulSet.Spec.Template),
 			},
 		}, nil
 	})
@@ -282,9 +366,10 @@ func createDeploymentInformer(workloadFactory informers.SharedInformerFactory) (
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      deployment.Name,
 				Namespace: deployment.Namespace,
+				Labels:    deployment.Labels,
 			},
 			Spec: appsv1.DeploymentSpec{
-				Template: deployment.Spec.Template,
+				Template: trimPodTemplate(deployment.Spec.Template),
 			},
 		}, nil
 	})
@@ -301,6 +386,23 @@ func createDeploymentInformer(workloadFactory informers.SharedInformerFactory) (
 	return deploymentInformer, err
 }
 
+func createNamespaceInformer(factory informers.SharedInformerFactory) (cache.SharedIndexInformer, error) {
+	namespaceInformer := factory.Core().V1().Namespaces().Informer()
+	err := namespaceInformer.SetTransform(func(obj interface{}) (interface{}, error) {
+		namespace, ok := obj.(*corev1.Namespace)
+		if !ok {
+			return obj, fmt.Errorf("error transforming namespace: %s not a namespace", obj)
+		}
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace.Name,
+				Labels: namespace.Labels,
+			},
+		}, nil
+	})
+	return namespaceInformer, err
+}
+
 func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Service) {
 	if !m.config.RestartPods {
 		return
@@ -316,11 +418,15 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 		if err != nil {
 			m.logger.Error(err, "Failed to marshal resource")
 		}
-		deployment, err := m.k8sInterface.AppsV1().Deployments(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
-		if err != nil {
-			m.logger.Error(err, "failed to update deployment", "deployment", resource.Name)
-		}
-		m.logger.V(1).Info("Updated deployment", "deployment", deployment)
+		m.patchWorkload(&resource, func(ctx context.Context) error {
+			deployment, err := m.k8sInterface.AppsV1().Deployments(resource.GetNamespace()).Patch(ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
+			if err != nil {
+				m.logger.Error(err, "failed to update deployment", "deployment", resource.Name)
+				return err
+			}
+			m.logger.V(1).Info("Updated deployment", "deployment", deployment)
+			return nil
+		})
 	}
 	for _, resource := range m.listServiceStatefulSets(oldService, service) {
 		mutatedAnnotations := m.MutateObject(&resource, &resource).(map[string]string)
@@ -332,10 +438,13 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 			m.logger.Error(err, "Failed to marshal resource")
 		}
 
-		_, err = m.k8sInterface.AppsV1().StatefulSets(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
-		if err != nil {
-			m.logger.Error(err, "failed to update statefulset", "statefulset", resource.Name)
-		}
+		m.patchWorkload(&resource, func(ctx context.Context) error {
+			_, err := m.k8sInterface.AppsV1().StatefulSets(resource.GetNamespace()).Patch(ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
+			if err != nil {
+				m.logger.Error(err, "failed to update statefulset", "statefulset", resource.Name)
+			}
+			return err
+		})
 	}
 	for _, resource := range m.listServiceDaemonSets(oldService, service) {
 		mutatedAnnotations := m.MutateObject(&resource, &resource).(map[string]string)
@@ -346,13 +455,25 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 		if err != nil {
 			m.logger.Error(err, "Failed to marshal resource")
 		}
-		_, err = m.k8sInterface.AppsV1().DaemonSets(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
-		if err != nil {
-			m.logger.Error(err, "failed to update daemonset", "daemonset", resource.Name)
-		}
+		m.patchWorkload(&resource, func(ctx context.Context) error {
+			_, err := m.k8sInterface.AppsV1().DaemonSets(resource.GetNamespace()).Patch(ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
+			if err != nil {
+				m.logger.Error(err, "failed to update daemonset", "daemonset", resource.Name)
+			}
+			return err
+		})
 	}
 }
 
+// patchWorkload applies the patch right away, or queues it on the restart scheduler if restarts are rate limited.
+func (m *Monitor) patchWorkload(obj client.Object, patch func(ctx context.Context) error) {
+	if m.scheduler == nil {
+		_ = patch(m.ctx)
+		return
+	}
+	m.scheduler.enqueue(workloadKey(obj), obj.GetNamespace(), patch)
+}
+
 func getAnnotationsPatch(annotations map[string]string) ([]byte, error) {
 	return json.Marshal([]interface{}{
 		map[string]interface{}{
@@ -448,13 +569,16 @@ func getTemplateSpecLabels(obj metav1.Object) labels.Set {
 	}
 }
 
-// MutateObject adds all enabled languages in config. Should only be run if selected by auto monitor or custom selector
+// MutateObject adds all enabled languages in config. Should only be run if selected by auto monitor, custom selector or label selector
 func (m *Monitor) MutateObject(oldObj client.Object, obj client.Object) any {
 	if !safeToMutate(oldObj, obj, m.config.RestartPods) {
 		return map[string]string{}
 	}
 
 	languagesToAnnotate := m.config.CustomSelector.LanguagesOf(obj, false)
+	for l := range m.selectors.LanguagesOf(obj) {
+		languagesToAnnotate[l] = nil
+	}
 	if m.isWorkloadAutoMonitored(obj) {
 		for l := range m.config.Languages {
 			languagesToAnnotate[l] = nil
@@ -464,6 +588,19 @@ func (m *Monitor) MutateObject(oldObj client.Object, obj client.Object) any {
 	for l := range m.config.Exclude.LanguagesOf(obj, true) {
 		delete(languagesToAnnotate, l)
 	}
+	for l := range languagesToAnnotate {
+		if !m.config.isLanguageEnabled(l) {
+			delete(languagesToAnnotate, l)
+		}
+	}
+	// only annotate the languages that can be injected into Windows pods
+	if podTemplate := getPodTemplate(obj); podTemplate != nil && instrumentation.IsWindowsPodSpec(podTemplate.Spec) {
+		for l := range languagesToAnnotate {
+			if _, ok := instrumentation.WindowsSupportedTypes[l]; !ok {
+				delete(languagesToAnnotate, l)
+			}
+		}
+	}
 
 	m.logger.V(2).Info("languages to annotate", "objName", obj.GetName(), "languages", languagesToAnnotate)
 	return mutate(obj, languagesToAnnotate)
@@ -482,6 +619,10 @@ func (m *Monitor) isWorkloadAutoMonitored(obj client.Object) bool {
 	if slices.Contains(excludedNamespaces, obj.GetNamespace()) {
 		return false
 	}
+	if m.excluder.excludes(obj, m.namespaceLabels(obj.GetNamespace())) {
+		m.logger.V(2).Info("workload matches auto monitor exclusions", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return false
+	}
 	// determine if the object is currently selected by a service
 	objectLabels := getTemplateSpecLabels(obj)
 	for _, informerObj := range m.serviceInformer.GetStore().List() {
@@ -499,6 +640,18 @@ func (m *Monitor) isWorkloadAutoMonitored(obj client.Object) bool {
 	return false
 }
 
+// namespaceLabels returns the labels of the namespace if the namespace informer is running.
+func (m *Monitor) namespaceLabels(name string) labels.Set {
+	if m.namespaceInformer == nil {
+		return nil
+	}
+	obj, exists, err := m.namespaceInformer.GetStore().GetByKey(name)
+	if err != nil || !exists {
+		return nil
+	}
+	return obj.(*corev1.Namespace).Labels
+}
+
 // mutate if object is a workload, mutate the pod template. otherwise, mutate the object's annotations itself. It will add annotations if needsInstrumentation is true. Otherwise, it will remove instrumentation annotations.
 func mutate(object client.Object, languagesToMonitor instrumentation.TypeSet) map[string]string {
 	var obj metav1.Object
@@ -13,4 +13,16 @@ type MonitorConfig struct {
 	RestartPods        bool                    `json:"restartPods"`
 	Exclude            AnnotationConfig        `json:"exclude,omitempty"`
 	CustomSelector     AnnotationConfig        `json:"customSelector,omitempty"`
+	Exclusions         ExclusionConfig         `json:"exclusions,omitempty"`
+	LabelSelector      LabelSelectorConfig     `json:"labelSelector,omitempty"`
+	Restart            RestartConfig           `json:"restart,omitempty"`
+	// LanguageToggles enables or disables each language cluster-wide. Disabled languages are never annotated,
+	// including by the custom and label selectors, and their existing auto-annotations are removed.
+	LanguageToggles map[instrumentation.Type]bool `json:"languageToggles,omitempty"`
+}
+
+// isLanguageEnabled returns false if the language has been disabled with the language toggles.
+func (c MonitorConfig) isLanguageEnabled(instType instrumentation.Type) bool {
+	enabled, ok := c.LanguageToggles[instType]
+	return !ok || enabled
 }
@@ -15,6 +15,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -289,6 +290,37 @@ func TestMonitor_MutateObject(t *testing.T) {
 			serviceSelector:             map[string]string{"app": "different-2"},
 			expectedWorkloadAnnotations: map[string]string{}, // empty because even though it should be custom selected, it is modified on the pod level for namespaces, so the pod template is not updated
 		},
+		{
+			name:                        "same namespace, same selector, monitorallservices true, language toggled off",
+			config:                      withLanguageToggles(simpleConfig(true, false, none, none), map[instrumentation.Type]bool{instrumentation.TypeJava: false}),
+			deploymentNs:                "namespace-1",
+			serviceNs:                   "namespace-1",
+			deploymentSelector:          map[string]string{"app": "same"},
+			serviceSelector:             map[string]string{"app": "same"},
+			expectedWorkloadAnnotations: map[string]string{},
+		},
+		{
+			name:                        "same namespace, same selector, monitorallservices true, other language toggled off",
+			config:                      withLanguageToggles(simpleConfig(true, false, none, none), map[instrumentation.Type]bool{instrumentation.TypeNodeJS: false}),
+			deploymentNs:                "namespace-1",
+			serviceNs:                   "namespace-1",
+			deploymentSelector:          map[string]string{"app": "same"},
+			serviceSelector:             map[string]string{"app": "same"},
+			expectedWorkloadAnnotations: annotated,
+		},
+		{
+			name: "different namespace, different selector, custom selected workload, language toggled off",
+			config: withLanguageToggles(simpleConfig(false, false, AnnotationConfig{Java: AnnotationResources{
+				Deployments:  []string{"namespace-1/workload"},
+				DaemonSets:   []string{"namespace-1/workload"},
+				StatefulSets: []string{"namespace-1/workload"},
+			}}, none), map[instrumentation.Type]bool{instrumentation.TypeJava: false}),
+			deploymentNs:                "namespace-1",
+			serviceNs:                   "namespace-2",
+			deploymentSelector:          map[string]string{"app": "different-1"},
+			serviceSelector:             map[string]string{"app": "different-2"},
+			expectedWorkloadAnnotations: map[string]string{},
+		},
 	}
 
 	workloadTypes := []struct {
@@ -1011,3 +1043,53 @@ func simpleConfig(monitorAll bool, restartPods bool, customSelector AnnotationCo
 		CustomSelector:     customSelector,
 	}
 }
+
+func withLanguageToggles(config MonitorConfig, toggles map[instrumentation.Type]bool) MonitorConfig {
+	config.LanguageToggles = toggles
+	return config
+}
+
+func TestMonitor_MutateObject_Windows(t *testing.T) {
+	config := simpleConfig(false, true, AnnotationConfig{
+		Java:   AnnotationResources{Deployments: []string{"default/workload"}},
+		Python: AnnotationResources{Deployments: []string{"default/workload"}},
+		DotNet: AnnotationResources{Deployments: []string{"default/workload"}},
+		NodeJS: AnnotationResources{Deployments: []string{"default/workload"}},
+	}, none)
+	c := fake2.NewFakeClient()
+	monitor := NewMonitor(context.TODO(), config, fake.NewSimpleClientset(), c, c, testr.New(t))
+
+	linux := newTestDeployment("workload", defaultNs, nil, nil)
+	assert.Equal(t, mergeMaps(
+		buildAnnotations(instrumentation.TypeJava),
+		buildAnnotations(instrumentation.TypePython),
+		buildAnnotations(instrumentation.TypeDotNet),
+		buildAnnotations(instrumentation.TypeNodeJS),
+	), monitor.MutateObject(nil, linux))
+
+	windows := newTestDeployment("workload", defaultNs, nil, nil)
+	windows.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	assert.Equal(t, mergeMaps(
+		buildAnnotations(instrumentation.TypeJava),
+		buildAnnotations(instrumentation.TypeDotNet),
+	), monitor.MutateObject(nil, windows))
+}
+
+func TestTrimPodTemplate(t *testing.T) {
+	deployment := newTestDeployment("workload", defaultNs, map[string]string{"app": "test"}, map[string]string{"key": "value"})
+	deployment.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app:latest"}}
+
+	trimmed := trimPodTemplate(deployment.Spec.Template)
+	assert.Equal(t, deployment.Spec.Template.Labels, trimmed.Labels)
+	assert.Equal(t, deployment.Spec.Template.Annotations, trimmed.Annotations)
+	assert.Empty(t, trimmed.Spec.Containers)
+	assert.True(t, instrumentation.IsWindowsPodSpec(trimmed.Spec))
+}
+
+func TestExcludedNamespacesFieldSelector(t *testing.T) {
+	selector := excludedNamespacesFieldSelector()
+	assert.False(t, selector.Matches(fields.Set{"metadata.namespace": "kube-system"}))
+	assert.False(t, selector.Matches(fields.Set{"metadata.namespace": "amazon-cloudwatch"}))
+	assert.True(t, selector.Matches(fields.Set{"metadata.namespace": defaultNs}))
+}
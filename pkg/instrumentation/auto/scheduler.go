@@ -0,0 +1,372 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	defaultBatchInterval      = time.Minute
+	maintenanceWindowPollTime = time.Minute
+	maintenanceWindowFormat   = "15:04"
+)
+
+var restartsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "auto_monitor_workload_restarts",
+	Help: "Number of workload restarts scheduled by the auto monitor, by state.",
+}, []string{"state"})
+
+func init() {
+	metrics.Registry.MustRegister(restartsGauge)
+}
+
+// RestartConfig rate limits the workload restarts done by the auto monitor, e.g. when monitorAllServices is enabled
+// on a cluster with hundreds of workloads. When any limit is set, restarts are queued and applied in batches.
+type RestartConfig struct {
+	// BatchSize is the maximum number of workloads restarted per batch across the cluster. 0 means no limit.
+	BatchSize int `json:"batchSize,omitempty"`
+	// NamespaceBatchSize is the maximum number of workloads restarted per batch in a single namespace. 0 means no
+	// limit.
+	NamespaceBatchSize int `json:"namespaceBatchSize,omitempty"`
+	// BatchInterval is the time to wait between two batches. Defaults to 1m.
+	BatchInterval metav1.Duration `json:"batchInterval,omitempty"`
+	// Parallelism is the maximum number of workloads restarted concurrently within a batch. Defaults to 1.
+	Parallelism int `json:"parallelism,omitempty"`
+	// MaintenanceWindows restricts restarts to the given windows. Restarts are allowed at any time if empty.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// StatusConfigMap is the namespace/name of a ConfigMap the restart progress, and the workloads whose last restart
+	// failed, are written to after each batch.
+	StatusConfigMap string `json:"statusConfigMap,omitempty"`
+}
+
+// MaintenanceWindow is a daily time window in UTC. End may be before Start for windows spanning midnight.
+type MaintenanceWindow struct {
+	// Start is the start of the window in HH:MM format.
+	Start string `json:"start"`
+	// End is the end of the window in HH:MM format.
+	End string `json:"end"`
+}
+
+func (c RestartConfig) enabled() bool {
+	return c.BatchSize > 0 || c.NamespaceBatchSize > 0 || c.Parallelism > 0 || len(c.MaintenanceWindows) > 0
+}
+
+// RestartProgress reports the state of the restarts handled by the scheduler.
+type RestartProgress struct {
+	Pending   int
+	Completed int
+	Failed    int
+}
+
+type restartTask struct {
+	key       string
+	namespace string
+	fn        func(ctx context.Context) error
+}
+
+// window is a parsed MaintenanceWindow in minutes since midnight.
+type window struct {
+	start, end int
+}
+
+func (w window) contains(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// restartScheduler queues workload restarts and applies them in batches, respecting the cluster-wide and namespace
+// batch sizes and the maintenance windows.
+type restartScheduler struct {
+	cfg     RestartConfig
+	windows []window
+	logger  logr.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	pending  []*restartTask
+	tasks    map[string]*restartTask
+	progress RestartProgress
+	wake     chan struct{}
+	// failures is the error of the last restart of the workloads whose last restart failed, by workload key
+	failures map[string]string
+
+	// status writes the progress to the statusKey ConfigMap, written is the last progress written
+	status    client.Writer
+	statusKey types.NamespacedName
+	written   *RestartProgress
+}
+
+func newRestartScheduler(cfg RestartConfig, logger logr.Logger) (*restartScheduler, error) {
+	if cfg.BatchSize < 0 || cfg.NamespaceBatchSize < 0 {
+		return nil, fmt.Errorf("restart batch sizes must not be negative")
+	}
+	if cfg.Parallelism < 0 {
+		return nil, fmt.Errorf("restart parallelism must not be negative")
+	}
+	if cfg.Parallelism == 0 {
+		cfg.Parallelism = 1
+	}
+	if cfg.BatchInterval.Duration <= 0 {
+		cfg.BatchInterval.Duration = defaultBatchInterval
+	}
+	var windows []window
+	for _, w := range cfg.MaintenanceWindows {
+		start, err := time.Parse(maintenanceWindowFormat, w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window start %q: %w", w.Start, err)
+		}
+		end, err := time.Parse(maintenanceWindowFormat, w.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window end %q: %w", w.End, err)
+		}
+		windows = append(windows, window{
+			start: start.Hour()*60 + start.Minute(),
+			end:   end.Hour()*60 + end.Minute(),
+		})
+	}
+	var statusKey types.NamespacedName
+	if cfg.StatusConfigMap != "" {
+		namespace, name, ok := strings.Cut(cfg.StatusConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid restart status ConfigMap %q, expected namespace/name", cfg.StatusConfigMap)
+		}
+		statusKey = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return &restartScheduler{
+		cfg:       cfg,
+		windows:   windows,
+		logger:    logger,
+		now:       time.Now,
+		tasks:     map[string]*restartTask{},
+		wake:      make(chan struct{}, 1),
+		failures:  map[string]string{},
+		statusKey: statusKey,
+	}, nil
+}
+
+// enqueue schedules fn to restart the workload identified by key. If the workload is already pending, the pending
+// restart is replaced so that only the latest change is applied.
+func (s *restartScheduler) enqueue(key, namespace string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task, ok := s.tasks[key]; ok {
+		task.fn = fn
+		return
+	}
+	task := &restartTask{key: key, namespace: namespace, fn: fn}
+	s.tasks[key] = task
+	s.pending = append(s.pending, task)
+	s.progress.Pending = len(s.pending)
+	s.reportLocked()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextBatch removes and returns the next batch of tasks in queue order.
+func (s *restartScheduler) nextBatch() []*restartTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var batch, remaining []*restartTask
+	perNamespace := map[string]int{}
+	for _, task := range s.pending {
+		if (s.cfg.BatchSize > 0 && len(batch) >= s.cfg.BatchSize) ||
+			(s.cfg.NamespaceBatchSize > 0 && perNamespace[task.namespace] >= s.cfg.NamespaceBatchSize) {
+			remaining = append(remaining, task)
+			continue
+		}
+		perNamespace[task.namespace]++
+		batch = append(batch, task)
+		delete(s.tasks, task.key)
+	}
+	s.pending = remaining
+	s.progress.Pending = len(s.pending)
+	return batch
+}
+
+func (s *restartScheduler) inMaintenanceWindow() bool {
+	if len(s.windows) == 0 {
+		return true
+	}
+	now := s.now()
+	for _, w := range s.windows {
+		if w.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// runBatch applies the next batch of restarts and returns the number of restarts still pending.
+func (s *restartScheduler) runBatch(ctx context.Context) int {
+	batch := s.nextBatch()
+	// the restarts of the batch are applied by at most Parallelism workers
+	tasks := make(chan *restartTask)
+	var wg sync.WaitGroup
+	for i := 0; i < min(s.cfg.Parallelism, len(batch)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				err := task.fn(ctx)
+				s.mu.Lock()
+				if err != nil {
+					s.progress.Failed++
+					s.failures[task.key] = err.Error()
+					s.logger.Error(err, "failed to restart workload", "workload", task.key)
+				} else {
+					s.progress.Completed++
+					delete(s.failures, task.key)
+				}
+				s.mu.Unlock()
+			}
+		}()
+	}
+	for _, task := range batch {
+		tasks <- task
+	}
+	close(tasks)
+	wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(batch) > 0 {
+		s.logger.Info("Restarted batch of workloads", "batch", len(batch), "pending", s.progress.Pending,
+			"completed", s.progress.Completed, "failed", s.progress.Failed)
+	}
+	s.reportLocked()
+	return s.progress.Pending
+}
+
+// Run applies the queued restarts until the context is done.
+func (s *restartScheduler) Run(ctx context.Context) {
+	for {
+		var wait <-chan time.Time
+		switch {
+		case !s.inMaintenanceWindow():
+			wait = time.After(maintenanceWindowPollTime)
+		case s.runBatch(ctx) > 0:
+			wait = time.After(s.cfg.BatchInterval.Duration)
+		}
+		s.writeStatus(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+		case <-s.wake:
+			// a new restart was queued, but batches are still spaced by the batch interval
+			if wait != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-wait:
+				}
+			}
+		}
+	}
+}
+
+// Progress returns the current restart progress.
+func (s *restartScheduler) Progress() RestartProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.progress
+}
+
+// Failures returns the error of the last restart of the workloads whose last restart failed, by workload key.
+func (s *restartScheduler) Failures() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.failures)
+}
+
+// writeStatus writes the progress and the failed workloads to the status ConfigMap, if any, when the progress changed
+// since the last write. Every restart changes the progress, so the failed workloads can't change without it.
+func (s *restartScheduler) writeStatus(ctx context.Context) {
+	if s.status == nil || s.statusKey.Name == "" {
+		return
+	}
+	progress := s.Progress()
+	if s.written != nil && *s.written == progress {
+		return
+	}
+	// the workload keys are not valid ConfigMap keys, so the failed workloads are listed one per line
+	failures := s.Failures()
+	var failed []string
+	for _, key := range slices.Sorted(maps.Keys(failures)) {
+		failed = append(failed, fmt.Sprintf("%s: %s", key, failures[key]))
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: s.statusKey.Name, Namespace: s.statusKey.Namespace},
+		Data: map[string]string{
+			"pending":   strconv.Itoa(progress.Pending),
+			"completed": strconv.Itoa(progress.Completed),
+			"failed":    strconv.Itoa(progress.Failed),
+			"failures":  strings.Join(failed, "\n"),
+			"updated":   s.now().UTC().Format(time.RFC3339),
+		},
+	}
+	err := s.status.Update(ctx, cm)
+	if apierrors.IsNotFound(err) {
+		err = s.status.Create(ctx, cm)
+	}
+	if err != nil {
+		s.logger.Error(err, "failed to write the restart progress", "configMap", s.statusKey)
+		return
+	}
+	s.written = &progress
+}
+
+func (s *restartScheduler) reportLocked() {
+	restartsGauge.WithLabelValues("pending").Set(float64(s.progress.Pending))
+	restartsGauge.WithLabelValues("completed").Set(float64(s.progress.Completed))
+	restartsGauge.WithLabelValues("failed").Set(float64(s.progress.Failed))
+}
+
+// scheduledWriter queues workload patches on the restart scheduler instead of sending them immediately. All other
+// requests are passed through. A queued patch returns nil once queued, and its outcome is only reported by the
+// progress of the scheduler and the failed workloads of the status ConfigMap.
+type scheduledWriter struct {
+	client.Writer
+	scheduler *restartScheduler
+}
+
+var _ client.Writer = (*scheduledWriter)(nil)
+
+func (w *scheduledWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if isNamespace(obj) || !isMutableType(obj) {
+		return w.Writer.Patch(ctx, obj, patch, opts...)
+	}
+	// the caller may reuse the object before the patch is applied
+	obj = obj.DeepCopyObject().(client.Object)
+	w.scheduler.enqueue(workloadKey(obj), obj.GetNamespace(), func(ctx context.Context) error {
+		return w.Writer.Patch(ctx, obj, patch, opts...)
+	})
+	return nil
+}
+
+func workloadKey(obj client.Object) string {
+	return fmt.Sprintf("%s/%s", workloadKind(obj), namespacedName(obj))
+}
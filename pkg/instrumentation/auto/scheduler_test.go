@@ -0,0 +1,205 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewRestartScheduler(t *testing.T) {
+	s, err := newRestartScheduler(RestartConfig{BatchSize: 10}, logr.Discard())
+	require.NoError(t, err)
+	assert.Equal(t, defaultBatchInterval, s.cfg.BatchInterval.Duration)
+
+	_, err = newRestartScheduler(RestartConfig{BatchSize: -1}, logr.Discard())
+	assert.Error(t, err)
+
+	_, err = newRestartScheduler(RestartConfig{MaintenanceWindows: []MaintenanceWindow{{Start: "25:00", End: "04:00"}}}, logr.Discard())
+	assert.Error(t, err)
+
+	_, err = newRestartScheduler(RestartConfig{MaintenanceWindows: []MaintenanceWindow{{Start: "22:00", End: "4am"}}}, logr.Discard())
+	assert.Error(t, err)
+
+	_, err = newRestartScheduler(RestartConfig{StatusConfigMap: "restart-status"}, logr.Discard())
+	assert.Error(t, err)
+}
+
+func TestRestartScheduler_nextBatch(t *testing.T) {
+	s, err := newRestartScheduler(RestartConfig{BatchSize: 3, NamespaceBatchSize: 2}, logr.Discard())
+	require.NoError(t, err)
+
+	noop := func(context.Context) error { return nil }
+	for _, key := range []string{"a/1", "a/2", "a/3", "b/1", "c/1", "c/2"} {
+		s.enqueue(key, key[:1], noop)
+	}
+	// enqueuing a pending workload again does not add it twice
+	s.enqueue("a/1", "a", noop)
+
+	keys := func(tasks []*restartTask) []string {
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.key)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"a/1", "a/2", "b/1"}, keys(s.nextBatch()))
+	assert.Equal(t, []string{"a/3", "c/1", "c/2"}, keys(s.nextBatch()))
+	assert.Empty(t, s.nextBatch())
+}
+
+func TestRestartScheduler_runBatch(t *testing.T) {
+	s, err := newRestartScheduler(RestartConfig{BatchSize: 2}, logr.Discard())
+	require.NoError(t, err)
+
+	var restarted []string
+	restart := func(key string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			restarted = append(restarted, key)
+			return err
+		}
+	}
+	s.enqueue("ns/a", "ns", restart("ns/a", nil))
+	s.enqueue("ns/b", "ns", restart("ns/b", errors.New("conflict")))
+	s.enqueue("ns/c", "ns", restart("ns/c", nil))
+	assert.Equal(t, RestartProgress{Pending: 3}, s.Progress())
+
+	assert.Equal(t, 1, s.runBatch(context.TODO()))
+	assert.Equal(t, RestartProgress{Pending: 1, Completed: 1, Failed: 1}, s.Progress())
+	assert.Equal(t, 0, s.runBatch(context.TODO()))
+	assert.Equal(t, RestartProgress{Completed: 2, Failed: 1}, s.Progress())
+	assert.Equal(t, []string{"ns/a", "ns/b", "ns/c"}, restarted)
+}
+
+func TestRestartScheduler_runBatchParallelism(t *testing.T) {
+	_, err := newRestartScheduler(RestartConfig{Parallelism: -1}, logr.Discard())
+	assert.Error(t, err)
+
+	s, err := newRestartScheduler(RestartConfig{Parallelism: 2}, logr.Discard())
+	require.NoError(t, err)
+	var running, maxRunning atomic.Int32
+	restart := func(context.Context) error {
+		current := running.Add(1)
+		for {
+			seen := maxRunning.Load()
+			if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	for _, key := range []string{"ns/a", "ns/b", "ns/c", "ns/d", "ns/e"} {
+		s.enqueue(key, "ns", restart)
+	}
+
+	assert.Equal(t, 0, s.runBatch(context.TODO()))
+	assert.Equal(t, RestartProgress{Completed: 5}, s.Progress())
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestRestartScheduler_inMaintenanceWindow(t *testing.T) {
+	s, err := newRestartScheduler(RestartConfig{MaintenanceWindows: []MaintenanceWindow{
+		{Start: "22:00", End: "04:00"},
+		{Start: "12:00", End: "12:30"},
+	}}, logr.Discard())
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		now      string
+		expected bool
+	}{
+		{now: "23:15", expected: true},
+		{now: "03:59", expected: true},
+		{now: "04:00", expected: false},
+		{now: "12:10", expected: true},
+		{now: "12:30", expected: false},
+		{now: "18:00", expected: false},
+	} {
+		now, err := time.Parse(maintenanceWindowFormat, tt.now)
+		require.NoError(t, err)
+		s.now = func() time.Time { return now }
+		assert.Equal(t, tt.expected, s.inMaintenanceWindow(), tt.now)
+	}
+}
+
+func TestRestartScheduler_writeStatus(t *testing.T) {
+	s, err := newRestartScheduler(RestartConfig{BatchSize: 1, StatusConfigMap: "amazon-cloudwatch/restart-status"}, logr.Discard())
+	require.NoError(t, err)
+	c := fake2.NewFakeClient()
+	s.status = c
+	s.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	noop := func(context.Context) error { return nil }
+	s.enqueue("ns/a", "ns", noop)
+	s.enqueue("ns/b", "ns", noop)
+	s.writeStatus(context.TODO())
+
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: "amazon-cloudwatch", Name: "restart-status"}
+	require.NoError(t, c.Get(context.TODO(), key, cm))
+	assert.Equal(t, map[string]string{"pending": "2", "completed": "0", "failed": "0", "failures": "", "updated": "2025-01-01T00:00:00Z"}, cm.Data)
+
+	s.runBatch(context.TODO())
+	s.writeStatus(context.TODO())
+	require.NoError(t, c.Get(context.TODO(), key, cm))
+	assert.Equal(t, "1", cm.Data["pending"])
+	assert.Equal(t, "1", cm.Data["completed"])
+
+	// the failed workloads are listed until their next restart succeeds
+	s.enqueue("ns/c", "ns", func(context.Context) error { return errors.New("forbidden") })
+	s.runBatch(context.TODO())
+	s.runBatch(context.TODO())
+	s.writeStatus(context.TODO())
+	require.NoError(t, c.Get(context.TODO(), key, cm))
+	assert.Equal(t, "1", cm.Data["failed"])
+	assert.Equal(t, "ns/c: forbidden", cm.Data["failures"])
+
+	s.enqueue("ns/c", "ns", noop)
+	s.runBatch(context.TODO())
+	s.writeStatus(context.TODO())
+	require.NoError(t, c.Get(context.TODO(), key, cm))
+	assert.Empty(t, cm.Data["failures"])
+}
+
+func TestScheduledWriter(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: defaultNs}}
+	deployment := newTestDeployment("app", defaultNs, nil, nil)
+	c := fake2.NewFakeClient(namespace, deployment)
+	s, err := newRestartScheduler(RestartConfig{BatchSize: 1}, logr.Discard())
+	require.NoError(t, err)
+	w := &scheduledWriter{Writer: c, scheduler: s}
+
+	patch := client.MergeFrom(namespace.DeepCopy())
+	namespace.SetAnnotations(map[string]string{"foo": "bar"})
+	require.NoError(t, w.Patch(context.TODO(), namespace, patch))
+	assert.Equal(t, RestartProgress{}, s.Progress())
+
+	patch = client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Template.SetAnnotations(map[string]string{"foo": "bar"})
+	require.NoError(t, w.Patch(context.TODO(), deployment, patch))
+	assert.Equal(t, RestartProgress{Pending: 1}, s.Progress())
+	// the queued patch is not changed by the caller reusing the object
+	deployment.Spec.Template.SetAnnotations(map[string]string{"foo": "baz"})
+
+	updated := newTestDeployment("", "", nil, nil)
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(deployment), updated))
+	assert.Empty(t, updated.Spec.Template.Annotations)
+
+	s.runBatch(context.TODO())
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(deployment), updated))
+	assert.Equal(t, map[string]string{"foo": "bar"}, updated.Spec.Template.Annotations)
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+// LabelSelectorConfig selects the workloads to annotate for each instrumentation type by label. A workload is
+// selected if either its own labels or its pod template labels match.
+type LabelSelectorConfig struct {
+	Java   *metav1.LabelSelector `json:"java,omitempty"`
+	Python *metav1.LabelSelector `json:"python,omitempty"`
+	DotNet *metav1.LabelSelector `json:"dotnet,omitempty"`
+	NodeJS *metav1.LabelSelector `json:"nodejs,omitempty"`
+}
+
+func (c LabelSelectorConfig) getSelector(instType instrumentation.Type) *metav1.LabelSelector {
+	switch instType {
+	case instrumentation.TypeJava:
+		return c.Java
+	case instrumentation.TypePython:
+		return c.Python
+	case instrumentation.TypeDotNet:
+		return c.DotNet
+	case instrumentation.TypeNodeJS:
+		return c.NodeJS
+	default:
+		return nil
+	}
+}
+
+// languageSelectors is the compiled form of LabelSelectorConfig.
+type languageSelectors map[instrumentation.Type]labels.Selector
+
+func newLanguageSelectors(cfg LabelSelectorConfig) (languageSelectors, error) {
+	selectors := languageSelectors{}
+	for t := range instrumentation.SupportedTypes {
+		labelSelector := cfg.getSelector(t)
+		if labelSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s label selector: %w", t, err)
+		}
+		// an empty selector matches everything, which is what monitorAllServices is for
+		if selector.Empty() {
+			return nil, fmt.Errorf("invalid %s label selector: selector must not be empty", t)
+		}
+		selectors[t] = selector
+	}
+	return selectors, nil
+}
+
+// LanguagesOf returns the languages whose selector matches the workload. Namespaces are never selected by label.
+func (s languageSelectors) LanguagesOf(obj client.Object) instrumentation.TypeSet {
+	typesSelected := instrumentation.TypeSet{}
+	if isNamespace(obj) {
+		return typesSelected
+	}
+	objectLabels := labels.Set(obj.GetLabels())
+	templateLabels := getTemplateSpecLabels(obj)
+	for t, selector := range s {
+		if selector.Matches(objectLabels) || selector.Matches(templateLabels) {
+			typesSelected[t] = nil
+		}
+	}
+	return typesSelected
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func TestNewLanguageSelectors(t *testing.T) {
+	selectors, err := newLanguageSelectors(LabelSelectorConfig{})
+	assert.NoError(t, err)
+	assert.Empty(t, selectors)
+
+	_, err = newLanguageSelectors(LabelSelectorConfig{Java: &metav1.LabelSelector{}})
+	assert.Error(t, err)
+
+	_, err = newLanguageSelectors(LabelSelectorConfig{Python: &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "observability", Operator: "Bogus"}},
+	}})
+	assert.Error(t, err)
+}
+
+func TestLanguageSelectors_LanguagesOf(t *testing.T) {
+	selectors, err := newLanguageSelectors(LabelSelectorConfig{
+		Java:   &metav1.LabelSelector{MatchLabels: map[string]string{"observability": "appsignals"}},
+		Python: &metav1.LabelSelector{MatchLabels: map[string]string{"observability": "appsignals-python"}},
+	})
+	require.NoError(t, err)
+
+	for _, wt := range workloadTypes {
+		t.Run(wt.name, func(t *testing.T) {
+			obj := wt.create("app", defaultNs, map[string]string{"observability": "appsignals"}, nil)
+			assert.Equal(t, instrumentation.NewTypeSet(instrumentation.TypeJava), selectors.LanguagesOf(obj))
+
+			obj = wt.create("app", defaultNs, nil, nil)
+			obj.SetLabels(map[string]string{"observability": "appsignals-python"})
+			assert.Equal(t, instrumentation.NewTypeSet(instrumentation.TypePython), selectors.LanguagesOf(obj))
+
+			obj = wt.create("app", defaultNs, map[string]string{"app": "test"}, nil)
+			assert.Empty(t, selectors.LanguagesOf(obj))
+		})
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   defaultNs,
+		Labels: map[string]string{"observability": "appsignals"},
+	}}
+	assert.Empty(t, selectors.LanguagesOf(namespace))
+}
+
+func TestMonitor_MutateObject_LabelSelector(t *testing.T) {
+	config := simpleConfig(false, true, none, none)
+	config.LabelSelector = LabelSelectorConfig{
+		DotNet: &metav1.LabelSelector{MatchLabels: map[string]string{"observability": "appsignals"}},
+	}
+	c := fake2.NewFakeClient()
+	monitor := NewMonitor(context.TODO(), config, fake.NewSimpleClientset(), c, c, testr.New(t))
+	require.NotNil(t, monitor)
+
+	for _, wt := range workloadTypes {
+		t.Run(wt.name, func(t *testing.T) {
+			selected := wt.create("app", defaultNs, map[string]string{"observability": "appsignals"}, nil)
+			assert.Equal(t, buildAnnotations(instrumentation.TypeDotNet), monitor.MutateObject(nil, selected))
+
+			notSelected := wt.create("app", defaultNs, map[string]string{"app": "test"}, nil)
+			assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, notSelected))
+		})
+	}
+
+	t.Run("excluded language", func(t *testing.T) {
+		config.Exclude = AnnotationConfig{DotNet: AnnotationResources{Namespaces: []string{defaultNs}}}
+		monitor := NewMonitor(context.TODO(), config, fake.NewSimpleClientset(), c, c, testr.New(t))
+		require.NotNil(t, monitor)
+		selected := newTestDeployment("app", defaultNs, map[string]string{"observability": "appsignals"}, nil)
+		assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, selected))
+	})
+}
@@ -79,12 +79,8 @@ func configureAutoMonitor(ctx context.Context, autoMonitorConfigStr string, clie
 }
 
 // CreateInstrumentationAnnotator creates an instrumentationAnnotator based on config and environment. Returns the InstrumentationAnnotator and whether AutoMonitor is enabled.
-func CreateInstrumentationAnnotator(autoMonitorConfigStr string, autoAnnotationConfigStr string, ctx context.Context, client client.Client, reader client.Reader, setupLog logr.Logger) InstrumentationAnnotator {
-	k8sConfig, err := rest.InClusterConfig()
-	if err != nil {
-		setupLog.Error(err, "unable to create in-cluster config")
-	}
-
+// The informers of the auto monitor use the k8sConfig, such as the config of the manager with its client rate limits.
+func CreateInstrumentationAnnotator(autoMonitorConfigStr string, autoAnnotationConfigStr string, ctx context.Context, k8sConfig *rest.Config, client client.Client, reader client.Reader, setupLog logr.Logger) InstrumentationAnnotator {
 	clientSet, err := kubernetes.NewForConfig(k8sConfig)
 	if err != nil {
 		setupLog.Error(err, "unable to create clientset")
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+const (
+	envJavaDisabledResourceProviders = "OTEL_JAVA_DISABLED_RESOURCE_PROVIDERS"
+	envPythonResourceDetectors       = "OTEL_EXPERIMENTAL_RESOURCE_DETECTORS"
+	envNodeJSResourceDetectors       = "OTEL_NODE_RESOURCE_DETECTORS"
+)
+
+// awsJavaResourceProviders are the resource providers of the Java agent which read the instance metadata or the
+// metadata endpoints of the AWS compute services.
+var awsJavaResourceProviders = []string{
+	"io.opentelemetry.contrib.aws.resource.BeanstalkResourceProvider",
+	"io.opentelemetry.contrib.aws.resource.Ec2ResourceProvider",
+	"io.opentelemetry.contrib.aws.resource.EcsResourceProvider",
+	"io.opentelemetry.contrib.aws.resource.EksResourceProvider",
+	"io.opentelemetry.contrib.aws.resource.LambdaResourceProvider",
+}
+
+// The resource detectors of the Python and Node.js SDKs outside of AWS, without the AWS ones.
+const (
+	pythonResourceDetectors = "otel,process"
+	nodeJSResourceDetectors = "env,host,os,process"
+)
+
+// injectResourceDetectionConfig disables the AWS resource detectors of the SDK of the container at index when the
+// cluster doesn't run on AWS, where they fail to reach the instance metadata and log errors, or when the AWS resource
+// attributes are injected instead. Env vars set by the user are never overridden.
+func (i *sdkInjector) injectResourceDetectionConfig(pod corev1.Pod, index int, allEnvs []corev1.EnvVar, instType Type) corev1.Pod {
+	if i.config.RunsOnAWS() && !i.config.InjectsAWSResource() {
+		return pod
+	}
+	container := &pod.Spec.Containers[index]
+	switch instType {
+	case TypeJava:
+		setEnvIfNotUserDefined(container, allEnvs, envJavaDisabledResourceProviders, strings.Join(awsJavaResourceProviders, ","))
+	case TypePython:
+		setEnvIfNotUserDefined(container, allEnvs, envPythonResourceDetectors, pythonResourceDetectors)
+	case TypeNodeJS:
+		setEnvIfNotUserDefined(container, allEnvs, envNodeJSResourceDetectors, nodeJSResourceDetectors)
+	}
+	return pod
+}
+
+// clusterResourceAttributes returns the resource attributes the SDKs can't detect outside of AWS: the name of the
+// cluster set by hand and the cloud provider.
+func (i *sdkInjector) clusterResourceAttributes() map[string]string {
+	attributes := map[string]string{}
+	if name := i.config.ClusterName(); name != "" {
+		attributes[string(semconv.K8SClusterNameKey)] = name
+	}
+	switch i.config.CloudProvider() {
+	case config.CloudProviderGCP:
+		attributes[string(semconv.CloudProviderKey)] = semconv.CloudProviderGCP.Value.AsString()
+	case config.CloudProviderAzure:
+		attributes[string(semconv.CloudProviderKey)] = semconv.CloudProviderAzure.Value.AsString()
+	}
+	return attributes
+}
+
+// awsResourceAttributes returns the AWS resource attributes of the pod the resource detectors would read from the
+// instance metadata, when the operator injects them. The availability zone is only known for the pods pinned to one.
+func (i *sdkInjector) awsResourceAttributes(pod corev1.Pod) map[string]string {
+	attributes := map[string]string{}
+	if !i.config.InjectsAWSResource() {
+		return attributes
+	}
+	attributes[string(semconv.CloudProviderKey)] = semconv.CloudProviderAWS.Value.AsString()
+	attributes[string(semconv.CloudPlatformKey)] = semconv.CloudPlatformAWSEKS.Value.AsString()
+	attributes[string(semconv.CloudRegionKey)] = i.config.AWSRegion()
+	if accountID := i.config.AWSAccountID(); accountID != "" {
+		attributes[string(semconv.CloudAccountIDKey)] = accountID
+	}
+	if zone := pod.Spec.NodeSelector[corev1.LabelTopologyZone]; zone != "" {
+		attributes[string(semconv.CloudAvailabilityZoneKey)] = zone
+	}
+	return attributes
+}
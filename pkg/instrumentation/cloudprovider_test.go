@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestInjectResourceDetectionConfig(t *testing.T) {
+	pod := func(env ...corev1.EnvVar) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Env: env}}}}
+	}
+	userDetectors := corev1.EnvVar{Name: "OTEL_NODE_RESOURCE_DETECTORS", Value: "env"}
+
+	for _, tt := range []struct {
+		name     string
+		provider string
+		region   string
+		instType Type
+		pod      corev1.Pod
+		expected []corev1.EnvVar
+	}{
+		{name: "aws", provider: config.CloudProviderAWS, instType: TypeJava, pod: pod()},
+		{
+			name:     "java",
+			provider: config.CloudProviderOnPremises,
+			instType: TypeJava,
+			pod:      pod(),
+			expected: []corev1.EnvVar{{
+				Name: "OTEL_JAVA_DISABLED_RESOURCE_PROVIDERS",
+				Value: "io.opentelemetry.contrib.aws.resource.BeanstalkResourceProvider,io.opentelemetry.contrib.aws.resource.Ec2ResourceProvider," +
+					"io.opentelemetry.contrib.aws.resource.EcsResourceProvider,io.opentelemetry.contrib.aws.resource.EksResourceProvider," +
+					"io.opentelemetry.contrib.aws.resource.LambdaResourceProvider",
+			}},
+		},
+		{
+			name:     "python",
+			provider: config.CloudProviderGCP,
+			instType: TypePython,
+			pod:      pod(),
+			expected: []corev1.EnvVar{{Name: "OTEL_EXPERIMENTAL_RESOURCE_DETECTORS", Value: "otel,process"}},
+		},
+		{
+			name:     "nodejs",
+			provider: config.CloudProviderAzure,
+			instType: TypeNodeJS,
+			pod:      pod(),
+			expected: []corev1.EnvVar{{Name: "OTEL_NODE_RESOURCE_DETECTORS", Value: "env,host,os,process"}},
+		},
+		{
+			name:     "user defined",
+			provider: config.CloudProviderAzure,
+			instType: TypeNodeJS,
+			pod:      pod(userDetectors),
+			expected: []corev1.EnvVar{userDetectors},
+		},
+		{
+			name:     "aws resource injected",
+			provider: config.CloudProviderAWS,
+			region:   "us-west-2",
+			instType: TypePython,
+			pod:      pod(),
+			expected: []corev1.EnvVar{{Name: "OTEL_EXPERIMENTAL_RESOURCE_DETECTORS", Value: "otel,process"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := sdkInjector{config: config.New(config.WithCloudProvider(tt.provider), config.WithAWSResource(tt.region, ""))}
+			actual := injector.injectResourceDetectionConfig(tt.pod, 0, tt.pod.Spec.Containers[0].Env, tt.instType)
+			assert.Equal(t, tt.expected, actual.Spec.Containers[0].Env)
+		})
+	}
+}
+
+func TestClusterResourceAttributes(t *testing.T) {
+	injector := sdkInjector{config: config.New()}
+	assert.Empty(t, injector.clusterResourceAttributes())
+
+	injector = sdkInjector{config: config.New(config.WithCloudProvider(config.CloudProviderGCP), config.WithClusterName("gke-cluster"))}
+	assert.Equal(t, map[string]string{"k8s.cluster.name": "gke-cluster", "cloud.provider": "gcp"}, injector.clusterResourceAttributes())
+
+	injector = sdkInjector{config: config.New(config.WithCloudProvider(config.CloudProviderOnPremises), config.WithClusterName("lab"))}
+	assert.Equal(t, map[string]string{"k8s.cluster.name": "lab"}, injector.clusterResourceAttributes())
+}
+
+func TestAWSResourceAttributes(t *testing.T) {
+	pod := corev1.Pod{}
+	injector := sdkInjector{config: config.New()}
+	assert.Empty(t, injector.awsResourceAttributes(pod))
+
+	injector = sdkInjector{config: config.New(config.WithAWSResource("us-west-2", "123456789012"))}
+	assert.Equal(t, map[string]string{
+		"cloud.provider":   "aws",
+		"cloud.platform":   "aws_eks",
+		"cloud.region":     "us-west-2",
+		"cloud.account.id": "123456789012",
+	}, injector.awsResourceAttributes(pod))
+
+	pod.Spec.NodeSelector = map[string]string{"topology.kubernetes.io/zone": "us-west-2a"}
+	injector = sdkInjector{config: config.New(config.WithAWSResource("us-west-2", ""))}
+	assert.Equal(t, map[string]string{
+		"cloud.provider":          "aws",
+		"cloud.platform":          "aws_eks",
+		"cloud.region":            "us-west-2",
+		"cloud.availability_zone": "us-west-2a",
+	}, injector.awsResourceAttributes(pod))
+}
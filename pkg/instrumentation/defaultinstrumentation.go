@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
 )
@@ -137,6 +138,27 @@ func getDefaultInstrumentation(agentConfig *adapters.CwaConfig, additionalEnvs m
 	}, nil
 }
 
+// withOperatorDefaults overrides the images and the init container resources of the default instrumentation with the
+// ones of the operator configuration, which may have changed since the operator started.
+func withOperatorDefaults(inst *v1alpha1.Instrumentation, cfg config.Config) {
+	overrideLanguageDefaults(&inst.Spec.Java.Image, &inst.Spec.Java.Resources, cfg.Defaults().AutoInstrumentation[config.LanguageJava])
+	overrideLanguageDefaults(&inst.Spec.Python.Image, &inst.Spec.Python.Resources, cfg.Defaults().AutoInstrumentation[config.LanguagePython])
+	overrideLanguageDefaults(&inst.Spec.DotNet.Image, &inst.Spec.DotNet.Resources, cfg.Defaults().AutoInstrumentation[config.LanguageDotNet])
+	overrideLanguageDefaults(&inst.Spec.NodeJS.Image, &inst.Spec.NodeJS.Resources, cfg.Defaults().AutoInstrumentation[config.LanguageNodeJS])
+}
+
+func overrideLanguageDefaults(image *string, resources *corev1.ResourceRequirements, defaults config.LanguageDefaults) {
+	if defaults.Image != "" {
+		*image = defaults.Image
+	}
+	if defaults.Resources.Limits != nil {
+		resources.Limits = defaults.Resources.Limits
+	}
+	if defaults.Resources.Requests != nil {
+		resources.Requests = defaults.Resources.Requests
+	}
+}
+
 func getJavaEnvs(isAppSignalsEnabled bool, cloudwatchAgentServiceEndpoint, exporterPrefix string, additionalEnvs map[string]string) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
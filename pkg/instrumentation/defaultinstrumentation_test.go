@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
 )
@@ -914,3 +915,41 @@ func Test_getDefaultInstrumentationLinuxWithApplicationSignalsDisabled(t *testin
 		})
 	}
 }
+
+func Test_withOperatorDefaults(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		Spec: v1alpha1.InstrumentationSpec{
+			Java: v1alpha1.Java{
+				Image: "java:v1",
+				Resources: corev1.ResourceRequirements{
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			},
+			Python: v1alpha1.Python{Image: "python:v1"},
+		},
+	}
+	cfg := config.New()
+	cfg.SetDefaults(config.Defaults{
+		AutoInstrumentation: map[string]config.LanguageDefaults{
+			config.LanguageJava: {
+				Image: "java:v2",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+				},
+			},
+		},
+	})
+
+	withOperatorDefaults(inst, cfg)
+
+	if inst.Spec.Java.Image != "java:v2" || inst.Spec.Python.Image != "python:v1" {
+		t.Errorf("withOperatorDefaults() images = %s, %s", inst.Spec.Java.Image, inst.Spec.Python.Image)
+	}
+	if !reflect.DeepEqual(inst.Spec.Java.Resources, corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}) {
+		t.Errorf("withOperatorDefaults() resources = %v", inst.Spec.Java.Resources)
+	}
+}
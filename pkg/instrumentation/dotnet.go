@@ -6,6 +6,7 @@ package instrumentation
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -117,14 +118,14 @@ func injectDotNetSDK(dotNetSpec v1alpha1.DotNet, pod corev1.Pod, index int, runt
 		setDotNetEnvVar(container, envDotNetSharedStore, dotNetSharedStorePath, concatEnvValues)
 	}
 
-	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+	setVolumeMount(container, corev1.VolumeMount{
 		Name:      dotnetVolumeName,
 		MountPath: dotnetInstrMountPath,
 	})
 
 	// We just inject Volumes and init containers for the first processed container.
 	if isInitContainerMissing(pod, dotnetInitContainerName) {
-		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		setVolume(&pod, corev1.Volume{
 			Name: dotnetVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
@@ -164,7 +165,7 @@ func setDotNetEnvVar(container *corev1.Container, envVarName string, envVarValue
 		})
 		return
 	}
-	if concatValues {
+	if concatValues && !strings.Contains(container.Env[idx].Value, envVarValue) {
 		container.Env[idx].Value = fmt.Sprintf("%s:%s", container.Env[idx].Value, envVarValue)
 	}
 }
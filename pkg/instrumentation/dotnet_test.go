@@ -539,7 +539,7 @@ func TestInjectDotNetSDK(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectDotNetSDK(test.DotNet, test.pod, 0, test.runtime)
+			pod, err := injectDotNetSDK(test.DotNet, test.pod, 0, test.runtime, test.pod.Spec.Containers[0].Env)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	// annotationDualExport enables dual export for a workload, see v1alpha1.Exporter. Possible values are "true" or
+	// "false".
+	annotationDualExport = "cloudwatch.aws.amazon.com/dual-export"
+
+	envAppSignalsEnabled = "OTEL_AWS_APPLICATION_SIGNALS_ENABLED"
+)
+
+// dualExportEnabled returns whether the SDK should be injected alongside a third-party OTLP endpoint configured on
+// the containers. Annotations take precedence over the Instrumentation.
+func dualExportEnabled(otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod) bool {
+	if value := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationDualExport); value != "" {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			return enabled
+		}
+	}
+	return otelinst.Spec.Exporter.DualExport
+}
+
+// dualExportEnvs returns the env vars the injection decisions are based on. With dual export, Application Signals is
+// treated as explicitly enabled, so the SDK is injected and the user's OTLP endpoints are kept for traces instead
+// of being replaced by the CloudWatch agent.
+func dualExportEnvs(otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod, allEnvs []corev1.EnvVar) []corev1.EnvVar {
+	if !dualExportEnabled(otelinst, ns, pod) || getEnvValue(allEnvs, envAppSignalsEnabled) != "" {
+		return allEnvs
+	}
+	envs := make([]corev1.EnvVar, len(allEnvs), len(allEnvs)+1)
+	copy(envs, allEnvs)
+	return append(envs, corev1.EnvVar{Name: envAppSignalsEnabled, Value: "true"})
+}
+
+// injectDualExportConfig enables Application Signals on the container at index when dual export is enabled, unless
+// the user has set it on the original container.
+func (i *sdkInjector) injectDualExportConfig(otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) corev1.Pod {
+	if !dualExportEnabled(otelinst, ns, pod) {
+		return pod
+	}
+	setEnvIfNotUserDefined(&pod.Spec.Containers[index], allEnvs, envAppSignalsEnabled, "true")
+	return pod
+}
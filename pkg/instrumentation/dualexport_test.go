@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestDualExportEnabled(t *testing.T) {
+	inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{Exporter: v1alpha1.Exporter{DualExport: true}}}
+	annotated := func(value string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationDualExport: value}}}
+	}
+
+	assert.False(t, dualExportEnabled(v1alpha1.Instrumentation{}, corev1.Namespace{}, corev1.Pod{}))
+	assert.True(t, dualExportEnabled(inst, corev1.Namespace{}, corev1.Pod{}))
+	assert.True(t, dualExportEnabled(v1alpha1.Instrumentation{}, corev1.Namespace{}, annotated("true")))
+	assert.False(t, dualExportEnabled(inst, corev1.Namespace{}, annotated("false")))
+}
+
+func TestInjectJavaagentDualExport(t *testing.T) {
+	inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{
+		Exporter: v1alpha1.Exporter{DualExport: true},
+		Java: v1alpha1.Java{
+			Image: "foo/bar:1",
+			Env: []corev1.EnvVar{
+				{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+				{Name: "OTEL_AWS_APPLICATION_SIGNALS_EXPORTER_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/metrics"},
+			},
+		},
+	}}
+	userEnvs := []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://collector.observability:4318"}}
+	newPod := func() corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Env: append([]corev1.EnvVar{}, userEnvs...)}}}}
+	}
+	injector := &sdkInjector{}
+
+	t.Run("skipped without dual export", func(t *testing.T) {
+		pod, err := injectJavaagent(inst.Spec.Java, newPod(), 0, userEnvs)
+		assert.NoError(t, err)
+		assert.Equal(t, userEnvs, pod.Spec.Containers[0].Env)
+	})
+	t.Run("injected with dual export", func(t *testing.T) {
+		pod := newPod()
+		pod, err := injectJavaagent(inst.Spec.Java, pod, 0, dualExportEnvs(inst, corev1.Namespace{}, pod, userEnvs))
+		assert.NoError(t, err)
+		pod = injector.injectDualExportConfig(inst, corev1.Namespace{}, pod, 0, userEnvs)
+		env := pod.Spec.Containers[0].Env
+		assert.Contains(t, env, corev1.EnvVar{Name: envAppSignalsEnabled, Value: "true"})
+		assert.Contains(t, env, corev1.EnvVar{Name: "OTEL_AWS_APPLICATION_SIGNALS_EXPORTER_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/metrics"})
+		// the user's endpoint is kept for traces
+		assert.Equal(t, "", getEnvValue(env, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"))
+		assert.Equal(t, "http://collector.observability:4318", getEnvValue(env, "OTEL_EXPORTER_OTLP_ENDPOINT"))
+	})
+}
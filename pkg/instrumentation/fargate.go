@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/sidecar"
+)
+
+// localAgentHost is the host of the agent sidecar, which shares the network namespace of the pod.
+const localAgentHost = "localhost"
+
+// usesLocalAgent returns whether the pod sends its telemetry to its agent sidecar: the Fargate pods have no agent on
+// their node to send it to.
+func usesLocalAgent(pod corev1.Pod) bool {
+	if !sidecar.IsFargatePod(pod) {
+		return false
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == naming.Container() {
+			return true
+		}
+	}
+	return false
+}
+
+// withLocalAgentEndpoints points the CloudWatch agent endpoints of the env vars of the application containers at the
+// agent sidecar, keeping their scheme, port and path.
+func withLocalAgentEndpoints(pod corev1.Pod) corev1.Pod {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name == naming.Container() {
+			continue
+		}
+		for j := range container.Env {
+			container.Env[j].Value = localAgentEndpoint(container.Env[j].Value)
+		}
+	}
+	return pod
+}
+
+func localAgentEndpoint(value string) string {
+	for _, host := range cloudwatchAgentNoProxyHosts {
+		value = strings.ReplaceAll(value, "://"+host+":", "://"+localAgentHost+":")
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWithLocalAgentEndpoints(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			SchedulerName: "fargate-scheduler",
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Env: []corev1.EnvVar{
+						{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+						{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "endpoint=http://cloudwatch-agent.amazon-cloudwatch.svc.cluster.local:2000"},
+						{Name: "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", Value: "https://metrics.example.com:4318/v1/metrics"},
+					},
+				},
+				{
+					Name: "otc-container",
+					Env:  []corev1.EnvVar{{Name: "ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316"}},
+				},
+			},
+		},
+	}
+	assert.True(t, usesLocalAgent(pod))
+
+	pod = withLocalAgentEndpoints(pod)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://localhost:4316/v1/traces"},
+		{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "endpoint=http://localhost:2000"},
+		{Name: "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", Value: "https://metrics.example.com:4318/v1/metrics"},
+	}, pod.Spec.Containers[0].Env)
+	assert.Equal(t, "http://cloudwatch-agent.amazon-cloudwatch:4316", pod.Spec.Containers[1].Env[0].Value)
+}
+
+func TestUsesLocalAgent(t *testing.T) {
+	app := corev1.Container{Name: "app"}
+	agent := corev1.Container{Name: "otc-container"}
+
+	assert.False(t, usesLocalAgent(corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "fargate-scheduler", Containers: []corev1.Container{app}}}))
+	assert.False(t, usesLocalAgent(corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{app, agent}}}))
+	assert.True(t, usesLocalAgent(corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "fargate-scheduler", Containers: []corev1.Container{app, agent}}}))
+}
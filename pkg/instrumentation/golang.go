@@ -78,7 +78,7 @@ func injectGoSDK(goSpec v1alpha1.Go, pod corev1.Pod) (corev1.Pod, error) {
 	}
 
 	pod.Spec.Containers = append(pod.Spec.Containers, goAgent)
-	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+	setVolume(&pod, corev1.Volume{
 		Name: kernelDebugVolumeName,
 		VolumeSource: corev1.VolumeSource{
 			HostPath: &corev1.HostPathVolumeSource{
@@ -89,6 +89,7 @@ func isAutoInstrumentationInjected(pod corev1.Pod) bool {
 			nodejsInitContainerName,
 			pythonInitContainerName,
 			apacheAgentInitContainerName,
+			nginxAgentInitContainerName,
 			apacheAgentCloneContainerName,
 		}, cont.Name) {
 			return true
@@ -168,6 +169,42 @@ func isInstrWithoutContainers(inst instrumentationWithContainers) int {
 	return 0
 }
 
+// appendArgument appends the argument to the value of an environment variable, unless the value already has it, such
+// as when the webhook is reinvoked after another webhook changed the pod.
+func appendArgument(value string, argument string) string {
+	if strings.Contains(value, strings.TrimSpace(argument)) {
+		return value
+	}
+	return value + argument
+}
+
+// containsPath returns whether the colon separated paths of the value contain the path.
+func containsPath(value string, path string) bool {
+	return slices.Contains(strings.Split(value, ":"), path)
+}
+
+// setVolumeMount adds the volume mount to the container, replacing the mount of the same volume or at the same path.
+func setVolumeMount(container *corev1.Container, mount corev1.VolumeMount) {
+	for i, existing := range container.VolumeMounts {
+		if existing.Name == mount.Name || existing.MountPath == mount.MountPath {
+			container.VolumeMounts[i] = mount
+			return
+		}
+	}
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+}
+
+// setVolume adds the volume to the pod, replacing the volume of the same name.
+func setVolume(pod *corev1.Pod, volume corev1.Volume) {
+	for i, existing := range pod.Spec.Volumes {
+		if existing.Name == volume.Name {
+			pod.Spec.Volumes[i] = volume
+			return
+		}
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+}
+
 func volumeSize(quantity *resource.Quantity) *resource.Quantity {
 	if quantity == nil {
 		return &defaultSize
@@ -228,3 +228,33 @@ func TestInstrWithoutContainers(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendArgument(t *testing.T) {
+	assert.Equal(t, "-Xmx1g"+javaJVMArgument, appendArgument("-Xmx1g", javaJVMArgument))
+	assert.Equal(t, "-Xmx1g"+javaJVMArgument, appendArgument("-Xmx1g"+javaJVMArgument, javaJVMArgument))
+	assert.Equal(t, javaJVMArgument, appendArgument("", javaJVMArgument))
+}
+
+func TestContainsPath(t *testing.T) {
+	assert.True(t, containsPath("/app:/otel-auto-instrumentation-python", "/otel-auto-instrumentation-python"))
+	assert.False(t, containsPath("/app:/otel-auto-instrumentation-python/lib", "/otel-auto-instrumentation-python"))
+}
+
+func TestSetVolumeMount(t *testing.T) {
+	container := corev1.Container{VolumeMounts: []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data"},
+		{Name: "stale", MountPath: javaInstrMountPath},
+	}}
+	mount := corev1.VolumeMount{Name: javaVolumeName, MountPath: javaInstrMountPath}
+	setVolumeMount(&container, mount)
+	setVolumeMount(&container, mount)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "data", MountPath: "/data"}, mount}, container.VolumeMounts)
+}
+
+func TestSetVolume(t *testing.T) {
+	pod := corev1.Pod{}
+	volume := corev1.Volume{Name: javaVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	setVolume(&pod, volume)
+	setVolume(&pod, volume)
+	assert.Equal(t, []corev1.Volume{volume}, pod.Spec.Volumes)
+}
@@ -4,9 +4,12 @@
 package instrumentation
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
 )
 
 const (
@@ -25,8 +28,9 @@ var (
 
 func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) (corev1.Pod, error) {
 	container := &pod.Spec.Containers[index]
+	javaAgentEnv := javaAgentEnvVarName(pod, *container, javaJVMArgument)
 
-	err := validateContainerEnv(container.Env, envJavaToolsOptions)
+	err := validateContainerEnv(container.Env, javaAgentEnv)
 	if err != nil {
 		return pod, err
 	}
@@ -43,24 +47,31 @@ func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int, allEnvs
 		}
 	}
 
-	idx := getIndexOfEnv(container.Env, envJavaToolsOptions)
-	if idx == -1 {
+	injectJavaKafkaConfig(javaSpec.Kafka, container, allEnvs)
+
+	if javaSpec.JmxInsights != nil && len(javaSpec.JmxInsights.TargetSystems) > 0 &&
+		getIndexOfEnv(container.Env, jmx.EnvTargetSystem) == -1 && getEnvValue(allEnvs, jmx.EnvTargetSystem) == "" {
 		container.Env = append(container.Env, corev1.EnvVar{
-			Name:  envJavaToolsOptions,
-			Value: javaJVMArgument,
+			Name:  jmx.EnvTargetSystem,
+			Value: jmxTargetSystems(javaSpec.JmxInsights),
 		})
-	} else {
-		container.Env[idx].Value = container.Env[idx].Value + javaJVMArgument
 	}
 
-	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+	addJavaAgentArgument(pod, container, javaJVMArgument)
+
+	if shouldInjectProfiler(javaSpec.Profiler, pod) {
+		addJavaAgentArgument(pod, container, javaProfilerJVMArgument)
+		pod = injectProfiler(*javaSpec.Profiler, javaProfilerLayout, pod, index, allEnvs)
+	}
+
+	setVolumeMount(container, corev1.VolumeMount{
 		Name:      javaVolumeName,
 		MountPath: javaInstrMountPath,
 	})
 
 	// We just inject Volumes and init containers for the first processed container.
 	if isInitContainerMissing(pod, javaInitContainerName) {
-		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		setVolume(&pod, corev1.Volume{
 			Name: javaVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
@@ -88,3 +99,16 @@ func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int, allEnvs
 
 	return pod, err
 }
+
+// jmxTargetSystems returns the comma separated target systems of the JMX insights, without duplicates.
+func jmxTargetSystems(insights *v1alpha1.JmxInsights) string {
+	var targetSystems []string
+	seen := map[v1alpha1.JmxTargetSystem]bool{}
+	for _, target := range insights.TargetSystems {
+		if !seen[target] {
+			seen[target] = true
+			targetSystems = append(targetSystems, string(target))
+		}
+	}
+	return strings.Join(targetSystems, ",")
+}
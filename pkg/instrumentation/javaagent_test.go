@@ -172,7 +172,7 @@ func TestInjectJavaagent(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectJavaagent(test.Java, test.pod, 0)
+			pod, err := injectJavaagent(test.Java, test.pod, 0, test.pod.Spec.Containers[0].Env)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
@@ -344,9 +344,49 @@ func TestInjectJavaagentWindows(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectJavaagent(test.Java, test.pod, 0)
+			pod, err := injectJavaagent(test.Java, test.pod, 0, test.pod.Spec.Containers[0].Env)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
 	}
 }
+
+func TestInjectJavaagentJmxInsights(t *testing.T) {
+	javaSpec := v1alpha1.Java{
+		Image: "foo/bar:1",
+		JmxInsights: &v1alpha1.JmxInsights{
+			TargetSystems: []v1alpha1.JmxTargetSystem{"jvm", "tomcat", "jvm", "hikari"},
+		},
+	}
+
+	t.Run("target systems injected", func(t *testing.T) {
+		pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{}}}}
+		pod, err := injectJavaagent(javaSpec, pod, 0, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "OTEL_JMX_TARGET_SYSTEM", Value: "jvm,tomcat,hikari"})
+	})
+	t.Run("container target systems kept", func(t *testing.T) {
+		userEnv := corev1.EnvVar{Name: "OTEL_JMX_TARGET_SYSTEM", Value: "kafka"}
+		pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Env: []corev1.EnvVar{userEnv}}}}}
+		pod, err := injectJavaagent(javaSpec, pod, 0, []corev1.EnvVar{userEnv})
+		assert.NoError(t, err)
+		assert.Equal(t, []corev1.EnvVar{userEnv, {Name: "JAVA_TOOL_OPTIONS", Value: javaJVMArgument}}, pod.Spec.Containers[0].Env)
+	})
+}
+
+func TestInjectJavaagentReinvocation(t *testing.T) {
+	javaSpec := v1alpha1.Java{Image: "foo/bar:1"}
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Env: []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"}},
+	}}}}
+
+	pod, err := injectJavaagent(javaSpec, pod, 0, nil)
+	assert.NoError(t, err)
+	injected := pod.DeepCopy()
+	// a later webhook removes the init container, and the webhook is reinvoked
+	pod.Spec.InitContainers = nil
+	pod, err = injectJavaagent(javaSpec, pod, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, injected.Spec, pod.Spec)
+	assert.Equal(t, "-Xmx1g"+javaJVMArgument, pod.Spec.Containers[0].Env[0].Value)
+}
@@ -15,9 +15,10 @@ const (
 	TargetKafka         = "kafka"
 	TargetKafkaConsumer = "kafka-consumer"
 	TargetKafkaProducer = "kafka-producer"
+	TargetHikari        = "hikari"
 )
 
-var SupportedTargets = []string{TargetJVM, TargetTomcat, TargetKafka, TargetKafkaConsumer, TargetKafkaProducer}
+var SupportedTargets = []string{TargetJVM, TargetTomcat, TargetKafka, TargetKafkaConsumer, TargetKafkaProducer, TargetHikari}
 
 func AnnotationKey(target string) string {
 	return annotationPrefix + target
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const (
+	envJavaKafkaEnabled               = "OTEL_INSTRUMENTATION_KAFKA_ENABLED"
+	envPythonDisabledInstrumentations = "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS"
+
+	kafkaConsumerGroupAttribute = "messaging.kafka.consumer.group"
+	kafkaClusterAttribute       = "messaging.kafka.cluster"
+)
+
+// pythonKafkaInstrumentations are the entry point names of the kafka-python and confluent-kafka instrumentations.
+var pythonKafkaInstrumentations = []string{"kafka", "confluent_kafka"}
+
+// injectJavaKafkaConfig applies the Kafka preset of the Java spec to the container. Env vars set by the user are
+// never overridden.
+func injectJavaKafkaConfig(kafka *v1alpha1.Kafka, container *corev1.Container, allEnvs []corev1.EnvVar) {
+	if kafka == nil {
+		return
+	}
+	if kafka.Enabled != nil && getIndexOfEnv(container.Env, envJavaKafkaEnabled) == -1 && getEnvValue(allEnvs, envJavaKafkaEnabled) == "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envJavaKafkaEnabled,
+			Value: strconv.FormatBool(*kafka.Enabled),
+		})
+	}
+	injectKafkaResourceAttributes(kafka, container)
+}
+
+// injectPythonKafkaConfig applies the Kafka preset of the Python spec to the container. The Python instrumentations
+// are enabled by default, so only disabling them requires changes.
+func injectPythonKafkaConfig(kafka *v1alpha1.Kafka, container *corev1.Container, allEnvs []corev1.EnvVar) {
+	if kafka == nil {
+		return
+	}
+	if kafka.Enabled != nil && !*kafka.Enabled {
+		idx := getIndexOfEnv(container.Env, envPythonDisabledInstrumentations)
+		switch {
+		case idx != -1:
+			if container.Env[idx].ValueFrom == nil {
+				container.Env[idx].Value = appendToList(container.Env[idx].Value, pythonKafkaInstrumentations)
+			}
+		case getEnvValue(allEnvs, envPythonDisabledInstrumentations) == "":
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  envPythonDisabledInstrumentations,
+				Value: strings.Join(pythonKafkaInstrumentations, ","),
+			})
+		}
+	}
+	injectKafkaResourceAttributes(kafka, container)
+}
+
+// injectKafkaResourceAttributes adds the consumer group and cluster to the resource attributes of the container,
+// unless the attributes are already set.
+func injectKafkaResourceAttributes(kafka *v1alpha1.Kafka, container *corev1.Container) {
+	attributes := map[string]string{}
+	if kafka.ConsumerGroup != "" {
+		attributes[kafkaConsumerGroupAttribute] = kafka.ConsumerGroup
+	}
+	if kafka.Cluster != "" {
+		attributes[kafkaClusterAttribute] = kafka.Cluster
+	}
+	if len(attributes) == 0 {
+		return
+	}
+	idx := getIndexOfEnv(container.Env, constants.EnvOTELResourceAttrs)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.EnvOTELResourceAttrs,
+			Value: resourceMapToStr(attributes),
+		})
+		return
+	}
+	value := container.Env[idx].Value
+	for _, key := range []string{kafkaConsumerGroupAttribute, kafkaClusterAttribute} {
+		v, ok := attributes[key]
+		if !ok || strings.Contains(value, key+"=") {
+			continue
+		}
+		if value != "" && !strings.HasSuffix(value, ",") {
+			value += ","
+		}
+		value += fmt.Sprintf("%s=%s", key, v)
+	}
+	container.Env[idx].Value = value
+}
+
+// appendToList appends the missing items to the comma separated list.
+func appendToList(list string, items []string) string {
+	existing := map[string]bool{}
+	for _, item := range strings.Split(list, ",") {
+		existing[strings.TrimSpace(item)] = true
+	}
+	for _, item := range items {
+		if existing[item] {
+			continue
+		}
+		if list != "" {
+			list += ","
+		}
+		list += item
+	}
+	return list
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestInjectJavaKafkaConfig(t *testing.T) {
+	disabled := false
+	kafka := &v1alpha1.Kafka{Enabled: &disabled, ConsumerGroup: "orders", Cluster: "msk-prod"}
+
+	container := &corev1.Container{}
+	injectJavaKafkaConfig(kafka, container, nil)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: envJavaKafkaEnabled, Value: "false"},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "messaging.kafka.cluster=msk-prod,messaging.kafka.consumer.group=orders"},
+	}, container.Env)
+
+	userEnvs := []corev1.EnvVar{
+		{Name: envJavaKafkaEnabled, Value: "true"},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "messaging.kafka.consumer.group=payments"},
+	}
+	container = &corev1.Container{Env: append([]corev1.EnvVar{}, userEnvs...)}
+	injectJavaKafkaConfig(kafka, container, userEnvs)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: envJavaKafkaEnabled, Value: "true"},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "messaging.kafka.consumer.group=payments,messaging.kafka.cluster=msk-prod"},
+	}, container.Env)
+
+	container = &corev1.Container{}
+	injectJavaKafkaConfig(nil, container, nil)
+	assert.Empty(t, container.Env)
+}
+
+func TestInjectPythonKafkaConfig(t *testing.T) {
+	disabled, enabled := false, true
+
+	container := &corev1.Container{}
+	injectPythonKafkaConfig(&v1alpha1.Kafka{Enabled: &enabled}, container, nil)
+	assert.Empty(t, container.Env)
+
+	injectPythonKafkaConfig(&v1alpha1.Kafka{Enabled: &disabled}, container, nil)
+	assert.Equal(t, []corev1.EnvVar{{Name: envPythonDisabledInstrumentations, Value: "kafka,confluent_kafka"}}, container.Env)
+
+	userEnv := corev1.EnvVar{Name: envPythonDisabledInstrumentations, Value: "redis,kafka"}
+	container = &corev1.Container{Env: []corev1.EnvVar{userEnv}}
+	injectPythonKafkaConfig(&v1alpha1.Kafka{Enabled: &disabled, ConsumerGroup: "orders"}, container, []corev1.EnvVar{userEnv})
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: envPythonDisabledInstrumentations, Value: "redis,kafka,confluent_kafka"},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "messaging.kafka.consumer.group=orders"},
+	}, container.Env)
+}
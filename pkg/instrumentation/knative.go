@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
+)
+
+const (
+	knativeServiceLabel       = "serving.knative.dev/service"
+	knativeConfigurationLabel = "serving.knative.dev/configuration"
+	knativeRevisionLabel      = "serving.knative.dev/revision"
+)
+
+var (
+	knativeServiceGVK       = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Service"}
+	knativeConfigurationGVK = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Configuration"}
+)
+
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=services;configurations,verbs=get
+
+// isKnativePod returns true if the pod was created for a Knative Revision.
+func isKnativePod(pod corev1.Pod) bool {
+	return pod.Labels[knativeRevisionLabel] != ""
+}
+
+// isInheritableAnnotation returns true for the annotations that control instrumentation injection and can therefore
+// be set on a Knative Service or Configuration instead of on the revision template.
+func isInheritableAnnotation(key string) bool {
+	return strings.HasPrefix(key, "instrumentation.opentelemetry.io/") || strings.HasPrefix(key, jmx.AnnotationKey(""))
+}
+
+// inheritKnativeAnnotations copies the instrumentation annotations set on the Knative Configuration and Service that
+// own the pod's Revision onto the pod. Knative does not propagate annotations from the Service or Configuration
+// metadata to revisions, so without this users would have to edit the revision template, which creates a new
+// revision for every change. Annotations already present on the pod take precedence, followed by the Configuration
+// and then the Service.
+func (pm *instPodMutator) inheritKnativeAnnotations(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
+	if !isKnativePod(pod) {
+		return pod
+	}
+	var owners []metav1.Object
+	if name := pod.Labels[knativeConfigurationLabel]; name != "" {
+		if obj := pm.getKnativeObject(ctx, knativeConfigurationGVK, ns.Name, name); obj != nil {
+			owners = append(owners, obj)
+		}
+	}
+	if name := pod.Labels[knativeServiceLabel]; name != "" {
+		if obj := pm.getKnativeObject(ctx, knativeServiceGVK, ns.Name, name); obj != nil {
+			owners = append(owners, obj)
+		}
+	}
+	for _, owner := range owners {
+		pod = inheritAnnotations(pod, owner)
+	}
+	return pod
+}
+
+func (pm *instPodMutator) getKnativeObject(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) metav1.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := pm.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		pm.Logger.V(1).Info("unable to get knative object", "kind", gvk.Kind, "namespace", namespace, "name", name, "error", err.Error())
+		return nil
+	}
+	return obj
+}
+
+// inheritAnnotations copies the inheritable annotations from the owner to the pod if they are not already set.
+func inheritAnnotations(pod corev1.Pod, owner metav1.Object) corev1.Pod {
+	for key, value := range owner.GetAnnotations() {
+		if !isInheritableAnnotation(key) {
+			continue
+		}
+		if _, ok := pod.Annotations[key]; ok {
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[key] = value
+	}
+	return pod
+}
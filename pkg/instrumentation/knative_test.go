@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newKnativeObject(kind, name string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("serving.knative.dev/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("knative-ns")
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestInheritKnativeAnnotations(t *testing.T) {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "knative-ns"}}
+	svc := newKnativeObject("Service", "my-func", map[string]string{
+		annotationInjectJava:                       "true",
+		annotationInjectPython:                     "true",
+		"cloudwatch.aws.amazon.com/inject-jmx-jvm": "true",
+		"serving.knative.dev/creator":              "someone",
+	})
+	cfg := newKnativeObject("Configuration", "my-func", map[string]string{
+		annotationInjectPython: "false",
+	})
+	pm := &instPodMutator{
+		Client: fake.NewClientBuilder().WithObjects(svc, cfg).Build(),
+		Logger: logr.Discard(),
+	}
+
+	for _, tt := range []struct {
+		name     string
+		pod      corev1.Pod
+		expected map[string]string
+	}{
+		{
+			name: "not a knative pod",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{knativeServiceLabel: "my-func"},
+			}},
+			expected: nil,
+		},
+		{
+			name: "inherits from configuration and service",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					knativeServiceLabel:       "my-func",
+					knativeConfigurationLabel: "my-func",
+					knativeRevisionLabel:      "my-func-00001",
+				},
+			}},
+			expected: map[string]string{
+				annotationInjectJava:                       "true",
+				annotationInjectPython:                     "false",
+				"cloudwatch.aws.amazon.com/inject-jmx-jvm": "true",
+			},
+		},
+		{
+			name: "pod annotations take precedence",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					knativeServiceLabel:  "my-func",
+					knativeRevisionLabel: "my-func-00001",
+				},
+				Annotations: map[string]string{annotationInjectJava: "false"},
+			}},
+			expected: map[string]string{
+				annotationInjectJava:                       "false",
+				annotationInjectPython:                     "true",
+				"cloudwatch.aws.amazon.com/inject-jmx-jvm": "true",
+			},
+		},
+		{
+			name: "missing knative service",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					knativeServiceLabel:  "other-func",
+					knativeRevisionLabel: "other-func-00001",
+				},
+			}},
+			expected: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := pm.inheritKnativeAnnotations(context.Background(), ns, tt.pod)
+			assert.Equal(t, tt.expected, pod.Annotations)
+		})
+	}
+}
+
+func TestChooseServiceNameKnative(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				knativeServiceLabel:  "my-func",
+				knativeRevisionLabel: "my-func-00002",
+			},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "user-container"}}},
+	}
+	resources := map[string]string{
+		string(semconv.K8SDeploymentNameKey): "my-func-00002-deployment",
+	}
+	assert.Equal(t, "my-func", chooseServiceName(pod, resources, 0))
+}
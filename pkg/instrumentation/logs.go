@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envOtelLogsExporter              = "OTEL_LOGS_EXPORTER"
+	envOtelExporterOTLPLogsEndpoint  = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+	envOtelExporterOTLPLogsProtocol  = "OTEL_EXPORTER_OTLP_LOGS_PROTOCOL"
+	envJavaLogbackAppenderEnabled    = "OTEL_INSTRUMENTATION_LOGBACK_APPENDER_ENABLED"
+	envJavaLog4jAppenderEnabled      = "OTEL_INSTRUMENTATION_LOG4J_APPENDER_ENABLED"
+	envPythonLoggingAutoInstrEnabled = "OTEL_PYTHON_LOGGING_AUTO_INSTRUMENTATION_ENABLED"
+)
+
+// logsEndpoint returns the endpoint the logs of the pod are exported to.
+func logsEndpoint(logs *v1alpha1.Logs, pod corev1.Pod) string {
+	if logs.Endpoint != "" {
+		return logs.Endpoint
+	}
+	endpoint := cloudwatchAgentStandardEndpoint
+	if isWindowsPod(pod) {
+		endpoint = cloudwatchAgentWindowsEndpoint
+	}
+	return fmt.Sprintf("%s://%s:%s/v1/logs", http, endpoint, cloudwatchAgentPort)
+}
+
+// injectLogsConfig enables the OTLP logs exporter of the container at index when logs are enabled in the
+// Instrumentation, replacing the defaults that disable logs. Env vars set by the user are never overridden.
+func (i *sdkInjector) injectLogsConfig(otelinst v1alpha1.Instrumentation, pod corev1.Pod, index int, allEnvs []corev1.EnvVar, instType Type) corev1.Pod {
+	logs := otelinst.Spec.Logs
+	if logs == nil || !logs.Enabled {
+		return pod
+	}
+	container := &pod.Spec.Containers[index]
+	setEnvIfNotUserDefined(container, allEnvs, envOtelLogsExporter, "otlp")
+	setEnvIfNotUserDefined(container, allEnvs, envOtelExporterOTLPLogsEndpoint, logsEndpoint(logs, pod))
+	setEnvIfNotUserDefined(container, allEnvs, envOtelExporterOTLPLogsProtocol, "http/protobuf")
+	switch instType {
+	case TypeJava:
+		setEnvIfNotUserDefined(container, allEnvs, envJavaLogbackAppenderEnabled, "true")
+		setEnvIfNotUserDefined(container, allEnvs, envJavaLog4jAppenderEnabled, "true")
+	case TypePython:
+		setEnvIfNotUserDefined(container, allEnvs, envPythonLoggingAutoInstrEnabled, "true")
+	}
+	return pod
+}
+
+// setEnvIfNotUserDefined sets the env var on the container, replacing any value injected by the operator. It does
+// nothing if the env var is defined in allEnvs, the env vars of the original container.
+func setEnvIfNotUserDefined(container *corev1.Container, allEnvs []corev1.EnvVar, name, value string) {
+	if getEnvValue(allEnvs, name) != "" {
+		return
+	}
+	if idx := getIndexOfEnv(container.Env, name); idx != -1 {
+		container.Env[idx].Value = value
+	} else {
+		container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: value})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestInjectLogsConfig(t *testing.T) {
+	injector := &sdkInjector{logger: logr.Discard()}
+	enabled := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{Logs: &v1alpha1.Logs{Enabled: true}}}
+	newPod := func(env ...corev1.EnvVar) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Env: env}}}}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		pod := injector.injectLogsConfig(v1alpha1.Instrumentation{}, newPod(), 0, nil, TypeJava)
+		assert.Empty(t, pod.Spec.Containers[0].Env)
+	})
+	t.Run("java", func(t *testing.T) {
+		pod := injector.injectLogsConfig(enabled, newPod(corev1.EnvVar{Name: envOtelLogsExporter, Value: "none"}), 0, nil, TypeJava)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: envOtelLogsExporter, Value: "otlp"},
+			{Name: envOtelExporterOTLPLogsEndpoint, Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/logs"},
+			{Name: envOtelExporterOTLPLogsProtocol, Value: "http/protobuf"},
+			{Name: envJavaLogbackAppenderEnabled, Value: "true"},
+			{Name: envJavaLog4jAppenderEnabled, Value: "true"},
+		}, pod.Spec.Containers[0].Env)
+	})
+	t.Run("python on windows", func(t *testing.T) {
+		pod := newPod()
+		pod.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+		pod = injector.injectLogsConfig(enabled, pod, 0, nil, TypePython)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: envOtelLogsExporter, Value: "otlp"},
+			{Name: envOtelExporterOTLPLogsEndpoint, Value: "http://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/logs"},
+			{Name: envOtelExporterOTLPLogsProtocol, Value: "http/protobuf"},
+			{Name: envPythonLoggingAutoInstrEnabled, Value: "true"},
+		}, pod.Spec.Containers[0].Env)
+	})
+	t.Run("user defined env vars are kept", func(t *testing.T) {
+		userEnvs := []corev1.EnvVar{
+			{Name: envOtelLogsExporter, Value: "console"},
+			{Name: envOtelExporterOTLPLogsEndpoint, Value: "http://collector:4318/v1/logs"},
+		}
+		inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{
+			Logs: &v1alpha1.Logs{Enabled: true, Endpoint: "http://gateway:4318/v1/logs"},
+		}}
+		pod := injector.injectLogsConfig(inst, newPod(userEnvs...), 0, userEnvs, TypeDotNet)
+		assert.Equal(t, append(userEnvs, corev1.EnvVar{Name: envOtelExporterOTLPLogsProtocol, Value: "http/protobuf"}),
+			pod.Spec.Containers[0].Env)
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// serviceMesh is a service mesh whose proxy sidecar the injected workloads export their telemetry through.
+type serviceMesh string
+
+const (
+	meshNone    serviceMesh = ""
+	meshIstio   serviceMesh = "istio"
+	meshLinkerd serviceMesh = "linkerd"
+
+	istioInjectAnnotation      = "sidecar.istio.io/inject"
+	istioInjectLabel           = "istio-injection"
+	istioRevisionLabel         = "istio.io/rev"
+	istioProxyContainer        = "istio-proxy"
+	istioProxyConfigAnnotation = "proxy.istio.io/config"
+	istioHoldApplicationKey    = "holdApplicationUntilProxyStarts"
+
+	linkerdInjectAnnotation     = "linkerd.io/inject"
+	linkerdProxyContainer       = "linkerd-proxy"
+	linkerdProxyAwaitAnnotation = "config.alpha.linkerd.io/proxy-await"
+
+	// envJavaExporterRetry enables the retries of the OTLP exporters of the Java agent, so that the exports failing
+	// until the proxy is ready are retried instead of dropped.
+	envJavaExporterRetry = "OTEL_EXPERIMENTAL_EXPORTER_OTLP_RETRY_ENABLED"
+)
+
+// meshOf returns the service mesh injecting its proxy into the pod, whether the proxy is already injected or the
+// pod or its namespace request the injection, which may happen after the instrumentation.
+func meshOf(ns corev1.Namespace, pod corev1.Pod) serviceMesh {
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		switch container.Name {
+		case istioProxyContainer:
+			return meshIstio
+		case linkerdProxyContainer:
+			return meshLinkerd
+		}
+	}
+
+	// the annotation of the pod opts in or out of the injection of the namespace
+	if inject, ok := pod.Annotations[istioInjectAnnotation]; ok {
+		if strings.EqualFold(inject, "true") {
+			return meshIstio
+		}
+	} else if pod.Labels[istioInjectAnnotation] == "true" || ns.Labels[istioInjectLabel] == "enabled" || ns.Labels[istioRevisionLabel] != "" {
+		return meshIstio
+	}
+	if inject := pod.Annotations[linkerdInjectAnnotation]; inject != "" {
+		if inject != "disabled" {
+			return meshLinkerd
+		}
+	} else if inject := ns.Annotations[linkerdInjectAnnotation]; inject != "" && inject != "disabled" {
+		return meshLinkerd
+	}
+	return meshNone
+}
+
+// hasNativeProxy returns whether the proxy of the mesh runs as a native sidecar, an init container which is always
+// restarted and which the kubelet starts before the containers of the pod.
+func hasNativeProxy(pod corev1.Pod) bool {
+	for _, container := range pod.Spec.InitContainers {
+		if (container.Name == istioProxyContainer || container.Name == linkerdProxyContainer) &&
+			container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			return true
+		}
+	}
+	return false
+}
+
+// withMeshStartupOrdering holds the containers of the instrumented pod, the Go instrumentation sidecar included,
+// until the proxy of its mesh is started, so that the first exports of the SDKs don't fail. Nothing is needed when
+// the proxy is a native sidecar, and the settings of the user are never overridden.
+func withMeshStartupOrdering(ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
+	if hasNativeProxy(pod) {
+		return pod
+	}
+	switch meshOf(ns, pod) {
+	case meshIstio:
+		proxyConfig, ok := holdApplicationProxyConfig(pod.Annotations[istioProxyConfigAnnotation])
+		if !ok {
+			return pod
+		}
+		pod = withAnnotation(pod, istioProxyConfigAnnotation, proxyConfig)
+	case meshLinkerd:
+		if _, ok := pod.Annotations[linkerdProxyAwaitAnnotation]; !ok {
+			pod = withAnnotation(pod, linkerdProxyAwaitAnnotation, "enabled")
+		}
+	}
+	return pod
+}
+
+// holdApplicationProxyConfig adds holdApplicationUntilProxyStarts to the Istio proxy config of the pod, returning
+// false when it is already set or the config can't be parsed.
+func holdApplicationProxyConfig(proxyConfig string) (string, bool) {
+	config := map[string]interface{}{}
+	if proxyConfig != "" {
+		if err := yaml.Unmarshal([]byte(proxyConfig), &config); err != nil {
+			return "", false
+		}
+	}
+	if _, ok := config[istioHoldApplicationKey]; ok {
+		return "", false
+	}
+	config[istioHoldApplicationKey] = true
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+func withAnnotation(pod corev1.Pod, key, value string) corev1.Pod {
+	pod.Annotations = maps.Clone(pod.Annotations)
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[key] = value
+	return pod
+}
+
+// injectExportRetryConfig enables the retries of the exporters of the Java agent in the container at index when the
+// pod is in a mesh, whose proxy may not be ready for the first exports.
+func (i *sdkInjector) injectExportRetryConfig(ns corev1.Namespace, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) corev1.Pod {
+	if meshOf(ns, pod) == meshNone {
+		return pod
+	}
+	setEnvIfNotUserDefined(&pod.Spec.Containers[index], allEnvs, envJavaExporterRetry, "true")
+	return pod
+}
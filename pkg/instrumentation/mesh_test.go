@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMeshOf(t *testing.T) {
+	meta := func(labels, annotations map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Labels: labels, Annotations: annotations}
+	}
+	for _, tt := range []struct {
+		name     string
+		ns       corev1.Namespace
+		pod      corev1.Pod
+		expected serviceMesh
+	}{
+		{name: "no mesh", expected: meshNone},
+		{name: "istio namespace", ns: corev1.Namespace{ObjectMeta: meta(map[string]string{"istio-injection": "enabled"}, nil)}, expected: meshIstio},
+		{name: "istio revision", ns: corev1.Namespace{ObjectMeta: meta(map[string]string{"istio.io/rev": "canary"}, nil)}, expected: meshIstio},
+		{
+			name:     "istio opt out",
+			ns:       corev1.Namespace{ObjectMeta: meta(map[string]string{"istio-injection": "enabled"}, nil)},
+			pod:      corev1.Pod{ObjectMeta: meta(nil, map[string]string{"sidecar.istio.io/inject": "false"})},
+			expected: meshNone,
+		},
+		{name: "istio pod label", pod: corev1.Pod{ObjectMeta: meta(map[string]string{"sidecar.istio.io/inject": "true"}, nil)}, expected: meshIstio},
+		{name: "istio proxy injected", pod: corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}}}}, expected: meshIstio},
+		{name: "linkerd namespace", ns: corev1.Namespace{ObjectMeta: meta(nil, map[string]string{"linkerd.io/inject": "enabled"})}, expected: meshLinkerd},
+		{
+			name:     "linkerd opt out",
+			ns:       corev1.Namespace{ObjectMeta: meta(nil, map[string]string{"linkerd.io/inject": "enabled"})},
+			pod:      corev1.Pod{ObjectMeta: meta(nil, map[string]string{"linkerd.io/inject": "disabled"})},
+			expected: meshNone,
+		},
+		{name: "linkerd pod", pod: corev1.Pod{ObjectMeta: meta(nil, map[string]string{"linkerd.io/inject": "ingress"})}, expected: meshLinkerd},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, meshOf(tt.ns, tt.pod))
+		})
+	}
+}
+
+func TestWithMeshStartupOrdering(t *testing.T) {
+	istio := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio-injection": "enabled"}}}
+	linkerd := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/inject": "enabled"}}}
+	always := corev1.ContainerRestartPolicyAlways
+
+	pod := withMeshStartupOrdering(istio, corev1.Pod{})
+	assert.Equal(t, map[string]string{"proxy.istio.io/config": `{"holdApplicationUntilProxyStarts":true}`}, pod.Annotations)
+
+	annotations := map[string]string{"proxy.istio.io/config": "concurrency: 2\n"}
+	pod = withMeshStartupOrdering(istio, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}})
+	assert.Equal(t, `{"concurrency":2,"holdApplicationUntilProxyStarts":true}`, pod.Annotations["proxy.istio.io/config"])
+	assert.Equal(t, "concurrency: 2\n", annotations["proxy.istio.io/config"])
+
+	// the settings of the user are kept
+	pod = withMeshStartupOrdering(istio, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"proxy.istio.io/config": "holdApplicationUntilProxyStarts: false"}}})
+	assert.Equal(t, "holdApplicationUntilProxyStarts: false", pod.Annotations["proxy.istio.io/config"])
+	pod = withMeshStartupOrdering(linkerd, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"config.alpha.linkerd.io/proxy-await": "disabled"}}})
+	assert.Equal(t, "disabled", pod.Annotations["config.alpha.linkerd.io/proxy-await"])
+
+	pod = withMeshStartupOrdering(linkerd, corev1.Pod{})
+	assert.Equal(t, map[string]string{"config.alpha.linkerd.io/proxy-await": "enabled"}, pod.Annotations)
+
+	// the native sidecars are started before the containers of the pod
+	pod = withMeshStartupOrdering(istio, corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "istio-proxy", RestartPolicy: &always}}}})
+	assert.Empty(t, pod.Annotations)
+
+	assert.Empty(t, withMeshStartupOrdering(corev1.Namespace{}, corev1.Pod{}).Annotations)
+}
+
+func TestInjectExportRetryConfig(t *testing.T) {
+	injector := sdkInjector{}
+	istio := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio-injection": "enabled"}}}
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+
+	assert.Empty(t, injector.injectExportRetryConfig(corev1.Namespace{}, pod, 0, nil).Spec.Containers[0].Env)
+	pod = injector.injectExportRetryConfig(istio, pod, 0, nil)
+	assert.Equal(t, []corev1.EnvVar{{Name: "OTEL_EXPERIMENTAL_EXPORTER_OTLP_RETRY_ENABLED", Value: "true"}}, pod.Spec.Containers[0].Env)
+}
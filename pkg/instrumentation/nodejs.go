@@ -44,17 +44,17 @@ func injectNodeJSSDK(nodeJSSpec v1alpha1.NodeJS, pod corev1.Pod, index int, allE
 			Value: nodeRequireArgument,
 		})
 	} else if idx > -1 {
-		container.Env[idx].Value = container.Env[idx].Value + nodeRequireArgument
+		container.Env[idx].Value = appendArgument(container.Env[idx].Value, nodeRequireArgument)
 	}
 
-	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+	setVolumeMount(container, corev1.VolumeMount{
 		Name:      nodejsVolumeName,
 		MountPath: nodejsInstrMountPath,
 	})
 
 	// We just inject Volumes and init containers for the first processed container
 	if isInitContainerMissing(pod, nodejsInitContainerName) {
-		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		setVolume(&pod, corev1.Volume{
 			Name: nodejsVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
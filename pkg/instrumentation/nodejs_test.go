@@ -172,7 +172,7 @@ func TestInjectNodeJSSDK(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectNodeJSSDK(test.NodeJS, test.pod, 0)
+			pod, err := injectNodeJSSDK(test.NodeJS, test.pod, 0, test.pod.Spec.Containers[0].Env)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
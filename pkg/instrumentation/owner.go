@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	ownerKindAttribute = "k8s.owner.kind"
+	ownerNameAttribute = "k8s.owner.name"
+
+	// maxOwnerDepth bounds the owner traversal in case of misconfigured owner references.
+	maxOwnerDepth = 5
+)
+
+// addOwnerResourceLabels follows the controller owner references starting at owner, which is not a built-in workload,
+// and records the last owner visited. The traversal stops at the root, at one of the stop kinds, or when an owner
+// cannot be read, e.g. because the operator is not allowed to get it.
+func (i *sdkInjector) addOwnerResourceLabels(ctx context.Context, stopKinds []string, ns corev1.Namespace, owner metav1.OwnerReference, resources map[attribute.Key]string, depth int) {
+	resources[ownerKindAttribute] = owner.Kind
+	resources[ownerNameAttribute] = owner.Name
+	if i.client == nil || depth >= maxOwnerDepth || isOwnerStopKind(stopKinds, owner.Kind) {
+		return
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(owner.APIVersion)
+	obj.SetKind(owner.Kind)
+	if err := i.client.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: owner.Name}, obj); err != nil {
+		i.logger.V(1).Info("failed to get owner", "kind", owner.Kind, "name", owner.Name, "namespace", ns.Name, "error", err.Error())
+		return
+	}
+	if next := metav1.GetControllerOfNoCopy(obj); next != nil {
+		i.addOwnerResourceLabels(ctx, stopKinds, ns, *next, resources, depth+1)
+	}
+}
+
+func isOwnerStopKind(stopKinds []string, kind string) bool {
+	for _, stopKind := range stopKinds {
+		if strings.EqualFold(stopKind, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// ownerClient serves the owners of the test pods by kind and name.
+type ownerClient struct {
+	client.Client
+	owners map[string][]metav1.OwnerReference
+}
+
+func (c ownerClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	u := obj.(*unstructured.Unstructured)
+	refs, ok := c.owners[u.GetKind()+"/"+key.Name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: u.GetKind()}, key.Name)
+	}
+	u.SetName(key.Name)
+	u.SetOwnerReferences(refs)
+	return nil
+}
+
+func TestCreateResourceMapOwnerChain(t *testing.T) {
+	injector := &sdkInjector{logger: logr.Discard(), client: ownerClient{owners: map[string][]metav1.OwnerReference{
+		"StrimziPodSet/events-kafka": {{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka", Name: "events", Controller: ptr.To(true)}},
+		"Kafka/events":               nil,
+	}}}
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "streaming"}}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "events-kafka-0",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "core.strimzi.io/v1beta2", Kind: "StrimziPodSet", Name: "events-kafka", Controller: ptr.To(true),
+			}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kafka"}}},
+	}
+
+	t.Run("root owner", func(t *testing.T) {
+		res, _ := injector.createResourceMap(context.Background(), v1alpha1.Instrumentation{}, ns, pod, 0)
+		assert.Equal(t, "Kafka", res[ownerKindAttribute])
+		assert.Equal(t, "events", res[ownerNameAttribute])
+		assert.Equal(t, "events", chooseServiceName(pod, res, 0))
+	})
+	t.Run("stop kind", func(t *testing.T) {
+		inst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{
+			Resource: v1alpha1.Resource{OwnerStopKinds: []string{"StrimziPodSet"}},
+		}}
+		res, _ := injector.createResourceMap(context.Background(), inst, ns, pod, 0)
+		assert.Equal(t, "StrimziPodSet", res[ownerKindAttribute])
+		assert.Equal(t, "events-kafka", chooseServiceName(pod, res, 0))
+	})
+	t.Run("owner not found", func(t *testing.T) {
+		pod := *pod.DeepCopy()
+		pod.OwnerReferences[0].Name = "other-kafka"
+		res, _ := injector.createResourceMap(context.Background(), v1alpha1.Instrumentation{}, ns, pod, 0)
+		assert.Equal(t, "other-kafka", res[ownerNameAttribute])
+	})
+	t.Run("built-in workloads take precedence", func(t *testing.T) {
+		res := map[string]string{ownerNameAttribute: "events", "k8s.statefulset.name": "events-kafka"}
+		assert.Equal(t, "events-kafka", chooseServiceName(pod, res, 0))
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"sort"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -16,7 +18,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
@@ -27,6 +32,13 @@ const (
 	amazonCloudWatchAgentName = "cloudwatch-agent"
 )
 
+const (
+	// InjectionStatusInjected is the injection status of the instrumented pods.
+	InjectionStatusInjected = "injected"
+	// InjectionStatusSkipped prefixes the reason the instrumentations requested by a pod were not injected.
+	InjectionStatusSkipped = "skipped"
+)
+
 var (
 	errMultipleInstancesPossible = errors.New("multiple OpenTelemetry Instrumentation instances available, cannot determine which one to select")
 )
@@ -36,6 +48,7 @@ type instPodMutator struct {
 	sdkInjector *sdkInjector
 	Logger      logr.Logger
 	Recorder    record.EventRecorder
+	config      config.Config
 }
 
 type instrumentationWithContainers struct {
@@ -87,6 +100,25 @@ func (langInsts languageInstrumentations) isSingleInstrumentationEnabled() bool
 	return count == 1
 }
 
+// dropWindowsUnsupported removes the instrumentations that cannot be injected into Windows pods and returns the
+// annotations of the dropped ones.
+func (langInsts *languageInstrumentations) dropWindowsUnsupported() []string {
+	var dropped []string
+	for annotation, inst := range map[string]*instrumentationWithContainers{
+		annotationInjectNodeJS:      &langInsts.NodeJS,
+		annotationInjectPython:      &langInsts.Python,
+		annotationInjectGo:          &langInsts.Go,
+		annotationInjectApacheHttpd: &langInsts.ApacheHttpd,
+		annotationInjectNginx:       &langInsts.Nginx,
+	} {
+		if inst.Instrumentation != nil {
+			inst.Instrumentation = nil
+			dropped = append(dropped, annotation)
+		}
+	}
+	return dropped
+}
+
 // Check if specific containers are provided for configured instrumentation.
 func (langInsts languageInstrumentations) areContainerNamesConfiguredForMultipleInstrumentations() (bool, error) {
 	var instrWithoutContainers int
@@ -192,15 +224,17 @@ func (langInsts *languageInstrumentations) setInstrumentationLanguageContainers(
 
 var _ podmutation.PodMutator = (*instPodMutator)(nil)
 
-func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder) *instPodMutator {
+func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder, cfg config.Config) *instPodMutator {
 	return &instPodMutator{
 		Logger: logger,
 		Client: client,
 		sdkInjector: &sdkInjector{
 			logger: logger,
 			client: client,
+			config: cfg,
 		},
 		Recorder: recorder,
+		config:   cfg,
 	}
 }
 
@@ -213,10 +247,14 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		return pod, nil
 	}
 
+	pod = pm.inheritKnativeAnnotations(ctx, ns, pod)
+
 	var inst *v1alpha1.Instrumentation
 	var err error
 
 	insts := languageInstrumentations{}
+	// skipped are the reasons the requested instrumentations are not injected
+	var skipped []string
 
 	// We bail out if any annotation fails to process.
 
@@ -230,6 +268,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Java auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Java auto instrumentation is not enabled")
+		skipped = append(skipped, "support for Java auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNodeJS); err != nil {
@@ -242,6 +281,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for NodeJS auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
+		skipped = append(skipped, "support for NodeJS auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPython); err != nil {
@@ -254,6 +294,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Python auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Python auto instrumentation is not enabled")
+		skipped = append(skipped, "support for Python auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectDotNet); err != nil {
@@ -267,6 +308,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for .NET auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for .NET auto instrumentation is not enabled")
+		skipped = append(skipped, "support for .NET auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectGo); err != nil {
@@ -279,6 +321,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(err, "support for Go auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Go auto instrumentation is not enabled")
+		skipped = append(skipped, "support for Go auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectApacheHttpd); err != nil {
@@ -291,6 +334,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Apache HTTPD auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Apache HTTPD auto instrumentation is not enabled")
+		skipped = append(skipped, "support for Apache HTTPD auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNginx); err != nil {
@@ -303,6 +347,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Nginx auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Nginx auto instrumentation is not enabled")
+		skipped = append(skipped, "support for Nginx auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectSdk); err != nil {
@@ -312,11 +357,22 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	}
 	insts.Sdk.Instrumentation = inst
 
+	if isWindowsPod(pod) {
+		for _, annotation := range insts.dropWindowsUnsupported() {
+			logger.Info("Skipping instrumentation not supported on Windows", "annotation", annotation)
+			skipped = append(skipped, annotation+" is not supported on Windows")
+		}
+	}
+
 	if insts.Java.Instrumentation == nil && insts.NodeJS.Instrumentation == nil && insts.Python.Instrumentation == nil &&
 		insts.DotNet.Instrumentation == nil && insts.Go.Instrumentation == nil && insts.ApacheHttpd.Instrumentation == nil &&
 		insts.Nginx.Instrumentation == nil &&
 		insts.Sdk.Instrumentation == nil {
 
+		if len(skipped) > 0 {
+			sort.Strings(skipped)
+			return withInjectionStatus(pod, skippedStatus(strings.Join(skipped, ", "))), nil
+		}
 		logger.V(1).Info("annotation not present in deployment, skipping instrumentation injection")
 		return pod, nil
 	}
@@ -337,7 +393,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		ok, msg := insts.areContainerNamesConfiguredForMultipleInstrumentations()
 		if !ok {
 			logger.V(1).Error(msg, "skipping instrumentation injection")
-			return pod, nil
+			return withInjectionStatus(pod, skippedStatus(msg.Error())), nil
 		}
 	} else {
 		// We use general annotation for container names
@@ -348,7 +404,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 			insts.setInstrumentationLanguageContainers(generalContainerNames)
 		} else {
 			logger.V(1).Error(fmt.Errorf("multiple injection annotations present"), "skipping instrumentation injection")
-			return pod, nil
+			return withInjectionStatus(pod, skippedStatus("multiple injection annotations present")), nil
 		}
 
 	}
@@ -356,9 +412,48 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	// once it's been determined that instrumentation is desired, none exists yet, and we know which instance it should talk to,
 	// we should inject the instrumentation.
 	modifiedPod := pod
+	if pm.config.VerifiesImages() {
+		// the injection is refused when one of its images doesn't verify, the pod must be left untouched
+		modifiedPod = *pod.DeepCopy()
+	}
 	modifiedPod = pm.sdkInjector.inject(ctx, insts, ns, modifiedPod)
+	// the images of the init containers may come from a registry requiring authentication
+	modifiedPod.Spec.ImagePullSecrets = manifestutils.ImagePullSecrets(modifiedPod.Spec.ImagePullSecrets, pm.config.ImagePullSecrets())
+	// the verification is bounded by a budget of its own, which leaves the admission of the pod in time
+	verifyCtx, cancel := context.WithTimeout(ctx, config.AdmissionImageVerificationTimeout)
+	defer cancel()
+	pinned, err := pm.config.VerifyImages(verifyCtx, manifestutils.AddedImages(pod.Spec, modifiedPod.Spec), config.NewImagePull(ns.Name, modifiedPod.Spec))
+	if err != nil {
+		logger.Error(err, "skipping instrumentation injection")
+		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", err.Error())
+		return withInjectionStatus(pod, skippedStatus(err.Error())), nil
+	}
+	manifestutils.PinImages([]*corev1.PodSpec{&modifiedPod.Spec}, pinned)
+	if usesLocalAgent(modifiedPod) {
+		modifiedPod = withLocalAgentEndpoints(modifiedPod)
+	}
+	modifiedPod = withMeshStartupOrdering(ns, modifiedPod)
+	status := InjectionStatusInjected
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		status += ", " + skippedStatus(strings.Join(skipped, ", "))
+	}
+
+	return withInjectionStatus(modifiedPod, status), nil
+}
+
+// withInjectionStatus records the outcome of the injection on the pod.
+func withInjectionStatus(pod corev1.Pod, status string) corev1.Pod {
+	pod.Annotations = maps.Clone(pod.Annotations)
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[podmutation.InjectionStatusAnnotation] = status
+	return pod
+}
 
-	return modifiedPod, nil
+func skippedStatus(reason string) string {
+	return InjectionStatusSkipped + ": " + reason
 }
 
 func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, instAnnotation string) (*v1alpha1.Instrumentation, error) {
@@ -414,7 +509,12 @@ func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context
 			pm.Logger.Error(err, "unable to retrieve cloudwatch agent config for instrumentation")
 		}
 
-		return getDefaultInstrumentation(config, additionalEnvs, isWindowsPod)
+		inst, err := getDefaultInstrumentation(config, additionalEnvs, isWindowsPod)
+		if err != nil {
+			return nil, err
+		}
+		withOperatorDefaults(inst, pm.config)
+		return inst, nil
 	case s > 1:
 		return nil, errMultipleInstancesPossible
 	default:
@@ -430,11 +530,24 @@ func GetAmazonCloudWatchAgentResource(ctx context.Context, c client.Client, name
 		Name:      name,
 	}, cr)
 
+	// the merged configuration is what the agent runs with, fall back to the plain config if it cannot be built
+	if resolved, err := collector.ResolveConfigSources(ctx, c, *cr); err == nil {
+		return resolved
+	}
 	return *cr
 }
 
 func isWindowsPod(pod corev1.Pod) bool {
-	return pod.Spec.NodeSelector["kubernetes.io/os"] == "windows"
+	return IsWindowsPodSpec(pod.Spec)
+}
+
+// IsWindowsPodSpec returns true if the pod is scheduled on Windows nodes, either through the OS field or the
+// kubernetes.io/os node selector.
+func IsWindowsPodSpec(spec corev1.PodSpec) bool {
+	if spec.OS != nil {
+		return spec.OS.Name == corev1.Windows
+	}
+	return spec.NodeSelector[corev1.LabelOSStable] == string(corev1.Windows)
 }
 
 func getJmxTargetSystems(ns corev1.Namespace, pod corev1.Pod) []string {
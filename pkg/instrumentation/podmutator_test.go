@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -19,7 +20,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
 )
@@ -105,7 +108,7 @@ func TestGetInstrumentationInstanceJMX(t *testing.T) {
 }
 
 func TestMutatePod(t *testing.T) {
-	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100))
+	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100), config.New())
 	require.NotNil(t, mutator)
 
 	true := true
@@ -5192,6 +5195,11 @@ func TestMutatePod(t *testing.T) {
 			pod, err := mutator.Mutate(context.Background(), test.ns, test.pod)
 			if test.err == "" {
 				require.NoError(t, err)
+				// the injection status is covered by TestMutatePodInjectionStatus
+				delete(pod.Annotations, podmutation.InjectionStatusAnnotation)
+				if len(pod.Annotations) == 0 && test.pod.Annotations == nil {
+					pod.Annotations = nil
+				}
 				assert.Equal(t, test.expected, pod)
 			} else {
 				assert.Contains(t, err.Error(), test.err)
@@ -5200,6 +5208,154 @@ func TestMutatePod(t *testing.T) {
 	}
 }
 
+func TestMutatePodInjectionStatus(t *testing.T) {
+	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100), config.New())
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "injection-status"}}
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:12345"},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), &ns))
+	defer func() {
+		_ = k8sClient.Delete(context.Background(), &ns)
+	}()
+	require.NoError(t, k8sClient.Create(context.Background(), &inst))
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "not requested",
+			annotations: nil,
+			expected:    "",
+		},
+		{
+			name:        "injected",
+			annotations: map[string]string{annotationInjectJava: "true"},
+			expected:    InjectionStatusInjected,
+		},
+		{
+			name:        "disabled",
+			annotations: map[string]string{annotationInjectGo: "true"},
+			expected:    "skipped: support for Go auto instrumentation is not enabled",
+		},
+		{
+			name:        "injected and disabled",
+			annotations: map[string]string{annotationInjectJava: "true", annotationInjectGo: "true"},
+			expected:    "injected, skipped: support for Go auto instrumentation is not enabled",
+		},
+	}
+	for _, test := range tests {
+		overrideFeatureFlags(t)
+		t.Run(test.name, func(t *testing.T) {
+			originalValGoInstr := featuregate.EnableGoAutoInstrumentationSupport.IsEnabled()
+			require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableGoAutoInstrumentationSupport.ID(), false))
+			t.Cleanup(func() {
+				require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableGoAutoInstrumentationSupport.ID(), originalValGoInstr))
+			})
+
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			}
+			mutated, err := mutator.Mutate(context.Background(), ns, pod)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, mutated.Annotations[podmutation.InjectionStatusAnnotation])
+			// the annotations of the pod are not modified in place
+			assert.NotContains(t, pod.Annotations, podmutation.InjectionStatusAnnotation)
+		})
+	}
+}
+
+func TestMutatePodImagePullSecrets(t *testing.T) {
+	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100), config.New(config.WithImagePullSecrets([]string{"mirror"})))
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "image-pull-secrets"}}
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:12345"},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), &ns))
+	defer func() {
+		_ = k8sClient.Delete(context.Background(), &ns)
+	}()
+	require.NoError(t, k8sClient.Create(context.Background(), &inst))
+	overrideFeatureFlags(t)
+
+	// the pods which are not instrumented are left untouched
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+	mutated, err := mutator.Mutate(context.Background(), ns, pod)
+	require.NoError(t, err)
+	assert.Empty(t, mutated.Spec.ImagePullSecrets)
+
+	pod = corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationInjectJava: "true"}},
+		Spec: corev1.PodSpec{
+			Containers:       []corev1.Container{{Name: "app"}},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "app-registry"}},
+		},
+	}
+	mutated, err = mutator.Mutate(context.Background(), ns, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "app-registry"}, {Name: "mirror"}}, mutated.Spec.ImagePullSecrets)
+}
+
+// unsignedImages fails the verification of the images of its list, and of the images not read in the namespace of
+// the pod.
+type unsignedImages []string
+
+func (u unsignedImages) Verify(_ context.Context, image string, pull config.ImagePull) (string, error) {
+	if slices.Contains(u, image) || pull.Namespace != "image-verification" {
+		return "", fmt.Errorf("the signature of image %s can't be verified: the image is not signed", image)
+	}
+	return image + "@sha256:signed", nil
+}
+
+func TestMutatePodImageVerification(t *testing.T) {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "image-verification"}}
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:12345"},
+			Java:     v1alpha1.Java{Image: "otel/java:1"},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), &ns))
+	defer func() {
+		_ = k8sClient.Delete(context.Background(), &ns)
+	}()
+	require.NoError(t, k8sClient.Create(context.Background(), &inst))
+	overrideFeatureFlags(t)
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationInjectJava: "true"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:1"}}},
+	}
+
+	// the images of the pod itself are not verified
+	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100), config.New(config.WithImageVerifier(unsignedImages{"app:1"})))
+	mutated, err := mutator.Mutate(context.Background(), ns, pod)
+	require.NoError(t, err)
+	assert.Equal(t, InjectionStatusInjected, mutated.Annotations[podmutation.InjectionStatusAnnotation])
+	require.Len(t, mutated.Spec.InitContainers, 1)
+	// the injected image is pinned to its verified digest, the image of the application is left as it is
+	assert.Equal(t, "otel/java:1@sha256:signed", mutated.Spec.InitContainers[0].Image)
+	assert.Equal(t, "app:1", mutated.Spec.Containers[0].Image)
+
+	mutator = NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100), config.New(config.WithImageVerifier(unsignedImages{"otel/java:1"})))
+	mutated, err = mutator.Mutate(context.Background(), ns, pod)
+	require.NoError(t, err)
+	assert.Equal(t, "skipped: the signature of image otel/java:1 can't be verified: the image is not signed", mutated.Annotations[podmutation.InjectionStatusAnnotation])
+	assert.Empty(t, mutated.Spec.InitContainers)
+	assert.Empty(t, mutated.Spec.Containers[0].Env)
+	assert.Empty(t, pod.Spec.Containers[0].Env)
+}
+
 func TestSingleInstrumentationEnabled(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -5364,3 +5520,56 @@ func TestInstrumentationLanguageContainersSet(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWindowsPodSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     corev1.PodSpec
+		expected bool
+	}{
+		{
+			name:     "no os",
+			spec:     corev1.PodSpec{},
+			expected: false,
+		},
+		{
+			name:     "windows node selector",
+			spec:     corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "windows"}},
+			expected: true,
+		},
+		{
+			name:     "windows os field",
+			spec:     corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Windows}},
+			expected: true,
+		},
+		{
+			name: "linux os field takes precedence",
+			spec: corev1.PodSpec{
+				OS:           &corev1.PodOS{Name: corev1.Linux},
+				NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+			},
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsWindowsPodSpec(test.spec))
+		})
+	}
+}
+
+func TestDropWindowsUnsupported(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{}
+	insts := languageInstrumentations{
+		Java:   instrumentationWithContainers{Instrumentation: inst},
+		DotNet: instrumentationWithContainers{Instrumentation: inst},
+		Python: instrumentationWithContainers{Instrumentation: inst},
+		NodeJS: instrumentationWithContainers{Instrumentation: inst},
+	}
+	dropped := insts.dropWindowsUnsupported()
+	assert.ElementsMatch(t, []string{annotationInjectPython, annotationInjectNodeJS}, dropped)
+	assert.NotNil(t, insts.Java.Instrumentation)
+	assert.NotNil(t, insts.DotNet.Instrumentation)
+	assert.Nil(t, insts.Python.Instrumentation)
+	assert.Nil(t, insts.NodeJS.Instrumentation)
+}
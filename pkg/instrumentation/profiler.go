@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envProfilerServerAddress = "PYROSCOPE_SERVER_ADDRESS"
+
+	javaProfilerMountPath   = "/otel-auto-instrumentation-profiler-java"
+	javaProfilerJVMArgument = " -javaagent:" + javaProfilerMountPath + "/profiler.jar"
+	pythonProfilerMountPath = "/otel-auto-instrumentation-profiler-python"
+
+	// the profiler image ships the sitecustomize.py starting the Python profiler in its bootstrap directory, which comes
+	// first in the PYTHONPATH since Python only runs the first sitecustomize module it finds. The module then runs the
+	// next sitecustomize module of the path, the one of the auto-instrumentation or of the application.
+	pythonProfilerBootstrapPath = pythonProfilerMountPath + "/bootstrap"
+)
+
+// profilerLayout describes how the profiler agent of a language is copied from the profiler image.
+type profilerLayout struct {
+	volumeName        string
+	initContainerName string
+	mountPath         string
+	command           []string
+}
+
+var (
+	javaProfilerLayout = profilerLayout{
+		volumeName:        volumeName + "-profiler-java",
+		initContainerName: initContainerName + "-profiler-java",
+		mountPath:         javaProfilerMountPath,
+		command:           []string{"cp", "/profiler.jar", javaProfilerMountPath + "/profiler.jar"},
+	}
+	pythonProfilerLayout = profilerLayout{
+		volumeName:        volumeName + "-profiler-python",
+		initContainerName: initContainerName + "-profiler-python",
+		mountPath:         pythonProfilerMountPath,
+		command:           []string{"cp", "-a", "/profiler/.", pythonProfilerMountPath},
+	}
+)
+
+// injectProfiler mounts the profiler agent in the container at index and configures its endpoint. The
+// language specific part of enabling the agent is left to the caller.
+func injectProfiler(profiler v1alpha1.Profiler, layout profilerLayout, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) corev1.Pod {
+	container := &pod.Spec.Containers[index]
+	for _, env := range profiler.Env {
+		if getIndexOfEnv(container.Env, env.Name) == -1 && getEnvValue(allEnvs, env.Name) == "" {
+			container.Env = append(container.Env, env)
+		}
+	}
+	if profiler.Endpoint != "" && getIndexOfEnv(container.Env, envProfilerServerAddress) == -1 && getEnvValue(allEnvs, envProfilerServerAddress) == "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: envProfilerServerAddress, Value: profiler.Endpoint})
+	}
+	setVolumeMount(container, corev1.VolumeMount{
+		Name:      layout.volumeName,
+		MountPath: layout.mountPath,
+	})
+
+	// We just inject Volumes and init containers for the first processed container.
+	if isInitContainerMissing(pod, layout.initContainerName) {
+		setVolume(&pod, corev1.Volume{
+			Name: layout.volumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: volumeSize(profiler.VolumeSizeLimit),
+				},
+			}})
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:      layout.initContainerName,
+			Image:     profiler.Image,
+			Command:   layout.command,
+			Resources: profiler.Resources,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      layout.volumeName,
+				MountPath: layout.mountPath,
+			}},
+		})
+	}
+	return pod
+}
+
+// shouldInjectProfiler returns true if the profiler is configured and can be injected in the pod. The profiler is
+// not supported on Windows.
+func shouldInjectProfiler(profiler *v1alpha1.Profiler, pod corev1.Pod) bool {
+	return profiler != nil && profiler.Image != "" && !isWindowsPod(pod)
+}
+
+// pythonPathWithProfiler puts the bootstrap directory of the profiler before the sitecustomize modules of the
+// auto-instrumentation and of the application, which it chains to, and the profiler packages last.
+func pythonPathWithProfiler(pythonPath string) string {
+	if containsPath(pythonPath, pythonProfilerBootstrapPath) {
+		return pythonPath
+	}
+	return fmt.Sprintf("%s:%s:%s", pythonProfilerBootstrapPath, pythonPath, pythonProfilerMountPath)
+}
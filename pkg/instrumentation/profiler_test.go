@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestInjectProfiler(t *testing.T) {
+	profiler := &v1alpha1.Profiler{
+		Image:    "profiler:1.0",
+		Endpoint: "http://pyroscope.monitoring:4040",
+		Env:      []corev1.EnvVar{{Name: "PYROSCOPE_APPLICATION_NAME", Value: "app"}},
+	}
+	newPod := func() corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+	}
+
+	t.Run("java", func(t *testing.T) {
+		pod, err := injectJavaagent(v1alpha1.Java{Image: "java:1.0", Profiler: profiler}, newPod(), 0, nil)
+		require.NoError(t, err)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, javaJVMArgument+javaProfilerJVMArgument, getEnvValue(container.Env, envJavaToolsOptions))
+		assert.Equal(t, "http://pyroscope.monitoring:4040", getEnvValue(container.Env, envProfilerServerAddress))
+		assert.Equal(t, "app", getEnvValue(container.Env, "PYROSCOPE_APPLICATION_NAME"))
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: javaProfilerLayout.volumeName, MountPath: javaProfilerMountPath})
+		require.Len(t, pod.Spec.InitContainers, 2)
+		assert.Equal(t, javaProfilerLayout.initContainerName, pod.Spec.InitContainers[0].Name)
+		assert.Equal(t, "profiler:1.0", pod.Spec.InitContainers[0].Image)
+		assert.Len(t, pod.Spec.Volumes, 2)
+	})
+	t.Run("python", func(t *testing.T) {
+		pod, err := injectPythonSDK(v1alpha1.Python{Image: "python:1.0", Profiler: profiler}, newPod(), 0, nil)
+		require.NoError(t, err)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, pythonProfilerBootstrapPath+":"+pythonPathPrefix+":"+pythonPathSuffix+":"+pythonProfilerMountPath, getEnvValue(container.Env, envPythonPath))
+		assert.Equal(t, "http://pyroscope.monitoring:4040", getEnvValue(container.Env, envProfilerServerAddress))
+		require.Len(t, pod.Spec.InitContainers, 2)
+		assert.Equal(t, pythonProfilerLayout.command, pod.Spec.InitContainers[0].Command)
+
+		// the sitecustomize starting the profiler is copied from the profiler image, without a shell
+		assert.Equal(t, []string{"cp", "-a", "/profiler/.", pythonProfilerMountPath}, pod.Spec.InitContainers[0].Command)
+		assert.Empty(t, pod.Spec.InitContainers[0].Env)
+
+		// mutating the pod again does not add the profiler twice
+		pod, err = injectPythonSDK(v1alpha1.Python{Image: "python:1.0", Profiler: profiler}, pod, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, pythonProfilerBootstrapPath+":"+pythonPathPrefix+":"+pythonPathSuffix+":"+pythonProfilerMountPath, getEnvValue(pod.Spec.Containers[0].Env, envPythonPath))
+	})
+	t.Run("user defined server address is kept", func(t *testing.T) {
+		pod := newPod()
+		userEnvs := []corev1.EnvVar{{Name: envProfilerServerAddress, Value: "http://other:4040"}}
+		pod.Spec.Containers[0].Env = userEnvs
+		pod, err := injectJavaagent(v1alpha1.Java{Image: "java:1.0", Profiler: profiler}, pod, 0, userEnvs)
+		require.NoError(t, err)
+		assert.Equal(t, "http://other:4040", getEnvValue(pod.Spec.Containers[0].Env, envProfilerServerAddress))
+	})
+	t.Run("windows", func(t *testing.T) {
+		pod := newPod()
+		pod.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+		pod, err := injectJavaagent(v1alpha1.Java{Image: "java:1.0", Profiler: profiler}, pod, 0, nil)
+		require.NoError(t, err)
+		assert.Len(t, pod.Spec.InitContainers, 1)
+		assert.Empty(t, getEnvValue(pod.Spec.Containers[0].Env, envProfilerServerAddress))
+	})
+	t.Run("no image", func(t *testing.T) {
+		assert.False(t, shouldInjectProfiler(&v1alpha1.Profiler{}, newPod()))
+		assert.False(t, shouldInjectProfiler(nil, newPod()))
+	})
+}
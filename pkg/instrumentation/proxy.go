@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envHTTPSProxy = "HTTPS_PROXY"
+	envHTTPProxy  = "HTTP_PROXY"
+	envNoProxy    = "NO_PROXY"
+)
+
+// cloudwatchAgentNoProxyHosts are always excluded from the proxy, so telemetry is sent to the CloudWatch agent
+// directly.
+var cloudwatchAgentNoProxyHosts = []string{
+	cloudwatchAgentStandardEndpoint,
+	cloudwatchAgentStandardEndpoint + ".svc",
+	cloudwatchAgentStandardEndpoint + ".svc.cluster.local",
+	cloudwatchAgentWindowsEndpoint,
+}
+
+// injectProxyConfig sets the proxy env vars of the Instrumentation on the container. Proxies set on the container
+// are kept, but the CloudWatch agent is always added to NO_PROXY.
+func injectProxyConfig(proxy *v1alpha1.Proxy, container *corev1.Container) {
+	if proxy == nil || (proxy.HTTPSProxy == "" && proxy.HTTPProxy == "") {
+		return
+	}
+	if proxy.HTTPSProxy != "" && getIndexOfEnv(container.Env, envHTTPSProxy) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{Name: envHTTPSProxy, Value: proxy.HTTPSProxy})
+	}
+	if proxy.HTTPProxy != "" && getIndexOfEnv(container.Env, envHTTPProxy) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{Name: envHTTPProxy, Value: proxy.HTTPProxy})
+	}
+
+	noProxy := append(append([]string{}, proxy.NoProxy...), cloudwatchAgentNoProxyHosts...)
+	idx := getIndexOfEnv(container.Env, envNoProxy)
+	switch {
+	case idx == -1:
+		container.Env = append(container.Env, corev1.EnvVar{Name: envNoProxy, Value: appendToList("", noProxy)})
+	case container.Env[idx].ValueFrom == nil:
+		container.Env[idx].Value = appendToList(strings.TrimSuffix(container.Env[idx].Value, ","), noProxy)
+	}
+}
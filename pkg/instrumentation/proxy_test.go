@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestInjectProxyConfig(t *testing.T) {
+	agentHosts := "cloudwatch-agent.amazon-cloudwatch,cloudwatch-agent.amazon-cloudwatch.svc," +
+		"cloudwatch-agent.amazon-cloudwatch.svc.cluster.local,cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local"
+
+	for _, tt := range []struct {
+		name     string
+		proxy    *v1alpha1.Proxy
+		env      []corev1.EnvVar
+		expected []corev1.EnvVar
+	}{
+		{
+			name: "no proxy",
+		},
+		{
+			name:  "no proxy url",
+			proxy: &v1alpha1.Proxy{NoProxy: []string{"internal"}},
+		},
+		{
+			name:  "https proxy",
+			proxy: &v1alpha1.Proxy{HTTPSProxy: "http://proxy:3128", NoProxy: []string{"169.254.169.254"}},
+			expected: []corev1.EnvVar{
+				{Name: envHTTPSProxy, Value: "http://proxy:3128"},
+				{Name: envNoProxy, Value: "169.254.169.254," + agentHosts},
+			},
+		},
+		{
+			name:  "container proxy is kept",
+			proxy: &v1alpha1.Proxy{HTTPSProxy: "http://proxy:3128", HTTPProxy: "http://proxy:3128"},
+			env: []corev1.EnvVar{
+				{Name: envHTTPSProxy, Value: "http://other:8080"},
+				{Name: envNoProxy, Value: "localhost,cloudwatch-agent.amazon-cloudwatch,"},
+			},
+			expected: []corev1.EnvVar{
+				{Name: envHTTPSProxy, Value: "http://other:8080"},
+				{Name: envNoProxy, Value: "localhost," + agentHosts},
+				{Name: envHTTPProxy, Value: "http://proxy:3128"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			container := &corev1.Container{Env: tt.env}
+			injectProxyConfig(tt.proxy, container)
+			assert.Equal(t, tt.expected, container.Env)
+		})
+	}
+}
@@ -44,16 +44,24 @@ func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int, allE
 		}
 	}
 
+	injectPythonKafkaConfig(pythonSpec.Kafka, container, allEnvs)
+
 	idx := getIndexOfEnv(container.Env, envPythonPath)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
 			Name:  envPythonPath,
 			Value: fmt.Sprintf("%s:%s", pythonPathPrefix, pythonPathSuffix),
 		})
-	} else if idx > -1 {
+	} else if idx > -1 && !containsPath(container.Env[idx].Value, pythonPathPrefix) {
 		container.Env[idx].Value = fmt.Sprintf("%s:%s:%s", pythonPathPrefix, container.Env[idx].Value, pythonPathSuffix)
 	}
 
+	if shouldInjectProfiler(pythonSpec.Profiler, pod) {
+		idx = getIndexOfEnv(container.Env, envPythonPath)
+		container.Env[idx].Value = pythonPathWithProfiler(container.Env[idx].Value)
+		pod = injectProfiler(*pythonSpec.Profiler, pythonProfilerLayout, pod, index, allEnvs)
+	}
+
 	// Set OTEL_TRACES_EXPORTER to otlp exporter if not set by user and validation allows
 	if shouldInjectEnvVar(allEnvs, envOtelTracesExporter, "otlp") {
 		container.Env = append(container.Env, corev1.EnvVar{
@@ -86,14 +94,14 @@ func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int, allE
 		})
 	}
 
-	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+	setVolumeMount(container, corev1.VolumeMount{
 		Name:      pythonVolumeName,
 		MountPath: pythonInstrMountPath,
 	})
 
 	// We just inject Volumes and init containers for the first processed container.
 	if isInitContainerMissing(pod, pythonInitContainerName) {
-		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		setVolume(&pod, corev1.Volume{
 			Name: pythonVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
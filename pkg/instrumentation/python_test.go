@@ -356,9 +356,24 @@ func TestInjectPythonSDK(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectPythonSDK(test.Python, test.pod, 0)
+			pod, err := injectPythonSDK(test.Python, test.pod, 0, test.pod.Spec.Containers[0].Env)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
 	}
 }
+
+func TestInjectPythonSDKReinvocation(t *testing.T) {
+	pythonSpec := v1alpha1.Python{Image: "foo/bar:1"}
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Env: []corev1.EnvVar{{Name: "PYTHONPATH", Value: "/app"}},
+	}}}}
+
+	pod, err := injectPythonSDK(pythonSpec, pod, 0, nil)
+	assert.NoError(t, err)
+	injected := pod.DeepCopy()
+	pod.Spec.InitContainers = nil
+	pod, err = injectPythonSDK(pythonSpec, pod, 0, injected.Spec.Containers[0].Env)
+	assert.NoError(t, err)
+	assert.Equal(t, injected.Spec, pod.Spec)
+}
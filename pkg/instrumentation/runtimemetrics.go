@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	// annotationRuntimeMetrics enables or disables the runtime metrics (e.g. JVM or CLR metrics) emitted by the
+	// instrumented containers of a workload. Traces are not affected. Possible values are "true" or "false".
+	annotationRuntimeMetrics = "cloudwatch.aws.amazon.com/runtime-metrics"
+
+	envAppSignalsRuntimeEnabled = "OTEL_AWS_APPLICATION_SIGNALS_RUNTIME_ENABLED"
+)
+
+// runtimeMetricsEnabled returns whether runtime metrics should be emitted, or nil if neither the pod, its namespace
+// nor the Instrumentation override the instrumentation defaults. Annotations take precedence over the Instrumentation.
+func runtimeMetricsEnabled(otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod) *bool {
+	if value := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationRuntimeMetrics); value != "" {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			return &enabled
+		}
+	}
+	if otelinst.Spec.RuntimeMetrics != nil {
+		return otelinst.Spec.RuntimeMetrics.Enabled
+	}
+	return nil
+}
+
+// injectRuntimeMetricsConfig sets the runtime metrics env var on the container at index according to
+// runtimeMetricsEnabled. A value set by the user on the original container is never overridden.
+func (i *sdkInjector) injectRuntimeMetricsConfig(otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) corev1.Pod {
+	enabled := runtimeMetricsEnabled(otelinst, ns, pod)
+	if enabled == nil {
+		return pod
+	}
+	setEnvIfNotUserDefined(&pod.Spec.Containers[index], allEnvs, envAppSignalsRuntimeEnabled, strconv.FormatBool(*enabled))
+	return pod
+}
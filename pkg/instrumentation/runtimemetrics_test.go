@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestRuntimeMetricsEnabled(t *testing.T) {
+	enabled, disabled := true, false
+	for _, tt := range []struct {
+		name           string
+		runtimeMetrics *v1alpha1.RuntimeMetrics
+		nsAnnotation   string
+		podAnnotation  string
+		expected       *bool
+	}{
+		{
+			name:     "not configured",
+			expected: nil,
+		},
+		{
+			name:           "instrumentation",
+			runtimeMetrics: &v1alpha1.RuntimeMetrics{Enabled: &disabled},
+			expected:       &disabled,
+		},
+		{
+			name:           "pod annotation overrides instrumentation",
+			runtimeMetrics: &v1alpha1.RuntimeMetrics{Enabled: &enabled},
+			podAnnotation:  "false",
+			expected:       &disabled,
+		},
+		{
+			name:         "namespace annotation",
+			nsAnnotation: "false",
+			expected:     &disabled,
+		},
+		{
+			name:          "pod annotation overrides namespace annotation",
+			nsAnnotation:  "false",
+			podAnnotation: "true",
+			expected:      &enabled,
+		},
+		{
+			name:           "invalid annotation is ignored",
+			runtimeMetrics: &v1alpha1.RuntimeMetrics{Enabled: &disabled},
+			podAnnotation:  "nope",
+			expected:       &disabled,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			otelinst := v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{RuntimeMetrics: tt.runtimeMetrics}}
+			ns := corev1.Namespace{}
+			if tt.nsAnnotation != "" {
+				ns.Annotations = map[string]string{annotationRuntimeMetrics: tt.nsAnnotation}
+			}
+			pod := corev1.Pod{}
+			if tt.podAnnotation != "" {
+				pod.Annotations = map[string]string{annotationRuntimeMetrics: tt.podAnnotation}
+			}
+			assert.Equal(t, tt.expected, runtimeMetricsEnabled(otelinst, ns, pod))
+		})
+	}
+}
+
+func TestInjectRuntimeMetricsConfig(t *testing.T) {
+	injector := &sdkInjector{logger: logr.Discard()}
+	pod := func(env ...corev1.EnvVar) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationRuntimeMetrics: "false"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Env: env}}},
+		}
+	}
+
+	t.Run("overrides instrumentation default", func(t *testing.T) {
+		result := injector.injectRuntimeMetricsConfig(v1alpha1.Instrumentation{}, corev1.Namespace{},
+			pod(corev1.EnvVar{Name: envAppSignalsRuntimeEnabled, Value: "true"}), 0, nil)
+		assert.Equal(t, []corev1.EnvVar{{Name: envAppSignalsRuntimeEnabled, Value: "false"}}, result.Spec.Containers[0].Env)
+	})
+	t.Run("adds missing env var", func(t *testing.T) {
+		result := injector.injectRuntimeMetricsConfig(v1alpha1.Instrumentation{}, corev1.Namespace{}, pod(), 0, nil)
+		assert.Equal(t, []corev1.EnvVar{{Name: envAppSignalsRuntimeEnabled, Value: "false"}}, result.Spec.Containers[0].Env)
+	})
+	t.Run("keeps user defined value", func(t *testing.T) {
+		userEnv := corev1.EnvVar{Name: envAppSignalsRuntimeEnabled, Value: "true"}
+		result := injector.injectRuntimeMetricsConfig(v1alpha1.Instrumentation{}, corev1.Namespace{}, pod(userEnv), 0,
+			[]corev1.EnvVar{userEnv})
+		assert.Equal(t, []corev1.EnvVar{userEnv}, result.Spec.Containers[0].Env)
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
@@ -46,6 +47,7 @@ var vendorCollectorImageMatcher = []string{
 type sdkInjector struct {
 	client client.Client
 	logger logr.Logger
+	config config.Config
 }
 
 func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations, ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
@@ -56,7 +58,8 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	// Note: There is a potential edge case where injection might be skipped if CloudWatch Agent
 	// is already present as a sidecar. This is considered low risk since running CloudWatch Agent
 	// as a sidecar is not a officially supported configuration pattern within the operator.
-	if otcContainerExistsIn(pod) {
+	// The agent sidecar of the Fargate pods receives their telemetry, so they are still injected.
+	if otcContainerExistsIn(pod) && !usesLocalAgent(pod) {
 		i.logger.V(3).Info("An otel collector container already exists, skipping injection")
 		return pod
 	}
@@ -94,12 +97,17 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
 				continue
 			}
-			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index, envs)
+			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index, dualExportEnvs(otelinst, ns, pod, envs))
 			if err != nil {
 				i.logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.injectDualExportConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectRuntimeMetricsConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectLogsConfig(otelinst, pod, index, envs, TypeJava)
+				pod = i.injectResourceDetectionConfig(pod, index, envs, TypeJava)
+				pod = i.injectExportRetryConfig(ns, pod, index, envs)
 				//disable setting security context in init container due to issue with runAsNonRoot conflict
 				//https://github.com/open-telemetry/opentelemetry-operator/issues/2272
 				//pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, javaInitContainerName)
@@ -121,12 +129,15 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
 				continue
 			}
-			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index, envs)
+			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index, dualExportEnvs(otelinst, ns, pod, envs))
 			if err != nil {
 				i.logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.injectDualExportConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectLogsConfig(otelinst, pod, index, envs, TypeNodeJS)
+				pod = i.injectResourceDetectionConfig(pod, index, envs, TypeNodeJS)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, nodejsInitContainerName)
 			}
 		}
@@ -146,12 +157,16 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
 				continue
 			}
-			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index, envs)
+			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index, dualExportEnvs(otelinst, ns, pod, envs))
 			if err != nil {
 				i.logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.injectDualExportConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectRuntimeMetricsConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectLogsConfig(otelinst, pod, index, envs, TypePython)
+				pod = i.injectResourceDetectionConfig(pod, index, envs, TypePython)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, pythonInitContainerName)
 			}
 		}
@@ -171,12 +186,15 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
 				continue
 			}
-			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime], envs)
+			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime], dualExportEnvs(otelinst, ns, pod, envs))
 			if err != nil {
 				i.logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.injectDualExportConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectRuntimeMetricsConfig(otelinst, ns, pod, index, envs)
+				pod = i.injectLogsConfig(otelinst, pod, index, envs, TypeDotNet)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, dotnetInitContainerName)
 			}
 		}
@@ -322,6 +340,7 @@ func (i *sdkInjector) injectCommonEnvVar(otelinst v1alpha1.Instrumentation, pod
 			container.Env = append(container.Env, env)
 		}
 	}
+	injectProxyConfig(otelinst.Spec.Proxy, container)
 	return pod
 }
 
@@ -458,6 +477,14 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 }
 
 func chooseServiceName(pod corev1.Pod, resources map[string]string, index int) string {
+	// Knative generates a new Deployment for every Revision, so the Knative Service is the only stable name.
+	if name := pod.Labels[knativeServiceLabel]; name != "" {
+		return name
+	}
+	// Spark driver and executor pods are not owned by a workload, so use the application name instead of the pod name.
+	if name := pod.Labels[sparkAppNameLabel]; name != "" && sparkRole(pod) != "" {
+		return name
+	}
 	if name := resources[string(semconv.K8SDeploymentNameKey)]; name != "" {
 		return name
 	}
@@ -476,6 +503,9 @@ func chooseServiceName(pod corev1.Pod, resources map[string]string, index int) s
 	if name := resources[string(semconv.K8SJobNameKey)]; name != "" {
 		return name
 	}
+	if name := resources[ownerNameAttribute]; name != "" {
+		return name
+	}
 	if name := resources[string(semconv.K8SPodNameKey)]; name != "" {
 		return name
 	}
@@ -527,6 +557,23 @@ func (i *sdkInjector) createResourceMap(ctx context.Context, otelinst v1alpha1.I
 			res[k] = v
 		}
 	}
+	for k, v := range sparkResourceAttributes(pod) {
+		if _, ok := res[k]; !ok && !existingRes[k] {
+			res[k] = v
+		}
+	}
+	for _, attributes := range []map[string]string{i.clusterResourceAttributes(), i.awsResourceAttributes(pod)} {
+		for k, v := range attributes {
+			if _, ok := res[k]; !ok && !existingRes[k] {
+				res[k] = v
+			}
+		}
+	}
+	if arn := applicationARN(otelinst, ns); arn != "" {
+		if _, ok := res[awsApplicationAttribute]; !ok && !existingRes[awsApplicationAttribute] {
+			res[awsApplicationAttribute] = arn
+		}
+	}
 	k8sResources := map[attribute.Key]string{}
 	k8sResources[semconv.K8SNamespaceNameKey] = ns.Name
 	k8sResources[semconv.K8SContainerNameKey] = pod.Spec.Containers[index].Name
@@ -536,7 +583,12 @@ func (i *sdkInjector) createResourceMap(ctx context.Context, otelinst v1alpha1.I
 	k8sResources[semconv.K8SPodUIDKey] = string(pod.UID)
 	k8sResources[semconv.K8SNodeNameKey] = pod.Spec.NodeName
 	k8sResources[semconv.ServiceInstanceIDKey] = createServiceInstanceId(ns.Name, pod.Name, pod.Spec.Containers[index].Name)
-	i.addParentResourceLabels(ctx, otelinst.Spec.Resource.AddK8sUIDAttributes, ns, pod.ObjectMeta, k8sResources)
+	// StatefulSet pods keep their identity across restarts, so the instance is derived from the ordinal
+	if statefulSet, ordinal, ok := statefulSetOrdinal(pod); ok {
+		k8sResources[semconv.ServiceInstanceIDKey] = createServiceInstanceId(ns.Name, statefulSet+"-"+ordinal, pod.Spec.Containers[index].Name)
+		k8sResources[statefulSetPodOrdinalAttribute] = ordinal
+	}
+	i.addParentResourceLabels(ctx, otelinst.Spec.Resource, ns, pod.ObjectMeta, k8sResources)
 	for k, v := range k8sResources {
 		if !existingRes[string(k)] && v != "" {
 			res[string(k)] = v
@@ -545,7 +597,8 @@ func (i *sdkInjector) createResourceMap(ctx context.Context, otelinst v1alpha1.I
 	return res, existingRes
 }
 
-func (i *sdkInjector) addParentResourceLabels(ctx context.Context, uid bool, ns corev1.Namespace, objectMeta metav1.ObjectMeta, resources map[attribute.Key]string) {
+func (i *sdkInjector) addParentResourceLabels(ctx context.Context, resource v1alpha1.Resource, ns corev1.Namespace, objectMeta metav1.ObjectMeta, resources map[attribute.Key]string) {
+	uid := resource.AddK8sUIDAttributes
 	for _, owner := range objectMeta.OwnerReferences {
 		switch strings.ToLower(owner.Kind) {
 		case "replicaset":
@@ -571,7 +624,7 @@ func (i *sdkInjector) addParentResourceLabels(ctx context.Context, uid bool, ns
 			if err != nil {
 				i.logger.Error(err, "failed to get replicaset", "replicaset", nsn.Name, "namespace", nsn.Namespace)
 			}
-			i.addParentResourceLabels(ctx, uid, ns, rs.ObjectMeta, resources)
+			i.addParentResourceLabels(ctx, resource, ns, rs.ObjectMeta, resources)
 		case "deployment":
 			resources[semconv.K8SDeploymentNameKey] = owner.Name
 			if uid {
@@ -597,6 +650,11 @@ func (i *sdkInjector) addParentResourceLabels(ctx context.Context, uid bool, ns
 			if uid {
 				resources[semconv.K8SCronJobUIDKey] = string(owner.UID)
 			}
+		default:
+			// pods created by third-party operators, e.g. Strimzi or Flink
+			if owner.Controller != nil && *owner.Controller {
+				i.addOwnerResourceLabels(ctx, resource.OwnerStopKinds, ns, owner, resources, 0)
+			}
 		}
 	}
 }
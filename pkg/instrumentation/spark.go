@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// labels set by Spark on the driver and executor pods it creates.
+	sparkRoleLabel    = "spark-role"
+	sparkAppIDLabel   = "spark-app-selector"
+	sparkAppNameLabel = "spark-app-name"
+
+	sparkRoleDriver   = "driver"
+	sparkRoleExecutor = "executor"
+
+	// envSparkSubmitOpts is added by the Spark launcher to the JVM that runs the driver.
+	envSparkSubmitOpts = "SPARK_SUBMIT_OPTS"
+	// envSparkJavaOptPrefix is collected, ordered by its numeric suffix, into the executor JVM command by the
+	// Spark image entrypoint.
+	envSparkJavaOptPrefix = "SPARK_JAVA_OPT_"
+
+	attributeSparkRole  = "spark.role"
+	attributeSparkAppID = "spark.app.id"
+)
+
+// sparkRole returns the Spark role (driver or executor) of the pod, or an empty string if it is not a Spark pod.
+func sparkRole(pod corev1.Pod) string {
+	switch role := pod.Labels[sparkRoleLabel]; role {
+	case sparkRoleDriver, sparkRoleExecutor:
+		return role
+	default:
+		return ""
+	}
+}
+
+// javaAgentEnvVarName returns the environment variable used to add the JVM argument to the JVM options of the
+// container. JAVA_TOOL_OPTIONS is picked up by every JVM started in the container, which for Spark includes the
+// short-lived launcher and spark-submit processes, so Spark pods use the mechanisms Spark itself provides instead.
+func javaAgentEnvVarName(pod corev1.Pod, container corev1.Container, argument string) string {
+	switch sparkRole(pod) {
+	case sparkRoleDriver:
+		return envSparkSubmitOpts
+	case sparkRoleExecutor:
+		return sparkJavaOptName(container.Env, argument)
+	default:
+		return envJavaToolsOptions
+	}
+}
+
+// sparkJavaOptName returns the SPARK_JAVA_OPT_<n> option of the executor which already carries the JVM argument, so
+// that a pod mutated again is given the argument once, or else a name that sorts after the options Spark already set on
+// the executor, so that user supplied spark.executor.extraJavaOptions keep their order.
+func sparkJavaOptName(envs []corev1.EnvVar, argument string) string {
+	next := 0
+	for _, env := range envs {
+		suffix, ok := strings.CutPrefix(env.Name, envSparkJavaOptPrefix)
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(env.Value) == strings.TrimSpace(argument) {
+			return env.Name
+		}
+		if n, err := strconv.Atoi(suffix); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return fmt.Sprintf("%s%d", envSparkJavaOptPrefix, next)
+}
+
+// addJavaAgentArgument adds the JVM argument to the JVM options of the container. Each SPARK_JAVA_OPT_<n> is passed
+// as exactly one argument to the executor JVM, so the argument is given its own option, without the leading space.
+func addJavaAgentArgument(pod corev1.Pod, container *corev1.Container, argument string) {
+	name := javaAgentEnvVarName(pod, *container, argument)
+	if sparkRole(pod) == sparkRoleExecutor {
+		argument = strings.TrimSpace(argument)
+	}
+	idx := getIndexOfEnv(container.Env, name)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  name,
+			Value: argument,
+		})
+	} else {
+		container.Env[idx].Value = appendArgument(container.Env[idx].Value, argument)
+	}
+}
+
+// sparkResourceAttributes returns the resource attributes identifying the Spark application and role of the pod.
+func sparkResourceAttributes(pod corev1.Pod) map[string]string {
+	role := sparkRole(pod)
+	if role == "" {
+		return nil
+	}
+	attributes := map[string]string{attributeSparkRole: role}
+	if appID := pod.Labels[sparkAppIDLabel]; appID != "" {
+		attributes[attributeSparkAppID] = appID
+	}
+	return attributes
+}
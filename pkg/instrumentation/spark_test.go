@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestJavaAgentEnvVarName(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		labels   map[string]string
+		env      []corev1.EnvVar
+		expected string
+	}{
+		{
+			name:     "not a spark pod",
+			expected: envJavaToolsOptions,
+		},
+		{
+			name:     "unknown spark role",
+			labels:   map[string]string{sparkRoleLabel: "shuffle"},
+			expected: envJavaToolsOptions,
+		},
+		{
+			name:     "spark driver",
+			labels:   map[string]string{sparkRoleLabel: sparkRoleDriver},
+			expected: envSparkSubmitOpts,
+		},
+		{
+			name:     "spark executor without options",
+			labels:   map[string]string{sparkRoleLabel: sparkRoleExecutor},
+			expected: "SPARK_JAVA_OPT_0",
+		},
+		{
+			name:   "spark executor with options",
+			labels: map[string]string{sparkRoleLabel: sparkRoleExecutor},
+			env: []corev1.EnvVar{
+				{Name: "SPARK_JAVA_OPT_0", Value: "-Dfoo=bar"},
+				{Name: "SPARK_JAVA_OPT_3", Value: "-Dbar=baz"},
+				{Name: "SPARK_JAVA_OPT_X", Value: "-Dignored"},
+			},
+			expected: "SPARK_JAVA_OPT_4",
+		},
+		{
+			name:   "spark executor with the agent",
+			labels: map[string]string{sparkRoleLabel: sparkRoleExecutor},
+			env: []corev1.EnvVar{
+				{Name: "SPARK_JAVA_OPT_0", Value: "-Dfoo=bar"},
+				{Name: "SPARK_JAVA_OPT_1", Value: strings.TrimSpace(javaJVMArgument)},
+				{Name: "SPARK_JAVA_OPT_2", Value: "-Dbar=baz"},
+			},
+			expected: "SPARK_JAVA_OPT_1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			assert.Equal(t, tt.expected, javaAgentEnvVarName(pod, corev1.Container{Env: tt.env}, javaJVMArgument))
+		})
+	}
+}
+
+func TestInjectJavaagentSparkExecutorTwice(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{sparkRoleLabel: sparkRoleExecutor}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "spark-kubernetes-executor",
+			Env:  []corev1.EnvVar{{Name: "SPARK_JAVA_OPT_0", Value: "-Dfoo=bar"}},
+		}}},
+	}
+	java := v1alpha1.Java{Image: "foo/bar:1"}
+
+	once, err := injectJavaagent(java, pod, 0, pod.Spec.Containers[0].Env)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "SPARK_JAVA_OPT_0", Value: "-Dfoo=bar"},
+		{Name: "SPARK_JAVA_OPT_1", Value: "-javaagent:/otel-auto-instrumentation-java/javaagent.jar"},
+	}, once.Spec.Containers[0].Env)
+
+	// a pod reinvoked through the webhook is mutated again
+	twice, err := injectJavaagent(java, *once.DeepCopy(), 0, once.Spec.Containers[0].Env)
+	require.NoError(t, err)
+	assert.Equal(t, once, twice)
+}
+
+func TestInjectJavaagentSparkExecutorProfiler(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{sparkRoleLabel: sparkRoleExecutor}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "spark-kubernetes-executor",
+			Env:  []corev1.EnvVar{{Name: "SPARK_JAVA_OPT_0", Value: "-Dfoo=bar"}},
+		}}},
+	}
+	java := v1alpha1.Java{Image: "foo/bar:1", Profiler: &v1alpha1.Profiler{Image: "profiler:1.0"}}
+
+	once, err := injectJavaagent(java, pod, 0, pod.Spec.Containers[0].Env)
+	require.NoError(t, err)
+	// each agent is its own JVM argument
+	assert.Equal(t, "-javaagent:/otel-auto-instrumentation-java/javaagent.jar", getEnvValue(once.Spec.Containers[0].Env, "SPARK_JAVA_OPT_1"))
+	assert.Equal(t, "-javaagent:"+javaProfilerMountPath+"/profiler.jar", getEnvValue(once.Spec.Containers[0].Env, "SPARK_JAVA_OPT_2"))
+
+	twice, err := injectJavaagent(java, *once.DeepCopy(), 0, once.Spec.Containers[0].Env)
+	require.NoError(t, err)
+	assert.Equal(t, once.Spec.Containers[0].Env, twice.Spec.Containers[0].Env)
+}
+
+func TestSparkResourceAttributes(t *testing.T) {
+	assert.Nil(t, sparkResourceAttributes(corev1.Pod{}))
+
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		sparkRoleLabel:    sparkRoleExecutor,
+		sparkAppIDLabel:   "spark-1234",
+		sparkAppNameLabel: "etl",
+	}}}
+	assert.Equal(t, map[string]string{
+		attributeSparkRole:  sparkRoleExecutor,
+		attributeSparkAppID: "spark-1234",
+	}, sparkResourceAttributes(pod))
+
+	pod.Spec.Containers = []corev1.Container{{Name: "spark-kubernetes-executor"}}
+	assert.Equal(t, "etl", chooseServiceName(pod, map[string]string{}, 0))
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// labelStatefulSetPodIndex is set by the StatefulSet controller on Kubernetes 1.28+.
+	labelStatefulSetPodIndex = "apps.kubernetes.io/pod-index"
+
+	statefulSetPodOrdinalAttribute = "k8s.statefulset.pod.ordinal"
+)
+
+// statefulSetOrdinal returns the name of the StatefulSet owning the pod and the ordinal of the pod in it. The
+// ordinal is read from the pod index label and falls back to the suffix of the pod name.
+func statefulSetOrdinal(pod corev1.Pod) (string, string, bool) {
+	for _, owner := range pod.OwnerReferences {
+		if !strings.EqualFold(owner.Kind, "StatefulSet") {
+			continue
+		}
+		ordinal, ok := pod.Labels[labelStatefulSetPodIndex]
+		if !ok {
+			ordinal, ok = strings.CutPrefix(pod.Name, owner.Name+"-")
+		}
+		if !ok {
+			return "", "", false
+		}
+		if _, err := strconv.ParseUint(ordinal, 10, 32); err != nil {
+			return "", "", false
+		}
+		return owner.Name, ordinal, true
+	}
+	return "", "", false
+}
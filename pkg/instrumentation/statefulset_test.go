@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestStatefulSetOrdinal(t *testing.T) {
+	owner := []metav1.OwnerReference{{Kind: "StatefulSet", Name: "kafka"}}
+	for _, tt := range []struct {
+		name            string
+		meta            metav1.ObjectMeta
+		wantStatefulSet string
+		wantOrdinal     string
+		wantOk          bool
+	}{
+		{name: "pod name", meta: metav1.ObjectMeta{Name: "kafka-2", OwnerReferences: owner}, wantStatefulSet: "kafka", wantOrdinal: "2", wantOk: true},
+		{name: "pod index label", meta: metav1.ObjectMeta{Labels: map[string]string{labelStatefulSetPodIndex: "11"}, OwnerReferences: owner}, wantStatefulSet: "kafka", wantOrdinal: "11", wantOk: true},
+		{name: "not an ordinal", meta: metav1.ObjectMeta{Name: "kafka-abc", OwnerReferences: owner}},
+		{name: "unrelated name", meta: metav1.ObjectMeta{Name: "zookeeper-0", OwnerReferences: owner}},
+		{name: "not a statefulset", meta: metav1.ObjectMeta{Name: "kafka-0", OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "kafka"}}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			statefulSet, ordinal, ok := statefulSetOrdinal(corev1.Pod{ObjectMeta: tt.meta})
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantStatefulSet, statefulSet)
+			assert.Equal(t, tt.wantOrdinal, ordinal)
+		})
+	}
+}
+
+func TestCreateResourceMapStatefulSet(t *testing.T) {
+	injector := &sdkInjector{logger: logr.Discard()}
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "streaming"}}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:          map[string]string{labelStatefulSetPodIndex: "1"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "kafka"}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "broker"}}},
+	}
+
+	res, _ := injector.createResourceMap(context.Background(), v1alpha1.Instrumentation{}, ns, pod, 0)
+	assert.Equal(t, "streaming.kafka-1.broker", res["service.instance.id"])
+	assert.Equal(t, "1", res[statefulSetPodOrdinalAttribute])
+	assert.Equal(t, "kafka", res["k8s.statefulset.name"])
+}
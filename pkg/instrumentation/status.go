@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// injectAnnotations maps the languages to their inject annotation.
+var injectAnnotations = map[string]string{
+	string(TypeJava):   annotationInjectJava,
+	string(TypeNodeJS): annotationInjectNodeJS,
+	string(TypePython): annotationInjectPython,
+	string(TypeDotNet): annotationInjectDotNet,
+	string(TypeGo):     annotationInjectGo,
+	"apache-httpd":     annotationInjectApacheHttpd,
+	"nginx":            annotationInjectNginx,
+}
+
+// injectedInitContainers maps the init containers added by the injection to their language.
+var injectedInitContainers = map[string]string{
+	javaInitContainerName:        string(TypeJava),
+	nodejsInitContainerName:      string(TypeNodeJS),
+	pythonInitContainerName:      string(TypePython),
+	dotnetInitContainerName:      string(TypeDotNet),
+	apacheAgentInitContainerName: "apache-httpd",
+	nginxAgentInitContainerName:  "nginx",
+}
+
+// RequestedInstrumentations returns the sorted languages whose auto-instrumentation is requested for the pods by the
+// annotations of their template and of their namespace.
+func RequestedInstrumentations(ns metav1.ObjectMeta, pod metav1.ObjectMeta) []string {
+	var languages []string
+	for language, annotation := range injectAnnotations {
+		if value := annotationValue(ns, pod, annotation); value != "" && !strings.EqualFold(value, "false") {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// InjectedInstrumentations returns the images of the auto-instrumentations injected into the pod by language.
+func InjectedInstrumentations(pod corev1.Pod) map[string]string {
+	images := map[string]string{}
+	for _, container := range pod.Spec.InitContainers {
+		if language, found := injectedInitContainers[container.Name]; found {
+			images[language] = container.Image
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == sideCarName {
+			images[string(TypeGo)] = container.Image
+		}
+	}
+	return images
+}
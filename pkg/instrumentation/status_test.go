@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRequestedInstrumentations(t *testing.T) {
+	ns := metav1.ObjectMeta{Annotations: map[string]string{
+		annotationInjectJava:   "true",
+		annotationInjectPython: "true",
+	}}
+	pod := metav1.ObjectMeta{Annotations: map[string]string{
+		annotationInjectPython: "false",
+		annotationInjectNginx:  "my-instrumentation",
+	}}
+	assert.Equal(t, []string{"java", "nginx"}, RequestedInstrumentations(ns, pod))
+	assert.Empty(t, RequestedInstrumentations(metav1.ObjectMeta{}, metav1.ObjectMeta{}))
+}
+
+func TestInjectedInstrumentations(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "setup", Image: "busybox"},
+				{Name: javaInitContainerName, Image: "test.registry/adot-autoinstrumentation-java:v1.32.0"},
+			},
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:latest"},
+				{Name: sideCarName, Image: "test.registry/autoinstrumentation-go:v0.10.0"},
+			},
+		},
+	}
+	assert.Equal(t, map[string]string{
+		"java": "test.registry/adot-autoinstrumentation-java:v1.32.0",
+		"go":   "test.registry/autoinstrumentation-go:v0.10.0",
+	}, InjectedInstrumentations(pod))
+	assert.Empty(t, InjectedInstrumentations(corev1.Pod{}))
+}
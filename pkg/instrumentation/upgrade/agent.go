@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// AgentUpgrade bumps the agents following the latest channel to the default agent image of the operator. Agents
+// without a version nor an image follow the operator by themselves on the stable channel, and keep the version
+// recorded in their status when pinned, so neither channel changes their spec.
+type AgentUpgrade struct {
+	Client       client.Client
+	Logger       logr.Logger
+	Recorder     record.EventRecorder
+	DefaultImage string
+	// DefaultChannel is the channel of the instances that do not opt into one, stable when empty.
+	DefaultChannel Channel
+}
+
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch;update;patch
+
+// ManagedInstances upgrades the AmazonCloudWatchAgent instances managed by the amazon-cloudwatch-agent-operator.
+func (u *AgentUpgrade) ManagedInstances(ctx context.Context) error {
+	u.Logger.Info("looking for managed AmazonCloudWatchAgent instances to upgrade")
+
+	opts := []client.ListOption{
+		client.MatchingLabels(map[string]string{
+			"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		}),
+	}
+	list := &v1alpha1.AmazonCloudWatchAgentList{}
+	if err := u.Client.List(ctx, list, opts...); err != nil {
+		return fmt.Errorf("failed to list: %w", err)
+	}
+
+	for i := range list.Items {
+		toUpgrade := list.Items[i]
+		if toUpgrade.Spec.UpgradeStrategy == v1alpha1.UpgradeStrategyNone {
+			continue
+		}
+		channel, err := channelOf(&toUpgrade, u.defaultChannel())
+		if err != nil {
+			u.Logger.Error(err, "skipping the upgrade of instance", "name", toUpgrade.Name, "namespace", toUpgrade.Namespace)
+			u.Recorder.Event(&toUpgrade, "Warning", "AgentUpgradeRejected", err.Error())
+			continue
+		}
+		if channel != ChannelLatest {
+			continue
+		}
+		upgraded := u.upgrade(toUpgrade)
+		if !reflect.DeepEqual(upgraded, &toUpgrade) {
+			if err := u.Client.Update(ctx, upgraded); err != nil {
+				u.Logger.Error(err, "failed to apply changes to instance", "name", upgraded.Name, "namespace", upgraded.Namespace)
+				continue
+			}
+			u.Logger.Info("upgraded instance", "name", upgraded.Name, "namespace", upgraded.Namespace, "image", u.DefaultImage)
+		}
+	}
+
+	if len(list.Items) == 0 {
+		u.Logger.Info("no instances to upgrade")
+	}
+	return nil
+}
+
+func (u *AgentUpgrade) defaultChannel() Channel {
+	if u.DefaultChannel == "" {
+		return ChannelStable
+	}
+	return u.DefaultChannel
+}
+
+// upgrade moves the version and the image of the agent to the default agent image when they are older or pulled
+// from its repository.
+func (u *AgentUpgrade) upgrade(agent v1alpha1.AmazonCloudWatchAgent) *v1alpha1.AmazonCloudWatchAgent {
+	upgraded := agent.DeepCopy()
+	if version := tag(u.DefaultImage); agent.Spec.Version != "" && olderVersion(agent.Spec.Version, version) {
+		upgraded.Spec.Version = version
+	}
+	if agent.Spec.Image != "" && repository(agent.Spec.Image) == repository(u.DefaultImage) {
+		upgraded.Spec.Image = u.DefaultImage
+	}
+	return upgraded
+}
+
+// tag returns the tag of the image reference, or an empty string when it has none.
+func tag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// olderVersion returns whether the agent version a precedes b. Versions which are not made of a major, minor and
+// patch number, such as custom tags, are never considered older.
+func olderVersion(a string, b string) bool {
+	numbersA, ok := versionNumbers(a)
+	if !ok {
+		return false
+	}
+	numbersB, ok := versionNumbers(b)
+	if !ok {
+		return false
+	}
+	return slices.Compare(numbersA, numbersB) < 0
+}
+
+func versionNumbers(version string) ([]int, bool) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) != 3 {
+		return nil, false
+	}
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		// the patch number may be followed by a build suffix, e.g. 1.300040.0b123
+		if i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			part = part[:i]
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, true
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestAgentUpgrade(t *testing.T) {
+	nsName := strings.ToLower(t.Name())
+	err := k8sClient.Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nsName,
+		},
+	})
+	require.NoError(t, err)
+
+	agent := func(name string, channel string, spec v1alpha1.AmazonCloudWatchAgentSpec) *v1alpha1.AmazonCloudWatchAgent {
+		return &v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   nsName,
+				Labels:      map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"},
+				Annotations: map[string]string{constants.AnnotationUpgradeChannel: channel},
+			},
+			Spec: spec,
+		}
+	}
+	for _, inst := range []*v1alpha1.AmazonCloudWatchAgent{
+		agent("latest-version", "latest", v1alpha1.AmazonCloudWatchAgentSpec{Version: "1.300040.0"}),
+		agent("latest-image", "latest", v1alpha1.AmazonCloudWatchAgentSpec{Image: "cloudwatch-agent:1.300040.0"}),
+		agent("stable", "stable", v1alpha1.AmazonCloudWatchAgentSpec{Version: "1.300040.0"}),
+		agent("none", "latest", v1alpha1.AmazonCloudWatchAgentSpec{Version: "1.300040.0", UpgradeStrategy: v1alpha1.UpgradeStrategyNone}),
+	} {
+		require.NoError(t, k8sClient.Create(context.Background(), inst))
+	}
+
+	up := &AgentUpgrade{
+		Client:       k8sClient,
+		Logger:       logr.Discard(),
+		Recorder:     record.NewFakeRecorder(10),
+		DefaultImage: "cloudwatch-agent:1.300049.1",
+	}
+	require.NoError(t, up.ManagedInstances(context.Background()))
+
+	get := func(name string) v1alpha1.AmazonCloudWatchAgentSpec {
+		updated := v1alpha1.AmazonCloudWatchAgent{}
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: nsName, Name: name}, &updated))
+		return updated.Spec
+	}
+	assert.Equal(t, "1.300049.1", get("latest-version").Version)
+	assert.Equal(t, "cloudwatch-agent:1.300049.1", get("latest-image").Image)
+	assert.Equal(t, "1.300040.0", get("stable").Version)
+	assert.Equal(t, "1.300040.0", get("none").Version)
+}
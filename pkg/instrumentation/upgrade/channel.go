@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// Channel defines which images of a custom resource the operator bumps when it is upgraded.
+type Channel string
+
+const (
+	// ChannelStable bumps the images that are still the defaults of the previous operator version.
+	ChannelStable Channel = "stable"
+
+	// ChannelLatest bumps every image pulled from the repository of a default image, even when its tag was set by
+	// hand, so that the resource always runs the newest images the operator ships.
+	ChannelLatest Channel = "latest"
+
+	// ChannelPinned never bumps any image.
+	ChannelPinned Channel = "pinned"
+)
+
+// ParseChannel returns the channel of the given name.
+func ParseChannel(name string) (Channel, error) {
+	switch channel := Channel(name); channel {
+	case ChannelStable, ChannelLatest, ChannelPinned:
+		return channel, nil
+	}
+	return "", fmt.Errorf("unknown upgrade channel %q, expected one of %s, %s or %s", name, ChannelStable, ChannelLatest, ChannelPinned)
+}
+
+// channelOf returns the channel the resource opted into through its annotations, or the fallback when it did not.
+// Resources opting out of upgrades are pinned.
+func channelOf(obj metav1.Object, fallback Channel) (Channel, error) {
+	annotations := obj.GetAnnotations()
+	if skip, _ := strconv.ParseBool(annotations[constants.AnnotationSkipUpgrade]); skip {
+		return ChannelPinned, nil
+	}
+	name, ok := annotations[constants.AnnotationUpgradeChannel]
+	if !ok {
+		return fallback, nil
+	}
+	return ParseChannel(name)
+}
+
+// upgradable returns whether an image is bumped to the new default image on the given channel.
+func upgradable(channel Channel, image string, previousDefault string, newDefault string) bool {
+	switch channel {
+	case ChannelStable:
+		return image == previousDefault
+	case ChannelLatest:
+		return image == previousDefault || repository(image) == repository(newDefault)
+	}
+	return false
+}
+
+// repository strips the tag and the digest from the image reference.
+func repository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestChannelOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    Channel
+		expectedErr string
+	}{
+		{
+			name:     "default channel",
+			expected: ChannelStable,
+		},
+		{
+			name:        "opted into a channel",
+			annotations: map[string]string{constants.AnnotationUpgradeChannel: "latest"},
+			expected:    ChannelLatest,
+		},
+		{
+			name: "opted out",
+			annotations: map[string]string{
+				constants.AnnotationUpgradeChannel: "latest",
+				constants.AnnotationSkipUpgrade:    "true",
+			},
+			expected: ChannelPinned,
+		},
+		{
+			name:        "unknown channel",
+			annotations: map[string]string{constants.AnnotationUpgradeChannel: "nightly"},
+			expectedErr: `unknown upgrade channel "nightly", expected one of stable, latest or pinned`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, err := channelOf(&metav1.ObjectMeta{Annotations: tt.annotations}, ChannelStable)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, channel)
+		})
+	}
+}
+
+func TestUpgradable(t *testing.T) {
+	assert.True(t, upgradable(ChannelStable, "java:1", "java:1", "java:2"))
+	assert.False(t, upgradable(ChannelStable, "java:1.5", "java:1", "java:2"))
+	assert.True(t, upgradable(ChannelLatest, "java:1.5", "java:1", "java:2"))
+	assert.True(t, upgradable(ChannelLatest, "java@sha256:abc", "java:1", "java:2"))
+	assert.False(t, upgradable(ChannelLatest, "my-java:1.5", "java:1", "java:2"))
+	assert.False(t, upgradable(ChannelPinned, "java:1", "java:1", "java:2"))
+}
+
+func TestOlderVersion(t *testing.T) {
+	assert.True(t, olderVersion("1.300040.0", "1.300049.1"))
+	assert.True(t, olderVersion("1.300040.0b123", "1.300040.1"))
+	assert.False(t, olderVersion("1.300049.1", "1.300049.1"))
+	assert.False(t, olderVersion("1.300049.1", "1.300040.0"))
+	assert.False(t, olderVersion("latest", "1.300040.0"))
+}
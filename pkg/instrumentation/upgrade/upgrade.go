@@ -41,6 +41,8 @@ type InstrumentationUpgrade struct {
 	DefaultAutoInstApacheHttpd string
 	DefaultAutoInstNginx       string
 	DefaultAutoInstGo          string
+	// DefaultChannel is the channel of the instances that do not opt into one, stable when empty.
+	DefaultChannel Channel
 }
 
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch;update;patch
@@ -61,7 +63,16 @@ func (u *InstrumentationUpgrade) ManagedInstances(ctx context.Context) error {
 
 	for i := range list.Items {
 		toUpgrade := list.Items[i]
-		upgraded := u.upgrade(ctx, toUpgrade)
+		channel, err := channelOf(&toUpgrade, u.defaultChannel())
+		if err != nil {
+			u.Logger.Error(err, "skipping the upgrade of instance", "name", toUpgrade.Name, "namespace", toUpgrade.Namespace)
+			u.Recorder.Event(&toUpgrade, "Warning", "InstrumentationUpgradeRejected", err.Error())
+			continue
+		}
+		if channel == ChannelPinned {
+			continue
+		}
+		upgraded := u.upgrade(ctx, toUpgrade, channel)
 		if !reflect.DeepEqual(upgraded, toUpgrade) {
 			// use update instead of patch because the patch does not upgrade annotations
 			if err := u.Client.Update(ctx, upgraded); err != nil {
@@ -77,7 +88,14 @@ func (u *InstrumentationUpgrade) ManagedInstances(ctx context.Context) error {
 	return nil
 }
 
-func (u *InstrumentationUpgrade) upgrade(_ context.Context, inst v1alpha1.Instrumentation) *v1alpha1.Instrumentation {
+func (u *InstrumentationUpgrade) defaultChannel() Channel {
+	if u.DefaultChannel == "" {
+		return ChannelStable
+	}
+	return u.DefaultChannel
+}
+
+func (u *InstrumentationUpgrade) upgrade(_ context.Context, inst v1alpha1.Instrumentation, channel Channel) *v1alpha1.Instrumentation {
 	upgraded := inst.DeepCopy()
 	for annotation, gate := range defaultAnnotationToGate {
 		autoInst := upgraded.Annotations[annotation]
@@ -85,37 +103,37 @@ func (u *InstrumentationUpgrade) upgrade(_ context.Context, inst v1alpha1.Instru
 			if gate.IsEnabled() {
 				switch annotation {
 				case constants.AnnotationDefaultAutoInstrumentationJava:
-					if inst.Spec.Java.Image == autoInst {
+					if upgradable(channel, inst.Spec.Java.Image, autoInst, u.DefaultAutoInstJava) {
 						upgraded.Spec.Java.Image = u.DefaultAutoInstJava
 						upgraded.Annotations[annotation] = u.DefaultAutoInstJava
 					}
 				case constants.AnnotationDefaultAutoInstrumentationNodeJS:
-					if inst.Spec.NodeJS.Image == autoInst {
+					if upgradable(channel, inst.Spec.NodeJS.Image, autoInst, u.DefaultAutoInstNodeJS) {
 						upgraded.Spec.NodeJS.Image = u.DefaultAutoInstNodeJS
 						upgraded.Annotations[annotation] = u.DefaultAutoInstNodeJS
 					}
 				case constants.AnnotationDefaultAutoInstrumentationPython:
-					if inst.Spec.Python.Image == autoInst {
+					if upgradable(channel, inst.Spec.Python.Image, autoInst, u.DefaultAutoInstPython) {
 						upgraded.Spec.Python.Image = u.DefaultAutoInstPython
 						upgraded.Annotations[annotation] = u.DefaultAutoInstPython
 					}
 				case constants.AnnotationDefaultAutoInstrumentationDotNet:
-					if inst.Spec.DotNet.Image == autoInst {
+					if upgradable(channel, inst.Spec.DotNet.Image, autoInst, u.DefaultAutoInstDotNet) {
 						upgraded.Spec.DotNet.Image = u.DefaultAutoInstDotNet
 						upgraded.Annotations[annotation] = u.DefaultAutoInstDotNet
 					}
 				case constants.AnnotationDefaultAutoInstrumentationGo:
-					if inst.Spec.Go.Image == autoInst {
+					if upgradable(channel, inst.Spec.Go.Image, autoInst, u.DefaultAutoInstGo) {
 						upgraded.Spec.Go.Image = u.DefaultAutoInstGo
 						upgraded.Annotations[annotation] = u.DefaultAutoInstGo
 					}
 				case constants.AnnotationDefaultAutoInstrumentationApacheHttpd:
-					if inst.Spec.ApacheHttpd.Image == autoInst {
+					if upgradable(channel, inst.Spec.ApacheHttpd.Image, autoInst, u.DefaultAutoInstApacheHttpd) {
 						upgraded.Spec.ApacheHttpd.Image = u.DefaultAutoInstApacheHttpd
 						upgraded.Annotations[annotation] = u.DefaultAutoInstApacheHttpd
 					}
 				case constants.AnnotationDefaultAutoInstrumentationNginx:
-					if inst.Spec.Nginx.Image == autoInst {
+					if upgradable(channel, inst.Spec.Nginx.Image, autoInst, u.DefaultAutoInstNginx) {
 						upgraded.Spec.Nginx.Image = u.DefaultAutoInstNginx
 						upgraded.Annotations[annotation] = u.DefaultAutoInstNginx
 					}
@@ -12,14 +12,25 @@ import (
 const (
 	// Annotation contains the annotation name that pods contain, indicating whether a sidecar is desired.
 	Annotation = "sidecar.opentelemetry.io/inject"
+
+	// AnnotationInjectAgent is the CloudWatch equivalent of Annotation. It takes precedence when both are set.
+	AnnotationInjectAgent = "cloudwatch.aws.amazon.com/inject-agent"
 )
 
+// lookupAnnotation returns the value of the inject-agent annotation, falling back to the OpenTelemetry one.
+func lookupAnnotation(annotations map[string]string) string {
+	if value, ok := annotations[AnnotationInjectAgent]; ok {
+		return value
+	}
+	return annotations[Annotation]
+}
+
 // annotationValue returns the effective annotation value, based on the annotations from the pod and namespace.
 func annotationValue(ns corev1.Namespace, pod corev1.Pod) string {
 	// is the pod annotated with instructions to inject sidecars? is the namespace annotated?
 	// if any of those is true, a sidecar might be desired.
-	podAnnValue := pod.Annotations[Annotation]
-	nsAnnValue := ns.Annotations[Annotation]
+	podAnnValue := lookupAnnotation(pod.Annotations)
+	nsAnnValue := lookupAnnotation(ns.Annotations)
 
 	// if the namespace value is empty, the pod annotation should be used, whatever it is
 	if len(nsAnnValue) == 0 {
@@ -119,6 +119,39 @@ func TestEffectiveAnnotationValue(t *testing.T) {
 			},
 			corev1.Namespace{},
 		},
+
+		{
+			"pod-inject-agent-overrides-otel-annotation",
+			"cloudwatch-sidecar",
+			corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationInjectAgent: "cloudwatch-sidecar",
+						Annotation:            "otel-sidecar",
+					},
+				},
+			},
+			corev1.Namespace{},
+		},
+
+		{
+			"ns-inject-agent",
+			"cloudwatch-sidecar",
+			corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						Annotation: "true",
+					},
+				},
+			},
+			corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationInjectAgent: "cloudwatch-sidecar",
+					},
+				},
+			},
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
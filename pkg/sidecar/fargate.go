@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// fargateSchedulerName is the scheduler the EKS Fargate admission webhook gives the pods of the Fargate profiles.
+	fargateSchedulerName = "fargate-scheduler"
+	// fargateProfileLabel is the label the EKS Fargate admission webhook sets to the Fargate profile of the pod.
+	fargateProfileLabel = "eks.amazonaws.com/fargate-profile"
+	// computeTypeLabel is the node label of the EKS compute type, which the pods select Fargate nodes with.
+	computeTypeLabel   = "eks.amazonaws.com/compute-type"
+	computeTypeFargate = "fargate"
+
+	instrumentationAnnotationPrefix = "instrumentation.opentelemetry.io/inject-"
+	containerNamesAnnotationSuffix  = "-container-names"
+)
+
+// IsFargatePod returns whether the pod runs on EKS Fargate, where there is no agent DaemonSet to send the telemetry
+// to, either because the Fargate admission webhook scheduled it to a Fargate profile, or because it selects the
+// Fargate nodes.
+func IsFargatePod(pod corev1.Pod) bool {
+	return pod.Spec.SchedulerName == fargateSchedulerName ||
+		pod.Labels[fargateProfileLabel] != "" ||
+		pod.Spec.NodeSelector[computeTypeLabel] == computeTypeFargate
+}
+
+// requestsInstrumentation returns whether the pod or its namespace request an auto-instrumentation the pod doesn't
+// opt out of.
+func requestsInstrumentation(ns corev1.Namespace, pod corev1.Pod) bool {
+	for _, annotations := range []map[string]string{pod.Annotations, ns.Annotations} {
+		for annotation := range annotations {
+			if !strings.HasPrefix(annotation, instrumentationAnnotationPrefix) || strings.HasSuffix(annotation, containerNamesAnnotationSuffix) {
+				continue
+			}
+			// the annotation of the pod takes precedence over the one of the namespace
+			value := pod.Annotations[annotation]
+			if value == "" {
+				value = ns.Annotations[annotation]
+			}
+			if value != "" && !strings.EqualFold(value, "false") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fargateAgent returns the agent of the sidecar injected into the instrumented Fargate pods, or an empty string when
+// the pod is not an instrumented Fargate pod or no agent is configured for them.
+func (p *sidecarPodMutator) fargateAgent(ns corev1.Namespace, pod corev1.Pod) string {
+	if p.config.FargateAgent() == "" || !IsFargatePod(pod) || !requestsInstrumentation(ns, pod) {
+		return ""
+	}
+	return p.config.FargateAgent()
+}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestIsFargatePod(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		pod      corev1.Pod
+		expected bool
+	}{
+		{name: "ec2", pod: corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "default-scheduler"}}},
+		{name: "scheduler", pod: corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "fargate-scheduler"}}, expected: true},
+		{name: "profile", pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"eks.amazonaws.com/fargate-profile": "default"}}}, expected: true},
+		{name: "node selector", pod: corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{"eks.amazonaws.com/compute-type": "fargate"}}}, expected: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsFargatePod(tt.pod))
+		})
+	}
+}
+
+func TestFargateAgent(t *testing.T) {
+	fargatePod := func(annotations map[string]string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec:       corev1.PodSpec{SchedulerName: "fargate-scheduler"},
+		}
+	}
+	instrumented := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "true"}}}
+
+	for _, tt := range []struct {
+		name     string
+		agent    string
+		ns       corev1.Namespace
+		pod      corev1.Pod
+		expected string
+	}{
+		{
+			name:     "instrumented pod",
+			agent:    "amazon-cloudwatch/cloudwatch-agent-fargate",
+			pod:      fargatePod(map[string]string{"instrumentation.opentelemetry.io/inject-python": "true"}),
+			expected: "amazon-cloudwatch/cloudwatch-agent-fargate",
+		},
+		{
+			name:     "instrumented namespace",
+			agent:    "amazon-cloudwatch/cloudwatch-agent-fargate",
+			ns:       instrumented,
+			pod:      fargatePod(nil),
+			expected: "amazon-cloudwatch/cloudwatch-agent-fargate",
+		},
+		{
+			name:  "pod opts out",
+			agent: "amazon-cloudwatch/cloudwatch-agent-fargate",
+			ns:    instrumented,
+			pod:   fargatePod(map[string]string{"instrumentation.opentelemetry.io/inject-java": "false"}),
+		},
+		{
+			name:  "container names only",
+			agent: "amazon-cloudwatch/cloudwatch-agent-fargate",
+			pod:   fargatePod(map[string]string{"instrumentation.opentelemetry.io/inject-nginx-container-names": "app"}),
+		},
+		{
+			name:  "not instrumented",
+			agent: "amazon-cloudwatch/cloudwatch-agent-fargate",
+			pod:   fargatePod(nil),
+		},
+		{
+			name:  "not fargate",
+			agent: "amazon-cloudwatch/cloudwatch-agent-fargate",
+			ns:    instrumented,
+		},
+		{
+			name: "no agent",
+			ns:   instrumented,
+			pod:  fargatePod(nil),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator := NewMutator(logr.Discard(), config.New(config.WithFargateAgent(tt.agent)), nil)
+			assert.Equal(t, tt.expected, mutator.fargateAgent(tt.ns, tt.pod))
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 )
 
@@ -35,9 +36,23 @@ func add(cfg config.Config, logger logr.Logger, otelcol v1alpha1.AmazonCloudWatc
 	if !hasResourceAttributeEnvVar(container.Env) {
 		container.Env = append(container.Env, attributes...)
 	}
-	pod.Spec.InitContainers = append(pod.Spec.InitContainers, otelcol.Spec.InitContainers...)
+	for _, initContainer := range otelcol.Spec.InitContainers {
+		if !hasContainer(pod.Spec.InitContainers, initContainer.Name) {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+		}
+	}
+	for _, additional := range otelcol.Spec.AdditionalContainers {
+		if !hasContainer(pod.Spec.Containers, additional.Name) {
+			pod.Spec.Containers = append(pod.Spec.Containers, additional)
+		}
+	}
 	pod.Spec.Containers = append(pod.Spec.Containers, container)
-	pod.Spec.Volumes = append(pod.Spec.Volumes, otelcol.Spec.Volumes...)
+	for _, volume := range otelcol.Spec.Volumes {
+		if !hasVolume(pod.Spec.Volumes, volume.Name) {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		}
+	}
+	pod.Spec.ImagePullSecrets = manifestutils.ImagePullSecrets(pod.Spec.ImagePullSecrets, cfg.ImagePullSecrets())
 
 	if pod.Labels == nil {
 		pod.Labels = map[string]string{}
@@ -65,8 +80,21 @@ func remove(pod corev1.Pod) (corev1.Pod, error) {
 
 // existsIn checks whether a sidecar container exists in the given pod.
 func existsIn(pod corev1.Pod) bool {
-	for _, container := range pod.Spec.Containers {
-		if container.Name == naming.Container() {
+	return hasContainer(pod.Spec.Containers, naming.Container())
+}
+
+func hasContainer(containers []corev1.Container, name string) bool {
+	for _, container := range containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	for _, volume := range volumes {
+		if volume.Name == name {
 			return true
 		}
 	}
@@ -125,6 +125,67 @@ func TestAddSidecarWhenOneExistsAlready(t *testing.T) {
 	assert.Len(t, changed.Spec.Containers, 3)
 }
 
+func TestAddSidecarWithImagePullSecrets(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers:       []corev1.Container{{Name: "my-app"}},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "app-registry"}},
+		},
+	}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{}
+	cfg := config.New(
+		config.WithCollectorImage("some-default-image"),
+		config.WithImagePullSecrets([]string{"mirror", "app-registry"}),
+	)
+
+	// test
+	changed, err := add(cfg, logger, otelcol, pod, nil)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "app-registry"}, {Name: "mirror"}}, changed.Spec.ImagePullSecrets)
+}
+
+func TestAddSidecarWithAdditionalAndInitContainers(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "my-app"},
+				{Name: "config-reloader"},
+			},
+			InitContainers: []corev1.Container{
+				{Name: "wait-for-config"},
+			},
+		},
+	}
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			AdditionalContainers: []corev1.Container{
+				{Name: "log-shipper"},
+				{Name: "config-reloader"},
+			},
+			InitContainers: []corev1.Container{
+				{Name: "wait-for-config"},
+				{Name: "sysctl"},
+			},
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := add(cfg, logger, otelcol, pod, nil)
+
+	// verify
+	assert.NoError(t, err)
+	require.Len(t, changed.Spec.Containers, 4)
+	assert.Equal(t, "log-shipper", changed.Spec.Containers[2].Name)
+	assert.Equal(t, naming.Container(), changed.Spec.Containers[3].Name)
+	require.Len(t, changed.Spec.InitContainers, 2)
+	assert.Equal(t, "sysctl", changed.Spec.InitContainers[1].Name)
+}
+
 func TestRemoveSidecar(t *testing.T) {
 	// prepare
 	pod := corev1.Pod{
@@ -11,12 +11,15 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 )
 
@@ -47,9 +50,14 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 
 	// if no annotations are found at all, just return the same pod
 	annValue := annotationValue(ns, pod)
+	fargate := false
 	if len(annValue) == 0 {
-		logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
-		return pod, nil
+		// the instrumented Fargate pods have no agent on their node, they are given an agent sidecar instead
+		if annValue = p.fargateAgent(ns, pod); len(annValue) == 0 {
+			logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
+			return pod, nil
+		}
+		fargate = true
 	}
 
 	// is the annotation value 'false'? if so, we need a pod without the sidecar (ie, remove if exists)
@@ -68,7 +76,8 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 	// which instance should it talk to?
 	otelcol, err := p.getCollectorInstance(ctx, ns, annValue)
 	if err != nil {
-		if errors.Is(err, errMultipleInstancesPossible) || errors.Is(err, errNoInstancesAvailable) || errors.Is(err, errInstanceNotSidecar) {
+		if errors.Is(err, errMultipleInstancesPossible) || errors.Is(err, errNoInstancesAvailable) || errors.Is(err, errInstanceNotSidecar) ||
+			(fargate && apierrors.IsNotFound(err)) {
 			// we still allow the pod to be created, but we log a message to the operator's logs
 			logger.Error(err, "failed to select an OpenTelemetry Collector instance for this pod's sidecar")
 			return pod, nil
@@ -78,6 +87,11 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 		return pod, err
 	}
 
+	otelcol, err = collector.ResolveConfigSources(ctx, p.client, otelcol)
+	if err != nil {
+		return pod, err
+	}
+
 	// getting pod references, if any
 	references := p.podReferences(ctx, pod.OwnerReferences, ns)
 	attributes := getResourceAttributesEnv(ns, references)
@@ -86,7 +100,22 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 	// we should add the sidecar.
 	logger.V(1).Info("injecting sidecar into pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
 
-	return add(p.config, p.logger, otelcol, pod, attributes)
+	// the sidecar is added to a copy of the pod, which is left untouched when one of the images doesn't verify
+	mutated, err := add(p.config, p.logger, otelcol, *pod.DeepCopy(), attributes)
+	if err != nil {
+		return pod, err
+	}
+	// the verification is bounded by a budget of its own, which leaves the admission of the pod in time
+	verifyCtx, cancel := context.WithTimeout(ctx, config.AdmissionImageVerificationTimeout)
+	defer cancel()
+	pinned, err := p.config.VerifyImages(verifyCtx, manifestutils.AddedImages(pod.Spec, mutated.Spec), config.NewImagePull(ns.Name, mutated.Spec))
+	if err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "refusing to inject the sidecar")
+		return pod, nil
+	}
+	manifestutils.PinImages([]*corev1.PodSpec{&mutated.Spec}, pinned)
+	return mutated, nil
 }
 
 func (p *sidecarPodMutator) getCollectorInstance(ctx context.Context, ns corev1.Namespace, ann string) (v1alpha1.AmazonCloudWatchAgent, error) {